@@ -127,6 +127,7 @@ func InitializeState(
 		MongoInfo:                 info,
 		MongoDialOpts:             dialOpts,
 		NewPolicy:                 newPolicy,
+		SecretsKey:                servingInfo.SecretsKey,
 	})
 	if err != nil {
 		return nil, nil, errors.Errorf("failed to initialize state: %v", err)
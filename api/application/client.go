@@ -8,6 +8,8 @@
 package application
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/charm.v6-unstable"
@@ -51,6 +53,112 @@ func (c *Client) SetMetricCredentials(service string, credentials []byte) error
 	return errors.Trace(results.OneError())
 }
 
+// SetUpdateStatusInterval sets how often the update-status hook runs
+// for the units of the application. A zero interval reverts to the
+// model's update-status-hook-interval.
+func (c *Client) SetUpdateStatusInterval(application string, interval time.Duration) error {
+	if c.BestAPIVersion() < 7 {
+		return errors.New("this juju controller does not support per-application update status intervals")
+	}
+	args := params.ApplicationUpdateStatusIntervals{
+		Args: []params.ApplicationUpdateStatusInterval{{
+			ApplicationName: application,
+			Interval:        interval,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetUpdateStatusIntervals", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// SetHookTimeout sets how long the hooks of the units of the
+// application may run before they are killed, and whether hooks that
+// time out are retried. A zero timeout reverts to the timeout declared
+// by the charm.
+func (c *Client) SetHookTimeout(application string, timeout time.Duration, retry bool) error {
+	if c.BestAPIVersion() < 9 {
+		return errors.New("this juju controller does not support hook timeouts")
+	}
+	args := params.ApplicationHookTimeouts{
+		Args: []params.ApplicationHookTimeout{{
+			ApplicationName: application,
+			Timeout:         timeout,
+			Retry:           retry,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetHookTimeouts", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// PrecheckDeploy checks, without changing the model, whether the given
+// machines could be provisioned. The result for each machine holds
+// every problem found with it.
+func (c *Client) PrecheckDeploy(machines []params.PrecheckDeployMachine) ([]params.PrecheckDeployResult, error) {
+	if c.BestAPIVersion() < 10 {
+		return nil, errors.New("this juju controller does not support deploy prechecks")
+	}
+	args := params.PrecheckDeployArgs{Machines: machines}
+	var results params.PrecheckDeployResults
+	if err := c.facade.FacadeCall("PrecheckDeploy", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(machines) {
+		return nil, errors.Errorf("expected %d results, got %d", len(machines), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// UnitHookExecutions returns the most recent hook and action
+// executions recorded for the unit, newest first and without their
+// output. If limit is positive, at most that many are returned.
+func (c *Client) UnitHookExecutions(unit string, limit int) ([]params.HookExecution, error) {
+	return c.unitHookExecutions(params.UnitHookExecutionsQuery{
+		Tag:   names.NewUnitTag(unit).String(),
+		Limit: limit,
+	})
+}
+
+// UnitHookExecution returns the hook or action execution of the unit
+// with the given sequence number, including its output.
+func (c *Client) UnitHookExecution(unit string, seq int) (params.HookExecution, error) {
+	executions, err := c.unitHookExecutions(params.UnitHookExecutionsQuery{
+		Tag: names.NewUnitTag(unit).String(),
+		Seq: seq,
+	})
+	if err != nil {
+		return params.HookExecution{}, errors.Trace(err)
+	}
+	if len(executions) != 1 {
+		return params.HookExecution{}, errors.Errorf("expected 1 execution, got %d", len(executions))
+	}
+	return executions[0], nil
+}
+
+func (c *Client) unitHookExecutions(query params.UnitHookExecutionsQuery) ([]params.HookExecution, error) {
+	if c.BestAPIVersion() < 8 {
+		return nil, errors.New("this juju controller does not support hook execution history")
+	}
+	args := params.UnitHookExecutionsQueries{
+		Queries: []params.UnitHookExecutionsQuery{query},
+	}
+	var results params.HookExecutionsResults
+	if err := c.facade.FacadeCall("UnitHookExecutions", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Executions, nil
+}
+
 // ModelUUID returns the model UUID from the client connection.
 func (c *Client) ModelUUID() string {
 	tag, ok := c.st.ModelTag()
@@ -552,6 +660,34 @@ func (c *Client) Unexpose(application string) error {
 	return c.facade.FacadeCall("Unexpose", params, nil)
 }
 
+// ExposeEndpoints exposes the ports opened for the specified endpoints
+// of the application to the given spaces and/or CIDRs. The settings
+// are merged into any existing expose settings for the application.
+func (c *Client) ExposeEndpoints(application string, exposedEndpoints map[string]params.ExposedEndpoint) error {
+	if c.BestAPIVersion() < 6 {
+		return errors.New("this juju controller does not support endpoint specific expose settings")
+	}
+	args := params.ApplicationExpose{
+		ApplicationName:  application,
+		ExposedEndpoints: exposedEndpoints,
+	}
+	return c.facade.FacadeCall("Expose", args, nil)
+}
+
+// UnexposeEndpoints removes the expose settings for the specified
+// endpoints of the application. The application is unexposed once
+// no endpoint settings remain.
+func (c *Client) UnexposeEndpoints(application string, endpoints []string) error {
+	if c.BestAPIVersion() < 6 {
+		return errors.New("this juju controller does not support endpoint specific expose settings")
+	}
+	args := params.ApplicationUnexpose{
+		ApplicationName:  application,
+		ExposedEndpoints: endpoints,
+	}
+	return c.facade.FacadeCall("Unexpose", args, nil)
+}
+
 // Get returns the configuration for the named application.
 func (c *Client) Get(application string) (*params.ApplicationGetResults, error) {
 	var results params.ApplicationGetResults
@@ -4,6 +4,8 @@
 package application_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -818,3 +820,257 @@ func (s *applicationSuite) TestGetConstraintsAPIv4(c *gc.C) {
 		fooConstraints, barConstraints,
 	})
 }
+
+func (s *applicationSuite) TestExposeEndpoints(c *gc.C) {
+	exposedEndpoints := map[string]params.ExposedEndpoint{
+		"website": {
+			ExposeToSpaces: []string{"public"},
+			ExposeToCIDRs:  []string{"10.0.0.0/8"},
+		},
+	}
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "Expose")
+				c.Assert(a, jc.DeepEquals, params.ApplicationExpose{
+					ApplicationName:  "foo",
+					ExposedEndpoints: exposedEndpoints,
+				})
+				return nil
+			},
+		),
+		BestVersion: 6,
+	})
+	err := client.ExposeEndpoints("foo", exposedEndpoints)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestExposeEndpointsNotSupported(c *gc.C) {
+	var called bool
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		called = true
+		return nil
+	})
+	err := client.ExposeEndpoints("foo", map[string]params.ExposedEndpoint{"website": {}})
+	c.Assert(err, gc.ErrorMatches, "this juju controller does not support endpoint specific expose settings")
+	c.Assert(called, jc.IsFalse)
+}
+
+func (s *applicationSuite) TestSetUpdateStatusInterval(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "SetUpdateStatusIntervals")
+				c.Assert(a, jc.DeepEquals, params.ApplicationUpdateStatusIntervals{
+					Args: []params.ApplicationUpdateStatusInterval{{
+						ApplicationName: "foo",
+						Interval:        15 * time.Minute,
+					}},
+				})
+				result := response.(*params.ErrorResults)
+				result.Results = make([]params.ErrorResult, 1)
+				return nil
+			},
+		),
+		BestVersion: 7,
+	})
+	err := client.SetUpdateStatusInterval("foo", 15*time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestSetUpdateStatusIntervalNotSupported(c *gc.C) {
+	var called bool
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		called = true
+		return nil
+	})
+	err := client.SetUpdateStatusInterval("foo", 15*time.Minute)
+	c.Assert(err, gc.ErrorMatches, "this juju controller does not support per-application update status intervals")
+	c.Assert(called, jc.IsFalse)
+}
+
+func (s *applicationSuite) TestUnitHookExecutions(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "UnitHookExecutions")
+				c.Assert(a, jc.DeepEquals, params.UnitHookExecutionsQueries{
+					Queries: []params.UnitHookExecutionsQuery{{
+						Tag:   "unit-foo-0",
+						Limit: 10,
+					}},
+				})
+				result := response.(*params.HookExecutionsResults)
+				result.Results = []params.HookExecutionsResult{{
+					Executions: []params.HookExecution{{Seq: 2, Kind: "hook", Name: "start"}},
+				}}
+				return nil
+			},
+		),
+		BestVersion: 8,
+	})
+	executions, err := client.UnitHookExecutions("foo/0", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(executions, jc.DeepEquals, []params.HookExecution{{Seq: 2, Kind: "hook", Name: "start"}})
+}
+
+func (s *applicationSuite) TestUnitHookExecution(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "UnitHookExecutions")
+				c.Assert(a, jc.DeepEquals, params.UnitHookExecutionsQueries{
+					Queries: []params.UnitHookExecutionsQuery{{
+						Tag: "unit-foo-0",
+						Seq: 2,
+					}},
+				})
+				result := response.(*params.HookExecutionsResults)
+				result.Results = []params.HookExecutionsResult{{
+					Error: &params.Error{Message: "hook execution 2 for unit \"foo/0\" not found"},
+				}}
+				return nil
+			},
+		),
+		BestVersion: 8,
+	})
+	_, err := client.UnitHookExecution("foo/0", 2)
+	c.Assert(err, gc.ErrorMatches, `hook execution 2 for unit "foo/0" not found`)
+}
+
+func (s *applicationSuite) TestUnitHookExecutionsNotSupported(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected call to %s", request)
+				return nil
+			},
+		),
+		BestVersion: 7,
+	})
+	_, err := client.UnitHookExecutions("foo/0", 0)
+	c.Assert(err, gc.ErrorMatches, "this juju controller does not support hook execution history")
+}
+
+func (s *applicationSuite) TestSetHookTimeout(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "SetHookTimeouts")
+				c.Assert(a, jc.DeepEquals, params.ApplicationHookTimeouts{
+					Args: []params.ApplicationHookTimeout{{
+						ApplicationName: "foo",
+						Timeout:         10 * time.Minute,
+						Retry:           true,
+					}},
+				})
+				result := response.(*params.ErrorResults)
+				result.Results = make([]params.ErrorResult, 1)
+				return nil
+			},
+		),
+		BestVersion: 9,
+	})
+	err := client.SetHookTimeout("foo", 10*time.Minute, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestSetHookTimeoutNotSupported(c *gc.C) {
+	var called bool
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		called = true
+		return nil
+	})
+	err := client.SetHookTimeout("foo", 10*time.Minute, true)
+	c.Assert(err, gc.ErrorMatches, "this juju controller does not support hook timeouts")
+	c.Assert(called, jc.IsFalse)
+}
+
+func (s *applicationSuite) TestPrecheckDeploy(c *gc.C) {
+	machines := []params.PrecheckDeployMachine{{
+		Series:      "xenial",
+		Constraints: constraints.MustParse("mem=4G"),
+	}, {
+		Series:    "xenial",
+		Placement: &instance.Placement{Scope: coretesting.ModelTag.Id(), Directive: "zone=a"},
+	}}
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "PrecheckDeploy")
+				c.Assert(a, jc.DeepEquals, params.PrecheckDeployArgs{Machines: machines})
+				result := response.(*params.PrecheckDeployResults)
+				result.Results = []params.PrecheckDeployResult{{}, {
+					Errors: []*params.Error{{Message: "boom"}},
+				}}
+				return nil
+			},
+		),
+		BestVersion: 10,
+	})
+	results, err := client.PrecheckDeploy(machines)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(results, jc.DeepEquals, []params.PrecheckDeployResult{{}, {
+		Errors: []*params.Error{{Message: "boom"}},
+	}})
+}
+
+func (s *applicationSuite) TestPrecheckDeployWrongResultCount(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				return nil
+			},
+		),
+		BestVersion: 10,
+	})
+	_, err := client.PrecheckDeploy([]params.PrecheckDeployMachine{{Series: "xenial"}})
+	c.Assert(err, gc.ErrorMatches, "expected 1 results, got 0")
+}
+
+func (s *applicationSuite) TestPrecheckDeployNotSupported(c *gc.C) {
+	var called bool
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		called = true
+		return nil
+	})
+	_, err := client.PrecheckDeploy([]params.PrecheckDeployMachine{{Series: "xenial"}})
+	c.Assert(err, gc.ErrorMatches, "this juju controller does not support deploy prechecks")
+	c.Assert(called, jc.IsFalse)
+}
+
+func (s *applicationSuite) TestUnexposeEndpoints(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "Unexpose")
+				c.Assert(a, jc.DeepEquals, params.ApplicationUnexpose{
+					ApplicationName:  "foo",
+					ExposedEndpoints: []string{"website"},
+				})
+				return nil
+			},
+		),
+		BestVersion: 6,
+	})
+	err := client.UnexposeEndpoints("foo", []string{"website"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
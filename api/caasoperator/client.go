@@ -0,0 +1,100 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/watcher"
+)
+
+const caasOperatorFacade = "CAASOperator"
+
+// Client allows access to the CAAS operator API endpoint.
+type Client struct {
+	facade base.FacadeCaller
+}
+
+// NewClient returns a client used to access the CAAS operator API.
+func NewClient(caller base.APICaller) *Client {
+	return &Client{
+		facade: base.NewFacadeCaller(caller, caasOperatorFacade),
+	}
+}
+
+// WatchUnits returns a StringsWatcher that notifies of
+// changes to the lifecycles of units of the specified
+// CAAS application in the current model.
+func (c *Client) WatchUnits(application string) (watcher.StringsWatcher, error) {
+	if !names.IsValidApplication(application) {
+		return nil, errors.NotValidf("application name %q", application)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewApplicationTag(application).String()}},
+	}
+	var results params.StringsWatchResults
+	if err := c.facade.FacadeCall("WatchUnits", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := apiwatcher.NewStringsWatcher(c.facade.RawAPICaller(), results.Results[0])
+	return w, nil
+}
+
+// Life returns the lifecycle state for the specified CAAS application
+// or unit in the current model.
+func (c *Client) Life(entityName string) (params.Life, error) {
+	var tag names.Tag
+	switch {
+	case names.IsValidUnit(entityName):
+		tag = names.NewUnitTag(entityName)
+	case names.IsValidApplication(entityName):
+		tag = names.NewApplicationTag(entityName)
+	default:
+		return "", errors.NotValidf("application or unit name %q", entityName)
+	}
+	life, err := common.OneLife(c.facade, tag)
+	if err != nil {
+		return "", maybeNotFound(err)
+	}
+	return life, nil
+}
+
+// SetUnitPassword sets the password the operator uses to connect
+// to the API on behalf of the specified unit.
+func (c *Client) SetUnitPassword(unitName, password string) error {
+	if !names.IsValidUnit(unitName) {
+		return errors.NotValidf("unit name %q", unitName)
+	}
+	args := params.EntityPasswords{
+		Changes: []params.EntityPassword{{
+			Tag:      names.NewUnitTag(unitName).String(),
+			Password: password,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetPasswords", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// maybeNotFound returns an error satisfying errors.IsNotFound
+// if the supplied error has a CodeNotFound error.
+func maybeNotFound(err error) error {
+	if err == nil || !params.IsCodeNotFound(err) {
+		return err
+	}
+	return errors.NewNotFound(err, "")
+}
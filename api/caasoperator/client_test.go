@@ -0,0 +1,109 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/caasoperator"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type operatorSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&operatorSuite{})
+
+func (s *operatorSuite) TestWatchUnits(c *gc.C) {
+	var called bool
+	client := caasoperator.NewClient(basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "CAASOperator")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "WatchUnits")
+		c.Assert(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "application-gitlab"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResults{})
+		*(result.(*params.StringsWatchResults)) = params.StringsWatchResults{
+			Results: []params.StringsWatchResult{{
+				Error: &params.Error{Message: "FAIL"},
+			}},
+		}
+		return nil
+	}))
+	_, err := client.WatchUnits("gitlab")
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(called, jc.IsTrue)
+}
+
+func (s *operatorSuite) TestWatchUnitsInvalidApplication(c *gc.C) {
+	client := caasoperator.NewClient(basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("should not be called")
+	}))
+	_, err := client.WatchUnits("gitlab/0")
+	c.Check(err, gc.ErrorMatches, `application name "gitlab/0" not valid`)
+}
+
+func (s *operatorSuite) TestLife(c *gc.C) {
+	tag := "unit-gitlab-0"
+	client := caasoperator.NewClient(basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "CAASOperator")
+		c.Check(request, gc.Equals, "Life")
+		c.Assert(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: tag}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.LifeResults{})
+		*(result.(*params.LifeResults)) = params.LifeResults{
+			Results: []params.LifeResult{{Life: params.Alive}},
+		}
+		return nil
+	}))
+	life, err := client.Life("gitlab/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(life, gc.Equals, params.Alive)
+
+	tag = "application-gitlab"
+	life, err = client.Life("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(life, gc.Equals, params.Alive)
+}
+
+func (s *operatorSuite) TestLifeNotFound(c *gc.C) {
+	client := caasoperator.NewClient(basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.LifeResults)) = params.LifeResults{
+			Results: []params.LifeResult{{
+				Error: &params.Error{Code: params.CodeNotFound, Message: "bam"},
+			}},
+		}
+		return nil
+	}))
+	_, err := client.Life("gitlab")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *operatorSuite) TestSetUnitPassword(c *gc.C) {
+	var called bool
+	client := caasoperator.NewClient(basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "CAASOperator")
+		c.Check(request, gc.Equals, "SetPasswords")
+		c.Assert(arg, jc.DeepEquals, params.EntityPasswords{
+			Changes: []params.EntityPassword{{Tag: "unit-gitlab-0", Password: "sekrit"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "FAIL"}}},
+		}
+		return nil
+	}))
+	err := client.SetUnitPassword("gitlab/0", "sekrit")
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(called, jc.IsTrue)
+}
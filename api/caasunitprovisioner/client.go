@@ -0,0 +1,216 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/api/common/cloudspec"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/watcher"
+)
+
+const caasUnitProvisionerFacade = "CAASUnitProvisioner"
+
+// Client allows access to the CAAS unit provisioner API endpoint.
+type Client struct {
+	facade base.FacadeCaller
+	*common.ModelWatcher
+	*cloudspec.CloudSpecAPI
+}
+
+// NewClient returns a client used to access the CAAS unit provisioner API.
+func NewClient(caller base.APICaller) (*Client, error) {
+	modelTag, isModel := caller.ModelTag()
+	if !isModel {
+		return nil, errors.New("expected model specific API connection")
+	}
+	facadeCaller := base.NewFacadeCaller(caller, caasUnitProvisionerFacade)
+	return &Client{
+		facade:       facadeCaller,
+		ModelWatcher: common.NewModelWatcher(facadeCaller),
+		CloudSpecAPI: cloudspec.NewCloudSpecAPI(facadeCaller, modelTag),
+	}, nil
+}
+
+// WatchApplications returns a StringsWatcher that notifies of
+// changes to the lifecycles of CAAS applications in the current model.
+func (c *Client) WatchApplications() (watcher.StringsWatcher, error) {
+	var result params.StringsWatchResult
+	if err := c.facade.FacadeCall("WatchApplications", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewStringsWatcher(c.facade.RawAPICaller(), result)
+	return w, nil
+}
+
+func applicationTagEntities(application string) (params.Entities, error) {
+	if !names.IsValidApplication(application) {
+		return params.Entities{}, errors.NotValidf("application name %q", application)
+	}
+	return params.Entities{
+		Entities: []params.Entity{{Tag: names.NewApplicationTag(application).String()}},
+	}, nil
+}
+
+// WatchUnits returns a StringsWatcher that notifies of
+// changes to the lifecycles of units of the specified
+// CAAS application in the current model.
+func (c *Client) WatchUnits(application string) (watcher.StringsWatcher, error) {
+	args, err := applicationTagEntities(application)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var results params.StringsWatchResults
+	if err := c.facade.FacadeCall("WatchUnits", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := apiwatcher.NewStringsWatcher(c.facade.RawAPICaller(), results.Results[0])
+	return w, nil
+}
+
+// WatchPodSpec returns a NotifyWatcher that notifies of
+// changes to the pod spec of the specified CAAS application
+// in the current model.
+func (c *Client) WatchPodSpec(application string) (watcher.NotifyWatcher, error) {
+	args, err := applicationTagEntities(application)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var results params.NotifyWatchResults
+	if err := c.facade.FacadeCall("WatchPodSpec", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), results.Results[0])
+	return w, nil
+}
+
+// ProvisioningInfo returns the info needed to provision the units
+// of the specified CAAS application. It returns an error satisfying
+// errors.IsNotFound if the application's charm has not yet set
+// a pod spec.
+func (c *Client) ProvisioningInfo(application string) (*params.CAASUnitProvisioningInfo, error) {
+	args, err := applicationTagEntities(application)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var results params.CAASUnitProvisioningInfoResults
+	if err := c.facade.FacadeCall("ProvisioningInfo", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, maybeNotFound(err)
+	}
+	return results.Results[0].Result, nil
+}
+
+// Life returns the lifecycle state for the specified CAAS application
+// or unit in the current model.
+func (c *Client) Life(entityName string) (params.Life, error) {
+	var tag names.Tag
+	switch {
+	case names.IsValidUnit(entityName):
+		tag = names.NewUnitTag(entityName)
+	case names.IsValidApplication(entityName):
+		tag = names.NewApplicationTag(entityName)
+	default:
+		return "", errors.NotValidf("application or unit name %q", entityName)
+	}
+	life, err := common.OneLife(c.facade, tag)
+	if err != nil {
+		return "", maybeNotFound(err)
+	}
+	return life, nil
+}
+
+// SetContainerStatuses sets the status of the containers running the
+// specified units, keyed on unit name.
+func (c *Client) SetContainerStatuses(statuses map[string]status.StatusInfo) error {
+	args := params.SetStatus{
+		Entities: make([]params.EntityStatusArgs, 0, len(statuses)),
+	}
+	unitNames := make([]string, 0, len(statuses))
+	for unitName := range statuses {
+		unitNames = append(unitNames, unitName)
+	}
+	sort.Strings(unitNames)
+	for _, unitName := range unitNames {
+		info := statuses[unitName]
+		if !names.IsValidUnit(unitName) {
+			return errors.NotValidf("unit name %q", unitName)
+		}
+		args.Entities = append(args.Entities, params.EntityStatusArgs{
+			Tag:    names.NewUnitTag(unitName).String(),
+			Status: info.Status.String(),
+			Info:   info.Message,
+			Data:   info.Data,
+		})
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetContainerStatus", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
+
+// SetContainerAddresses records the addresses of the containers
+// running the specified units, keyed on unit name.
+func (c *Client) SetContainerAddresses(addresses map[string]string) error {
+	args := params.SetUnitContainerAddresses{
+		Args: make([]params.UnitContainerAddress, 0, len(addresses)),
+	}
+	unitNames := make([]string, 0, len(addresses))
+	for unitName := range addresses {
+		unitNames = append(unitNames, unitName)
+	}
+	sort.Strings(unitNames)
+	for _, unitName := range unitNames {
+		if !names.IsValidUnit(unitName) {
+			return errors.NotValidf("unit name %q", unitName)
+		}
+		args.Args = append(args.Args, params.UnitContainerAddress{
+			Tag:     names.NewUnitTag(unitName).String(),
+			Address: addresses[unitName],
+		})
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetContainerAddresses", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
+
+// maybeNotFound returns an error satisfying errors.IsNotFound
+// if the supplied error has a CodeNotFound error.
+func maybeNotFound(err error) error {
+	if err == nil || !params.IsCodeNotFound(err) {
+		return err
+	}
+	return errors.NewNotFound(err, "")
+}
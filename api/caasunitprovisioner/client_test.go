@@ -0,0 +1,226 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/caasunitprovisioner"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/status"
+)
+
+type unitprovisionerSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&unitprovisionerSuite{})
+
+func newClient(f basetesting.APICallerFunc) *caasunitprovisioner.Client {
+	client, err := caasunitprovisioner.NewClient(f)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+func (s *unitprovisionerSuite) TestWatchApplications(c *gc.C) {
+	var called bool
+	client := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "CAASUnitProvisioner")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "WatchApplications")
+		c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResult{})
+		*(result.(*params.StringsWatchResult)) = params.StringsWatchResult{
+			Error: &params.Error{Message: "FAIL"},
+		}
+		return nil
+	})
+	_, err := client.WatchApplications()
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(called, jc.IsTrue)
+}
+
+func (s *unitprovisionerSuite) TestWatchUnits(c *gc.C) {
+	var called bool
+	client := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "CAASUnitProvisioner")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "WatchUnits")
+		c.Assert(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "application-gitlab"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResults{})
+		*(result.(*params.StringsWatchResults)) = params.StringsWatchResults{
+			Results: []params.StringsWatchResult{{
+				Error: &params.Error{Message: "FAIL"},
+			}},
+		}
+		return nil
+	})
+	_, err := client.WatchUnits("gitlab")
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(called, jc.IsTrue)
+}
+
+func (s *unitprovisionerSuite) TestWatchPodSpec(c *gc.C) {
+	var called bool
+	client := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(request, gc.Equals, "WatchPodSpec")
+		c.Assert(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "application-gitlab"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.NotifyWatchResults{})
+		*(result.(*params.NotifyWatchResults)) = params.NotifyWatchResults{
+			Results: []params.NotifyWatchResult{{
+				Error: &params.Error{Message: "FAIL"},
+			}},
+		}
+		return nil
+	})
+	_, err := client.WatchPodSpec("gitlab")
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(called, jc.IsTrue)
+}
+
+func (s *unitprovisionerSuite) TestWatchInvalidApplication(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call")
+		return nil
+	})
+	_, err := client.WatchUnits("gitlab/0")
+	c.Check(err, gc.ErrorMatches, `application name "gitlab/0" not valid`)
+}
+
+func (s *unitprovisionerSuite) TestProvisioningInfo(c *gc.C) {
+	var called bool
+	info := &params.CAASUnitProvisioningInfo{
+		PodSpec: "spec",
+		Filesystems: []params.KubernetesFilesystemParams{{
+			StorageName: "data", Size: 100, Location: "/srv",
+		}},
+	}
+	client := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(request, gc.Equals, "ProvisioningInfo")
+		c.Assert(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "application-gitlab"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.CAASUnitProvisioningInfoResults{})
+		*(result.(*params.CAASUnitProvisioningInfoResults)) = params.CAASUnitProvisioningInfoResults{
+			Results: []params.CAASUnitProvisioningInfoResult{{Result: info}},
+		}
+		return nil
+	})
+	result, err := client.ProvisioningInfo("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(called, jc.IsTrue)
+	c.Assert(result, jc.DeepEquals, info)
+}
+
+func (s *unitprovisionerSuite) TestProvisioningInfoNotFound(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.CAASUnitProvisioningInfoResults)) = params.CAASUnitProvisioningInfoResults{
+			Results: []params.CAASUnitProvisioningInfoResult{{
+				Error: &params.Error{Code: params.CodeNotFound, Message: "pod spec not found"},
+			}},
+		}
+		return nil
+	})
+	_, err := client.ProvisioningInfo("gitlab")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *unitprovisionerSuite) TestLife(c *gc.C) {
+	tag := "unit-gitlab-0"
+	client := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "Life")
+		c.Assert(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: tag}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.LifeResults{})
+		*(result.(*params.LifeResults)) = params.LifeResults{
+			Results: []params.LifeResult{{Life: params.Alive}},
+		}
+		return nil
+	})
+	life, err := client.Life("gitlab/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(life, gc.Equals, params.Alive)
+
+	tag = "application-gitlab"
+	life, err = client.Life("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(life, gc.Equals, params.Alive)
+}
+
+func (s *unitprovisionerSuite) TestLifeNotFound(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.LifeResults)) = params.LifeResults{
+			Results: []params.LifeResult{{
+				Error: &params.Error{Code: params.CodeNotFound, Message: "bam"},
+			}},
+		}
+		return nil
+	})
+	_, err := client.Life("gitlab")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *unitprovisionerSuite) TestSetContainerStatuses(c *gc.C) {
+	var called bool
+	client := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(request, gc.Equals, "SetContainerStatus")
+		c.Assert(arg, jc.DeepEquals, params.SetStatus{
+			Entities: []params.EntityStatusArgs{
+				{Tag: "unit-gitlab-0", Status: "running", Info: "pod running"},
+				{Tag: "unit-gitlab-1", Status: "waiting"},
+			},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}, {Error: &params.Error{Message: "FAIL"}}},
+		}
+		return nil
+	})
+	err := client.SetContainerStatuses(map[string]status.StatusInfo{
+		"gitlab/1": {Status: status.Waiting},
+		"gitlab/0": {Status: status.Running, Message: "pod running"},
+	})
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(called, jc.IsTrue)
+}
+
+func (s *unitprovisionerSuite) TestSetContainerAddresses(c *gc.C) {
+	var called bool
+	client := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(request, gc.Equals, "SetContainerAddresses")
+		c.Assert(arg, jc.DeepEquals, params.SetUnitContainerAddresses{
+			Args: []params.UnitContainerAddress{
+				{Tag: "unit-gitlab-0", Address: "10.0.0.1"},
+				{Tag: "unit-gitlab-1", Address: "10.0.0.2"},
+			},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}, {}},
+		}
+		return nil
+	})
+	err := client.SetContainerAddresses(map[string]string{
+		"gitlab/1": "10.0.0.2",
+		"gitlab/0": "10.0.0.1",
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(called, jc.IsTrue)
+}
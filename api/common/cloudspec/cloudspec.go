@@ -67,6 +67,7 @@ func (api *CloudSpecAPI) MakeCloudSpec(pSpec *params.CloudSpec) (environs.CloudS
 		IdentityEndpoint: pSpec.IdentityEndpoint,
 		StorageEndpoint:  pSpec.StorageEndpoint,
 		Credential:       credential,
+		CACertificates:   pSpec.CACertificates,
 	}
 	if err := spec.Validate(); err != nil {
 		return environs.CloudSpec{}, errors.Annotate(err, "validating CloudSpec")
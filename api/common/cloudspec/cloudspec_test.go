@@ -49,6 +49,7 @@ func (s *CloudSpecSuite) TestCloudSpec(c *gc.C) {
 						AuthType:   "auth-type",
 						Attributes: map[string]string{"k": "v"},
 					},
+					CACertificates: []string{"cert"},
 				},
 			}},
 		}
@@ -70,6 +71,7 @@ func (s *CloudSpecSuite) TestCloudSpec(c *gc.C) {
 		IdentityEndpoint: "identity-endpoint",
 		StorageEndpoint:  "storage-endpoint",
 		Credential:       &credential,
+		CACertificates:   []string{"cert"},
 	})
 }
 
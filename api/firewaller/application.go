@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/watcher"
 )
 
@@ -75,3 +76,51 @@ func (s *Application) IsExposed() (bool, error) {
 	}
 	return result.Result, nil
 }
+
+// ExposeInfo returns the application's expose settings, including any
+// endpoint specific settings along with the endpoint bindings and space
+// subnets needed to apply them.
+func (s *Application) ExposeInfo() (params.ExposeInfoResult, error) {
+	var results params.ExposeInfoResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetExposeInfo", args, &results)
+	if err != nil {
+		return params.ExposeInfoResult{}, err
+	}
+	if len(results.Results) != 1 {
+		return params.ExposeInfoResult{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.ExposeInfoResult{}, result.Error
+	}
+	return result, nil
+}
+
+// EgressRules returns the rules restricting the destinations to which
+// the application's units may send traffic. No rules means that
+// outbound traffic is not restricted.
+func (s *Application) EgressRules() ([]network.EgressRule, error) {
+	var results params.EgressRulesResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetEgressRules", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	var rules []network.EgressRule
+	for _, rule := range result.Rules {
+		rules = append(rules, rule.NetworkEgressRule())
+	}
+	return rules, nil
+}
@@ -255,3 +255,36 @@ func (c *Client) FirewallRules(knownServices ...string) ([]params.FirewallRule,
 	}
 	return results.Rules, nil
 }
+
+// ApplicationFirewallRules returns the firewall rules which restrict
+// ingress to the specified application when it is exposed.
+func (c *Client) ApplicationFirewallRules(tag names.ApplicationTag) ([]params.FirewallRule, error) {
+	args := params.Entities{[]params.Entity{{Tag: tag.String()}}}
+	var results params.FirewallRulesResults
+	err := c.facade.FacadeCall("ApplicationFirewallRules", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Rules, nil
+}
+
+// WatchFirewallRules returns a NotifyWatcher that notifies of
+// changes to the firewall rules for the current model.
+func (c *Client) WatchFirewallRules() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	err := c.facade.FacadeCall("WatchFirewallRules", nil, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result), nil
+}
@@ -246,3 +246,38 @@ func (s *firewallerSuite) TestFirewallRules(c *gc.C) {
 	c.Assert(result, gc.HasLen, 1)
 	c.Check(callCount, gc.Equals, 1)
 }
+
+func (s *firewallerSuite) TestApplicationFirewallRules(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Firewaller")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "ApplicationFirewallRules")
+		c.Assert(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "application-wordpress"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.FirewallRulesResults{})
+		*(result.(*params.FirewallRulesResults)) = params.FirewallRulesResults{
+			Results: []params.FirewallRulesResult{{
+				Rules: []params.FirewallRule{{
+					KnownService:   params.ApplicationRule,
+					Application:    "wordpress",
+					WhitelistCIDRS: []string{"10.0.0.0/16"},
+				}},
+			}},
+		}
+		callCount++
+		return nil
+	})
+	client, err := firewaller.NewClient(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := client.ApplicationFirewallRules(names.NewApplicationTag("wordpress"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, []params.FirewallRule{{
+		KnownService:   params.ApplicationRule,
+		Application:    "wordpress",
+		WhitelistCIDRS: []string{"10.0.0.0/16"},
+	}})
+	c.Check(callCount, gc.Equals, 1)
+}
@@ -5,9 +5,11 @@ package firewallrules
 
 import (
 	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 )
 
 // Client allows access to the firewall rules API end point.
@@ -44,6 +46,61 @@ func (c *Client) SetFirewallRule(service string, whiteListCidrs []string) error
 	return results.OneError()
 }
 
+// SetApplicationFirewallRule creates or updates the firewall rules
+// restricting ingress to the ports opened by the specified application.
+// If no port ranges are specified, the rule applies to all ports.
+func (c *Client) SetApplicationFirewallRule(application string, portRanges []network.PortRange, whiteListCidrs []string) error {
+	if !names.IsValidApplication(application) {
+		return errors.NotValidf("application name %q", application)
+	}
+	rule := params.FirewallRule{
+		KnownService:   params.ApplicationRule,
+		Application:    application,
+		WhitelistCIDRS: whiteListCidrs,
+	}
+	var args params.FirewallRuleArgs
+	if len(portRanges) == 0 {
+		args.Args = []params.FirewallRule{rule}
+	}
+	for _, portRange := range portRanges {
+		paramsPortRange := params.FromNetworkPortRange(portRange)
+		rule.PortRange = &paramsPortRange
+		args.Args = append(args.Args, rule)
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetFirewallRules", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
+
+// SetEgressRules replaces the egress rules of the specified application.
+// Calling SetEgressRules with no rules removes any egress restrictions
+// for the application.
+func (c *Client) SetEgressRules(application string, rules []network.EgressRule) error {
+	if c.BestAPIVersion() < 2 {
+		return errors.New("this juju controller does not support egress rules")
+	}
+	if !names.IsValidApplication(application) {
+		return errors.NotValidf("application name %q", application)
+	}
+	arg := params.ApplicationEgressRules{
+		ApplicationTag: names.NewApplicationTag(application).String(),
+		Rules:          make([]params.EgressRule, len(rules)),
+	}
+	for i, rule := range rules {
+		arg.Rules[i] = params.FromNetworkEgressRule(rule)
+	}
+	args := params.ApplicationEgressRulesArgs{
+		Args: []params.ApplicationEgressRules{arg},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetEgressRules", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // ListFirewallRules returns all the firewall rules.
 func (c *Client) ListFirewallRules() ([]params.FirewallRule, error) {
 	var results params.ListFirewallRulesResults
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/api/firewallrules"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/testing"
 )
 
@@ -86,6 +87,64 @@ func (s *FirewallRulesSuite) TestSetFirewallRuleInvalid(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `known service "foo" not valid`)
 }
 
+func (s *FirewallRulesSuite) TestSetApplicationFirewallRule(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "FirewallRules")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "SetFirewallRules")
+
+			called = true
+			args, ok := a.(params.FirewallRuleArgs)
+			c.Assert(ok, jc.IsTrue)
+			c.Assert(args.Args, jc.DeepEquals, []params.FirewallRule{{
+				KnownService:   params.ApplicationRule,
+				Application:    "mysql",
+				PortRange:      &params.PortRange{FromPort: 3306, ToPort: 3306, Protocol: "tcp"},
+				WhitelistCIDRS: []string{"10.0.0.0/8"},
+			}, {
+				KnownService:   params.ApplicationRule,
+				Application:    "mysql",
+				PortRange:      &params.PortRange{FromPort: 8000, ToPort: 8080, Protocol: "tcp"},
+				WhitelistCIDRS: []string{"10.0.0.0/8"},
+			}})
+
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{}, {}}
+			}
+			return nil
+		})
+
+	client := firewallrules.NewClient(apiCaller)
+	err := client.SetApplicationFirewallRule("mysql", []network.PortRange{
+		network.MustParsePortRange("3306/tcp"),
+		network.MustParsePortRange("8000-8080/tcp"),
+	}, []string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *FirewallRulesSuite) TestSetApplicationFirewallRuleInvalid(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Fail()
+			return errors.New("unexpected")
+		})
+
+	client := firewallrules.NewClient(apiCaller)
+	err := client.SetApplicationFirewallRule("-foo", nil, []string{"192.168.1.0/32"})
+	c.Assert(err, gc.ErrorMatches, `application name "-foo" not valid`)
+}
+
 func (s *FirewallRulesSuite) TestList(c *gc.C) {
 	called := false
 	apiCaller := basetesting.APICallerFunc(
@@ -141,3 +200,56 @@ func (s *FirewallRulesSuite) TestListError(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.ErrorMatches, "fail")
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *FirewallRulesSuite) TestSetEgressRules(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "FirewallRules")
+				c.Check(id, gc.Equals, "")
+				c.Check(request, gc.Equals, "SetEgressRules")
+				c.Assert(a, jc.DeepEquals, params.ApplicationEgressRulesArgs{
+					Args: []params.ApplicationEgressRules{{
+						ApplicationTag: "application-mysql",
+						Rules: []params.EgressRule{{
+							PortRange:        params.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"},
+							DestinationCIDRs: []string{"10.0.0.0/8"},
+						}},
+					}},
+				})
+				if results, ok := result.(*params.ErrorResults); ok {
+					results.Results = []params.ErrorResult{{
+						Error: common.ServerError(errors.New("fail"))}}
+				}
+				return nil
+			}),
+		BestVersion: 2,
+	}
+	client := firewallrules.NewClient(apiCaller)
+	err := client.SetEgressRules("mysql", []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+	})
+	c.Assert(err, gc.ErrorMatches, "fail")
+}
+
+func (s *FirewallRulesSuite) TestSetEgressRulesNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Fatalf("unexpected API call")
+				return nil
+			}),
+		BestVersion: 1,
+	}
+	client := firewallrules.NewClient(apiCaller)
+	err := client.SetEgressRules("mysql", nil)
+	c.Assert(err, gc.ErrorMatches, "this juju controller does not support egress rules")
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/replicaset"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
@@ -55,6 +56,37 @@ func (c *Client) EnableHA(
 	return result.Result, nil
 }
 
+// RemoveControllerMachine arranges for the controller machines with the
+// given ids to be removed. Any voting controller removed is replaced by
+// a new one, keeping the number of voting controllers unchanged.
+func (c *Client) RemoveControllerMachine(machineIds ...string) ([]params.ControllersChanges, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("removing controller machines")
+	}
+	arg := params.Entities{Entities: make([]params.Entity, len(machineIds))}
+	for i, id := range machineIds {
+		if !names.IsValidMachine(id) {
+			return nil, errors.NotValidf("machine ID %q", id)
+		}
+		arg.Entities[i].Tag = names.NewMachineTag(id).String()
+	}
+	var results params.ControllersChangeResults
+	if err := c.facade.FacadeCall("RemoveControllerMachine", arg, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(machineIds) {
+		return nil, errors.Errorf("expected %d results, got %d", len(machineIds), len(results.Results))
+	}
+	changes := make([]params.ControllersChanges, len(results.Results))
+	for i, result := range results.Results {
+		if result.Error != nil {
+			return nil, errors.Annotatef(result.Error, "machine %s", machineIds[i])
+		}
+		changes[i] = result.Result
+	}
+	return changes, nil
+}
+
 // MongoUpgradeMode will make all Slave members of the HA
 // to shut down their mongo server.
 func (c *Client) MongoUpgradeMode(v mongo.Version) (params.MongoUpgradeResults, error) {
@@ -77,5 +77,30 @@ func (s *clientSuite) TestClientEnableHA(c *gc.C) {
 
 func (s *clientSuite) TestClientEnableHAVersion(c *gc.C) {
 	client := highavailability.NewClient(s.APIState)
-	c.Assert(client.BestAPIVersion(), gc.Equals, 2)
+	c.Assert(client.BestAPIVersion(), gc.Equals, 3)
+}
+
+func (s *clientSuite) TestClientRemoveControllerMachine(c *gc.C) {
+	assertEnableHA(c, &s.JujuConnSuite)
+	pinger0 := setAgentPresence(c, &s.JujuConnSuite, "0")
+	defer assertKill(c, pinger0)
+	pinger2 := setAgentPresence(c, &s.JujuConnSuite, "2")
+	defer assertKill(c, pinger2)
+
+	client := highavailability.NewClient(s.APIState)
+	changes, err := client.RemoveControllerMachine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, gc.HasLen, 1)
+	c.Assert(changes[0].Demoted, gc.DeepEquals, []string{"machine-1"})
+	c.Assert(changes[0].Added, gc.DeepEquals, []string{"machine-3"})
+
+	m, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.IsDecommissioning(), jc.IsTrue)
+}
+
+func (s *clientSuite) TestClientRemoveControllerMachineInvalidId(c *gc.C) {
+	client := highavailability.NewClient(s.APIState)
+	_, err := client.RemoveControllerMachine("foo")
+	c.Assert(err, gc.ErrorMatches, `machine ID "foo" not valid`)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 )
 
 const machineManagerFacade = "MachineManager"
@@ -135,3 +136,49 @@ func (client *Client) UpdateMachineSeries(machineName, series string, force bool
 	}
 	return results.OneError()
 }
+
+// MoveMachine requests that the container with the given id be moved
+// to the host machine with the given id. If live is true, the container
+// keeps running while it is moved.
+func (client *Client) MoveMachine(machineId, hostId string, live bool) error {
+	if client.BestAPIVersion() < 5 {
+		return errors.NotSupportedf("moving machines")
+	}
+	if !names.IsValidMachine(machineId) {
+		return errors.NotValidf("machine ID %q", machineId)
+	}
+	if !names.IsValidMachine(hostId) {
+		return errors.NotValidf("machine ID %q", hostId)
+	}
+	args := params.MoveMachineArgs{
+		Args: []params.MoveMachineArg{{
+			MachineTag: names.NewMachineTag(machineId).String(),
+			HostTag:    names.NewMachineTag(hostId).String(),
+			Live:       live,
+		}},
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("MoveMachine", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// InstanceTypes returns the instance types, with their cost, that match
+// each of the given constraints in the cloud and region of the model.
+func (client *Client) InstanceTypes(cons []constraints.Value) ([]params.InstanceTypesResult, error) {
+	args := params.ModelInstanceTypesConstraints{
+		Constraints: make([]params.ModelInstanceTypesConstraint, len(cons)),
+	}
+	for i := range cons {
+		args.Constraints[i].Value = &cons[i]
+	}
+	var result params.InstanceTypesResults
+	if err := client.facade.FacadeCall("InstanceTypes", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(result.Results); n != len(cons) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(cons), n)
+	}
+	return result.Results, nil
+}
@@ -13,6 +13,7 @@ import (
 	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -210,3 +211,83 @@ func (s *MachinemanagerSuite) TestDestroyMachinesWithParams(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
+
+func (s *MachinemanagerSuite) TestMoveMachine(c *gc.C) {
+	called := false
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			called = true
+			c.Assert(request, gc.Equals, "MoveMachine")
+			c.Assert(a, jc.DeepEquals, params.MoveMachineArgs{
+				Args: []params.MoveMachineArg{{
+					MachineTag: "machine-3-lxd-1",
+					HostTag:    "machine-5",
+					Live:       true,
+				}},
+			})
+			c.Assert(response, gc.FitsTypeOf, &params.ErrorResults{})
+			out := response.(*params.ErrorResults)
+			*out = params.ErrorResults{Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}}}
+			return nil
+		},
+		BestVersion: 5,
+	}
+	client := machinemanager.NewClient(apiCaller)
+	err := client.MoveMachine("3/lxd/1", "5", true)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+
+	err = client.MoveMachine("3/lxd/1", "five", true)
+	c.Assert(err, gc.ErrorMatches, `machine ID "five" not valid`)
+}
+
+func (s *MachinemanagerSuite) TestMoveMachineNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		},
+		BestVersion: 4,
+	}
+	client := machinemanager.NewClient(apiCaller)
+	err := client.MoveMachine("3/lxd/1", "5", false)
+	c.Assert(err, gc.ErrorMatches, "moving machines not supported")
+}
+
+func (s *MachinemanagerSuite) TestInstanceTypes(c *gc.C) {
+	expectedResults := []params.InstanceTypesResult{{
+		InstanceTypes: []params.InstanceType{{Name: "small", Cost: 20}},
+		CostUnit:      "$USD/hour",
+		CostCurrency:  "USD",
+		CostDivisor:   1000,
+	}, {
+		Error: &params.Error{Message: "no instance types"},
+	}}
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Assert(request, gc.Equals, "InstanceTypes")
+		small, big := constraints.MustParse("mem=1G"), constraints.MustParse("mem=1T")
+		c.Assert(a, jc.DeepEquals, params.ModelInstanceTypesConstraints{
+			Constraints: []params.ModelInstanceTypesConstraint{{Value: &small}, {Value: &big}},
+		})
+		c.Assert(response, gc.FitsTypeOf, &params.InstanceTypesResults{})
+		out := response.(*params.InstanceTypesResults)
+		*out = params.InstanceTypesResults{expectedResults}
+		return nil
+	})
+	results, err := client.InstanceTypes([]constraints.Value{
+		constraints.MustParse("mem=1G"),
+		constraints.MustParse("mem=1T"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expectedResults)
+}
+
+func (s *MachinemanagerSuite) TestInstanceTypesResultCount(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		return nil
+	})
+	_, err := client.InstanceTypes([]constraints.Value{{}})
+	c.Assert(err, gc.ErrorMatches, `expected 1 result\(s\), got 0`)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/api/common"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/tools"
 	"github.com/juju/juju/watcher"
@@ -341,3 +342,130 @@ func (st *State) DistributionGroupByMachineId(tags ...names.MachineTag) ([]Distr
 	}
 	return results, nil
 }
+
+// CharmProfiles returns the charm LXD profiles, keyed by profile name,
+// that the given container should have, along with the names of the
+// profiles currently applied to it.
+func (st *State) CharmProfiles(containerTag names.MachineTag) (map[string]lxdprofile.Profile, []string, error) {
+	if st.facade.BestAPIVersion() < 6 {
+		return nil, nil, errors.NotSupportedf("charm LXD profiles")
+	}
+	var results params.CharmProfilesResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: containerTag.String()}},
+	}
+	if err := st.facade.FacadeCall("CharmProfiles", args, &results); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, nil, result.Error
+	}
+	profiles := make(map[string]lxdprofile.Profile)
+	for name, profile := range result.Result.Profiles {
+		profiles[name] = lxdprofile.Profile{
+			Config:      profile.Config,
+			Description: profile.Description,
+			Devices:     profile.Devices,
+		}
+	}
+	return profiles, result.Result.Current, nil
+}
+
+// SetCharmProfiles records the names of the charm LXD profiles
+// applied to the given container.
+func (st *State) SetCharmProfiles(containerTag names.MachineTag, profiles []string) error {
+	if st.facade.BestAPIVersion() < 6 {
+		return errors.NotSupportedf("charm LXD profiles")
+	}
+	var results params.ErrorResults
+	args := params.SetCharmProfilesArgs{
+		Args: []params.SetCharmProfilesArg{{
+			Tag:      containerTag.String(),
+			Profiles: profiles,
+		}},
+	}
+	if err := st.facade.FacadeCall("SetCharmProfiles", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// WatchApplicationCharms returns a NotifyWatcher that notifies when
+// the charm of any application in the model changes.
+func (st *State) WatchApplicationCharms() (watcher.NotifyWatcher, error) {
+	if st.facade.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("watching application charms")
+	}
+	var result params.NotifyWatchResult
+	if err := st.facade.FacadeCall("WatchApplicationCharms", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(st.facade.RawAPICaller(), result), nil
+}
+
+// WatchContainerMoves returns a StringsWatcher that notifies of changes
+// to the moves of containers to or from the given host machine.
+func (st *State) WatchContainerMoves(hostTag names.MachineTag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("watching container moves")
+	}
+	var results params.StringsWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: hostTag.String()}},
+	}
+	if err := st.facade.FacadeCall("WatchContainerMoves", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewStringsWatcher(st.facade.RawAPICaller(), result), nil
+}
+
+// ContainerMove returns the move in progress of the given container.
+func (st *State) ContainerMove(containerTag names.MachineTag) (params.ContainerMove, error) {
+	if st.facade.BestAPIVersion() < 7 {
+		return params.ContainerMove{}, errors.NotSupportedf("container moves")
+	}
+	var results params.ContainerMoveResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: containerTag.String()}},
+	}
+	if err := st.facade.FacadeCall("ContainerMoves", args, &results); err != nil {
+		return params.ContainerMove{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.ContainerMove{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.ContainerMove{}, result.Error
+	}
+	return *result.Result, nil
+}
+
+// UpdateContainerMove records the progress of a container's move.
+func (st *State) UpdateContainerMove(arg params.UpdateContainerMoveArg) error {
+	if st.facade.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("container moves")
+	}
+	var results params.ErrorResults
+	args := params.UpdateContainerMoveArgs{
+		Args: []params.UpdateContainerMoveArg{arg},
+	}
+	if err := st.facade.FacadeCall("UpdateContainerMoves", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
@@ -942,3 +943,163 @@ func (s *prepareContainerSuite) TestPrepareContainerInterfaceInfoSingleNIC(c *gc
 		}},
 	}})
 }
+
+type charmProfilesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&charmProfilesSuite{})
+
+func (s *charmProfilesSuite) TestCharmProfiles(c *gc.C) {
+	apiCaller := apibasetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Provisioner")
+			c.Check(request, gc.Equals, "CharmProfiles")
+			c.Check(arg, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-0-lxd-0"}},
+			})
+			*(result.(*params.CharmProfilesResults)) = params.CharmProfilesResults{
+				Results: []params.CharmProfilesResult{{
+					Result: &params.CharmProfiles{
+						Profiles: map[string]params.CharmLXDProfile{
+							"juju-default-app-1": {
+								Config: map[string]string{"security.nesting": "true"},
+							},
+						},
+						Current: []string{"juju-default-app-0"},
+					},
+				}},
+			}
+			return nil
+		},
+		BestVersion: 6,
+	}
+	st := provisioner.NewState(apiCaller)
+	profiles, current, err := st.CharmProfiles(names.NewMachineTag("0/lxd/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profiles, jc.DeepEquals, map[string]lxdprofile.Profile{
+		"juju-default-app-1": {
+			Config: map[string]string{"security.nesting": "true"},
+		},
+	})
+	c.Assert(current, jc.DeepEquals, []string{"juju-default-app-0"})
+}
+
+func (s *charmProfilesSuite) TestSetCharmProfiles(c *gc.C) {
+	apiCaller := apibasetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(request, gc.Equals, "SetCharmProfiles")
+			c.Check(arg, jc.DeepEquals, params.SetCharmProfilesArgs{
+				Args: []params.SetCharmProfilesArg{{
+					Tag:      "machine-0-lxd-0",
+					Profiles: []string{"juju-default-app-1"},
+				}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		},
+		BestVersion: 6,
+	}
+	st := provisioner.NewState(apiCaller)
+	err := st.SetCharmProfiles(names.NewMachineTag("0/lxd/0"), []string{"juju-default-app-1"})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *charmProfilesSuite) TestCharmProfilesNotSupported(c *gc.C) {
+	apiCaller := apibasetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		},
+		BestVersion: 5,
+	}
+	st := provisioner.NewState(apiCaller)
+	_, _, err := st.CharmProfiles(names.NewMachineTag("0/lxd/0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = st.SetCharmProfiles(names.NewMachineTag("0/lxd/0"), nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = st.WatchApplicationCharms()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+type containerMovesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&containerMovesSuite{})
+
+func (s *containerMovesSuite) TestContainerMove(c *gc.C) {
+	apiCaller := apibasetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Provisioner")
+			c.Check(request, gc.Equals, "ContainerMoves")
+			c.Check(arg, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-0-lxd-0"}},
+			})
+			*(result.(*params.ContainerMoveResults)) = params.ContainerMoveResults{
+				Results: []params.ContainerMoveResult{{
+					Result: &params.ContainerMove{
+						InstanceId: "juju-0-lxd-0",
+						HostTag:    "machine-0",
+						TargetTag:  "machine-1",
+						Phase:      "requested",
+					},
+				}},
+			}
+			return nil
+		},
+		BestVersion: 7,
+	}
+	st := provisioner.NewState(apiCaller)
+	move, err := st.ContainerMove(names.NewMachineTag("0/lxd/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(move, jc.DeepEquals, params.ContainerMove{
+		InstanceId: "juju-0-lxd-0",
+		HostTag:    "machine-0",
+		TargetTag:  "machine-1",
+		Phase:      "requested",
+	})
+}
+
+func (s *containerMovesSuite) TestUpdateContainerMove(c *gc.C) {
+	arg := params.UpdateContainerMoveArg{
+		Tag:     "machine-0-lxd-0",
+		Phase:   params.ContainerMoveFailed,
+		Message: "no route to host",
+	}
+	apiCaller := apibasetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(request, gc.Equals, "UpdateContainerMoves")
+			c.Check(a, jc.DeepEquals, params.UpdateContainerMoveArgs{
+				Args: []params.UpdateContainerMoveArg{arg},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		},
+		BestVersion: 7,
+	}
+	st := provisioner.NewState(apiCaller)
+	err := st.UpdateContainerMove(arg)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *containerMovesSuite) TestContainerMovesNotSupported(c *gc.C) {
+	apiCaller := apibasetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		},
+		BestVersion: 6,
+	}
+	st := provisioner.NewState(apiCaller)
+	_, err := st.WatchContainerMoves(names.NewMachineTag("0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = st.ContainerMove(names.NewMachineTag("0/lxd/0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = st.UpdateContainerMove(params.UpdateContainerMoveArg{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
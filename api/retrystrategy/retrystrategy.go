@@ -43,7 +43,13 @@ func (c *Client) RetryStrategy(agentTag names.Tag) (params.RetryStrategy, error)
 	if result.Error != nil {
 		return params.RetryStrategy{}, errors.Trace(result.Error)
 	}
-	return *result.Result, nil
+	strategy := *result.Result
+	if c.facade.BestAPIVersion() < 2 {
+		// Older controllers have no hook timeout policy, and
+		// so always retry hooks that time out.
+		strategy.RetryTimedOutHooks = true
+	}
+	return strategy, nil
 }
 
 // WatchRetryStrategy returns a notify watcher that looks for changes in the
@@ -6,6 +6,7 @@ package retrystrategy_test
 
 import (
 	"fmt"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -53,6 +54,34 @@ func (s *retryStrategySuite) TestRetryStrategyOk(c *gc.C) {
 	retryStrategy, err := client.RetryStrategy(tag)
 	c.Assert(called, jc.IsTrue)
 	c.Assert(err, jc.ErrorIsNil)
+	// Older controllers always retry hooks that time out.
+	expectedRetryStrategy.RetryTimedOutHooks = true
+	c.Assert(retryStrategy, jc.DeepEquals, expectedRetryStrategy)
+}
+
+func (s *retryStrategySuite) TestRetryStrategyHookTimeout(c *gc.C) {
+	tag := names.NewUnitTag("wp/1")
+	expectedRetryStrategy := params.RetryStrategy{
+		ShouldRetry: true,
+		HookTimeout: 5 * time.Minute,
+	}
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, response interface{}) error {
+			c.Check(objType, gc.Equals, "RetryStrategy")
+			c.Check(version, gc.Equals, 2)
+			c.Check(request, gc.Equals, "RetryStrategy")
+			result := response.(*params.RetryStrategyResults)
+			result.Results = []params.RetryStrategyResult{{
+				Result: &expectedRetryStrategy,
+			}}
+			return nil
+		},
+		BestVersion: 2,
+	}
+
+	client := retrystrategy.NewClient(apiCaller)
+	retryStrategy, err := client.RetryStrategy(tag)
+	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(retryStrategy, jc.DeepEquals, expectedRetryStrategy)
 }
 
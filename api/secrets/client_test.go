@@ -0,0 +1,73 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/secrets"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type SecretsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&SecretsSuite{})
+
+func (s *SecretsSuite) TestListSecrets(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Secrets")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ListSecrets")
+			c.Assert(a, gc.IsNil)
+
+			called = true
+			if results, ok := result.(*params.ListSecretResults); ok {
+				results.Results = []params.SecretMetadata{{
+					Id:       "secret-0",
+					Owner:    "mysql",
+					Label:    "admin",
+					Revision: 1,
+				}}
+			}
+			return nil
+		})
+
+	client := secrets.NewClient(apiCaller)
+	results, err := client.ListSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(results, jc.DeepEquals, []params.SecretMetadata{{
+		Id:       "secret-0",
+		Owner:    "mysql",
+		Label:    "admin",
+		Revision: 1,
+	}})
+}
+
+func (s *SecretsSuite) TestListSecretsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			return errors.New("fail")
+		})
+
+	client := secrets.NewClient(apiCaller)
+	_, err := client.ListSecrets()
+	c.Assert(errors.Cause(err), gc.ErrorMatches, "fail")
+}
@@ -5,6 +5,7 @@ package uniter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"gopkg.in/juju/charm.v6-unstable"
@@ -85,6 +86,33 @@ func (s *Application) CharmModifiedVersion() (int, error) {
 	return result.Result, nil
 }
 
+// UpdateStatusHookInterval returns how often the update-status hook
+// runs for the application's units. This is the application's own
+// interval if it has one, and the model's update-status-hook-interval
+// otherwise. A NotSupported error is returned if the controller does
+// not support per-application intervals.
+func (s *Application) UpdateStatusHookInterval() (time.Duration, error) {
+	if s.st.facade.BestAPIVersion() < 9 {
+		return 0, errors.NotSupportedf("per-application update status intervals")
+	}
+	var results params.UpdateStatusHookIntervalResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("UpdateStatusHookIntervals", args, &results)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return 0, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.Interval, nil
+}
+
 // CharmURL returns the service's charm URL, and whether units should
 // upgrade to the charm with that URL even if they are in an error
 // state (force flag).
@@ -93,6 +93,18 @@ func (s *applicationSuite) TestCharmModifiedVersion(c *gc.C) {
 	c.Assert(ver, gc.Equals, s.wordpressApplication.CharmModifiedVersion())
 }
 
+func (s *applicationSuite) TestUpdateStatusHookInterval(c *gc.C) {
+	interval, err := s.apiApplication.UpdateStatusHookInterval()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(interval, gc.Equals, 5*time.Minute)
+
+	err = s.wordpressApplication.SetUpdateStatusInterval(10 * time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	interval, err = s.apiApplication.UpdateStatusHookInterval()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(interval, gc.Equals, 10*time.Minute)
+}
+
 func (s *applicationSuite) TestSetApplicationStatus(c *gc.C) {
 	message := "a test message"
 	stat, err := s.wordpressApplication.Status()
@@ -0,0 +1,33 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// SetPodSpec sets the pod spec of the specified application. Only the
+// leader unit of the application may set its pod spec.
+func (st *State) SetPodSpec(appName string, spec string) error {
+	if st.BestAPIVersion() < 12 {
+		return errors.NotSupportedf("setting pod spec")
+	}
+	if !names.IsValidApplication(appName) {
+		return errors.NotValidf("application name %q", appName)
+	}
+	args := params.SetPodSpecParams{
+		Specs: []params.EntityString{{
+			Tag:   names.NewApplicationTag(appName).String(),
+			Value: spec,
+		}},
+	}
+	var result params.ErrorResults
+	if err := st.facade.FacadeCall("SetPodSpec", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
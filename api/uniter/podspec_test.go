@@ -0,0 +1,59 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/uniter"
+)
+
+const wordpressSpec = `
+containers:
+  - name: wordpress
+    image: wordpress/latest
+`
+
+type podSpecSuite struct {
+	uniterSuite
+}
+
+var _ = gc.Suite(&podSpecSuite{})
+
+func (s *podSpecSuite) TestSetPodSpec(c *gc.C) {
+	err := s.State.LeadershipClaimer().ClaimLeadership("wordpress", "wordpress/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.uniter.SetPodSpec("wordpress", wordpressSpec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	spec, err := s.wordpressApplication.PodSpec()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, gc.Equals, wordpressSpec)
+}
+
+func (s *podSpecSuite) TestSetPodSpecNotLeader(c *gc.C) {
+	err := s.uniter.SetPodSpec("wordpress", wordpressSpec)
+	c.Assert(err, gc.ErrorMatches, `"wordpress/0" is not leader of "wordpress"`)
+}
+
+func (s *podSpecSuite) TestSetPodSpecInvalidApplication(c *gc.C) {
+	err := s.uniter.SetPodSpec("wordpress/0", wordpressSpec)
+	c.Assert(err, gc.ErrorMatches, `application name "wordpress/0" not valid`)
+}
+
+func (s *podSpecSuite) TestSetPodSpecOldFacadeVersion(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %s", request)
+		return nil
+	})
+	st := uniter.NewState(apiCaller, names.NewUnitTag("wordpress/0"))
+	err := st.SetPodSpec("wordpress", wordpressSpec)
+	c.Assert(err, gc.ErrorMatches, "setting pod spec not supported")
+}
@@ -0,0 +1,168 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/watcher"
+)
+
+// checkSecretsSupported returns an error if the controller does not
+// support charm secrets.
+func (u *Unit) checkSecretsSupported() error {
+	if u.st.facade.BestAPIVersion() < 11 {
+		return errors.NotSupportedf("secrets")
+	}
+	return nil
+}
+
+// AddSecret creates a secret owned by the unit's application, and
+// returns its id.
+func (u *Unit) AddSecret(label string, value map[string]string) (string, error) {
+	if err := u.checkSecretsSupported(); err != nil {
+		return "", err
+	}
+	var results params.StringResults
+	args := params.CreateSecretArgs{
+		Args: []params.CreateSecretArg{{
+			UnitTag: u.tag.String(),
+			Label:   label,
+			Value:   value,
+		}},
+	}
+	if err := u.st.facade.FacadeCall("CreateSecrets", args, &results); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// RotateSecret replaces the value of a secret owned by the unit's
+// application.
+func (u *Unit) RotateSecret(id string, value map[string]string) error {
+	if err := u.checkSecretsSupported(); err != nil {
+		return err
+	}
+	var result params.ErrorResults
+	args := params.RotateSecretArgs{
+		Args: []params.RotateSecretArg{{
+			UnitTag:  u.tag.String(),
+			SecretId: id,
+			Value:    value,
+		}},
+	}
+	if err := u.st.facade.FacadeCall("RotateSecrets", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// GrantSecret grants access to a secret owned by the unit's
+// application to the units at the other end of the relation; or, if
+// grantee is not empty, to just that unit.
+func (u *Unit) GrantSecret(id string, relationTag names.RelationTag, grantee string) error {
+	if err := u.checkSecretsSupported(); err != nil {
+		return err
+	}
+	arg := params.GrantSecretArg{
+		UnitTag:     u.tag.String(),
+		SecretId:    id,
+		RelationTag: relationTag.String(),
+	}
+	if grantee != "" {
+		if !names.IsValidUnit(grantee) {
+			return errors.NotValidf("unit name %q", grantee)
+		}
+		arg.GranteeTag = names.NewUnitTag(grantee).String()
+	}
+	var result params.ErrorResults
+	args := params.GrantSecretArgs{Args: []params.GrantSecretArg{arg}}
+	if err := u.st.facade.FacadeCall("GrantSecrets", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// SecretValue returns the value of a secret readable by the unit.
+func (u *Unit) SecretValue(id string) (map[string]string, error) {
+	if err := u.checkSecretsSupported(); err != nil {
+		return nil, err
+	}
+	var results params.SecretValueResults
+	args := params.GetSecretValueArgs{
+		Args: []params.GetSecretValueArg{{
+			UnitTag:  u.tag.String(),
+			SecretId: id,
+		}},
+	}
+	if err := u.st.facade.FacadeCall("GetSecretValues", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Value, nil
+}
+
+// SecretRevisions returns the current revision of each secret granted
+// to the unit by another application, keyed on secret id.
+func (u *Unit) SecretRevisions() (map[string]int, error) {
+	if u.st.facade.BestAPIVersion() < 11 {
+		// No secrets can have been granted.
+		return nil, nil
+	}
+	var results params.SecretRevisionsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	if err := u.st.facade.FacadeCall("SecretRevisions", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Revisions, nil
+}
+
+// WatchSecrets returns a watcher that notifies of changes to the
+// secrets that may be granted to the unit.
+func (u *Unit) WatchSecrets() (watcher.NotifyWatcher, error) {
+	if err := u.checkSecretsSupported(); err != nil {
+		return nil, err
+	}
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	if err := u.st.facade.FacadeCall("WatchSecrets", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewNotifyWatcher(u.st.facade.RawAPICaller(), result)
+	return w, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/watcher/watchertest"
+)
+
+type secretsSuite struct {
+	uniterSuite
+
+	apiUnit *uniter.Unit
+}
+
+var _ = gc.Suite(&secretsSuite{})
+
+func (s *secretsSuite) SetUpTest(c *gc.C) {
+	s.uniterSuite.SetUpTest(c)
+
+	var err error
+	s.apiUnit, err = s.uniter.Unit(s.wordpressUnit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *secretsSuite) TestAddAndRotateSecret(c *gc.C) {
+	id, err := s.apiUnit.AddSecret("admin", map[string]string{"password": "sekrit"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	value, err := s.apiUnit.SecretValue(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(value, jc.DeepEquals, map[string]string{"password": "sekrit"})
+
+	err = s.apiUnit.RotateSecret(id, map[string]string{"password": "new"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	secret, err := s.State.Secret(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Owner(), gc.Equals, "wordpress")
+	c.Assert(secret.Label(), gc.Equals, "admin")
+	c.Assert(secret.Revision(), gc.Equals, 2)
+}
+
+func (s *secretsSuite) TestGrantSecret(c *gc.C) {
+	rel, _, _ := s.addRelatedApplication(c, "wordpress", "mysql", s.wordpressUnit)
+	id, err := s.apiUnit.AddSecret("", map[string]string{"password": "sekrit"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.apiUnit.GrantSecret(id, rel.Tag().(names.RelationTag), "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	secret, err := s.State.Secret(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Grants(), jc.DeepEquals, []state.SecretGrant{
+		{Relation: rel.String(), Unit: "mysql/0"},
+	})
+}
+
+func (s *secretsSuite) TestSecretValuePermissionDenied(c *gc.C) {
+	secret, err := s.State.AddSecret(state.AddSecretArgs{
+		Owner: "mysql",
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.apiUnit.SecretValue(secret.Id())
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *secretsSuite) TestSecretRevisions(c *gc.C) {
+	rel, _, _ := s.addRelatedApplication(c, "wordpress", "mysql", s.wordpressUnit)
+	secret, err := s.State.AddSecret(state.AddSecretArgs{
+		Owner: "mysql",
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	revisions, err := s.apiUnit.SecretRevisions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revisions, gc.HasLen, 0)
+
+	err = secret.Grant(state.SecretGrant{Relation: rel.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	revisions, err = s.apiUnit.SecretRevisions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revisions, jc.DeepEquals, map[string]int{secret.Id(): 1})
+}
+
+func (s *secretsSuite) TestWatchSecrets(c *gc.C) {
+	w, err := s.apiUnit.WatchSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	wc := watchertest.NewNotifyWatcherC(c, w, s.BackingState.StartSync)
+	defer wc.AssertStops()
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	_, err = s.State.AddSecret(state.AddSecretArgs{
+		Owner: "wordpress",
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
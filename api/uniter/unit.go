@@ -98,6 +98,44 @@ func (u *Unit) SetUnitStatus(unitStatus status.Status, info string, data map[str
 	return result.OneError()
 }
 
+// SetHealthStatus sets the health status of the unit, as found by
+// running the health checks declared by its charm.
+func (u *Unit) SetHealthStatus(healthStatus status.Status, info string, data map[string]interface{}) error {
+	if u.st.facade.BestAPIVersion() < 8 {
+		return errors.NotSupportedf("health checks")
+	}
+	var result params.ErrorResults
+	args := params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: u.tag.String(), Status: healthStatus.String(), Info: info, Data: data},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetHealthStatus", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// RecordHookExecution records an execution of a hook or action by the
+// unit.
+func (u *Unit) RecordHookExecution(execution params.HookExecution) error {
+	if u.st.facade.BestAPIVersion() < 10 {
+		return errors.NotSupportedf("hook execution history")
+	}
+	var result params.ErrorResults
+	args := params.UnitHookExecutions{
+		Args: []params.UnitHookExecution{
+			{Tag: u.tag.String(), Execution: execution},
+		},
+	}
+	err := u.st.facade.FacadeCall("RecordHookExecutions", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
 // UnitStatus gets the status details of the unit.
 func (u *Unit) UnitStatus() (params.StatusResult, error) {
 	var results params.StatusResults
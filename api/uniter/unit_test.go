@@ -122,6 +122,38 @@ func (s *unitSuite) TestSetUnitStatus(c *gc.C) {
 	c.Assert(agentStatusInfo.Data, gc.HasLen, 0)
 }
 
+func (s *unitSuite) TestSetHealthStatus(c *gc.C) {
+	err := s.apiUnit.SetHealthStatus(status.Unhealthy, `check "http" failed`, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	healthInfo, err := s.wordpressUnit.HealthStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(healthInfo.Status, gc.Equals, status.Unhealthy)
+	c.Assert(healthInfo.Message, gc.Equals, `check "http" failed`)
+
+	// The unit's workload status is unaffected.
+	statusInfo, err := s.wordpressUnit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, status.Waiting)
+}
+
+func (s *unitSuite) TestRecordHookExecution(c *gc.C) {
+	started := time.Date(2017, 10, 1, 12, 0, 0, 0, time.UTC)
+	err := s.apiUnit.RecordHookExecution(params.HookExecution{
+		Kind:     "hook",
+		Name:     "install",
+		Started:  started,
+		Finished: started.Add(time.Second),
+		Stdout:   "installed",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	execution, err := s.wordpressUnit.HookExecution(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(execution.Name, gc.Equals, "install")
+	c.Assert(execution.Stdout, gc.Equals, "installed")
+}
+
 func (s *unitSuite) TestUnitStatus(c *gc.C) {
 	now := time.Now()
 	sInfo := status.StatusInfo{
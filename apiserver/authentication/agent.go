@@ -12,7 +12,8 @@ import (
 	"github.com/juju/juju/state"
 )
 
-// AgentIdentityProvider performs authentication for machine and unit agents.
+// AgentIdentityProvider performs authentication for machine, unit and
+// application agents.
 type AgentAuthenticator struct{}
 
 var _ EntityAuthenticator = (*AgentAuthenticator)(nil)
@@ -20,9 +20,11 @@ type agentAuthenticatorSuite struct {
 	machinePassword string
 	machineNonce    string
 	unitPassword    string
+	appPassword     string
 	machine         *state.Machine
 	user            *state.User
 	unit            *state.Unit
+	application     *state.Application
 	relation        *state.Relation
 }
 
@@ -64,6 +66,14 @@ func (s *agentAuthenticatorSuite) SetUpTest(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	s.unitPassword = password
 
+	// set a password for testing application agent authentication
+	password, err = utils.RandomPassword()
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.SetPassword(password)
+	c.Assert(err, jc.ErrorIsNil)
+	s.application = wordpress
+	s.appPassword = password
+
 	// add relation
 	wordpressEP, err := wordpress.Endpoint("db")
 	c.Assert(err, jc.ErrorIsNil)
@@ -97,6 +107,10 @@ func (s *agentAuthenticatorSuite) TestValidLogins(c *gc.C) {
 		entity:      s.unit,
 		credentials: s.unitPassword,
 		about:       "unit login",
+	}, {
+		entity:      s.application,
+		credentials: s.appPassword,
+		about:       "application login",
 	}}
 
 	for i, t := range testCases {
@@ -128,6 +142,11 @@ func (s *agentAuthenticatorSuite) TestInvalidLogins(c *gc.C) {
 		nonce:        "123",
 		about:        "machine login",
 		errorMessage: "machine 0 not provisioned",
+	}, {
+		entity:       s.application,
+		credentials:  "wrong-secret",
+		about:        "application login with wrong password",
+		errorMessage: "invalid entity name or password",
 	}, {
 		entity:       s.user,
 		credentials:  "wrong-secret",
@@ -241,6 +241,9 @@ func (h *charmsHandler) processPost(r *http.Request, st *state.State) (*charm.UR
 	if err := charm.ValidateName(name); err != nil {
 		return nil, errors.NewBadRequest(err, "")
 	}
+	if _, err := application.CharmLXDProfile(archive); err != nil {
+		return nil, errors.NewBadRequest(err, "")
+	}
 
 	// We got it, now let's reserve a charm URL for it in state.
 	curl := &charm.URL{
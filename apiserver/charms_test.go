@@ -197,6 +197,40 @@ func (s *charmsSuite) TestUploadBumpsRevision(c *gc.C) {
 	c.Assert(sch.BundleSha256(), gc.Not(gc.Equals), "")
 }
 
+func (s *charmsSuite) TestUploadStoresLXDProfile(c *gc.C) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "lxd-profile")
+	resp := s.uploadRequest(c, s.charmsURI(c, "?series=quantal"), "application/zip", ch.Path)
+	expectedURL := charm.MustParseURL("local:quantal/lxd-profile-1")
+	s.assertUploadResponse(c, resp, expectedURL.String())
+	sch, err := s.State.Charm(expectedURL)
+	c.Assert(err, jc.ErrorIsNil)
+	profile := sch.LXDProfile()
+	c.Assert(profile, gc.NotNil)
+	c.Assert(profile.Config["security.nesting"], gc.Equals, "true")
+	c.Assert(profile.Devices["sony"], jc.DeepEquals, map[string]string{
+		"type":      "usb",
+		"vendorid":  "0fce",
+		"productid": "51da",
+	})
+}
+
+func (s *charmsSuite) TestUploadRejectsDisallowedLXDProfile(c *gc.C) {
+	dir := testcharms.Repo.ClonedDir(c.MkDir(), "lxd-profile")
+	err := ioutil.WriteFile(filepath.Join(dir.Path, "lxd-profile.yaml"), []byte(`
+config:
+  boot.autostart: "false"
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	tempFile, err := ioutil.TempFile(c.MkDir(), "charm")
+	c.Assert(err, jc.ErrorIsNil)
+	defer tempFile.Close()
+	err = dir.ArchiveTo(tempFile)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.uploadRequest(c, s.charmsURI(c, "?series=quantal"), "application/zip", tempFile.Name())
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, `.*invalid charm: lxd profile: config "boot.autostart" not allowed not valid$`)
+}
+
 func (s *charmsSuite) TestUploadRespectsLocalRevision(c *gc.C) {
 	// Make a dummy charm dir with revision 123.
 	dir := testcharms.Repo.ClonedDir(c.MkDir(), "dummy")
@@ -81,6 +81,7 @@ func (s cloudSpecAPI) GetCloudSpec(tag names.ModelTag) params.CloudSpecResult {
 		spec.IdentityEndpoint,
 		spec.StorageEndpoint,
 		paramsCloudCredential,
+		spec.CACertificates,
 	}
 	return result
 }
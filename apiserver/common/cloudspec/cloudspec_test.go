@@ -58,6 +58,7 @@ func (s *CloudSpecSuite) SetUpTest(c *gc.C) {
 		"identity-endpoint",
 		"storage-endpoint",
 		&credential,
+		[]string{"cert"},
 	}
 }
 
@@ -82,6 +83,7 @@ func (s *CloudSpecSuite) TestCloudSpec(c *gc.C) {
 				AuthType:   "auth-type",
 				Attributes: map[string]string{"k": "v"},
 			},
+			[]string{"cert"},
 		},
 	}, {
 		Error: &params.Error{
@@ -116,6 +118,7 @@ func (s *CloudSpecSuite) TestCloudSpecNilCredential(c *gc.C) {
 			"identity-endpoint",
 			"storage-endpoint",
 			nil,
+			[]string{"cert"},
 		},
 	}})
 }
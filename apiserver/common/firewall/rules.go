@@ -0,0 +1,25 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+// FirewallRuleToParams converts a state firewall rule into its
+// API representation.
+func FirewallRuleToParams(rule *state.FirewallRule) params.FirewallRule {
+	result := params.FirewallRule{
+		KnownService:   params.KnownServiceValue(rule.WellKnownService),
+		Application:    rule.Application,
+		WhitelistCIDRS: rule.WhitelistCIDRs,
+	}
+	if rule.PortRange != (network.PortRange{}) {
+		portRange := params.FromNetworkPortRange(rule.PortRange)
+		result.PortRange = &portRange
+	}
+	return result
+}
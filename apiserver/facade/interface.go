@@ -97,6 +97,10 @@ type Authorizer interface {
 	// func if anything.
 	AuthUnitAgent() bool
 
+	// AuthApplicationAgent returns true if the entity is an
+	// application operator agent.
+	AuthApplicationAgent() bool
+
 	// AuthOwner returns true if tag == .GetAuthTag(). Doesn't need
 	// to be on this interface, should be a utility fun if anything.
 	AuthOwner(tag names.Tag) bool
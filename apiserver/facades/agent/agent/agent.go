@@ -37,7 +37,7 @@ type AgentAPIV2 struct {
 // with the given authorizer representing the currently logged in client.
 func NewAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Authorizer) (*AgentAPIV2, error) {
 	// Agents are defined to be any user that's not a client user.
-	if !auth.AuthMachineAgent() && !auth.AuthUnitAgent() {
+	if !auth.AuthMachineAgent() && !auth.AuthUnitAgent() && !auth.AuthApplicationAgent() {
 		return nil, common.ErrPerm
 	}
 	getCanChange := func() (common.AuthFunc, error) {
@@ -122,6 +122,9 @@ func (api *AgentAPIV2) StateServingInfo() (result params.StateServingInfo, err e
 		CAPrivateKey:   info.CAPrivateKey,
 		SharedSecret:   info.SharedSecret,
 		SystemIdentity: info.SystemIdentity,
+		// The secrets key isn't stored in the database, so it is
+		// taken from this controller's configuration.
+		SecretsKey: api.st.SecretsKey(),
 	}
 
 	return result, nil
@@ -83,6 +83,13 @@ func (s *agentSuite) TestAgentSucceedsWithUnitAgent(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *agentSuite) TestAgentSucceedsWithApplicationAgent(c *gc.C) {
+	auth := s.authorizer
+	auth.Tag = names.NewApplicationTag("gitlab")
+	_, err := agent.NewAgentAPIV2(s.State, s.resources, auth)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *agentSuite) TestGetEntities(c *gc.C) {
 	err := s.container.Destroy()
 	c.Assert(err, jc.ErrorIsNil)
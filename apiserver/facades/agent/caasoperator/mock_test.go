@@ -0,0 +1,128 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/apiserver/facades/agent/caasoperator"
+	"github.com/juju/juju/state"
+)
+
+type mockState struct {
+	testing.Stub
+	application mockApplication
+	unit        mockUnit
+}
+
+func (st *mockState) Application(name string) (caasoperator.Application, error) {
+	st.MethodCall(st, "Application", name)
+	if name != "gitlab" {
+		return nil, errors.NotFoundf("application %v", name)
+	}
+	return &st.application, nil
+}
+
+func (st *mockState) FindEntity(tag names.Tag) (state.Entity, error) {
+	st.MethodCall(st, "FindEntity", tag)
+	switch tag {
+	case names.NewApplicationTag("gitlab"):
+		return &st.application, nil
+	case names.NewUnitTag("gitlab/0"):
+		return &st.unit, nil
+	}
+	return nil, errors.NotFoundf("%s", names.ReadableString(tag))
+}
+
+type mockApplication struct {
+	testing.Stub
+	life         state.Life
+	unitsWatcher *mockStringsWatcher
+}
+
+func (a *mockApplication) Tag() names.Tag {
+	return names.NewApplicationTag("gitlab")
+}
+
+func (a *mockApplication) Life() state.Life {
+	a.MethodCall(a, "Life")
+	return a.life
+}
+
+func (a *mockApplication) WatchUnits() state.StringsWatcher {
+	a.MethodCall(a, "WatchUnits")
+	return a.unitsWatcher
+}
+
+type mockUnit struct {
+	testing.Stub
+	life state.Life
+}
+
+func (u *mockUnit) Tag() names.Tag {
+	return names.NewUnitTag("gitlab/0")
+}
+
+func (u *mockUnit) Life() state.Life {
+	u.MethodCall(u, "Life")
+	return u.life
+}
+
+func (u *mockUnit) Refresh() error {
+	u.MethodCall(u, "Refresh")
+	return u.NextErr()
+}
+
+func (u *mockUnit) SetPassword(password string) error {
+	u.MethodCall(u, "SetPassword", password)
+	return u.NextErr()
+}
+
+func (u *mockUnit) PasswordValid(password string) bool {
+	u.MethodCall(u, "PasswordValid", password)
+	return false
+}
+
+type mockWatcher struct {
+	testing.Stub
+	tomb.Tomb
+}
+
+func (w *mockWatcher) doneWhenDying() {
+	<-w.Tomb.Dying()
+	w.Tomb.Done()
+}
+
+func (w *mockWatcher) Kill() {
+	w.MethodCall(w, "Kill")
+	w.Tomb.Kill(nil)
+}
+
+func (w *mockWatcher) Stop() error {
+	w.MethodCall(w, "Stop")
+	if err := w.NextErr(); err != nil {
+		return err
+	}
+	w.Tomb.Kill(nil)
+	return w.Tomb.Wait()
+}
+
+type mockStringsWatcher struct {
+	mockWatcher
+	changes chan []string
+}
+
+func newMockStringsWatcher() *mockStringsWatcher {
+	w := &mockStringsWatcher{changes: make(chan []string, 1)}
+	go w.doneWhenDying()
+	return w
+}
+
+func (w *mockStringsWatcher) Changes() <-chan []string {
+	w.MethodCall(w, "Changes")
+	return w.changes
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state/watcher"
+)
+
+// Facade provides access to the CAASOperator API facade, used by
+// the operator agent that runs hooks on behalf of the units of a
+// CAAS application.
+type Facade struct {
+	*common.LifeGetter
+	*common.PasswordChanger
+
+	resources facade.Resources
+	state     CAASOperatorState
+	canAccess common.AuthFunc
+}
+
+// NewStateFacade provides the signature required for facade registration.
+func NewStateFacade(ctx facade.Context) (*Facade, error) {
+	return NewFacade(
+		ctx.Resources(),
+		ctx.Auth(),
+		stateShim{ctx.State()},
+	)
+}
+
+// NewFacade returns a new CAASOperator facade.
+func NewFacade(
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+	st CAASOperatorState,
+) (*Facade, error) {
+	if !authorizer.AuthApplicationAgent() {
+		return nil, common.ErrPerm
+	}
+	appTag, ok := authorizer.GetAuthTag().(names.ApplicationTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	// The operator may act on its own application, and on the
+	// units of that application.
+	canAccess := func(tag names.Tag) bool {
+		switch tag := tag.(type) {
+		case names.ApplicationTag:
+			return tag == appTag
+		case names.UnitTag:
+			appName, err := names.UnitApplication(tag.Id())
+			return err == nil && appName == appTag.Id()
+		}
+		return false
+	}
+	getCanAccess := func() (common.AuthFunc, error) {
+		return canAccess, nil
+	}
+	getCanChangeUnitPassword := func() (common.AuthFunc, error) {
+		return func(tag names.Tag) bool {
+			return tag.Kind() == names.UnitTagKind && canAccess(tag)
+		}, nil
+	}
+	return &Facade{
+		LifeGetter:      common.NewLifeGetter(st, getCanAccess),
+		PasswordChanger: common.NewPasswordChanger(st, getCanChangeUnitPassword),
+		resources:       resources,
+		state:           st,
+		canAccess:       canAccess,
+	}, nil
+}
+
+// WatchUnits starts a StringsWatcher to watch changes to the
+// lifecycle states of units of the specified applications, which
+// must be the operator's own application.
+func (f *Facade) WatchUnits(args params.Entities) (params.StringsWatchResults, error) {
+	results := params.StringsWatchResults{
+		Results: make([]params.StringsWatchResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		id, changes, err := f.watchUnits(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].StringsWatcherId = id
+		results.Results[i].Changes = changes
+	}
+	return results, nil
+}
+
+func (f *Facade) watchUnits(tagString string) (string, []string, error) {
+	tag, err := names.ParseApplicationTag(tagString)
+	if err != nil {
+		return "", nil, common.ErrPerm
+	}
+	if !f.canAccess(tag) {
+		return "", nil, common.ErrPerm
+	}
+	app, err := f.state.Application(tag.Id())
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	w := app.WatchUnits()
+	if changes, ok := <-w.Changes(); ok {
+		return f.resources.Register(w), changes, nil
+	}
+	return "", nil, watcher.EnsureErr(w)
+}
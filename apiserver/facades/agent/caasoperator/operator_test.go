@@ -0,0 +1,128 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/agent/caasoperator"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&CAASOperatorSuite{})
+
+type CAASOperatorSuite struct {
+	coretesting.BaseSuite
+
+	resources  *common.Resources
+	authorizer *apiservertesting.FakeAuthorizer
+	st         *mockState
+	facade     *caasoperator.Facade
+}
+
+func (s *CAASOperatorSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.st = &mockState{
+		application: mockApplication{
+			life:         state.Alive,
+			unitsWatcher: newMockStringsWatcher(),
+		},
+	}
+	s.AddCleanup(func(c *gc.C) { stopWatcher(c, &s.st.application.unitsWatcher.mockWatcher) })
+
+	s.resources = common.NewResources()
+	s.authorizer = &apiservertesting.FakeAuthorizer{
+		Tag: names.NewApplicationTag("gitlab"),
+	}
+
+	facade, err := caasoperator.NewFacade(s.resources, s.authorizer, s.st)
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade = facade
+}
+
+func stopWatcher(c *gc.C, w *mockWatcher) {
+	w.Kill()
+	c.Check(w.Wait(), jc.ErrorIsNil)
+}
+
+func (s *CAASOperatorSuite) TestPermission(c *gc.C) {
+	for _, tag := range []names.Tag{
+		names.NewMachineTag("0"),
+		names.NewUnitTag("gitlab/0"),
+		names.NewUserTag("admin"),
+	} {
+		s.authorizer = &apiservertesting.FakeAuthorizer{Tag: tag}
+		_, err := caasoperator.NewFacade(s.resources, s.authorizer, s.st)
+		c.Check(err, gc.ErrorMatches, "permission denied")
+	}
+}
+
+func (s *CAASOperatorSuite) TestWatchUnits(c *gc.C) {
+	unitNames := []string{"gitlab/0", "gitlab/1"}
+	s.st.application.unitsWatcher.changes <- unitNames
+
+	results, err := s.facade.WatchUnits(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "application-mysql"},
+			{Tag: "unit-gitlab-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StringsWatchResult{{
+		StringsWatcherId: "1",
+		Changes:          unitNames,
+	}, {
+		Error: &params.Error{Code: params.CodeUnauthorized, Message: "permission denied"},
+	}, {
+		Error: &params.Error{Code: params.CodeUnauthorized, Message: "permission denied"},
+	}})
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.Equals, s.st.application.unitsWatcher)
+}
+
+func (s *CAASOperatorSuite) TestLife(c *gc.C) {
+	s.st.application.life = state.Dying
+	results, err := s.facade.Life(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "unit-gitlab-0"},
+			{Tag: "unit-mysql-0"},
+			{Tag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.LifeResult{
+		{Life: params.Dying},
+		{Life: params.Alive},
+		{Error: &params.Error{Code: params.CodeUnauthorized, Message: "permission denied"}},
+		{Error: &params.Error{Code: params.CodeUnauthorized, Message: "permission denied"}},
+	})
+}
+
+func (s *CAASOperatorSuite) TestSetPasswords(c *gc.C) {
+	results, err := s.facade.SetPasswords(params.EntityPasswords{
+		Changes: []params.EntityPassword{
+			{Tag: "unit-gitlab-0", Password: "xxx-12345678901234567890"},
+			{Tag: "application-gitlab", Password: "yyy-12345678901234567890"},
+			{Tag: "unit-mysql-0", Password: "zzz-12345678901234567890"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{},
+		{apiservertesting.ErrUnauthorized},
+		{apiservertesting.ErrUnauthorized},
+	})
+	s.st.unit.CheckCallNames(c, "SetPassword")
+	s.st.unit.CheckCall(c, 0, "SetPassword", "xxx-12345678901234567890")
+}
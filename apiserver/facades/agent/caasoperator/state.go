@@ -0,0 +1,35 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator
+
+import (
+	"github.com/juju/juju/state"
+)
+
+// CAASOperatorState provides the subset of global state
+// required by the CAAS operator facade.
+type CAASOperatorState interface {
+	state.EntityFinder
+
+	// Application returns the application with the given name.
+	Application(string) (Application, error)
+}
+
+// Application provides the subset of application state required
+// by the CAAS operator facade.
+type Application interface {
+	WatchUnits() state.StringsWatcher
+}
+
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) Application(name string) (Application, error) {
+	app, err := s.State.Application(name)
+	if err != nil {
+		return nil, err
+	}
+	return app, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/lxdprofile"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// CharmProfiles returns, for each given container, the LXD profiles
+// declared by the charms of the applications with units on it, along
+// with the names of the profiles currently applied to it.
+func (p *ProvisionerAPIV6) CharmProfiles(args params.Entities) (params.CharmProfilesResults, error) {
+	result := params.CharmProfilesResults{
+		Results: make([]params.CharmProfilesResult, len(args.Entities)),
+	}
+	canAccess, err := p.getAuthFunc()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := p.getMachine(canAccess, tag)
+		if err == nil {
+			result.Results[i].Result, err = p.machineCharmProfiles(machine)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (p *ProvisionerAPIV6) machineCharmProfiles(m *state.Machine) (*params.CharmProfiles, error) {
+	units, err := m.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	profiles := make(map[string]params.CharmLXDProfile)
+	for _, unit := range units {
+		app, err := unit.Application()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ch, _, err := app.Charm()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		profile := ch.LXDProfile()
+		if profile.Empty() {
+			continue
+		}
+		name := lxdprofile.Name(p.m.Name(), app.Name(), ch.Revision())
+		profiles[name] = params.CharmLXDProfile{
+			Config:      profile.Config,
+			Description: profile.Description,
+			Devices:     profile.Devices,
+		}
+	}
+	return &params.CharmProfiles{
+		Profiles: profiles,
+		Current:  m.CharmProfiles(),
+	}, nil
+}
+
+// SetCharmProfiles records the names of the charm LXD profiles
+// applied to each given container.
+func (p *ProvisionerAPIV6) SetCharmProfiles(args params.SetCharmProfilesArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := p.getAuthFunc()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Args {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := p.getMachine(canAccess, tag)
+		if err == nil {
+			err = machine.SetCharmProfiles(arg.Profiles)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WatchApplicationCharms returns a NotifyWatcher that notifies when
+// the charm of any application in the model changes, so that hosts
+// can update the charm LXD profiles of their containers.
+func (p *ProvisionerAPIV6) WatchApplicationCharms() (params.NotifyWatchResult, error) {
+	result := params.NotifyWatchResult{}
+	watch := p.st.WatchApplicationCharms()
+	// Consume the initial event and forward it to the result.
+	if _, ok := <-watch.Changes(); ok {
+		result.NotifyWatcherId = p.resources.Register(watch)
+	} else {
+		return result, watcher.EnsureErr(watch)
+	}
+	return result, nil
+}
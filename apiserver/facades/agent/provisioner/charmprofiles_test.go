@@ -0,0 +1,125 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/agent/provisioner"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type CharmProfilesSuite struct {
+	provisionerSuite
+
+	container   *state.Machine
+	provisioner *provisioner.ProvisionerAPIV6
+}
+
+var _ = gc.Suite(&CharmProfilesSuite{})
+
+func (s *CharmProfilesSuite) SetUpTest(c *gc.C) {
+	s.provisionerSuite.SetUpTest(c)
+
+	var err error
+	s.container, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.machines[0].Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Log in as the container's host.
+	authorizer := apiservertesting.FakeAuthorizer{Tag: s.machines[0].Tag()}
+	s.provisioner, err = provisioner.NewProvisionerAPIV6(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CharmProfilesSuite) addUnit(c *gc.C, name string, m *state.Machine) {
+	app := s.AddTestingApplication(c, name, s.AddTestingCharm(c, name))
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CharmProfilesSuite) TestCharmProfiles(c *gc.C) {
+	s.addUnit(c, "lxd-profile", s.container)
+	s.addUnit(c, "mysql", s.container)
+	err := s.container.SetCharmProfiles([]string{"juju-controller-lxd-profile-0"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.provisioner.CharmProfiles(params.Entities{Entities: []params.Entity{
+		{Tag: s.container.Tag().String()},
+		{Tag: s.machines[1].Tag().String()},
+		{Tag: "application-mysql"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	profiles := result.Results[0].Result
+	c.Assert(profiles.Current, jc.DeepEquals, []string{"juju-controller-lxd-profile-0"})
+	c.Assert(profiles.Profiles, gc.HasLen, 1)
+	profile, ok := profiles.Profiles["juju-controller-lxd-profile-1"]
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(profile.Description, gc.Equals, "lxd profile for testing")
+	c.Assert(profile.Config["security.nesting"], gc.Equals, "true")
+	c.Assert(profile.Devices["sony"]["type"], gc.Equals, "usb")
+	c.Assert(result.Results[1].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+}
+
+func (s *CharmProfilesSuite) TestSetCharmProfiles(c *gc.C) {
+	result, err := s.provisioner.SetCharmProfiles(params.SetCharmProfilesArgs{
+		Args: []params.SetCharmProfilesArg{
+			{Tag: s.container.Tag().String(), Profiles: []string{"juju-controller-lxd-profile-1"}},
+			{Tag: s.machines[1].Tag().String(), Profiles: []string{"juju-controller-lxd-profile-1"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.container.CharmProfiles(), jc.DeepEquals, []string{"juju-controller-lxd-profile-1"})
+}
+
+func (s *CharmProfilesSuite) TestWatchApplicationCharms(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	result, err := s.provisioner.WatchApplicationCharms()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "1"})
+
+	// Verify the resource was registered and stop it when done.
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	s.addUnit(c, "lxd-profile", s.container)
+	wc.AssertOneChange()
+}
+
+func (s *CharmProfilesSuite) TestOlderVersionsLackCharmProfiles(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	api, err := provisioner.NewProvisionerAPIV5(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := interface{}(api).(interface {
+		CharmProfiles(params.Entities) (params.CharmProfilesResults, error)
+	})
+	c.Assert(ok, jc.IsFalse)
+}
@@ -0,0 +1,201 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// WatchContainerMoves returns a StringsWatcher for each given machine,
+// that notifies of changes to the moves of containers to or from it.
+func (p *ProvisionerAPIV7) WatchContainerMoves(args params.Entities) (params.StringsWatchResults, error) {
+	result := params.StringsWatchResults{
+		Results: make([]params.StringsWatchResult, len(args.Entities)),
+	}
+	canAccess, err := p.getAuthFunc()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := p.getMachine(canAccess, tag)
+		if err == nil {
+			result.Results[i], err = p.watchContainerMoves(machine)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (p *ProvisionerAPIV7) watchContainerMoves(m *state.Machine) (params.StringsWatchResult, error) {
+	watch := m.WatchContainerMoves()
+	// Consume the initial event and forward it to the result.
+	if changes, ok := <-watch.Changes(); ok {
+		return params.StringsWatchResult{
+			StringsWatcherId: p.resources.Register(watch),
+			Changes:          changes,
+		}, nil
+	}
+	return params.StringsWatchResult{}, watcher.EnsureErr(watch)
+}
+
+// ContainerMoves returns the move in progress of each given container.
+// A container's move is accessible by its current host, and by the
+// machine it is moving to.
+func (p *ProvisionerAPIV7) ContainerMoves(args params.Entities) (params.ContainerMoveResults, error) {
+	result := params.ContainerMoveResults{
+		Results: make([]params.ContainerMoveResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, _, _, err := p.getMovingContainer(tag)
+		if err == nil {
+			result.Results[i].Result, err = containerMove(machine)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func containerMove(m *state.Machine) (*params.ContainerMove, error) {
+	move, ok := m.ContainerMove()
+	if !ok {
+		return nil, errors.NotFoundf("move of machine %s", m.Id())
+	}
+	instanceId, err := m.InstanceId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	parentId, _ := m.ParentId()
+	result := &params.ContainerMove{
+		InstanceId: string(instanceId),
+		HostTag:    names.NewMachineTag(parentId).String(),
+		TargetTag:  names.NewMachineTag(move.TargetId).String(),
+		Live:       move.Live,
+		Phase:      string(move.Phase),
+		Message:    move.Message,
+	}
+	if source := move.Source; source != nil {
+		secrets, err := m.ContainerMoveSecrets()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result.Source = &params.ContainerMoveSource{
+			Addresses:    source.Addresses,
+			Operation:    source.Operation,
+			Certificate:  source.Certificate,
+			Secrets:      secrets,
+			Architecture: source.Architecture,
+			Config:       source.Config,
+			Devices:      source.Devices,
+			Profiles:     source.Profiles,
+		}
+	}
+	return result, nil
+}
+
+// UpdateContainerMoves records the progress of each given container
+// move. The container's current host reports that it has prepared the
+// container, that it has removed its copy, or that it has restored the
+// container after a failure; the target host reports that it has
+// copied the container, or failed to.
+func (p *ProvisionerAPIV7) UpdateContainerMoves(args params.UpdateContainerMoveArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := p.updateContainerMove(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (p *ProvisionerAPIV7) updateContainerMove(arg params.UpdateContainerMoveArg) error {
+	tag, err := names.ParseMachineTag(arg.Tag)
+	if err != nil {
+		return common.ErrPerm
+	}
+	machine, isHost, isTarget, err := p.getMovingContainer(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch arg.Phase {
+	case params.ContainerMoveSourceReady:
+		if !isHost {
+			return common.ErrPerm
+		}
+		if arg.Source == nil {
+			return errors.NotValidf("move source for machine %s", tag.Id())
+		}
+		return machine.SetMoveSource(state.ContainerMoveSource{
+			Addresses:    arg.Source.Addresses,
+			Operation:    arg.Source.Operation,
+			Certificate:  arg.Source.Certificate,
+			Secrets:      arg.Source.Secrets,
+			Architecture: arg.Source.Architecture,
+			Config:       arg.Source.Config,
+			Devices:      arg.Source.Devices,
+			Profiles:     arg.Source.Profiles,
+		})
+	case params.ContainerMoveCopied:
+		if !isTarget {
+			return common.ErrPerm
+		}
+		return machine.SetMoveCopied()
+	case params.ContainerMoveFailed:
+		if !isTarget {
+			return common.ErrPerm
+		}
+		return machine.SetMoveFailed(arg.Message)
+	case params.ContainerMoveCompleted:
+		if !isHost {
+			return common.ErrPerm
+		}
+		return machine.CompleteMove()
+	case params.ContainerMoveAborted:
+		if !isHost {
+			return common.ErrPerm
+		}
+		return machine.AbortMove()
+	}
+	return errors.NotValidf("container move phase %q", arg.Phase)
+}
+
+// getMovingContainer returns the container identified by tag, and
+// whether the authenticated machine is its current host or the target
+// of its move.
+func (p *ProvisionerAPIV7) getMovingContainer(tag names.MachineTag) (*state.Machine, bool, bool, error) {
+	machine, err := p.st.Machine(tag.Id())
+	if errors.IsNotFound(err) {
+		return nil, false, false, common.ErrPerm
+	} else if err != nil {
+		return nil, false, false, errors.Trace(err)
+	}
+	authTag := p.authorizer.GetAuthTag()
+	parentId, isContainer := machine.ParentId()
+	if !isContainer {
+		return nil, false, false, common.ErrPerm
+	}
+	isHost := names.NewMachineTag(parentId) == authTag
+	move, _ := machine.ContainerMove()
+	isTarget := move.TargetId != "" && names.NewMachineTag(move.TargetId) == authTag
+	if !isHost && !isTarget {
+		return nil, false, false, common.ErrPerm
+	}
+	return machine, isHost, isTarget, nil
+}
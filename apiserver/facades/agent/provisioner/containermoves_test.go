@@ -0,0 +1,200 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/agent/provisioner"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type ContainerMovesSuite struct {
+	provisionerSuite
+
+	container *state.Machine
+	host      *provisioner.ProvisionerAPIV7
+	target    *provisioner.ProvisionerAPIV7
+	other     *provisioner.ProvisionerAPIV7
+}
+
+var _ = gc.Suite(&ContainerMovesSuite{})
+
+func (s *ContainerMovesSuite) SetUpTest(c *gc.C) {
+	s.provisionerSuite.SetUpTest(c)
+
+	var err error
+	s.container, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.machines[0].Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.SetProvisioned("juju-0-lxd-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newAPI := func(m *state.Machine) *provisioner.ProvisionerAPIV7 {
+		authorizer := apiservertesting.FakeAuthorizer{Tag: m.Tag()}
+		api, err := provisioner.NewProvisionerAPIV7(s.State, s.resources, authorizer)
+		c.Assert(err, jc.ErrorIsNil)
+		return api
+	}
+	s.host = newAPI(s.machines[0])
+	s.target = newAPI(s.machines[1])
+	s.other = newAPI(s.machines[2])
+}
+
+func (s *ContainerMovesSuite) startMove(c *gc.C) {
+	err := s.container.StartMove(s.machines[1], false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ContainerMovesSuite) update(c *gc.C, api *provisioner.ProvisionerAPIV7, arg params.UpdateContainerMoveArg) error {
+	arg.Tag = s.container.Tag().String()
+	result, err := api.UpdateContainerMoves(params.UpdateContainerMoveArgs{
+		Args: []params.UpdateContainerMoveArg{arg},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	if result.Results[0].Error != nil {
+		return result.Results[0].Error
+	}
+	return nil
+}
+
+func (s *ContainerMovesSuite) TestContainerMoves(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{{Tag: s.container.Tag().String()}}}
+	result, err := s.host.ContainerMoves(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	s.startMove(c)
+	expected := params.ContainerMoveResults{
+		Results: []params.ContainerMoveResult{{
+			Result: &params.ContainerMove{
+				InstanceId: "juju-0-lxd-0",
+				HostTag:    "machine-0",
+				TargetTag:  "machine-1",
+				Phase:      "requested",
+			},
+		}},
+	}
+	result, err = s.host.ContainerMoves(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expected)
+	result, err = s.target.ContainerMoves(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expected)
+
+	result, err = s.other.ContainerMoves(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+}
+
+func (s *ContainerMovesSuite) TestUpdateContainerMoves(c *gc.C) {
+	s.startMove(c)
+	source := &params.ContainerMoveSource{
+		Addresses:   []string{"10.0.0.1:8443"},
+		Operation:   "/1.0/operations/a-b-c",
+		Certificate: "cert",
+		Secrets:     map[string]string{"fs": "secret"},
+		Config:      map[string]string{"user.user-data": "data"},
+	}
+
+	// Only the current host may prepare the container.
+	err := s.update(c, s.target, params.UpdateContainerMoveArg{Phase: "source-ready", Source: source})
+	c.Assert(err, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	err = s.update(c, s.host, params.UpdateContainerMoveArg{Phase: "source-ready"})
+	c.Assert(err, gc.ErrorMatches, "move source for machine 0/lxd/0 not valid")
+	err = s.update(c, s.host, params.UpdateContainerMoveArg{Phase: "source-ready", Source: source})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.target.ContainerMoves(params.Entities{Entities: []params.Entity{{Tag: s.container.Tag().String()}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Result.Phase, gc.Equals, "source-ready")
+	c.Assert(result.Results[0].Result.Source, jc.DeepEquals, source)
+
+	// Only the target may report the copy.
+	err = s.update(c, s.host, params.UpdateContainerMoveArg{Phase: "copied"})
+	c.Assert(err, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	err = s.update(c, s.target, params.UpdateContainerMoveArg{Phase: "copied"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.update(c, s.host, params.UpdateContainerMoveArg{Phase: "completed"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	parentId, _ := s.container.ParentId()
+	c.Assert(parentId, gc.Equals, s.machines[1].Id())
+
+	err = s.update(c, s.other, params.UpdateContainerMoveArg{Phase: "completed"})
+	c.Assert(err, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+}
+
+func (s *ContainerMovesSuite) TestUpdateContainerMovesFailed(c *gc.C) {
+	s.startMove(c)
+	err := s.update(c, s.host, params.UpdateContainerMoveArg{
+		Phase:  "source-ready",
+		Source: &params.ContainerMoveSource{Operation: "op"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.update(c, s.target, params.UpdateContainerMoveArg{Phase: "failed", Message: "boom"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.update(c, s.host, params.UpdateContainerMoveArg{Phase: "aborted"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.update(c, s.host, params.UpdateContainerMoveArg{Phase: "bogus"})
+	c.Assert(err, gc.ErrorMatches, `container move phase "bogus" not valid`)
+
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, moving := s.container.ContainerMove()
+	c.Assert(moving, jc.IsFalse)
+}
+
+func (s *ContainerMovesSuite) TestMovedContainerAccessibleByNewHost(c *gc.C) {
+	s.startMove(c)
+	c.Assert(s.update(c, s.host, params.UpdateContainerMoveArg{
+		Phase:  "source-ready",
+		Source: &params.ContainerMoveSource{Operation: "op"},
+	}), gc.IsNil)
+	c.Assert(s.update(c, s.target, params.UpdateContainerMoveArg{Phase: "copied"}), gc.IsNil)
+	c.Assert(s.update(c, s.host, params.UpdateContainerMoveArg{Phase: "completed"}), gc.IsNil)
+
+	result, err := s.target.Life(params.Entities{Entities: []params.Entity{{Tag: s.container.Tag().String()}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, jc.DeepEquals, []params.LifeResult{{Life: params.Alive}})
+}
+
+func (s *ContainerMovesSuite) TestWatchContainerMoves(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	result, err := s.target.WatchContainerMoves(params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[1].Tag().String()},
+		{Tag: s.machines[0].Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop it when done.
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event.
+	wc := statetesting.NewStringsWatcherC(c, s.State, resource.(state.StringsWatcher))
+	wc.AssertNoChange()
+
+	s.startMove(c)
+	wc.AssertChange(s.container.Id())
+	wc.AssertNoChange()
+}
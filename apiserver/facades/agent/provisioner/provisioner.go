@@ -84,12 +84,14 @@ func NewProvisionerAPI(st *state.State, resources facade.Resources, authorizer f
 					// environment manager.
 					return isModelManager
 				}
-				// All containers with the authenticated machine as a
-				// parent are accessible by it.
+				// All containers hosted by the authenticated machine,
+				// or moving to it, are accessible by it. A container
+				// that has been moved is no longer hosted by the
+				// parent named by its id.
 				// TODO(dfc) sometimes authEntity tag is nil, which is fine because nil is
 				// only equal to nil, but it suggests someone is passing an authorizer
 				// with a nil tag.
-				return isMachineAgent && names.NewMachineTag(parentId) == authEntityTag
+				return isMachineAgent && isHostedBy(st, tag, authEntityTag)
 			default:
 				return false
 			}
@@ -153,6 +155,59 @@ func NewProvisionerAPIV5(st *state.State, resources facade.Resources, authorizer
 	return &ProvisionerAPIV5{provisionerAPI}, nil
 }
 
+// ProvisionerAPIV6 provides v6 of the Provisioner API facade, which
+// adds the methods used to maintain charm LXD profiles.
+type ProvisionerAPIV6 struct {
+	*ProvisionerAPIV5
+}
+
+// NewProvisionerAPIV6 creates a new server-side Provisioner API facade.
+func NewProvisionerAPIV6(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ProvisionerAPIV6, error) {
+	provisionerAPI, err := NewProvisionerAPIV5(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ProvisionerAPIV6{provisionerAPI}, nil
+}
+
+// ProvisionerAPIV7 provides v7 of the Provisioner API facade, which
+// adds the methods used to move containers between host machines.
+type ProvisionerAPIV7 struct {
+	*ProvisionerAPIV6
+}
+
+// NewProvisionerAPIV7 creates a new server-side Provisioner API facade.
+func NewProvisionerAPIV7(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ProvisionerAPIV7, error) {
+	provisionerAPI, err := NewProvisionerAPIV6(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ProvisionerAPIV7{provisionerAPI}, nil
+}
+
+// isHostedBy reports whether the container identified by tag is
+// currently hosted by the machine identified by hostTag, or is being
+// moved to it.
+func isHostedBy(st *state.State, tag names.MachineTag, hostTag names.Tag) bool {
+	m, err := st.Machine(tag.Id())
+	if errors.IsNotFound(err) {
+		// The container has been removed; it can only be
+		// referred to by its original host.
+		return names.NewMachineTag(state.ParentId(tag.Id())) == hostTag
+	} else if err != nil {
+		return false
+	}
+	parentId, isContainer := m.ParentId()
+	if !isContainer {
+		return false
+	}
+	if names.NewMachineTag(parentId) == hostTag {
+		return true
+	}
+	move, moving := m.ContainerMove()
+	return moving && names.NewMachineTag(move.TargetId) == hostTag
+}
+
 func (p *ProvisionerAPI) getMachine(canAccess common.AuthFunc, tag names.MachineTag) (*state.Machine, error) {
 	if !canAccess(tag) {
 		return nil, common.ErrPerm
@@ -266,6 +321,14 @@ func (p *ProvisionerAPI) ContainerManagerConfig(args params.ContainerManagerConf
 		// TODO(jam): Do we want to handle ImageStream here, or do we
 		// hide it from them? (all cached images must come from the
 		// same image stream?)
+	case instance.KVM:
+		config, err := p.m.ModelConfig()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		if nicModel := config.KVMNICModel(); nicModel != "" {
+			cfg[container.ConfigNICModel] = nicModel
+		}
 	}
 
 	result.ManagerConfig = cfg
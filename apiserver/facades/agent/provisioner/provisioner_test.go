@@ -1349,6 +1349,23 @@ func (s *withoutControllerSuite) TestContainerManagerConfig(c *gc.C) {
 	})
 }
 
+func (s *withoutControllerSuite) TestContainerManagerConfigKVMNICModel(c *gc.C) {
+	err := s.Model.UpdateModelConfig(map[string]interface{}{
+		"kvm-nic-model": "e1000",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg := s.getManagerConfig(c, instance.KVM)
+	c.Assert(cfg, jc.DeepEquals, map[string]string{
+		container.ConfigModelUUID: coretesting.ModelTag.Id(),
+		container.ConfigNICModel:  "e1000",
+	})
+	cfg = s.getManagerConfig(c, instance.LXD)
+	c.Assert(cfg, jc.DeepEquals, map[string]string{
+		container.ConfigModelUUID: coretesting.ModelTag.Id(),
+	})
+}
+
 func (s *withoutControllerSuite) TestContainerConfig(c *gc.C) {
 	attrs := map[string]interface{}{
 		"http-proxy":            "http://proxy.example.com:9000",
@@ -89,25 +89,56 @@ func (h *RetryStrategyAPI) RetryStrategy(args params.Entities) (params.RetryStra
 		}
 		err = common.ErrPerm
 		if canAccess(tag) {
-			// Right now the only real configurable value is ShouldRetry,
-			// which is taken from the environment
+			// Right now the only real configurable values are ShouldRetry,
+			// which is taken from the environment, and the hook timeout
+			// policy, which is taken from the unit's application.
 			// The rest are hardcoded
-			results.Results[i].Result = &params.RetryStrategy{
-				ShouldRetry:     config.AutomaticallyRetryHooks(),
-				MinRetryTime:    MinRetryTime,
-				MaxRetryTime:    MaxRetryTime,
-				JitterRetryTime: JitterRetryTime,
-				RetryTimeFactor: RetryTimeFactor,
+			strategy := &params.RetryStrategy{
+				ShouldRetry:        config.AutomaticallyRetryHooks(),
+				MinRetryTime:       MinRetryTime,
+				MaxRetryTime:       MaxRetryTime,
+				JitterRetryTime:    JitterRetryTime,
+				RetryTimeFactor:    RetryTimeFactor,
+				RetryTimedOutHooks: true,
+			}
+			err = h.setHookTimeoutPolicy(tag, strategy)
+			if err == nil {
+				results.Results[i].Result = strategy
 			}
-			err = nil
 		}
 		results.Results[i].Error = common.ServerError(err)
 	}
 	return results, nil
 }
 
-// WatchRetryStrategy watches for changes to the environment. Currently we only allow
-// changes to the boolean that determines whether retries should be attempted or not.
+// setHookTimeoutPolicy fills in the hook timeout policy of the
+// application of the unit with the given tag.
+func (h *RetryStrategyAPI) setHookTimeoutPolicy(tag names.Tag, strategy *params.RetryStrategy) error {
+	if tag.Kind() != names.UnitTagKind {
+		return nil
+	}
+	app, err := h.unitApplication(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	policy := app.HookTimeoutPolicy()
+	strategy.HookTimeout = policy.Timeout
+	strategy.RetryTimedOutHooks = policy.Retry
+	return nil
+}
+
+func (h *RetryStrategyAPI) unitApplication(tag names.Tag) (*state.Application, error) {
+	unit, err := h.st.Unit(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return unit.Application()
+}
+
+// WatchRetryStrategy watches for changes to the environment and to the
+// application of each unit. Currently we only allow changes to the boolean
+// that determines whether retries should be attempted or not, and to the
+// hook timeout policy of the application.
 func (h *RetryStrategyAPI) WatchRetryStrategy(args params.Entities) (params.NotifyWatchResults, error) {
 	results := params.NotifyWatchResults{
 		Results: make([]params.NotifyWatchResult, len(args.Entities)),
@@ -124,18 +155,32 @@ func (h *RetryStrategyAPI) WatchRetryStrategy(args params.Entities) (params.Noti
 		}
 		err = common.ErrPerm
 		if canAccess(tag) {
-			watch := h.model.WatchForModelConfigChanges()
-			// Consume the initial event. Technically, API calls to Watch
-			// 'transmit' the initial event in the Watch response. But
-			// NotifyWatchers have no state to transmit.
-			if _, ok := <-watch.Changes(); ok {
-				results.Results[i].NotifyWatcherId = h.resources.Register(watch)
-				err = nil
-			} else {
-				err = watcher.EnsureErr(watch)
-			}
+			results.Results[i].NotifyWatcherId, err = h.watchRetryStrategy(tag)
 		}
 		results.Results[i].Error = common.ServerError(err)
 	}
 	return results, nil
 }
+
+func (h *RetryStrategyAPI) watchRetryStrategy(tag names.Tag) (string, error) {
+	var app *state.Application
+	if tag.Kind() == names.UnitTagKind {
+		var err error
+		app, err = h.unitApplication(tag)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	watchers := []state.NotifyWatcher{h.model.WatchForModelConfigChanges()}
+	if app != nil {
+		watchers = append(watchers, app.Watch())
+	}
+	watch := common.NewMultiNotifyWatcher(watchers...)
+	// Consume the initial event. Technically, API calls to Watch
+	// 'transmit' the initial event in the Watch response. But
+	// NotifyWatchers have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		return h.resources.Register(watch), nil
+	}
+	return "", watcher.EnsureErr(watch)
+}
@@ -5,6 +5,8 @@
 package retrystrategy_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -95,11 +97,12 @@ func (s *retryStrategySuite) TestRetryStrategyBadTag(c *gc.C) {
 
 func (s *retryStrategySuite) TestRetryStrategy(c *gc.C) {
 	expected := &params.RetryStrategy{
-		ShouldRetry:     true,
-		MinRetryTime:    retrystrategy.MinRetryTime,
-		MaxRetryTime:    retrystrategy.MaxRetryTime,
-		JitterRetryTime: retrystrategy.JitterRetryTime,
-		RetryTimeFactor: retrystrategy.RetryTimeFactor,
+		ShouldRetry:        true,
+		MinRetryTime:       retrystrategy.MinRetryTime,
+		MaxRetryTime:       retrystrategy.MaxRetryTime,
+		JitterRetryTime:    retrystrategy.JitterRetryTime,
+		RetryTimeFactor:    retrystrategy.RetryTimeFactor,
+		RetryTimedOutHooks: true,
 	}
 	args := params.Entities{Entities: []params.Entity{{Tag: s.unit.Tag().String()}}}
 	r, err := s.strategy.RetryStrategy(args)
@@ -118,6 +121,25 @@ func (s *retryStrategySuite) TestRetryStrategy(c *gc.C) {
 	c.Assert(r.Results[0].Result, jc.DeepEquals, expected)
 }
 
+func (s *retryStrategySuite) TestRetryStrategyHookTimeout(c *gc.C) {
+	s.setHookTimeoutPolicy(c, state.HookTimeoutPolicy{Timeout: 10 * time.Minute})
+
+	args := params.Entities{Entities: []params.Entity{{Tag: s.unit.Tag().String()}}}
+	r, err := s.strategy.RetryStrategy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Results, gc.HasLen, 1)
+	c.Assert(r.Results[0].Error, gc.IsNil)
+	c.Assert(r.Results[0].Result.HookTimeout, gc.Equals, 10*time.Minute)
+	c.Assert(r.Results[0].Result.RetryTimedOutHooks, jc.IsFalse)
+}
+
+func (s *retryStrategySuite) setHookTimeoutPolicy(c *gc.C, policy state.HookTimeoutPolicy) {
+	app, err := s.unit.Application()
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.SetHookTimeoutPolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *retryStrategySuite) setRetryStrategy(c *gc.C, automaticallyRetryHooks bool) {
 	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{"automatically-retry-hooks": automaticallyRetryHooks}, nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -180,4 +202,7 @@ func (s *retryStrategySuite) TestWatchRetryStrategy(c *gc.C) {
 	s.setRetryStrategy(c, false)
 	c.Assert(err, jc.ErrorIsNil)
 	wc.AssertOneChange()
+
+	s.setHookTimeoutPolicy(c, state.HookTimeoutPolicy{Timeout: time.Minute, Retry: true})
+	wc.AssertOneChange()
 }
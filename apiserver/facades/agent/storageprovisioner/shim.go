@@ -52,6 +52,7 @@ type Backend interface {
 
 	ControllerConfig() (controller.Config, error)
 	MachineInstanceId(names.MachineTag) (instance.Id, error)
+	MachineParentId(names.MachineTag) (string, error)
 	ModelTag() names.ModelTag
 	BlockDevices(names.MachineTag) ([]state.BlockDeviceInfo, error)
 
@@ -111,6 +112,17 @@ func (s stateShim) MachineInstanceId(tag names.MachineTag) (instance.Id, error)
 	return m.InstanceId()
 }
 
+// MachineParentId returns the id of the machine hosting the given
+// machine, or the empty string if the machine is not a container.
+func (s stateShim) MachineParentId(tag names.MachineTag) (string, error) {
+	m, err := s.Machine(tag.Id())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	parentId, _ := m.ParentId()
+	return parentId, nil
+}
+
 func (s stateShim) WatchMachine(tag names.MachineTag) (state.NotifyWatcher, error) {
 	m, err := s.Machine(tag.Id())
 	if err != nil {
@@ -68,12 +68,18 @@ func NewStorageProvisionerAPIv3(
 			// scoped to their own machine.
 			return true
 		}
-		parentId := state.ParentId(tag.Id())
+		parentId, err := st.MachineParentId(tag)
+		if errors.IsNotFound(err) {
+			parentId = state.ParentId(tag.Id())
+		} else if err != nil {
+			return false
+		}
 		if parentId == "" {
 			return allowEnvironManager && authorizer.AuthController()
 		}
-		// All containers with the authenticated
-		// machine as a parent are accessible by it.
+		// All containers hosted by the authenticated machine are
+		// accessible by it. A container that has been moved is no
+		// longer hosted by the parent named by its id.
 		return names.NewMachineTag(parentId) == authEntityTag
 	}
 	getScopeAuthFunc := func() (common.AuthFunc, error) {
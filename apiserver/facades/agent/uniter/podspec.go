@@ -0,0 +1,52 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+)
+
+// SetPodSpec sets the pod specs of the given applications, which must
+// be the application of the calling unit. Only the leader unit of an
+// application may set its pod spec.
+func (u *UniterAPI) SetPodSpec(args params.SetPodSpecParams) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Specs)),
+	}
+	canAccess, err := u.accessApplication()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Specs {
+		results.Results[i].Error = common.ServerError(u.setPodSpec(arg, canAccess))
+	}
+	return results, nil
+}
+
+func (u *UniterAPI) setPodSpec(arg params.EntityString, canAccess common.AuthFunc) error {
+	tag, err := names.ParseApplicationTag(arg.Tag)
+	if err != nil {
+		return common.ErrPerm
+	}
+	if !canAccess(tag) {
+		return common.ErrPerm
+	}
+	if _, err := caas.ParsePodSpec(arg.Value); err != nil {
+		return errors.Annotate(err, "invalid pod spec")
+	}
+	token := u.st.LeadershipChecker().LeadershipCheck(tag.Id(), u.unit.Name())
+	if err := token.Check(nil); err != nil {
+		return errors.Trace(err)
+	}
+	app, err := u.st.Application(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return app.SetPodSpec(arg.Value)
+}
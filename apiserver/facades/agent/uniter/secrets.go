@@ -0,0 +1,231 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// CreateSecrets creates secrets owned by the applications of the
+// given units, returning the ids of the new secrets.
+func (u *UniterAPI) CreateSecrets(args params.CreateSecretArgs) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringResults{}, err
+	}
+	for i, arg := range args.Args {
+		unit, err := u.authSecretUnit(arg.UnitTag, canAccess)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		secret, err := u.st.AddSecret(state.AddSecretArgs{
+			Owner: unit.ApplicationName(),
+			Label: arg.Label,
+			Value: arg.Value,
+		})
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = secret.Id()
+	}
+	return result, nil
+}
+
+// RotateSecrets replaces the values of secrets owned by the
+// applications of the given units.
+func (u *UniterAPI) RotateSecrets(args params.RotateSecretArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		secret, err := u.ownedSecret(arg.UnitTag, arg.SecretId, canAccess)
+		if err == nil {
+			err = secret.Rotate(arg.Value)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// GrantSecrets grants access to secrets owned by the applications of
+// the given units, over the given relations.
+func (u *UniterAPI) GrantSecrets(args params.GrantSecretArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		result.Results[i].Error = common.ServerError(u.grantSecret(arg, canAccess))
+	}
+	return result, nil
+}
+
+func (u *UniterAPI) grantSecret(arg params.GrantSecretArg, canAccess common.AuthFunc) error {
+	secret, err := u.ownedSecret(arg.UnitTag, arg.SecretId, canAccess)
+	if err != nil {
+		return err
+	}
+	relTag, err := names.ParseRelationTag(arg.RelationTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	grant := state.SecretGrant{Relation: relTag.Id()}
+	if arg.GranteeTag != "" {
+		granteeTag, err := names.ParseUnitTag(arg.GranteeTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		grant.Unit = granteeTag.Id()
+	}
+	return secret.Grant(grant)
+}
+
+// GetSecretValues returns the values of the given secrets, which must
+// be readable by the units asking for them.
+func (u *UniterAPI) GetSecretValues(args params.GetSecretValueArgs) (params.SecretValueResults, error) {
+	result := params.SecretValueResults{
+		Results: make([]params.SecretValueResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.SecretValueResults{}, err
+	}
+	for i, arg := range args.Args {
+		value, revision, err := u.secretValue(arg, canAccess)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Value = value
+		result.Results[i].Revision = revision
+	}
+	return result, nil
+}
+
+func (u *UniterAPI) secretValue(arg params.GetSecretValueArg, canAccess common.AuthFunc) (map[string]string, int, error) {
+	unit, err := u.authSecretUnit(arg.UnitTag, canAccess)
+	if err != nil {
+		return nil, 0, err
+	}
+	secret, err := u.st.Secret(arg.SecretId)
+	if err != nil {
+		return nil, 0, err
+	}
+	canRead, err := secret.CanRead(unit.Name())
+	if err != nil {
+		return nil, 0, err
+	}
+	if !canRead {
+		return nil, 0, common.ErrPerm
+	}
+	value, err := secret.Value()
+	if err != nil {
+		return nil, 0, err
+	}
+	return value, secret.Revision(), nil
+}
+
+// SecretRevisions returns the current revisions of the secrets granted
+// to each given unit by other applications.
+func (u *UniterAPI) SecretRevisions(args params.Entities) (params.SecretRevisionsResults, error) {
+	result := params.SecretRevisionsResults{
+		Results: make([]params.SecretRevisionsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.SecretRevisionsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		unit, err := u.authSecretUnit(entity.Tag, canAccess)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		secrets, err := u.st.SecretsGrantedTo(unit.Name())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		revisions := make(map[string]int)
+		for _, secret := range secrets {
+			revisions[secret.Id()] = secret.Revision()
+		}
+		result.Results[i].Revisions = revisions
+	}
+	return result, nil
+}
+
+// WatchSecrets returns a NotifyWatcher for observing changes to the
+// secrets that may be granted to each given unit.
+func (u *UniterAPI) WatchSecrets(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		if _, err := u.authSecretUnit(entity.Tag, canAccess); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		watch := u.st.WatchSecrets()
+		// Consume the initial event.
+		if _, ok := <-watch.Changes(); ok {
+			result.Results[i].NotifyWatcherId = u.resources.Register(watch)
+		} else {
+			result.Results[i].Error = common.ServerError(watcher.EnsureErr(watch))
+		}
+	}
+	return result, nil
+}
+
+// authSecretUnit returns the unit with the given tag, if the caller
+// may act on its behalf.
+func (u *UniterAPI) authSecretUnit(unitTag string, canAccess common.AuthFunc) (*state.Unit, error) {
+	tag, err := names.ParseUnitTag(unitTag)
+	if err != nil {
+		return nil, common.ErrPerm
+	}
+	if !canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	return u.getUnit(tag)
+}
+
+// ownedSecret returns the secret with the given id, if it is owned
+// by the application of the unit with the given tag.
+func (u *UniterAPI) ownedSecret(unitTag, secretId string, canAccess common.AuthFunc) (*state.Secret, error) {
+	unit, err := u.authSecretUnit(unitTag, canAccess)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := u.st.Secret(secretId)
+	if err != nil {
+		return nil, err
+	}
+	if secret.Owner() != unit.ApplicationName() {
+		return nil, common.ErrPerm
+	}
+	return secret, nil
+}
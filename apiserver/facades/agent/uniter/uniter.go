@@ -8,6 +8,7 @@ package uniter
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -33,7 +34,7 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.uniter")
 
-// UniterAPI implements the latest version (v7) of the Uniter API.
+// UniterAPI implements the latest version (v12) of the Uniter API.
 type UniterAPI struct {
 	*common.LifeGetter
 	*StatusAPI
@@ -55,9 +56,34 @@ type UniterAPI struct {
 	StorageAPI
 }
 
+// UniterAPIV11 doesn't have the SetPodSpec method.
+type UniterAPIV11 struct {
+	UniterAPI
+}
+
+// UniterAPIV10 doesn't have the secrets methods.
+type UniterAPIV10 struct {
+	UniterAPIV11
+}
+
+// UniterAPIV9 doesn't have the RecordHookExecutions method.
+type UniterAPIV9 struct {
+	UniterAPIV10
+}
+
+// UniterAPIV8 doesn't have the UpdateStatusHookIntervals method.
+type UniterAPIV8 struct {
+	UniterAPIV9
+}
+
+// UniterAPIV7 doesn't have the SetHealthStatus method.
+type UniterAPIV7 struct {
+	UniterAPIV8
+}
+
 // UniterAPIV6 adds NetworkInfo as a preferred method to calling NetworkConfig.
 type UniterAPIV6 struct {
-	UniterAPI
+	UniterAPIV7
 }
 
 // UniterAPIV5 returns a RelationResultsV5 instead of RelationResults
@@ -172,14 +198,69 @@ func NewUniterAPI(st *state.State, resources facade.Resources, authorizer facade
 	}, nil
 }
 
+// NewUniterAPIV11 creates an instance of the V11 uniter API.
+func NewUniterAPIV11(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV11, error) {
+	uniterAPI, err := NewUniterAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV11{
+		UniterAPI: *uniterAPI,
+	}, nil
+}
+
+// NewUniterAPIV10 creates an instance of the V10 uniter API.
+func NewUniterAPIV10(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV10, error) {
+	uniterAPI, err := NewUniterAPIV11(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV10{
+		UniterAPIV11: *uniterAPI,
+	}, nil
+}
+
+// NewUniterAPIV9 creates an instance of the V9 uniter API.
+func NewUniterAPIV9(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV9, error) {
+	uniterAPI, err := NewUniterAPIV10(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV9{
+		UniterAPIV10: *uniterAPI,
+	}, nil
+}
+
+// NewUniterAPIV8 creates an instance of the V8 uniter API.
+func NewUniterAPIV8(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV8, error) {
+	uniterAPI, err := NewUniterAPIV9(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV8{
+		UniterAPIV9: *uniterAPI,
+	}, nil
+}
+
+// NewUniterAPIV7 creates an instance of the V7 uniter API.
+func NewUniterAPIV7(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV7, error) {
+	uniterAPI, err := NewUniterAPIV8(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV7{
+		UniterAPIV8: *uniterAPI,
+	}, nil
+}
+
 // NewUniterAPIV6 creates an instance of the V6 uniter API.
 func NewUniterAPIV6(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV6, error) {
-	uniterAPI, err := NewUniterAPI(st, resources, authorizer)
+	uniterAPI, err := NewUniterAPIV7(st, resources, authorizer)
 	if err != nil {
 		return nil, err
 	}
 	return &UniterAPIV6{
-		UniterAPI: *uniterAPI,
+		UniterAPIV7: *uniterAPI,
 	}, nil
 }
 
@@ -779,6 +860,90 @@ func (u *UniterAPI) SetWorkloadVersion(args params.EntityWorkloadVersions) (para
 	return result, nil
 }
 
+// SetHealthStatus sets the health status of each given unit, as found
+// by running the health checks declared by its charm.
+func (u *UniterAPI) SetHealthStatus(args params.SetStatus) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		resultItem := &result.Results[i]
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		if !canAccess(tag) {
+			resultItem.Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		err = unit.SetHealthStatus(status.StatusInfo{
+			Status:  status.Status(entity.Status),
+			Message: entity.Info,
+			Data:    entity.Data,
+		})
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// RecordHookExecutions records the given executions of hooks and
+// actions by each unit.
+func (u *UniterAPI) RecordHookExecutions(args params.UnitHookExecutions) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		resultItem := &result.Results[i]
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		if !canAccess(tag) {
+			resultItem.Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		execution := arg.Execution
+		_, err = unit.RecordHookExecution(state.HookExecution{
+			Kind:       execution.Kind,
+			Name:       execution.Name,
+			Relation:   execution.Relation,
+			RemoteUnit: execution.RemoteUnit,
+			Started:    execution.Started,
+			Finished:   execution.Finished,
+			ExitCode:   execution.ExitCode,
+			Error:      execution.Error,
+			Stdout:     execution.Stdout,
+			Stderr:     execution.Stderr,
+		})
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
 // OpenPorts sets the policy of the port range with protocol to be
 // opened, for all given units.
 func (u *UniterAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorResults, error) {
@@ -1814,6 +1979,63 @@ func (u *UniterAPI) AddMetricBatches(args params.MetricBatchParams) (params.Erro
 //  specific methods - the new SLALevel, NetworkInfo and
 // WatchUnitRelations methods.
 
+// UpdateStatusHookIntervals returns how often the update-status hook
+// runs for each given unit or application: the application's interval
+// if it has one, and the model's update-status-hook-interval otherwise.
+func (u *UniterAPI) UpdateStatusHookIntervals(args params.Entities) (params.UpdateStatusHookIntervalResults, error) {
+	result := params.UpdateStatusHookIntervalResults{
+		Results: make([]params.UpdateStatusHookIntervalResult, len(args.Entities)),
+	}
+	accessUnitOrApplication := common.AuthAny(u.accessUnit, u.accessApplication)
+	canAccess, err := accessUnitOrApplication()
+	if err != nil {
+		return params.UpdateStatusHookIntervalResults{}, err
+	}
+	cfg, err := u.m.ModelConfig()
+	if err != nil {
+		return params.UpdateStatusHookIntervalResults{}, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		interval, err := u.updateStatusHookInterval(entity.Tag, canAccess)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if interval == 0 {
+			interval = cfg.UpdateStatusHookInterval()
+		}
+		result.Results[i].Interval = interval
+	}
+	return result, nil
+}
+
+func (u *UniterAPI) updateStatusHookInterval(tagStr string, canAccess common.AuthFunc) (time.Duration, error) {
+	tag, err := names.ParseTag(tagStr)
+	if err != nil {
+		return 0, common.ErrPerm
+	}
+	if !canAccess(tag) {
+		return 0, common.ErrPerm
+	}
+	entity, err := u.st.FindEntity(tag)
+	if err != nil {
+		return 0, err
+	}
+	var application *state.Application
+	switch entity := entity.(type) {
+	case *state.Application:
+		application = entity
+	case *state.Unit:
+		application, err = entity.Application()
+		if err != nil {
+			return 0, err
+		}
+	default:
+		return 0, errors.BadRequestf("type %T does not have an update status hook interval", entity)
+	}
+	return application.UpdateStatusInterval(), nil
+}
+
 // SLALevel returns the model's SLA level.
 func (u *UniterAPI) SLALevel() (params.StringResult, error) {
 	result := params.StringResult{}
@@ -1846,7 +2068,11 @@ func (u *UniterAPI) NetworkInfo(args params.NetworkInfoParams) (params.NetworkIn
 	}
 
 	machineID, err := unit.AssignedMachineId()
-	if err != nil {
+	if errors.IsNotAssigned(err) {
+		// Units deployed to a CAAS model have no machine;
+		// they are addressed by the containers they run in.
+		return u.containerNetworkInfo(unit, args.Bindings)
+	} else if err != nil {
 		return params.NetworkInfoResults{}, err
 	}
 
@@ -1951,6 +2177,40 @@ func (u *UniterAPI) NetworkInfo(args params.NetworkInfoParams) (params.NetworkIn
 	return result, nil
 }
 
+// containerNetworkInfo returns the network info for each binding of a
+// unit that is not assigned to a machine. Every binding resolves to
+// the address of the container running the unit.
+func (u *UniterAPI) containerNetworkInfo(unit *state.Unit, bindings []string) (params.NetworkInfoResults, error) {
+	model, err := u.st.Model()
+	if err != nil {
+		return params.NetworkInfoResults{}, err
+	}
+	modelCfg, err := model.ModelConfig()
+	if err != nil {
+		return params.NetworkInfoResults{}, err
+	}
+	result := params.NetworkInfoResults{
+		Results: make(map[string]params.NetworkInfoResult),
+	}
+	address, err := unit.ContainerAddress()
+	if err != nil {
+		for _, binding := range bindings {
+			result.Results[binding] = params.NetworkInfoResult{Error: common.ServerError(err)}
+		}
+		return result, nil
+	}
+	for _, binding := range bindings {
+		result.Results[binding] = params.NetworkInfoResult{
+			Info: []params.NetworkInfo{{
+				Addresses: []params.InterfaceAddress{{Address: address.Value}},
+			}},
+			EgressSubnets:    modelCfg.EgressSubnets(),
+			IngressAddresses: []string{address.Value},
+		}
+	}
+	return result, nil
+}
+
 // WatchUnitRelations returns a StringsWatcher, for each given
 // unit, that notifies of changes to the lifecycles of relations
 // relevant to that unit. For principal units, this will be all of the
@@ -2233,3 +2493,33 @@ func (u *UniterAPIV4) NetworkInfo(_, _ struct{}) {}
 
 // WatchUnitRelations isn't on the V4 API.
 func (u *UniterAPIV4) WatchUnitRelations(_, _ struct{}) {}
+
+// SetHealthStatus isn't on the V7 API.
+func (u *UniterAPIV7) SetHealthStatus(_, _ struct{}) {}
+
+// UpdateStatusHookIntervals isn't on the V8 API.
+func (u *UniterAPIV8) UpdateStatusHookIntervals(_, _ struct{}) {}
+
+// RecordHookExecutions isn't on the V9 API.
+func (u *UniterAPIV9) RecordHookExecutions(_, _ struct{}) {}
+
+// CreateSecrets isn't on the V10 API.
+func (u *UniterAPIV10) CreateSecrets(_, _ struct{}) {}
+
+// RotateSecrets isn't on the V10 API.
+func (u *UniterAPIV10) RotateSecrets(_, _ struct{}) {}
+
+// GrantSecrets isn't on the V10 API.
+func (u *UniterAPIV10) GrantSecrets(_, _ struct{}) {}
+
+// GetSecretValues isn't on the V10 API.
+func (u *UniterAPIV10) GetSecretValues(_, _ struct{}) {}
+
+// SecretRevisions isn't on the V10 API.
+func (u *UniterAPIV10) SecretRevisions(_, _ struct{}) {}
+
+// WatchSecrets isn't on the V10 API.
+func (u *UniterAPIV10) WatchSecrets(_, _ struct{}) {}
+
+// SetPodSpec isn't on the V11 API.
+func (u *UniterAPIV11) SetPodSpec(_, _ struct{}) {}
@@ -547,6 +547,49 @@ func (s *uniterSuite) TestNetworkInfoSpaceless(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestNetworkInfoContainerAddress(c *gc.C) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{config.EgressSubnets: "10.0.0.0/8"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	// The unit is not assigned to a machine.
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	authorizer := s.authorizer
+	authorizer.Tag = unit.Tag()
+	uniterAPI, err := uniter.NewUniterAPI(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.NetworkInfoParams{
+		Unit:     unit.Tag().String(),
+		Bindings: []string{"db"},
+	}
+	result, err := uniterAPI.NetworkInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, params.NetworkInfoResults{
+		Results: map[string]params.NetworkInfoResult{
+			"db": {Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `container address for unit "wordpress/1" not found`,
+			}},
+		},
+	})
+
+	err = unit.SetContainerAddress("10.0.0.1")
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = uniterAPI.NetworkInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, params.NetworkInfoResults{
+		Results: map[string]params.NetworkInfoResult{
+			"db": {
+				Info: []params.NetworkInfo{{
+					Addresses: []params.InterfaceAddress{{Address: "10.0.0.1"}},
+				}},
+				EgressSubnets:    []string{"10.0.0.0/8"},
+				IngressAddresses: []string{"10.0.0.1"},
+			},
+		},
+	})
+}
+
 func (s *uniterSuite) TestAvailabilityZone(c *gc.C) {
 	s.PatchValue(uniter.GetZone, func(st *state.State, tag names.Tag) (string, error) {
 		return "a_zone", nil
@@ -879,6 +922,287 @@ func (s *uniterSuite) TestSetWorkloadVersion(c *gc.C) {
 	c.Assert(newVersion, gc.Equals, "shiro")
 }
 
+func (s *uniterSuite) TestSetHealthStatus(c *gc.C) {
+	args := params.SetStatus{Entities: []params.EntityStatusArgs{
+		{Tag: "unit-mysql-0", Status: "healthy"},
+		{Tag: "unit-wordpress-0", Status: "unhealthy", Info: `check "http" failed`},
+		{Tag: "unit-foo-42", Status: "healthy"},
+	}}
+	result, err := s.uniter.SetHealthStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	health, err := s.wordpressUnit.HealthStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Status, gc.Equals, status.Unhealthy)
+	c.Assert(health.Message, gc.Equals, `check "http" failed`)
+}
+
+func (s *uniterSuite) TestRecordHookExecutions(c *gc.C) {
+	started := time.Date(2017, 10, 1, 12, 0, 0, 0, time.UTC)
+	execution := params.HookExecution{
+		Kind:     "hook",
+		Name:     "config-changed",
+		Started:  started,
+		Finished: started.Add(time.Second),
+		ExitCode: 1,
+		Error:    "exit status 1",
+		Stderr:   "oops",
+	}
+	args := params.UnitHookExecutions{Args: []params.UnitHookExecution{
+		{Tag: "unit-mysql-0", Execution: execution},
+		{Tag: "unit-wordpress-0", Execution: execution},
+		{Tag: "unit-foo-42", Execution: execution},
+	}}
+	result, err := s.uniter.RecordHookExecutions(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	recorded, err := s.wordpressUnit.HookExecution(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded.Name, gc.Equals, "config-changed")
+	c.Assert(recorded.ExitCode, gc.Equals, 1)
+	c.Assert(recorded.Stderr, gc.Equals, "oops")
+}
+
+func (s *uniterSuite) TestCreateSecrets(c *gc.C) {
+	value := map[string]string{"password": "sekrit"}
+	args := params.CreateSecretArgs{Args: []params.CreateSecretArg{
+		{UnitTag: "unit-mysql-0", Value: value},
+		{UnitTag: "unit-wordpress-0", Label: "admin", Value: value},
+		{UnitTag: "unit-wordpress-0"},
+	}}
+	result, err := s.uniter.CreateSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: "secret-0"},
+			{Error: &params.Error{
+				Message: `cannot add secret for application "wordpress": empty secret value not valid`,
+				Code:    params.CodeNotValid,
+			}},
+		},
+	})
+
+	secret, err := s.State.Secret("secret-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Owner(), gc.Equals, "wordpress")
+	c.Assert(secret.Label(), gc.Equals, "admin")
+}
+
+func (s *uniterSuite) TestSetPodSpec(c *gc.C) {
+	err := s.State.LeadershipClaimer().ClaimLeadership("wordpress", "wordpress/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	spec := "containers:\n  - name: wordpress\n    image: wordpress/latest\n"
+	args := params.SetPodSpecParams{Specs: []params.EntityString{
+		{Tag: "application-wordpress", Value: spec},
+		{Tag: "application-wordpress", Value: "containers: []"},
+		{Tag: "application-mysql", Value: spec},
+		{Tag: "unit-wordpress-0", Value: spec},
+	}}
+	result, err := s.uniter.SetPodSpec(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{&params.Error{Message: "invalid pod spec: require at least one container spec"}},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	stored, err := s.wordpress.PodSpec()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stored, gc.Equals, spec)
+}
+
+func (s *uniterSuite) TestSetPodSpecNotLeader(c *gc.C) {
+	spec := "containers:\n  - name: wordpress\n    image: wordpress/latest\n"
+	args := params.SetPodSpecParams{Specs: []params.EntityString{
+		{Tag: "application-wordpress", Value: spec},
+	}}
+	result, err := s.uniter.SetPodSpec(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{&params.Error{Message: `"wordpress/0" is not leader of "wordpress"`}},
+		},
+	})
+	_, err = s.wordpress.PodSpec()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *uniterSuite) TestRotateSecrets(c *gc.C) {
+	owned, err := s.State.AddSecret(state.AddSecretArgs{
+		Owner: "wordpress",
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.State.AddSecret(state.AddSecretArgs{
+		Owner: "mysql",
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	value := map[string]string{"password": "new"}
+	args := params.RotateSecretArgs{Args: []params.RotateSecretArg{
+		{UnitTag: "unit-wordpress-0", SecretId: owned.Id(), Value: value},
+		{UnitTag: "unit-wordpress-0", SecretId: other.Id(), Value: value},
+		{UnitTag: "unit-mysql-0", SecretId: other.Id(), Value: value},
+	}}
+	result, err := s.uniter.RotateSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = owned.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(owned.Revision(), gc.Equals, 2)
+	err = other.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(other.Revision(), gc.Equals, 1)
+}
+
+func (s *uniterSuite) TestGetSecretValues(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	secret, err := s.State.AddSecret(state.AddSecretArgs{
+		Owner: "mysql",
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.GetSecretValueArgs{Args: []params.GetSecretValueArg{
+		{UnitTag: "unit-wordpress-0", SecretId: secret.Id()},
+		{UnitTag: "unit-mysql-0", SecretId: secret.Id()},
+	}}
+	result, err := s.uniter.GetSecretValues(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.SecretValueResults{
+		Results: []params.SecretValueResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = secret.Grant(state.SecretGrant{Relation: rel.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.GetSecretValues(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.SecretValueResults{
+		Results: []params.SecretValueResult{
+			{Value: map[string]string{"password": "sekrit"}, Revision: 1},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterSuite) TestGrantSecrets(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	secret, err := s.State.AddSecret(state.AddSecretArgs{
+		Owner: "wordpress",
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.GrantSecretArgs{Args: []params.GrantSecretArg{
+		{UnitTag: "unit-mysql-0", SecretId: secret.Id(), RelationTag: rel.Tag().String()},
+		{UnitTag: "unit-wordpress-0", SecretId: secret.Id(), RelationTag: rel.Tag().String(), GranteeTag: "unit-mysql-0"},
+	}}
+	result, err := s.uniter.GrantSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+		},
+	})
+
+	err = secret.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Grants(), jc.DeepEquals, []state.SecretGrant{
+		{Relation: rel.String(), Unit: "mysql/0"},
+	})
+}
+
+func (s *uniterSuite) TestSecretRevisions(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	secret, err := s.State.AddSecret(state.AddSecretArgs{
+		Owner: "mysql",
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Grant(state.SecretGrant{Relation: rel.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Rotate(map[string]string{"password": "new"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+	}}
+	result, err := s.uniter.SecretRevisions(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.SecretRevisionsResults{
+		Results: []params.SecretRevisionsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Revisions: map[string]int{secret.Id(): 2}},
+		},
+	})
+}
+
+func (s *uniterSuite) TestWatchSecrets(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+	}}
+	result, err := s.uniter.WatchSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{NotifyWatcherId: "1"},
+		},
+	})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event ("returned" in
+	// the Watch call)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	_, err = s.State.AddSecret(state.AddSecretArgs{
+		Owner: "mysql",
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
 func (s *uniterSuite) TestCharmModifiedVersion(c *gc.C) {
 	args := params.Entities{Entities: []params.Entity{
 		{Tag: "application-mysql"},
@@ -898,6 +1222,35 @@ func (s *uniterSuite) TestCharmModifiedVersion(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestUpdateStatusHookIntervals(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "application-mysql"},
+		{Tag: "application-wordpress"},
+		{Tag: "unit-wordpress-0"},
+	}}
+	result, err := s.uniter.UpdateStatusHookIntervals(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.UpdateStatusHookIntervalResults{
+		Results: []params.UpdateStatusHookIntervalResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Interval: 5 * time.Minute},
+			{Interval: 5 * time.Minute},
+		},
+	})
+
+	err = s.wordpress.SetUpdateStatusInterval(15 * time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.UpdateStatusHookIntervals(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.UpdateStatusHookIntervalResults{
+		Results: []params.UpdateStatusHookIntervalResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Interval: 15 * time.Minute},
+			{Interval: 15 * time.Minute},
+		},
+	})
+}
+
 func (s *uniterSuite) TestOpenPorts(c *gc.C) {
 	openedPorts, err := s.wordpressUnit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
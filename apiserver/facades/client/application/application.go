@@ -38,13 +38,38 @@ var logger = loggo.GetLogger("juju.apiserver.application")
 
 // APIv4 provides the Application API facade for versions 1-4.
 type APIv4 struct {
+	*APIv5
+}
+
+// APIv5 provides the Application API facade for version 5.
+type APIv5 struct {
+	*APIv6
+}
+
+// APIv6 provides the Application API facade for version 6.
+type APIv6 struct {
+	*APIv7
+}
+
+// APIv7 provides the Application API facade for version 7.
+type APIv7 struct {
+	*APIv8
+}
+
+// APIv8 provides the Application API facade for version 8.
+type APIv8 struct {
+	*APIv9
+}
+
+// APIv9 provides the Application API facade for version 9.
+type APIv9 struct {
 	*API
 }
 
 // API implements the application interface and is the concrete
 // implementation of the api end point.
 //
-// API provides the Application API facade for version 5.
+// API provides the Application API facade for version 10.
 type API struct {
 	backend    Backend
 	authorizer facade.Authorizer
@@ -57,19 +82,69 @@ type API struct {
 	stateCharm func(Charm) *state.Charm
 
 	deployApplicationFunc func(ApplicationDeployer, DeployApplicationParams) (Application, error)
-	getEnviron            stateenvirons.NewEnvironFunc
+	newEnviron            func() (environs.Environ, error)
 }
 
 // NewFacadeV4 provides the signature required for facade registration
 // for versions 1-4.
 func NewFacadeV4(ctx facade.Context) (*APIv4, error) {
-	api, err := NewFacade(ctx)
+	api, err := NewFacadeV5(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv4{api}, nil
 }
 
+// NewFacadeV5 provides the signature required for facade registration
+// for version 5.
+func NewFacadeV5(ctx facade.Context) (*APIv5, error) {
+	api, err := NewFacadeV6(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv5{api}, nil
+}
+
+// NewFacadeV6 provides the signature required for facade registration
+// for version 6.
+func NewFacadeV6(ctx facade.Context) (*APIv6, error) {
+	api, err := NewFacadeV7(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv6{api}, nil
+}
+
+// NewFacadeV7 provides the signature required for facade registration
+// for version 7.
+func NewFacadeV7(ctx facade.Context) (*APIv7, error) {
+	api, err := NewFacadeV8(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv7{api}, nil
+}
+
+// NewFacadeV8 provides the signature required for facade registration
+// for version 8.
+func NewFacadeV8(ctx facade.Context) (*APIv8, error) {
+	api, err := NewFacadeV9(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv8{api}, nil
+}
+
+// NewFacadeV9 provides the signature required for facade registration
+// for version 9.
+func NewFacadeV9(ctx facade.Context) (*APIv9, error) {
+	api, err := NewFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv9{api}, nil
+}
+
 // NewFacade provides the signature required for facade registration.
 func NewFacade(ctx facade.Context) (*API, error) {
 	backend, err := NewStateBackend(ctx.State())
@@ -78,12 +153,16 @@ func NewFacade(ctx facade.Context) (*API, error) {
 	}
 	blockChecker := common.NewBlockChecker(ctx.State())
 	stateCharm := CharmToStateCharm
+	newEnviron := func() (environs.Environ, error) {
+		return stateenvirons.GetNewEnvironFunc(environs.New)(ctx.State())
+	}
 	return NewAPI(
 		backend,
 		ctx.Auth(),
 		blockChecker,
 		stateCharm,
 		DeployApplication,
+		newEnviron,
 	)
 }
 
@@ -94,6 +173,7 @@ func NewAPI(
 	blockChecker BlockChecker,
 	stateCharm func(Charm) *state.Charm,
 	deployApplication func(ApplicationDeployer, DeployApplicationParams) (Application, error),
+	newEnviron func() (environs.Environ, error),
 ) (*API, error) {
 	if !authorizer.AuthClient() {
 		return nil, common.ErrPerm
@@ -104,6 +184,7 @@ func NewAPI(
 		check:                 blockChecker,
 		stateCharm:            stateCharm,
 		deployApplicationFunc: deployApplication,
+		newEnviron:            newEnviron,
 	}, nil
 }
 
@@ -151,6 +232,123 @@ func (api *API) SetMetricCredentials(args params.ApplicationMetricCredentials) (
 	return result, nil
 }
 
+// SetUpdateStatusIntervals sets how often the update-status hook runs
+// for the units of each application.
+func (api *API) SetUpdateStatusIntervals(args params.ApplicationUpdateStatusIntervals) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		application, err := api.backend.Application(arg.ApplicationName)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if err := application.SetUpdateStatusInterval(arg.Interval); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// SetHookTimeouts sets how long the hooks of the units of each
+// application may run before they are killed, and whether hooks that
+// time out are retried.
+func (api *API) SetHookTimeouts(args params.ApplicationHookTimeouts) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		application, err := api.backend.Application(arg.ApplicationName)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		policy := state.HookTimeoutPolicy{
+			Timeout: arg.Timeout,
+			Retry:   arg.Retry,
+		}
+		if err := application.SetHookTimeoutPolicy(policy); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// UnitHookExecutions returns the hook and action executions recorded
+// for each given unit: either the most recent executions, without
+// their output, or a single execution with its output.
+func (api *API) UnitHookExecutions(args params.UnitHookExecutionsQueries) (params.HookExecutionsResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.HookExecutionsResults{}, errors.Trace(err)
+	}
+	result := params.HookExecutionsResults{
+		Results: make([]params.HookExecutionsResult, len(args.Queries)),
+	}
+	for i, query := range args.Queries {
+		executions, err := api.unitHookExecutions(query)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Executions = executions
+	}
+	return result, nil
+}
+
+func (api *API) unitHookExecutions(query params.UnitHookExecutionsQuery) ([]params.HookExecution, error) {
+	tag, err := names.ParseUnitTag(query.Tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	unit, err := api.backend.Unit(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var executions []state.HookExecution
+	if query.Seq != 0 {
+		execution, err := unit.HookExecution(query.Seq)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		executions = []state.HookExecution{execution}
+	} else {
+		executions, err = unit.HookExecutions(query.Limit)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	results := make([]params.HookExecution, len(executions))
+	for i, execution := range executions {
+		results[i] = params.HookExecution{
+			Seq:        execution.Seq,
+			Kind:       execution.Kind,
+			Name:       execution.Name,
+			Relation:   execution.Relation,
+			RemoteUnit: execution.RemoteUnit,
+			Started:    execution.Started,
+			Finished:   execution.Finished,
+			ExitCode:   execution.ExitCode,
+			Error:      execution.Error,
+			Stdout:     execution.Stdout,
+			Stderr:     execution.Stderr,
+		}
+	}
+	return results, nil
+}
+
 // Deploy fetches the charms from the charm store and deploys them
 // using the specified placement directives.
 func (api *API) Deploy(args params.ApplicationsDeploy) (params.ErrorResults, error) {
@@ -690,7 +888,9 @@ func (api *API) CharmRelations(p params.ApplicationCharmRelations) (params.Appli
 }
 
 // Expose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
+// were also explicitly marked by units as open. If endpoint specific
+// expose settings are provided, they are merged into any existing
+// settings for the application.
 func (api *API) Expose(args params.ApplicationExpose) error {
 	if err := api.checkCanWrite(); err != nil {
 		return err
@@ -702,11 +902,22 @@ func (api *API) Expose(args params.ApplicationExpose) error {
 	if err != nil {
 		return err
 	}
-	return app.SetExposed()
+	if len(args.ExposedEndpoints) == 0 {
+		return app.SetExposed()
+	}
+	exposedEndpoints := make(map[string]state.ExposedEndpoint, len(args.ExposedEndpoints))
+	for endpoint, exp := range args.ExposedEndpoints {
+		exposedEndpoints[endpoint] = state.ExposedEndpoint{
+			ExposeToSpaces: exp.ExposeToSpaces,
+			ExposeToCIDRs:  exp.ExposeToCIDRs,
+		}
+	}
+	return app.MergeExposeSettings(exposedEndpoints)
 }
 
 // Unexpose changes the juju-managed firewall to unexpose any ports that
-// were also explicitly marked by units as open.
+// were also explicitly marked by units as open. If endpoints are
+// specified, only the expose settings for those endpoints are removed.
 func (api *API) Unexpose(args params.ApplicationUnexpose) error {
 	if err := api.checkCanWrite(); err != nil {
 		return err
@@ -718,7 +929,26 @@ func (api *API) Unexpose(args params.ApplicationUnexpose) error {
 	if err != nil {
 		return err
 	}
-	return app.ClearExposed()
+	if len(args.ExposedEndpoints) == 0 {
+		return app.ClearExposed()
+	}
+	return app.UnsetExposeSettings(args.ExposedEndpoints)
+}
+
+// Expose changes the juju-managed firewall to expose any ports that
+// were also explicitly marked by units as open. The V5 API does not
+// support endpoint specific expose settings.
+func (api *APIv5) Expose(args params.ApplicationExpose) error {
+	args.ExposedEndpoints = nil
+	return api.API.Expose(args)
+}
+
+// Unexpose changes the juju-managed firewall to unexpose any ports that
+// were also explicitly marked by units as open. The V5 API does not
+// support endpoint specific expose settings.
+func (api *APIv5) Unexpose(args params.ApplicationUnexpose) error {
+	args.ExposedEndpoints = nil
+	return api.API.Unexpose(args)
 }
 
 // AddUnits adds a given number of units to an application.
@@ -1377,16 +1607,29 @@ func (api *API) maybeUpdateExistingApplicationEndpoints(
 	return existingRemoteApp, nil
 }
 
-// Mask the new methods from the V4 API. The API reflection code in
-// rpc/rpcreflect/type.go:newMethod skips 2-argument methods, so this
-// removes the method as far as the RPC machinery is concerned.
+// Mask the new methods from older versions of the API. The API
+// reflection code in rpc/rpcreflect/type.go:newMethod skips 2-argument
+// methods, so this removes the method as far as the RPC machinery is
+// concerned.
 
 // UpdateApplicationSeries isn't on the V4 API.
 func (u *APIv4) UpdateApplicationSeries(_, _ struct{}) {}
 
+// SetUpdateStatusIntervals isn't on the V6 API.
+func (u *APIv6) SetUpdateStatusIntervals(_, _ struct{}) {}
+
+// UnitHookExecutions isn't on the V7 API.
+func (u *APIv7) UnitHookExecutions(_, _ struct{}) {}
+
+// SetHookTimeouts isn't on the V8 API.
+func (u *APIv8) SetHookTimeouts(_, _ struct{}) {}
+
 // GetConfig isn't on the V4 API.
 func (u *APIv4) GetConfig(_, _ struct{}) {}
 
+// PrecheckDeploy isn't on the V9 API.
+func (u *APIv9) PrecheckDeploy(_, _ struct{}) {}
+
 // GetConstraints returns the v4 implementation of GetConstraints.
 func (api *APIv4) GetConstraints(args params.GetApplicationConstraints) (params.GetConstraintsResults, error) {
 	if err := api.checkCanRead(); err != nil {
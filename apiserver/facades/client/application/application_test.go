@@ -29,9 +29,11 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
 	statestorage "github.com/juju/juju/state/storage"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/status"
@@ -96,6 +98,9 @@ func (s *applicationSuite) makeAPI(c *gc.C) *application.API {
 		blockChecker,
 		application.CharmToStateCharm,
 		application.DeployApplication,
+		func() (environs.Environ, error) {
+			return stateenvirons.GetNewEnvironFunc(environs.New)(s.State)
+		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
 	return api
@@ -272,6 +277,112 @@ func (s *applicationSuite) TestSetMetricCredentials(c *gc.C) {
 	}
 }
 
+func (s *applicationSuite) TestSetUpdateStatusIntervals(c *gc.C) {
+	results, err := s.applicationAPI.SetUpdateStatusIntervals(params.ApplicationUpdateStatusIntervals{
+		Args: []params.ApplicationUpdateStatusInterval{
+			{ApplicationName: s.application.Name(), Interval: 15 * time.Minute},
+			{ApplicationName: s.application.Name(), Interval: time.Second},
+			{ApplicationName: "not-a-application", Interval: 15 * time.Minute},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot set update status interval for application ".*": interval 1s less than 1m0s not valid`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `application "not-a-application" not found`)
+
+	err = s.application.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.application.UpdateStatusInterval(), gc.Equals, 15*time.Minute)
+
+	get, err := s.applicationAPI.Get(params.ApplicationGet{s.application.Name()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(get.UpdateStatusInterval, gc.Equals, 15*time.Minute)
+}
+
+func (s *applicationSuite) TestSetUpdateStatusIntervalsBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestSetUpdateStatusIntervalsBlocked")
+	_, err := s.applicationAPI.SetUpdateStatusIntervals(params.ApplicationUpdateStatusIntervals{
+		Args: []params.ApplicationUpdateStatusInterval{
+			{ApplicationName: s.application.Name(), Interval: 15 * time.Minute},
+		},
+	})
+	s.AssertBlocked(c, err, "TestSetUpdateStatusIntervalsBlocked")
+}
+
+func (s *applicationSuite) TestSetHookTimeouts(c *gc.C) {
+	results, err := s.applicationAPI.SetHookTimeouts(params.ApplicationHookTimeouts{
+		Args: []params.ApplicationHookTimeout{
+			{ApplicationName: s.application.Name(), Timeout: 10 * time.Minute},
+			{ApplicationName: s.application.Name(), Timeout: time.Second},
+			{ApplicationName: "not-a-application", Timeout: 10 * time.Minute},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot set hook timeout for application ".*": timeout 1s less than 10s not valid`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `application "not-a-application" not found`)
+
+	err = s.application.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.application.HookTimeoutPolicy(), jc.DeepEquals, state.HookTimeoutPolicy{
+		Timeout: 10 * time.Minute,
+	})
+
+	get, err := s.applicationAPI.Get(params.ApplicationGet{s.application.Name()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(get.HookTimeout, gc.Equals, 10*time.Minute)
+	c.Assert(get.HookTimeoutNoRetry, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestSetHookTimeoutsBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestSetHookTimeoutsBlocked")
+	_, err := s.applicationAPI.SetHookTimeouts(params.ApplicationHookTimeouts{
+		Args: []params.ApplicationHookTimeout{
+			{ApplicationName: s.application.Name(), Timeout: 10 * time.Minute, Retry: true},
+		},
+	})
+	s.AssertBlocked(c, err, "TestSetHookTimeoutsBlocked")
+}
+
+func (s *applicationSuite) TestUnitHookExecutions(c *gc.C) {
+	unit, err := s.application.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	started := time.Date(2017, 10, 1, 12, 0, 0, 0, time.UTC)
+	for _, name := range []string{"install", "start"} {
+		_, err := unit.RecordHookExecution(state.HookExecution{
+			Kind:     state.HookExecutionHook,
+			Name:     name,
+			Started:  started,
+			Finished: started,
+			Stdout:   name + " output",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	results, err := s.applicationAPI.UnitHookExecutions(params.UnitHookExecutionsQueries{
+		Queries: []params.UnitHookExecutionsQuery{
+			{Tag: unit.Tag().String()},
+			{Tag: unit.Tag().String(), Seq: 1},
+			{Tag: unit.Tag().String(), Seq: 42},
+			{Tag: "unit-foo-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Executions, gc.HasLen, 2)
+	c.Assert(results.Results[0].Executions[0].Name, gc.Equals, "start")
+	c.Assert(results.Results[0].Executions[0].Stdout, gc.Equals, "")
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[1].Executions, gc.HasLen, 1)
+	c.Assert(results.Results[1].Executions[0].Name, gc.Equals, "install")
+	c.Assert(results.Results[1].Executions[0].Stdout, gc.Equals, "install output")
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `hook execution 42 for unit ".*" not found`)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `unit "foo/0" not found`)
+}
+
 func (s *applicationSuite) TestCompatibleSettingsParsing(c *gc.C) {
 	// Test the exported settings parsing in a compatible way.
 	s.AddTestingApplication(c, "dummy", s.AddTestingCharm(c, "dummy"))
@@ -1996,7 +2107,7 @@ func (s *applicationSuite) TestApplicationExpose(c *gc.C) {
 	c.Assert(apps[1].IsExposed(), jc.IsTrue)
 	for i, t := range applicationExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err = s.applicationAPI.Expose(params.ApplicationExpose{t.application})
+		err = s.applicationAPI.Expose(params.ApplicationExpose{ApplicationName: t.application})
 		if t.err != "" {
 			c.Assert(err, gc.ErrorMatches, t.err)
 		} else {
@@ -2048,7 +2159,7 @@ var applicationExposeTests = []struct {
 func (s *applicationSuite) assertApplicationExpose(c *gc.C) {
 	for i, t := range applicationExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err := s.applicationAPI.Expose(params.ApplicationExpose{t.application})
+		err := s.applicationAPI.Expose(params.ApplicationExpose{ApplicationName: t.application})
 		if t.err != "" {
 			c.Assert(err, gc.ErrorMatches, t.err)
 		} else {
@@ -2063,7 +2174,7 @@ func (s *applicationSuite) assertApplicationExpose(c *gc.C) {
 func (s *applicationSuite) assertApplicationExposeBlocked(c *gc.C, msg string) {
 	for i, t := range applicationExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err := s.applicationAPI.Expose(params.ApplicationExpose{t.application})
+		err := s.applicationAPI.Expose(params.ApplicationExpose{ApplicationName: t.application})
 		s.AssertBlocked(c, err, msg)
 	}
 }
@@ -2086,6 +2197,81 @@ func (s *applicationSuite) TestBlockChangesApplicationExpose(c *gc.C) {
 	s.assertApplicationExposeBlocked(c, "TestBlockChangesApplicationExpose")
 }
 
+func (s *applicationSuite) TestApplicationExposeEndpoints(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := s.applicationAPI.Expose(params.ApplicationExpose{
+		ApplicationName: "wordpress",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"url": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.IsExposed(), jc.IsTrue)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+
+	err = s.applicationAPI.Expose(params.ApplicationExpose{
+		ApplicationName: "wordpress",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"bogus": {},
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot update expose settings for application "wordpress": endpoint "bogus" not found`)
+}
+
+func (s *applicationSuite) TestApplicationUnexposeEndpoints(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url":             {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		"monitoring-port": {ExposeToCIDRs: []string{"192.168.0.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.applicationAPI.Unexpose(params.ApplicationUnexpose{
+		ApplicationName:  "wordpress",
+		ExposedEndpoints: []string{"url"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.IsExposed(), jc.IsTrue)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"monitoring-port": {ExposeToCIDRs: []string{"192.168.0.0/24"}},
+	})
+}
+
+func (s *applicationSuite) TestApplicationExposeEndpointsV5(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	apiV5 := &application.APIv5{&application.APIv6{&application.APIv7{&application.APIv8{&application.APIv9{s.applicationAPI}}}}}
+	err := apiV5.Expose(params.ApplicationExpose{
+		ApplicationName: "wordpress",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"url": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.IsExposed(), jc.IsTrue)
+	c.Assert(app.ExposedEndpoints(), gc.HasLen, 0)
+
+	err = apiV5.Unexpose(params.ApplicationUnexpose{
+		ApplicationName:  "wordpress",
+		ExposedEndpoints: []string{"url"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.IsExposed(), jc.IsFalse)
+}
+
 var applicationUnexposeTests = []struct {
 	about       string
 	application string
@@ -2121,7 +2307,7 @@ func (s *applicationSuite) TestApplicationUnexpose(c *gc.C) {
 			app.SetExposed()
 		}
 		c.Assert(app.IsExposed(), gc.Equals, t.initial)
-		err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{t.application})
+		err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{ApplicationName: t.application})
 		if t.err == "" {
 			c.Assert(err, jc.ErrorIsNil)
 			app.Refresh()
@@ -2143,7 +2329,7 @@ func (s *applicationSuite) setupApplicationUnexpose(c *gc.C) *state.Application
 }
 
 func (s *applicationSuite) assertApplicationUnexpose(c *gc.C, app *state.Application) {
-	err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{"dummy-application"})
+	err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{ApplicationName: "dummy-application"})
 	c.Assert(err, jc.ErrorIsNil)
 	app.Refresh()
 	c.Assert(app.IsExposed(), gc.Equals, false)
@@ -2152,7 +2338,7 @@ func (s *applicationSuite) assertApplicationUnexpose(c *gc.C, app *state.Applica
 }
 
 func (s *applicationSuite) assertApplicationUnexposeBlocked(c *gc.C, app *state.Application, msg string) {
-	err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{"dummy-application"})
+	err := s.applicationAPI.Unexpose(params.ApplicationUnexpose{ApplicationName: "dummy-application"})
 	s.AssertBlocked(c, err, msg)
 	err = app.Destroy()
 	c.Assert(err, jc.ErrorIsNil)
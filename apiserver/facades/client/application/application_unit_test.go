@@ -51,6 +51,9 @@ func (s *ApplicationSuite) setAPIUser(c *gc.C, user names.UserTag) {
 		func(application.ApplicationDeployer, application.DeployApplicationParams) (application.Application, error) {
 			return nil, nil
 		},
+		func() (environs.Environ, error) {
+			return s.env, nil
+		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
 	s.api = api
@@ -158,6 +161,9 @@ func (s *ApplicationSuite) SetUpTest(c *gc.C) {
 		func(application.ApplicationDeployer, application.DeployApplicationParams) (application.Application, error) {
 			return nil, nil
 		},
+		func() (environs.Environ, error) {
+			return s.env, nil
+		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
 	s.api = api
@@ -566,6 +572,9 @@ func (s *ApplicationSuite) TestSetRelationSuspendedPermissionDenied(c *gc.C) {
 		func(application.ApplicationDeployer, application.DeployApplicationParams) (application.Application, error) {
 			return nil, nil
 		},
+		func() (environs.Environ, error) {
+			return s.env, nil
+		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
 	_, err = api.SetRelationsSuspended(params.RelationSuspendedArgs{
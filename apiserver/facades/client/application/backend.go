@@ -4,6 +4,8 @@
 package application
 
 import (
+	"time"
+
 	"gopkg.in/juju/charm.v6-unstable"
 	csparams "gopkg.in/juju/charmrepo.v2-unstable/csclient/params"
 	"gopkg.in/juju/names.v2"
@@ -35,6 +37,7 @@ type Backend interface {
 	Relation(int) (Relation, error)
 	InferEndpoints(...string) ([]state.Endpoint, error)
 	Machine(string) (Machine, error)
+	ModelConstraints() (constraints.Value, error)
 	ModelTag() names.ModelTag
 	Unit(string) (Unit, error)
 	SaveController(info crossmodel.ControllerInfo, modelUUID string) (ExternalController, error)
@@ -68,15 +71,21 @@ type Application interface {
 	Destroy() error
 	DestroyOperation() *state.DestroyApplicationOperation
 	Endpoints() ([]state.Endpoint, error)
+	HookTimeoutPolicy() state.HookTimeoutPolicy
 	IsPrincipal() bool
+	MergeExposeSettings(map[string]state.ExposedEndpoint) error
 	Series() string
 	SetCharm(state.SetCharmConfig) error
 	SetConstraints(constraints.Value) error
 	SetExposed() error
+	SetHookTimeoutPolicy(state.HookTimeoutPolicy) error
 	SetMetricCredentials([]byte) error
 	SetMinUnits(int) error
+	SetUpdateStatusInterval(time.Duration) error
+	UnsetExposeSettings([]string) error
 	UpdateApplicationSeries(string, bool) error
 	UpdateConfigSettings(charm.Settings) error
+	UpdateStatusInterval() time.Duration
 }
 
 // Charm defines a subset of the functionality provided by the
@@ -116,6 +125,8 @@ type Unit interface {
 	UnitTag() names.UnitTag
 	Destroy() error
 	DestroyOperation() *state.DestroyUnitOperation
+	HookExecution(int) (state.HookExecution, error)
+	HookExecutions(int) ([]state.HookExecution, error)
 	IsPrincipal() bool
 	Life() state.Life
 
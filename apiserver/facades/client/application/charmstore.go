@@ -20,6 +20,7 @@ import (
 	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/storage"
@@ -186,6 +187,19 @@ func minVersionError(minver, jujuver version.Number) error {
 	return minJujuVersionErr{&err}
 }
 
+// CharmLXDProfile returns the LXD profile declared by the charm, if
+// any, after checking that it only uses allowed config and devices.
+func CharmLXDProfile(ch charm.Charm) (*lxdprofile.Profile, error) {
+	profile, err := lxdprofile.ReadCharm(ch)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, errors.Annotate(err, "invalid charm")
+	}
+	return profile, nil
+}
+
 // CharmArchive is the data that needs to be stored for a charm archive in
 // state.
 type CharmArchive struct {
@@ -210,6 +224,11 @@ type CharmArchive struct {
 
 // StoreCharmArchive stores a charm archive in environment storage.
 func StoreCharmArchive(st *state.State, archive CharmArchive) error {
+	profile, err := CharmLXDProfile(archive.Charm)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	storage := newStateStorage(st.ModelUUID(), st.MongoSession())
 	storagePath, err := charmArchiveStoragePath(archive.ID)
 	if err != nil {
@@ -225,6 +244,7 @@ func StoreCharmArchive(st *state.State, archive CharmArchive) error {
 		StoragePath: storagePath,
 		SHA256:      archive.SHA256,
 		Macaroon:    archive.Macaroon,
+		LXDProfile:  profile,
 	}
 
 	// Now update the charm data in state and mark it as no longer pending.
@@ -50,12 +50,16 @@ func (api *API) getApplicationSettings(
 			return params.ApplicationGetResults{}, err
 		}
 	}
+	hookTimeout := app.HookTimeoutPolicy()
 	return params.ApplicationGetResults{
-		Application: args.ApplicationName,
-		Charm:       charm.Meta().Name,
-		Config:      configInfo,
-		Constraints: constraints,
-		Series:      app.Series(),
+		Application:          args.ApplicationName,
+		Charm:                charm.Meta().Name,
+		Config:               configInfo,
+		Constraints:          constraints,
+		Series:               app.Series(),
+		UpdateStatusInterval: app.UpdateStatusInterval(),
+		HookTimeout:          hookTimeout.Timeout,
+		HookTimeoutNoRetry:   !hookTimeout.Retry,
 	}, nil
 }
 
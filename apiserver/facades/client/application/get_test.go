@@ -16,7 +16,9 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state/stateenvirons"
 )
 
 type getSuite struct {
@@ -43,13 +45,16 @@ func (s *getSuite) SetUpTest(c *gc.C) {
 		blockChecker,
 		application.CharmToStateCharm,
 		application.DeployApplication,
+		func() (environs.Environ, error) {
+			return stateenvirons.GetNewEnvironFunc(environs.New)(s.State)
+		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (s *getSuite) TestClientServiceGetSmoketestV4(c *gc.C) {
 	s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
-	v4 := &application.APIv4{s.serviceAPI}
+	v4 := &application.APIv4{&application.APIv5{&application.APIv6{&application.APIv7{&application.APIv8{&application.APIv9{s.serviceAPI}}}}}}
 	results, err := v4.Get(params.ApplicationGet{"wordpress"})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, gc.DeepEquals, params.ApplicationGetResults{
@@ -16,10 +16,12 @@ import (
 	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver/facades/client/application"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	providercommon "github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state"
 	statestorage "github.com/juju/juju/state/storage"
 	"github.com/juju/juju/status"
@@ -31,6 +33,8 @@ type mockEnviron struct {
 
 	stub      jtesting.Stub
 	spaceInfo *environs.ProviderSpaceInfo
+	zones     []providercommon.AvailabilityZone
+	subnets   []network.SubnetInfo
 }
 
 func (e *mockEnviron) ProviderSpaceInfo(space *network.SpaceInfo) (*environs.ProviderSpaceInfo, error) {
@@ -38,6 +42,51 @@ func (e *mockEnviron) ProviderSpaceInfo(space *network.SpaceInfo) (*environs.Pro
 	return e.spaceInfo, e.stub.NextErr()
 }
 
+func (e *mockEnviron) ConstraintsValidator() (constraints.Validator, error) {
+	e.stub.MethodCall(e, "ConstraintsValidator")
+	validator := constraints.NewValidator()
+	validator.RegisterVocabulary(constraints.Arch, []string{"amd64"})
+	return validator, e.stub.NextErr()
+}
+
+func (e *mockEnviron) PrecheckInstance(args environs.PrecheckInstanceParams) error {
+	e.stub.MethodCall(e, "PrecheckInstance", args)
+	return e.stub.NextErr()
+}
+
+func (e *mockEnviron) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
+	e.stub.MethodCall(e, "AvailabilityZones")
+	return e.zones, e.stub.NextErr()
+}
+
+func (e *mockEnviron) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	e.stub.MethodCall(e, "InstanceAvailabilityZoneNames", ids)
+	return nil, e.stub.NextErr()
+}
+
+func (e *mockEnviron) DeriveAvailabilityZone(args environs.StartInstanceParams) (string, error) {
+	e.stub.MethodCall(e, "DeriveAvailabilityZone", args)
+	return "", e.stub.NextErr()
+}
+
+func (e *mockEnviron) Subnets(inst instance.Id, subnetIds []network.Id) ([]network.SubnetInfo, error) {
+	e.stub.MethodCall(e, "Subnets", inst, subnetIds)
+	return e.subnets, e.stub.NextErr()
+}
+
+type mockAvailabilityZone struct {
+	name      string
+	available bool
+}
+
+func (z *mockAvailabilityZone) Name() string {
+	return z.name
+}
+
+func (z *mockAvailabilityZone) Available() bool {
+	return z.available
+}
+
 type mockNoNetworkEnviron struct {
 	environs.Environ
 }
@@ -266,6 +315,8 @@ type mockBackend struct {
 	storageInstances           map[string]*mockStorage
 	storageInstanceFilesystems map[string]*mockFilesystem
 	controllers                map[string]crossmodel.ControllerInfo
+	machines                   map[string]application.Machine
+	modelConstraints           constraints.Value
 }
 
 func (m *mockBackend) ControllerTag() names.ControllerTag {
@@ -497,6 +548,23 @@ func (m *mockBackend) ModelUUID() string {
 	return m.modelUUID
 }
 
+func (m *mockBackend) Machine(id string) (application.Machine, error) {
+	m.MethodCall(m, "Machine", id)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	machine, ok := m.machines[id]
+	if !ok {
+		return nil, errors.NotFoundf("machine %s", id)
+	}
+	return machine, nil
+}
+
+func (m *mockBackend) ModelConstraints() (constraints.Value, error) {
+	m.MethodCall(m, "ModelConstraints")
+	return m.modelConstraints, m.NextErr()
+}
+
 func (m *mockBackend) ModelTag() names.ModelTag {
 	m.MethodCall(m, "ModelTag")
 	m.PopNoErr()
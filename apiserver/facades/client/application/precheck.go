@@ -0,0 +1,192 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	providercommon "github.com/juju/juju/provider/common"
+)
+
+// PrecheckDeploy checks whether the machines a deployment would create
+// or place units on could be provisioned, without changing the model.
+// Every problem found with a machine is reported in its result.
+func (api *API) PrecheckDeploy(args params.PrecheckDeployArgs) (params.PrecheckDeployResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.PrecheckDeployResults{}, errors.Trace(err)
+	}
+	results := params.PrecheckDeployResults{
+		Results: make([]params.PrecheckDeployResult, len(args.Machines)),
+	}
+	if len(args.Machines) == 0 {
+		return results, nil
+	}
+
+	env, err := api.newEnviron()
+	if err != nil {
+		return params.PrecheckDeployResults{}, errors.Annotate(err, "opening environ")
+	}
+	validator, err := env.ConstraintsValidator()
+	if err != nil {
+		return params.PrecheckDeployResults{}, errors.Annotate(err, "getting constraints validator")
+	}
+	modelCons, err := api.backend.ModelConstraints()
+	if err != nil {
+		return params.PrecheckDeployResults{}, errors.Annotate(err, "getting model constraints")
+	}
+
+	p := &deployPrechecker{
+		backend:   api.backend,
+		env:       env,
+		validator: validator,
+		modelCons: modelCons,
+		modelUUID: api.backend.ModelTag().Id(),
+	}
+	for i, machine := range args.Machines {
+		for _, err := range p.check(machine) {
+			results.Results[i].Errors = append(results.Results[i].Errors, common.ServerError(err))
+		}
+	}
+	return results, nil
+}
+
+// deployPrechecker checks would-be machines against the model's
+// environ, caching the zones and subnets it looks up.
+type deployPrechecker struct {
+	backend   Backend
+	env       environs.Environ
+	validator constraints.Validator
+	modelCons constraints.Value
+	modelUUID string
+
+	zones   []providercommon.AvailabilityZone
+	subnets []network.SubnetInfo
+}
+
+// check returns every problem found with the given machine.
+func (p *deployPrechecker) check(m params.PrecheckDeployMachine) []error {
+	var errs []error
+	cons, err := p.validator.Merge(p.modelCons, m.Constraints)
+	if err != nil {
+		errs = append(errs, errors.Annotate(err, "invalid constraints"))
+		cons = m.Constraints
+	}
+
+	var placement string
+	if m.Placement != nil {
+		scope, directive := m.Placement.Scope, m.Placement.Directive
+		switch {
+		case scope == instance.MachineScope:
+			// Units are placed on an existing machine or container,
+			// so there is no instance to provision.
+			return append(errs, p.checkMachine(directive)...)
+		case isContainerScope(scope):
+			if directive != "" {
+				return append(errs, p.checkMachine(directive)...)
+			}
+			// The container goes on a new machine, which is checked
+			// without any placement.
+		case scope == p.modelUUID:
+			if err := p.checkPlacement(directive); err != nil {
+				errs = append(errs, err)
+			} else {
+				placement = directive
+			}
+		default:
+			errs = append(errs, errors.NotValidf("placement scope %q", scope))
+		}
+	}
+
+	if err := p.env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Series:      m.Series,
+		Constraints: cons,
+		Placement:   placement,
+	}); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func isContainerScope(scope string) bool {
+	_, err := instance.ParseContainerType(scope)
+	return err == nil
+}
+
+func (p *deployPrechecker) checkMachine(id string) []error {
+	if _, err := p.backend.Machine(id); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// checkPlacement checks zone and subnet placement directives against
+// the zones and subnets known to the environ. Other directives are
+// left to the environ's PrecheckInstance.
+func (p *deployPrechecker) checkPlacement(directive string) error {
+	parts := strings.SplitN(directive, "=", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	switch parts[0] {
+	case "zone":
+		return p.checkZone(parts[1])
+	case "subnet":
+		return p.checkSubnet(parts[1])
+	}
+	return nil
+}
+
+func (p *deployPrechecker) checkZone(name string) error {
+	zonedEnv, ok := p.env.(providercommon.ZonedEnviron)
+	if !ok {
+		return nil
+	}
+	if p.zones == nil {
+		zones, err := zonedEnv.AvailabilityZones()
+		if err != nil {
+			return errors.Annotate(err, "getting availability zones")
+		}
+		p.zones = zones
+	}
+	for _, zone := range p.zones {
+		if zone.Name() != name {
+			continue
+		}
+		if !zone.Available() {
+			return errors.Errorf("availability zone %q is unavailable", name)
+		}
+		return nil
+	}
+	return errors.NotFoundf("availability zone %q", name)
+}
+
+func (p *deployPrechecker) checkSubnet(cidrOrID string) error {
+	netEnv, ok := p.env.(environs.NetworkingEnviron)
+	if !ok {
+		return nil
+	}
+	if p.subnets == nil {
+		subnets, err := netEnv.Subnets(instance.UnknownId, nil)
+		if errors.IsNotSupported(err) {
+			return nil
+		} else if err != nil {
+			return errors.Annotate(err, "getting subnets")
+		}
+		p.subnets = subnets
+	}
+	for _, subnet := range p.subnets {
+		if subnet.CIDR == cidrOrID || string(subnet.ProviderId) == cidrOrID {
+			return nil
+		}
+	}
+	return errors.NotFoundf("subnet %q", cidrOrID)
+}
@@ -0,0 +1,139 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	providercommon "github.com/juju/juju/provider/common"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func (s *ApplicationSuite) setUpPrecheck() *mockEnviron {
+	s.backend.modelUUID = coretesting.ModelTag.Id()
+	s.backend.modelConstraints = constraints.MustParse("mem=4G")
+	s.backend.machines = map[string]application.Machine{
+		"3": struct{}{},
+	}
+	env := &mockEnviron{
+		zones: []providercommon.AvailabilityZone{
+			&mockAvailabilityZone{name: "zone-a", available: true},
+			&mockAvailabilityZone{name: "zone-b", available: false},
+		},
+		subnets: []network.SubnetInfo{
+			{CIDR: "10.0.0.0/24", ProviderId: "subnet-1"},
+		},
+	}
+	s.env = env
+	return env
+}
+
+func (s *ApplicationSuite) modelPlacement(directive string) *instance.Placement {
+	return &instance.Placement{Scope: coretesting.ModelTag.Id(), Directive: directive}
+}
+
+func (s *ApplicationSuite) TestPrecheckDeploy(c *gc.C) {
+	env := s.setUpPrecheck()
+	results, err := s.api.PrecheckDeploy(params.PrecheckDeployArgs{
+		Machines: []params.PrecheckDeployMachine{
+			{Series: "quantal", Constraints: constraints.MustParse("cores=2")},
+			{Series: "quantal", Placement: &instance.Placement{Scope: instance.MachineScope, Directive: "3"}},
+			{Series: "quantal", Placement: s.modelPlacement("zone=zone-a")},
+			{Series: "quantal", Placement: s.modelPlacement("subnet=subnet-1")},
+			{Series: "quantal", Placement: &instance.Placement{Scope: "lxd"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, make([]params.PrecheckDeployResult, 5))
+
+	s.backend.CheckCallNames(c, "ModelTag", "ModelConstraints", "ModelTag", "Machine")
+	env.stub.CheckCallNames(c,
+		"ConstraintsValidator",
+		"PrecheckInstance",
+		"AvailabilityZones", "PrecheckInstance",
+		"Subnets", "PrecheckInstance",
+		"PrecheckInstance",
+	)
+	env.stub.CheckCall(c, 1, "PrecheckInstance", environs.PrecheckInstanceParams{
+		Series:      "quantal",
+		Constraints: constraints.MustParse("cores=2 mem=4G"),
+	})
+	env.stub.CheckCall(c, 3, "PrecheckInstance", environs.PrecheckInstanceParams{
+		Series:      "quantal",
+		Constraints: constraints.MustParse("mem=4G"),
+		Placement:   "zone=zone-a",
+	})
+}
+
+func (s *ApplicationSuite) TestPrecheckDeployReportsEveryProblem(c *gc.C) {
+	env := s.setUpPrecheck()
+	env.stub.SetErrors(
+		nil, // ConstraintsValidator
+		nil, // PrecheckInstance
+		nil, // AvailabilityZones
+		errors.New("no capacity"),
+	)
+	results, err := s.api.PrecheckDeploy(params.PrecheckDeployArgs{
+		Machines: []params.PrecheckDeployMachine{
+			{Series: "quantal", Constraints: constraints.MustParse("arch=arm64")},
+			{Series: "quantal", Placement: s.modelPlacement("zone=zone-c")},
+			{Series: "quantal", Placement: s.modelPlacement("zone=zone-b")},
+			{Series: "quantal", Placement: &instance.Placement{Scope: "lxd", Directive: "7"}},
+			{Series: "quantal", Placement: s.modelPlacement("subnet=10.1.0.0/24")},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 5)
+
+	messages := func(result params.PrecheckDeployResult) []string {
+		var out []string
+		for _, err := range result.Errors {
+			out = append(out, err.Message)
+		}
+		return out
+	}
+	c.Assert(messages(results.Results[0]), gc.HasLen, 1)
+	c.Assert(messages(results.Results[0])[0], gc.Matches, `(?s)invalid constraints: invalid constraint value: arch=arm64.*`)
+	c.Assert(messages(results.Results[1]), jc.DeepEquals, []string{
+		`availability zone "zone-c" not found`,
+		"no capacity",
+	})
+	c.Assert(messages(results.Results[2]), jc.DeepEquals, []string{
+		`availability zone "zone-b" is unavailable`,
+	})
+	c.Assert(messages(results.Results[3]), jc.DeepEquals, []string{"machine 7 not found"})
+	c.Assert(results.Results[3].Errors[0].Code, gc.Equals, params.CodeNotFound)
+	c.Assert(messages(results.Results[4]), jc.DeepEquals, []string{`subnet "10.1.0.0/24" not found`})
+
+	// A placement that fails its checks is not passed on to the environ.
+	env.stub.CheckCall(c, 3, "PrecheckInstance", environs.PrecheckInstanceParams{
+		Series:      "quantal",
+		Constraints: constraints.MustParse("mem=4G"),
+	})
+}
+
+func (s *ApplicationSuite) TestPrecheckDeployNoMachines(c *gc.C) {
+	env := s.setUpPrecheck()
+	results, err := s.api.PrecheckDeploy(params.PrecheckDeployArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 0)
+	env.stub.CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestPrecheckDeployPermissionDenied(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	_, err := s.api.PrecheckDeploy(params.PrecheckDeployArgs{
+		Machines: []params.PrecheckDeployMachine{{Series: "quantal"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -4,6 +4,9 @@
 package client
 
 import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
 	"github.com/juju/juju/environs"
 )
 
@@ -11,8 +14,19 @@ import (
 var (
 	MatchPortRanges = matchPortRanges
 	MatchSubnet     = matchSubnet
+
+	ParseFilterExpression = parseFilterExpression
 )
 
+// FilterExpressionMatches reports whether the filter expression parsed
+// from pattern matches the value.
+func FilterExpressionMatches(c *gc.C, pattern, value string) bool {
+	expr, err := parseFilterExpression(pattern)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expr, gc.NotNil)
+	return expr.matches(value)
+}
+
 func SetNewEnviron(c *Client, newEnviron func() (environs.Environ, error)) {
 	c.newEnviron = newEnviron
 }
@@ -0,0 +1,254 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"path"
+	"regexp"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+)
+
+// filterExpressionPattern matches status filter patterns of the form
+// <field><operator><value>, such as "workload=blocked" or "app~^db".
+var filterExpressionPattern = regexp.MustCompile(`^([a-z][a-z.-]*)(!=|=|~)(.*)$`)
+
+// machineFilterFields holds the fields of a filter expression which
+// refer to a machine. Units are matched on the fields of the machine
+// they are assigned to.
+var machineFilterFields = set.NewStrings(
+	"machine", "machine.status", "machine.zone", "machine.series",
+)
+
+// unitFilterFields holds the fields of a filter expression which refer
+// to a unit.
+var unitFilterFields = set.NewStrings(
+	"unit", "app", "application", "workload", "agent",
+)
+
+// applicationFilterFields holds the fields of a filter expression which
+// refer to an application.
+var applicationFilterFields = set.NewStrings("app", "application")
+
+// filterExpression is a status filter pattern which compares a field
+// of a machine, application or unit with a value.
+type filterExpression struct {
+	field    string
+	operator string
+	value    string
+	re       *regexp.Regexp
+}
+
+// parseFilterExpression parses the pattern as a filter expression. If
+// the pattern is not an expression, it returns nil and no error.
+func parseFilterExpression(pattern string) (*filterExpression, error) {
+	parts := filterExpressionPattern.FindStringSubmatch(pattern)
+	if parts == nil {
+		return nil, nil
+	}
+	expr := &filterExpression{
+		field:    parts[1],
+		operator: parts[2],
+		value:    parts[3],
+	}
+	if !machineFilterFields.Contains(expr.field) && !unitFilterFields.Contains(expr.field) {
+		return nil, errors.NotValidf("filter field %q in %q", expr.field, pattern)
+	}
+	switch expr.operator {
+	case "~":
+		re, err := regexp.Compile(expr.value)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid filter %q", pattern)
+		}
+		expr.re = re
+	default:
+		if _, err := path.Match(expr.value, ""); err != nil {
+			return nil, errors.Annotatef(err, "invalid filter %q", pattern)
+		}
+	}
+	return expr, nil
+}
+
+// matches reports whether the value of the expression's field matches.
+// Values compared with = and != may contain the wildcards of
+// path.Match; values compared with ~ are regular expressions.
+func (e *filterExpression) matches(value string) bool {
+	switch e.operator {
+	case "~":
+		return e.re.MatchString(value)
+	case "!=":
+		ok, _ := path.Match(e.value, value)
+		return !ok
+	default:
+		ok, _ := path.Match(e.value, value)
+		return ok
+	}
+}
+
+// splitFilterPatterns separates the filter expressions from the plain
+// patterns.
+func splitFilterPatterns(patterns []string) (plain []string, exprs []*filterExpression, _ error) {
+	for _, pattern := range patterns {
+		expr, err := parseFilterExpression(pattern)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if expr == nil {
+			plain = append(plain, pattern)
+			continue
+		}
+		exprs = append(exprs, expr)
+	}
+	return plain, exprs, nil
+}
+
+// buildPredicate returns a Predicate which will evaluate a machine,
+// application, or unit against the given patterns. Plain patterns are
+// matched as by BuildPredicateFor, and an element must match every
+// filter expression as well. An element cannot match an expression
+// referring to fields it does not have; units are matched on the
+// fields of the machine returned for them by getMachine.
+func buildPredicate(patterns []string, getMachine func(id string) *state.Machine) (Predicate, error) {
+	plain, exprs, err := splitFilterPatterns(patterns)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var plainPredicate Predicate
+	if len(plain) > 0 {
+		plainPredicate = buildPlainPredicate(plain)
+	}
+	return func(i interface{}) (bool, error) {
+		if plainPredicate != nil {
+			if matches, err := plainPredicate(i); err != nil || !matches {
+				return false, err
+			}
+		}
+		for _, expr := range exprs {
+			matches, err := matchFilterExpression(expr, i, getMachine)
+			if err != nil || !matches {
+				return false, errors.Trace(err)
+			}
+		}
+		return true, nil
+	}, nil
+}
+
+func matchFilterExpression(expr *filterExpression, i interface{}, getMachine func(id string) *state.Machine) (bool, error) {
+	switch entity := i.(type) {
+	case *state.Machine:
+		if !machineFilterFields.Contains(expr.field) {
+			return false, nil
+		}
+		value, err := machineFilterValue(entity, expr.field)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		return expr.matches(value), nil
+	case *state.Unit:
+		if machineFilterFields.Contains(expr.field) {
+			machineId, err := entity.AssignedMachineId()
+			if errors.IsNotAssigned(err) {
+				return false, nil
+			} else if err != nil {
+				return false, errors.Trace(err)
+			}
+			var m *state.Machine
+			if getMachine != nil {
+				m = getMachine(machineId)
+			}
+			if m == nil {
+				return false, nil
+			}
+			value, err := machineFilterValue(m, expr.field)
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			return expr.matches(value), nil
+		}
+		value, err := unitFilterValue(entity, expr.field)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		return expr.matches(value), nil
+	case *state.Application:
+		if !applicationFilterFields.Contains(expr.field) {
+			return false, nil
+		}
+		return expr.matches(entity.Name()), nil
+	}
+	return false, errors.Errorf("cannot filter %T", i)
+}
+
+func machineFilterValue(m *state.Machine, field string) (string, error) {
+	switch field {
+	case "machine":
+		return m.Id(), nil
+	case "machine.status":
+		statusInfo, err := m.Status()
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return string(statusInfo.Status), nil
+	case "machine.zone":
+		zone, err := m.AvailabilityZone()
+		if errors.IsNotProvisioned(err) {
+			return "", nil
+		}
+		return zone, errors.Trace(err)
+	case "machine.series":
+		return m.Series(), nil
+	}
+	return "", errors.NotValidf("machine filter field %q", field)
+}
+
+// unitFilterValue returns the value of the field for the unit. As in
+// the status output, a unit whose agent is in error is shown with an
+// idle agent and a workload in error.
+func unitFilterValue(u *state.Unit, field string) (string, error) {
+	switch field {
+	case "unit":
+		return u.Name(), nil
+	case "app", "application":
+		return u.ApplicationName(), nil
+	}
+	agentStatus, err := u.AgentStatus()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	switch field {
+	case "agent":
+		if agentStatus.Status == status.Error {
+			return string(status.Idle), nil
+		}
+		return string(agentStatus.Status), nil
+	case "workload":
+		if agentStatus.Status == status.Error {
+			return string(status.Error), nil
+		}
+		workloadStatus, err := u.Status()
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return string(workloadStatus.Status), nil
+	}
+	return "", errors.NotValidf("unit filter field %q", field)
+}
+
+// machinesById returns a function returning the machine with the given
+// id among the machines, which are keyed by their top level machine.
+func machinesById(machines map[string][]*state.Machine) func(string) *state.Machine {
+	byId := make(map[string]*state.Machine)
+	for _, machineList := range machines {
+		for _, m := range machineList {
+			byId[m.Id()] = m
+		}
+	}
+	return func(id string) *state.Machine {
+		return byId[id]
+	}
+}
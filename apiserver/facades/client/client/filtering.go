@@ -70,9 +70,21 @@ func UnitChainPredicateFn(
 	return f
 }
 
-// BuildPredicate returns a Predicate which will evaluate a machine,
-// service, or unit against the given patterns.
+// BuildPredicateFor returns a Predicate which will evaluate a machine,
+// service, or unit against the given patterns. Units cannot be matched
+// on the fields of their machine in filter expressions.
 func BuildPredicateFor(patterns []string) Predicate {
+	predicate, err := buildPredicate(patterns, nil)
+	if err != nil {
+		return func(interface{}) (bool, error) { return false, err }
+	}
+	return predicate
+}
+
+// buildPlainPredicate returns a Predicate which will evaluate a
+// machine, service, or unit against the given patterns, none of which
+// are filter expressions.
+func buildPlainPredicate(patterns []string) Predicate {
 
 	or := func(predicates ...closurePredicate) (bool, error) {
 		// Differentiate between a valid format that elimintated all
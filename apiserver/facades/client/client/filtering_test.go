@@ -57,3 +57,40 @@ func (s *filteringUnitTests) TestMatchSubnet(c *gc.C) {
 	c.Check(ok, jc.IsTrue)
 	c.Check(match, jc.IsTrue)
 }
+
+func (s *filteringUnitTests) TestParseFilterExpression(c *gc.C) {
+	for i, test := range []struct {
+		pattern string
+		isExpr  bool
+		err     string
+	}{
+		{pattern: "mysql/0"},
+		{pattern: "10.0.0.0/8"},
+		{pattern: "workload=blocked", isExpr: true},
+		{pattern: "agent!=idle", isExpr: true},
+		{pattern: "app~^db", isExpr: true},
+		{pattern: "machine.zone=us-east-1a", isExpr: true},
+		{pattern: "colour=red", err: `filter field "colour" in "colour=red" not valid`},
+		{pattern: "app~[", err: `invalid filter "app~\[": .*`},
+		{pattern: "app=[", err: `invalid filter "app=\[": .*`},
+	} {
+		c.Logf("test %d: %q", i, test.pattern)
+		expr, err := client.ParseFilterExpression(test.pattern)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(expr != nil, gc.Equals, test.isExpr)
+	}
+}
+
+func (s *filteringUnitTests) TestFilterExpressionMatches(c *gc.C) {
+	c.Check(client.FilterExpressionMatches(c, "workload=blocked", "blocked"), jc.IsTrue)
+	c.Check(client.FilterExpressionMatches(c, "workload=blocked", "active"), jc.IsFalse)
+	c.Check(client.FilterExpressionMatches(c, "agent!=idle", "idle"), jc.IsFalse)
+	c.Check(client.FilterExpressionMatches(c, "agent!=idle", "executing"), jc.IsTrue)
+	c.Check(client.FilterExpressionMatches(c, "app~^db", "db-primary"), jc.IsTrue)
+	c.Check(client.FilterExpressionMatches(c, "app~^db", "mydb"), jc.IsFalse)
+	c.Check(client.FilterExpressionMatches(c, "unit=mysql/*", "mysql/1"), jc.IsTrue)
+}
@@ -49,7 +49,8 @@ func (s byTime) Less(i, j int) bool {
 	return s[i].Since.Before(*s[j].Since)
 }
 
-// unitStatusHistory returns a list of status history entries for unit agents or workloads.
+// unitStatusHistory returns a list of status history entries for unit agents,
+// workloads or health.
 func (c *Client) unitStatusHistory(unitTag names.UnitTag, filter status.StatusHistoryFilter, kind status.HistoryKind) ([]params.DetailedStatus, error) {
 	unit, err := c.api.stateAccessor.Unit(unitTag.Id())
 	if err != nil {
@@ -71,6 +72,13 @@ func (c *Client) unitStatusHistory(unitTag names.UnitTag, filter status.StatusHi
 		}
 		statuses = append(statuses, agentStatusFromStatusInfo(agentStatuses, status.KindUnitAgent)...)
 	}
+	if kind == status.KindHealth {
+		healthStatuses, err := unit.HealthHistory().StatusHistory(filter)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		statuses = agentStatusFromStatusInfo(healthStatuses, status.KindHealth)
+	}
 
 	sort.Sort(byTime(statuses))
 	if kind == status.KindUnit && filter.Size > 0 {
@@ -137,7 +145,7 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 		kind := status.HistoryKind(request.Kind)
 		err = errors.NotValidf("%q requires a unit, got %T", kind, request.Tag)
 		switch kind {
-		case status.KindUnit, status.KindWorkload, status.KindUnitAgent:
+		case status.KindUnit, status.KindWorkload, status.KindUnitAgent, status.KindHealth:
 			var u names.UnitTag
 			if u, err = names.ParseUnitTag(request.Tag); err == nil {
 				hist, err = c.unitStatusHistory(u, filter, kind)
@@ -214,7 +222,10 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 	logger.Debugf("Offers: %v", context.offers)
 
 	if len(args.Patterns) > 0 {
-		predicate := BuildPredicateFor(args.Patterns)
+		predicate, err := buildPredicate(args.Patterns, machinesById(context.machines))
+		if err != nil {
+			return noStatus, errors.Trace(err)
+		}
 
 		// First, attempt to match machines. Any units on those
 		// machines are implicitly matched.
@@ -405,28 +416,55 @@ func fetchMachines(st Backend, machineIds set.Strings) (map[string][]*state.Mach
 	if err != nil {
 		return nil, err
 	}
-	// AllMachines gives us machines sorted by id.
+	machinesById := make(map[string]*state.Machine)
+	for _, m := range machines {
+		machinesById[m.Id()] = m
+	}
+	// AllMachines gives us machines sorted by id. A container that has
+	// been moved may sort before its host, so the top level host
+	// machines, which go directly into the machine map, are added
+	// first.
+	var containers []*state.Machine
 	for _, m := range machines {
 		if machineIds != nil && !machineIds.Contains(m.Id()) {
 			continue
 		}
-		parentId, ok := m.ParentId()
-		if !ok {
-			// Only top level host machines go directly into the machine map.
+		if _, ok := m.ParentId(); !ok {
 			v[m.Id()] = []*state.Machine{m}
 		} else {
-			topParentId := state.TopParentId(m.Id())
-			machines, ok := v[topParentId]
-			if !ok {
-				panic(fmt.Errorf("unexpected machine id %q", parentId))
-			}
-			machines = append(machines, m)
-			v[topParentId] = machines
+			containers = append(containers, m)
 		}
 	}
+	for _, m := range containers {
+		topParentId := topParentId(m, machinesById)
+		machines, ok := v[topParentId]
+		if !ok {
+			parentId, _ := m.ParentId()
+			panic(fmt.Errorf("unexpected machine id %q", parentId))
+		}
+		machines = append(machines, m)
+		v[topParentId] = machines
+	}
 	return v, nil
 }
 
+// topParentId returns the id of the top level machine hosting the given
+// machine, following the hosts recorded on containers that have been
+// moved rather than the parents named by their ids.
+func topParentId(m *state.Machine, machinesById map[string]*state.Machine) string {
+	for {
+		parentId, ok := m.ParentId()
+		if !ok {
+			return m.Id()
+		}
+		parent, ok := machinesById[parentId]
+		if !ok {
+			return state.TopParentId(parentId)
+		}
+		m = parent
+	}
+}
+
 // fetchNetworkInterfaces returns maps from machine id to ip.addresses, machine
 // id to a map of interface names from space names, and machine id to
 // linklayerdevices.
@@ -670,7 +708,8 @@ func (c *statusContext) processMachines() map[string]params.MachineStatus {
 		cache[id] = hostStatus
 
 		for _, machine := range machines[1:] {
-			parent, ok := cache[state.ParentId(machine.Id())]
+			parentId, _ := machine.ParentId()
+			parent, ok := cache[parentId]
 			if !ok {
 				logger.Errorf("programmer error, please file a bug, reference this whole log line: %q, %q", id, machine.Id())
 				continue
@@ -699,6 +738,7 @@ func (c *statusContext) makeMachineStatus(machine *state.Machine) (status params
 	status.Jobs = paramsJobsFromJobs(machine.Jobs())
 	status.WantsVote = machine.WantsVote()
 	status.HasVote = machine.HasVote()
+	status.LXDProfiles = machine.CharmProfiles()
 	sInfo, err := c.status.MachineInstance(machineID)
 	populateStatusFromStatusInfoAndErr(&status.InstanceStatus, sInfo, err)
 	// TODO: fetch all instance data for machines in one go.
@@ -1067,6 +1107,18 @@ func (context *statusContext) processUnit(unit *state.Unit, applicationCharm str
 	}
 
 	result.AgentStatus, result.WorkloadStatus = context.processUnitAndAgentStatus(unit)
+	health, err := context.status.UnitHealth(unit.Name())
+	if err == nil {
+		populateStatusFromStatusInfoAndErr(&result.HealthStatus, health, nil)
+	} else if !errors.IsNotFound(err) {
+		logger.Debugf("error fetching health status: %v", err)
+	}
+	container, err := context.status.UnitContainer(unit.Name())
+	if err == nil {
+		populateStatusFromStatusInfoAndErr(&result.ContainerStatus, container, nil)
+	} else if !errors.IsNotFound(err) {
+		logger.Debugf("error fetching container status: %v", err)
+	}
 
 	if subUnits := unit.SubordinateNames(); len(subUnits) > 0 {
 		result.Subordinates = make(map[string]params.UnitStatus)
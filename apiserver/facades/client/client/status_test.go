@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/instance"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -117,6 +118,50 @@ func (s *statusUnitTestSuite) TestProcessMachinesWithEmbeddedContainers(c *gc.C)
 	c.Check(mStatus.Containers, gc.HasLen, 1)
 }
 
+func (s *statusUnitTestSuite) TestProcessMachinesWithMovedContainer(c *gc.C) {
+	source := s.Factory.MakeMachine(c, &factory.MachineParams{InstanceId: instance.Id("0")})
+	target := s.Factory.MakeMachine(c, &factory.MachineParams{InstanceId: instance.Id("1")})
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, source.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.SetProvisioned("juju-0-lxd-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = container.StartMove(target, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.SetMoveSource(state.ContainerMoveSource{Operation: "op"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.SetMoveCopied()
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.CompleteMove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertContainerHostedBy(c, container.Id(), target.Id())
+
+	// The container is still shown once its original host is gone.
+	err = source.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = source.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertContainerHostedBy(c, container.Id(), target.Id())
+}
+
+func (s *statusUnitTestSuite) assertContainerHostedBy(c *gc.C, containerId, hostId string) {
+	client := s.APIState.Client()
+	status, err := client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	mStatus, ok := status.Machines[hostId]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(mStatus.Containers, gc.HasLen, 1)
+	_, ok = mStatus.Containers[containerId]
+	c.Check(ok, jc.IsTrue)
+}
+
 var testUnits = []struct {
 	unitName       string
 	setStatus      *state.MeterStatus
@@ -437,3 +482,39 @@ func (s *statusUpgradeUnitSuite) TestUpdateRevisions(c *gc.C) {
 	c.Assert(ok, gc.Equals, true)
 	c.Assert(serviceStatus.CanUpgradeTo, gc.Equals, "cs:quantal/mysql-23")
 }
+
+func (s *statusUnitTestSuite) TestFilterExpressions(c *gc.C) {
+	mysql := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:  "mysql",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	blocked := s.Factory.MakeUnit(c, &factory.UnitParams{Application: mysql})
+	err := blocked.SetStatus(status.StatusInfo{Status: status.Blocked, Message: "waiting for db"})
+	c.Assert(err, jc.ErrorIsNil)
+	active := s.Factory.MakeUnit(c, &factory.UnitParams{Application: mysql})
+	err = active.SetStatus(status.StatusInfo{Status: status.Active})
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:  "wordpress",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+
+	client := s.APIState.Client()
+	fullStatus, err := client.Status([]string{"app~^my", "workload=blocked"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fullStatus.Applications, gc.HasLen, 1)
+	units := fullStatus.Applications["mysql"].Units
+	c.Assert(units, gc.HasLen, 1)
+	_, ok := units[blocked.Name()]
+	c.Assert(ok, jc.IsTrue)
+
+	fullStatus, err = client.Status([]string{"workload!=blocked"})
+	c.Assert(err, jc.ErrorIsNil)
+	units = fullStatus.Applications["mysql"].Units
+	c.Assert(units, gc.HasLen, 1)
+	_, ok = units[active.Name()]
+	c.Assert(ok, jc.IsTrue)
+
+	_, err = client.Status([]string{"colour=red"})
+	c.Assert(err, gc.ErrorMatches, `filter field "colour" in "colour=red" not valid`)
+}
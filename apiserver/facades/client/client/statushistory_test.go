@@ -181,6 +181,33 @@ func (s *statusHistoryTestSuite) TestStatusHistoryAgentOnly(c *gc.C) {
 	checkStatusInfo(c, h.Results[0].History.Statuses, reverseStatusInfo(s.st.agentHistory))
 }
 
+func (s *statusHistoryTestSuite) TestStatusHistoryHealthOnly(c *gc.C) {
+	s.st.unitHistory = statusInfoWithDates([]status.StatusInfo{
+		{
+			Status:  status.Active,
+			Message: "running",
+		},
+	})
+	s.st.healthHistory = statusInfoWithDates([]status.StatusInfo{
+		{
+			Status: status.Healthy,
+		},
+		{
+			Status:  status.Unhealthy,
+			Message: `check "http" failed`,
+		},
+	})
+	h := s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:    "unit-unit-0",
+			Kind:   status.KindHealth.String(),
+			Filter: params.StatusHistoryFilter{Size: 10},
+		}}})
+	c.Assert(h.Results, gc.HasLen, 1)
+	c.Assert(h.Results[0].Error, gc.IsNil)
+	checkStatusInfo(c, h.Results[0].History.Statuses, reverseStatusInfo(s.st.healthHistory))
+}
+
 func (s *statusHistoryTestSuite) TestStatusHistoryCombined(c *gc.C) {
 	s.st.unitHistory = statusInfoWithDates([]status.StatusInfo{
 		{
@@ -222,8 +249,9 @@ func (s *statusHistoryTestSuite) TestStatusHistoryCombined(c *gc.C) {
 
 type mockState struct {
 	client.Backend
-	unitHistory  []status.StatusInfo
-	agentHistory []status.StatusInfo
+	unitHistory   []status.StatusInfo
+	agentHistory  []status.StatusInfo
+	healthHistory []status.StatusInfo
 }
 
 func (m *mockState) ModelUUID() string {
@@ -245,12 +273,14 @@ func (m *mockState) Unit(name string) (client.Unit, error) {
 	return &mockUnit{
 		status: m.unitHistory,
 		agent:  &mockUnitAgent{m.agentHistory},
+		health: m.healthHistory,
 	}, nil
 }
 
 type mockUnit struct {
 	status statuses
 	agent  *mockUnitAgent
+	health statuses
 	client.Unit
 }
 
@@ -262,6 +292,10 @@ func (m *mockUnit) AgentHistory() status.StatusHistoryGetter {
 	return m.agent
 }
 
+func (m *mockUnit) HealthHistory() status.StatusHistoryGetter {
+	return m.health
+}
+
 type mockUnitAgent struct {
 	statuses
 }
@@ -6,6 +6,7 @@ package firewallrules
 import (
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 )
 
@@ -16,6 +17,7 @@ type Backend interface {
 	ModelTag() names.ModelTag
 	SaveFirewallRule(state.FirewallRule) error
 	ListFirewallRules() ([]*state.FirewallRule, error)
+	SetApplicationEgressRules(application string, rules []network.EgressRule) error
 }
 
 // BlockChecker defines the block-checking functionality required by
@@ -53,3 +55,11 @@ func (s stateShim) ListFirewallRules() ([]*state.FirewallRule, error) {
 	api := state.NewFirewallRules(s.State)
 	return api.AllRules()
 }
+
+func (s stateShim) SetApplicationEgressRules(application string, rules []network.EgressRule) error {
+	app, err := s.State.Application(application)
+	if err != nil {
+		return err
+	}
+	return app.SetEgressRules(rules)
+}
@@ -9,15 +9,17 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/firewall"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.firewallrules")
 
-// API provides the firewallrules facade APIs for v1.
+// API provides the firewallrules facade APIs for v2.
 type API struct {
 	backend    Backend
 	authorizer facade.Authorizer
@@ -86,10 +88,46 @@ func (api *API) SetFirewallRules(args params.FirewallRuleArgs) (params.ErrorResu
 	results := make([]params.ErrorResult, len(args.Args))
 	for i, arg := range args.Args {
 		logger.Debugf("saving firewall rule %+v", arg)
-		err := api.backend.SaveFirewallRule(state.FirewallRule{
+		rule := state.FirewallRule{
 			WellKnownService: state.WellKnownServiceType(arg.KnownService),
+			Application:      arg.Application,
 			WhitelistCIDRs:   arg.WhitelistCIDRS,
-		})
+		}
+		if arg.PortRange != nil {
+			rule.PortRange = arg.PortRange.NetworkPortRange()
+		}
+		err := api.backend.SaveFirewallRule(rule)
+		results[i].Error = common.ServerError(err)
+	}
+	errResults.Results = results
+	return errResults, nil
+}
+
+// SetEgressRules replaces the egress rules of the specified
+// applications. An empty set of rules removes any egress restrictions
+// for an application.
+func (api *API) SetEgressRules(args params.ApplicationEgressRulesArgs) (params.ErrorResults, error) {
+	var errResults params.ErrorResults
+	if err := api.checkAdmin(); err != nil {
+		return errResults, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errResults, errors.Trace(err)
+	}
+
+	results := make([]params.ErrorResult, len(args.Args))
+	for i, arg := range args.Args {
+		tag, err := names.ParseApplicationTag(arg.ApplicationTag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		rules := make([]network.EgressRule, len(arg.Rules))
+		for j, rule := range arg.Rules {
+			rules[j] = rule.NetworkEgressRule()
+		}
+		logger.Debugf("setting egress rules for %v: %v", tag.Id(), rules)
+		err = api.backend.SetApplicationEgressRules(tag.Id(), rules)
 		results[i].Error = common.ServerError(err)
 	}
 	errResults.Results = results
@@ -108,10 +146,7 @@ func (api *API) ListFirewallRules() (params.ListFirewallRulesResults, error) {
 	}
 	listResults.Rules = make([]params.FirewallRule, len(rules))
 	for i, r := range rules {
-		listResults.Rules[i] = params.FirewallRule{
-			KnownService:   params.KnownServiceValue(r.WellKnownService),
-			WhitelistCIDRS: r.WhitelistCIDRs,
-		}
+		listResults.Rules[i] = firewall.FirewallRuleToParams(r)
 	}
 	return listResults, nil
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/firewallrules"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -47,8 +48,9 @@ func (s *FirewallRulesSuite) SetUpTest(c *gc.C) {
 		Tag: names.NewUserTag("admin"),
 	}
 	s.backend = mockBackend{
-		modelUUID: coretesting.ModelTag.Id(),
-		rules:     make(map[string]state.FirewallRule),
+		modelUUID:   coretesting.ModelTag.Id(),
+		rules:       make(map[string]state.FirewallRule),
+		egressRules: make(map[string][]network.EgressRule),
 	}
 	s.blockChecker = mockBlockChecker{}
 	api, err := firewallrules.NewAPI(
@@ -80,6 +82,25 @@ func (s *FirewallRulesSuite) TestSetFirewallRules(c *gc.C) {
 	})
 }
 
+func (s *FirewallRulesSuite) TestSetApplicationFirewallRule(c *gc.C) {
+	result, err := s.api.SetFirewallRules(params.FirewallRuleArgs{
+		Args: []params.FirewallRule{{
+			KnownService:   params.ApplicationRule,
+			Application:    "mysql",
+			PortRange:      &params.PortRange{FromPort: 3306, ToPort: 3306, Protocol: "tcp"},
+			WhitelistCIDRS: []string{"10.0.0.0/8"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{[]params.ErrorResult{{Error: nil}}})
+	c.Assert(s.backend.rules["application"], jc.DeepEquals, state.FirewallRule{
+		WellKnownService: state.ApplicationRule,
+		Application:      "mysql",
+		PortRange:        network.MustParsePortRange("3306/tcp"),
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+}
+
 func (s *FirewallRulesSuite) TestSetFirewallRulesPermission(c *gc.C) {
 	s.setAPIUser(c, names.NewUserTag("mary"))
 	_, err := s.api.SetFirewallRules(params.FirewallRuleArgs{
@@ -105,6 +126,50 @@ func (s *FirewallRulesSuite) TestSetFirewallRulesBlocked(c *gc.C) {
 	c.Assert(s.backend.rules, gc.HasLen, 0)
 }
 
+func (s *FirewallRulesSuite) TestSetEgressRules(c *gc.C) {
+	// The first error is consumed by ModelTag.
+	s.backend.SetErrors(nil, nil, errors.NotFoundf(`application "foo"`))
+	result, err := s.api.SetEgressRules(params.ApplicationEgressRulesArgs{
+		Args: []params.ApplicationEgressRules{{
+			ApplicationTag: "application-mysql",
+			Rules: []params.EgressRule{{
+				PortRange:        params.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"},
+				DestinationCIDRs: []string{"10.0.0.0/8"},
+			}},
+		}, {
+			ApplicationTag: "application-foo",
+		}, {
+			ApplicationTag: "machine-0",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `application "foo" not found`)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid application tag`)
+	c.Assert(s.backend.egressRules, jc.DeepEquals, map[string][]network.EgressRule{
+		"mysql": {network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8")},
+	})
+}
+
+func (s *FirewallRulesSuite) TestSetEgressRulesPermission(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("mary"))
+	_, err := s.api.SetEgressRules(params.ApplicationEgressRulesArgs{
+		Args: []params.ApplicationEgressRules{{ApplicationTag: "application-mysql"}},
+	})
+	c.Assert(err, gc.ErrorMatches, ".*permission denied.*")
+	c.Assert(s.backend.egressRules, gc.HasLen, 0)
+}
+
+func (s *FirewallRulesSuite) TestSetEgressRulesBlocked(c *gc.C) {
+	s.blockChecker.SetErrors(errors.New("blocked"))
+	_, err := s.api.SetEgressRules(params.ApplicationEgressRulesArgs{
+		Args: []params.ApplicationEgressRules{{ApplicationTag: "application-mysql"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "blocked")
+	c.Assert(s.backend.egressRules, gc.HasLen, 0)
+}
+
 func (s *FirewallRulesSuite) TestListFirewallRules(c *gc.C) {
 	result, err := s.api.ListFirewallRules()
 	c.Assert(err, jc.ErrorIsNil)
@@ -8,6 +8,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/facades/client/firewallrules"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 )
 
@@ -15,8 +16,9 @@ type mockBackend struct {
 	jtesting.Stub
 	firewallrules.Backend
 
-	modelUUID string
-	rules     map[string]state.FirewallRule
+	modelUUID   string
+	rules       map[string]state.FirewallRule
+	egressRules map[string][]network.EgressRule
 }
 
 func (m *mockBackend) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
@@ -47,6 +49,15 @@ func (m *mockBackend) ListFirewallRules() ([]*state.FirewallRule, error) {
 	}, nil
 }
 
+func (m *mockBackend) SetApplicationEgressRules(application string, rules []network.EgressRule) error {
+	m.MethodCall(m, "SetApplicationEgressRules", application, rules)
+	if err := m.NextErr(); err != nil {
+		return err
+	}
+	m.egressRules[application] = rules
+	return nil
+}
+
 type mockBlockChecker struct {
 	jtesting.Stub
 }
@@ -15,9 +15,12 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/permission"
+	providercommon "github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.highavailability")
@@ -25,6 +28,7 @@ var logger = loggo.GetLogger("juju.apiserver.highavailability")
 // HighAvailability defines the methods on the highavailability API end point.
 type HighAvailability interface {
 	EnableHA(args params.ControllersSpecs) (params.ControllersChangeResults, error)
+	RemoveControllerMachine(args params.Entities) (params.ControllersChangeResults, error)
 }
 
 // HighAvailabilityAPI implements the HighAvailability interface and is the concrete
@@ -77,6 +81,54 @@ func (api *HighAvailabilityAPI) EnableHA(args params.ControllersSpecs) (params.C
 	return results, nil
 }
 
+// RemoveControllerMachine removes the specified controller machines.
+// Each voting controller removed is replaced, by promoting an existing
+// controller or adding a new machine, so that the number of voting
+// controllers is maintained. The removed machines are decommissioned
+// once they no longer take part in the replica set.
+func (api *HighAvailabilityAPI) RemoveControllerMachine(args params.Entities) (params.ControllersChangeResults, error) {
+	results := params.ControllersChangeResults{}
+
+	admin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.state.ControllerTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return results, errors.Trace(err)
+	}
+	if !admin {
+		return results, common.ServerError(common.ErrPerm)
+	}
+
+	results.Results = make([]params.ControllersChangeResult, len(args.Entities))
+	for i, entity := range args.Entities {
+		result, err := removeControllerMachine(api.state, entity.Tag)
+		results.Results[i].Result = result
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func removeControllerMachine(st *state.State, tag string) (params.ControllersChanges, error) {
+	if !st.IsController() {
+		return params.ControllersChanges{}, errors.New("unsupported with hosted models")
+	}
+	blockChecker := common.NewBlockChecker(st)
+	if err := blockChecker.RemoveAllowed(); err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
+	}
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
+	}
+	series, cons, zones, err := newControllerParams(st, params.ControllersSpec{})
+	if err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
+	}
+	changes, err := st.RemoveControllerMachine(machineTag.Id(), cons, series, zones)
+	if err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
+	}
+	return controllersChanges(changes), nil
+}
+
 // Convert machine ids to tags.
 func machineIdsToTags(ids ...string) []string {
 	var result []string
@@ -108,11 +160,26 @@ func enableHASingle(st *state.State, spec params.ControllersSpec) (params.Contro
 		return params.ControllersChanges{}, errors.Trace(err)
 	}
 
+	series, cons, zones, err := newControllerParams(st, spec)
+	if err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
+	}
+	changes, err := st.EnableHAInZones(spec.NumControllers, cons, series, spec.Placement, zones)
+	if err != nil {
+		return params.ControllersChanges{}, err
+	}
+	return controllersChanges(changes), nil
+}
+
+// newControllerParams returns the series, constraints and availability
+// zones for any new controller machines. The series and constraints
+// default to those of the existing controllers if not specified.
+func newControllerParams(st *state.State, spec params.ControllersSpec) (string, constraints.Value, []string, error) {
 	series := spec.Series
 	if series == "" {
 		ssi, err := st.ControllerInfo()
 		if err != nil {
-			return params.ControllersChanges{}, err
+			return "", constraints.Value{}, nil, err
 		}
 
 		// We should always have at least one voting machine
@@ -121,11 +188,11 @@ func enableHASingle(st *state.State, spec params.ControllersSpec) (params.Contro
 		// the first one, then they'll stay in sync.
 		if len(ssi.VotingMachineIds) == 0 {
 			// Better than a panic()?
-			return params.ControllersChanges{}, errors.Errorf("internal error, failed to find any voting machines")
+			return "", constraints.Value{}, nil, errors.Errorf("internal error, failed to find any voting machines")
 		}
 		templateMachine, err := st.Machine(ssi.VotingMachineIds[0])
 		if err != nil {
-			return params.ControllersChanges{}, err
+			return "", constraints.Value{}, nil, err
 		}
 		series = templateMachine.Series()
 	}
@@ -134,7 +201,7 @@ func enableHASingle(st *state.State, spec params.ControllersSpec) (params.Contro
 		// a running controller.
 		controllerInfo, err := st.ControllerInfo()
 		if err != nil {
-			return params.ControllersChanges{}, err
+			return "", constraints.Value{}, nil, err
 		}
 		// We'll sort the controller ids to find the smallest.
 		// This will typically give the initial bootstrap machine.
@@ -156,19 +223,54 @@ func enableHASingle(st *state.State, spec params.ControllersSpec) (params.Contro
 		controllerId := controllerIds[0]
 		controller, err := st.Machine(strconv.Itoa(controllerId))
 		if err != nil {
-			return params.ControllersChanges{}, errors.Annotatef(err, "reading controller id %v", controllerId)
+			return "", constraints.Value{}, nil, errors.Annotatef(err, "reading controller id %v", controllerId)
 		}
 		spec.Constraints, err = controller.Constraints()
 		if err != nil {
-			return params.ControllersChanges{}, errors.Annotatef(err, "reading constraints for controller id %v", controllerId)
+			return "", constraints.Value{}, nil, errors.Annotatef(err, "reading constraints for controller id %v", controllerId)
 		}
 	}
 
-	changes, err := st.EnableHA(spec.NumControllers, spec.Constraints, series, spec.Placement)
+	zones, err := controllerZones(st)
 	if err != nil {
-		return params.ControllersChanges{}, err
+		// Not knowing the zones doesn't prevent us from adding
+		// controllers; the provisioner will still try to spread
+		// them across zones as they are started.
+		logger.Warningf("cannot spread controllers across availability zones: %v", err)
 	}
-	return controllersChanges(changes), nil
+	return series, spec.Constraints, zones, nil
+}
+
+// controllerZones returns the names of the available zones in which
+// controller machines may be started, or nil if the controller's cloud
+// does not support availability zones.
+func controllerZones(st *state.State) ([]string, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	env, err := environs.GetEnviron(stateenvirons.EnvironConfigGetter{st, model}, environs.New)
+	if err != nil {
+		return nil, errors.Annotate(err, "opening environment")
+	}
+	zonedEnv, ok := env.(providercommon.ZonedEnviron)
+	if !ok {
+		return nil, nil
+	}
+	zones, err := zonedEnv.AvailabilityZones()
+	if errors.IsNotImplemented(err) || errors.IsNotSupported(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "getting availability zones")
+	}
+	var names []string
+	for _, zone := range zones {
+		if zone.Available() {
+			names = append(names, zone.Name())
+		}
+	}
+	return names, nil
 }
 
 // StopHAReplicationForUpgrade will prompt the HA cluster to enter upgrade
@@ -237,7 +237,8 @@ func (s *clientSuite) TestEnableHAPlacement(c *gc.C) {
 		{},
 		constraints.MustParse("mem=4G tags=foobar"),
 	}
-	expectedPlacement := []string{"", "valid", ""}
+	// The dummy provider's first available zone is "zone1".
+	expectedPlacement := []string{"", "valid", "zone=zone1"}
 	for i, m := range machines {
 		cons, err := m.Constraints()
 		c.Assert(err, jc.ErrorIsNil)
@@ -246,6 +247,21 @@ func (s *clientSuite) TestEnableHAPlacement(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestEnableHASpreadsAcrossZones(c *gc.C) {
+	enableHAResult, err := s.enableHA(c, 3, emptyCons, defaultSeries, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enableHAResult.Added, gc.DeepEquals, []string{"machine-1", "machine-2"})
+
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 3)
+	// The dummy provider's available zones are "zone1" and "zone4".
+	expectedPlacement := []string{"", "zone=zone1", "zone=zone4"}
+	for i, m := range machines {
+		c.Check(m.Placement(), gc.Equals, expectedPlacement[i])
+	}
+}
+
 func (s *clientSuite) TestEnableHAPlacementTo(c *gc.C) {
 	machine1Cons := constraints.MustParse("mem=8G")
 	_, err := s.State.AddMachines(state.MachineTemplate{
@@ -352,8 +368,22 @@ func (s *clientSuite) TestEnableHAErrors(c *gc.C) {
 	c.Assert(enableHAResult.Removed, gc.HasLen, 0)
 	c.Assert(enableHAResult.Converted, gc.HasLen, 0)
 
-	_, err = s.enableHA(c, 1, emptyCons, defaultSeries, nil)
-	c.Assert(err, gc.ErrorMatches, "failed to create new controller machines: cannot reduce controller count")
+	_, err = s.enableHA(c, 2, emptyCons, defaultSeries, nil)
+	c.Assert(err, gc.ErrorMatches, "number of controllers must be odd and non-negative")
+}
+
+func (s *clientSuite) TestEnableHAReducesControllerCount(c *gc.C) {
+	enableHAResult, err := s.enableHA(c, 3, emptyCons, defaultSeries, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enableHAResult.Added, gc.DeepEquals, []string{"machine-1", "machine-2"})
+	s.setAgentPresence(c, "1")
+	s.setAgentPresence(c, "2")
+
+	enableHAResult, err = s.enableHA(c, 1, emptyCons, defaultSeries, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enableHAResult.Maintained, gc.DeepEquals, []string{"machine-0"})
+	c.Assert(enableHAResult.Demoted, gc.DeepEquals, []string{"machine-2", "machine-1"})
+	c.Assert(enableHAResult.Added, gc.HasLen, 0)
 }
 
 func (s *clientSuite) TestEnableHAHostedEnvErrors(c *gc.C) {
@@ -396,3 +426,41 @@ func (s *clientSuite) TestEnableHANoSpecs(c *gc.C) {
 	c.Check(err, jc.ErrorIsNil)
 	c.Check(results.Results, gc.HasLen, 0)
 }
+
+func (s *clientSuite) TestRemoveControllerMachine(c *gc.C) {
+	_, err := s.enableHA(c, 3, emptyCons, defaultSeries, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setAgentPresence(c, "1")
+	s.setAgentPresence(c, "2")
+
+	results, err := s.haServer.RemoveControllerMachine(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}, {Tag: "unit-foo-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	result := results.Results[0].Result
+	c.Check(result.Maintained, gc.DeepEquals, []string{"machine-0", "machine-2"})
+	c.Check(result.Added, gc.DeepEquals, []string{"machine-3"})
+	c.Check(result.Demoted, gc.DeepEquals, []string{"machine-1"})
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `"unit-foo-0" is not a valid machine tag`)
+
+	m1, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m1.IsDecommissioning(), jc.IsTrue)
+	info, err := s.State.ControllerInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.VotingMachineIds, jc.SameContents, []string{"0", "2", "3"})
+}
+
+func (s *clientSuite) TestRemoveControllerMachineBlocked(c *gc.C) {
+	_, err := s.enableHA(c, 3, emptyCons, defaultSeries, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.BlockRemoveObject(c, "TestRemoveControllerMachineBlocked")
+
+	results, err := s.haServer.RemoveControllerMachine(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertBlocked(c, results.Results[0].Error, "TestRemoveControllerMachineBlocked")
+}
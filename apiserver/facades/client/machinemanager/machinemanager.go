@@ -57,6 +57,19 @@ func NewFacadeV4(ctx facade.Context) (*MachineManagerAPIV4, error) {
 	return &MachineManagerAPIV4{machineManagerAPI}, nil
 }
 
+type MachineManagerAPIV5 struct {
+	*MachineManagerAPIV4
+}
+
+// NewFacadeV5 creates a new server-side MachineManager API facade.
+func NewFacadeV5(ctx facade.Context) (*MachineManagerAPIV5, error) {
+	machineManagerAPIV4, err := NewFacadeV4(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV5{machineManagerAPIV4}, nil
+}
+
 // NewMachineManagerAPI creates a new server-side MachineManager API facade.
 func NewMachineManagerAPI(backend Backend, pool Pool, auth facade.Authorizer) (*MachineManagerAPI, error) {
 	if !auth.AuthClient() {
@@ -330,3 +343,35 @@ func (mm *MachineManagerAPIV4) updateOneMachineSeries(arg params.UpdateSeriesArg
 	}
 	return machine.UpdateMachineSeries(arg.Series, arg.Force)
 }
+
+// MoveMachine requests that each of the given containers be moved to
+// another host machine. The hosts carry out the move.
+func (mm *MachineManagerAPIV5) MoveMachine(args params.MoveMachineArgs) (params.ErrorResults, error) {
+	if err := mm.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := mm.moveOneMachine(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPIV5) moveOneMachine(arg params.MoveMachineArg) error {
+	machineTag, err := names.ParseMachineTag(arg.MachineTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	hostTag, err := names.ParseMachineTag(arg.HostTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("moving machine %v to machine %v (live: %v)", machineTag.Id(), hostTag.Id(), arg.Live)
+	return mm.st.MoveMachine(machineTag.Id(), hostTag.Id(), arg.Live)
+}
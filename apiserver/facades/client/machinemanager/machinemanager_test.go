@@ -313,6 +313,43 @@ func (s *MachineManagerSuite) TestUpdateMachineSeriesPermissionDenied(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
+func (s *MachineManagerSuite) TestMoveMachine(c *gc.C) {
+	apiV5 := machinemanager.MachineManagerAPIV5{&machinemanager.MachineManagerAPIV4{s.api}}
+	s.st.moveErr = errors.New("boom")
+	results, err := apiV5.MoveMachine(params.MoveMachineArgs{
+		Args: []params.MoveMachineArg{{
+			MachineTag: "machine-0-lxd-1",
+			HostTag:    "machine-2",
+			Live:       true,
+		}, {
+			MachineTag: "unit-mysql-0",
+			HostTag:    "machine-2",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: &params.Error{Message: "boom"}},
+			{Error: &params.Error{Message: `"unit-mysql-0" is not a valid machine tag`}},
+		},
+	})
+	c.Assert(s.st.moves, jc.DeepEquals, []moveCall{{"0/lxd/1", "2", true}})
+}
+
+func (s *MachineManagerSuite) TestMoveMachineBlockedChanges(c *gc.C) {
+	apiV5 := machinemanager.MachineManagerAPIV5{&machinemanager.MachineManagerAPIV4{s.api}}
+	s.st.blockMsg = "TestMoveMachineBlockedChanges"
+	s.st.block = state.ChangeBlock
+	_, err := apiV5.MoveMachine(params.MoveMachineArgs{
+		Args: []params.MoveMachineArg{{
+			MachineTag: "machine-0-lxd-1",
+			HostTag:    "machine-2",
+		}},
+	})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue, gc.Commentf("error: %#v", err))
+	c.Assert(s.st.moves, gc.HasLen, 0)
+}
+
 type mockState struct {
 	machinemanager.Backend
 	calls            int
@@ -321,6 +358,19 @@ type mockState struct {
 	err              error
 	blockMsg         string
 	block            state.BlockType
+	moves            []moveCall
+	moveErr          error
+}
+
+type moveCall struct {
+	machineId string
+	hostId    string
+	live      bool
+}
+
+func (st *mockState) MoveMachine(machineId, hostId string, live bool) error {
+	st.moves = append(st.moves, moveCall{machineId, hostId, live})
+	return st.moveErr
 }
 
 func (st *mockState) AddOneMachine(template state.MachineTemplate) (*state.Machine, error) {
@@ -25,6 +25,7 @@ type Backend interface {
 	AddOneMachine(template state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
+	MoveMachine(machineId, hostId string, live bool) error
 }
 
 type Pool interface {
@@ -62,6 +63,20 @@ func (s stateShim) Machine(name string) (Machine, error) {
 	return machineShim{m}, nil
 }
 
+// MoveMachine starts moving the container with the given id to the
+// host machine with the given id.
+func (s stateShim) MoveMachine(machineId, hostId string, live bool) error {
+	m, err := s.State.Machine(machineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	host, err := s.State.Machine(hostId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return m.StartMove(host, live)
+}
+
 func (s stateShim) Model() (Model, error) {
 	return s.State.Model()
 }
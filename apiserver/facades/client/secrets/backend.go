@@ -0,0 +1,53 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets
+
+import (
+	"time"
+
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the secrets
+// facade. For details on the methods, see the methods on state.State
+// with the same names.
+type Backend interface {
+	ModelTag() names.ModelTag
+	AllSecrets() ([]Secret, error)
+}
+
+// Secret defines the secret metadata required by the secrets facade.
+// This is implemented by *state.Secret.
+type Secret interface {
+	Id() string
+	Owner() string
+	Label() string
+	Revision() int
+	CreateTime() time.Time
+	UpdateTime() time.Time
+	Grants() []state.SecretGrant
+}
+
+type stateShim struct {
+	*state.State
+}
+
+// NewStateBackend converts a state.State into a Backend.
+func NewStateBackend(st *state.State) Backend {
+	return stateShim{st}
+}
+
+func (s stateShim) AllSecrets() ([]Secret, error) {
+	secrets, err := s.State.AllSecrets()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Secret, len(secrets))
+	for i, secret := range secrets {
+		result[i] = secret
+	}
+	return result, nil
+}
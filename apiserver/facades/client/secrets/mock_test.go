@@ -0,0 +1,53 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"time"
+
+	jtesting "github.com/juju/testing"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/secrets"
+	"github.com/juju/juju/state"
+)
+
+type mockBackend struct {
+	jtesting.Stub
+
+	modelUUID string
+	secrets   []secrets.Secret
+}
+
+func (m *mockBackend) ModelTag() names.ModelTag {
+	m.MethodCall(m, "ModelTag")
+	m.PopNoErr()
+	return names.NewModelTag(m.modelUUID)
+}
+
+func (m *mockBackend) AllSecrets() ([]secrets.Secret, error) {
+	m.MethodCall(m, "AllSecrets")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.secrets, nil
+}
+
+type mockSecret struct {
+	id       string
+	owner    string
+	label    string
+	revision int
+	created  time.Time
+	updated  time.Time
+	grants   []state.SecretGrant
+}
+
+func (s *mockSecret) Id() string                  { return s.id }
+func (s *mockSecret) Owner() string               { return s.owner }
+func (s *mockSecret) Label() string               { return s.label }
+func (s *mockSecret) Revision() int               { return s.revision }
+func (s *mockSecret) CreateTime() time.Time       { return s.created }
+func (s *mockSecret) UpdateTime() time.Time       { return s.updated }
+func (s *mockSecret) Grants() []state.SecretGrant { return s.grants }
@@ -0,0 +1,80 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secrets provides the client facade for inspecting the
+// secrets created by charms. Secret values are never exposed to
+// clients; only their metadata.
+package secrets
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+)
+
+// SecretsAPI provides the Secrets API facade for version 1.
+type SecretsAPI struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewSecretsAPI provides the signature required for facade registration.
+func NewSecretsAPI(ctx facade.Context) (*SecretsAPI, error) {
+	return NewAPI(NewStateBackend(ctx.State()), ctx.Auth())
+}
+
+// NewAPI returns a new secrets API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*SecretsAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &SecretsAPI{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *SecretsAPI) checkCanRead() error {
+	canRead, err := api.authorizer.HasPermission(permission.ReadAccess, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !canRead {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// ListSecrets returns the metadata of all the secrets in the model.
+func (api *SecretsAPI) ListSecrets() (params.ListSecretResults, error) {
+	var result params.ListSecretResults
+	if err := api.checkCanRead(); err != nil {
+		return result, errors.Trace(err)
+	}
+	secrets, err := api.backend.AllSecrets()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Results = make([]params.SecretMetadata, len(secrets))
+	for i, secret := range secrets {
+		metadata := params.SecretMetadata{
+			Id:         secret.Id(),
+			Owner:      secret.Owner(),
+			Label:      secret.Label(),
+			Revision:   secret.Revision(),
+			CreateTime: secret.CreateTime(),
+			UpdateTime: secret.UpdateTime(),
+		}
+		for _, grant := range secret.Grants() {
+			metadata.Grants = append(metadata.Grants, params.SecretGrant{
+				Relation: grant.Relation,
+				Unit:     grant.Unit,
+			})
+		}
+		result.Results[i] = metadata
+	}
+	return result, nil
+}
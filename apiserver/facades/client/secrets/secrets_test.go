@@ -0,0 +1,115 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/secrets"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SecretsSuite struct {
+	testing.IsolationSuite
+
+	backend    mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+	api        *secrets.SecretsAPI
+}
+
+var _ = gc.Suite(&SecretsSuite{})
+
+func (s *SecretsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = mockBackend{
+		modelUUID: coretesting.ModelTag.Id(),
+	}
+	s.setAPIUser(c, names.NewUserTag("admin"))
+}
+
+func (s *SecretsSuite) setAPIUser(c *gc.C, user names.UserTag) {
+	s.authorizer.Tag = user
+	api, err := secrets.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *SecretsSuite) TestNewAPINonClient(c *gc.C) {
+	s.authorizer.Tag = names.NewUnitTag("mysql/0")
+	_, err := secrets.NewAPI(&s.backend, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *SecretsSuite) TestListSecrets(c *gc.C) {
+	created := time.Date(2017, 11, 1, 12, 0, 0, 0, time.UTC)
+	updated := created.Add(time.Hour)
+	s.backend.secrets = []secrets.Secret{
+		&mockSecret{
+			id:       "secret-0",
+			owner:    "mysql",
+			label:    "admin",
+			revision: 2,
+			created:  created,
+			updated:  updated,
+			grants: []state.SecretGrant{
+				{Relation: "wordpress:db mysql:server", Unit: "wordpress/1"},
+			},
+		},
+		&mockSecret{
+			id:       "secret-1",
+			owner:    "wordpress",
+			revision: 1,
+			created:  created,
+			updated:  created,
+		},
+	}
+
+	result, err := s.api.ListSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ListSecretResults{
+		Results: []params.SecretMetadata{{
+			Id:         "secret-0",
+			Owner:      "mysql",
+			Label:      "admin",
+			Revision:   2,
+			CreateTime: created,
+			UpdateTime: updated,
+			Grants: []params.SecretGrant{
+				{Relation: "wordpress:db mysql:server", Unit: "wordpress/1"},
+			},
+		}, {
+			Id:         "secret-1",
+			Owner:      "wordpress",
+			Revision:   1,
+			CreateTime: created,
+			UpdateTime: created,
+		}},
+	})
+	s.backend.CheckCallNames(c, "ModelTag", "AllSecrets")
+}
+
+func (s *SecretsSuite) TestListSecretsError(c *gc.C) {
+	s.backend.SetErrors(nil, errors.New("boom"))
+	_, err := s.api.ListSecrets()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *SecretsSuite) TestListSecretsPermission(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("mary"))
+	_, err := s.api.ListSecrets()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ModelTag")
+}
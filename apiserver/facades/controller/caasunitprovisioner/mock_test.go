@@ -0,0 +1,197 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/apiserver/facades/controller/caasunitprovisioner"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+)
+
+type mockState struct {
+	testing.Stub
+	caasunitprovisioner.CAASUnitProvisionerState
+	application         mockApplication
+	unit                mockUnit
+	applicationsWatcher *mockStringsWatcher
+}
+
+func (st *mockState) WatchApplications() state.StringsWatcher {
+	st.MethodCall(st, "WatchApplications")
+	return st.applicationsWatcher
+}
+
+func (st *mockState) Application(name string) (caasunitprovisioner.Application, error) {
+	st.MethodCall(st, "Application", name)
+	if name != "gitlab" {
+		return nil, errors.NotFoundf("application %v", name)
+	}
+	return &st.application, nil
+}
+
+func (st *mockState) Unit(name string) (caasunitprovisioner.Unit, error) {
+	st.MethodCall(st, "Unit", name)
+	if name != "gitlab/0" {
+		return nil, errors.NotFoundf("unit %v", name)
+	}
+	return &st.unit, nil
+}
+
+func (st *mockState) FindEntity(tag names.Tag) (state.Entity, error) {
+	st.MethodCall(st, "FindEntity", tag)
+	switch tag {
+	case names.NewApplicationTag("gitlab"):
+		return &st.application, nil
+	case names.NewUnitTag("gitlab/0"):
+		return &st.unit, nil
+	}
+	return nil, errors.NotFoundf("%s", names.ReadableString(tag))
+}
+
+type mockApplication struct {
+	testing.Stub
+	life         state.Life
+	podSpec      string
+	meta         *charm.Meta
+	storage      map[string]state.StorageConstraints
+	unitsWatcher *mockStringsWatcher
+	specWatcher  *mockNotifyWatcher
+}
+
+func (a *mockApplication) Tag() names.Tag {
+	return names.NewApplicationTag("gitlab")
+}
+
+func (a *mockApplication) Life() state.Life {
+	a.MethodCall(a, "Life")
+	return a.life
+}
+
+func (a *mockApplication) Charm() (caasunitprovisioner.Charm, error) {
+	a.MethodCall(a, "Charm")
+	if err := a.NextErr(); err != nil {
+		return nil, err
+	}
+	return &mockCharm{meta: a.meta}, nil
+}
+
+func (a *mockApplication) PodSpec() (string, error) {
+	a.MethodCall(a, "PodSpec")
+	if err := a.NextErr(); err != nil {
+		return "", err
+	}
+	return a.podSpec, nil
+}
+
+func (a *mockApplication) StorageConstraints() (map[string]state.StorageConstraints, error) {
+	a.MethodCall(a, "StorageConstraints")
+	if err := a.NextErr(); err != nil {
+		return nil, err
+	}
+	return a.storage, nil
+}
+
+func (a *mockApplication) WatchPodSpec() state.NotifyWatcher {
+	a.MethodCall(a, "WatchPodSpec")
+	return a.specWatcher
+}
+
+func (a *mockApplication) WatchUnits() state.StringsWatcher {
+	a.MethodCall(a, "WatchUnits")
+	return a.unitsWatcher
+}
+
+type mockCharm struct {
+	meta *charm.Meta
+}
+
+func (ch *mockCharm) Meta() *charm.Meta {
+	return ch.meta
+}
+
+type mockUnit struct {
+	testing.Stub
+	life state.Life
+}
+
+func (u *mockUnit) Tag() names.Tag {
+	return names.NewUnitTag("gitlab/0")
+}
+
+func (u *mockUnit) Life() state.Life {
+	u.MethodCall(u, "Life")
+	return u.life
+}
+
+func (u *mockUnit) SetContainerStatus(info status.StatusInfo) error {
+	u.MethodCall(u, "SetContainerStatus", info)
+	return u.NextErr()
+}
+
+func (u *mockUnit) SetContainerAddress(address string) error {
+	u.MethodCall(u, "SetContainerAddress", address)
+	return u.NextErr()
+}
+
+type mockWatcher struct {
+	testing.Stub
+	tomb.Tomb
+}
+
+func (w *mockWatcher) doneWhenDying() {
+	<-w.Tomb.Dying()
+	w.Tomb.Done()
+}
+
+func (w *mockWatcher) Kill() {
+	w.MethodCall(w, "Kill")
+	w.Tomb.Kill(nil)
+}
+
+func (w *mockWatcher) Stop() error {
+	w.MethodCall(w, "Stop")
+	if err := w.NextErr(); err != nil {
+		return err
+	}
+	w.Tomb.Kill(nil)
+	return w.Tomb.Wait()
+}
+
+type mockStringsWatcher struct {
+	mockWatcher
+	changes chan []string
+}
+
+func newMockStringsWatcher() *mockStringsWatcher {
+	w := &mockStringsWatcher{changes: make(chan []string, 1)}
+	go w.doneWhenDying()
+	return w
+}
+
+func (w *mockStringsWatcher) Changes() <-chan []string {
+	w.MethodCall(w, "Changes")
+	return w.changes
+}
+
+type mockNotifyWatcher struct {
+	mockWatcher
+	changes chan struct{}
+}
+
+func newMockNotifyWatcher() *mockNotifyWatcher {
+	w := &mockNotifyWatcher{changes: make(chan struct{}, 1)}
+	go w.doneWhenDying()
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() <-chan struct{} {
+	w.MethodCall(w, "Changes")
+	return w.changes
+}
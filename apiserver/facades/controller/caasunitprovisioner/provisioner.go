@@ -0,0 +1,269 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/cloudspec"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/status"
+)
+
+// defaultStorageRoot is the directory under which filesystems are
+// mounted in a unit's container, if the charm does not specify
+// a location.
+const defaultStorageRoot = "/var/lib/juju/storage"
+
+// Facade provides access to the CAASUnitProvisioner API facade.
+type Facade struct {
+	*common.LifeGetter
+	*common.ModelWatcher
+	cloudspec.CloudSpecAPI
+
+	resources facade.Resources
+	state     CAASUnitProvisionerState
+}
+
+// NewStateFacade provides the signature required for facade registration.
+func NewStateFacade(ctx facade.Context) (*Facade, error) {
+	st := ctx.State()
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cloudSpecAPI := cloudspec.NewCloudSpec(
+		cloudspec.MakeCloudSpecGetterForModel(st),
+		common.AuthFuncForTag(model.ModelTag()),
+	)
+	return NewFacade(
+		ctx.Resources(),
+		ctx.Auth(),
+		stateShim{State: st, model: model},
+		cloudSpecAPI,
+	)
+}
+
+// NewFacade returns a new CAASUnitProvisioner facade.
+func NewFacade(
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+	st CAASUnitProvisionerState,
+	cloudSpecAPI cloudspec.CloudSpecAPI,
+) (*Facade, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	accessApplication := common.AuthFuncForTagKind(names.ApplicationTagKind)
+	accessUnit := common.AuthFuncForTagKind(names.UnitTagKind)
+	return &Facade{
+		LifeGetter: common.NewLifeGetter(
+			st, common.AuthAny(accessApplication, accessUnit),
+		),
+		ModelWatcher: common.NewModelWatcher(st, resources, authorizer),
+		CloudSpecAPI: cloudSpecAPI,
+		resources:    resources,
+		state:        st,
+	}, nil
+}
+
+// WatchApplications starts a StringsWatcher to watch CAAS applications
+// deployed to this model.
+func (f *Facade) WatchApplications() (params.StringsWatchResult, error) {
+	w := f.state.WatchApplications()
+	if changes, ok := <-w.Changes(); ok {
+		return params.StringsWatchResult{
+			StringsWatcherId: f.resources.Register(w),
+			Changes:          changes,
+		}, nil
+	}
+	return params.StringsWatchResult{}, watcher.EnsureErr(w)
+}
+
+// WatchUnits starts a StringsWatcher to watch changes to the
+// lifecycle states of units for the specified applications in
+// this model.
+func (f *Facade) WatchUnits(args params.Entities) (params.StringsWatchResults, error) {
+	results := params.StringsWatchResults{
+		Results: make([]params.StringsWatchResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		id, changes, err := f.watchUnits(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].StringsWatcherId = id
+		results.Results[i].Changes = changes
+	}
+	return results, nil
+}
+
+func (f *Facade) watchUnits(tagString string) (string, []string, error) {
+	app, err := f.application(tagString)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	w := app.WatchUnits()
+	if changes, ok := <-w.Changes(); ok {
+		return f.resources.Register(w), changes, nil
+	}
+	return "", nil, watcher.EnsureErr(w)
+}
+
+// WatchPodSpec starts a NotifyWatcher to watch changes to the
+// pod spec for the specified applications in this model.
+func (f *Facade) WatchPodSpec(args params.Entities) (params.NotifyWatchResults, error) {
+	results := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		id, err := f.watchPodSpec(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].NotifyWatcherId = id
+	}
+	return results, nil
+}
+
+func (f *Facade) watchPodSpec(tagString string) (string, error) {
+	app, err := f.application(tagString)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	w := app.WatchPodSpec()
+	if _, ok := <-w.Changes(); ok {
+		return f.resources.Register(w), nil
+	}
+	return "", watcher.EnsureErr(w)
+}
+
+// ProvisioningInfo returns the info needed to provision the units
+// of the specified applications: the pod spec set by the charm, and
+// the filesystems required by its storage.
+func (f *Facade) ProvisioningInfo(args params.Entities) (params.CAASUnitProvisioningInfoResults, error) {
+	results := params.CAASUnitProvisioningInfoResults{
+		Results: make([]params.CAASUnitProvisioningInfoResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		info, err := f.provisioningInfo(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = info
+	}
+	return results, nil
+}
+
+func (f *Facade) provisioningInfo(tagString string) (*params.CAASUnitProvisioningInfo, error) {
+	app, err := f.application(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	podSpec, err := app.PodSpec()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ch, err := app.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cons, err := app.StorageConstraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var filesystems []params.KubernetesFilesystemParams
+	for name, meta := range ch.Meta().Storage {
+		if meta.Type != charm.StorageFilesystem {
+			continue
+		}
+		location := meta.Location
+		if location == "" {
+			location = fmt.Sprintf("%s/%s", defaultStorageRoot, name)
+		}
+		filesystems = append(filesystems, params.KubernetesFilesystemParams{
+			StorageName: name,
+			Size:        cons[name].Size,
+			Location:    location,
+		})
+	}
+	sort.Slice(filesystems, func(i, j int) bool {
+		return filesystems[i].StorageName < filesystems[j].StorageName
+	})
+	return &params.CAASUnitProvisioningInfo{
+		PodSpec:     podSpec,
+		Filesystems: filesystems,
+	}, nil
+}
+
+// SetContainerStatus sets the status of the containers running the
+// specified units, as reported by the CAAS substrate.
+func (f *Facade) SetContainerStatus(args params.SetStatus) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		results.Results[i].Error = common.ServerError(f.setContainerStatus(arg))
+	}
+	return results, nil
+}
+
+func (f *Facade) setContainerStatus(arg params.EntityStatusArgs) error {
+	tag, err := names.ParseUnitTag(arg.Tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	unit, err := f.state.Unit(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return unit.SetContainerStatus(status.StatusInfo{
+		Status:  status.Status(arg.Status),
+		Message: arg.Info,
+		Data:    arg.Data,
+	})
+}
+
+// SetContainerAddresses records the addresses of the containers
+// running the specified units, as reported by the CAAS substrate.
+func (f *Facade) SetContainerAddresses(args params.SetUnitContainerAddresses) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		results.Results[i].Error = common.ServerError(f.setContainerAddress(arg))
+	}
+	return results, nil
+}
+
+func (f *Facade) setContainerAddress(arg params.UnitContainerAddress) error {
+	tag, err := names.ParseUnitTag(arg.Tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	unit, err := f.state.Unit(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return unit.SetContainerAddress(arg.Address)
+}
+
+func (f *Facade) application(tagString string) (Application, error) {
+	tag, err := names.ParseApplicationTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return f.state.Application(tag.Id())
+}
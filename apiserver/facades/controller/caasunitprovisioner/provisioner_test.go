@@ -0,0 +1,240 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/cloudspec"
+	"github.com/juju/juju/apiserver/facades/controller/caasunitprovisioner"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&CAASProvisionerSuite{})
+
+type CAASProvisionerSuite struct {
+	coretesting.BaseSuite
+
+	resources  *common.Resources
+	authorizer *apiservertesting.FakeAuthorizer
+	st         *mockState
+	facade     *caasunitprovisioner.Facade
+}
+
+func (s *CAASProvisionerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.st = &mockState{
+		application: mockApplication{
+			life:    state.Alive,
+			podSpec: "containers: []",
+			meta: &charm.Meta{
+				Storage: map[string]charm.Storage{
+					"data": {Name: "data", Type: charm.StorageFilesystem, Location: "/srv/data"},
+					"logs": {Name: "logs", Type: charm.StorageFilesystem},
+					"disk": {Name: "disk", Type: charm.StorageBlock},
+				},
+			},
+			storage: map[string]state.StorageConstraints{
+				"data": {Size: 1024},
+				"logs": {Size: 100},
+			},
+			unitsWatcher: newMockStringsWatcher(),
+			specWatcher:  newMockNotifyWatcher(),
+		},
+		applicationsWatcher: newMockStringsWatcher(),
+	}
+	s.AddCleanup(func(c *gc.C) { stopWatcher(c, &s.st.applicationsWatcher.mockWatcher) })
+	s.AddCleanup(func(c *gc.C) { stopWatcher(c, &s.st.application.unitsWatcher.mockWatcher) })
+	s.AddCleanup(func(c *gc.C) { stopWatcher(c, &s.st.application.specWatcher.mockWatcher) })
+
+	s.resources = common.NewResources()
+	s.authorizer = &apiservertesting.FakeAuthorizer{
+		Tag:        names.NewMachineTag("0"),
+		Controller: true,
+	}
+
+	facade, err := caasunitprovisioner.NewFacade(s.resources, s.authorizer, s.st, s.cloudSpecAPI())
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade = facade
+}
+
+func (s *CAASProvisionerSuite) cloudSpecAPI() cloudspec.CloudSpecAPI {
+	return cloudspec.NewCloudSpec(func(names.ModelTag) (environs.CloudSpec, error) {
+		return environs.CloudSpec{Type: "kubernetes"}, nil
+	}, common.AuthFuncForTag(coretesting.ModelTag))
+}
+
+func stopWatcher(c *gc.C, w *mockWatcher) {
+	w.Kill()
+	c.Check(w.Wait(), jc.ErrorIsNil)
+}
+
+func (s *CAASProvisionerSuite) TestPermission(c *gc.C) {
+	s.authorizer = &apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := caasunitprovisioner.NewFacade(s.resources, s.authorizer, s.st, s.cloudSpecAPI())
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *CAASProvisionerSuite) TestWatchApplications(c *gc.C) {
+	applicationNames := []string{"db2", "hadoop"}
+	s.st.applicationsWatcher.changes <- applicationNames
+	result, err := s.facade.WatchApplications()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.StringsWatcherId, gc.Equals, "1")
+	c.Assert(result.Changes, jc.DeepEquals, applicationNames)
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.Equals, s.st.applicationsWatcher)
+}
+
+func (s *CAASProvisionerSuite) TestWatchUnits(c *gc.C) {
+	unitNames := []string{"gitlab/0", "gitlab/1"}
+	s.st.application.unitsWatcher.changes <- unitNames
+
+	results, err := s.facade.WatchUnits(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "application-mysql"},
+			{Tag: "unit-gitlab-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StringsWatchResult{{
+		StringsWatcherId: "1",
+		Changes:          unitNames,
+	}, {
+		Error: &params.Error{
+			Code:    params.CodeNotFound,
+			Message: "application mysql not found",
+		},
+	}, {
+		Error: &params.Error{
+			Message: `"unit-gitlab-0" is not a valid application tag`,
+		},
+	}})
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.Equals, s.st.application.unitsWatcher)
+}
+
+func (s *CAASProvisionerSuite) TestWatchPodSpec(c *gc.C) {
+	s.st.application.specWatcher.changes <- struct{}{}
+
+	results, err := s.facade.WatchPodSpec(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "application-mysql"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.NotifyWatchResult{{
+		NotifyWatcherId: "1",
+	}, {
+		Error: &params.Error{
+			Code:    params.CodeNotFound,
+			Message: "application mysql not found",
+		},
+	}})
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.Equals, s.st.application.specWatcher)
+}
+
+func (s *CAASProvisionerSuite) TestProvisioningInfo(c *gc.C) {
+	results, err := s.facade.ProvisioningInfo(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "application-mysql"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.CAASUnitProvisioningInfoResult{{
+		Result: &params.CAASUnitProvisioningInfo{
+			PodSpec: "containers: []",
+			Filesystems: []params.KubernetesFilesystemParams{{
+				StorageName: "data",
+				Size:        1024,
+				Location:    "/srv/data",
+			}, {
+				StorageName: "logs",
+				Size:        100,
+				Location:    "/var/lib/juju/storage/logs",
+			}},
+		},
+	}, {
+		Error: &params.Error{
+			Code:    params.CodeNotFound,
+			Message: "application mysql not found",
+		},
+	}})
+}
+
+func (s *CAASProvisionerSuite) TestSetContainerStatus(c *gc.C) {
+	results, err := s.facade.SetContainerStatus(params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: "unit-gitlab-0", Status: "running", Info: "pod running"},
+			{Tag: "unit-gitlab-1", Status: "running"},
+			{Tag: "application-gitlab", Status: "running"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{},
+		{Error: &params.Error{Code: params.CodeNotFound, Message: "unit gitlab/1 not found"}},
+		{Error: &params.Error{Message: `"application-gitlab" is not a valid unit tag`}},
+	})
+	s.st.unit.CheckCallNames(c, "SetContainerStatus")
+	s.st.unit.CheckCall(c, 0, "SetContainerStatus", status.StatusInfo{
+		Status:  status.Running,
+		Message: "pod running",
+	})
+}
+
+func (s *CAASProvisionerSuite) TestSetContainerAddresses(c *gc.C) {
+	results, err := s.facade.SetContainerAddresses(params.SetUnitContainerAddresses{
+		Args: []params.UnitContainerAddress{
+			{Tag: "unit-gitlab-0", Address: "10.0.0.1"},
+			{Tag: "unit-gitlab-1", Address: "10.0.0.2"},
+			{Tag: "application-gitlab", Address: "10.0.0.3"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{},
+		{Error: &params.Error{Code: params.CodeNotFound, Message: "unit gitlab/1 not found"}},
+		{Error: &params.Error{Message: `"application-gitlab" is not a valid unit tag`}},
+	})
+	s.st.unit.CheckCallNames(c, "SetContainerAddress")
+	s.st.unit.CheckCall(c, 0, "SetContainerAddress", "10.0.0.1")
+}
+
+func (s *CAASProvisionerSuite) TestLife(c *gc.C) {
+	s.st.application.life = state.Dying
+	results, err := s.facade.Life(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "unit-gitlab-0"},
+			{Tag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.LifeResult{
+		{Life: params.Dying},
+		{Life: params.Alive},
+		{Error: &params.Error{Code: params.CodeUnauthorized, Message: "permission denied"}},
+	})
+}
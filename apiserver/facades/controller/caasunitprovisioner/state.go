@@ -0,0 +1,93 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner
+
+import (
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+)
+
+// CAASUnitProvisionerState provides the subset of global state
+// required by the CAAS unit provisioner facade.
+type CAASUnitProvisionerState interface {
+	state.EntityFinder
+	state.ModelAccessor
+
+	// WatchApplications returns a StringsWatcher that notifies of
+	// changes to the lifecycles of the applications in the model.
+	WatchApplications() state.StringsWatcher
+
+	// Application returns the application with the given name.
+	Application(string) (Application, error)
+
+	// Unit returns the unit with the given name.
+	Unit(string) (Unit, error)
+}
+
+// Application provides the subset of application state required
+// by the CAAS unit provisioner facade.
+type Application interface {
+	Charm() (Charm, error)
+	PodSpec() (string, error)
+	StorageConstraints() (map[string]state.StorageConstraints, error)
+	WatchPodSpec() state.NotifyWatcher
+	WatchUnits() state.StringsWatcher
+}
+
+// Charm provides the subset of charm state required by the
+// CAAS unit provisioner facade.
+type Charm interface {
+	Meta() *charm.Meta
+}
+
+// Unit provides the subset of unit state required by the
+// CAAS unit provisioner facade.
+type Unit interface {
+	SetContainerStatus(status.StatusInfo) error
+	SetContainerAddress(string) error
+}
+
+type stateShim struct {
+	*state.State
+	model *state.Model
+}
+
+func (s stateShim) ModelConfig() (*config.Config, error) {
+	return s.model.ModelConfig()
+}
+
+func (s stateShim) WatchForModelConfigChanges() state.NotifyWatcher {
+	return s.model.WatchForModelConfigChanges()
+}
+
+func (s stateShim) Application(name string) (Application, error) {
+	app, err := s.State.Application(name)
+	if err != nil {
+		return nil, err
+	}
+	return applicationShim{app}, nil
+}
+
+func (s stateShim) Unit(name string) (Unit, error) {
+	unit, err := s.State.Unit(name)
+	if err != nil {
+		return nil, err
+	}
+	return unit, nil
+}
+
+type applicationShim struct {
+	*state.Application
+}
+
+func (a applicationShim) Charm() (Charm, error) {
+	ch, _, err := a.Application.Charm()
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
@@ -6,6 +6,7 @@ package firewaller
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
@@ -46,6 +47,11 @@ type FirewallerAPIV4 struct {
 	*common.ControllerConfigAPI
 }
 
+// FirewallerAPIV5 provides access to the Firewaller v5 API facade.
+type FirewallerAPIV5 struct {
+	*FirewallerAPIV4
+}
+
 // NewStateFirewallerAPIv3 creates a new server-side FirewallerAPIV3 facade.
 func NewStateFirewallerAPIV3(context facade.Context) (*FirewallerAPIV3, error) {
 	st := context.State()
@@ -74,6 +80,17 @@ func NewStateFirewallerAPIV4(context facade.Context) (*FirewallerAPIV4, error) {
 	}, nil
 }
 
+// NewStateFirewallerAPIv5 creates a new server-side FirewallerAPIV5 facade.
+func NewStateFirewallerAPIV5(context facade.Context) (*FirewallerAPIV5, error) {
+	facadev4, err := NewStateFirewallerAPIV4(context)
+	if err != nil {
+		return nil, err
+	}
+	return &FirewallerAPIV5{
+		FirewallerAPIV4: facadev4,
+	}, nil
+}
+
 // NewFirewallerAPI creates a new server-side FirewallerAPIV3 facade.
 func NewFirewallerAPI(
 	st State,
@@ -491,3 +508,147 @@ func (f *FirewallerAPIV4) FirewallRules(args params.KnownServiceArgs) (params.Li
 	}
 	return result, nil
 }
+
+// ApplicationFirewallRules returns the firewall rules which restrict
+// ingress to each of the specified applications when exposed.
+func (f *FirewallerAPIV5) ApplicationFirewallRules(args params.Entities) (params.FirewallRulesResults, error) {
+	result := params.FirewallRulesResults{
+		Results: make([]params.FirewallRulesResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.FirewallRulesResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		rules, err := f.st.ApplicationFirewallRules(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		for _, rule := range rules {
+			result.Results[i].Rules = append(result.Results[i].Rules, firewall.FirewallRuleToParams(rule))
+		}
+	}
+	return result, nil
+}
+
+// WatchFirewallRules returns a NotifyWatcher which notifies when
+// any of the model's firewall rules change.
+func (f *FirewallerAPIV5) WatchFirewallRules() (params.NotifyWatchResult, error) {
+	var result params.NotifyWatchResult
+	w := f.st.WatchFirewallRules()
+	// Consume the initial event.
+	if _, ok := <-w.Changes(); !ok {
+		result.Error = common.ServerError(watcher.EnsureErr(w))
+		return result, nil
+	}
+	result.NotifyWatcherId = f.resources.Register(w)
+	return result, nil
+}
+
+// GetExposeInfo returns the expose settings for each given application,
+// along with its endpoint bindings and the subnets of the spaces they
+// reference, so that ingress can be limited to the subnets on which the
+// exposed endpoints are available.
+func (f *FirewallerAPIV5) GetExposeInfo(args params.Entities) (params.ExposeInfoResults, error) {
+	result := params.ExposeInfoResults{
+		Results: make([]params.ExposeInfoResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.ExposeInfoResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		application, err := f.getApplication(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i], err = f.exposeInfo(application)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+func (f *FirewallerAPIV5) exposeInfo(application *state.Application) (params.ExposeInfoResult, error) {
+	result := params.ExposeInfoResult{Exposed: application.IsExposed()}
+	exposedEndpoints := application.ExposedEndpoints()
+	if !result.Exposed || len(exposedEndpoints) == 0 {
+		return result, nil
+	}
+	bindings, err := application.EndpointBindings()
+	if err != nil {
+		return params.ExposeInfoResult{}, errors.Trace(err)
+	}
+	spaces := set.NewStrings()
+	for _, space := range bindings {
+		spaces.Add(space)
+	}
+	result.EndpointBindings = bindings
+	result.ExposedEndpoints = make(map[string]params.ExposedEndpoint, len(exposedEndpoints))
+	for name, exp := range exposedEndpoints {
+		result.ExposedEndpoints[name] = params.ExposedEndpoint{
+			ExposeToSpaces: exp.ExposeToSpaces,
+			ExposeToCIDRs:  exp.ExposeToCIDRs,
+		}
+		spaces = spaces.Union(set.NewStrings(exp.ExposeToSpaces...))
+	}
+	// The default space has no subnets of its own.
+	spaces.Remove("")
+	if spaces.IsEmpty() {
+		return result, nil
+	}
+	result.SpaceSubnets = make(map[string][]string, spaces.Size())
+	for _, space := range spaces.SortedValues() {
+		cidrs, err := f.st.SpaceSubnetCIDRs(space)
+		if err != nil {
+			return params.ExposeInfoResult{}, errors.Trace(err)
+		}
+		result.SpaceSubnets[space] = cidrs
+	}
+	return result, nil
+}
+
+// GetEgressRules returns the egress rules for each given application.
+func (f *FirewallerAPIV5) GetEgressRules(args params.Entities) (params.EgressRulesResults, error) {
+	result := params.EgressRulesResults{
+		Results: make([]params.EgressRulesResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.EgressRulesResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		application, err := f.getApplication(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		rules, err := application.EgressRules()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		for _, rule := range rules {
+			result.Results[i].Rules = append(result.Results[i].Rules, params.FromNetworkEgressRule(rule))
+		}
+	}
+	return result, nil
+}
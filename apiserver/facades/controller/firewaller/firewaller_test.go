@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/firewaller"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 )
@@ -227,3 +228,71 @@ func (s *firewallerSuite) TestGetMachineActiveSubnets(c *gc.C) {
 		},
 	})
 }
+
+func (s *firewallerSuite) TestGetExposeInfo(c *gc.C) {
+	_, err := s.State.AddSpace("public", "", []string{"10.20.30.0/24"}, true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.application.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url": {
+			ExposeToSpaces: []string{"public"},
+			ExposeToCIDRs:  []string{"10.0.0.0/8"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := &firewaller.FirewallerAPIV5{
+		FirewallerAPIV4: &firewaller.FirewallerAPIV4{FirewallerAPIV3: s.firewaller},
+	}
+	result, err := api.GetExposeInfo(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+		{Tag: "application-bar"},
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+
+	info := result.Results[0]
+	c.Assert(info.Error, gc.IsNil)
+	c.Assert(info.Exposed, jc.IsTrue)
+	c.Assert(info.ExposedEndpoints, jc.DeepEquals, map[string]params.ExposedEndpoint{
+		"url": {
+			ExposeToSpaces: []string{"public"},
+			ExposeToCIDRs:  []string{"10.0.0.0/8"},
+		},
+	})
+	c.Assert(info.EndpointBindings["url"], gc.Equals, "")
+	c.Assert(info.SpaceSubnets, jc.DeepEquals, map[string][]string{
+		"public": {"10.20.30.0/24"},
+	})
+	c.Assert(result.Results[1].Error, jc.DeepEquals, apiservertesting.NotFoundError(`application "bar"`))
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+}
+
+func (s *firewallerSuite) TestGetEgressRules(c *gc.C) {
+	err := s.application.SetEgressRules([]network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := &firewaller.FirewallerAPIV5{
+		FirewallerAPIV4: &firewaller.FirewallerAPIV4{FirewallerAPIV3: s.firewaller},
+	}
+	result, err := api.GetEgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+		{Tag: "application-bar"},
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.EgressRulesResults{
+		Results: []params.EgressRulesResult{{
+			Rules: []params.EgressRule{{
+				PortRange:        params.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"},
+				DestinationCIDRs: []string{"10.0.0.0/8"},
+			}},
+		}, {
+			Error: apiservertesting.NotFoundError(`application "bar"`),
+		}, {
+			Error: apiservertesting.ErrUnauthorized,
+		}},
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/status"
 	coretesting "github.com/juju/juju/testing"
@@ -28,7 +29,7 @@ type RemoteFirewallerSuite struct {
 	resources  *common.Resources
 	authorizer *apiservertesting.FakeAuthorizer
 	st         *mockState
-	api        *firewaller.FirewallerAPIV4
+	api        *firewaller.FirewallerAPIV5
 }
 
 func (s *RemoteFirewallerSuite) SetUpTest(c *gc.C) {
@@ -45,7 +46,9 @@ func (s *RemoteFirewallerSuite) SetUpTest(c *gc.C) {
 	s.st = newMockState(coretesting.ModelTag.Id())
 	api, err := firewaller.NewFirewallerAPI(s.st, s.resources, s.authorizer, &mockCloudSpecAPI{})
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &firewaller.FirewallerAPIV4{FirewallerAPIV3: api, ControllerConfigAPI: common.NewControllerConfig(s.st)}
+	s.api = &firewaller.FirewallerAPIV5{
+		FirewallerAPIV4: &firewaller.FirewallerAPIV4{FirewallerAPIV3: api, ControllerConfigAPI: common.NewControllerConfig(s.st)},
+	}
 }
 
 func (s *RemoteFirewallerSuite) TestWatchIngressAddressesForRelations(c *gc.C) {
@@ -134,3 +137,42 @@ func (s *RemoteFirewallerSuite) TestFirewallRules(c *gc.C) {
 	c.Assert(result.Rules[0].KnownService, gc.Equals, params.KnownServiceValue("juju-application-offer"))
 	c.Assert(result.Rules[0].WhitelistCIDRS, jc.SameContents, []string{"192.168.0.0/16"})
 }
+
+func (s *RemoteFirewallerSuite) TestApplicationFirewallRules(c *gc.C) {
+	s.st.appRules["mysql"] = []*state.FirewallRule{{
+		WellKnownService: state.ApplicationRule,
+		Application:      "mysql",
+		PortRange:        network.MustParsePortRange("3306/tcp"),
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	}}
+	result, err := s.api.ApplicationFirewallRules(params.Entities{Entities: []params.Entity{
+		{Tag: "application-mysql"}, {Tag: "application-wordpress"}, {Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.FirewallRulesResults{
+		Results: []params.FirewallRulesResult{{
+			Rules: []params.FirewallRule{{
+				KnownService:   params.ApplicationRule,
+				Application:    "mysql",
+				PortRange:      &params.PortRange{FromPort: 3306, ToPort: 3306, Protocol: "tcp"},
+				WhitelistCIDRS: []string{"10.0.0.0/8"},
+			}},
+		}, {}, {
+			Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+		}},
+	})
+	s.st.CheckCalls(c, []testing.StubCall{
+		{"ApplicationFirewallRules", []interface{}{"mysql"}},
+		{"ApplicationFirewallRules", []interface{}{"wordpress"}},
+	})
+}
+
+func (s *RemoteFirewallerSuite) TestWatchFirewallRules(c *gc.C) {
+	result, err := s.api.WatchFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.Equals, s.st.rulesWatcher)
+}
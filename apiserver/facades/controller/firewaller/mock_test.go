@@ -40,6 +40,8 @@ type mockState struct {
 	relations      map[string]*mockRelation
 	controllerInfo map[string]*mockControllerInfo
 	firewallRules  map[state.WellKnownServiceType]*state.FirewallRule
+	appRules       map[string][]*state.FirewallRule
+	rulesWatcher   *mockNotifyWatcher
 	subnetsWatcher *mockStringsWatcher
 	modelWatcher   *mockNotifyWatcher
 	configAttrs    map[string]interface{}
@@ -53,6 +55,8 @@ func newMockState(modelUUID string) *mockState {
 		macaroons:      make(map[names.Tag]*macaroon.Macaroon),
 		controllerInfo: make(map[string]*mockControllerInfo),
 		firewallRules:  make(map[state.WellKnownServiceType]*state.FirewallRule),
+		appRules:       make(map[string][]*state.FirewallRule),
+		rulesWatcher:   newMockNotifyWatcher(),
 		subnetsWatcher: newMockStringsWatcher(),
 		modelWatcher:   newMockNotifyWatcher(),
 		configAttrs:    coretesting.FakeConfig(),
@@ -120,6 +124,24 @@ func (st *mockState) FirewallRule(service state.WellKnownServiceType) (*state.Fi
 	return r, nil
 }
 
+func (st *mockState) ApplicationFirewallRules(application string) ([]*state.FirewallRule, error) {
+	st.MethodCall(st, "ApplicationFirewallRules", application)
+	if err := st.NextErr(); err != nil {
+		return nil, err
+	}
+	return st.appRules[application], nil
+}
+
+func (st *mockState) WatchFirewallRules() state.NotifyWatcher {
+	st.MethodCall(st, "WatchFirewallRules")
+	return st.rulesWatcher
+}
+
+func (st *mockState) SpaceSubnetCIDRs(space string) ([]string, error) {
+	st.MethodCall(st, "SpaceSubnetCIDRs", space)
+	return nil, st.NextErr()
+}
+
 type mockWatcher struct {
 	testing.Stub
 	tomb.Tomb
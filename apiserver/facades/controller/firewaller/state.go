@@ -4,6 +4,7 @@
 package firewaller
 
 import (
+	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon.v1"
 
@@ -25,6 +26,12 @@ type State interface {
 	FindEntity(tag names.Tag) (state.Entity, error)
 
 	FirewallRule(service state.WellKnownServiceType) (*state.FirewallRule, error)
+
+	ApplicationFirewallRules(application string) ([]*state.FirewallRule, error)
+
+	WatchFirewallRules() state.NotifyWatcher
+
+	SpaceSubnetCIDRs(space string) ([]string, error)
 }
 
 // TODO(wallyworld) - for tests, remove when remaining firewaller tests become unit tests.
@@ -58,3 +65,28 @@ func (s stateShim) FirewallRule(service state.WellKnownServiceType) (*state.Fire
 	api := state.NewFirewallRules(s.st)
 	return api.Rule(service)
 }
+
+func (s stateShim) ApplicationFirewallRules(application string) ([]*state.FirewallRule, error) {
+	api := state.NewFirewallRules(s.st)
+	return api.ApplicationRules(application)
+}
+
+func (s stateShim) WatchFirewallRules() state.NotifyWatcher {
+	return s.st.WatchFirewallRules()
+}
+
+func (s stateShim) SpaceSubnetCIDRs(spaceName string) ([]string, error) {
+	space, err := s.st.Space(spaceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnets, err := space.Subnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cidrs := make([]string, len(subnets))
+	for i, subnet := range subnets {
+		cidrs[i] = subnet.CIDR()
+	}
+	return cidrs, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// KubernetesFilesystemParams holds the parameters for creating
+// a filesystem for each unit of a CAAS application.
+type KubernetesFilesystemParams struct {
+	StorageName string `json:"storagename"`
+	Size        uint64 `json:"size"`
+	Location    string `json:"location"`
+}
+
+// CAASUnitProvisioningInfo holds the info needed to provision
+// the units of a CAAS application.
+type CAASUnitProvisioningInfo struct {
+	PodSpec     string                       `json:"pod-spec"`
+	Filesystems []KubernetesFilesystemParams `json:"filesystems,omitempty"`
+}
+
+// CAASUnitProvisioningInfoResult holds unit provisioning info or an error.
+type CAASUnitProvisioningInfoResult struct {
+	Result *CAASUnitProvisioningInfo `json:"result,omitempty"`
+	Error  *Error                    `json:"error,omitempty"`
+}
+
+// CAASUnitProvisioningInfoResults holds multiple unit provisioning info results.
+type CAASUnitProvisioningInfoResults struct {
+	Results []CAASUnitProvisioningInfoResult `json:"results"`
+}
+
+// UnitContainerAddress holds the address of the container running
+// a unit, as reported by the CAAS substrate.
+type UnitContainerAddress struct {
+	Tag     string `json:"tag"`
+	Address string `json:"address"`
+}
+
+// SetUnitContainerAddresses holds the addresses of the containers
+// running a number of units.
+type SetUnitContainerAddresses struct {
+	Args []UnitContainerAddress `json:"args"`
+}
+
+// EntityString holds an entity tag and a string value.
+type EntityString struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+// SetPodSpecParams holds the arguments for setting the pod specs
+// of a number of applications.
+type SetPodSpecParams struct {
+	Specs []EntityString `json:"specs"`
+}
@@ -11,6 +11,7 @@ type Cloud struct {
 	IdentityEndpoint string        `json:"identity-endpoint,omitempty"`
 	StorageEndpoint  string        `json:"storage-endpoint,omitempty"`
 	Regions          []CloudRegion `json:"regions,omitempty"`
+	CACertificates   []string      `json:"ca-certificates,omitempty"`
 }
 
 // CloudRegion holds information about a cloud region.
@@ -100,6 +101,7 @@ type CloudSpec struct {
 	IdentityEndpoint string           `json:"identity-endpoint,omitempty"`
 	StorageEndpoint  string           `json:"storage-endpoint,omitempty"`
 	Credential       *CloudCredential `json:"credential,omitempty"`
+	CACertificates   []string         `json:"ca-certificates,omitempty"`
 }
 
 // CloudSpecResult contains a CloudSpec or an error.
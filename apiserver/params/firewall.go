@@ -3,7 +3,11 @@
 
 package params
 
-import "github.com/juju/errors"
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/network"
+)
 
 // FirewallRuleArgs holds the parameters for updating
 // one or more firewall rules.
@@ -23,10 +27,104 @@ type FirewallRule struct {
 	// KnownService is the well known service for a firewall rule.
 	KnownService KnownServiceValue `json:"known-service"`
 
+	// Application is the name of the application for an
+	// application firewall rule.
+	Application string `json:"application,omitempty"`
+
+	// PortRange, if set, limits an application firewall rule
+	// to the specified port range.
+	PortRange *PortRange `json:"port-range,omitempty"`
+
 	// WhitelistCIDRS is the ist of subnets allowed access.
 	WhitelistCIDRS []string `json:"whitelist-cidrs,omitempty"`
 }
 
+// FirewallRulesResults holds the firewall rules for a number of entities.
+type FirewallRulesResults struct {
+	Results []FirewallRulesResult `json:"results"`
+}
+
+// FirewallRulesResult holds the firewall rules for an entity.
+type FirewallRulesResult struct {
+	Rules []FirewallRule `json:"rules,omitempty"`
+	Error *Error         `json:"error,omitempty"`
+}
+
+// EgressRule is a rule allowing traffic from an application's units
+// to the specified destinations.
+type EgressRule struct {
+	// PortRange is the range of destination ports to which the
+	// rule applies.
+	PortRange PortRange `json:"port-range"`
+
+	// DestinationCIDRs is the list of subnets to which traffic
+	// is allowed.
+	DestinationCIDRs []string `json:"destination-cidrs"`
+}
+
+// FromNetworkEgressRule is a convenience helper to create a parameter
+// out of the network type, here for EgressRule.
+func FromNetworkEgressRule(rule network.EgressRule) EgressRule {
+	return EgressRule{
+		PortRange:        FromNetworkPortRange(rule.PortRange),
+		DestinationCIDRs: rule.DestinationCIDRs,
+	}
+}
+
+// NetworkEgressRule is a convenience helper to return the parameter
+// as network type, here for EgressRule.
+func (r EgressRule) NetworkEgressRule() network.EgressRule {
+	return network.EgressRule{
+		PortRange:        r.PortRange.NetworkPortRange(),
+		DestinationCIDRs: r.DestinationCIDRs,
+	}
+}
+
+// ApplicationEgressRulesArgs holds the parameters for replacing the
+// egress rules of one or more applications.
+type ApplicationEgressRulesArgs struct {
+	Args []ApplicationEgressRules `json:"args"`
+}
+
+// ApplicationEgressRules holds the complete set of egress rules for
+// an application. An empty set of rules removes any egress
+// restrictions for the application.
+type ApplicationEgressRules struct {
+	ApplicationTag string       `json:"application-tag"`
+	Rules          []EgressRule `json:"rules"`
+}
+
+// EgressRulesResults holds the egress rules for a number of entities.
+type EgressRulesResults struct {
+	Results []EgressRulesResult `json:"results"`
+}
+
+// EgressRulesResult holds the egress rules for an entity.
+type EgressRulesResult struct {
+	Rules []EgressRule `json:"rules,omitempty"`
+	Error *Error       `json:"error,omitempty"`
+}
+
+// ExposeInfoResults holds the results of a GetExposeInfo call.
+type ExposeInfoResults struct {
+	Results []ExposeInfoResult `json:"results"`
+}
+
+// ExposeInfoResult holds the expose settings for an application.
+// EndpointBindings and SpaceSubnets are only populated when the
+// application has endpoint specific expose settings.
+type ExposeInfoResult struct {
+	Exposed          bool                       `json:"exposed,omitempty"`
+	ExposedEndpoints map[string]ExposedEndpoint `json:"exposed-endpoints,omitempty"`
+	// EndpointBindings maps the application's endpoints to the
+	// spaces they are bound to.
+	EndpointBindings map[string]string `json:"endpoint-bindings,omitempty"`
+	// SpaceSubnets maps the names of the spaces referenced by the
+	// bindings and expose settings to the CIDRs of their subnets.
+	SpaceSubnets map[string][]string `json:"space-subnets,omitempty"`
+	Error        *Error              `json:"error,omitempty"`
+}
+
 // KnownServiceArgs holds the parameters for retrieving firewall rules.
 type KnownServiceArgs struct {
 	// KnownServices are the well known services for a firewall rule.
@@ -50,12 +148,16 @@ const (
 
 	// JujuApplicationOfferRule is a rule for connections to a Juju offer.
 	JujuApplicationOfferRule KnownServiceValue = "juju-application-offer"
+
+	// ApplicationRule is a rule for connections to the ports
+	// opened by an exposed application.
+	ApplicationRule KnownServiceValue = "application"
 )
 
 // Validate returns an error if the service value is not valid.
 func (v KnownServiceValue) Validate() error {
 	switch v {
-	case SSHRule, JujuControllerRule, JujuApplicationOfferRule:
+	case SSHRule, JujuControllerRule, JujuApplicationOfferRule, ApplicationRule:
 		return nil
 	}
 	return errors.NotValidf("known service %q", v)
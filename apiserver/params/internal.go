@@ -687,6 +687,106 @@ type ProvisioningInfo struct {
 	ControllerConfig  map[string]interface{}    `json:"controller-config,omitempty"`
 }
 
+// CharmLXDProfile holds the LXD profile declared by a charm.
+type CharmLXDProfile struct {
+	Config      map[string]string            `json:"config,omitempty"`
+	Description string                       `json:"description,omitempty"`
+	Devices     map[string]map[string]string `json:"devices,omitempty"`
+}
+
+// CharmProfiles holds the charm LXD profiles, keyed by profile name,
+// that a container should have, along with the names of those
+// currently applied to it.
+type CharmProfiles struct {
+	Profiles map[string]CharmLXDProfile `json:"profiles,omitempty"`
+	Current  []string                   `json:"current,omitempty"`
+}
+
+// CharmProfilesResult holds a container's charm profiles or an error.
+type CharmProfilesResult struct {
+	Error  *Error         `json:"error,omitempty"`
+	Result *CharmProfiles `json:"result,omitempty"`
+}
+
+// CharmProfilesResults holds multiple charm profiles results.
+type CharmProfilesResults struct {
+	Results []CharmProfilesResult `json:"results"`
+}
+
+// SetCharmProfilesArg records the names of the charm LXD profiles
+// applied to a container.
+type SetCharmProfilesArg struct {
+	Tag      string   `json:"tag"`
+	Profiles []string `json:"profiles"`
+}
+
+// SetCharmProfilesArgs holds the arguments for recording the charm
+// LXD profiles applied to multiple containers.
+type SetCharmProfilesArgs struct {
+	Args []SetCharmProfilesArg `json:"args"`
+}
+
+// The phases of a container move. All but ContainerMoveRequested are
+// reported by UpdateContainerMoves.
+const (
+	ContainerMoveRequested   = "requested"
+	ContainerMoveSourceReady = "source-ready"
+	ContainerMoveCopied      = "copied"
+	ContainerMoveFailed      = "failed"
+	ContainerMoveCompleted   = "completed"
+	ContainerMoveAborted     = "aborted"
+)
+
+// ContainerMoveSource holds what a host machine needs in order to copy
+// a container from the container's current host.
+type ContainerMoveSource struct {
+	Addresses    []string                     `json:"addresses"`
+	Operation    string                       `json:"operation"`
+	Certificate  string                       `json:"certificate"`
+	Secrets      map[string]string            `json:"secrets,omitempty"`
+	Architecture string                       `json:"architecture,omitempty"`
+	Config       map[string]string            `json:"config,omitempty"`
+	Devices      map[string]map[string]string `json:"devices,omitempty"`
+	Profiles     []string                     `json:"profiles,omitempty"`
+}
+
+// ContainerMove describes the move of a container from its current
+// host machine to a target host machine.
+type ContainerMove struct {
+	InstanceId string               `json:"instance-id"`
+	HostTag    string               `json:"host-tag"`
+	TargetTag  string               `json:"target-tag"`
+	Live       bool                 `json:"live"`
+	Phase      string               `json:"phase"`
+	Source     *ContainerMoveSource `json:"source,omitempty"`
+	Message    string               `json:"message,omitempty"`
+}
+
+// ContainerMoveResult holds a container move or an error.
+type ContainerMoveResult struct {
+	Result *ContainerMove `json:"result,omitempty"`
+	Error  *Error         `json:"error,omitempty"`
+}
+
+// ContainerMoveResults holds the results of a ContainerMoves call.
+type ContainerMoveResults struct {
+	Results []ContainerMoveResult `json:"results"`
+}
+
+// UpdateContainerMoveArg records the progress of a container move.
+type UpdateContainerMoveArg struct {
+	Tag     string               `json:"tag"`
+	Phase   string               `json:"phase"`
+	Source  *ContainerMoveSource `json:"source,omitempty"`
+	Message string               `json:"message,omitempty"`
+}
+
+// UpdateContainerMoveArgs holds the arguments of an
+// UpdateContainerMoves call.
+type UpdateContainerMoveArgs struct {
+	Args []UpdateContainerMoveArg `json:"args"`
+}
+
 // ProvisioningInfoResult holds machine provisioning info or an error.
 type ProvisioningInfoResult struct {
 	Error  *Error            `json:"error,omitempty"`
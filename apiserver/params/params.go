@@ -296,6 +296,20 @@ type UpdateSeriesArgs struct {
 	Args []UpdateSeriesArg `json:"args"`
 }
 
+// MoveMachineArg holds the parameters for moving a container to
+// another host machine.
+type MoveMachineArg struct {
+	MachineTag string `json:"machine-tag"`
+	HostTag    string `json:"host-tag"`
+	Live       bool   `json:"live,omitempty"`
+}
+
+// MoveMachineArgs holds the parameters for moving containers to other
+// host machines.
+type MoveMachineArgs struct {
+	Args []MoveMachineArg `json:"args"`
+}
+
 // ApplicationSetCharm sets the charm for a given application.
 type ApplicationSetCharm struct {
 	// ApplicationName is the name of the application to set the charm on.
@@ -338,6 +352,19 @@ type ApplicationSetCharm struct {
 // ApplicationExpose holds the parameters for making the application Expose call.
 type ApplicationExpose struct {
 	ApplicationName string `json:"application"`
+
+	// ExposedEndpoints holds the expose settings to apply, keyed on
+	// endpoint name. The empty endpoint name applies the settings to
+	// all endpoints. This field is only understood by Application
+	// facade version 6 and greater.
+	ExposedEndpoints map[string]ExposedEndpoint `json:"exposed-endpoints,omitempty"`
+}
+
+// ExposedEndpoint describes the spaces and/or CIDRs that should be
+// able to access the ports opened for an application endpoint.
+type ExposedEndpoint struct {
+	ExposeToSpaces []string `json:"expose-to-spaces,omitempty"`
+	ExposeToCIDRs  []string `json:"expose-to-cidrs,omitempty"`
 }
 
 // ApplicationSet holds the parameters for an application Set
@@ -368,6 +395,18 @@ type ApplicationGetResults struct {
 	Config      map[string]interface{} `json:"config"`
 	Constraints constraints.Value      `json:"constraints"`
 	Series      string                 `json:"series"`
+
+	// UpdateStatusInterval holds the application's update-status hook
+	// interval, if it overrides the model's interval. This field is
+	// only set by Application facade version 7 and greater.
+	UpdateStatusInterval time.Duration `json:"update-status-interval,omitempty"`
+
+	// HookTimeout holds the application's hook timeout, if it has
+	// one, and HookTimeoutNoRetry records that hooks that time out
+	// are not retried. These fields are only set by Application facade
+	// version 9 and greater.
+	HookTimeout        time.Duration `json:"hook-timeout,omitempty"`
+	HookTimeoutNoRetry bool          `json:"hook-timeout-no-retry,omitempty"`
 }
 
 // ApplicationCharmRelations holds parameters for making the application CharmRelations call.
@@ -383,6 +422,12 @@ type ApplicationCharmRelationsResults struct {
 // ApplicationUnexpose holds parameters for the application Unexpose call.
 type ApplicationUnexpose struct {
 	ApplicationName string `json:"application"`
+
+	// ExposedEndpoints holds the names of the endpoints whose expose
+	// settings should be removed. If empty, the application is
+	// unexposed entirely. This field is only understood by Application
+	// facade version 6 and greater.
+	ExposedEndpoints []string `json:"exposed-endpoints,omitempty"`
 }
 
 // ApplicationMetricCredential holds parameters for the SetApplicationCredentials call.
@@ -396,6 +441,129 @@ type ApplicationMetricCredentials struct {
 	Creds []ApplicationMetricCredential `json:"creds"`
 }
 
+// ApplicationUpdateStatusInterval holds parameters for setting the
+// update-status hook interval of an application. A zero interval
+// reverts to the model's interval.
+type ApplicationUpdateStatusInterval struct {
+	ApplicationName string        `json:"application"`
+	Interval        time.Duration `json:"interval"`
+}
+
+// ApplicationUpdateStatusIntervals holds multiple
+// ApplicationUpdateStatusInterval parameters.
+type ApplicationUpdateStatusIntervals struct {
+	Args []ApplicationUpdateStatusInterval `json:"args"`
+}
+
+// ApplicationHookTimeout holds parameters for setting the hook
+// timeout policy of an application. A zero timeout reverts to the
+// timeout declared by the charm.
+type ApplicationHookTimeout struct {
+	ApplicationName string        `json:"application"`
+	Timeout         time.Duration `json:"timeout"`
+	Retry           bool          `json:"retry"`
+}
+
+// ApplicationHookTimeouts holds multiple ApplicationHookTimeout
+// parameters.
+type ApplicationHookTimeouts struct {
+	Args []ApplicationHookTimeout `json:"args"`
+}
+
+// PrecheckDeployMachine holds the details of a machine that a
+// deployment would create or place units on.
+type PrecheckDeployMachine struct {
+	Series      string              `json:"series"`
+	Constraints constraints.Value   `json:"constraints"`
+	Placement   *instance.Placement `json:"placement,omitempty"`
+}
+
+// PrecheckDeployArgs holds the machines to check for the
+// PrecheckDeploy call.
+type PrecheckDeployArgs struct {
+	Machines []PrecheckDeployMachine `json:"machines"`
+}
+
+// PrecheckDeployResult holds every problem found with a machine
+// that a deployment would create or place units on.
+type PrecheckDeployResult struct {
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// PrecheckDeployResults holds the results of a PrecheckDeploy call.
+type PrecheckDeployResults struct {
+	Results []PrecheckDeployResult `json:"results"`
+}
+
+// UpdateStatusHookIntervalResult holds the update-status hook interval
+// in effect for a unit or application, or an error.
+type UpdateStatusHookIntervalResult struct {
+	Interval time.Duration `json:"interval"`
+	Error    *Error        `json:"error,omitempty"`
+}
+
+// UpdateStatusHookIntervalResults holds multiple
+// UpdateStatusHookIntervalResult values.
+type UpdateStatusHookIntervalResults struct {
+	Results []UpdateStatusHookIntervalResult `json:"results"`
+}
+
+// HookExecution holds the record of an execution of a hook or action
+// by a unit agent.
+type HookExecution struct {
+	Seq        int       `json:"seq,omitempty"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	Relation   string    `json:"relation,omitempty"`
+	RemoteUnit string    `json:"remote-unit,omitempty"`
+	Started    time.Time `json:"started"`
+	Finished   time.Time `json:"finished"`
+	ExitCode   int       `json:"exit-code"`
+	Error      string    `json:"error,omitempty"`
+	Stdout     string    `json:"stdout,omitempty"`
+	Stderr     string    `json:"stderr,omitempty"`
+}
+
+// UnitHookExecution holds a hook execution to record for a unit.
+type UnitHookExecution struct {
+	Tag       string        `json:"tag"`
+	Execution HookExecution `json:"execution"`
+}
+
+// UnitHookExecutions holds multiple UnitHookExecution parameters.
+type UnitHookExecutions struct {
+	Args []UnitHookExecution `json:"args"`
+}
+
+// UnitHookExecutionsQuery holds parameters for fetching the hook
+// executions recorded for a unit. If Seq is non-zero, only that
+// execution is returned, with its output; otherwise the most recent
+// executions, up to Limit if it is positive, are returned without
+// their output.
+type UnitHookExecutionsQuery struct {
+	Tag   string `json:"tag"`
+	Limit int    `json:"limit,omitempty"`
+	Seq   int    `json:"seq,omitempty"`
+}
+
+// UnitHookExecutionsQueries holds multiple UnitHookExecutionsQuery
+// parameters.
+type UnitHookExecutionsQueries struct {
+	Queries []UnitHookExecutionsQuery `json:"queries"`
+}
+
+// HookExecutionsResult holds the hook executions of a unit, newest
+// first, or an error.
+type HookExecutionsResult struct {
+	Executions []HookExecution `json:"executions,omitempty"`
+	Error      *Error          `json:"error,omitempty"`
+}
+
+// HookExecutionsResults holds multiple HookExecutionsResult values.
+type HookExecutionsResults struct {
+	Results []HookExecutionsResult `json:"results"`
+}
+
 // ApplicationGetConfigResults holds the return values for application GetConfig.
 type ApplicationGetConfigResults struct {
 	Results []ConfigResult
@@ -624,6 +792,10 @@ type StateServingInfo struct {
 	// this will be passed as the KeyFile argument to MongoDB
 	SharedSecret   string `json:"shared-secret"`
 	SystemIdentity string `json:"system-identity"`
+	// The key from which the keys that encrypt charm secrets are
+	// derived. It is kept in the controller agents' configuration
+	// rather than in the database.
+	SecretsKey string `json:"secrets-key,omitempty"`
 }
 
 // IsMasterResult holds the result of an IsMaster API call.
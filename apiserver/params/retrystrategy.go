@@ -15,6 +15,13 @@ type RetryStrategy struct {
 	MaxRetryTime    time.Duration `json:"max-retry-time"`
 	JitterRetryTime bool          `json:"jitter-retry-time"`
 	RetryTimeFactor int64         `json:"retry-time-factor"`
+
+	// HookTimeout limits how long a hook may run, if it is non-zero;
+	// RetryTimedOutHooks records whether hooks that time out are
+	// retried like other failed hooks. These fields are only set by
+	// RetryStrategy facade version 2 and greater.
+	HookTimeout        time.Duration `json:"hook-timeout,omitempty"`
+	RetryTimedOutHooks bool          `json:"retry-timed-out-hooks,omitempty"`
 }
 
 // RetryStrategyResult holds a RetryStrategy or an error.
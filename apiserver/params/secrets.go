@@ -0,0 +1,110 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// CreateSecretArgs holds the arguments for creating secrets.
+type CreateSecretArgs struct {
+	Args []CreateSecretArg `json:"args"`
+}
+
+// CreateSecretArg holds the arguments for a unit to create a secret
+// owned by its application.
+type CreateSecretArg struct {
+	UnitTag string            `json:"unit-tag"`
+	Label   string            `json:"label,omitempty"`
+	Value   map[string]string `json:"value"`
+}
+
+// RotateSecretArgs holds the arguments for rotating secrets.
+type RotateSecretArgs struct {
+	Args []RotateSecretArg `json:"args"`
+}
+
+// RotateSecretArg holds the arguments for a unit to replace the value
+// of a secret owned by its application.
+type RotateSecretArg struct {
+	UnitTag  string            `json:"unit-tag"`
+	SecretId string            `json:"secret-id"`
+	Value    map[string]string `json:"value"`
+}
+
+// GetSecretValueArgs holds the arguments for getting secret values.
+type GetSecretValueArgs struct {
+	Args []GetSecretValueArg `json:"args"`
+}
+
+// GetSecretValueArg holds the arguments for a unit to read a secret.
+type GetSecretValueArg struct {
+	UnitTag  string `json:"unit-tag"`
+	SecretId string `json:"secret-id"`
+}
+
+// SecretValueResults holds the values of secrets.
+type SecretValueResults struct {
+	Results []SecretValueResult `json:"results"`
+}
+
+// SecretValueResult holds the value and revision of a secret, or an
+// error.
+type SecretValueResult struct {
+	Value    map[string]string `json:"value,omitempty"`
+	Revision int               `json:"revision,omitempty"`
+	Error    *Error            `json:"error,omitempty"`
+}
+
+// GrantSecretArgs holds the arguments for granting access to secrets.
+type GrantSecretArgs struct {
+	Args []GrantSecretArg `json:"args"`
+}
+
+// GrantSecretArg holds the arguments for a unit to grant access to a
+// secret owned by its application, over one of its relations.
+type GrantSecretArg struct {
+	UnitTag     string `json:"unit-tag"`
+	SecretId    string `json:"secret-id"`
+	RelationTag string `json:"relation-tag"`
+
+	// GranteeTag, if set, restricts the grant to a single unit of the
+	// related application.
+	GranteeTag string `json:"grantee-tag,omitempty"`
+}
+
+// SecretRevisionsResults holds the revisions of the secrets granted to
+// units.
+type SecretRevisionsResults struct {
+	Results []SecretRevisionsResult `json:"results"`
+}
+
+// SecretRevisionsResult holds the current revision of each secret
+// granted to a unit, keyed on secret id, or an error.
+type SecretRevisionsResult struct {
+	Revisions map[string]int `json:"revisions,omitempty"`
+	Error     *Error         `json:"error,omitempty"`
+}
+
+// ListSecretResults holds the metadata of the secrets in a model.
+type ListSecretResults struct {
+	Results []SecretMetadata `json:"results"`
+}
+
+// SecretMetadata describes a secret, without its value.
+type SecretMetadata struct {
+	Id         string        `json:"id"`
+	Owner      string        `json:"owner"`
+	Label      string        `json:"label,omitempty"`
+	Revision   int           `json:"revision"`
+	CreateTime time.Time     `json:"create-time"`
+	UpdateTime time.Time     `json:"update-time"`
+	Grants     []SecretGrant `json:"grants,omitempty"`
+}
+
+// SecretGrant describes access to a secret granted over a relation.
+type SecretGrant struct {
+	Relation string `json:"relation"`
+	Unit     string `json:"unit,omitempty"`
+}
@@ -101,6 +101,10 @@ type MachineStatus struct {
 	Jobs      []multiwatcher.MachineJob `json:"jobs"`
 	HasVote   bool                      `json:"has-vote"`
 	WantsVote bool                      `json:"wants-vote"`
+
+	// LXDProfiles holds the names of the charm LXD profiles applied to
+	// this machine, if it is an LXD container.
+	LXDProfiles []string `json:"lxd-profiles,omitempty"`
 }
 
 // ApplicationStatus holds status info about an application.
@@ -156,6 +160,14 @@ type UnitStatus struct {
 	WorkloadStatus  DetailedStatus `json:"workload-status"`
 	WorkloadVersion string         `json:"workload-version"`
 
+	// HealthStatus holds the result of the health checks declared
+	// by the unit's charm. It is empty if the charm declares none.
+	HealthStatus DetailedStatus `json:"health-status"`
+
+	// ContainerStatus holds the status of the container running the
+	// unit on a CAAS substrate. It is empty for units on machines.
+	ContainerStatus DetailedStatus `json:"container-status"`
+
 	Machine       string                `json:"machine"`
 	OpenedPorts   []string              `json:"opened-ports"`
 	PublicAddress string                `json:"public-address"`
@@ -396,6 +396,13 @@ func (r *apiHandler) AuthUnitAgent() bool {
 	return isUnit
 }
 
+// AuthApplicationAgent returns whether the current client is an
+// application operator agent.
+func (r *apiHandler) AuthApplicationAgent() bool {
+	_, isApp := r.GetAuthTag().(names.ApplicationTag)
+	return isApp
+}
+
 // AuthOwner returns whether the authenticated user's tag matches the
 // given entity tag.
 func (r *apiHandler) AuthOwner(tag names.Tag) bool {
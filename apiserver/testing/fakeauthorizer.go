@@ -40,6 +40,13 @@ func (fa FakeAuthorizer) AuthUnitAgent() bool {
 	return isUnit
 }
 
+// AuthApplicationAgent returns whether the current client is an
+// application operator agent.
+func (fa FakeAuthorizer) AuthApplicationAgent() bool {
+	_, isApp := fa.GetAuthTag().(names.ApplicationTag)
+	return isApp
+}
+
 // AuthClient returns whether the authenticated entity is a client
 // user.
 func (fa FakeAuthorizer) AuthClient() bool {
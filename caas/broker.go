@@ -0,0 +1,67 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"github.com/juju/juju/status"
+)
+
+// Broker instances interact with a container orchestration
+// substrate, such as Kubernetes, on behalf of a single model.
+type Broker interface {
+	// EnsureNamespace ensures this broker's namespace is created.
+	EnsureNamespace() error
+
+	// EnsureService creates or updates a service for pods with the
+	// given params, scaled to the given number of units.
+	EnsureService(appName string, params *ServiceParams, numUnits int) error
+
+	// DeleteService deletes the specified service, along with any
+	// pods and volume claims belonging to it.
+	DeleteService(appName string) error
+
+	// Units returns all units belonging to the specified application.
+	Units(appName string) ([]Unit, error)
+
+	// AssignUnit records that the unit with the given id, belonging
+	// to the specified application, runs the named juju unit.
+	AssignUnit(appName, id, unitName string) error
+}
+
+// ServiceParams defines parameters used to create a service.
+type ServiceParams struct {
+	// PodSpec is the spec used to configure a pod.
+	PodSpec *PodSpec
+
+	// Filesystems is the set of persistent filesystems to be
+	// mounted into each pod.
+	Filesystems []FilesystemParams
+}
+
+// FilesystemParams holds the parameters for a filesystem
+// to be provisioned for each unit of a service.
+type FilesystemParams struct {
+	// StorageName is the name of the charm storage the
+	// filesystem is for.
+	StorageName string
+
+	// Size is the size of the filesystem, in MiB.
+	Size uint64
+
+	// Location is the path at which the filesystem is
+	// mounted inside each container.
+	Location string
+}
+
+// Unit represents information about the status of a "pod".
+type Unit struct {
+	Id string
+
+	// UnitName is the name of the juju unit the pod has been
+	// assigned to, or empty if it has not been assigned one.
+	UnitName string
+
+	Address string
+	Status  status.StatusInfo
+}
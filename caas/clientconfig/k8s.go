@@ -1,10 +1,16 @@
 package caas
 
 import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"gopkg.in/yaml.v2"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
@@ -13,26 +19,59 @@ import (
 
 var logger = loggo.GetLogger("juju.caas.clientconfig")
 
-// K8SClientConfig parses Kubernetes client configuration from the default location or $KUBECONFIG.
-func K8SClientConfig() (*ClientConfig, error) {
-
-	configPath := getKubeConfigPath()
+// K8SClientConfig parses Kubernetes client configuration from the given
+// reader or, if the reader is nil, from the default location or $KUBECONFIG.
+func K8SClientConfig(r io.Reader) (*ClientConfig, error) {
+	var (
+		data    []byte
+		baseDir string
+		err     error
+	)
+	if r == nil {
+		configPath := getKubeConfigPath()
+		data, err = ioutil.ReadFile(configPath)
+		if err != nil {
+			return nil, errors.Annotatef(err, "failed to read kubernetes config from '%s'", configPath)
+		}
+		baseDir = filepath.Dir(configPath)
+	} else {
+		data, err = ioutil.ReadAll(r)
+		if err != nil {
+			return nil, errors.Annotate(err, "failed to read kubernetes config")
+		}
+		// Relative paths in the config are resolved as kubectl
+		// does, against the directory of the file being read, or
+		// the working directory if the config does not come from
+		// a file.
+		if f, ok := r.(interface {
+			Name() string
+		}); ok {
+			baseDir = filepath.Dir(f.Name())
+		} else if baseDir, err = os.Getwd(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 
-	config, err := clientcmd.LoadFromFile(configPath)
+	config, err := clientcmd.Load(data)
 	if err != nil {
-		return nil, errors.Annotatef(err, "failed to read kubernetes config from '%s'", configPath)
+		return nil, errors.Annotate(err, "failed to parse kubernetes config")
 	}
 	contexts, err := contextsFromConfig(config)
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to read contexts from kubernetes config.")
 	}
 
-	clouds, err := cloudsFromConfig(config)
+	clouds, err := cloudsFromConfig(config, baseDir)
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to read clouds from kubernetes config.")
 	}
 
-	credentials, err := credentialsFromConfig(config)
+	execConfigs, err := execConfigsFromConfig(data)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to read exec credentials from kubernetes config.")
+	}
+
+	credentials, err := credentialsFromConfig(config, execConfigs, baseDir)
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to read credentials from kubernetes config.")
 	}
@@ -57,11 +96,15 @@ func contextsFromConfig(config *clientcmdapi.Config) (map[string]Context, error)
 	return rv, nil
 }
 
-func cloudsFromConfig(config *clientcmdapi.Config) (map[string]CloudConfig, error) {
+func cloudsFromConfig(config *clientcmdapi.Config, baseDir string) (map[string]CloudConfig, error) {
 	rv := map[string]CloudConfig{}
 	for name, cluster := range config.Clusters {
+		caData, err := dataOrFile(cluster.CertificateAuthorityData, cluster.CertificateAuthority, baseDir)
+		if err != nil {
+			return nil, errors.Annotatef(err, "reading certificate authority for cluster '%s'", name)
+		}
 		attrs := map[string]interface{}{}
-		attrs["CAData"] = cluster.CertificateAuthorityData
+		attrs["CAData"] = caData
 
 		rv[name] = CloudConfig{
 			Endpoint:   cluster.Server,
@@ -71,26 +114,57 @@ func cloudsFromConfig(config *clientcmdapi.Config) (map[string]CloudConfig, erro
 	return rv, nil
 }
 
-func credentialsFromConfig(config *clientcmdapi.Config) (map[string]cloud.Credential, error) {
+// execConfig holds a kubeconfig user's exec stanza, which names a
+// command that prints a credential for the user. The version of
+// client-go we use predates exec credential plugins, so these are
+// read from the raw config.
+type execConfig struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+	Env     []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	} `yaml:"env"`
+}
+
+func execConfigsFromConfig(data []byte) (map[string]*execConfig, error) {
+	var raw struct {
+		Users []struct {
+			Name string `yaml:"name"`
+			User struct {
+				Exec *execConfig `yaml:"exec"`
+			} `yaml:"user"`
+		} `yaml:"users"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Trace(err)
+	}
+	rv := map[string]*execConfig{}
+	for _, user := range raw.Users {
+		if user.User.Exec != nil {
+			rv[user.Name] = user.User.Exec
+		}
+	}
+	return rv, nil
+}
+
+func credentialsFromConfig(config *clientcmdapi.Config, execConfigs map[string]*execConfig, baseDir string) (map[string]cloud.Credential, error) {
 	rv := map[string]cloud.Credential{}
 	for name, user := range config.AuthInfos {
-		var hasCert bool
-		attrs := map[string]string{}
-		if len(user.ClientCertificateData) > 0 {
-			attrs["ClientCertificateData"] = string(user.ClientCertificateData)
-			hasCert = true
-		}
-		if len(user.ClientKeyData) > 0 {
-			attrs["ClientKeyData"] = string(user.ClientKeyData)
+		attrs, err := credentialAttributes(user, execConfigs[name], baseDir)
+		if err != nil {
+			return nil, errors.Annotatef(err, "AuthInfo '%s'", name)
 		}
+		_, hasCert := attrs["ClientCertificateData"]
 
 		var authType cloud.AuthType
-		if user.Token != "" {
+		if attrs[cloud.ExecCommandKey] != "" {
+			authType = cloud.ExecAuthType
+		} else if attrs["Token"] != "" {
 			if user.Username != "" || user.Password != "" {
 				logger.Warningf("invalid AuthInfo: '%s' has both Token and User/Pass: skipping", name)
 				continue
 			}
-			attrs["Token"] = user.Token
 			if hasCert {
 				authType = cloud.OAuth2WithCertAuthType
 			} else {
@@ -110,7 +184,8 @@ func credentialsFromConfig(config *clientcmdapi.Config) (map[string]cloud.Creden
 		} else if hasCert {
 			authType = cloud.CertificateAuthType
 		} else {
-			logger.Warningf("unsupported configuration for AuthInfo '%s'", name)
+			logger.Warningf("unsupported configuration for AuthInfo '%s': skipping", name)
+			continue
 		}
 
 		rv[name] = cloud.NewCredential(authType, attrs)
@@ -118,6 +193,81 @@ func credentialsFromConfig(config *clientcmdapi.Config) (map[string]cloud.Creden
 	return rv, nil
 }
 
+// credentialAttributes returns the client certificate, key and token
+// attributes for the given user, reading any files the user refers to.
+// Users with an exec credential plugin, and no token, instead have the
+// plugin's command recorded, so that it is run whenever a connection
+// to the cluster is made.
+func credentialAttributes(user *clientcmdapi.AuthInfo, execCfg *execConfig, baseDir string) (map[string]string, error) {
+	attrs := map[string]string{}
+	certData, err := dataOrFile(user.ClientCertificateData, user.ClientCertificate, baseDir)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading client certificate")
+	}
+	keyData, err := dataOrFile(user.ClientKeyData, user.ClientKey, baseDir)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading client key")
+	}
+	token := user.Token
+	if token == "" && user.TokenFile != "" {
+		tokenData, err := dataOrFile(nil, user.TokenFile, baseDir)
+		if err != nil {
+			return nil, errors.Annotate(err, "reading token")
+		}
+		token = strings.TrimSpace(string(tokenData))
+	}
+	if execCfg != nil && token == "" {
+		return execCredentialAttributes(execCfg)
+	}
+	if len(certData) > 0 {
+		attrs["ClientCertificateData"] = string(certData)
+	}
+	if len(keyData) > 0 {
+		attrs["ClientKeyData"] = string(keyData)
+	}
+	if token != "" {
+		attrs["Token"] = token
+	}
+	return attrs, nil
+}
+
+// execCredentialAttributes returns the exec credential attributes
+// for the given exec stanza.
+func execCredentialAttributes(cfg *execConfig) (map[string]string, error) {
+	attrs := map[string]string{cloud.ExecCommandKey: cfg.Command}
+	if len(cfg.Args) > 0 {
+		args, err := json.Marshal(cfg.Args)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		attrs[cloud.ExecArgsKey] = string(args)
+	}
+	if len(cfg.Env) > 0 {
+		env := make([]string, len(cfg.Env))
+		for i, v := range cfg.Env {
+			env[i] = v.Name + "=" + v.Value
+		}
+		envData, err := json.Marshal(env)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		attrs[cloud.ExecEnvKey] = string(envData)
+	}
+	return attrs, nil
+}
+
+// dataOrFile returns data if it is not empty, and otherwise the
+// contents of the named file, relative to baseDir, if one is named.
+func dataOrFile(data []byte, path, baseDir string) ([]byte, error) {
+	if len(data) > 0 || path == "" {
+		return data, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return ioutil.ReadFile(path)
+}
+
 func getKubeConfigPath() string {
 	envPath := os.Getenv(clientcmd.RecommendedConfigPathEnvVar)
 	if envPath == "" {
@@ -4,6 +4,7 @@
 package caas_test
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -125,7 +126,7 @@ func (s *k8sConfigSuite) writeTempKubeConfig(c *gc.C, filename string, data stri
 func (s *k8sConfigSuite) TestGetEmptyConfig(c *gc.C) {
 	s.writeTempKubeConfig(c, "emptyConfig", emptyConfig)
 
-	cfg, err := caascfg.K8SClientConfig()
+	cfg, err := caascfg.K8SClientConfig(nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cfg, jc.DeepEquals,
 		&caascfg.ClientConfig{
@@ -140,7 +141,7 @@ func (s *k8sConfigSuite) TestGetEmptyConfig(c *gc.C) {
 func (s *k8sConfigSuite) TestGetSingleConfig(c *gc.C) {
 	s.writeTempKubeConfig(c, "singleConfig", singleConfig)
 
-	cfg, err := caascfg.K8SClientConfig()
+	cfg, err := caascfg.K8SClientConfig(nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cfg, jc.DeepEquals,
 		&caascfg.ClientConfig{
@@ -165,7 +166,7 @@ func (s *k8sConfigSuite) TestGetSingleConfig(c *gc.C) {
 func (s *k8sConfigSuite) TestGetMultiConfig(c *gc.C) {
 	s.writeTempKubeConfig(c, "multiConfig", multiConfig)
 
-	cfg, err := caascfg.K8SClientConfig()
+	cfg, err := caascfg.K8SClientConfig(nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cfg, jc.DeepEquals,
 		&caascfg.ClientConfig{
@@ -206,3 +207,152 @@ func (s *k8sConfigSuite) TestGetMultiConfig(c *gc.C) {
 			},
 		})
 }
+
+func (s *k8sConfigSuite) TestGetConfigFromReader(c *gc.C) {
+	cfg, err := caascfg.K8SClientConfig(bytes.NewBufferString(singleConfig))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.CurrentContext, gc.Equals, "the-context")
+	c.Assert(cfg.Credentials, jc.DeepEquals, map[string]cloud.Credential{
+		"the-user": cloud.NewCredential(
+			cloud.UserPassAuthType,
+			map[string]string{"Username": "theuser", "Password": "thepassword"}),
+	})
+}
+
+func (s *k8sConfigSuite) TestGetConfigFromReaderInvalid(c *gc.C) {
+	_, err := caascfg.K8SClientConfig(bytes.NewBufferString("clusters: 42"))
+	c.Assert(err, gc.ErrorMatches, "failed to parse kubernetes config: .*")
+}
+
+var fileConfig = `
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://1.1.1.1:8888
+  name: the-cluster
+contexts:
+- context:
+    cluster: the-cluster
+    user: cert-user
+  name: the-context
+current-context: the-context
+users:
+- name: cert-user
+  user:
+    client-certificate: client.crt
+    client-key: client.key
+- name: token-user
+  user:
+    tokenFile: token
+- name: unsupported-user
+  user: {}
+`
+
+func (s *k8sConfigSuite) TestGetConfigReadsFiles(c *gc.C) {
+	for name, content := range map[string]string{
+		"client.crt": "A",
+		"client.key": "B",
+		"token":      "atoken\n",
+	} {
+		err := ioutil.WriteFile(filepath.Join(s.dir, name), []byte(content), 0600)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.writeTempKubeConfig(c, "fileConfig", fileConfig)
+
+	cfg, err := caascfg.K8SClientConfig(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Credentials, jc.DeepEquals, map[string]cloud.Credential{
+		"cert-user": cloud.NewCredential(
+			cloud.CertificateAuthType,
+			map[string]string{"ClientCertificateData": "A", "ClientKeyData": "B"}),
+		"token-user": cloud.NewCredential(
+			cloud.OAuth2AuthType,
+			map[string]string{"Token": "atoken"}),
+	})
+}
+
+func (s *k8sConfigSuite) TestGetConfigFromFileReadsRelativeFiles(c *gc.C) {
+	for name, content := range map[string]string{
+		"client.crt": "A",
+		"client.key": "B",
+		"token":      "atoken",
+	} {
+		err := ioutil.WriteFile(filepath.Join(s.dir, name), []byte(content), 0600)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	f, err := os.Open(s.writeTempKubeConfig(c, "fileConfig", fileConfig))
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+
+	cfg, err := caascfg.K8SClientConfig(f)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Credentials, gc.HasLen, 2)
+	c.Assert(cfg.Credentials["token-user"].Attributes()["Token"], gc.Equals, "atoken")
+}
+
+var caFileConfig = `
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://1.1.1.1:8888
+    certificate-authority: ca.crt
+  name: the-cluster
+`
+
+func (s *k8sConfigSuite) TestGetConfigReadsCAFile(c *gc.C) {
+	err := ioutil.WriteFile(filepath.Join(s.dir, "ca.crt"), []byte("C"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	s.writeTempKubeConfig(c, "caFileConfig", caFileConfig)
+
+	cfg, err := caascfg.K8SClientConfig(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Clouds["the-cluster"].Attributes["CAData"], jc.DeepEquals, []byte("C"))
+}
+
+func (s *k8sConfigSuite) TestGetConfigMissingFile(c *gc.C) {
+	s.writeTempKubeConfig(c, "fileConfig", fileConfig)
+	_, err := caascfg.K8SClientConfig(nil)
+	c.Assert(err, gc.ErrorMatches, "failed to read credentials from kubernetes config.: "+
+		"AuthInfo '(cert|token)-user': reading (client certificate|token): .*")
+}
+
+var execConfig = `
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://1.1.1.1:8888
+  name: the-cluster
+contexts:
+- context:
+    cluster: the-cluster
+    user: exec-user
+  name: the-context
+current-context: the-context
+users:
+- name: exec-user
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1alpha1
+      command: get-token
+      args: ["--cluster", "the-cluster"]
+      env:
+      - name: REGION
+        value: north
+`
+
+func (s *k8sConfigSuite) TestGetConfigExec(c *gc.C) {
+	cfg, err := caascfg.K8SClientConfig(bytes.NewBufferString(execConfig))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Credentials, jc.DeepEquals, map[string]cloud.Credential{
+		"exec-user": cloud.NewCredential(
+			cloud.ExecAuthType,
+			map[string]string{
+				"Command": "get-token",
+				"Args":    `["--cluster","the-cluster"]`,
+				"Env":     `["REGION=north"]`,
+			}),
+	})
+}
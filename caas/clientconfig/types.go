@@ -1,6 +1,8 @@
 package caas
 
 import (
+	"io"
+
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/cloud"
@@ -38,7 +40,8 @@ type CloudConfig struct {
 // Cluster_A, User_B: No new Cloud, new Credential for the cloud.
 
 // ClientConfigFunc is a function that returns a ClientConfig. Functions of this type should be available for each supported CAAS framework, e.g. Kubernetes.
-type ClientConfigFunc func() (*ClientConfig, error)
+// The config is read from the given reader or, if it is nil, from the framework's default location.
+type ClientConfigFunc func(io.Reader) (*ClientConfig, error)
 
 // NewClientConfigReader returns a function of type ClientConfigFunc to read the client config for a given cloud type.
 func NewClientConfigReader(cloudType string) (ClientConfigFunc, error) {
@@ -0,0 +1,77 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cloud"
+)
+
+// execCredentialStatus holds the status of the ExecCredential
+// printed by an exec credential plugin.
+type execCredentialStatus struct {
+	Token                 string `json:"token"`
+	ClientCertificateData string `json:"clientCertificateData"`
+	ClientKeyData         string `json:"clientKeyData"`
+}
+
+// runExecCommand runs an exec credential plugin and returns its
+// output. It is patched out in tests.
+var runExecCommand = func(command string, args, env []string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(command, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.Errorf("%v: %s", err, msg)
+		}
+		return nil, errors.Trace(err)
+	}
+	return out, nil
+}
+
+// runExecCredential runs the exec credential plugin described by the
+// given exec credential's attributes, and returns the credential it
+// prints. Plugins commonly return short-lived tokens, so the plugin is
+// run each time a connection to the cluster is made.
+func runExecCredential(credentialAttrs map[string]string) (*execCredentialStatus, error) {
+	command := credentialAttrs[cloud.ExecCommandKey]
+	if command == "" {
+		return nil, errors.NotValidf("exec credential without command")
+	}
+	var args, env []string
+	if v := credentialAttrs[cloud.ExecArgsKey]; v != "" {
+		if err := json.Unmarshal([]byte(v), &args); err != nil {
+			return nil, errors.Annotate(err, "parsing exec credential args")
+		}
+	}
+	if v := credentialAttrs[cloud.ExecEnvKey]; v != "" {
+		if err := json.Unmarshal([]byte(v), &env); err != nil {
+			return nil, errors.Annotate(err, "parsing exec credential env")
+		}
+	}
+	out, err := runExecCommand(command, args, env)
+	if err != nil {
+		return nil, errors.Annotatef(err, "running %q", command)
+	}
+	var execCredential struct {
+		Status *execCredentialStatus `json:"status"`
+	}
+	if err := json.Unmarshal(out, &execCredential); err != nil {
+		return nil, errors.Annotatef(err, "parsing output of %q", command)
+	}
+	if execCredential.Status == nil {
+		return nil, errors.Errorf("%q returned no credential", command)
+	}
+	return execCredential.Status, nil
+}
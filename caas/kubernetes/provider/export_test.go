@@ -0,0 +1,21 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/juju/juju/caas"
+)
+
+var (
+	NewK8sConfig   = newK8sConfig
+	NewK8sClient   = &newK8sClient
+	RunExecCommand = &runExecCommand
+)
+
+// NewK8sBrokerForTest returns a broker using the given client.
+func NewK8sBrokerForTest(client kubernetes.Interface, namespace string) caas.Broker {
+	return &kubernetesClient{Interface: client, namespace: namespace}
+}
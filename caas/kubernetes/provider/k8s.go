@@ -0,0 +1,438 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"k8s.io/api/apps/v1beta1"
+	core "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/status"
+)
+
+var logger = loggo.GetLogger("juju.kubernetes.provider")
+
+const (
+	// CloudType is the type of cloud handled by this provider.
+	CloudType = "kubernetes"
+
+	labelApplication = "juju-application"
+
+	// annotationUnit is the pod annotation recording the name of
+	// the juju unit the pod has been assigned to. Unit names are
+	// not valid label values, so an annotation is used instead.
+	annotationUnit = "juju-unit"
+)
+
+type kubernetesClient struct {
+	kubernetes.Interface
+
+	// namespace is the k8s namespace to use when
+	// creating k8s resources.
+	namespace string
+}
+
+// newK8sClient is patched out in tests.
+var newK8sClient = func(c *rest.Config) (kubernetes.Interface, error) {
+	return kubernetes.NewForConfig(c)
+}
+
+// NewK8sBroker returns a kubernetes client for the specified k8s cluster,
+// which operates on resources in the given namespace.
+func NewK8sBroker(cloudSpec environs.CloudSpec, namespace string) (caas.Broker, error) {
+	config, err := newK8sConfig(cloudSpec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	client, err := newK8sClient(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &kubernetesClient{Interface: client, namespace: namespace}, nil
+}
+
+// Ping checks that the k8s cluster described by the given cloud spec
+// can be reached and that it accepts the spec's credential.
+func Ping(cloudSpec environs.CloudSpec) error {
+	config, err := newK8sConfig(cloudSpec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := newK8sClient(config)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := client.Discovery().ServerVersion(); err != nil {
+		return errors.Annotatef(err, "cannot connect to k8s cluster at %q", cloudSpec.Endpoint)
+	}
+	return nil
+}
+
+func newK8sConfig(cloudSpec environs.CloudSpec) (*rest.Config, error) {
+	if cloudSpec.Type != CloudType {
+		return nil, errors.NotValidf("cloud type %q", cloudSpec.Type)
+	}
+	if cloudSpec.Endpoint == "" {
+		return nil, errors.NotValidf("empty endpoint")
+	}
+	if cloudSpec.Credential == nil {
+		return nil, errors.NotValidf("missing credential")
+	}
+	credentialAttrs := cloudSpec.Credential.Attributes()
+	config := &rest.Config{
+		Host:        cloudSpec.Endpoint,
+		Username:    credentialAttrs["Username"],
+		Password:    credentialAttrs["Password"],
+		BearerToken: credentialAttrs["Token"],
+		TLSClientConfig: rest.TLSClientConfig{
+			CertData: []byte(credentialAttrs["ClientCertificateData"]),
+			KeyData:  []byte(credentialAttrs["ClientKeyData"]),
+			// The cluster's certificate is verified against the
+			// cloud's CA certificates if it has any, and otherwise
+			// against the system's trusted roots.
+			CAData: []byte(strings.Join(cloudSpec.CACertificates, "\n")),
+		},
+	}
+	switch authType := cloudSpec.Credential.AuthType(); authType {
+	case cloud.CertificateAuthType, cloud.OAuth2AuthType, cloud.OAuth2WithCertAuthType,
+		cloud.UserPassAuthType, cloud.UserPassWithCertAuthType:
+	case cloud.ExecAuthType:
+		status, err := runExecCredential(credentialAttrs)
+		if err != nil {
+			return nil, errors.Annotate(err, "getting exec credential")
+		}
+		config.BearerToken = status.Token
+		config.CertData = []byte(status.ClientCertificateData)
+		config.KeyData = []byte(status.ClientKeyData)
+	default:
+		return nil, errors.NotSupportedf("auth type %q", authType)
+	}
+	return config, nil
+}
+
+// EnsureNamespace ensures this broker's namespace is created.
+func (k *kubernetesClient) EnsureNamespace() error {
+	ns := &core.Namespace{ObjectMeta: v1.ObjectMeta{Name: k.namespace}}
+	_, err := k.CoreV1().Namespaces().Create(ns)
+	if k8serrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// EnsureService creates or updates a service for pods with the given
+// params. Applications without persistent storage are run as a
+// Deployment; those with storage are run as a StatefulSet so that each
+// unit retains its volume claims across restarts.
+func (k *kubernetesClient) EnsureService(appName string, params *caas.ServiceParams, numUnits int) error {
+	if params == nil || params.PodSpec == nil {
+		return errors.Errorf("missing pod spec for application %q", appName)
+	}
+	if err := params.PodSpec.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	replicas := int32(numUnits)
+	podSpec := makePodSpec(params.PodSpec)
+	if len(params.Filesystems) == 0 {
+		if err := k.ensureDeployment(appName, podSpec, replicas); err != nil {
+			return errors.Annotate(err, "creating or updating deployment")
+		}
+	} else {
+		if err := k.ensureStatefulSet(appName, podSpec, params.Filesystems, replicas); err != nil {
+			return errors.Annotate(err, "creating or updating stateful set")
+		}
+	}
+
+	ports := servicePorts(params.PodSpec)
+	if len(ports) == 0 {
+		logger.Debugf("application %q exposes no ports, not creating a service", appName)
+		return nil
+	}
+	service := &core.Service{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   appName,
+			Labels: map[string]string{labelApplication: appName},
+		},
+		Spec: core.ServiceSpec{
+			Selector: map[string]string{labelApplication: appName},
+			Ports:    ports,
+		},
+	}
+	return errors.Annotate(k.ensureK8sService(service), "creating or updating service")
+}
+
+func (k *kubernetesClient) ensureDeployment(appName string, podSpec core.PodSpec, replicas int32) error {
+	deployment := &v1beta1.Deployment{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   appName,
+			Labels: map[string]string{labelApplication: appName},
+		},
+		Spec: v1beta1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &v1.LabelSelector{
+				MatchLabels: map[string]string{labelApplication: appName},
+			},
+			Template: core.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{
+					Labels: map[string]string{labelApplication: appName},
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+	deployments := k.AppsV1beta1().Deployments(k.namespace)
+	_, err := deployments.Create(deployment)
+	if k8serrors.IsAlreadyExists(err) {
+		_, err = deployments.Update(deployment)
+	}
+	return errors.Trace(err)
+}
+
+func (k *kubernetesClient) ensureStatefulSet(
+	appName string, podSpec core.PodSpec, filesystems []caas.FilesystemParams, replicas int32,
+) error {
+	var claims []core.PersistentVolumeClaim
+	for _, fs := range filesystems {
+		name := volumeClaimName(appName, fs.StorageName)
+		size, err := resource.ParseQuantity(fmt.Sprintf("%dMi", fs.Size))
+		if err != nil {
+			return errors.Annotatef(err, "invalid size %d for filesystem %q", fs.Size, fs.StorageName)
+		}
+		claims = append(claims, core.PersistentVolumeClaim{
+			ObjectMeta: v1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{labelApplication: appName},
+			},
+			Spec: core.PersistentVolumeClaimSpec{
+				AccessModes: []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+				Resources: core.ResourceRequirements{
+					Requests: core.ResourceList{core.ResourceStorage: size},
+				},
+			},
+		})
+		for i := range podSpec.Containers {
+			podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, core.VolumeMount{
+				Name:      name,
+				MountPath: fs.Location,
+			})
+		}
+	}
+	statefulSet := &v1beta1.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   appName,
+			Labels: map[string]string{labelApplication: appName},
+		},
+		Spec: v1beta1.StatefulSetSpec{
+			Replicas: &replicas,
+			Selector: &v1.LabelSelector{
+				MatchLabels: map[string]string{labelApplication: appName},
+			},
+			Template: core.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{
+					Labels: map[string]string{labelApplication: appName},
+				},
+				Spec: podSpec,
+			},
+			VolumeClaimTemplates: claims,
+			ServiceName:          appName,
+		},
+	}
+	statefulSets := k.AppsV1beta1().StatefulSets(k.namespace)
+	_, err := statefulSets.Create(statefulSet)
+	if k8serrors.IsAlreadyExists(err) {
+		_, err = statefulSets.Update(statefulSet)
+	}
+	return errors.Trace(err)
+}
+
+func (k *kubernetesClient) ensureK8sService(spec *core.Service) error {
+	services := k.CoreV1().Services(k.namespace)
+	existing, err := services.Get(spec.Name, v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = services.Create(spec)
+		return errors.Trace(err)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The cluster IP is immutable once allocated.
+	spec.Spec.ClusterIP = existing.Spec.ClusterIP
+	spec.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
+	_, err = services.Update(spec)
+	return errors.Trace(err)
+}
+
+// DeleteService deletes the specified service, along with any
+// pods and volume claims belonging to it.
+func (k *kubernetesClient) DeleteService(appName string) error {
+	orphanDependents := false
+	options := &v1.DeleteOptions{OrphanDependents: &orphanDependents}
+	if err := k.CoreV1().Services(k.namespace).Delete(appName, options); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Annotate(err, "deleting service")
+	}
+	if err := k.AppsV1beta1().Deployments(k.namespace).Delete(appName, options); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Annotate(err, "deleting deployment")
+	}
+	if err := k.AppsV1beta1().StatefulSets(k.namespace).Delete(appName, options); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Annotate(err, "deleting stateful set")
+	}
+
+	pvcs := k.CoreV1().PersistentVolumeClaims(k.namespace)
+	claims, err := pvcs.List(v1.ListOptions{LabelSelector: applicationSelector(appName)})
+	if err != nil {
+		return errors.Annotate(err, "listing volume claims")
+	}
+	for _, claim := range claims.Items {
+		if err := pvcs.Delete(claim.Name, options); err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Annotatef(err, "deleting volume claim %q", claim.Name)
+		}
+	}
+	return nil
+}
+
+// Units returns all units belonging to the specified application,
+// ordered by creation time.
+func (k *kubernetesClient) Units(appName string) ([]caas.Unit, error) {
+	pods, err := k.CoreV1().Pods(k.namespace).List(v1.ListOptions{
+		LabelSelector: applicationSelector(appName),
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	items := pods.Items
+	sort.Slice(items, func(i, j int) bool {
+		ti, tj := items[i].CreationTimestamp, items[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return items[i].Name < items[j].Name
+	})
+	var units []caas.Unit
+	for _, p := range items {
+		units = append(units, caas.Unit{
+			Id:       string(p.UID),
+			UnitName: p.Annotations[annotationUnit],
+			Address:  p.Status.PodIP,
+			Status: status.StatusInfo{
+				Status:  podPhaseStatus(p.Status.Phase),
+				Message: p.Status.Message,
+			},
+		})
+	}
+	return units, nil
+}
+
+// AssignUnit records that the pod with the given id belongs to the
+// named juju unit, so that the pod's status is reported against that
+// unit for as long as the pod exists.
+func (k *kubernetesClient) AssignUnit(appName, id, unitName string) error {
+	pods := k.CoreV1().Pods(k.namespace)
+	list, err := pods.List(v1.ListOptions{LabelSelector: applicationSelector(appName)})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, p := range list.Items {
+		if string(p.UID) != id {
+			continue
+		}
+		if p.Annotations == nil {
+			p.Annotations = make(map[string]string)
+		}
+		p.Annotations[annotationUnit] = unitName
+		_, err := pods.Update(&p)
+		if k8serrors.IsNotFound(err) {
+			return errors.NotFoundf("pod %q", id)
+		}
+		return errors.Trace(err)
+	}
+	return errors.NotFoundf("pod %q", id)
+}
+
+func podPhaseStatus(phase core.PodPhase) status.Status {
+	switch phase {
+	case core.PodPending:
+		return status.Waiting
+	case core.PodRunning:
+		return status.Running
+	case core.PodSucceeded:
+		return status.Terminated
+	case core.PodFailed:
+		return status.Error
+	default:
+		return status.Unknown
+	}
+}
+
+func makePodSpec(spec *caas.PodSpec) core.PodSpec {
+	var containers []core.Container
+	for _, c := range spec.Containers {
+		container := core.Container{
+			Name:  c.Name,
+			Image: c.Image,
+		}
+		for _, p := range c.Ports {
+			container.Ports = append(container.Ports, core.ContainerPort{
+				Name:          p.Name,
+				ContainerPort: p.ContainerPort,
+				Protocol:      core.Protocol(p.Protocol),
+			})
+		}
+		// Sort the config so that the pod template is stable,
+		// and updates do not cause needless pod restarts.
+		keys := make([]string, 0, len(c.Config))
+		for key := range c.Config {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			container.Env = append(container.Env, core.EnvVar{
+				Name:  key,
+				Value: c.Config[key],
+			})
+		}
+		containers = append(containers, container)
+	}
+	return core.PodSpec{Containers: containers}
+}
+
+func servicePorts(spec *caas.PodSpec) []core.ServicePort {
+	var ports []core.ServicePort
+	for _, c := range spec.Containers {
+		for _, p := range c.Ports {
+			name := p.Name
+			if name == "" {
+				name = fmt.Sprintf("%s-%d", c.Name, p.ContainerPort)
+			}
+			ports = append(ports, core.ServicePort{
+				Name:       strings.ToLower(name),
+				Port:       p.ContainerPort,
+				TargetPort: intstr.FromInt(int(p.ContainerPort)),
+				Protocol:   core.Protocol(p.Protocol),
+			})
+		}
+	}
+	return ports
+}
+
+func volumeClaimName(appName, storageName string) string {
+	return fmt.Sprintf("%s-%s", appName, storageName)
+}
+
+func applicationSelector(appName string) string {
+	return labelApplication + "==" + appName
+}
@@ -0,0 +1,362 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/caas/kubernetes/provider"
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/status"
+)
+
+type K8sSuite struct {
+	testing.IsolationSuite
+
+	client *fake.Clientset
+	broker caas.Broker
+}
+
+var _ = gc.Suite(&K8sSuite{})
+
+func (s *K8sSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.client = fake.NewSimpleClientset()
+	s.broker = provider.NewK8sBrokerForTest(s.client, "test")
+}
+
+var basicPodSpec = &caas.PodSpec{
+	Containers: []caas.ContainerSpec{{
+		Name:  "gitlab",
+		Image: "gitlab/latest",
+		Ports: []caas.ContainerPort{
+			{ContainerPort: 80, Protocol: "TCP"},
+		},
+		Config: map[string]string{
+			"restricted": "yes",
+			"attr":       "foo=bar",
+		},
+	}},
+}
+
+func (s *K8sSuite) TestNewK8sConfig(c *gc.C) {
+	cred := cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		"Username": "fred",
+		"Password": "secret",
+	})
+	config, err := provider.NewK8sConfig(environs.CloudSpec{
+		Type:           "kubernetes",
+		Endpoint:       "https://10.0.0.1:8443",
+		Credential:     &cred,
+		CACertificates: []string{"ca-cert-1", "ca-cert-2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config.Host, gc.Equals, "https://10.0.0.1:8443")
+	c.Assert(config.Username, gc.Equals, "fred")
+	c.Assert(config.Password, gc.Equals, "secret")
+	c.Assert(config.Insecure, jc.IsFalse)
+	c.Assert(string(config.CAData), gc.Equals, "ca-cert-1\nca-cert-2")
+}
+
+func (s *K8sSuite) TestNewK8sConfigInvalid(c *gc.C) {
+	cred := cloud.NewCredential(cloud.AccessKeyAuthType, nil)
+	_, err := provider.NewK8sConfig(environs.CloudSpec{Type: "ec2"})
+	c.Assert(err, gc.ErrorMatches, `cloud type "ec2" not valid`)
+	_, err = provider.NewK8sConfig(environs.CloudSpec{Type: "kubernetes"})
+	c.Assert(err, gc.ErrorMatches, `empty endpoint not valid`)
+	_, err = provider.NewK8sConfig(environs.CloudSpec{Type: "kubernetes", Endpoint: "host"})
+	c.Assert(err, gc.ErrorMatches, `missing credential not valid`)
+	_, err = provider.NewK8sConfig(environs.CloudSpec{Type: "kubernetes", Endpoint: "host", Credential: &cred})
+	c.Assert(err, gc.ErrorMatches, `auth type "access-key" not supported`)
+}
+
+func (s *K8sSuite) TestNewK8sConfigExec(c *gc.C) {
+	s.PatchValue(provider.RunExecCommand, func(command string, args, env []string) ([]byte, error) {
+		c.Check(command, gc.Equals, "get-token")
+		c.Check(args, jc.DeepEquals, []string{"--cluster", "the-cluster"})
+		c.Check(env, jc.DeepEquals, []string{"REGION=north"})
+		return []byte(`{"kind": "ExecCredential", "status": {"token": "exectoken"}}`), nil
+	})
+	cred := cloud.NewCredential(cloud.ExecAuthType, map[string]string{
+		"Command": "get-token",
+		"Args":    `["--cluster","the-cluster"]`,
+		"Env":     `["REGION=north"]`,
+	})
+	config, err := provider.NewK8sConfig(environs.CloudSpec{
+		Type:       "kubernetes",
+		Endpoint:   "https://10.0.0.1:8443",
+		Credential: &cred,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config.BearerToken, gc.Equals, "exectoken")
+}
+
+func (s *K8sSuite) TestNewK8sConfigExecError(c *gc.C) {
+	s.PatchValue(provider.RunExecCommand, func(command string, args, env []string) ([]byte, error) {
+		return nil, errors.New("not logged in")
+	})
+	cred := cloud.NewCredential(cloud.ExecAuthType, map[string]string{"Command": "get-token"})
+	_, err := provider.NewK8sConfig(environs.CloudSpec{
+		Type:       "kubernetes",
+		Endpoint:   "https://10.0.0.1:8443",
+		Credential: &cred,
+	})
+	c.Assert(err, gc.ErrorMatches, `getting exec credential: running "get-token": not logged in`)
+}
+
+func (s *K8sSuite) TestRunExecCommandStderr(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("the exec command is a shell script")
+	}
+	_, err := (*provider.RunExecCommand)("sh", []string{"-c", "echo not logged in >&2; exit 1"}, nil)
+	c.Assert(err, gc.ErrorMatches, `exit status 1: not logged in`)
+}
+
+func (s *K8sSuite) TestNewK8sBroker(c *gc.C) {
+	var config *rest.Config
+	s.PatchValue(provider.NewK8sClient, func(c *rest.Config) (kubernetes.Interface, error) {
+		config = c
+		return s.client, nil
+	})
+	cred := cloud.NewCredential(cloud.OAuth2AuthType, map[string]string{"Token": "token"})
+	broker, err := provider.NewK8sBroker(environs.CloudSpec{
+		Type:       "kubernetes",
+		Endpoint:   "https://10.0.0.1:8443",
+		Credential: &cred,
+	}, "test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(broker, gc.NotNil)
+	c.Assert(config.BearerToken, gc.Equals, "token")
+}
+
+func (s *K8sSuite) TestPing(c *gc.C) {
+	s.PatchValue(provider.NewK8sClient, func(*rest.Config) (kubernetes.Interface, error) {
+		return s.client, nil
+	})
+	cred := cloud.NewCredential(cloud.OAuth2AuthType, map[string]string{"Token": "token"})
+	err := provider.Ping(environs.CloudSpec{
+		Type:       "kubernetes",
+		Endpoint:   "https://10.0.0.1:8443",
+		Credential: &cred,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *K8sSuite) TestPingInvalid(c *gc.C) {
+	err := provider.Ping(environs.CloudSpec{Type: "kubernetes"})
+	c.Assert(err, gc.ErrorMatches, `empty endpoint not valid`)
+}
+
+func (s *K8sSuite) TestPingClientError(c *gc.C) {
+	s.PatchValue(provider.NewK8sClient, func(*rest.Config) (kubernetes.Interface, error) {
+		return nil, errors.New("boom")
+	})
+	cred := cloud.NewCredential(cloud.OAuth2AuthType, map[string]string{"Token": "token"})
+	err := provider.Ping(environs.CloudSpec{
+		Type:       "kubernetes",
+		Endpoint:   "https://10.0.0.1:8443",
+		Credential: &cred,
+	})
+	c.Assert(err, gc.ErrorMatches, `boom`)
+}
+
+func (s *K8sSuite) TestEnsureNamespace(c *gc.C) {
+	err := s.broker.EnsureNamespace()
+	c.Assert(err, jc.ErrorIsNil)
+	ns, err := s.client.CoreV1().Namespaces().Get("test", v1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ns.Name, gc.Equals, "test")
+
+	// Idempotent.
+	err = s.broker.EnsureNamespace()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *K8sSuite) TestEnsureServiceNoSpec(c *gc.C) {
+	err := s.broker.EnsureService("gitlab", &caas.ServiceParams{}, 1)
+	c.Assert(err, gc.ErrorMatches, `missing pod spec for application "gitlab"`)
+}
+
+func (s *K8sSuite) TestEnsureServiceDeployment(c *gc.C) {
+	err := s.broker.EnsureService("gitlab", &caas.ServiceParams{PodSpec: basicPodSpec}, 2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	deployment, err := s.client.AppsV1beta1().Deployments("test").Get("gitlab", v1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*deployment.Spec.Replicas, gc.Equals, int32(2))
+	c.Assert(deployment.Spec.Template.Labels, jc.DeepEquals, map[string]string{"juju-application": "gitlab"})
+	c.Assert(deployment.Spec.Template.Spec.Containers, jc.DeepEquals, []core.Container{{
+		Name:  "gitlab",
+		Image: "gitlab/latest",
+		Ports: []core.ContainerPort{{ContainerPort: 80, Protocol: core.ProtocolTCP}},
+		Env: []core.EnvVar{
+			{Name: "attr", Value: "foo=bar"},
+			{Name: "restricted", Value: "yes"},
+		},
+	}})
+
+	service, err := s.client.CoreV1().Services("test").Get("gitlab", v1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.Spec.Selector, jc.DeepEquals, map[string]string{"juju-application": "gitlab"})
+	c.Assert(service.Spec.Ports, gc.HasLen, 1)
+	c.Assert(service.Spec.Ports[0].Name, gc.Equals, "gitlab-80")
+	c.Assert(service.Spec.Ports[0].Port, gc.Equals, int32(80))
+
+	_, err = s.client.AppsV1beta1().StatefulSets("test").Get("gitlab", v1.GetOptions{})
+	c.Assert(err, gc.ErrorMatches, `.*not found`)
+}
+
+func (s *K8sSuite) TestEnsureServiceScales(c *gc.C) {
+	err := s.broker.EnsureService("gitlab", &caas.ServiceParams{PodSpec: basicPodSpec}, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.broker.EnsureService("gitlab", &caas.ServiceParams{PodSpec: basicPodSpec}, 3)
+	c.Assert(err, jc.ErrorIsNil)
+
+	deployment, err := s.client.AppsV1beta1().Deployments("test").Get("gitlab", v1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*deployment.Spec.Replicas, gc.Equals, int32(3))
+}
+
+func (s *K8sSuite) TestEnsureServiceNoPorts(c *gc.C) {
+	spec := &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{Name: "worker", Image: "worker/latest"}},
+	}
+	err := s.broker.EnsureService("worker", &caas.ServiceParams{PodSpec: spec}, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.client.CoreV1().Services("test").Get("worker", v1.GetOptions{})
+	c.Assert(err, gc.ErrorMatches, `.*not found`)
+}
+
+func (s *K8sSuite) TestEnsureServiceStatefulSet(c *gc.C) {
+	err := s.broker.EnsureService("gitlab", &caas.ServiceParams{
+		PodSpec: basicPodSpec,
+		Filesystems: []caas.FilesystemParams{{
+			StorageName: "data",
+			Size:        1024,
+			Location:    "/var/lib/gitlab",
+		}},
+	}, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	statefulSet, err := s.client.AppsV1beta1().StatefulSets("test").Get("gitlab", v1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*statefulSet.Spec.Replicas, gc.Equals, int32(1))
+	c.Assert(statefulSet.Spec.ServiceName, gc.Equals, "gitlab")
+	c.Assert(statefulSet.Spec.VolumeClaimTemplates, gc.HasLen, 1)
+	claim := statefulSet.Spec.VolumeClaimTemplates[0]
+	c.Assert(claim.Name, gc.Equals, "gitlab-data")
+	c.Assert(claim.Spec.AccessModes, jc.DeepEquals, []core.PersistentVolumeAccessMode{core.ReadWriteOnce})
+	c.Assert(claim.Spec.Resources.Requests[core.ResourceStorage], jc.DeepEquals, resource.MustParse("1024Mi"))
+	c.Assert(statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts, jc.DeepEquals, []core.VolumeMount{{
+		Name:      "gitlab-data",
+		MountPath: "/var/lib/gitlab",
+	}})
+
+	_, err = s.client.AppsV1beta1().Deployments("test").Get("gitlab", v1.GetOptions{})
+	c.Assert(err, gc.ErrorMatches, `.*not found`)
+}
+
+func (s *K8sSuite) TestDeleteService(c *gc.C) {
+	err := s.broker.EnsureService("gitlab", &caas.ServiceParams{PodSpec: basicPodSpec}, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.client.CoreV1().PersistentVolumeClaims("test").Create(&core.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   "gitlab-data-gitlab-0",
+			Labels: map[string]string{"juju-application": "gitlab"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.broker.DeleteService("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.client.CoreV1().Services("test").Get("gitlab", v1.GetOptions{})
+	c.Assert(err, gc.ErrorMatches, `.*not found`)
+	_, err = s.client.AppsV1beta1().Deployments("test").Get("gitlab", v1.GetOptions{})
+	c.Assert(err, gc.ErrorMatches, `.*not found`)
+	claims, err := s.client.CoreV1().PersistentVolumeClaims("test").List(v1.ListOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(claims.Items, gc.HasLen, 0)
+
+	// Deleting again is not an error.
+	err = s.broker.DeleteService("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *K8sSuite) addPod(c *gc.C, name string, created time.Time, phase core.PodPhase) {
+	_, err := s.client.CoreV1().Pods("test").Create(&core.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:              name,
+			UID:               k8stypes.UID("uid-" + name),
+			Labels:            map[string]string{"juju-application": "gitlab"},
+			CreationTimestamp: v1.NewTime(created),
+		},
+		Status: core.PodStatus{
+			Phase:   phase,
+			PodIP:   "10.1.1." + name[len(name)-1:],
+			Message: string(phase),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *K8sSuite) TestUnits(c *gc.C) {
+	now := time.Now()
+	s.addPod(c, "gitlab-2", now.Add(time.Minute), core.PodPending)
+	s.addPod(c, "gitlab-1", now, core.PodRunning)
+	s.addPod(c, "gitlab-3", now.Add(time.Minute), core.PodFailed)
+	_, err := s.client.CoreV1().Pods("test").Create(&core.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   "mysql-1",
+			Labels: map[string]string{"juju-application": "mysql"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	units, err := s.broker.Units("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []caas.Unit{{
+		Id:      "uid-gitlab-1",
+		Address: "10.1.1.1",
+		Status:  status.StatusInfo{Status: status.Running, Message: "Running"},
+	}, {
+		Id:      "uid-gitlab-2",
+		Address: "10.1.1.2",
+		Status:  status.StatusInfo{Status: status.Waiting, Message: "Pending"},
+	}, {
+		Id:      "uid-gitlab-3",
+		Address: "10.1.1.3",
+		Status:  status.StatusInfo{Status: status.Error, Message: "Failed"},
+	}})
+}
+
+func (s *K8sSuite) TestAssignUnit(c *gc.C) {
+	s.addPod(c, "gitlab-1", time.Now(), core.PodRunning)
+
+	err := s.broker.AssignUnit("gitlab", "uid-gitlab-1", "gitlab/0")
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := s.broker.Units("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+	c.Assert(units[0].UnitName, gc.Equals, "gitlab/0")
+
+	err = s.broker.AssignUnit("gitlab", "uid-gitlab-2", "gitlab/1")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
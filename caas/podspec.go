@@ -0,0 +1,64 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ContainerPort defines a port on a container.
+type ContainerPort struct {
+	Name          string `yaml:"name,omitempty"`
+	ContainerPort int32  `yaml:"containerPort"`
+	Protocol      string `yaml:"protocol,omitempty"`
+}
+
+// ContainerSpec defines the data values used to configure
+// a container on the CAAS substrate.
+type ContainerSpec struct {
+	Name   string            `yaml:"name"`
+	Image  string            `yaml:"image"`
+	Ports  []ContainerPort   `yaml:"ports,omitempty"`
+	Config map[string]string `yaml:"config,omitempty"`
+}
+
+// PodSpec defines the data values used to configure
+// a pod on the CAAS substrate.
+type PodSpec struct {
+	Containers []ContainerSpec `yaml:"containers"`
+}
+
+// Validate returns an error if the spec is not valid.
+func (spec *PodSpec) Validate() error {
+	if len(spec.Containers) == 0 {
+		return errors.New("require at least one container spec")
+	}
+	names := make(map[string]bool)
+	for _, c := range spec.Containers {
+		if c.Name == "" {
+			return errors.New("spec name is missing")
+		}
+		if c.Image == "" {
+			return errors.Errorf("spec image for container %q is missing", c.Name)
+		}
+		if names[c.Name] {
+			return errors.Errorf("duplicate container name %q", c.Name)
+		}
+		names[c.Name] = true
+	}
+	return nil
+}
+
+// ParsePodSpec parses a YAML pod spec and validates it.
+func ParsePodSpec(in string) (*PodSpec, error) {
+	var spec PodSpec
+	if err := yaml.Unmarshal([]byte(in), &spec); err != nil {
+		return nil, errors.Annotate(err, "parsing pod spec")
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &spec, nil
+}
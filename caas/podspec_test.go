@@ -0,0 +1,72 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/caas"
+)
+
+type podSpecSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&podSpecSuite{})
+
+func (s *podSpecSuite) TestParse(c *gc.C) {
+	spec, err := caas.ParsePodSpec(`
+containers:
+  - name: gitlab
+    image: gitlab/latest
+    ports:
+      - containerPort: 80
+        protocol: TCP
+      - containerPort: 443
+    config:
+      attr: foo=bar; fred=blogs
+`[1:])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:  "gitlab",
+			Image: "gitlab/latest",
+			Ports: []caas.ContainerPort{
+				{ContainerPort: 80, Protocol: "TCP"},
+				{ContainerPort: 443},
+			},
+			Config: map[string]string{"attr": "foo=bar; fred=blogs"},
+		}},
+	})
+}
+
+func (s *podSpecSuite) TestParseInvalidYAML(c *gc.C) {
+	_, err := caas.ParsePodSpec("containers: [")
+	c.Assert(err, gc.ErrorMatches, "parsing pod spec: .*")
+}
+
+func (s *podSpecSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		spec string
+		err  string
+	}{{
+		spec: "containers: []",
+		err:  "require at least one container spec",
+	}, {
+		spec: "containers: [{image: foo}]",
+		err:  "spec name is missing",
+	}, {
+		spec: "containers: [{name: foo}]",
+		err:  `spec image for container "foo" is missing`,
+	}, {
+		spec: "containers: [{name: foo, image: foo}, {name: foo, image: bar}]",
+		err:  `duplicate container name "foo"`,
+	}} {
+		c.Logf("test %d: %s", i, test.spec)
+		_, err := caas.ParsePodSpec(test.spec)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -62,6 +62,27 @@ const (
 	// that require no credentials, e.g. "lxd", and "manual".
 	EmptyAuthType AuthType = "empty"
 
+	// ExecAuthType is an authentication type where a command is run to
+	// obtain a credential each time one is needed, e.g. a Kubernetes
+	// exec credential plugin. The command, and its arguments and
+	// environment, are held in the ExecCommandKey, ExecArgsKey and
+	// ExecEnvKey credential attributes.
+	ExecAuthType AuthType = "exec"
+
+	// ExecCommandKey is the name of the credential attribute that
+	// holds the command run for an exec credential.
+	ExecCommandKey = "Command"
+
+	// ExecArgsKey is the name of the credential attribute that holds
+	// the JSON encoded list of arguments for an exec credential's
+	// command.
+	ExecArgsKey = "Args"
+
+	// ExecEnvKey is the name of the credential attribute that holds
+	// the JSON encoded list of NAME=VALUE environment variables set
+	// for an exec credential's command.
+	ExecEnvKey = "Env"
+
 	// AuthTypesKey is the name of the key in a cloud config or cloud schema
 	// that holds the cloud's auth types.
 	AuthTypesKey = "auth-types"
@@ -114,6 +135,12 @@ type Cloud struct {
 	// regions, may be overridden by a region.
 	StorageEndpoint string
 
+	// CACertificates contains an optional list of Certificate
+	// Authority certificates to be used to validate certificates
+	// of cloud infrastructure components.
+	// The contents are Base64 encoded x.509 certs.
+	CACertificates []string
+
 	// Regions are the regions available in the cloud.
 	//
 	// Regions is a slice, and not a map, because order is important.
@@ -171,6 +198,7 @@ type cloud struct {
 	Regions          regions                `yaml:"regions,omitempty"`
 	Config           map[string]interface{} `yaml:"config,omitempty"`
 	RegionConfig     RegionConfig           `yaml:"region-config,omitempty"`
+	CACertificates   []string               `yaml:"ca-certificates,omitempty"`
 }
 
 // regions is a collection of regions, either as a map and/or
@@ -411,6 +439,7 @@ func cloudToInternal(in Cloud, withName bool) *cloud {
 		Regions:          regions,
 		Config:           in.Config,
 		RegionConfig:     in.RegionConfig,
+		CACertificates:   in.CACertificates,
 	}
 }
 
@@ -445,6 +474,7 @@ func cloudFromInternal(in *cloud) Cloud {
 		Config:           in.Config,
 		RegionConfig:     in.RegionConfig,
 		Description:      in.Description,
+		CACertificates:   in.CACertificates,
 	}
 	meta.denormaliseMetadata()
 	return meta
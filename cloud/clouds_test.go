@@ -301,6 +301,25 @@ endpoint: qux
 `[1:])
 }
 
+func (s *cloudSuite) TestMarshalCloudCACertificates(c *gc.C) {
+	in := cloud.Cloud{
+		Name:           "foo",
+		Type:           "bar",
+		CACertificates: []string{"cert"},
+	}
+	marshalled, err := cloud.MarshalCloud(in)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(marshalled), gc.Equals, `
+name: foo
+type: bar
+ca-certificates:
+- cert
+`[1:])
+	out, err := cloud.UnmarshalCloud(marshalled)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.DeepEquals, in)
+}
+
 func (s *cloudSuite) TestUnmarshalCloud(c *gc.C) {
 	in := []byte(`
 name: foo
@@ -105,6 +105,10 @@ type InstanceConfig struct {
 	// cloud config for the instance. If this is not set, hostname uses the default.
 	MachineContainerHostname string
 
+	// CharmLXDProfiles holds the names of the charm LXD profiles to
+	// apply to the instance, if it is an LXD container.
+	CharmLXDProfiles []string
+
 	// AuthorizedKeys specifies the keys that are allowed to
 	// connect to the instance (see cloudinit.SSHAddAuthorizedKeys)
 	// If no keys are supplied, there can be no ssh access to the node.
@@ -52,12 +52,12 @@ func (c *listCommand) SetFlags(f *gnuflag.FlagSet) {
 	// default which serves to indicate that the user wants default
 	// formatting behavior. This allows us to select the appropriate default
 	// behavior in the presence of the "default" format value.
-	c.out.AddFlags(f, "default", map[string]cmd.Formatter{
+	c.out.AddFlags(f, "default", output.AddReportFormatters(f, map[string]cmd.Formatter{
 		"yaml":    cmd.FormatYaml,
 		"json":    cmd.FormatJson,
 		"tabular": c.printTabular,
 		"default": c.dummyDefault,
-	})
+	}))
 	f.BoolVar(&c.fullSchema, "schema", false, "Display the full action schema")
 }
 
@@ -131,7 +131,7 @@ func (c *listCommand) Run(ctx *cmd.Context) error {
 
 	var output interface{}
 	switch c.out.Name() {
-	case "yaml", "json":
+	case "yaml", "json", "csv", "markdown", "template":
 		output = shortOutput
 	default:
 		if len(sortedNames) == 0 {
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/cmd"
@@ -33,6 +34,17 @@ Output includes the name of the charm used to deploy the application and a
 listing of the application-specific configuration settings.
 See ` + "`juju status`" + ` for application names.
 
+The --update-status-interval option sets how often the update-status
+hook runs for the application's units, overriding the model's
+update-status-hook-interval setting. An interval of 0 reverts to the
+model's setting.
+
+The --hook-timeout option sets how long the application's hooks may run
+before they are killed, overriding any timeout declared by the charm.
+A timeout of 0 reverts to the charm's timeout. A hook that times out
+fails, and is retried if the model's automatically-retry-hooks setting
+is true, unless --retry-timed-out-hooks=false is also given.
+
 Examples:
     juju config apache2
     juju config --format=json apache2
@@ -41,6 +53,8 @@ Examples:
     juju config apache2 --file path/to/config.yaml
     juju config mysql dataset-size=80% backup_dir=/vol1/mysql/backups
     juju config apache2 --model mymodel --file /home/ubuntu/mysql.yaml
+    juju config mysql --update-status-interval 15m
+    juju config mysql --hook-timeout 30m --retry-timed-out-hooks=false
 
 See also:
     deploy
@@ -77,6 +91,13 @@ type configCommand struct {
 	resetKeys       []string // Holds the keys to be reset once parsed.
 	useFile         bool
 	values          attributes
+
+	updateStatusInterval    string
+	newUpdateStatusInterval time.Duration
+
+	hookTimeout        string
+	retryTimedOutHooks bool
+	newHookTimeout     time.Duration
 }
 
 // configCommandAPI is an interface to allow passing in a fake implementation under test.
@@ -86,6 +107,8 @@ type configCommandAPI interface {
 	Get(application string) (*params.ApplicationGetResults, error)
 	Set(application string, options map[string]string) error
 	Unset(application string, options []string) error
+	SetUpdateStatusInterval(application string, interval time.Duration) error
+	SetHookTimeout(application string, timeout time.Duration, retry bool) error
 }
 
 // Info is part of the cmd.Command interface.
@@ -104,6 +127,9 @@ func (c *configCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
 	f.Var(&c.configFile, "file", "path to yaml-formatted application config")
 	f.Var(cmd.NewAppendStringsValue(&c.reset), "reset", "Reset the provided comma delimited keys")
+	f.StringVar(&c.updateStatusInterval, "update-status-interval", "", "Set how often the update-status hook runs for the application (0 reverts to the model setting)")
+	f.StringVar(&c.hookTimeout, "hook-timeout", "", "Set how long the application's hooks may run before they are killed (0 reverts to the charm setting)")
+	f.BoolVar(&c.retryTimedOutHooks, "retry-timed-out-hooks", true, "Retry hooks that time out, with --hook-timeout")
 }
 
 // getAPI either uses the fake API set at test time or that is nil, gets a real
@@ -137,6 +163,16 @@ func (c *configCommand) Init(args []string) error {
 	c.applicationName = args[0]
 	args = args[1:]
 
+	if c.updateStatusInterval != "" {
+		return c.parseUpdateStatusInterval(args)
+	}
+	if c.hookTimeout != "" {
+		return c.parseHookTimeout(args)
+	}
+	if !c.retryTimedOutHooks {
+		return errors.New("cannot specify --retry-timed-out-hooks without --hook-timeout")
+	}
+
 	switch len(args) {
 	case 0:
 		return c.handleZeroArgs()
@@ -196,6 +232,42 @@ func (c *configCommand) handleArgs(args []string) error {
 	return errors.New("cannot set and retrieve values simultaneously")
 }
 
+// parseUpdateStatusInterval handles --update-status-interval, which
+// cannot be combined with getting or setting charm settings.
+func (c *configCommand) parseUpdateStatusInterval(args []string) error {
+	if len(args) > 0 || len(c.reset) > 0 || c.configFile.Path != "" || c.hookTimeout != "" || !c.retryTimedOutHooks {
+		return errors.New("cannot specify --update-status-interval with other settings")
+	}
+	interval, err := time.ParseDuration(c.updateStatusInterval)
+	if err != nil {
+		return errors.Annotate(err, "invalid update status interval")
+	}
+	if interval < 0 {
+		return errors.Errorf("invalid update status interval %v", interval)
+	}
+	c.newUpdateStatusInterval = interval
+	c.action = c.setUpdateStatusInterval
+	return nil
+}
+
+// parseHookTimeout handles --hook-timeout, which cannot be combined
+// with getting or setting charm settings.
+func (c *configCommand) parseHookTimeout(args []string) error {
+	if len(args) > 0 || len(c.reset) > 0 || c.configFile.Path != "" {
+		return errors.New("cannot specify --hook-timeout with other settings")
+	}
+	timeout, err := time.ParseDuration(c.hookTimeout)
+	if err != nil {
+		return errors.Annotate(err, "invalid hook timeout")
+	}
+	if timeout < 0 {
+		return errors.Errorf("invalid hook timeout %v", timeout)
+	}
+	c.newHookTimeout = timeout
+	c.action = c.setHookTimeout
+	return nil
+}
+
 // parseResetKeys splits the keys provided to --reset.
 func (c *configCommand) parseResetKeys() error {
 	if len(c.reset) == 0 {
@@ -299,6 +371,20 @@ func (c *configCommand) setConfig(client configCommandAPI, ctx *cmd.Context) err
 	return block.ProcessBlockedError(client.Set(c.applicationName, settings), block.BlockChange)
 }
 
+// setUpdateStatusInterval is the run action when we are setting the
+// application's update-status hook interval.
+func (c *configCommand) setUpdateStatusInterval(client configCommandAPI, ctx *cmd.Context) error {
+	return block.ProcessBlockedError(
+		client.SetUpdateStatusInterval(c.applicationName, c.newUpdateStatusInterval), block.BlockChange)
+}
+
+// setHookTimeout is the run action when we are setting the
+// application's hook timeout.
+func (c *configCommand) setHookTimeout(client configCommandAPI, ctx *cmd.Context) error {
+	return block.ProcessBlockedError(
+		client.SetHookTimeout(c.applicationName, c.newHookTimeout, c.retryTimedOutHooks), block.BlockChange)
+}
+
 // setConfigFromFile sets the application configuration from settings passed
 // in a YAML file.
 func (c *configCommand) setConfigFromFile(client configCommandAPI, ctx *cmd.Context) error {
@@ -349,6 +435,15 @@ func (c *configCommand) getConfig(client configCommandAPI, ctx *cmd.Context) err
 		"charm":       results.Charm,
 		"settings":    results.Config,
 	}
+	if results.UpdateStatusInterval != 0 {
+		resultsMap["update-status-interval"] = results.UpdateStatusInterval.String()
+	}
+	if results.HookTimeout != 0 {
+		resultsMap["hook-timeout"] = results.HookTimeout.String()
+	}
+	if results.HookTimeoutNoRetry {
+		resultsMap["retry-timed-out-hooks"] = false
+	}
 	return c.out.Write(ctx, resultsMap)
 }
 
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/cmd"
@@ -295,6 +296,98 @@ func (s *configCommandSuite) TestResetConfigToDefault(c *gc.C) {
 	}, make(map[string]interface{}))
 }
 
+func (s *configCommandSuite) TestSetUpdateStatusInterval(c *gc.C) {
+	cmd := application.NewConfigCommandForTest(s.fake)
+	cmd.SetClientStore(application.NewMockStore())
+	_, err := cmdtesting.RunCommand(c, cmd, "dummy-application", "--update-status-interval", "15m")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.updateStatusInterval, gc.Equals, 15*time.Minute)
+
+	cmd = application.NewConfigCommandForTest(s.fake)
+	cmd.SetClientStore(application.NewMockStore())
+	ctx, err := cmdtesting.RunCommand(c, cmd, "dummy-application")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, "update-status-interval: 15m0s\n")
+
+	cmd = application.NewConfigCommandForTest(s.fake)
+	cmd.SetClientStore(application.NewMockStore())
+	_, err = cmdtesting.RunCommand(c, cmd, "dummy-application", "--update-status-interval", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.updateStatusInterval, gc.Equals, time.Duration(0))
+}
+
+func (s *configCommandSuite) TestSetUpdateStatusIntervalInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"app", "--update-status-interval", "soon"},
+		err:  `invalid update status interval: time: invalid duration "?soon"?`,
+	}, {
+		args: []string{"app", "--update-status-interval", "-5m"},
+		err:  "invalid update status interval -5m0s",
+	}, {
+		args: []string{"app", "--update-status-interval", "5m", "title=foo"},
+		err:  "cannot specify --update-status-interval with other settings",
+	}, {
+		args: []string{"app", "--update-status-interval", "5m", "--reset", "title"},
+		err:  "cannot specify --update-status-interval with other settings",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := cmdtesting.InitCommand(application.NewConfigCommandForTest(s.fake), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *configCommandSuite) TestSetHookTimeout(c *gc.C) {
+	cmd := application.NewConfigCommandForTest(s.fake)
+	cmd.SetClientStore(application.NewMockStore())
+	_, err := cmdtesting.RunCommand(c, cmd, "dummy-application", "--hook-timeout", "30m", "--retry-timed-out-hooks=false")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.hookTimeout, gc.Equals, 30*time.Minute)
+	c.Assert(s.fake.hookTimeoutNoRetry, jc.IsTrue)
+
+	cmd = application.NewConfigCommandForTest(s.fake)
+	cmd.SetClientStore(application.NewMockStore())
+	ctx, err := cmdtesting.RunCommand(c, cmd, "dummy-application")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, "hook-timeout: 30m0s\n")
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, "retry-timed-out-hooks: false\n")
+
+	cmd = application.NewConfigCommandForTest(s.fake)
+	cmd.SetClientStore(application.NewMockStore())
+	_, err = cmdtesting.RunCommand(c, cmd, "dummy-application", "--hook-timeout", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.hookTimeout, gc.Equals, time.Duration(0))
+	c.Assert(s.fake.hookTimeoutNoRetry, jc.IsFalse)
+}
+
+func (s *configCommandSuite) TestSetHookTimeoutInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"app", "--hook-timeout", "soon"},
+		err:  `invalid hook timeout: time: invalid duration "?soon"?`,
+	}, {
+		args: []string{"app", "--hook-timeout", "-5m"},
+		err:  "invalid hook timeout -5m0s",
+	}, {
+		args: []string{"app", "--hook-timeout", "5m", "title=foo"},
+		err:  "cannot specify --hook-timeout with other settings",
+	}, {
+		args: []string{"app", "--hook-timeout", "5m", "--update-status-interval", "5m"},
+		err:  "cannot specify --update-status-interval with other settings",
+	}, {
+		args: []string{"app", "--retry-timed-out-hooks=false"},
+		err:  "cannot specify --retry-timed-out-hooks without --hook-timeout",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := cmdtesting.InitCommand(application.NewConfigCommandForTest(s.fake), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
 func (s *configCommandSuite) TestBlockSetConfig(c *gc.C) {
 	// Block operation
 	s.fake.err = common.OperationBlockedError("TestBlockSetConfig")
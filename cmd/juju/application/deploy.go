@@ -83,9 +83,11 @@ type DeployAPI interface {
 	// ApplicationClient
 	CharmInfo(string) (*apicharms.CharmInfo, error)
 	Deploy(application.DeployArgs) error
+	PrecheckDeploy([]apiparams.PrecheckDeployMachine) ([]apiparams.PrecheckDeployResult, error)
 	Status(patterns []string) (*apiparams.FullStatus, error)
 
 	Resolve(*config.Config, *charm.URL) (*charm.URL, params.Channel, []string, error)
+	Get(*charm.URL) (charm.Charm, error)
 
 	GetBundle(*charm.URL) (charm.Bundle, error)
 
@@ -271,6 +273,10 @@ type DeployCommand struct {
 	// running an unsupported series.
 	Force bool
 
+	// DryRun is used to check the deployment for problems without
+	// changing the model.
+	DryRun bool
+
 	ApplicationName string
 	Config          cmd.FileVar
 	ConstraintsStr  string
@@ -377,6 +383,11 @@ When deploying an application or adding machines, the 'spaces' constraint can
 be used to define a comma-delimited list of required and forbidden spaces (the
 latter prefixed with "^", similar to the 'tags' constraint).
 
+The '--dry-run' option checks a deployment without making it. The charm or
+bundle, its series and its resources are resolved, and the controller checks
+the constraints and placement of every machine the deployment would create or
+use. All problems found are reported together, and the model is not changed.
+
 
 Examples:
     juju deploy mysql               (deploy to a new machine)
@@ -400,6 +411,9 @@ Examples:
     juju deploy mysql --to host.maas
     (deploy to a specific MAAS node)
 
+    juju deploy mysql -n 3 --to zone=us-east-1a --dry-run
+    (check the deployment of 3 units to a specific AZ without deploying)
+
     juju deploy haproxy -n 2 --constraints spaces=dmz,^cms,^database
     (deploy 2 units to machines that are in the 'dmz' space but not of
     the 'cmd' or the 'database' spaces)
@@ -469,6 +483,7 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(storageFlag{&c.Storage, &c.BundleStorage}, "storage", "Charm storage constraints")
 	f.Var(stringMap{&c.Resources}, "resource", "Resource to be uploaded to the controller")
 	f.StringVar(&c.BindToSpaces, "bind", "", "Configure application endpoint bindings to spaces")
+	f.BoolVar(&c.DryRun, "dry-run", false, "Check the deployment for problems without changing the model")
 
 	for _, step := range c.Steps {
 		step.SetFlags(f)
@@ -761,6 +776,14 @@ func (c *DeployCommand) maybePredeployedLocalCharm() (deployFn, error) {
 		}
 		formattedCharmURL := userCharmURL.String()
 		ctx.Infof("Located charm %q.", formattedCharmURL)
+		if c.DryRun {
+			charmInfo, err := api.CharmInfo(formattedCharmURL)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			ctx.Infof("Checking charm %q.", formattedCharmURL)
+			return errors.Trace(c.dryRunCharm(ctx, api, charmInfo.Meta, userCharmURL.Series))
+		}
 		ctx.Infof("Deploying charm %q.", formattedCharmURL)
 		return errors.Trace(c.deployCharm(
 			charmstore.CharmID{URL: userCharmURL},
@@ -832,6 +855,9 @@ func (c *DeployCommand) maybeReadLocalBundle() (deployFn, error) {
 				bundleDir = filepath.Dir(ctx.AbsPath(bundleFile))
 			}
 		}
+		if c.DryRun {
+			return errors.Trace(c.dryRunBundle(ctx, apiRoot, bundleDir, bundleData))
+		}
 		return errors.Trace(c.deployBundle(
 			ctx,
 			bundleDir,
@@ -907,6 +933,11 @@ func (c *DeployCommand) maybeReadLocalCharm(apiRoot DeployAPI) (deployFn, error)
 			return errors.Trace(err)
 		}
 
+		if c.DryRun {
+			ctx.Infof("Checking charm %q.", curl.String())
+			return errors.Trace(c.dryRunCharm(ctx, apiRoot, ch.Meta(), curl.Series))
+		}
+
 		if curl, err = apiRoot.AddLocalCharm(curl, ch); err != nil {
 			return errors.Trace(err)
 		}
@@ -965,6 +996,9 @@ func (c *DeployCommand) maybeReadCharmstoreBundleFn(apiRoot DeployAPI) func() (d
 			}
 			ctx.Infof("Located bundle %q", storeCharmOrBundleURL)
 			data := bundle.Data()
+			if c.DryRun {
+				return errors.Trace(c.dryRunBundle(ctx, apiRoot, "", data))
+			}
 
 			return errors.Trace(c.deployBundle(
 				ctx,
@@ -1021,6 +1055,16 @@ func (c *DeployCommand) charmStoreCharm() (deployFn, error) {
 			return errors.Errorf("%v. Use --force to deploy the charm anyway.", err)
 		}
 
+		if c.DryRun {
+			ch, err := apiRoot.Get(storeCharmOrBundleURL)
+			if err != nil {
+				return errors.Annotatef(err, "getting charm for URL %q", storeCharmOrBundleURL)
+			}
+			ctx.Infof("Located charm %q.", storeCharmOrBundleURL.String())
+			ctx.Infof("Checking charm %q.", storeCharmOrBundleURL.String())
+			return errors.Trace(c.dryRunCharm(ctx, apiRoot, ch.Meta(), series))
+		}
+
 		// Store the charm in the controller
 		curl, csMac, err := addCharmFromURL(apiRoot, storeCharmOrBundleURL, channel)
 		if err != nil {
@@ -1471,6 +1471,137 @@ func (s *DeployUnitTestSuite) TestDeployAttachStorageNotSupported(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "this juju controller does not support --attach-storage")
 }
 
+func (s *DeployUnitTestSuite) checkNoDeployCalls(c *gc.C, fakeAPI *fakeDeployAPI) {
+	for _, call := range fakeAPI.Calls() {
+		switch call.FuncName {
+		case "AddLocalCharm", "AddCharm", "AddCharmWithAuthorization", "Deploy", "AddMachines", "AddUnits":
+			c.Errorf("unexpected call to %s during dry run", call.FuncName)
+		}
+	}
+}
+
+func (s *DeployUnitTestSuite) TestDeployDryRunLocalCharm(c *gc.C) {
+	charmDir := s.makeCharmDir(c, "multi-series")
+	fakeAPI := s.fakeAPI()
+	fakeAPI.Call("PrecheckDeploy", []params.PrecheckDeployMachine{{
+		Series:      "trusty",
+		Constraints: constraints.MustParse("mem=4G"),
+		Placement: &instance.Placement{
+			Scope:     "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+			Directive: "zone=a",
+		},
+	}, {
+		Series:      "trusty",
+		Constraints: constraints.MustParse("mem=4G"),
+	}}).Returns([]params.PrecheckDeployResult{
+		{Errors: []*params.Error{{Message: `availability zone "a" not found`}}},
+		{},
+	}, error(nil))
+
+	ctx, err := s.runDeploy(c, fakeAPI, charmDir.Path, "--dry-run",
+		"--series", "trusty",
+		"-n", "2",
+		"--to", "zone=a",
+		"--constraints", "mem=4G",
+		"--resource", "foo=bar",
+	)
+	c.Assert(err, gc.ErrorMatches, "dry run found 2 problems")
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		`multi-series: resource "foo" not declared by the charm`+"\n"+
+		`multi-series/0: availability zone "a" not found`+"\n",
+	)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `Checking charm "local:trusty/multi-series-1".`+"\n")
+	s.checkNoDeployCalls(c, fakeAPI)
+}
+
+func (s *DeployUnitTestSuite) TestDeployDryRunCharmStoreCharm(c *gc.C) {
+	fakeAPI := s.fakeAPI()
+	cfg, err := config.New(config.NoDefaults, s.cfgAttrs())
+	c.Assert(err, jc.ErrorIsNil)
+	wordpressURL := charm.MustParseURL("cs:wordpress")
+	withCharmRepoResolvable(fakeAPI, wordpressURL, cfg)
+	fakeAPI.Call("Get", wordpressURL).Returns(charm.Charm(s.makeCharmDir(c, "wordpress")), error(nil))
+	fakeAPI.Call("PrecheckDeploy", []params.PrecheckDeployMachine{{
+		Series: "quantal",
+	}}).Returns([]params.PrecheckDeployResult{{}}, error(nil))
+
+	ctx, err := s.runDeploy(c, fakeAPI, "cs:wordpress", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		`Located charm "cs:wordpress".`+"\n"+
+		`Checking charm "cs:wordpress".`+"\n"+
+		`Dry run found no problems.`+"\n",
+	)
+	s.checkNoDeployCalls(c, fakeAPI)
+}
+
+func (s *DeployUnitTestSuite) TestDeployDryRunBundle(c *gc.C) {
+	bundlePath := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(bundlePath, []byte(`
+series: xenial
+applications:
+  mysql:
+    charm: cs:mysql
+    num_units: 1
+    to: ["0"]
+  wordpress:
+    charm: cs:wordpress
+    num_units: 2
+    constraints: mem=8G
+    to: ["lxd:0"]
+machines:
+  "0":
+    constraints: arch=arm64
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fakeAPI := s.fakeAPI()
+	cfg, err := config.New(config.NoDefaults, s.cfgAttrs())
+	c.Assert(err, jc.ErrorIsNil)
+	multiSeries := charm.Charm(s.makeCharmDir(c, "multi-series"))
+	for _, name := range []string{"cs:mysql", "cs:wordpress"} {
+		url := charm.MustParseURL(name)
+		withCharmRepoResolvable(fakeAPI, url, cfg)
+		fakeAPI.Call("Get", url).Returns(multiSeries, error(nil))
+	}
+	fakeAPI.Call("PrecheckDeploy", []params.PrecheckDeployMachine{{
+		Series:      "xenial",
+		Constraints: constraints.MustParse("arch=arm64"),
+	}, {
+		Series:      "xenial",
+		Constraints: constraints.MustParse("mem=8G"),
+	}}).Returns([]params.PrecheckDeployResult{
+		{Errors: []*params.Error{{Message: "no arm64 instances available"}}},
+		{},
+	}, error(nil))
+
+	ctx, err := s.runDeploy(c, fakeAPI, bundlePath, "--dry-run")
+	c.Assert(err, gc.ErrorMatches, "dry run found 1 problem")
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "machine 0: no arm64 instances available\n")
+	s.checkNoDeployCalls(c, fakeAPI)
+}
+
+func (s *DeployUnitTestSuite) TestDeployDryRunInvalidBundle(c *gc.C) {
+	bundlePath := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(bundlePath, []byte(`
+applications:
+  mysql:
+    charm: cs:xenial/mysql
+    num_units: 1
+    constraints: bad-wolf
+    to: ["1"]
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fakeAPI := s.fakeAPI()
+	ctx, err := s.runDeploy(c, fakeAPI, bundlePath, "--dry-run")
+	c.Assert(err, gc.ErrorMatches, "dry run found [0-9]+ problems")
+	c.Check(cmdtesting.Stdout(ctx), gc.Matches, `(?s)(.*\n)?bundle: [^\n]*"bad-wolf".*`)
+	c.Check(cmdtesting.Stdout(ctx), gc.Matches, `(?s)(.*\n)?bundle: [^\n]*placement "1".*`)
+	s.checkNoDeployCalls(c, fakeAPI)
+}
+
 // fakeDeployAPI is a mock of the API used by the deploy command. It's
 // a little muddled at the moment, but as the DeployAPI interface is
 // sharpened, this will become so as well.
@@ -1562,6 +1693,16 @@ func (f *fakeDeployAPI) Deploy(args application.DeployArgs) error {
 	return jujutesting.TypeAssertError(results[0])
 }
 
+func (f *fakeDeployAPI) PrecheckDeploy(machines []params.PrecheckDeployMachine) ([]params.PrecheckDeployResult, error) {
+	results := f.MethodCall(f, "PrecheckDeploy", machines)
+	return results[0].([]params.PrecheckDeployResult), jujutesting.TypeAssertError(results[1])
+}
+
+func (f *fakeDeployAPI) Get(url *charm.URL) (charm.Charm, error) {
+	results := f.MethodCall(f, "Get", url)
+	return results[0].(charm.Charm), jujutesting.TypeAssertError(results[1])
+}
+
 func (f *fakeDeployAPI) GetBundle(url *charm.URL) (charm.Bundle, error) {
 	results := f.MethodCall(f, "GetBundle", url)
 	return results[0].(charm.Bundle), jujutesting.TypeAssertError(results[1])
@@ -0,0 +1,401 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	apiparams "github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
+)
+
+// dryRunMachine is a machine that a deployment would create or place
+// units on, named after the unit or bundle machine it stands for.
+type dryRunMachine struct {
+	name   string
+	params apiparams.PrecheckDeployMachine
+}
+
+// dryRunReport collects the problems found by a dry run. Problems
+// with the same message are reported once, against every name they
+// were found for.
+type dryRunReport struct {
+	messages []string
+	names    map[string][]string
+}
+
+func newDryRunReport() *dryRunReport {
+	return &dryRunReport{names: make(map[string][]string)}
+}
+
+func (r *dryRunReport) add(name, message string) {
+	if _, ok := r.names[message]; !ok {
+		r.messages = append(r.messages, message)
+	}
+	r.names[message] = append(r.names[message], name)
+}
+
+// precheck asks the controller to check the given machines, and
+// records the problems it finds.
+func (r *dryRunReport) precheck(apiRoot DeployAPI, machines []dryRunMachine) error {
+	if len(machines) == 0 {
+		return nil
+	}
+	args := make([]apiparams.PrecheckDeployMachine, len(machines))
+	for i, m := range machines {
+		args[i] = m.params
+	}
+	results, err := apiRoot.PrecheckDeploy(args)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i, result := range results {
+		for _, err := range result.Errors {
+			r.add(machines[i].name, err.Error())
+		}
+	}
+	return nil
+}
+
+// checkResources records a problem for each resource that the charm
+// does not declare, or whose file cannot be read. The resources map
+// resource names to revisions or absolute file paths.
+func (r *dryRunReport) checkResources(application string, meta *charm.Meta, resources map[string]string) {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := meta.Resources[name]; !ok {
+			r.add(application, fmt.Sprintf("resource %q not declared by the charm", name))
+			continue
+		}
+		value := resources[name]
+		if _, err := strconv.Atoi(value); err == nil {
+			continue
+		}
+		if _, err := os.Stat(value); err != nil {
+			r.add(application, fmt.Sprintf("cannot read resource %q: %v", name, err))
+		}
+	}
+}
+
+// write writes the problems found to the context, returning an error
+// if there were any.
+func (r *dryRunReport) write(ctx *cmd.Context) error {
+	if len(r.messages) == 0 {
+		ctx.Infof("Dry run found no problems.")
+		return nil
+	}
+	for _, message := range r.messages {
+		fmt.Fprintf(ctx.Stdout, "%s: %s\n", strings.Join(r.names[message], ", "), message)
+	}
+	if len(r.messages) == 1 {
+		return errors.New("dry run found 1 problem")
+	}
+	return errors.Errorf("dry run found %d problems", len(r.messages))
+}
+
+// dryRunCharm checks the deployment of the given charm without making
+// it, reporting every problem found with the application and the
+// machines its units would be placed on.
+func (c *DeployCommand) dryRunCharm(
+	ctx *cmd.Context,
+	apiRoot DeployAPI,
+	meta *charm.Meta,
+	series string,
+) error {
+	report := newDryRunReport()
+	applicationName := c.ApplicationName
+	if applicationName == "" {
+		applicationName = meta.Name
+	}
+
+	numUnits := c.NumUnits
+	if meta.Subordinate {
+		if !constraints.IsEmpty(&c.Constraints) {
+			report.add(applicationName, "cannot use --constraints with subordinate application")
+		}
+		if numUnits != 1 || c.PlacementSpec != "" {
+			report.add(applicationName, "cannot use --num-units or --to with subordinate application")
+		}
+		numUnits = 0
+	}
+	if len(c.AttachStorage) > 0 && apiRoot.BestFacadeVersion("Application") < 5 {
+		report.add(applicationName, "this juju controller does not support --attach-storage")
+	}
+	for name := range c.Storage {
+		if _, ok := meta.Storage[name]; !ok {
+			report.add(applicationName, fmt.Sprintf("storage %q not declared by the charm", name))
+		}
+	}
+
+	resources := make(map[string]string, len(c.Resources))
+	for name, value := range c.Resources {
+		if _, err := strconv.Atoi(value); err != nil {
+			value = ctx.AbsPath(value)
+		}
+		resources[name] = value
+	}
+	report.checkResources(applicationName, meta, resources)
+
+	modelUUID, _ := apiRoot.ModelUUID()
+	machines := make([]dryRunMachine, numUnits)
+	for i := range machines {
+		var placement *instance.Placement
+		if i < len(c.Placement) && c.Placement[i] != nil {
+			p := *c.Placement[i]
+			if p.Scope == "model-uuid" {
+				p.Scope = modelUUID
+			}
+			placement = &p
+		}
+		machines[i] = dryRunMachine{
+			name: fmt.Sprintf("%s/%d", applicationName, i),
+			params: apiparams.PrecheckDeployMachine{
+				Series:      series,
+				Constraints: c.Constraints,
+				Placement:   placement,
+			},
+		}
+	}
+	if err := report.precheck(apiRoot, machines); err != nil {
+		return errors.Trace(err)
+	}
+	return report.write(ctx)
+}
+
+// dryRunBundle checks the deployment of the given bundle without
+// making it, reporting every problem found with its applications and
+// the machines they would be placed on. All machines are assumed to
+// be new.
+func (c *DeployCommand) dryRunBundle(
+	ctx *cmd.Context,
+	apiRoot DeployAPI,
+	bundleDir string,
+	data *charm.BundleData,
+) error {
+	if err := processBundleConfig(data, c.BundleConfigFile); err != nil {
+		return errors.Trace(err)
+	}
+	baseDir := bundleDir
+	if baseDir == "" {
+		baseDir = ctx.Dir
+	}
+	if err := processBundleIncludes(baseDir, data); err != nil {
+		return errors.Annotate(err, "unable to process includes")
+	}
+
+	report := newDryRunReport()
+	verifyConstraints := func(s string) error {
+		_, err := constraints.Parse(s)
+		return err
+	}
+	verifyStorage := func(s string) error {
+		_, err := storage.ParseConstraints(s)
+		return err
+	}
+	var verifyError error
+	if bundleDir == "" {
+		verifyError = data.Verify(verifyConstraints, verifyStorage)
+	} else {
+		verifyError = data.VerifyLocal(bundleDir, verifyConstraints, verifyStorage)
+	}
+	if verr, ok := verifyError.(*charm.VerificationError); ok {
+		// The bundle's placements cannot be relied upon, so
+		// report what is wrong with it before going any further.
+		for _, err := range verr.Errors {
+			report.add("bundle", err.Error())
+		}
+		return report.write(ctx)
+	} else if verifyError != nil {
+		return errors.Annotate(verifyError, "cannot deploy bundle")
+	}
+
+	modelCfg, err := getModelConfig(apiRoot)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	applicationNames := make([]string, 0, len(data.Applications))
+	for name := range data.Applications {
+		applicationNames = append(applicationNames, name)
+	}
+	sort.Strings(applicationNames)
+
+	var machines []dryRunMachine
+	machineSeries := make(map[string]string)
+	for _, name := range applicationNames {
+		spec := data.Applications[name]
+		meta, series, err := c.resolveBundleCharm(apiRoot, modelCfg, bundleDir, data, spec)
+		if err != nil {
+			report.add(name, err.Error())
+			continue
+		}
+
+		resources := make(map[string]string, len(spec.Resources))
+		for resName, value := range spec.Resources {
+			switch value := value.(type) {
+			case int:
+				resources[resName] = strconv.Itoa(value)
+			case string:
+				if !filepath.IsAbs(value) {
+					value = filepath.Join(baseDir, value)
+				}
+				resources[resName] = value
+			}
+		}
+		report.checkResources(name, meta, resources)
+
+		cons, err := constraints.Parse(spec.Constraints)
+		if err != nil {
+			// This should never happen, as the bundle is already verified.
+			return errors.Annotatef(err, "invalid constraints for application %q", name)
+		}
+		for i := 0; i < spec.NumUnits; i++ {
+			var placement *charm.UnitPlacement
+			if i < len(spec.To) {
+				placement, err = charm.ParsePlacement(spec.To[i])
+				if err != nil {
+					// This should never happen, as the bundle is already verified.
+					return errors.Annotatef(err, "invalid placement for application %q", name)
+				}
+			}
+			switch {
+			case placement == nil || placement.Machine == "new":
+				machines = append(machines, dryRunMachine{
+					name: fmt.Sprintf("%s/%d", name, i),
+					params: apiparams.PrecheckDeployMachine{
+						Series:      series,
+						Constraints: cons,
+					},
+				})
+			case placement.Machine != "":
+				// The machine takes the series of the first
+				// application placed on it, unless it has its own.
+				if _, ok := machineSeries[placement.Machine]; !ok {
+					machineSeries[placement.Machine] = series
+				}
+			default:
+				// The unit is placed alongside a unit of another
+				// application, so it needs no machine of its own.
+			}
+		}
+	}
+
+	machineIds := make([]string, 0, len(data.Machines))
+	for id := range data.Machines {
+		machineIds = append(machineIds, id)
+	}
+	sort.Strings(machineIds)
+	bundleMachines := make([]dryRunMachine, 0, len(machineIds))
+	for _, id := range machineIds {
+		var m apiparams.PrecheckDeployMachine
+		if spec := data.Machines[id]; spec != nil {
+			if m.Constraints, err = constraints.Parse(spec.Constraints); err != nil {
+				// This should never happen, as the bundle is already verified.
+				return errors.Annotatef(err, "invalid constraints for machine %q", id)
+			}
+			m.Series = spec.Series
+		}
+		if m.Series == "" {
+			m.Series = machineSeries[id]
+		}
+		if m.Series == "" {
+			m.Series = data.Series
+		}
+		if m.Series == "" {
+			m.Series, _ = modelCfg.DefaultSeries()
+		}
+		bundleMachines = append(bundleMachines, dryRunMachine{
+			name:   "machine " + id,
+			params: m,
+		})
+	}
+
+	if err := report.precheck(apiRoot, append(bundleMachines, machines...)); err != nil {
+		return errors.Trace(err)
+	}
+	return report.write(ctx)
+}
+
+// resolveBundleCharm returns the metadata of the charm for a bundle
+// application, along with the series it would be deployed with. Charm
+// store charms are resolved but not added to the controller.
+func (c *DeployCommand) resolveBundleCharm(
+	apiRoot DeployAPI,
+	modelCfg *config.Config,
+	bundleDir string,
+	data *charm.BundleData,
+	spec *charm.ApplicationSpec,
+) (*charm.Meta, string, error) {
+	seriesFlag := spec.Series
+	if seriesFlag == "" {
+		seriesFlag = data.Series
+	}
+
+	var (
+		meta      *charm.Meta
+		urlSeries string
+	)
+	if strings.HasPrefix(spec.Charm, ".") || filepath.IsAbs(spec.Charm) {
+		charmPath := spec.Charm
+		if !filepath.IsAbs(charmPath) {
+			charmPath = filepath.Join(bundleDir, charmPath)
+		}
+		ch, err := charm.ReadCharm(charmPath)
+		if err != nil {
+			return nil, "", errors.Annotatef(err, "cannot read local charm at %q", charmPath)
+		}
+		meta = ch.Meta()
+	} else {
+		curl, err := charm.ParseURL(spec.Charm)
+		if err != nil {
+			return nil, "", errors.Trace(err)
+		}
+		url, _, _, err := apiRoot.Resolve(modelCfg, curl)
+		if err != nil {
+			return nil, "", errors.Annotatef(err, "cannot resolve URL %q", spec.Charm)
+		}
+		if url.Series == "bundle" {
+			return nil, "", errors.Errorf("expected charm URL, got bundle URL %q", spec.Charm)
+		}
+		ch, err := apiRoot.Get(url)
+		if err != nil {
+			return nil, "", errors.Annotatef(err, "cannot get charm %q", url)
+		}
+		meta = ch.Meta()
+		urlSeries = url.Series
+	}
+
+	supportedSeries := meta.Series
+	if len(supportedSeries) == 0 && urlSeries != "" {
+		supportedSeries = []string{urlSeries}
+	}
+	selector := seriesSelector{
+		seriesFlag:      seriesFlag,
+		charmURLSeries:  urlSeries,
+		supportedSeries: supportedSeries,
+		conf:            modelCfg,
+		fromBundle:      true,
+	}
+	series, err := selector.charmSeries()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return meta, series, nil
+}
@@ -110,3 +110,9 @@ func PatchNewCharmStoreClient(s Patcher, url string) {
 		})
 	})
 }
+
+// NewShowUnitHooksCommandForTest returns a ShowUnitHooksCommand with the
+// api provided as specified.
+func NewShowUnitHooksCommandForTest(api unitHooksAPI) cmd.Command {
+	return modelcmd.Wrap(&showUnitHooksCommand{api: api})
+}
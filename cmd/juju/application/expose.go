@@ -4,10 +4,16 @@
 package application
 
 import (
+	"net"
+	"strings"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
 )
@@ -19,10 +25,24 @@ var usageExposeDetails = `
 Adjusts the firewall rules and any relevant security mechanisms of the
 cloud to allow public access to the application.
 
+Access is allowed from any address unless a firewall rule has been set
+for the application, in which case only the whitelisted subnets may
+access the application's opened ports.
+
+The --endpoints option restricts exposure to the ports opened on the
+subnets of the spaces that the given endpoints are bound to; the ports
+used only by other endpoints stay closed. The --to-spaces and --to-cidrs
+options limit access to the subnets of the given spaces and the given
+CIDRs respectively. When used without --endpoints they apply to all of
+the application's endpoints. Settings for different endpoints are
+combined across invocations.
+
 Examples:
     juju expose wordpress
+    juju expose wordpress --endpoints website --to-spaces public --to-cidrs 10.0.0.0/8
 
 See also: 
+    set-firewall-rule
     unexpose`[1:]
 
 // NewExposeCommand returns a command to expose services.
@@ -34,6 +54,14 @@ func NewExposeCommand() modelcmd.ModelCommand {
 type exposeCommand struct {
 	modelcmd.ModelCommandBase
 	ApplicationName string
+
+	Endpoints      []string
+	ExposeToSpaces []string
+	ExposeToCIDRs  []string
+
+	endpoints string
+	spaces    string
+	cidrs     string
 }
 
 func (c *exposeCommand) Info() *cmd.Info {
@@ -45,18 +73,52 @@ func (c *exposeCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *exposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.endpoints, "endpoints", "", "Comma separated list of endpoints to expose")
+	f.StringVar(&c.spaces, "to-spaces", "", "Comma separated list of spaces allowed to access the exposed ports")
+	f.StringVar(&c.cidrs, "to-cidrs", "", "Comma separated list of CIDRs allowed to access the exposed ports")
+}
+
 func (c *exposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no application name specified")
 	}
 	c.ApplicationName = args[0]
+	c.Endpoints = splitCommaList(c.endpoints)
+	c.ExposeToSpaces = splitCommaList(c.spaces)
+	for _, space := range c.ExposeToSpaces {
+		if !names.IsValidSpace(space) {
+			return errors.NotValidf("space name %q", space)
+		}
+	}
+	c.ExposeToCIDRs = splitCommaList(c.cidrs)
+	for _, cidr := range c.ExposeToCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("CIDR %q", cidr)
+		}
+	}
 	return cmd.CheckEmpty(args[1:])
 }
 
+// splitCommaList splits a comma separated flag value into its
+// non-empty elements.
+func splitCommaList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 type serviceExposeAPI interface {
 	Close() error
 	Expose(serviceName string) error
 	Unexpose(serviceName string) error
+	ExposeEndpoints(serviceName string, exposedEndpoints map[string]params.ExposedEndpoint) error
+	UnexposeEndpoints(serviceName string, endpoints []string) error
 }
 
 func (c *exposeCommand) getAPI() (serviceExposeAPI, error) {
@@ -75,5 +137,21 @@ func (c *exposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.Expose(c.ApplicationName), block.BlockChange)
+	if len(c.Endpoints)+len(c.ExposeToSpaces)+len(c.ExposeToCIDRs) == 0 {
+		return block.ProcessBlockedError(client.Expose(c.ApplicationName), block.BlockChange)
+	}
+	endpoints := c.Endpoints
+	if len(endpoints) == 0 {
+		// The settings apply to all of the application's endpoints.
+		endpoints = []string{""}
+	}
+	exposedEndpoints := make(map[string]params.ExposedEndpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		exposedEndpoints[endpoint] = params.ExposedEndpoint{
+			ExposeToSpaces: c.ExposeToSpaces,
+			ExposeToCIDRs:  c.ExposeToCIDRs,
+		}
+	}
+	err = client.ExposeEndpoints(c.ApplicationName, exposedEndpoints)
+	return block.ProcessBlockedError(err, block.BlockChange)
 }
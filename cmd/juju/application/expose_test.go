@@ -12,6 +12,7 @@ import (
 
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testcharms"
 	"github.com/juju/juju/testing"
 )
@@ -74,3 +75,45 @@ func (s *ExposeSuite) TestBlockExpose(c *gc.C) {
 	err = runExpose(c, "some-application-name")
 	s.AssertBlocked(c, err, ".*TestBlockExpose.*")
 }
+
+func (s *ExposeSuite) TestExposeEndpoints(c *gc.C) {
+	ch := testcharms.Repo.CharmArchivePath(s.CharmsPath, "multi-series")
+	_, err := runDeploy(c, ch, "some-application-name", "--series", "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runExpose(c, "some-application-name", "--endpoints", "multi-directory", "--to-cidrs", "10.0.0.0/8,192.168.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-application-name")
+
+	app, err := s.State.Application("some-application-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"multi-directory": {ExposeToCIDRs: []string{"10.0.0.0/8", "192.168.0.0/24"}},
+	})
+
+	err = runExpose(c, "some-application-name", "--endpoints", "bogus")
+	c.Assert(err, gc.ErrorMatches, `cannot update expose settings for application "some-application-name": endpoint "bogus" not found`)
+}
+
+func (s *ExposeSuite) TestExposeToCIDRsWithoutEndpoints(c *gc.C) {
+	ch := testcharms.Repo.CharmArchivePath(s.CharmsPath, "multi-series")
+	_, err := runDeploy(c, ch, "some-application-name", "--series", "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runExpose(c, "some-application-name", "--to-cidrs", "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+
+	app, err := s.State.Application("some-application-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		state.WildcardEndpoint: {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+}
+
+func (s *ExposeSuite) TestExposeInvalidFlags(c *gc.C) {
+	err := runExpose(c, "some-application-name", "--to-cidrs", "10.0.0")
+	c.Assert(err, gc.ErrorMatches, `CIDR "10.0.0" not valid`)
+
+	err = runExpose(c, "some-application-name", "--to-spaces", "-public")
+	c.Assert(err, gc.ErrorMatches, `space name "-public" not valid`)
+}
@@ -5,6 +5,7 @@ package application_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 
@@ -19,6 +20,10 @@ type fakeApplicationAPI struct {
 	values    map[string]interface{}
 	config    string
 	err       error
+
+	updateStatusInterval time.Duration
+	hookTimeout          time.Duration
+	hookTimeoutNoRetry   bool
 }
 
 func (f *fakeApplicationAPI) Update(args params.ApplicationUpdate) error {
@@ -53,12 +58,42 @@ func (f *fakeApplicationAPI) Get(application string) (*params.ApplicationGetResu
 	}
 
 	return &params.ApplicationGetResults{
-		Application: f.name,
-		Charm:       f.charmName,
-		Config:      configInfo,
+		Application:          f.name,
+		Charm:                f.charmName,
+		Config:               configInfo,
+		UpdateStatusInterval: f.updateStatusInterval,
+		HookTimeout:          f.hookTimeout,
+		HookTimeoutNoRetry:   f.hookTimeoutNoRetry,
 	}, nil
 }
 
+func (f *fakeApplicationAPI) SetUpdateStatusInterval(application string, interval time.Duration) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if application != f.name {
+		return errors.NotFoundf("application %q", application)
+	}
+
+	f.updateStatusInterval = interval
+	return nil
+}
+
+func (f *fakeApplicationAPI) SetHookTimeout(application string, timeout time.Duration, retry bool) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if application != f.name {
+		return errors.NotFoundf("application %q", application)
+	}
+
+	f.hookTimeout = timeout
+	f.hookTimeoutNoRetry = !retry
+	return nil
+}
+
 func (f *fakeApplicationAPI) Set(application string, options map[string]string) error {
 	if f.err != nil {
 		return f.err
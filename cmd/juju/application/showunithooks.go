@@ -0,0 +1,262 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/juju/osenv"
+)
+
+// NewShowUnitHooksCommand returns a command which shows the hook and
+// action executions recorded for a unit.
+func NewShowUnitHooksCommand() cmd.Command {
+	return modelcmd.Wrap(&showUnitHooksCommand{})
+}
+
+// unitHooksAPI defines a subset of the application facade, as required
+// by the show-unit-hooks command.
+type unitHooksAPI interface {
+	Close() error
+	UnitHookExecutions(unit string, limit int) ([]params.HookExecution, error)
+	UnitHookExecution(unit string, seq int) (params.HookExecution, error)
+}
+
+// showUnitHooksCommand shows the hook and action executions of a unit.
+type showUnitHooksCommand struct {
+	modelcmd.ModelCommandBase
+	out cmd.Output
+	api unitHooksAPI
+
+	unitName string
+	id       int
+	limit    int
+	isoTime  bool
+}
+
+const showUnitHooksDoc = `
+The unit agent records each execution of a hook or action by the unit: when
+it started and finished, its exit code and error, and the tail of what it
+wrote to stdout and stderr. The most recent executions are kept. The
+update-status hook runs regularly, so it is only recorded when it fails.
+
+With just a unit name, the recent executions are listed, newest first.
+Given the ID of one of them, its details are shown along with its output.
+
+Examples:
+    juju show-unit-hooks mysql/0
+    juju show-unit-hooks mysql/0 -n 5
+    juju show-unit-hooks mysql/0 12
+
+See also:
+    debug-log
+    show-status-log
+`
+
+// Info implements Command.Info.
+func (c *showUnitHooksCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-unit-hooks",
+		Args:    "<unit> [<id>]",
+		Purpose: "Show the recent hook and action executions of a unit.",
+		Doc:     showUnitHooksDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showUnitHooksCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.IntVar(&c.limit, "n", 20, "Show the last N executions (0 for all that are kept)")
+	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": c.formatTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *showUnitHooksCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit name specified")
+	}
+	c.unitName = args[0]
+	if !names.IsValidUnit(c.unitName) {
+		return errors.NotValidf("unit name %q", c.unitName)
+	}
+	if len(args) > 1 {
+		id, err := strconv.Atoi(args[1])
+		if err != nil || id <= 0 {
+			return errors.NotValidf("hook execution ID %q", args[1])
+		}
+		c.id = id
+		args = args[1:]
+	}
+	if c.limit < 0 {
+		return errors.Errorf("-n must not be negative")
+	}
+	if !c.isoTime {
+		// If use of ISO time not specified on command line,
+		// check env var.
+		if value := os.Getenv(osenv.JujuStatusIsoTimeEnvKey); value != "" {
+			var err error
+			if c.isoTime, err = strconv.ParseBool(value); err != nil {
+				return errors.Annotatef(err, "invalid %s env var, expected true|false", osenv.JujuStatusIsoTimeEnvKey)
+			}
+		}
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *showUnitHooksCommand) getAPI() (unitHooksAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return application.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *showUnitHooksCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if c.id != 0 {
+		execution, err := client.UnitHookExecution(c.unitName, c.id)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return c.out.Write(ctx, formatHookExecution(execution))
+	}
+	executions, err := client.UnitHookExecutions(c.unitName, c.limit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(executions) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No hook executions recorded for unit %q.", c.unitName)
+		return nil
+	}
+	formatted := make([]hookExecution, len(executions))
+	for i, execution := range executions {
+		formatted[i] = formatHookExecution(execution)
+	}
+	return c.out.Write(ctx, formatted)
+}
+
+// hookExecution is the output format of a hook or action execution.
+type hookExecution struct {
+	ID         int       `yaml:"id" json:"id"`
+	Kind       string    `yaml:"kind" json:"kind"`
+	Name       string    `yaml:"name" json:"name"`
+	Relation   string    `yaml:"relation,omitempty" json:"relation,omitempty"`
+	RemoteUnit string    `yaml:"remote-unit,omitempty" json:"remote-unit,omitempty"`
+	Started    time.Time `yaml:"started" json:"started"`
+	Finished   time.Time `yaml:"finished" json:"finished"`
+	ExitCode   int       `yaml:"exit-code" json:"exit-code"`
+	Error      string    `yaml:"error,omitempty" json:"error,omitempty"`
+	Stdout     string    `yaml:"stdout,omitempty" json:"stdout,omitempty"`
+	Stderr     string    `yaml:"stderr,omitempty" json:"stderr,omitempty"`
+}
+
+func formatHookExecution(execution params.HookExecution) hookExecution {
+	return hookExecution{
+		ID:         execution.Seq,
+		Kind:       execution.Kind,
+		Name:       execution.Name,
+		Relation:   execution.Relation,
+		RemoteUnit: execution.RemoteUnit,
+		Started:    execution.Started,
+		Finished:   execution.Finished,
+		ExitCode:   execution.ExitCode,
+		Error:      execution.Error,
+		Stdout:     execution.Stdout,
+		Stderr:     execution.Stderr,
+	}
+}
+
+func (c *showUnitHooksCommand) formatTabular(writer io.Writer, value interface{}) error {
+	switch value := value.(type) {
+	case []hookExecution:
+		return c.formatExecutionsTabular(writer, value)
+	case hookExecution:
+		return c.formatExecutionTabular(writer, value)
+	}
+	return errors.Errorf("expected value of type %T or %T, got %T", []hookExecution{}, hookExecution{}, value)
+}
+
+func (c *showUnitHooksCommand) formatExecutionsTabular(writer io.Writer, executions []hookExecution) error {
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("ID", "Kind", "Name", "Relation", "Started", "Duration", "Exit code")
+	for _, execution := range executions {
+		duration := execution.Finished.Sub(execution.Started)
+		w.Println(
+			execution.ID,
+			execution.Kind,
+			execution.Name,
+			execution.Relation,
+			common.FormatTime(&execution.Started, c.isoTime),
+			duration-duration%time.Millisecond,
+			execution.ExitCode,
+		)
+	}
+	return tw.Flush()
+}
+
+func (c *showUnitHooksCommand) formatExecutionTabular(writer io.Writer, execution hookExecution) error {
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("ID:", execution.ID)
+	w.Println("Kind:", execution.Kind)
+	w.Println("Name:", execution.Name)
+	if execution.Relation != "" {
+		w.Println("Relation:", execution.Relation)
+	}
+	if execution.RemoteUnit != "" {
+		w.Println("Remote unit:", execution.RemoteUnit)
+	}
+	w.Println("Started:", common.FormatTime(&execution.Started, c.isoTime))
+	w.Println("Finished:", common.FormatTime(&execution.Finished, c.isoTime))
+	w.Println("Exit code:", execution.ExitCode)
+	if execution.Error != "" {
+		w.Println("Error:", execution.Error)
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Trace(err)
+	}
+	for _, stream := range []struct {
+		name   string
+		output string
+	}{{"stdout", execution.Stdout}, {"stderr", execution.Stderr}} {
+		if stream.output == "" {
+			continue
+		}
+		fmt.Fprintf(writer, "\n%s:\n%s", stream.name, stream.output)
+		if !strings.HasSuffix(stream.output, "\n") {
+			fmt.Fprintln(writer)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type ShowUnitHooksSuite struct {
+	testing.IsolationSuite
+	mockAPI *mockUnitHooksAPI
+}
+
+var _ = gc.Suite(&ShowUnitHooksSuite{})
+
+func (s *ShowUnitHooksSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	started := time.Date(2017, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.mockAPI = &mockUnitHooksAPI{
+		Stub: &testing.Stub{},
+		executions: []params.HookExecution{{
+			Seq:        2,
+			Kind:       "hook",
+			Name:       "db-relation-changed",
+			Relation:   "db:1",
+			RemoteUnit: "wordpress/0",
+			Started:    started.Add(time.Minute),
+			Finished:   started.Add(time.Minute + 1500*time.Millisecond),
+			ExitCode:   1,
+			Error:      "exit status 1",
+			Stdout:     "connecting\n",
+			Stderr:     "connection refused",
+		}, {
+			Seq:      1,
+			Kind:     "hook",
+			Name:     "install",
+			Started:  started,
+			Finished: started.Add(20 * time.Second),
+		}},
+	}
+}
+
+func (s *ShowUnitHooksSuite) runShowUnitHooks(c *gc.C, args ...string) (string, error) {
+	ctx, err := cmdtesting.RunCommand(c, NewShowUnitHooksCommandForTest(s.mockAPI), args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stdout(ctx), nil
+}
+
+func (s *ShowUnitHooksSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no unit name specified",
+	}, {
+		args: []string{"mysql"},
+		err:  `unit name "mysql" not valid`,
+	}, {
+		args: []string{"mysql/0", "latest"},
+		err:  `hook execution ID "latest" not valid`,
+	}, {
+		args: []string{"mysql/0", "1", "2"},
+		err:  `unrecognized args: \["2"\]`,
+	}, {
+		args: []string{"mysql/0", "-n", "-1"},
+		err:  "-n must not be negative",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		_, err := s.runShowUnitHooks(c, t.args...)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *ShowUnitHooksSuite) TestListTabular(c *gc.C) {
+	out, err := s.runShowUnitHooks(c, "mysql/0", "-n", "5", "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"ID  Kind  Name                 Relation  Started               Duration  Exit code\n"+
+		"2   hook  db-relation-changed  db:1      2017-10-01 12:01:00Z  1.5s      1\n"+
+		"1   hook  install                        2017-10-01 12:00:00Z  20s       0\n")
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"UnitHookExecutions", []interface{}{"mysql/0", 5}},
+		{"Close", nil},
+	})
+}
+
+func (s *ShowUnitHooksSuite) TestListNone(c *gc.C) {
+	s.mockAPI.executions = nil
+	ctx, err := cmdtesting.RunCommand(c, NewShowUnitHooksCommandForTest(s.mockAPI), "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No hook executions recorded for unit \"mysql/0\".\n")
+}
+
+func (s *ShowUnitHooksSuite) TestShowTabular(c *gc.C) {
+	out, err := s.runShowUnitHooks(c, "mysql/0", "2", "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"ID:           2\n"+
+		"Kind:         hook\n"+
+		"Name:         db-relation-changed\n"+
+		"Relation:     db:1\n"+
+		"Remote unit:  wordpress/0\n"+
+		"Started:      2017-10-01 12:01:00Z\n"+
+		"Finished:     2017-10-01 12:01:01Z\n"+
+		"Exit code:    1\n"+
+		"Error:        exit status 1\n"+
+		"\n"+
+		"stdout:\n"+
+		"connecting\n"+
+		"\n"+
+		"stderr:\n"+
+		"connection refused\n")
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"UnitHookExecution", []interface{}{"mysql/0", 2}},
+		{"Close", nil},
+	})
+}
+
+func (s *ShowUnitHooksSuite) TestShowJSON(c *gc.C) {
+	out, err := s.runShowUnitHooks(c, "mysql/0", "1", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `{"id":1,"kind":"hook","name":"install",`+
+		`"started":"2017-10-01T12:00:00Z","finished":"2017-10-01T12:00:20Z","exit-code":0}`+"\n")
+}
+
+func (s *ShowUnitHooksSuite) TestShowNotFound(c *gc.C) {
+	_, err := s.runShowUnitHooks(c, "mysql/0", "42")
+	c.Assert(err, gc.ErrorMatches, `hook execution 42 for unit "mysql/0" not found`)
+}
+
+type mockUnitHooksAPI struct {
+	*testing.Stub
+	executions []params.HookExecution
+}
+
+func (s *mockUnitHooksAPI) Close() error {
+	s.MethodCall(s, "Close")
+	return s.NextErr()
+}
+
+func (s *mockUnitHooksAPI) UnitHookExecutions(unit string, limit int) ([]params.HookExecution, error) {
+	s.MethodCall(s, "UnitHookExecutions", unit, limit)
+	return s.executions, s.NextErr()
+}
+
+func (s *mockUnitHooksAPI) UnitHookExecution(unit string, seq int) (params.HookExecution, error) {
+	s.MethodCall(s, "UnitHookExecution", unit, seq)
+	if err := s.NextErr(); err != nil {
+		return params.HookExecution{}, err
+	}
+	for _, execution := range s.executions {
+		if execution.Seq == seq {
+			return execution, nil
+		}
+	}
+	return params.HookExecution{}, errors.NotFoundf("hook execution %d for unit %q", seq, unit)
+}
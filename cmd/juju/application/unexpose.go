@@ -6,6 +6,7 @@ package application
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 
 	"github.com/juju/juju/api/application"
 	"github.com/juju/juju/cmd/juju/block"
@@ -20,8 +21,13 @@ Adjusts the firewall rules and any relevant security mechanisms of the
 cloud to deny public access to the application.
 An application is unexposed by default when it gets created.
 
+The --endpoints option removes only the expose settings of the given
+endpoints, leaving the application exposed while settings for other
+endpoints remain.
+
 Examples:
     juju unexpose wordpress
+    juju unexpose wordpress --endpoints website
 
 See also: 
     expose`[1:]
@@ -35,6 +41,9 @@ func NewUnexposeCommand() modelcmd.ModelCommand {
 type unexposeCommand struct {
 	modelcmd.ModelCommandBase
 	ApplicationName string
+	Endpoints       []string
+
+	endpoints string
 }
 
 func (c *unexposeCommand) Info() *cmd.Info {
@@ -46,11 +55,17 @@ func (c *unexposeCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *unexposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.endpoints, "endpoints", "", "Comma separated list of endpoints to unexpose")
+}
+
 func (c *unexposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no application name specified")
 	}
 	c.ApplicationName = args[0]
+	c.Endpoints = splitCommaList(c.endpoints)
 	return cmd.CheckEmpty(args[1:])
 }
 
@@ -70,5 +85,9 @@ func (c *unexposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
+	if len(c.Endpoints) > 0 {
+		err = client.UnexposeEndpoints(c.ApplicationName, c.Endpoints)
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
 	return block.ProcessBlockedError(client.Unexpose(c.ApplicationName), block.BlockChange)
 }
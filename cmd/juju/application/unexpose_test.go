@@ -78,3 +78,17 @@ func (s *UnexposeSuite) TestBlockUnexpose(c *gc.C) {
 	err = runExpose(c, "some-application-name")
 	s.AssertBlocked(c, err, ".*TestBlockUnexpose.*")
 }
+
+func (s *UnexposeSuite) TestUnexposeEndpoints(c *gc.C) {
+	ch := testcharms.Repo.CharmArchivePath(s.CharmsPath, "multi-series")
+	_, err := runDeploy(c, ch, "some-application-name", "--series", "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runExpose(c, "some-application-name", "--endpoints", "multi-directory", "--to-cidrs", "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-application-name", true)
+
+	err = runUnexpose(c, "some-application-name", "--endpoints", "multi-directory")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-application-name", false)
+}
@@ -16,9 +16,11 @@ import (
 	"github.com/juju/juju/api/base"
 	cloudapi "github.com/juju/juju/api/cloud"
 	caascfg "github.com/juju/juju/caas/clientconfig"
+	"github.com/juju/juju/caas/kubernetes/provider"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/jujuclient"
 )
 
@@ -36,42 +38,58 @@ type CloudMetadataStore interface {
 type CloudAPI interface {
 	AddCloud(cloud.Cloud) error
 	AddCredential(tag string, credential cloud.Credential) error
+	UpdateCredential(names.CloudCredentialTag, cloud.Credential) error
 	Close() error
 }
 
-var usageAddCAASSummary = `
-Adds a CAAS endpoint and credential to Juju from among known types.`[1:]
+var usageAddK8sSummary = `
+Adds a k8s endpoint and credential to Juju.`[1:]
 
-var usageAddCAASDetails = `
+var usageAddK8sDetails = `
+The cluster and credential to add are read from a kubeconfig file. By
+default the current context of the file in $KUBECONFIG, or else
+~/.kube/config, is used. Use --kubeconfig to read a different file,
+or "-" to read it from stdin, and --context-name or --cluster-name to
+pick a context other than the current one.
+
+Token file credentials are resolved into a token when the cluster is
+added. Use update-k8s to refresh the credential when it is rotated or
+expires. Exec credentials are stored as the plugin command, which is
+run each time the cluster is contacted; the command must therefore be
+installed on the controller machines as well as the client.
+
+The cluster's certificate is verified against the certificate
+authority named in the kubeconfig, or else the system's trusted roots.
+
+The cluster is contacted with the credential before anything is stored.
 
 Examples:
-    juju add-caas myk8s kubernetes
+    juju add-k8s myk8s
+    juju add-k8s myk8s --kubeconfig ./cluster.yaml --context-name admin
+    kubectl config view --raw | juju add-k8s myk8s --kubeconfig -
 
 See also:
-    caas`
+    update-k8s`
 
-// AddCAASCommand is the command that allows you to add a caas and credential
-type AddCAASCommand struct {
+// AddK8sCommand is the command that allows you to add a k8s cluster
+// and credential.
+type AddK8sCommand struct {
 	modelcmd.ModelCommandBase
+	kubeConfigFlags
 
-	// caasName is the name of the caas to add.
-	caasName string
-
-	// CAASType is the type of CAAS being added
-	caasType string
-
-	// Context is the name of the context (k8s) or credential to import
-	context string
+	// k8sName is the name of the k8s cloud to add.
+	k8sName string
 
 	cloudMetadataStore    CloudMetadataStore
 	apiRoot               api.Connection
 	newCloudAPI           func(base.APICallCloser) CloudAPI
 	newClientConfigReader func(string) (caascfg.ClientConfigFunc, error)
+	validateCloud         func(environs.CloudSpec) error
 }
 
-// NewAddCAASCommand returns a command to add caas information.
-func NewAddCAASCommand(cloudMetadataStore CloudMetadataStore) *AddCAASCommand {
-	return &AddCAASCommand{
+// NewAddK8sCommand returns a command to add k8s cluster information.
+func NewAddK8sCommand(cloudMetadataStore CloudMetadataStore) *AddK8sCommand {
+	return &AddK8sCommand{
 		cloudMetadataStore: cloudMetadataStore,
 		newCloudAPI: func(caller base.APICallCloser) CloudAPI {
 			return cloudapi.NewClient(caller)
@@ -79,87 +97,93 @@ func NewAddCAASCommand(cloudMetadataStore CloudMetadataStore) *AddCAASCommand {
 		newClientConfigReader: func(caasType string) (caascfg.ClientConfigFunc, error) {
 			return caascfg.NewClientConfigReader(caasType)
 		},
+		validateCloud: provider.Ping,
 	}
 }
-func NewAddCAASCommandForTest(cloudMetadataStore CloudMetadataStore, apiRoot api.Connection, newCloudAPIFunc func(base.APICallCloser) CloudAPI, newClientConfigReaderFunc func(string) (caascfg.ClientConfigFunc, error)) *AddCAASCommand {
-	return &AddCAASCommand{
+
+// NewAddK8sCommandForTest returns an AddK8sCommand with the given
+// dependencies, wrapped for running.
+func NewAddK8sCommandForTest(
+	cloudMetadataStore CloudMetadataStore,
+	store jujuclient.ClientStore,
+	apiRoot api.Connection,
+	newCloudAPIFunc func(base.APICallCloser) CloudAPI,
+	newClientConfigReaderFunc func(string) (caascfg.ClientConfigFunc, error),
+	validateCloudFunc func(environs.CloudSpec) error,
+) modelcmd.ModelCommand {
+	c := &AddK8sCommand{
 		cloudMetadataStore:    cloudMetadataStore,
 		apiRoot:               apiRoot,
 		newCloudAPI:           newCloudAPIFunc,
 		newClientConfigReader: newClientConfigReaderFunc,
+		validateCloud:         validateCloudFunc,
 	}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
 }
 
 // Info returns help information about the command.
-func (c *AddCAASCommand) Info() *cmd.Info {
+func (c *AddK8sCommand) Info() *cmd.Info {
 	return &cmd.Info{
-		Name:    "add-caas",
-		Args:    "<caas type> <caas name>",
-		Purpose: usageAddCAASSummary,
-		Doc:     usageAddCAASDetails,
+		Name:    "add-k8s",
+		Args:    "<k8s name>",
+		Purpose: usageAddK8sSummary,
+		Doc:     usageAddK8sDetails,
 	}
 }
 
 // SetFlags initializes the flags supported by the command.
-func (c *AddCAASCommand) SetFlags(f *gnuflag.FlagSet) {
+func (c *AddK8sCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.CommandBase.SetFlags(f)
+	c.kubeConfigFlags.SetFlags(f)
 }
 
 // Init populates the command with the args from the command line.
-func (c *AddCAASCommand) Init(args []string) (err error) {
+func (c *AddK8sCommand) Init(args []string) (err error) {
 	if len(args) == 0 {
-		return errors.Errorf("missing CAAS type and CAAS name.")
-	}
-	if len(args) == 1 {
-		return errors.Errorf("missing CAAS name.")
+		return errors.Errorf("missing k8s name.")
 	}
-	c.caasType = args[0]
-	c.caasName = args[1]
-	return cmd.CheckEmpty(args[2:])
+	c.k8sName = args[0]
+	return cmd.CheckEmpty(args[1:])
 }
 
-func (c *AddCAASCommand) newAPIRoot() (api.Connection, error) {
+func (c *AddK8sCommand) newAPIRoot() (api.Connection, error) {
 	if c.apiRoot != nil {
 		return c.apiRoot, nil
 	}
 	return c.NewControllerAPIRoot()
 }
 
-func (c *AddCAASCommand) Run(ctxt *cmd.Context) error {
-	api, err := c.newAPIRoot()
-	if err != nil {
+// Run is defined on the Command interface.
+func (c *AddK8sCommand) Run(ctxt *cmd.Context) error {
+	if err := c.verifyName(c.k8sName); err != nil {
 		return errors.Trace(err)
 	}
-	defer api.Close()
 
-	if err := c.verifyName(c.caasName); err != nil {
+	clientConfigFunc, err := c.newClientConfigReader(k8sCloudType)
+	if err != nil {
 		return errors.Trace(err)
 	}
-
-	clientConfigFunc, err := c.newClientConfigReader(c.caasType)
+	cluster, err := c.readCluster(ctxt, clientConfigFunc)
 	if err != nil {
 		return errors.Trace(err)
 	}
-
-	caasConfig, err := clientConfigFunc()
-	if err != nil {
+	if err := c.validateCloud(cluster.cloudSpec(c.k8sName)); err != nil {
 		return errors.Trace(err)
 	}
 
-	if len(caasConfig.Contexts) == 0 {
-		return errors.Errorf("No CAAS cluster definitions found in config")
+	api, err := c.newAPIRoot()
+	if err != nil {
+		return errors.Trace(err)
 	}
-	defaultContext := caasConfig.Contexts[caasConfig.CurrentContext]
-
-	defaultCredential := caasConfig.Credentials[defaultContext.CredentialName]
-	defaultCloud := caasConfig.Clouds[defaultContext.CloudName]
+	defer api.Close()
 
 	newCloud := cloud.Cloud{
-		Name:     c.caasName,
-		Type:     c.caasType,
-		Endpoint: defaultCloud.Endpoint,
-
-		AuthTypes: []cloud.AuthType{defaultCredential.AuthType()},
+		Name:           c.k8sName,
+		Type:           k8sCloudType,
+		Endpoint:       cluster.cloud.Endpoint,
+		AuthTypes:      k8sAuthTypes,
+		CACertificates: cluster.caCertificates(),
 	}
 
 	if err := addCloudToLocal(c.cloudMetadataStore, newCloud); err != nil {
@@ -172,18 +196,20 @@ func (c *AddCAASCommand) Run(ctxt *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
-	if err := addCredentialToLocal(c.caasName, defaultCredential, defaultContext.CredentialName); err != nil {
+	if err := updateLocalCredential(c.ClientStore(), c.k8sName, cluster.credentialName, cluster.credential); err != nil {
 		return errors.Trace(err)
 	}
 
-	if err := c.addCredentialToController(cloudClient, defaultCredential, defaultContext.CredentialName); err != nil {
+	if err := c.addCredentialToController(cloudClient, cluster.credential, cluster.credentialName); err != nil {
 		return errors.Trace(err)
 	}
 
+	ctxt.Infof("Added k8s cluster %q from context %q with credential %q.",
+		c.k8sName, cluster.contextName, cluster.credentialName)
 	return nil
 }
 
-func (c *AddCAASCommand) verifyName(name string) error {
+func (c *AddK8sCommand) verifyName(name string) error {
 	public, _, err := c.cloudMetadataStore.PublicCloudMetadata()
 	if err != nil {
 		return err
@@ -234,27 +260,14 @@ func addCloudToController(apiClient CloudAPI, newCloud cloud.Cloud) error {
 	return nil
 }
 
-func addCredentialToLocal(cloudName string, newCredential cloud.Credential, credentialName string) error {
-	store := jujuclient.NewFileCredentialStore()
-	newCredentials := &cloud.CloudCredential{
-		AuthCredentials: make(map[string]cloud.Credential),
-	}
-	newCredentials.AuthCredentials[credentialName] = newCredential
-	err := store.UpdateCredential(cloudName, *newCredentials)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	return nil
-}
-
-func (c *AddCAASCommand) addCredentialToController(apiClient CloudAPI, newCredential cloud.Credential, credentialName string) error {
+func (c *AddK8sCommand) addCredentialToController(apiClient CloudAPI, newCredential cloud.Credential, credentialName string) error {
 	currentAccountDetails, err := c.CurrentAccountDetails()
 	if err != nil {
 		return errors.Trace(err)
 	}
 
 	cloudCredTag := names.NewCloudCredentialTag(fmt.Sprintf("%s/%s/%s",
-		c.caasName, currentAccountDetails.User, credentialName))
+		c.k8sName, currentAccountDetails.User, credentialName))
 
 	if err := apiClient.AddCredential(cloudCredTag.String(), newCredential); err != nil {
 		return errors.Trace(err)
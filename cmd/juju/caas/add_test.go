@@ -4,11 +4,16 @@
 package caas_test
 
 import (
+	"io"
+	"io/ioutil"
+	"strings"
+
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
@@ -17,13 +22,24 @@ import (
 	caascfg "github.com/juju/juju/caas/clientconfig"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/cmd/juju/caas"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/jujuclient"
 )
 
-type addCAASSuite struct {
+// k8sCommandSuite holds the fakes used by the add-k8s and update-k8s
+// command tests.
+type k8sCommandSuite struct {
 	jujutesting.IsolationSuite
 	fakeCloudAPI      *fakeCloudAPI
 	store             *fakeCloudMetadataStore
+	clientStore       *jujuclient.MemStore
 	fakeK8SConfigFunc caascfg.ClientConfigFunc
+	validated         []environs.CloudSpec
+	validateErr       error
+}
+
+type addCAASSuite struct {
+	k8sCommandSuite
 }
 
 var _ = gc.Suite(&addCAASSuite{})
@@ -68,26 +84,59 @@ func (f *fakeCloudMetadataStore) WritePersonalCloudMetadata(cloudsMap map[string
 type fakeCloudAPI struct {
 	caas.CloudAPI
 	jujutesting.Stub
-	authTypes   []cloud.AuthType
-	credentials []names.CloudCredentialTag
 }
 
-func fakeK8SClientConfig() (*caascfg.ClientConfig, error) {
-	return &caascfg.ClientConfig{}, nil
+func (api *fakeCloudAPI) AddCloud(cloud cloud.Cloud) error {
+	api.MethodCall(api, "AddCloud", cloud)
+	return api.NextErr()
 }
 
-func (s *addCAASSuite) SetUpTest(c *gc.C) {
-	s.IsolationSuite.SetUpTest(c)
-	s.fakeCloudAPI = &fakeCloudAPI{
-		authTypes: []cloud.AuthType{
-			cloud.EmptyAuthType,
-			cloud.AccessKeyAuthType,
+func (api *fakeCloudAPI) AddCredential(tag string, credential cloud.Credential) error {
+	api.MethodCall(api, "AddCredential", tag, credential)
+	return api.NextErr()
+}
+
+func (api *fakeCloudAPI) UpdateCredential(tag names.CloudCredentialTag, credential cloud.Credential) error {
+	api.MethodCall(api, "UpdateCredential", tag, credential)
+	return api.NextErr()
+}
+
+var (
+	theCredential   = cloud.NewCredential(cloud.OAuth2AuthType, map[string]string{"Token": "token"})
+	otherCredential = cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		"Username": "fred",
+		"Password": "secret",
+	})
+)
+
+// fakeK8SClientConfig returns a kubeconfig with two clusters, used by
+// three contexts.
+func fakeK8SClientConfig(io.Reader) (*caascfg.ClientConfig, error) {
+	return &caascfg.ClientConfig{
+		Type: "kubernetes",
+		Contexts: map[string]caascfg.Context{
+			"the-context":     {CloudName: "the-cluster", CredentialName: "the-user"},
+			"other-context":   {CloudName: "other-cluster", CredentialName: "other-user"},
+			"another-context": {CloudName: "other-cluster", CredentialName: "the-user"},
 		},
-		credentials: []names.CloudCredentialTag{
-			names.NewCloudCredentialTag("cloud/admin/default"),
-			names.NewCloudCredentialTag("aws/other/secrets"),
+		CurrentContext: "the-context",
+		Clouds: map[string]caascfg.CloudConfig{
+			"the-cluster":   {Endpoint: "https://1.1.1.1:8888"},
+			"other-cluster": {Endpoint: "https://2.2.2.2:8888"},
 		},
-	}
+		Credentials: map[string]cloud.Credential{
+			"the-user":   theCredential,
+			"other-user": otherCredential,
+		},
+	}, nil
+}
+
+func (s *k8sCommandSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.fakeCloudAPI = &fakeCloudAPI{}
+	s.fakeK8SConfigFunc = fakeK8SClientConfig
+	s.validated = nil
+	s.validateErr = nil
 	var logger loggo.Logger
 	s.store = &fakeCloudMetadataStore{CallMocker: jujutesting.NewCallMocker(logger)}
 	s.store.Call("PublicCloudMetadata", []string(nil)).Returns(map[string]cloud.Cloud{
@@ -95,58 +144,178 @@ func (s *addCAASSuite) SetUpTest(c *gc.C) {
 			Name: "mrcloud",
 			Type: "kubernetes"},
 	}, false, nil)
+
+	s.clientStore = jujuclient.NewMemStore()
+	s.clientStore.CurrentControllerName = "foo"
+	s.clientStore.Controllers["foo"] = jujuclient.ControllerDetails{}
+	s.clientStore.Models["foo"] = &jujuclient.ControllerModels{
+		CurrentModel: "admin/default",
+		Models: map[string]jujuclient.ModelDetails{
+			"admin/default": {"default-uuid"},
+		},
+	}
+	s.clientStore.Accounts["foo"] = jujuclient.AccountDetails{User: "bob"}
 }
 
-func (s *addCAASSuite) makeCommand(c *gc.C, cloudTypeExists bool) *caas.AddCAASCommand {
-	return caas.NewAddCAASCommandForTest(s.store, &fakeAPIConnection{},
+func (s *k8sCommandSuite) newClientConfigReader(caasType string) (caascfg.ClientConfigFunc, error) {
+	if caasType != "kubernetes" {
+		return nil, errors.Errorf("unsupported cloud type '%s'", caasType)
+	}
+	return s.fakeK8SConfigFunc, nil
+}
+
+func (s *k8sCommandSuite) validateCloud(spec environs.CloudSpec) error {
+	s.validated = append(s.validated, spec)
+	return s.validateErr
+}
+
+func (s *addCAASSuite) makeCommand(c *gc.C) cmd.Command {
+	return caas.NewAddK8sCommandForTest(s.store, s.clientStore, &fakeAPIConnection{},
 		func(caller base.APICallCloser) caas.CloudAPI {
 			return s.fakeCloudAPI
 		},
-		func(caasType string) (caascfg.ClientConfigFunc, error) {
-			if cloudTypeExists {
-				return fakeK8SClientConfig, nil
-			} else {
-				return nil, errors.Errorf("unsupported cloud type '%s'", caasType)
-			}
-		},
+		s.newClientConfigReader,
+		s.validateCloud,
 	)
 }
 
-func (s *addCAASSuite) runCommand(c *gc.C, cmd *caas.AddCAASCommand, args ...string) (*cmd.Context, error) {
-	return cmdtesting.RunCommand(c, cmd, args...)
+func (s *addCAASSuite) runCommand(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, s.makeCommand(c), args...)
+}
+
+func (s *addCAASSuite) expectWriteCloud(name, endpoint string, caCerts ...string) {
+	s.store.Call("PersonalCloudMetadata").Returns(map[string]cloud.Cloud{}, nil)
+	s.store.Call("WritePersonalCloudMetadata", map[string]cloud.Cloud{
+		name: {
+			Name:           name,
+			Type:           "kubernetes",
+			Endpoint:       endpoint,
+			CACertificates: caCerts,
+			AuthTypes: []cloud.AuthType{
+				cloud.CertificateAuthType,
+				cloud.OAuth2AuthType,
+				cloud.OAuth2WithCertAuthType,
+				cloud.UserPassAuthType,
+				cloud.UserPassWithCertAuthType,
+				cloud.ExecAuthType,
+			},
+		},
+	}).Returns(nil)
 }
 
 func (s *addCAASSuite) TestAddExtraArg(c *gc.C) {
-	cmd := s.makeCommand(c, true)
-	_, err := s.runCommand(c, cmd, "kubernetes", "caasname", "extra")
+	_, err := s.runCommand(c, "myk8s", "extra")
 	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
 }
 
 func (s *addCAASSuite) TestAddKnownTypeNoData(c *gc.C) {
-	cmd := s.makeCommand(c, true)
-	_, err := s.runCommand(c, cmd, "kubernetes", "caasname")
+	s.fakeK8SConfigFunc = func(io.Reader) (*caascfg.ClientConfig, error) {
+		return &caascfg.ClientConfig{}, nil
+	}
+	_, err := s.runCommand(c, "myk8s")
 	c.Assert(err, gc.ErrorMatches, `No CAAS cluster definitions found in config`)
 }
-func (s *addCAASSuite) TestAddUnknownType(c *gc.C) {
-	cmd := s.makeCommand(c, false)
-	_, err := s.runCommand(c, cmd, "ducttape", "caasname")
-	c.Assert(err, gc.ErrorMatches, `unsupported cloud type 'ducttape'`)
-}
 
 func (s *addCAASSuite) TestAddNameClash(c *gc.C) {
-	cmd := s.makeCommand(c, true)
-	_, err := s.runCommand(c, cmd, "kubernetes", "mrcloud")
+	_, err := s.runCommand(c, "mrcloud")
 	c.Assert(err, gc.ErrorMatches, `"mrcloud" is the name of a public cloud`)
 }
 
-func (s *addCAASSuite) TestMissingName(c *gc.C) {
-	cmd := s.makeCommand(c, true)
-	_, err := s.runCommand(c, cmd, "kubernetes")
-	c.Assert(err, gc.ErrorMatches, `missing CAAS name.`)
+func (s *addCAASSuite) TestMissingArgs(c *gc.C) {
+	_, err := s.runCommand(c)
+	c.Assert(err, gc.ErrorMatches, `missing k8s name.`)
 }
 
-func (s *addCAASSuite) TestMissingArgs(c *gc.C) {
-	cmd := s.makeCommand(c, true)
-	_, err := s.runCommand(c, cmd)
-	c.Assert(err, gc.ErrorMatches, `missing CAAS type and CAAS name.`)
+func (s *addCAASSuite) TestAddCurrentContext(c *gc.C) {
+	s.expectWriteCloud("myk8s", "https://1.1.1.1:8888")
+	ctx, err := s.runCommand(c, "myk8s")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals,
+		"Added k8s cluster \"myk8s\" from context \"the-context\" with credential \"the-user\".\n")
+
+	c.Assert(s.validated, gc.HasLen, 1)
+	c.Assert(s.validated[0].Endpoint, gc.Equals, "https://1.1.1.1:8888")
+	c.Assert(*s.validated[0].Credential, jc.DeepEquals, theCredential)
+
+	s.fakeCloudAPI.CheckCallNames(c, "AddCloud", "AddCredential")
+	s.fakeCloudAPI.CheckCall(c, 1, "AddCredential", "cloudcred-myk8s_bob_the-user", theCredential)
+	creds, err := s.clientStore.CredentialForCloud("myk8s")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(creds.AuthCredentials, jc.DeepEquals, map[string]cloud.Credential{
+		"the-user": theCredential,
+	})
+}
+
+func (s *addCAASSuite) TestAddContextName(c *gc.C) {
+	s.expectWriteCloud("myk8s", "https://2.2.2.2:8888")
+	_, err := s.runCommand(c, "myk8s", "--context-name", "other-context")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fakeCloudAPI.CheckCall(c, 1, "AddCredential", "cloudcred-myk8s_bob_other-user", otherCredential)
+}
+
+func (s *addCAASSuite) TestAddClusterName(c *gc.C) {
+	s.expectWriteCloud("myk8s", "https://1.1.1.1:8888")
+	_, err := s.runCommand(c, "myk8s", "--cluster-name", "the-cluster")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fakeCloudAPI.CheckCall(c, 1, "AddCredential", "cloudcred-myk8s_bob_the-user", theCredential)
+}
+
+func (s *addCAASSuite) TestAddClusterNameAmbiguous(c *gc.C) {
+	_, err := s.runCommand(c, "myk8s", "--cluster-name", "other-cluster")
+	c.Assert(err, gc.ErrorMatches,
+		`cluster "other-cluster" is used by contexts \["another-context" "other-context"\], use --context-name to pick one`)
+	s.fakeCloudAPI.CheckNoCalls(c)
+}
+
+func (s *addCAASSuite) TestAddContextAndClusterMismatch(c *gc.C) {
+	_, err := s.runCommand(c, "myk8s", "--context-name", "the-context", "--cluster-name", "other-cluster")
+	c.Assert(err, gc.ErrorMatches, `context "the-context" does not use cluster "other-cluster"`)
+}
+
+func (s *addCAASSuite) TestAddUnknownContext(c *gc.C) {
+	_, err := s.runCommand(c, "myk8s", "--context-name", "nope")
+	c.Assert(err, gc.ErrorMatches, `context "nope" not found`)
+}
+
+func (s *addCAASSuite) TestAddFromStdin(c *gc.C) {
+	var read string
+	s.fakeK8SConfigFunc = func(r io.Reader) (*caascfg.ClientConfig, error) {
+		c.Assert(r, gc.NotNil)
+		data, err := ioutil.ReadAll(r)
+		c.Assert(err, jc.ErrorIsNil)
+		read = string(data)
+		return fakeK8SClientConfig(r)
+	}
+	s.expectWriteCloud("myk8s", "https://1.1.1.1:8888")
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader("kubeconfig")
+	code := cmd.Main(s.makeCommand(c), ctx, []string{"myk8s", "--kubeconfig", "-"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(read, gc.Equals, "kubeconfig")
+}
+
+func (s *addCAASSuite) TestAddValidationFails(c *gc.C) {
+	s.validateErr = errors.New("cluster unreachable")
+	_, err := s.runCommand(c, "myk8s")
+	c.Assert(err, gc.ErrorMatches, `cluster unreachable`)
+	s.fakeCloudAPI.CheckNoCalls(c)
+	_, err = s.clientStore.CredentialForCloud("myk8s")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *addCAASSuite) TestAddCACertificates(c *gc.C) {
+	s.fakeK8SConfigFunc = func(r io.Reader) (*caascfg.ClientConfig, error) {
+		config, err := fakeK8SClientConfig(r)
+		c.Assert(err, jc.ErrorIsNil)
+		config.Clouds["the-cluster"] = caascfg.CloudConfig{
+			Endpoint:   "https://1.1.1.1:8888",
+			Attributes: map[string]interface{}{"CAData": []byte("cacert")},
+		}
+		return config, nil
+	}
+	s.expectWriteCloud("myk8s", "https://1.1.1.1:8888", "cacert")
+	_, err := s.runCommand(c, "myk8s")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.validated, gc.HasLen, 1)
+	c.Assert(s.validated[0].CACertificates, jc.DeepEquals, []string{"cacert"})
 }
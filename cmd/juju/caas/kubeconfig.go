@@ -0,0 +1,172 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"io"
+	"sort"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	caascfg "github.com/juju/juju/caas/clientconfig"
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/jujuclient"
+)
+
+// k8sCloudType is the cloud type of the clouds managed by the
+// add-k8s and update-k8s commands.
+const k8sCloudType = "kubernetes"
+
+// k8sAuthTypes are the credential auth types supported by k8s clouds.
+var k8sAuthTypes = []cloud.AuthType{
+	cloud.CertificateAuthType,
+	cloud.OAuth2AuthType,
+	cloud.OAuth2WithCertAuthType,
+	cloud.UserPassAuthType,
+	cloud.UserPassWithCertAuthType,
+	cloud.ExecAuthType,
+}
+
+// kubeConfigFlags holds the flags used to pick the cluster and
+// credential to import from a kubeconfig file.
+type kubeConfigFlags struct {
+	kubeConfig  cmd.FileVar
+	contextName string
+	clusterName string
+}
+
+// SetFlags adds the kubeconfig flags to the given flag set.
+func (f *kubeConfigFlags) SetFlags(fs *gnuflag.FlagSet) {
+	f.kubeConfig.SetStdin()
+	fs.Var(&f.kubeConfig, "kubeconfig", `Path to the kubeconfig file to read, or "-" to read it from stdin`)
+	fs.StringVar(&f.contextName, "context-name", "", "The name of the kubeconfig context to import")
+	fs.StringVar(&f.clusterName, "cluster-name", "", "The name of the kubeconfig cluster to import")
+}
+
+// k8sCluster holds the cluster and credential picked from a kubeconfig.
+type k8sCluster struct {
+	contextName    string
+	cloud          caascfg.CloudConfig
+	credentialName string
+	credential     cloud.Credential
+}
+
+// cloudSpec returns a cloud spec for the cluster, using the given
+// cloud name.
+func (k *k8sCluster) cloudSpec(name string) environs.CloudSpec {
+	return environs.CloudSpec{
+		Type:           k8sCloudType,
+		Name:           name,
+		Endpoint:       k.cloud.Endpoint,
+		Credential:     &k.credential,
+		CACertificates: k.caCertificates(),
+	}
+}
+
+// caCertificates returns the cluster's CA certificates, if the
+// kubeconfig names any.
+func (k *k8sCluster) caCertificates() []string {
+	caData, _ := k.cloud.Attributes["CAData"].([]byte)
+	if len(caData) == 0 {
+		return nil
+	}
+	return []string{string(caData)}
+}
+
+// readCluster reads the kubeconfig named by the flags, or the default
+// kubeconfig if none is named, and returns the cluster picked by the
+// flags.
+func (f *kubeConfigFlags) readCluster(ctx *cmd.Context, readConfig caascfg.ClientConfigFunc) (*k8sCluster, error) {
+	var r io.Reader
+	if f.kubeConfig.Path != "" {
+		rc, err := f.kubeConfig.Open(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer rc.Close()
+		r = rc
+	}
+	config, err := readConfig(r)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return f.selectCluster(config)
+}
+
+// selectCluster returns the cluster and credential of the named
+// context, or the context using the named cluster, or otherwise the
+// current context.
+func (f *kubeConfigFlags) selectCluster(config *caascfg.ClientConfig) (*k8sCluster, error) {
+	if len(config.Contexts) == 0 {
+		return nil, errors.Errorf("No CAAS cluster definitions found in config")
+	}
+	contextName := f.contextName
+	if contextName == "" && f.clusterName != "" {
+		var names []string
+		for name, context := range config.Contexts {
+			if context.CloudName == f.clusterName {
+				names = append(names, name)
+			}
+		}
+		switch len(names) {
+		case 0:
+			return nil, errors.NotFoundf("context for cluster %q", f.clusterName)
+		case 1:
+			contextName = names[0]
+		default:
+			sort.Strings(names)
+			return nil, errors.Errorf(
+				"cluster %q is used by contexts %q, use --context-name to pick one",
+				f.clusterName, names,
+			)
+		}
+	}
+	if contextName == "" {
+		contextName = config.CurrentContext
+		if contextName == "" {
+			return nil, errors.New("no current context, use --context-name or --cluster-name to pick one")
+		}
+	}
+
+	context, ok := config.Contexts[contextName]
+	if !ok {
+		return nil, errors.NotFoundf("context %q", contextName)
+	}
+	if f.clusterName != "" && context.CloudName != f.clusterName {
+		return nil, errors.Errorf("context %q does not use cluster %q", contextName, f.clusterName)
+	}
+	cloudConfig, ok := config.Clouds[context.CloudName]
+	if !ok {
+		return nil, errors.NotFoundf("cluster %q for context %q", context.CloudName, contextName)
+	}
+	credential, ok := config.Credentials[context.CredentialName]
+	if !ok {
+		return nil, errors.NotFoundf("supported credential %q for context %q", context.CredentialName, contextName)
+	}
+	return &k8sCluster{
+		contextName:    contextName,
+		cloud:          cloudConfig,
+		credentialName: context.CredentialName,
+		credential:     credential,
+	}, nil
+}
+
+// updateLocalCredential stores the named credential for the cloud in
+// the given store, leaving the cloud's other credentials alone.
+func updateLocalCredential(store jujuclient.CredentialStore, cloudName, credentialName string, credential cloud.Credential) error {
+	existing, err := store.CredentialForCloud(cloudName)
+	if errors.IsNotFound(err) {
+		existing = &cloud.CloudCredential{}
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if existing.AuthCredentials == nil {
+		existing.AuthCredentials = make(map[string]cloud.Credential)
+	}
+	existing.AuthCredentials[credentialName] = credential
+	return errors.Trace(store.UpdateCredential(cloudName, *existing))
+}
@@ -0,0 +1,194 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
+	cloudapi "github.com/juju/juju/api/cloud"
+	caascfg "github.com/juju/juju/caas/clientconfig"
+	"github.com/juju/juju/caas/kubernetes/provider"
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/jujuclient"
+)
+
+var usageUpdateK8sSummary = `
+Updates the credential of a k8s cluster known to Juju.`[1:]
+
+var usageUpdateK8sDetails = `
+The credential is read from a kubeconfig file in the same way as for
+add-k8s, and replaces the credential of the same name, both locally
+and on the controller. The kubeconfig context must refer to a cluster
+with the same endpoint as the k8s cluster being updated.
+
+Use this command to refresh a credential that has been rotated or has
+expired, such as a token read from a token file.
+
+Examples:
+    juju update-k8s myk8s
+    juju update-k8s myk8s --kubeconfig ./cluster.yaml --context-name admin
+
+See also:
+    add-k8s`
+
+// UpdateK8sCommand is the command that allows you to update the
+// credential of a k8s cluster.
+type UpdateK8sCommand struct {
+	modelcmd.ModelCommandBase
+	kubeConfigFlags
+
+	// k8sName is the name of the k8s cloud to update.
+	k8sName string
+
+	cloudMetadataStore    CloudMetadataStore
+	apiRoot               api.Connection
+	newCloudAPI           func(base.APICallCloser) CloudAPI
+	newClientConfigReader func(string) (caascfg.ClientConfigFunc, error)
+	validateCloud         func(environs.CloudSpec) error
+}
+
+// NewUpdateK8sCommand returns a command to update k8s credentials.
+func NewUpdateK8sCommand(cloudMetadataStore CloudMetadataStore) *UpdateK8sCommand {
+	return &UpdateK8sCommand{
+		cloudMetadataStore: cloudMetadataStore,
+		newCloudAPI: func(caller base.APICallCloser) CloudAPI {
+			return cloudapi.NewClient(caller)
+		},
+		newClientConfigReader: func(caasType string) (caascfg.ClientConfigFunc, error) {
+			return caascfg.NewClientConfigReader(caasType)
+		},
+		validateCloud: provider.Ping,
+	}
+}
+
+// NewUpdateK8sCommandForTest returns an UpdateK8sCommand with the given
+// dependencies, wrapped for running.
+func NewUpdateK8sCommandForTest(
+	cloudMetadataStore CloudMetadataStore,
+	store jujuclient.ClientStore,
+	apiRoot api.Connection,
+	newCloudAPIFunc func(base.APICallCloser) CloudAPI,
+	newClientConfigReaderFunc func(string) (caascfg.ClientConfigFunc, error),
+	validateCloudFunc func(environs.CloudSpec) error,
+) modelcmd.ModelCommand {
+	c := &UpdateK8sCommand{
+		cloudMetadataStore:    cloudMetadataStore,
+		apiRoot:               apiRoot,
+		newCloudAPI:           newCloudAPIFunc,
+		newClientConfigReader: newClientConfigReaderFunc,
+		validateCloud:         validateCloudFunc,
+	}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
+// Info returns help information about the command.
+func (c *UpdateK8sCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "update-k8s",
+		Args:    "<k8s name>",
+		Purpose: usageUpdateK8sSummary,
+		Doc:     usageUpdateK8sDetails,
+	}
+}
+
+// SetFlags initializes the flags supported by the command.
+func (c *UpdateK8sCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.kubeConfigFlags.SetFlags(f)
+}
+
+// Init populates the command with the args from the command line.
+func (c *UpdateK8sCommand) Init(args []string) (err error) {
+	if len(args) == 0 {
+		return errors.Errorf("missing k8s name.")
+	}
+	c.k8sName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *UpdateK8sCommand) newAPIRoot() (api.Connection, error) {
+	if c.apiRoot != nil {
+		return c.apiRoot, nil
+	}
+	return c.NewControllerAPIRoot()
+}
+
+// Run is defined on the Command interface.
+func (c *UpdateK8sCommand) Run(ctxt *cmd.Context) error {
+	personalClouds, err := c.cloudMetadataStore.PersonalCloudMetadata()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	existing, ok := personalClouds[c.k8sName]
+	if !ok {
+		return errors.NotFoundf("k8s cluster %q", c.k8sName)
+	}
+	if existing.Type != k8sCloudType {
+		return errors.Errorf("%q is a %q cloud, not a k8s cluster", c.k8sName, existing.Type)
+	}
+
+	clientConfigFunc, err := c.newClientConfigReader(k8sCloudType)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cluster, err := c.readCluster(ctxt, clientConfigFunc)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cluster.cloud.Endpoint != existing.Endpoint {
+		return errors.Errorf(
+			"context %q has endpoint %q, but k8s cluster %q has endpoint %q",
+			cluster.contextName, cluster.cloud.Endpoint, c.k8sName, existing.Endpoint,
+		)
+	}
+	authType := cluster.credential.AuthType()
+	if !hasAuthType(existing.AuthTypes, authType) {
+		return errors.NotSupportedf("auth type %q for k8s cluster %q", authType, c.k8sName)
+	}
+	if err := c.validateCloud(cluster.cloudSpec(c.k8sName)); err != nil {
+		return errors.Trace(err)
+	}
+
+	api, err := c.newAPIRoot()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := updateLocalCredential(c.ClientStore(), c.k8sName, cluster.credentialName, cluster.credential); err != nil {
+		return errors.Trace(err)
+	}
+
+	accountDetails, err := c.CurrentAccountDetails()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cloudCredTag := names.NewCloudCredentialTag(fmt.Sprintf("%s/%s/%s",
+		c.k8sName, accountDetails.User, cluster.credentialName))
+	if err := c.newCloudAPI(api).UpdateCredential(cloudCredTag, cluster.credential); err != nil {
+		return errors.Trace(err)
+	}
+
+	ctxt.Infof("Updated credential %q for k8s cluster %q.", cluster.credentialName, c.k8sName)
+	return nil
+}
+
+func hasAuthType(authTypes []cloud.AuthType, authType cloud.AuthType) bool {
+	for _, t := range authTypes {
+		if t == authType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/cmd/juju/caas"
+)
+
+type updateK8sSuite struct {
+	k8sCommandSuite
+}
+
+var _ = gc.Suite(&updateK8sSuite{})
+
+func (s *updateK8sSuite) SetUpTest(c *gc.C) {
+	s.k8sCommandSuite.SetUpTest(c)
+	s.store.Call("PersonalCloudMetadata").Returns(map[string]cloud.Cloud{
+		"myk8s": {
+			Name:      "myk8s",
+			Type:      "kubernetes",
+			Endpoint:  "https://1.1.1.1:8888",
+			AuthTypes: []cloud.AuthType{cloud.OAuth2AuthType, cloud.UserPassAuthType},
+		},
+		"myk8s-cert": {
+			Name:      "myk8s-cert",
+			Type:      "kubernetes",
+			Endpoint:  "https://1.1.1.1:8888",
+			AuthTypes: []cloud.AuthType{cloud.CertificateAuthType},
+		},
+		"mymaas": {
+			Name: "mymaas",
+			Type: "maas",
+		},
+	}, nil)
+	err := s.clientStore.UpdateCredential("myk8s", cloud.CloudCredential{
+		AuthCredentials: map[string]cloud.Credential{
+			"the-user":   cloud.NewCredential(cloud.OAuth2AuthType, map[string]string{"Token": "expired"}),
+			"other-user": otherCredential,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *updateK8sSuite) runUpdate(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := caas.NewUpdateK8sCommandForTest(s.store, s.clientStore, &fakeAPIConnection{},
+		func(caller base.APICallCloser) caas.CloudAPI {
+			return s.fakeCloudAPI
+		},
+		s.newClientConfigReader,
+		s.validateCloud,
+	)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *updateK8sSuite) TestMissingArgs(c *gc.C) {
+	_, err := s.runUpdate(c)
+	c.Assert(err, gc.ErrorMatches, `missing k8s name.`)
+}
+
+func (s *updateK8sSuite) TestUpdate(c *gc.C) {
+	ctx, err := s.runUpdate(c, "myk8s")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Updated credential \"the-user\" for k8s cluster \"myk8s\".\n")
+	c.Assert(s.validated, gc.HasLen, 1)
+
+	s.fakeCloudAPI.CheckCallNames(c, "UpdateCredential")
+	s.fakeCloudAPI.CheckCall(c, 0, "UpdateCredential",
+		names.NewCloudCredentialTag("myk8s/bob/the-user"), theCredential)
+	creds, err := s.clientStore.CredentialForCloud("myk8s")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(creds.AuthCredentials, jc.DeepEquals, map[string]cloud.Credential{
+		"the-user":   theCredential,
+		"other-user": otherCredential,
+	})
+}
+
+func (s *updateK8sSuite) TestUpdateUnknownCloud(c *gc.C) {
+	_, err := s.runUpdate(c, "nope")
+	c.Assert(err, gc.ErrorMatches, `k8s cluster "nope" not found`)
+}
+
+func (s *updateK8sSuite) TestUpdateNotK8s(c *gc.C) {
+	_, err := s.runUpdate(c, "mymaas")
+	c.Assert(err, gc.ErrorMatches, `"mymaas" is a "maas" cloud, not a k8s cluster`)
+}
+
+func (s *updateK8sSuite) TestUpdateEndpointMismatch(c *gc.C) {
+	_, err := s.runUpdate(c, "myk8s", "--context-name", "other-context")
+	c.Assert(err, gc.ErrorMatches,
+		`context "other-context" has endpoint "https://2.2.2.2:8888", but k8s cluster "myk8s" has endpoint "https://1.1.1.1:8888"`)
+	s.fakeCloudAPI.CheckNoCalls(c)
+}
+
+func (s *updateK8sSuite) TestUpdateUnsupportedAuthType(c *gc.C) {
+	_, err := s.runUpdate(c, "myk8s-cert")
+	c.Assert(err, gc.ErrorMatches, `auth type "oauth2" for k8s cluster "myk8s-cert" not supported`)
+	s.fakeCloudAPI.CheckNoCalls(c)
+}
+
+func (s *updateK8sSuite) TestUpdateValidationFails(c *gc.C) {
+	s.validateErr = errors.New("token rejected")
+	_, err := s.runUpdate(c, "myk8s")
+	c.Assert(err, gc.ErrorMatches, `token rejected`)
+	s.fakeCloudAPI.CheckNoCalls(c)
+	creds, err := s.clientStore.CredentialForCloud("myk8s")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(creds.AuthCredentials["the-user"].Attributes()["Token"], gc.Equals, "expired")
+}
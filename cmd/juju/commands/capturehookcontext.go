@@ -0,0 +1,128 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/network/ssh"
+	unitdebug "github.com/juju/juju/worker/uniter/runner/debug"
+)
+
+func newCaptureHookContextCommand(hostChecker ssh.ReachableChecker) cmd.Command {
+	c := new(captureHookContextCommand)
+	c.setHostChecker(hostChecker)
+	return modelcmd.Wrap(c)
+}
+
+// captureHookContextCommand captures the context of a hook on a unit,
+// for replay with replay-hook.
+type captureHookContextCommand struct {
+	debugHooksCommand
+	output string
+}
+
+const captureHookContextDoc = `
+Capture the context in which a hook runs on a unit, for replaying the
+hook offline with "juju replay-hook".
+
+When one of the named hooks next runs on the unit, its environment
+variables, the unit's config, leader settings and relation data, and
+the contents of the charm directory are captured just before the hook
+is run, and the bundle holding them is downloaded. The hook itself runs
+as usual. If "*" is given as the hook name, the next hook of any kind
+is captured.
+
+The bundle holds the unit's configuration and settings, which may
+include secrets; keep it safe.
+
+See the "juju help ssh" for information about SSH related options
+accepted by the capture-hook-context command.
+
+Examples:
+    juju capture-hook-context mysql/0 config-changed
+    juju capture-hook-context mysql/0 db-relation-changed -o db-changed.tar.gz
+
+See also:
+    debug-hooks
+    replay-hook
+`
+
+func (c *captureHookContextCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "capture-hook-context",
+		Args:    "<unit name> <hook name> [hook names]",
+		Purpose: "Capture the context of a hook on a unit for offline replay.",
+		Doc:     captureHookContextDoc,
+	}
+}
+
+func (c *captureHookContextCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.debugHooksCommand.SetFlags(f)
+	f.StringVar(&c.output, "o", "", "The file to which the hook context bundle is written")
+	f.StringVar(&c.output, "output", "", "")
+}
+
+func (c *captureHookContextCommand) Init(args []string) error {
+	if len(args) == 1 {
+		return errors.Errorf("no hook name specified")
+	}
+	if err := c.debugHooksCommand.Init(args); err != nil {
+		return errors.Trace(err)
+	}
+	if c.output == "" {
+		name := strings.Replace(c.Target, "/", "-", -1)
+		c.output = fmt.Sprintf("%s-hook-context.tar.gz", name)
+	}
+	return nil
+}
+
+// Run connects to the unit via SSH to request the capture of the hook
+// context, and writes the bundle it's sent to the output file.
+func (c *captureHookContextCommand) Run(ctx *cmd.Context) error {
+	err := c.initRun()
+	if err != nil {
+		return err
+	}
+	defer c.cleanupRun()
+	err = c.validateHooks()
+	if err != nil {
+		return err
+	}
+
+	output := ctx.AbsPath(c.output)
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+
+	debugctx := unitdebug.NewHooksContext(c.Target)
+	script := base64.StdEncoding.EncodeToString([]byte(unitdebug.CaptureClientScript(debugctx, c.hooks)))
+	innercmd := fmt.Sprintf(`F=$(mktemp); echo %s | base64 -d > $F; . $F`, script)
+	c.Args = []string{fmt.Sprintf("sudo /bin/bash -c '%s'", innercmd)}
+	// The bundle is written to stdout, which must not be mangled by
+	// a terminal.
+	c.pty = false
+
+	sshctx := *ctx
+	sshctx.Stdout = f
+	if err := c.sshCommand.Run(&sshctx); err != nil {
+		os.Remove(output)
+		return errors.Trace(err)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Hook context of %s captured to %s", c.Target, c.output)
+	return nil
+}
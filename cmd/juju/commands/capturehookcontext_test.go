@@ -0,0 +1,96 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	jujussh "github.com/juju/juju/network/ssh"
+)
+
+var _ = gc.Suite(&CaptureHookContextSuite{})
+
+type CaptureHookContextSuite struct {
+	SSHCommonSuite
+}
+
+var captureHookContextTests = []struct {
+	info        string
+	args        []string
+	hostChecker jujussh.ReachableChecker
+	output      string
+	error       string
+	expected    *argsSpec
+}{{
+	info:        "unit name and hook",
+	args:        []string{"mysql/0", "start"},
+	hostChecker: validAddresses("0.private", "0.public", "0.1.2.3"),
+	output:      "mysql-0-hook-context.tar.gz",
+	expected: &argsSpec{
+		hostKeyChecking: "yes",
+		knownHosts:      "0",
+		enablePty:       false,
+		argsMatch:       `ubuntu@0\.(private|public|1\.2\.3) sudo /bin/bash .+`,
+	},
+}, {
+	info:        "output file",
+	args:        []string{"mysql/0", "start", "-o", "start.tar.gz"},
+	hostChecker: validAddresses("0.private", "0.public", "0.1.2.3"),
+	output:      "start.tar.gz",
+	expected: &argsSpec{
+		hostKeyChecking: "yes",
+		knownHosts:      "0",
+		enablePty:       false,
+		argsMatch:       `ubuntu@0\.(private|public|1\.2\.3) sudo /bin/bash .+`,
+	},
+}, {
+	info:        `"*" captures the next hook`,
+	args:        []string{"mysql/0", "*"},
+	hostChecker: validAddresses("0.public"),
+	output:      "mysql-0-hook-context.tar.gz",
+}, {
+	info:  `invalid hook`,
+	args:  []string{"mysql/0", "invalid-hook"},
+	error: `unit "mysql/0" does not contain hook "invalid-hook"`,
+}, {
+	info:  `no hook`,
+	args:  []string{"mysql/0"},
+	error: `no hook name specified`,
+}, {
+	info:  `no args at all`,
+	args:  nil,
+	error: `no unit name specified`,
+}}
+
+func (s *CaptureHookContextSuite) TestCaptureHookContextCommand(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("hook contexts are captured only on unix")
+	}
+
+	s.setupModel(c)
+
+	for i, t := range captureHookContextTests {
+		c.Logf("test %d: %s\n\t%s\n", i, t.info, t.args)
+
+		s.setHostChecker(t.hostChecker)
+
+		ctx, err := cmdtesting.RunCommand(c, newCaptureHookContextCommand(s.hostChecker), t.args...)
+		if t.error != "" {
+			c.Check(err, gc.ErrorMatches, t.error)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		data, err := ioutil.ReadFile(filepath.Join(ctx.Dir, t.output))
+		c.Assert(err, jc.ErrorIsNil)
+		if t.expected != nil {
+			t.expected.check(c, string(data))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network/ssh"
+)
+
+func newConsoleCommand(hostChecker ssh.ReachableChecker) cmd.Command {
+	c := new(consoleCommand)
+	c.setHostChecker(hostChecker)
+	return modelcmd.Wrap(c)
+}
+
+// consoleCommand attaches to the serial console of a KVM container,
+// by way of the machine hosting it.
+type consoleCommand struct {
+	sshCommand
+	machineId string
+}
+
+const consoleDoc = `
+Attach to the serial console of a KVM container.
+
+The console is reached by connecting to the container's host machine
+over SSH and running "virsh console" there, so it remains available
+when the container's own network or SSH daemon is not. Press Ctrl+]
+to detach.
+
+See the "juju help ssh" for information about SSH related options
+accepted by the console command.
+
+Examples:
+
+    juju console 0/kvm/1
+
+See also:
+    ssh
+    debug-hooks
+`
+
+func (c *consoleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "console",
+		Args:    "<machine>",
+		Purpose: "Attach to the serial console of a KVM container.",
+		Doc:     consoleDoc,
+	}
+}
+
+func (c *consoleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no machine specified")
+	}
+	c.machineId, args = args[0], args[1:]
+	if !names.IsValidMachine(c.machineId) {
+		return errors.Errorf("invalid machine id %q", c.machineId)
+	}
+	parts := strings.Split(c.machineId, "/")
+	if len(parts) < 3 || parts[len(parts)-2] != string(instance.KVM) {
+		return errors.Errorf("machine %s is not a KVM container", c.machineId)
+	}
+	c.Target = strings.Join(parts[:len(parts)-2], "/")
+	return cmd.CheckEmpty(args)
+}
+
+// Run connects to the host of the KVM container via SSH, and attaches
+// to the container's serial console there.
+func (c *consoleCommand) Run(ctx *cmd.Context) error {
+	_, details, err := c.ModelDetails()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	namespace, err := instance.NewNamespace(details.ModelUUID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	domain, err := namespace.Hostname(c.machineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.pty = true
+	c.Args = []string{"sudo", "virsh", "console", domain}
+	return c.sshCommand.Run(ctx)
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"runtime"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+)
+
+var _ = gc.Suite(&ConsoleSuite{})
+
+type ConsoleSuite struct {
+	SSHCommonSuite
+}
+
+func (s *ConsoleSuite) TestConsoleCommand(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("bug 1403084: Skipping on windows for now")
+	}
+	s.setupModel(c)
+
+	namespace, err := instance.NewNamespace(s.State.ModelUUID())
+	c.Assert(err, jc.ErrorIsNil)
+	domain, err := namespace.Hostname("0/kvm/1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.setHostChecker(validAddresses("0.private", "0.public"))
+	s.setForceAPIv1(true)
+	ctx, err := cmdtesting.RunCommand(c, newConsoleCommand(s.hostChecker), "0/kvm/1")
+	c.Assert(err, jc.ErrorIsNil)
+	expected := &argsSpec{
+		hostKeyChecking: "yes",
+		knownHosts:      "0",
+		enablePty:       true,
+		args:            "ubuntu@0.public sudo virsh console " + domain,
+	}
+	expected.check(c, cmdtesting.Stdout(ctx))
+}
+
+func (s *ConsoleSuite) TestConsoleCommandInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no machine specified",
+	}, {
+		args: []string{"foo"},
+		err:  `invalid machine id "foo"`,
+	}, {
+		args: []string{"0"},
+		err:  "machine 0 is not a KVM container",
+	}, {
+		args: []string{"0/lxd/1"},
+		err:  "machine 0/lxd/1 is not a KVM container",
+	}, {
+		args: []string{"0/kvm/1", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		_, err := cmdtesting.RunCommand(c, newConsoleCommand(nil), t.args...)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
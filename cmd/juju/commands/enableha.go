@@ -72,6 +72,16 @@ controller.
 
 An odd number of controllers is required.
 
+Specifying a smaller number of controllers than are currently voting
+reduces the size of the controller: the most recently added voting
+controller machines lose their vote and are removed once the remaining
+controllers no longer depend on them. To remove or replace a specific
+controller machine, use remove-controller-machine.
+
+On clouds with availability zones, new controller machines which are not
+placed with --to are spread across the available zones, so that the loss
+of a single zone is least likely to leave the controller without quorum.
+
 Examples:
     # Ensure that the controller is still in highly available mode. If
     # there is only 1 controller running, this will ensure there
@@ -36,6 +36,7 @@ var expectedCommands = []string{
 	"application-version-set",
 	"close-port",
 	"config-get",
+	"health-check-set",
 	"is-leader",
 	"juju-log",
 	"juju-reboot",
@@ -47,11 +48,15 @@ var expectedCommands = []string{
 	"payload-register",
 	"payload-status-set",
 	"payload-unregister",
+	"pod-spec-set",
 	"relation-get",
 	"relation-ids",
 	"relation-list",
 	"relation-set",
 	"resource-get",
+	"secret-add",
+	"secret-get",
+	"secret-grant",
 	"status-get",
 	"status-set",
 	"storage-add",
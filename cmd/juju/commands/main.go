@@ -40,6 +40,7 @@ import (
 	"github.com/juju/juju/cmd/juju/model"
 	"github.com/juju/juju/cmd/juju/resource"
 	rcmd "github.com/juju/juju/cmd/juju/romulus/commands"
+	"github.com/juju/juju/cmd/juju/secrets"
 	"github.com/juju/juju/cmd/juju/setmeterstatus"
 	"github.com/juju/juju/cmd/juju/space"
 	"github.com/juju/juju/cmd/juju/status"
@@ -287,6 +288,10 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Firewall rule commands.
 	r.Register(firewall.NewSetFirewallRuleCommand())
 	r.Register(firewall.NewListFirewallRulesCommand())
+	r.Register(firewall.NewSetEgressRuleCommand())
+
+	// Charm secret commands.
+	r.Register(secrets.NewListSecretsCommand())
 
 	// Destruction commands.
 	r.Register(application.NewRemoveRelationCommand())
@@ -305,6 +310,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(newResolvedCommand())
 	r.Register(newDebugLogCommand())
 	r.Register(newDebugHooksCommand(nil))
+	r.Register(newConsoleCommand(nil))
+	r.Register(newCaptureHookContextCommand(nil))
 
 	// Configuration commands.
 	r.Register(model.NewModelGetConstraintsCommand())
@@ -313,9 +320,12 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(newUpgradeJujuCommand(nil))
 	r.Register(application.NewUpgradeCharmCommand())
 	r.Register(application.NewUpdateSeriesCommand())
+	r.Register(application.NewShowUnitHooksCommand())
 
 	// Charm tool commands.
 	r.Register(newHelpToolCommand())
+	r.Register(newReplayHookCommand())
+	r.Register(newTestCharmCommand())
 	// TODO (anastasiamac 2017-08-1) This needs to be removed in Juju 3.x
 	// lp#1707836
 	r.Register(charmcmd.NewSuperCommand())
@@ -356,6 +366,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(machine.NewRemoveCommand())
 	r.Register(machine.NewListMachinesCommand())
 	r.Register(machine.NewShowMachineCommand())
+	r.Register(machine.NewEstimateCostCommand())
+	r.Register(machine.NewMoveCommand())
 
 	// Manage model
 	r.Register(model.NewConfigCommand())
@@ -381,6 +393,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Manage controller availability
 	r.Register(newEnableHACommand())
+	r.Register(newRemoveControllerMachineCommand())
 
 	// Manage and control services
 	r.Register(application.NewAddUnitCommand())
@@ -459,7 +472,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// CAAS commands
 	if featureflag.Enabled(feature.CAAS) {
-		r.Register(modelcmd.Wrap(caas.NewAddCAASCommand(&cloudToCommandAdapter{})))
+		r.Register(modelcmd.Wrap(caas.NewAddK8sCommand(&cloudToCommandAdapter{})))
+		r.Register(modelcmd.Wrap(caas.NewUpdateK8sCommand(&cloudToCommandAdapter{})))
 	}
 
 	// Juju GUI commands.
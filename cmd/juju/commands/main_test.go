@@ -409,12 +409,14 @@ var commandNames = []string{
 	"budget",
 	"cached-images",
 	"cancel-action",
+	"capture-hook-context",
 	"change-user-password",
 	"charm",
 	"charm-resources",
 	"clouds",
 	"collect-metrics",
 	"config",
+	"console",
 	"consume",
 	"controller-config",
 	"controllers",
@@ -436,6 +438,7 @@ var commandNames = []string{
 	"enable-destroy-controller",
 	"enable-ha",
 	"enable-user",
+	"estimate-cost",
 	"expose",
 	"find-offers",
 	"firewall-rules",
@@ -465,6 +468,7 @@ var commandNames = []string{
 	"list-plans",
 	"list-regions",
 	"list-resources",
+	"list-secrets",
 	"list-spaces",
 	"list-ssh-keys",
 	"list-storage",
@@ -481,6 +485,7 @@ var commandNames = []string{
 	"model-default",
 	"model-defaults",
 	"models",
+	"move-machine",
 	"offer",
 	"offers",
 	"payloads",
@@ -493,6 +498,7 @@ var commandNames = []string{
 	"remove-backup",
 	"remove-cached-images",
 	"remove-cloud",
+	"remove-controller-machine",
 	"remove-credential",
 	"remove-machine",
 	"remove-offer",
@@ -501,6 +507,7 @@ var commandNames = []string{
 	"remove-storage",
 	"remove-unit",
 	"remove-user",
+	"replay-hook",
 	"resolved",
 	"resolve",
 	"resources",
@@ -511,9 +518,11 @@ var commandNames = []string{
 	"run",
 	"run-action",
 	"scp",
+	"secrets",
 	"set-constraints",
 	"set-default-credential",
 	"set-default-region",
+	"set-egress-rule",
 	"set-firewall-rule",
 	"set-meter-status",
 	"set-model-constraints",
@@ -530,6 +539,7 @@ var commandNames = []string{
 	"show-status",
 	"show-status-log",
 	"show-storage",
+	"show-unit-hooks",
 	"show-user",
 	"show-wallet",
 	"sla",
@@ -543,6 +553,7 @@ var commandNames = []string{
 	"suspend-relation",
 	"switch",
 	"sync-tools",
+	"test-charm",
 	"unexpose",
 	"unregister",
 	"update-clouds",
@@ -0,0 +1,126 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/highavailability"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+func newRemoveControllerMachineCommand() cmd.Command {
+	command := &removeControllerMachineCommand{}
+	command.newAPIFunc = func() (RemoveControllerMachineAPI, error) {
+		root, err := command.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot get API connection")
+		}
+		return highavailability.NewClient(root), nil
+	}
+	return modelcmd.WrapController(command)
+}
+
+// removeControllerMachineCommand removes machines from the controller.
+type removeControllerMachineCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	// newAPIFunc returns the client to be used by the command.
+	newAPIFunc func() (RemoveControllerMachineAPI, error)
+
+	// MachineIds holds the ids of the controller machines to remove.
+	MachineIds []string
+}
+
+const removeControllerMachineDoc = `
+Removes one or more machines from the controller. The machines lose
+their vote in the controller's database replicaset and, once the
+remaining controllers no longer depend on them, they are removed from
+the replicaset and destroyed.
+
+The number of voting controllers is left unchanged: a new controller
+machine is added to replace each voting machine removed. To reduce
+the number of controllers, use enable-ha with a smaller -n instead.
+
+The only remaining controller machine cannot be removed.
+
+Examples:
+    # Replace the controller machine 1, which may be unhealthy.
+    juju remove-controller-machine 1
+
+See also:
+    enable-ha`
+
+// RemoveControllerMachineAPI defines the API methods that the remove
+// controller machine command uses.
+type RemoveControllerMachineAPI interface {
+	Close() error
+	RemoveControllerMachine(machineIds ...string) ([]params.ControllersChanges, error)
+}
+
+// Info implements cmd.Command.
+func (c *removeControllerMachineCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove-controller-machine",
+		Args:    "<machine> ...",
+		Purpose: "Removes machines from the controller, replacing them if they have a vote.",
+		Doc:     removeControllerMachineDoc,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *removeControllerMachineCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "simple", map[string]cmd.Formatter{
+		"yaml":   cmd.FormatYaml,
+		"json":   cmd.FormatJson,
+		"simple": formatSimple,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *removeControllerMachineCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no machines specified")
+	}
+	for _, id := range args {
+		if !names.IsValidMachine(id) {
+			return errors.Errorf("invalid machine id %q", id)
+		}
+		if names.IsContainerMachine(id) {
+			return errors.Errorf("machine %q is a container and cannot be a controller", id)
+		}
+	}
+	c.MachineIds = args
+	return nil
+}
+
+// Run implements cmd.Command.
+func (c *removeControllerMachineCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	changes, err := client.RemoveControllerMachine(c.MachineIds...)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	var result availabilityInfo
+	for _, change := range changes {
+		result.Added = append(result.Added, machineTagsToIds(change.Added...)...)
+		result.Removed = append(result.Removed, machineTagsToIds(change.Removed...)...)
+		result.Promoted = append(result.Promoted, machineTagsToIds(change.Promoted...)...)
+		result.Demoted = append(result.Demoted, machineTagsToIds(change.Demoted...)...)
+		result.Converted = append(result.Converted, machineTagsToIds(change.Converted...)...)
+	}
+	return c.out.Write(ctx, result)
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type RemoveControllerMachineSuite struct {
+	testing.JujuConnSuite
+	fake *fakeRemoveControllerMachineAPI
+}
+
+var _ = gc.Suite(&RemoveControllerMachineSuite{})
+
+func (s *RemoveControllerMachineSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.fake = &fakeRemoveControllerMachineAPI{}
+}
+
+type fakeRemoveControllerMachineAPI struct {
+	machineIds []string
+	err        error
+}
+
+func (f *fakeRemoveControllerMachineAPI) Close() error {
+	return nil
+}
+
+func (f *fakeRemoveControllerMachineAPI) RemoveControllerMachine(machineIds ...string) ([]params.ControllersChanges, error) {
+	f.machineIds = machineIds
+	if f.err != nil {
+		return nil, f.err
+	}
+	changes := make([]params.ControllersChanges, len(machineIds))
+	for i, id := range machineIds {
+		changes[i] = params.ControllersChanges{
+			Maintained: []string{"machine-0"},
+			Added:      []string{"machine-" + id + "0"},
+			Demoted:    []string{"machine-" + id},
+		}
+	}
+	return changes, nil
+}
+
+func (s *RemoveControllerMachineSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &removeControllerMachineCommand{
+		newAPIFunc: func() (RemoveControllerMachineAPI, error) { return s.fake, nil },
+	}
+	return cmdtesting.RunCommand(c, modelcmd.WrapController(command), args...)
+}
+
+func (s *RemoveControllerMachineSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no machines specified",
+	}, {
+		args: []string{"foo"},
+		err:  `invalid machine id "foo"`,
+	}, {
+		args: []string{"1/lxd/0"},
+		err:  `machine "1/lxd/0" is a container and cannot be a controller`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	c.Assert(s.fake.machineIds, gc.IsNil)
+}
+
+func (s *RemoveControllerMachineSuite) TestRemove(c *gc.C) {
+	ctx, err := s.run(c, "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.machineIds, jc.DeepEquals, []string{"1", "2"})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"adding machines: 10, 20\n"+
+		"demoting machines: 1, 2\n",
+	)
+}
+
+func (s *RemoveControllerMachineSuite) TestBlocked(c *gc.C) {
+	s.fake.err = common.OperationBlockedError("TestBlocked")
+	_, err := s.run(c, "1")
+	coretesting.AssertOperationWasBlocked(c, err, ".*TestBlocked.*")
+}
@@ -0,0 +1,220 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/environs/tools"
+	"github.com/juju/juju/juju/names"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	"github.com/juju/juju/worker/uniter/runner/offline"
+)
+
+func newReplayHookCommand() cmd.Command {
+	return &replayHookCommand{}
+}
+
+// replayHookCommand replays a hook captured with capture-hook-context.
+type replayHookCommand struct {
+	cmd.CommandBase
+	bundle string
+	jujud  string
+	dir    string
+}
+
+const replayHookDoc = `
+Replay a hook captured with "juju capture-hook-context", without a
+controller.
+
+The hook context bundle is extracted, and the captured hook of the
+charm it holds is run with the captured environment. The hook tools
+it runs are served from the captured unit state rather than from a
+controller, so nothing the hook does with them has any effect beyond
+the replay. The changes made with them are reported once the hook has
+run. Anything else the hook does, such as installing packages or
+writing files, is done on this machine, so beware.
+
+The hook tools are links to the jujud executable, which is looked for
+next to the juju executable unless --jujud is specified.
+
+The bundle is extracted into a temporary directory that is removed
+once the hook has run, unless a directory to keep it in is specified
+with --dir.
+
+Examples:
+    juju replay-hook mysql-0-hook-context.tar.gz
+    juju replay-hook mysql-0-hook-context.tar.gz --dir ./replay
+
+See also:
+    capture-hook-context
+`
+
+func (c *replayHookCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "replay-hook",
+		Args:    "<bundle>",
+		Purpose: "Replay a captured hook offline.",
+		Doc:     replayHookDoc,
+	}
+}
+
+func (c *replayHookCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.jujud, "jujud", "", "The jujud executable providing the hook tools")
+	f.StringVar(&c.dir, "dir", "", "The directory into which the bundle is extracted")
+}
+
+func (c *replayHookCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no hook context bundle specified")
+	}
+	c.bundle = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *replayHookCommand) Run(ctx *cmd.Context) error {
+	jujud, err := findJujud(ctx, c.jujud)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	dir := c.dir
+	if dir == "" {
+		tempDir, err := ioutil.TempDir("", "juju-replay-hook")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer os.RemoveAll(tempDir)
+		dir = tempDir
+	}
+	dir = ctx.AbsPath(dir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Trace(err)
+	}
+	f, err := os.Open(ctx.AbsPath(c.bundle))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	snapshot, err := offline.ReadBundle(f, dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	state := snapshot.State()
+	hookctx, err := snapshot.Context(state)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	config := offline.HookConfig{
+		CharmDir: filepath.Join(dir, offline.BundleCharmDir),
+		Jujud:    jujud,
+		Env:      snapshot.Environ(),
+		Stdout:   ctx.Stdout,
+		Stderr:   ctx.Stderr,
+	}
+	ctx.Infof("Replaying %s hook of %s captured at %s", snapshot.Hook, snapshot.Unit, snapshot.Captured.Format("2006-01-02 15:04:05Z07:00"))
+	hookErr := offline.RunHook(config, hookctx, snapshot.Hook)
+	if errors.IsNotFound(hookErr) {
+		return errors.Errorf("charm does not implement the %s hook", snapshot.Hook)
+	}
+	for _, change := range stateChanges(snapshot.State(), state) {
+		ctx.Infof("%s", change)
+	}
+	if hookErr != nil {
+		return errors.Annotatef(hookErr, "%s hook failed", snapshot.Hook)
+	}
+	return nil
+}
+
+// findJujud returns the path of the jujud executable to which the
+// hook tools of offline hooks are linked: the given path if any, or
+// the jujud next to the running juju.
+func findJujud(ctx *cmd.Context, jujud string) (string, error) {
+	if jujud == "" {
+		dir, err := tools.ExistingJujudLocation()
+		if err != nil {
+			return "", errors.Annotate(err, "cannot find jujud")
+		}
+		jujud = filepath.Join(dir, names.Jujud)
+	}
+	jujud = ctx.AbsPath(jujud)
+	if _, err := os.Stat(jujud); err != nil {
+		return "", errors.Annotate(err, "cannot find jujud, specify it with --jujud")
+	}
+	return jujud, nil
+}
+
+// stateChanges describes the changes to the unit's state made by the
+// hook tools run by a replayed hook.
+func stateChanges(before, after *offline.State) []string {
+	var changes []string
+	change := func(format string, args ...interface{}) {
+		changes = append(changes, fmt.Sprintf(format, args...))
+	}
+	if !reflect.DeepEqual(before.UnitStatus, after.UnitStatus) {
+		change("status-set: unit status %s %q", after.UnitStatus.Status, after.UnitStatus.Info)
+	}
+	if !reflect.DeepEqual(before.ApplicationStatus, after.ApplicationStatus) {
+		change("status-set: application status %s %q", after.ApplicationStatus.Status, after.ApplicationStatus.Info)
+	}
+	if before.WorkloadVersion != after.WorkloadVersion {
+		change("application-version-set: %s", after.WorkloadVersion)
+	}
+	for _, c := range settingsChanges(before.LeaderSettings, after.LeaderSettings) {
+		change("leader-set: %s", c)
+	}
+	ids := make([]int, 0, len(after.Relations))
+	for id := range after.Relations {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		relation := after.Relations[id]
+		var settings map[string]string
+		if old, ok := before.Relations[id]; ok {
+			settings = old.Settings
+		}
+		for _, c := range settingsChanges(settings, relation.Settings) {
+			change("relation-set %s:%d: %s", relation.Name, relation.Id, c)
+		}
+	}
+	if !reflect.DeepEqual(before.Ports, after.Ports) {
+		ports := make([]string, len(after.Ports))
+		for i, port := range after.Ports {
+			ports[i] = port.String()
+		}
+		change("opened ports: %v", ports)
+	}
+	if after.RebootPriority != jujuc.RebootSkip {
+		change("juju-reboot requested")
+	}
+	return changes
+}
+
+// settingsChanges describes the changes between two sets of settings.
+func settingsChanges(before, after map[string]string) []string {
+	var changes []string
+	for key, value := range after {
+		if old, ok := before[key]; !ok || old != value {
+			changes = append(changes, fmt.Sprintf("%s=%s", key, value))
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			changes = append(changes, fmt.Sprintf("%s removed", key))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
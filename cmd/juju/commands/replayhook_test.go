@@ -0,0 +1,123 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	"github.com/juju/juju/worker/uniter/runner/offline"
+)
+
+type ReplayHookSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	jujud  string
+	bundle string
+}
+
+var _ = gc.Suite(&ReplayHookSuite{})
+
+func (s *ReplayHookSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("hooks are replayed only on unix")
+	}
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	dir := c.MkDir()
+	s.jujud = filepath.Join(dir, "jujud")
+	err := ioutil.WriteFile(s.jujud, nil, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.bundle = filepath.Join(dir, "bundle.tar.gz")
+}
+
+func (s *ReplayHookSuite) writeBundle(c *gc.C, hook, script string) {
+	charmDir := c.MkDir()
+	err := os.Mkdir(filepath.Join(charmDir, "hooks"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(charmDir, "hooks", hook), []byte("#!/bin/bash\n"+script), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := offline.NewContext(&offline.State{UnitName: "mysql/0"})
+	snapshot, err := offline.NewSnapshot(ctx, hook, []string{"FOO=bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = offline.WriteBundle(&buf, snapshot, charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(s.bundle, buf.Bytes(), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ReplayHookSuite) TestInit(c *gc.C) {
+	err := cmdtesting.InitCommand(newReplayHookCommand(), nil)
+	c.Assert(err, gc.ErrorMatches, "no hook context bundle specified")
+	err = cmdtesting.InitCommand(newReplayHookCommand(), []string{"a", "b"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+}
+
+func (s *ReplayHookSuite) TestReplayHook(c *gc.C) {
+	s.writeBundle(c, "start", "echo $JUJU_UNIT_NAME $FOO\n")
+	ctx, err := cmdtesting.RunCommand(c, newReplayHookCommand(), "--jujud", s.jujud, s.bundle)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "mysql/0 bar\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Matches, "Replaying start hook of mysql/0 captured at .*\n")
+}
+
+func (s *ReplayHookSuite) TestReplayHookDir(c *gc.C) {
+	s.writeBundle(c, "start", "exit 0\n")
+	dir := filepath.Join(c.MkDir(), "replay")
+	_, err := cmdtesting.RunCommand(c, newReplayHookCommand(), "--jujud", s.jujud, "--dir", dir, s.bundle)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(filepath.Join(dir, offline.BundleCharmDir, "hooks", "start"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ReplayHookSuite) TestReplayHookFails(c *gc.C) {
+	s.writeBundle(c, "start", "exit 2\n")
+	_, err := cmdtesting.RunCommand(c, newReplayHookCommand(), "--jujud", s.jujud, s.bundle)
+	c.Assert(err, gc.ErrorMatches, "start hook failed: exit status 2")
+}
+
+func (s *ReplayHookSuite) TestReplayHookNoJujud(c *gc.C) {
+	s.writeBundle(c, "start", "exit 0\n")
+	_, err := cmdtesting.RunCommand(c, newReplayHookCommand(), "--jujud", "/no/such/jujud", s.bundle)
+	c.Assert(err, gc.ErrorMatches, "cannot find jujud, specify it with --jujud: .*")
+}
+
+func (s *ReplayHookSuite) TestStateChanges(c *gc.C) {
+	before := &offline.State{
+		LeaderSettings: map[string]string{"a": "1", "b": "2"},
+		Relations: map[int]*offline.Relation{
+			1: {Id: 1, Name: "db", Settings: map[string]string{"host": "10.0.0.1"}},
+		},
+	}
+	after := &offline.State{
+		UnitStatus:      jujuc.StatusInfo{Status: string(status.Active), Info: "ready"},
+		WorkloadVersion: "5.7",
+		LeaderSettings:  map[string]string{"a": "1", "c": "3"},
+		Relations: map[int]*offline.Relation{
+			1: {Id: 1, Name: "db", Settings: map[string]string{"host": "10.0.0.2"}},
+		},
+		Ports:          []network.PortRange{{FromPort: 80, ToPort: 80, Protocol: "tcp"}},
+		RebootPriority: jujuc.RebootAfterHook,
+	}
+	c.Assert(stateChanges(before, after), jc.DeepEquals, []string{
+		`status-set: unit status active "ready"`,
+		"application-version-set: 5.7",
+		"leader-set: b removed",
+		"leader-set: c=3",
+		"relation-set db:1: host=10.0.0.2",
+		"opened ports: [80/tcp]",
+		"juju-reboot requested",
+	})
+}
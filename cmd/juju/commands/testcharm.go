@@ -0,0 +1,166 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/worker/uniter/runner/offline"
+)
+
+func newTestCharmCommand() cmd.Command {
+	return &testCharmCommand{}
+}
+
+// testCharmCommand runs scenarios of hooks against a charm offline.
+type testCharmCommand struct {
+	cmd.CommandBase
+	charmDir  string
+	jujud     string
+	scenarios []string
+}
+
+const testCharmDoc = `
+Test a charm's hooks without a controller, by running the scenarios
+described in the given files.
+
+A scenario describes the state of a unit of the charm, a sequence of
+hooks and actions to run against the unit, and the state the unit is
+expected to be in afterwards. The hook tools run by the hooks are
+served from the unit's state in memory, so the scenario's steps see
+each other's changes. Anything else the hooks do, such as installing
+packages or writing files, is done on this machine, so beware.
+
+A scenario is written in YAML, for example:
+
+    unit: wordpress/0
+    state:
+      config:
+        blog-title: My Blog
+      leader: true
+      relations:
+      - id: 0
+        name: db
+        units:
+          mysql/0: {host: 10.0.0.1}
+    steps:
+    - hook: install
+    - hook: config-changed
+      config: {blog-title: New Title}
+    - hook: db-relation-changed
+      relation: 0
+      remote-unit: mysql/0
+      remote-settings: {host: 10.0.0.1, password: secret}
+      expect:
+        relation-settings:
+          0: {database: wordpress}
+    - action: backup
+      params: {target: /srv/backups}
+      expect:
+        action-results: {outcome: success}
+    expect:
+      unit-status: {status: active, message: Ready}
+      ports: [80/tcp]
+
+The state of a unit may also hold leader-settings, storage (by id, with
+kind and location), ports, unit-status, availability-zone,
+public-address and private-address.
+
+Each step runs a hook or an action. A step may change the config or
+leadership of the unit before it runs, and relation hooks may replace
+the settings of the remote unit. A step that is expected to fail is
+marked "fails: true"; any other failure ends the scenario. Hooks that
+the charm does not implement are skipped.
+
+Expectations may be given for unit-status, application-status,
+workload-version, leader-settings, relation-settings (by relation id),
+ports, storage-added (by storage name), reboot, action-results and
+action-failed. Only the settings listed are checked; a setting that is
+expected to be unset is given an empty value.
+
+The output of a scenario's hooks is shown only if it fails.
+
+The hook tools are links to the jujud executable, which is looked for
+next to the juju executable unless --jujud is specified.
+
+Examples:
+    juju test-charm scenarios/*.yaml
+    juju test-charm --charm-dir ./wordpress upgrade.yaml
+
+See also:
+    replay-hook
+`
+
+func (c *testCharmCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "test-charm",
+		Args:    "<scenario file> ...",
+		Purpose: "Test a charm's hooks offline.",
+		Doc:     testCharmDoc,
+	}
+}
+
+func (c *testCharmCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.charmDir, "charm-dir", ".", "The directory holding the charm under test")
+	f.StringVar(&c.jujud, "jujud", "", "The jujud executable providing the hook tools")
+}
+
+func (c *testCharmCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no scenario files specified")
+	}
+	c.scenarios = args
+	return nil
+}
+
+func (c *testCharmCommand) Run(ctx *cmd.Context) error {
+	jujud, err := findJujud(ctx, c.jujud)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	failed := 0
+	for _, path := range c.scenarios {
+		data, err := ioutil.ReadFile(ctx.AbsPath(path))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		scenario, err := offline.ParseScenario(data)
+		if err != nil {
+			return errors.Annotatef(err, "scenario %s", path)
+		}
+		var output bytes.Buffer
+		result, err := scenario.Run(offline.HookConfig{
+			CharmDir: ctx.AbsPath(c.charmDir),
+			Jujud:    jujud,
+			Stdout:   &output,
+			Stderr:   &output,
+		})
+		if err != nil {
+			return errors.Annotatef(err, "scenario %s", path)
+		}
+		if len(result.Failures) == 0 {
+			fmt.Fprintf(ctx.Stdout, "PASS %s\n", path)
+			continue
+		}
+		failed++
+		fmt.Fprintf(ctx.Stdout, "FAIL %s\n", path)
+		for _, failure := range result.Failures {
+			fmt.Fprintf(ctx.Stdout, "    %s\n", failure)
+		}
+		if output.Len() > 0 {
+			fmt.Fprintf(ctx.Stdout, "  hook output:\n")
+			ctx.Stdout.Write(output.Bytes())
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d of %d scenarios failed", failed, len(c.scenarios))
+	}
+	return nil
+}
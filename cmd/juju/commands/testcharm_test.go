@@ -0,0 +1,95 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+)
+
+type TestCharmSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	jujud    string
+	charmDir string
+	dir      string
+}
+
+var _ = gc.Suite(&TestCharmSuite{})
+
+func (s *TestCharmSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("charms are tested offline only on unix")
+	}
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.jujud = filepath.Join(s.dir, "jujud")
+	err := ioutil.WriteFile(s.jujud, nil, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.charmDir = filepath.Join(s.dir, "charm")
+	err = os.MkdirAll(filepath.Join(s.charmDir, "hooks"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(s.charmDir, "hooks", "install"), []byte("#!/bin/bash\necho installing\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *TestCharmSuite) writeScenario(c *gc.C, name, scenario string) string {
+	path := filepath.Join(s.dir, name)
+	err := ioutil.WriteFile(path, []byte(scenario), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *TestCharmSuite) TestInit(c *gc.C) {
+	err := cmdtesting.InitCommand(newTestCharmCommand(), nil)
+	c.Assert(err, gc.ErrorMatches, "no scenario files specified")
+}
+
+func (s *TestCharmSuite) TestPass(c *gc.C) {
+	path := s.writeScenario(c, "pass.yaml", `
+unit: wordpress/0
+state:
+  ports: [80/tcp]
+steps:
+- hook: install
+- hook: start
+expect:
+  ports: [80/tcp]
+`)
+	ctx, err := cmdtesting.RunCommand(c, newTestCharmCommand(), "--jujud", s.jujud, "--charm-dir", s.charmDir, path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "PASS "+path+"\n")
+}
+
+func (s *TestCharmSuite) TestFail(c *gc.C) {
+	pass := s.writeScenario(c, "pass.yaml", "unit: wordpress/0\nsteps: [{hook: install}]\n")
+	fail := s.writeScenario(c, "fail.yaml", `
+unit: wordpress/0
+steps:
+- hook: install
+expect:
+  workload-version: "1.0"
+`)
+	ctx, err := cmdtesting.RunCommand(c, newTestCharmCommand(), "--jujud", s.jujud, "--charm-dir", s.charmDir, pass, fail)
+	c.Assert(err, gc.ErrorMatches, "1 of 2 scenarios failed")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"PASS "+pass+"\n"+
+		"FAIL "+fail+"\n"+
+		"    workload version: expected \"1.0\", got \"\"\n"+
+		"  hook output:\n"+
+		"installing\n")
+}
+
+func (s *TestCharmSuite) TestInvalidScenario(c *gc.C) {
+	path := s.writeScenario(c, "invalid.yaml", "unit: wordpress/0\n")
+	_, err := cmdtesting.RunCommand(c, newTestCharmCommand(), "--jujud", s.jujud, "--charm-dir", s.charmDir, path)
+	c.Assert(err, gc.ErrorMatches, "scenario .*invalid.yaml: scenario without steps not valid")
+}
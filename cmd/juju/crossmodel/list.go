@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/api/applicationoffers"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/jujuclient"
 )
@@ -120,12 +121,12 @@ func (c *listCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.consumerName, "allowed-consumer", "", "return results where the user is allowed to consume the offer")
 	f.StringVar(&c.connectedUserName, "connected-user", "", "return results where the user has a connection to the offer")
 	f.BoolVar(&c.activeOnly, "active-only", false, "only return results where the offer is in use")
-	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+	c.out.AddFlags(f, "tabular", output.AddReportFormatters(f, map[string]cmd.Formatter{
 		"yaml":    cmd.FormatYaml,
 		"json":    cmd.FormatJson,
 		"tabular": formatListTabular,
 		"summary": formatListSummary,
-	})
+	}))
 }
 
 // Run implements Command.Run.
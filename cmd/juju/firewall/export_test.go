@@ -30,3 +30,14 @@ func NewSetRulesCommandForTest(
 	}
 	return modelcmd.Wrap(aCmd)
 }
+
+func NewSetEgressRuleCommandForTest(
+	api SetEgressRuleAPI,
+) cmd.Command {
+	aCmd := &setEgressRuleCommand{
+		newAPIFunc: func() (SetEgressRuleAPI, error) {
+			return api, nil
+		},
+	}
+	return modelcmd.Wrap(aCmd)
+}
@@ -15,15 +15,29 @@ import (
 
 type firewallRule struct {
 	KnownService   string   `yaml:"known-service" json:"known-service"`
+	Application    string   `yaml:"application,omitempty" json:"application,omitempty"`
+	PortRange      string   `yaml:"port-range,omitempty" json:"port-range,omitempty"`
 	WhitelistCIDRS []string `yaml:"whitelist-subnets,omitempty" json:"whitelist-subnets,omitempty"`
 }
 
+// service returns the name to display for the rule's service,
+// which is the application name for application rules.
+func (r firewallRule) service() string {
+	if r.Application != "" {
+		return r.Application
+	}
+	return r.KnownService
+}
+
 type firewallRules []firewallRule
 
 func (o firewallRules) Len() int      { return len(o) }
 func (o firewallRules) Swap(i, j int) { o[i], o[j] = o[j], o[i] }
 func (o firewallRules) Less(i, j int) bool {
-	return o[i].KnownService < o[j].KnownService
+	if o[i].service() != o[j].service() {
+		return o[i].service() < o[j].service()
+	}
+	return o[i].PortRange < o[j].PortRange
 }
 
 func formatListTabular(writer io.Writer, value interface{}) error {
@@ -42,9 +56,27 @@ func formatFirewallRulesTabular(writer io.Writer, rules firewallRules) {
 
 	sort.Sort(rules)
 
-	w.Println("Service", "Whitelist subnets")
+	// Only show the ports column if any of the rules are
+	// limited to specific application ports.
+	var showPorts bool
+	for _, rule := range rules {
+		if rule.PortRange != "" {
+			showPorts = true
+			break
+		}
+	}
+
+	if showPorts {
+		w.Println("Service", "Ports", "Whitelist subnets")
+	} else {
+		w.Println("Service", "Whitelist subnets")
+	}
 	for _, rule := range rules {
-		w.Println(rule.KnownService, strings.Join(rule.WhitelistCIDRS, ","))
+		if showPorts {
+			w.Println(rule.service(), rule.PortRange, strings.Join(rule.WhitelistCIDRS, ","))
+		} else {
+			w.Println(rule.service(), strings.Join(rule.WhitelistCIDRS, ","))
+		}
 	}
 	tw.Flush()
 }
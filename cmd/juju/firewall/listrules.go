@@ -18,7 +18,7 @@ Prints the firewall rules.`[1:]
 
 var listRulesHelpDetails = `
 Lists the firewall rules which control ingress to well known services
-within a Juju model.
+and exposed applications within a Juju model.
 
 Examples:
     juju list-firewall-rules
@@ -94,8 +94,12 @@ func (c *listFirewallRulesCommand) Run(ctx *cmd.Context) error {
 	for i, r := range rulesResult {
 		rules[i] = firewallRule{
 			KnownService:   string(r.KnownService),
+			Application:    r.Application,
 			WhitelistCIDRS: r.WhitelistCIDRS,
 		}
+		if r.PortRange != nil {
+			rules[i].PortRange = r.PortRange.NetworkPortRange().String()
+		}
 	}
 	return c.out.Write(ctx, rules)
 }
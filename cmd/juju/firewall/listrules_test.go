@@ -76,6 +76,32 @@ func (s *ListSuite) TestListYAML(c *gc.C) {
 	)
 }
 
+func (s *ListSuite) TestListTabularApplicationRules(c *gc.C) {
+	s.mockAPI.rules = append(s.mockAPI.rules, params.FirewallRule{
+		KnownService:   params.ApplicationRule,
+		Application:    "wordpress",
+		PortRange:      &params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		WhitelistCIDRS: []string{"10.0.0.0/8"},
+	}, params.FirewallRule{
+		KnownService:   params.ApplicationRule,
+		Application:    "mysql",
+		WhitelistCIDRS: []string{"10.1.0.0/16"},
+	})
+	s.assertValidList(
+		c,
+		[]string{"--format", "tabular"},
+		`
+Service          Ports   Whitelist subnets
+juju-controller          10.2.0.0/16
+mysql                    10.1.0.0/16
+ssh                      192.168.1.0/16,10.0.0.0/8
+wordpress        80/tcp  10.0.0.0/8
+
+`[1:],
+		"",
+	)
+}
+
 func (s *ListSuite) runList(c *gc.C, args []string) (*cmd.Context, error) {
 	return cmdtesting.RunCommand(c, firewall.NewListRulesCommandForTest(s.mockAPI), args...)
 }
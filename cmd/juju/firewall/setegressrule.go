@@ -0,0 +1,146 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/firewallrules"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/network"
+)
+
+var setEgressRuleHelpSummary = `
+Sets the egress rules for an application.`[1:]
+
+var setEgressRuleHelpDetails = `
+Egress rules restrict the destinations to which the units of an
+application may send traffic. By default outbound traffic is not
+restricted; once egress rules are set, the machines hosting the
+application's units may only connect to the specified port ranges
+on the specified subnets.
+
+Each invocation replaces all of the application's existing egress
+rules. The --clear option removes all egress rules, lifting any
+restriction on outbound traffic.
+
+Egress rules are only enforced on clouds which support them.
+
+Examples:
+    juju set-egress-rule wordpress --ports 3306/tcp --to-cidrs 10.0.0.0/8
+    juju set-egress-rule wordpress --ports 80/tcp,443/tcp --to-cidrs 0.0.0.0/0
+    juju set-egress-rule wordpress --clear
+
+See also:
+    set-firewall-rule`
+
+// NewSetEgressRuleCommand returns a command to set application egress rules.
+func NewSetEgressRuleCommand() cmd.Command {
+	cmd := &setEgressRuleCommand{}
+	cmd.newAPIFunc = func() (SetEgressRuleAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return firewallrules.NewClient(root), nil
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+type setEgressRuleCommand struct {
+	modelcmd.ModelCommandBase
+	cidrsValue string
+	portsValue string
+	clear      bool
+
+	application string
+	rules       []network.EgressRule
+	newAPIFunc  func() (SetEgressRuleAPI, error)
+}
+
+// Info implements cmd.Command.
+func (c *setEgressRuleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-egress-rule",
+		Args:    "<application> (--ports <port-range>[,<port-range>...] --to-cidrs <cidr>[,<cidr>...] | --clear)",
+		Purpose: setEgressRuleHelpSummary,
+		Doc:     setEgressRuleHelpDetails,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *setEgressRuleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.cidrsValue, "to-cidrs", "", "list of subnets to which traffic is allowed")
+	f.StringVar(&c.portsValue, "ports", "", "list of destination port ranges to which traffic is allowed")
+	f.BoolVar(&c.clear, "clear", false, "remove all egress rules for the application")
+}
+
+// Init implements cmd.Command.
+func (c *setEgressRuleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no application specified")
+	}
+	c.application = args[0]
+	if !names.IsValidApplication(c.application) {
+		return errors.Errorf("invalid application name %q", c.application)
+	}
+	if err := cmd.CheckEmpty(args[1:]); err != nil {
+		return err
+	}
+	if c.clear {
+		if c.cidrsValue != "" || c.portsValue != "" {
+			return errors.New("--clear cannot be used with --ports or --to-cidrs")
+		}
+		return nil
+	}
+	if c.portsValue == "" {
+		return errors.New("no port ranges specified")
+	}
+	if c.cidrsValue == "" {
+		return errors.New("no destination subnets specified")
+	}
+	var cidrs []string
+	for _, cidr := range strings.Split(c.cidrsValue, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.Annotate(err, "invalid destination subnet")
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	for _, portStr := range strings.Split(c.portsValue, ",") {
+		portRange, err := network.ParsePortRange(strings.TrimSpace(portStr))
+		if err != nil {
+			return errors.Annotate(err, "invalid port range")
+		}
+		c.rules = append(c.rules, network.EgressRule{
+			PortRange:        portRange,
+			DestinationCIDRs: cidrs,
+		})
+	}
+	return nil
+}
+
+// SetEgressRuleAPI defines the API methods that the set egress rule command uses.
+type SetEgressRuleAPI interface {
+	Close() error
+	SetEgressRules(application string, rules []network.EgressRule) error
+}
+
+// Run implements cmd.Command.
+func (c *setEgressRuleCommand) Run(_ *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.SetEgressRules(c.application, c.rules)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,109 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/firewall"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type SetEgressRuleSuite struct {
+	testing.BaseSuite
+
+	mockAPI *mockSetEgressRuleAPI
+}
+
+var _ = gc.Suite(&SetEgressRuleSuite{})
+
+func (s *SetEgressRuleSuite) SetUpTest(c *gc.C) {
+	s.mockAPI = &mockSetEgressRuleAPI{}
+}
+
+func (s *SetEgressRuleSuite) TestInitMissingApplication(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--ports", "443/tcp", "--to-cidrs", "10.0.0.0/8")
+	c.Assert(err, gc.ErrorMatches, "no application specified")
+}
+
+func (s *SetEgressRuleSuite) TestInitInvalidApplication(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--ports", "443/tcp", "--to-cidrs", "10.0.0.0/8", "-foo")
+	c.Assert(err, gc.ErrorMatches, `invalid application name "-foo"`)
+}
+
+func (s *SetEgressRuleSuite) TestInitMissingPorts(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--to-cidrs", "10.0.0.0/8", "wordpress")
+	c.Assert(err, gc.ErrorMatches, "no port ranges specified")
+}
+
+func (s *SetEgressRuleSuite) TestInitMissingCIDRs(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--ports", "443/tcp", "wordpress")
+	c.Assert(err, gc.ErrorMatches, "no destination subnets specified")
+}
+
+func (s *SetEgressRuleSuite) TestInitInvalidCIDRs(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--ports", "443/tcp", "--to-cidrs", "foo", "wordpress")
+	c.Assert(err, gc.ErrorMatches, "invalid destination subnet: invalid CIDR address: foo")
+}
+
+func (s *SetEgressRuleSuite) TestInitInvalidPorts(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--ports", "foo", "--to-cidrs", "10.0.0.0/8", "wordpress")
+	c.Assert(err, gc.ErrorMatches, "invalid port range: .*")
+}
+
+func (s *SetEgressRuleSuite) TestInitClearWithRules(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--clear", "--ports", "443/tcp", "wordpress")
+	c.Assert(err, gc.ErrorMatches, "--clear cannot be used with --ports or --to-cidrs")
+}
+
+func (s *SetEgressRuleSuite) TestSetEgressRules(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--ports", "80/tcp, 443/tcp", "--to-cidrs", "10.0.0.0/8,192.168.1.0/24", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.application, gc.Equals, "wordpress")
+	c.Assert(s.mockAPI.rules, jc.DeepEquals, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 80, 80, "10.0.0.0/8", "192.168.1.0/24"),
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8", "192.168.1.0/24"),
+	})
+}
+
+func (s *SetEgressRuleSuite) TestClearEgressRules(c *gc.C) {
+	_, err := s.runSetEgressRule(c, "--clear", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.application, gc.Equals, "wordpress")
+	c.Assert(s.mockAPI.rules, gc.HasLen, 0)
+}
+
+func (s *SetEgressRuleSuite) TestSetError(c *gc.C) {
+	s.mockAPI.err = errors.New("fail")
+	_, err := s.runSetEgressRule(c, "--clear", "wordpress")
+	c.Assert(err, gc.ErrorMatches, ".*fail.*")
+}
+
+func (s *SetEgressRuleSuite) runSetEgressRule(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, firewall.NewSetEgressRuleCommandForTest(s.mockAPI), args...)
+}
+
+type mockSetEgressRuleAPI struct {
+	application string
+	rules       []network.EgressRule
+	err         error
+}
+
+func (s *mockSetEgressRuleAPI) Close() error {
+	return nil
+}
+
+func (s *mockSetEgressRuleAPI) SetEgressRules(application string, rules []network.EgressRule) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.application = application
+	s.rules = rules
+	return nil
+}
@@ -10,12 +10,14 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
 
 	"fmt"
 	"github.com/juju/juju/api/firewallrules"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/network"
 )
 
 var setRuleHelpSummary = `
@@ -28,10 +30,19 @@ and a whitelist of allowed ingress subnets.
 The currently supported services are:
 %v
 
+Any other service name is taken to be the name of an application.
+An application rule restricts ingress to the ports opened by the
+units of the application when it is exposed; without such a rule,
+an exposed application is open to 0.0.0.0/0. The --ports option
+limits an application rule to the specified port ranges, which
+take precedence over a rule for all of the application's ports.
+
 Examples:
     juju set-firewall-rule ssh --whitelist 192.168.1.0/16
     juju set-firewall-rule juju-controller --whitelist 192.168.1.0/16
     juju set-firewall-rule juju-application-offer --whitelist 192.168.1.0/16
+    juju set-firewall-rule mysql --whitelist 10.0.0.0/8
+    juju set-firewall-rule wordpress --ports 80/tcp,8000-8080/tcp --whitelist 10.0.0.0/8
 
 See also: 
     list-firewall-rules`
@@ -54,9 +65,12 @@ type setFirewallRuleCommand struct {
 	modelcmd.ModelCommandBase
 	service        string
 	whitelistValue string
+	portsValue     string
 
-	whiteList  []string
-	newAPIFunc func() (SetFirewallRuleAPI, error)
+	application string
+	whiteList   []string
+	portRanges  []network.PortRange
+	newAPIFunc  func() (SetFirewallRuleAPI, error)
 }
 
 // Info implements cmd.Command.
@@ -68,7 +82,7 @@ func (c *setFirewallRuleCommand) Info() *cmd.Info {
 	}
 	return &cmd.Info{
 		Name:    "set-firewall-rule",
-		Args:    "<service-name>, --whitelist <cidr>[,<cidr>...]",
+		Args:    "<service-name>|<application>, --whitelist <cidr>[,<cidr>...]",
 		Purpose: setRuleHelpSummary,
 		Doc:     fmt.Sprintf(setRuleHelpDetails, strings.Join(supportedRules, "\n")),
 	}
@@ -77,6 +91,7 @@ func (c *setFirewallRuleCommand) Info() *cmd.Info {
 // SetFlags implements cmd.Command.
 func (c *setFirewallRuleCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.whitelistValue, "whitelist", "", "list of subnets to whitelist")
+	f.StringVar(&c.portsValue, "ports", "", "list of application port ranges to which the rule applies")
 }
 
 // Init implements cmd.Command.
@@ -89,6 +104,19 @@ func (c *setFirewallRuleCommand) Init(args []string) (err error) {
 		if err := c.parseCIDRs(&c.whiteList, c.whitelistValue); err != nil {
 			return errors.Annotate(err, "invalid white-list subnet")
 		}
+		if isWellKnownService(c.service) {
+			if c.portsValue != "" {
+				return errors.Errorf("--ports is only valid for application rules")
+			}
+			return nil
+		}
+		if !names.IsValidApplication(c.service) {
+			return errors.Errorf("%q is not a well known service or application name", c.service)
+		}
+		c.application = c.service
+		if err := c.parsePortRanges(c.portsValue); err != nil {
+			return errors.Annotate(err, "invalid port range")
+		}
 		return nil
 	}
 	if len(args) == 0 {
@@ -112,10 +140,35 @@ func (c *setFirewallRuleCommand) parseCIDRs(cidrs *[]string, value string) error
 	return nil
 }
 
+func (c *setFirewallRuleCommand) parsePortRanges(value string) error {
+	if value == "" {
+		return nil
+	}
+	for _, portStr := range strings.Split(value, ",") {
+		portRange, err := network.ParsePortRange(strings.TrimSpace(portStr))
+		if err != nil {
+			return err
+		}
+		c.portRanges = append(c.portRanges, portRange)
+	}
+	return nil
+}
+
+// isWellKnownService returns whether the specified service name
+// refers to one of the well known services rather than an application.
+func isWellKnownService(service string) bool {
+	switch params.KnownServiceValue(service) {
+	case params.SSHRule, params.JujuControllerRule, params.JujuApplicationOfferRule:
+		return true
+	}
+	return false
+}
+
 // SetFirewallRuleAPI defines the API methods that the set firewall rules command uses.
 type SetFirewallRuleAPI interface {
 	Close() error
 	SetFirewallRule(service string, whiteListCidrs []string) error
+	SetApplicationFirewallRule(application string, portRanges []network.PortRange, whiteListCidrs []string) error
 }
 
 func (c *setFirewallRuleCommand) Run(_ *cmd.Context) error {
@@ -124,6 +177,10 @@ func (c *setFirewallRuleCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	err = client.SetFirewallRule(c.service, c.whiteList)
+	if c.application != "" {
+		err = client.SetApplicationFirewallRule(c.application, c.portRanges, c.whiteList)
+	} else {
+		err = client.SetFirewallRule(c.service, c.whiteList)
+	}
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
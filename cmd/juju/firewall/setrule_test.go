@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/firewall"
+	"github.com/juju/juju/network"
 )
 
 type SetRuleSuite struct {
@@ -51,6 +52,43 @@ func (s *SetRuleSuite) TestSetRule(c *gc.C) {
 	})
 }
 
+func (s *SetRuleSuite) TestInitInvalidService(c *gc.C) {
+	_, err := s.runSetRule(c, "--whitelist", "10.0.0.0/8", "-foo")
+	c.Assert(err, gc.ErrorMatches, `"-foo" is not a well known service or application name`)
+}
+
+func (s *SetRuleSuite) TestInitPortsForWellKnownService(c *gc.C) {
+	_, err := s.runSetRule(c, "--whitelist", "10.0.0.0/8", "--ports", "22/tcp", "ssh")
+	c.Assert(err, gc.ErrorMatches, `--ports is only valid for application rules`)
+}
+
+func (s *SetRuleSuite) TestInitInvalidPorts(c *gc.C) {
+	_, err := s.runSetRule(c, "--whitelist", "10.0.0.0/8", "--ports", "foo", "mysql")
+	c.Assert(err, gc.ErrorMatches, `invalid port range: .*`)
+}
+
+func (s *SetRuleSuite) TestSetApplicationRule(c *gc.C) {
+	_, err := s.runSetRule(c, "--whitelist", "10.2.1.0/8", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.application, gc.Equals, "mysql")
+	c.Assert(s.mockAPI.portRanges, gc.HasLen, 0)
+	c.Assert(s.mockAPI.rule, jc.DeepEquals, params.FirewallRule{
+		KnownService:   params.ApplicationRule,
+		Application:    "mysql",
+		WhitelistCIDRS: []string{"10.2.1.0/8"},
+	})
+}
+
+func (s *SetRuleSuite) TestSetApplicationRuleWithPorts(c *gc.C) {
+	_, err := s.runSetRule(c, "--whitelist", "10.2.1.0/8", "--ports", "80/tcp, 8000-8080/tcp", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.application, gc.Equals, "wordpress")
+	c.Assert(s.mockAPI.portRanges, jc.DeepEquals, []network.PortRange{
+		network.MustParsePortRange("80/tcp"),
+		network.MustParsePortRange("8000-8080/tcp"),
+	})
+}
+
 func (s *SetRuleSuite) TestSetError(c *gc.C) {
 	s.mockAPI.err = errors.New("fail")
 	_, err := s.runSetRule(c, "ssh", "--whitelist", "10.0.0.0/8")
@@ -62,8 +100,10 @@ func (s *SetRuleSuite) runSetRule(c *gc.C, args ...string) (*cmd.Context, error)
 }
 
 type mockSetRuleAPI struct {
-	rule params.FirewallRule
-	err  error
+	rule        params.FirewallRule
+	application string
+	portRanges  []network.PortRange
+	err         error
 }
 
 func (s *mockSetRuleAPI) Close() error {
@@ -80,3 +120,17 @@ func (s *mockSetRuleAPI) SetFirewallRule(service string, whiteListCidrs []string
 	}
 	return nil
 }
+
+func (s *mockSetRuleAPI) SetApplicationFirewallRule(application string, portRanges []network.PortRange, whiteListCidrs []string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.application = application
+	s.portRanges = portRanges
+	s.rule = params.FirewallRule{
+		KnownService:   params.ApplicationRule,
+		Application:    application,
+		WhitelistCIDRS: whiteListCidrs,
+	}
+	return nil
+}
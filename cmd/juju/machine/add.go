@@ -57,6 +57,20 @@ and bringing it under Juju's management; currently this requires that the
 machine be running Ubuntu, that it be accessible via SSH, and be running on
 the same network as the API server.
 
+Many existing machines can be provisioned at once by listing them in a
+YAML file passed with "--from-file", in the form:
+
+    hosts:
+      - user@10.10.0.3
+      - 10.10.0.4
+
+The machines are provisioned over SSH, up to "--parallel" at a time, and
+the outcome is reported for each of them. As several machines are being
+provisioned at once, each must accept the SSH key of the current user and
+allow passwordless sudo; no password prompts are shown. Before a machine
+is provisioned, its series is detected and checked, and it is checked
+for free disk space and for an existing Juju agent.
+
 It is possible to override or augment constraints by passing provider-specific
 "placement directives" as an argument; these give the provider additional
 information about how to allocate the machine. For example, one can direct the
@@ -71,6 +85,8 @@ Examples:
    juju add-machine --constraints mem=8G (starts a machine with at least 8GB RAM)
    juju add-machine ssh:user@10.10.0.3   (manually provisions machine with ssh)
    juju add-machine winrm:user@10.10.0.3 (manually provisions machine with winrm)
+   juju add-machine --from-file hosts.yaml --parallel 20
+                                         (manually provisions the machines listed in hosts.yaml)
    juju add-machine zone=us-east-1a      (start a machine in zone us-east-1a on AWS)
    juju add-machine maas2.name           (acquire machine maas2.name on MAAS)
 
@@ -114,6 +130,10 @@ type addCommand struct {
 	NumMachines int
 	// Disks describes disks that are to be attached to the machine.
 	Disks []storage.Constraints
+	// HostsFile is the path of a file listing hosts to provision manually.
+	HostsFile string
+	// Parallel is the number of hosts from HostsFile to provision at once.
+	Parallel int
 }
 
 func (c *addCommand) Info() *cmd.Info {
@@ -131,6 +151,8 @@ func (c *addCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumMachines, "n", 1, "The number of machines to add")
 	f.StringVar(&c.ConstraintsStr, "constraints", "", "Additional machine constraints")
 	f.Var(disksFlag{&c.Disks}, "disks", "Constraints for disks to attach to the machine")
+	f.StringVar(&c.HostsFile, "from-file", "", "Path to a YAML file listing existing machines to provision over SSH")
+	f.IntVar(&c.Parallel, "parallel", 10, "The number of machines from --from-file to provision at once")
 }
 
 func (c *addCommand) Init(args []string) error {
@@ -141,6 +163,18 @@ func (c *addCommand) Init(args []string) error {
 	if err != nil {
 		return err
 	}
+	if c.HostsFile != "" {
+		if placement != "" {
+			return errors.New("cannot use --from-file when specifying a placement directive")
+		}
+		if c.NumMachines != 1 || c.Series != "" || c.ConstraintsStr != "" || len(c.Disks) > 0 {
+			return errors.New("cannot use -n, --series, --constraints or --disks with --from-file")
+		}
+		if c.Parallel < 1 {
+			return errors.New("--parallel must be at least 1")
+		}
+		return nil
+	}
 	c.Placement, err = instance.ParsePlacement(placement)
 	if err == instance.ErrPlacementScopeMissing {
 		placement = "model-uuid" + ":" + placement
@@ -260,6 +294,10 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
+	if c.HostsFile != "" {
+		return c.addFromFile(client, config, ctx)
+	}
+
 	if c.Placement != nil {
 		err := c.tryManualProvision(client, config, ctx)
 		if err != errNonManualScope {
@@ -4,8 +4,11 @@
 package machine_test
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
@@ -156,6 +159,114 @@ func (s *AddMachineSuite) TestSSHPlacementError(c *gc.C) {
 	c.Assert(cmdtesting.Stderr(context), gc.Equals, "")
 }
 
+func (s *AddMachineSuite) writeHostsFile(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "hosts.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *AddMachineSuite) TestInitFromFile(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		errorString string
+	}{{
+		args: []string{"--from-file", "hosts.yaml"},
+	}, {
+		args: []string{"--from-file", "hosts.yaml", "--parallel", "50"},
+	}, {
+		args:        []string{"--from-file", "hosts.yaml", "ssh:10.0.0.1"},
+		errorString: "cannot use --from-file when specifying a placement directive",
+	}, {
+		args:        []string{"--from-file", "hosts.yaml", "-n", "2"},
+		errorString: "cannot use -n, --series, --constraints or --disks with --from-file",
+	}, {
+		args:        []string{"--from-file", "hosts.yaml", "--series", "xenial"},
+		errorString: "cannot use -n, --series, --constraints or --disks with --from-file",
+	}, {
+		args:        []string{"--from-file", "hosts.yaml", "--parallel", "0"},
+		errorString: "--parallel must be at least 1",
+	}} {
+		c.Logf("test %d", i)
+		wrappedCommand, _ := machine.NewAddCommandForTest(s.fakeAddMachine, s.fakeAddMachine, s.fakeMachineManager)
+		err := cmdtesting.InitCommand(wrappedCommand, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *AddMachineSuite) TestAddFromFile(c *gc.C) {
+	var (
+		mu       sync.Mutex
+		provided []manual.ProvisionMachineArgs
+	)
+	s.PatchValue(machine.SSHProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		provided = append(provided, args)
+		switch args.Host {
+		case "10.0.0.2":
+			return "", manual.ErrProvisioned
+		case "10.0.0.3":
+			return "", errors.New("not enough free disk space")
+		}
+		return "machine-" + args.Host, nil
+	})
+	path := s.writeHostsFile(c, `
+hosts:
+  - admin@10.0.0.1
+  - 10.0.0.2
+  - 10.0.0.3
+  - 10.0.0.4
+`)
+	ctx, err := s.run(c, "--from-file", path, "--parallel", "2")
+	c.Assert(err, gc.ErrorMatches, "failed to provision 2 of 4 hosts")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+provisioning 4 hosts, 2 at a time
+created machine machine-10.0.0.1 for 10.0.0.1
+failed to provision 10.0.0.2: machine is already provisioned
+failed to provision 10.0.0.3: not enough free disk space
+created machine machine-10.0.0.4 for 10.0.0.4
+`[1:])
+
+	c.Assert(provided, gc.HasLen, 4)
+	for _, args := range provided {
+		if args.Host == "10.0.0.1" {
+			c.Check(args.User, gc.Equals, "admin")
+		} else {
+			c.Check(args.User, gc.Equals, "")
+		}
+	}
+	c.Assert(s.fakeAddMachine.args, gc.HasLen, 0)
+}
+
+func (s *AddMachineSuite) TestAddFromFileInvalid(c *gc.C) {
+	for i, test := range []struct {
+		content     string
+		errorString string
+	}{{
+		content:     "hosts: []",
+		errorString: "no hosts specified in .*",
+	}, {
+		content:     "hosts:\n  - 10.0.0.1\n  - ubuntu@10.0.0.1",
+		errorString: `host "10.0.0.1" specified more than once in .*`,
+	}, {
+		content:     "hosts:\n  - ubuntu@",
+		errorString: `invalid host "ubuntu@" in .*`,
+	}, {
+		content:     "machines:\n  - 10.0.0.1",
+		errorString: "cannot parse .*",
+	}} {
+		c.Logf("test %d", i)
+		path := s.writeHostsFile(c, test.content)
+		_, err := s.run(c, "--from-file", path)
+		c.Check(err, gc.ErrorMatches, test.errorString)
+	}
+}
+
 func (s *AddMachineSuite) TestParamsPassedOn(c *gc.C) {
 	_, err := s.run(c, "--constraints", "mem=8G", "--series=special", "zone=nz")
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,107 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/manual"
+)
+
+// hostsFile is the format of the file read by "add-machine --from-file".
+type hostsFile struct {
+	// Hosts holds the hosts to enlist, as [user@]host.
+	Hosts []string `yaml:"hosts"`
+}
+
+// readHostsFile reads the hosts to enlist from the file at path.
+func readHostsFile(path string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var file hostsFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse %s", path)
+	}
+	if len(file.Hosts) == 0 {
+		return nil, errors.Errorf("no hosts specified in %s", path)
+	}
+	seen := make(map[string]bool)
+	for _, userHost := range file.Hosts {
+		_, host := splitUserHost(userHost)
+		if host == "" {
+			return nil, errors.Errorf("invalid host %q in %s", userHost, path)
+		}
+		if seen[host] {
+			return nil, errors.Errorf("host %q specified more than once in %s", host, path)
+		}
+		seen[host] = true
+	}
+	return file.Hosts, nil
+}
+
+// addFromFile enlists the hosts listed in the hosts file, provisioning
+// up to c.Parallel of them at a time, and reports the outcome for each.
+func (c *addCommand) addFromFile(client AddMachineAPI, config *config.Config, ctx *cmd.Context) error {
+	hosts, err := readHostsFile(ctx.AbsPath(c.HostsFile))
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	authKeys, err := common.ReadAuthorizedKeys(ctx, "")
+	if err != nil {
+		return errors.Annotatef(err, "cannot read authorized-keys")
+	}
+
+	args := make([]manual.ProvisionMachineArgs, len(hosts))
+	outputs := make([]*bytes.Buffer, len(hosts))
+	for i, userHost := range hosts {
+		user, host := splitUserHost(userHost)
+		// Several hosts are provisioned at once, so we can't
+		// share the terminal with them. Any password prompt
+		// fails rather than waiting for input.
+		outputs[i] = new(bytes.Buffer)
+		args[i] = manual.ProvisionMachineArgs{
+			Host:           host,
+			User:           user,
+			Client:         client,
+			Stdin:          strings.NewReader(""),
+			Stdout:         outputs[i],
+			Stderr:         outputs[i],
+			AuthorizedKeys: authKeys,
+			UpdateBehavior: &params.UpdateBehavior{
+				EnableOSRefreshUpdate: config.EnableOSRefreshUpdate(),
+				EnableOSUpgrade:       config.EnableOSUpgrade(),
+			},
+		}
+	}
+
+	ctx.Infof("provisioning %d hosts, %d at a time", len(hosts), c.Parallel)
+	results := manual.ProvisionMachines(sshProvisioner, args, c.Parallel)
+
+	failed := 0
+	for i, result := range results {
+		if result.Error != nil {
+			failed++
+			ctx.Infof("failed to provision %s: %v", result.Host, result.Error)
+			logger.Debugf("output provisioning %s:\n%s", result.Host, outputs[i])
+			continue
+		}
+		ctx.Infof("created machine %v for %s", result.MachineId, result.Host)
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to provision %d of %d hosts", failed, len(results))
+	}
+	return nil
+}
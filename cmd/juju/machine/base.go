@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/status"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
 )
 
 // statusAPI defines the API methods for the machines and show-machine commands.
@@ -38,11 +39,11 @@ func (c *baselistMachinesCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
 	f.BoolVar(&c.color, "color", false, "Force use of ANSI color codes")
-	c.out.AddFlags(f, c.defaultFormat, map[string]cmd.Formatter{
+	c.out.AddFlags(f, c.defaultFormat, output.AddReportFormatters(f, map[string]cmd.Formatter{
 		"yaml":    cmd.FormatYaml,
 		"json":    cmd.FormatJson,
 		"tabular": c.tabular,
-	})
+	}))
 }
 
 var newAPIClientForMachines = func(c *baselistMachinesCommand) (statusAPI, error) {
@@ -0,0 +1,479 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/charmrepo.v2-unstable"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/constraints"
+)
+
+// hoursPerMonth is the number of hours used to turn an hourly cost
+// into a monthly one.
+const hoursPerMonth = 730
+
+const estimateCostCommandDoc = `
+Estimates the cost of running the machines of a bundle, or of the
+current model if no bundle is given.
+
+The constraints of each machine are resolved to the cheapest matching
+instance type of the model's cloud and region, and the hourly cost of
+that instance type is used as the cost of the machine. Monthly costs
+assume the machine runs for 730 hours a month. Containers run on their
+host machine, so they have no cost of their own.
+
+A machine's cost is split evenly between the applications with units
+on it. Machines for which no cost is known are reported, but not
+included in the totals.
+
+Bundles are read from a local file or directory. Bundle units without
+a placement directive are costed on a new machine using the constraints
+of their application, combined with the constraints of the model.
+
+The cost is only as accurate as the cost metadata of the cloud, and
+does not include storage, network or other charges. Not all clouds
+provide cost metadata.
+
+Examples:
+    juju estimate-cost
+    juju estimate-cost ./bundle.yaml
+    juju estimate-cost ./bundle.yaml --format=json
+
+See also:
+    deploy
+    get-model-constraints
+`
+
+// EstimateCostAPI defines the API methods used by the estimate-cost
+// command.
+type EstimateCostAPI interface {
+	Status(pattern []string) (*params.FullStatus, error)
+	GetModelConstraints() (constraints.Value, error)
+	InstanceTypes(cons []constraints.Value) ([]params.InstanceTypesResult, error)
+	Close() error
+}
+
+// NewEstimateCostCommand returns a command that estimates the cost of
+// running a bundle or the current model.
+func NewEstimateCostCommand() cmd.Command {
+	return modelcmd.Wrap(&estimateCostCommand{})
+}
+
+// estimateCostCommand estimates the cost of a bundle or the current model.
+type estimateCostCommand struct {
+	modelcmd.ModelCommandBase
+	out        cmd.Output
+	api        EstimateCostAPI
+	bundlePath string
+}
+
+// Info implements Command.Info.
+func (c *estimateCostCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "estimate-cost",
+		Args:    "[<bundle>]",
+		Purpose: "Estimates the cost of the machines of a bundle or model.",
+		Doc:     estimateCostCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *estimateCostCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", output.AddReportFormatters(f, map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatCostEstimateTabular,
+	}))
+}
+
+// Init implements Command.Init.
+func (c *estimateCostCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.bundlePath = args[0]
+		args = args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// estimateCostAPIAdapter combines the client and machine manager
+// facades used by the command, which share an API connection.
+type estimateCostAPIAdapter struct {
+	*api.Client
+	machineManager *machinemanager.Client
+}
+
+// InstanceTypes is part of the EstimateCostAPI interface.
+func (a estimateCostAPIAdapter) InstanceTypes(cons []constraints.Value) ([]params.InstanceTypesResult, error) {
+	return a.machineManager.InstanceTypes(cons)
+}
+
+func (c *estimateCostCommand) getAPI() (EstimateCostAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return estimateCostAPIAdapter{
+		Client:         root.Client(),
+		machineManager: machinemanager.NewClient(root),
+	}, nil
+}
+
+// Run implements Command.Run.
+func (c *estimateCostCommand) Run(ctx *cmd.Context) error {
+	var bundleData *charm.BundleData
+	if c.bundlePath != "" {
+		var err error
+		if bundleData, err = readBundle(ctx.AbsPath(c.bundlePath)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	var machines map[string]*plannedMachine
+	if bundleData != nil {
+		modelCons, err := client.GetModelConstraints()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if machines, err = bundleMachines(bundleData, modelCons); err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		status, err := client.Status(nil)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if machines, err = modelMachines(status); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	estimate, err := estimateCost(client, machines)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, id := range sortedMachineIds(estimate.Machines) {
+		if msg := estimate.Machines[id].Error; msg != "" {
+			fmt.Fprintf(ctx.Stderr, "no cost for machine %s: %s\n", id, msg)
+		}
+	}
+	return c.out.Write(ctx, estimate)
+}
+
+// readBundle reads the bundle at the given path, which may be a bundle
+// file, a bundle directory or a bundle archive.
+func readBundle(path string) (*charm.BundleData, error) {
+	data, err := charmrepo.ReadBundleFile(path)
+	if err == nil {
+		return data, nil
+	}
+	bundle, _, pathErr := charmrepo.NewBundleAtPath(path)
+	if pathErr != nil {
+		return nil, errors.Annotatef(pathErr, "cannot read bundle %q", path)
+	}
+	return bundle.Data(), nil
+}
+
+// plannedMachine is a machine to be costed.
+type plannedMachine struct {
+	constraints  constraints.Value
+	applications []string
+}
+
+func (m *plannedMachine) addApplication(name string) {
+	for _, existing := range m.applications {
+		if existing == name {
+			return
+		}
+	}
+	m.applications = append(m.applications, name)
+}
+
+// modelMachines returns the top level machines of the model described by
+// the given status, with the applications that have units on each one.
+func modelMachines(status *params.FullStatus) (map[string]*plannedMachine, error) {
+	machines := make(map[string]*plannedMachine)
+	for id, m := range status.Machines {
+		cons, err := constraints.Parse(m.Constraints)
+		if err != nil {
+			return nil, errors.Annotatef(err, "machine %s", id)
+		}
+		machines[id] = &plannedMachine{constraints: cons}
+	}
+	for appName, app := range status.Applications {
+		for _, unit := range app.Units {
+			if unit.Machine == "" {
+				continue
+			}
+			hostId := strings.SplitN(unit.Machine, "/", 2)[0]
+			if m, ok := machines[hostId]; ok {
+				m.addApplication(appName)
+			}
+		}
+	}
+	for _, m := range machines {
+		sort.Strings(m.applications)
+	}
+	return machines, nil
+}
+
+// bundleMachines returns the machines that deploying the given bundle
+// would add, with the applications that would have units on each one.
+// Machines that are not in the bundle are named new-0, new-1 and so on.
+func bundleMachines(data *charm.BundleData, modelCons constraints.Value) (map[string]*plannedMachine, error) {
+	merger := constraints.NewValidator()
+	withModelCons := func(s string) (constraints.Value, error) {
+		cons, err := constraints.Parse(s)
+		if err != nil {
+			return constraints.Value{}, errors.Trace(err)
+		}
+		return merger.Merge(modelCons, cons)
+	}
+
+	machines := make(map[string]*plannedMachine)
+	for id, spec := range data.Machines {
+		var consStr string
+		if spec != nil {
+			consStr = spec.Constraints
+		}
+		cons, err := withModelCons(consStr)
+		if err != nil {
+			return nil, errors.Annotatef(err, "machine %s", id)
+		}
+		machines[id] = &plannedMachine{constraints: cons}
+	}
+
+	appNames := make([]string, 0, len(data.Applications))
+	for name := range data.Applications {
+		appNames = append(appNames, name)
+	}
+	sort.Strings(appNames)
+
+	var newMachines int
+	for _, appName := range appNames {
+		app := data.Applications[appName]
+		appCons, err := withModelCons(app.Constraints)
+		if err != nil {
+			return nil, errors.Annotatef(err, "application %s", appName)
+		}
+		for i := 0; i < app.NumUnits; i++ {
+			var target string
+			if i < len(app.To) {
+				target = app.To[i]
+			}
+			// Drop any container type; containers run on their host.
+			if colon := strings.LastIndex(target, ":"); colon >= 0 {
+				target = target[colon+1:]
+			}
+			switch {
+			case target == "" || target == "new":
+				machines[fmt.Sprintf("new-%d", newMachines)] = &plannedMachine{
+					constraints:  appCons,
+					applications: []string{appName},
+				}
+				newMachines++
+			case names.IsValidMachine(target):
+				m, ok := machines[target]
+				if !ok {
+					return nil, errors.NotFoundf("machine %s for application %s", target, appName)
+				}
+				m.addApplication(appName)
+			default:
+				// The unit is placed alongside a unit of another
+				// application, so it needs no machine of its own.
+			}
+		}
+	}
+	return machines, nil
+}
+
+// costEstimate holds the estimated cost of a set of machines.
+type costEstimate struct {
+	Currency     string                     `yaml:"currency,omitempty" json:"currency,omitempty"`
+	Machines     map[string]machineCost     `yaml:"machines" json:"machines"`
+	Applications map[string]applicationCost `yaml:"applications" json:"applications"`
+	Total        totalCost                  `yaml:"total" json:"total"`
+}
+
+// machineCost holds the estimated cost of a machine.
+type machineCost struct {
+	Constraints  string   `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+	InstanceType string   `yaml:"instance-type,omitempty" json:"instance-type,omitempty"`
+	Applications []string `yaml:"applications,omitempty" json:"applications,omitempty"`
+	Hourly       float64  `yaml:"hourly" json:"hourly"`
+	Monthly      float64  `yaml:"monthly" json:"monthly"`
+	Error        string   `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// applicationCost holds an application's share of the estimated cost
+// of the machines its units run on.
+type applicationCost struct {
+	Machines int     `yaml:"machines" json:"machines"`
+	Hourly   float64 `yaml:"hourly" json:"hourly"`
+	Monthly  float64 `yaml:"monthly" json:"monthly"`
+}
+
+// totalCost holds the total estimated cost of the machines with a
+// known cost.
+type totalCost struct {
+	Machines int     `yaml:"machines" json:"machines"`
+	Hourly   float64 `yaml:"hourly" json:"hourly"`
+	Monthly  float64 `yaml:"monthly" json:"monthly"`
+}
+
+// estimateCost resolves the constraints of each machine to an instance
+// type and returns the resulting costs.
+func estimateCost(client EstimateCostAPI, machines map[string]*plannedMachine) (*costEstimate, error) {
+	// Ask about each distinct set of constraints once.
+	var cons []constraints.Value
+	consIndex := make(map[string]int)
+	ids := make([]string, 0, len(machines))
+	for id, m := range machines {
+		ids = append(ids, id)
+		key := m.constraints.String()
+		if _, ok := consIndex[key]; !ok {
+			consIndex[key] = len(cons)
+			cons = append(cons, m.constraints)
+		}
+	}
+	sort.Strings(ids)
+
+	var results []params.InstanceTypesResult
+	if len(cons) > 0 {
+		var err error
+		if results, err = client.InstanceTypes(cons); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	estimate := &costEstimate{
+		Machines:     make(map[string]machineCost),
+		Applications: make(map[string]applicationCost),
+	}
+	for _, id := range ids {
+		m := machines[id]
+		result := results[consIndex[m.constraints.String()]]
+		mc := machineCost{
+			Constraints:  m.constraints.String(),
+			Applications: m.applications,
+		}
+		switch {
+		case result.Error != nil:
+			mc.Error = result.Error.Error()
+		case len(result.InstanceTypes) == 0:
+			mc.Error = "no matching instance types"
+		case result.InstanceTypes[0].Cost == 0:
+			mc.InstanceType = result.InstanceTypes[0].Name
+			mc.Error = "cloud provides no cost for instance type"
+		default:
+			// Instance types are sorted by cost, so the first is
+			// the one the provisioner would pick.
+			instanceType := result.InstanceTypes[0]
+			divisor := result.CostDivisor
+			if divisor == 0 {
+				divisor = 1
+			}
+			mc.InstanceType = instanceType.Name
+			mc.Hourly = float64(instanceType.Cost) / float64(divisor)
+			mc.Monthly = mc.Hourly * hoursPerMonth
+			if estimate.Currency == "" {
+				estimate.Currency = result.CostCurrency
+			}
+		}
+		estimate.Machines[id] = mc
+		if mc.Error != "" {
+			continue
+		}
+
+		estimate.Total.Machines++
+		estimate.Total.Hourly += mc.Hourly
+		estimate.Total.Monthly += mc.Monthly
+		for _, appName := range m.applications {
+			share := float64(len(m.applications))
+			ac := estimate.Applications[appName]
+			ac.Machines++
+			ac.Hourly += mc.Hourly / share
+			ac.Monthly += mc.Monthly / share
+			estimate.Applications[appName] = ac
+		}
+	}
+	return estimate, nil
+}
+
+func sortedMachineIds(machines map[string]machineCost) []string {
+	ids := make([]string, 0, len(machines))
+	for id := range machines {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// formatCostEstimateTabular writes a tabular summary of a cost estimate.
+func formatCostEstimateTabular(writer io.Writer, value interface{}) error {
+	estimate, ok := value.(*costEstimate)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", estimate, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+
+	w.Println("Machine", "Instance type", "Applications", "Hourly", "Monthly")
+	for _, id := range sortedMachineIds(estimate.Machines) {
+		mc := estimate.Machines[id]
+		hourly, monthly := formatCost(mc.Hourly, 3), formatCost(mc.Monthly, 2)
+		if mc.Error != "" {
+			hourly, monthly = "unknown", "unknown"
+		}
+		w.Println(id, mc.InstanceType, strings.Join(mc.Applications, ","), hourly, monthly)
+	}
+	w.Println()
+
+	appNames := make([]string, 0, len(estimate.Applications))
+	for name := range estimate.Applications {
+		appNames = append(appNames, name)
+	}
+	sort.Strings(appNames)
+	w.Println("Application", "Machines", "Hourly", "Monthly")
+	for _, name := range appNames {
+		ac := estimate.Applications[name]
+		w.Println(name, ac.Machines, formatCost(ac.Hourly, 3), formatCost(ac.Monthly, 2))
+	}
+	w.Println("Total", estimate.Total.Machines, formatCost(estimate.Total.Hourly, 3), formatCost(estimate.Total.Monthly, 2))
+	tw.Flush()
+
+	if estimate.Currency != "" {
+		fmt.Fprintf(writer, "\nCosts are in %s.\n", estimate.Currency)
+	}
+	return nil
+}
+
+func formatCost(cost float64, precision int) string {
+	return strconv.FormatFloat(cost, 'f', precision, 64)
+}
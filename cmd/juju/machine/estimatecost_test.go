@@ -0,0 +1,243 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/testing"
+)
+
+type EstimateCostSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api *fakeEstimateCostAPI
+}
+
+var _ = gc.Suite(&EstimateCostSuite{})
+
+func (s *EstimateCostSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeEstimateCostAPI{
+		status: &params.FullStatus{
+			Machines: map[string]params.MachineStatus{
+				"0": {Id: "0", Constraints: "mem=4G"},
+				"1": {Id: "1", Constraints: "mem=16G"},
+				"2": {Id: "2"},
+			},
+			Applications: map[string]params.ApplicationStatus{
+				"mysql": {Units: map[string]params.UnitStatus{
+					"mysql/0": {Machine: "0"},
+				}},
+				"wordpress": {Units: map[string]params.UnitStatus{
+					"wordpress/0": {Machine: "0"},
+					"wordpress/1": {Machine: "1/lxd/0"},
+				}},
+			},
+		},
+		modelCons: constraints.MustParse("mem=4G"),
+		instanceTypes: map[string]params.InstanceTypesResult{
+			"mem=4096M": {
+				InstanceTypes: []params.InstanceType{{Name: "medium", Cost: 80}, {Name: "large", Cost: 320}},
+				CostUnit:      "$USD/hour",
+				CostCurrency:  "USD",
+				CostDivisor:   1000,
+			},
+			"mem=16384M": {
+				InstanceTypes: []params.InstanceType{{Name: "large", Cost: 320}},
+				CostUnit:      "$USD/hour",
+				CostCurrency:  "USD",
+				CostDivisor:   1000,
+			},
+			"cores=8 mem=4096M": {
+				InstanceTypes: []params.InstanceType{{Name: "free"}},
+			},
+		},
+	}
+}
+
+func (s *EstimateCostSuite) TestInitTooManyArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, machine.NewEstimateCostCommandForTest(s.api), "a", "b")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+}
+
+func (s *EstimateCostSuite) TestModelTabular(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, machine.NewEstimateCostCommandForTest(s.api))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Machine  Instance type  Applications     Hourly   Monthly\n"+
+		"0        medium         mysql,wordpress  0.080    58.40\n"+
+		"1        large          wordpress        0.320    233.60\n"+
+		"2                                        unknown  unknown\n"+
+		"\n"+
+		"Application  Machines  Hourly  Monthly\n"+
+		"mysql        1         0.040   29.20\n"+
+		"wordpress    2         0.360   262.80\n"+
+		"Total        2         0.400   292.00\n"+
+		"\n"+
+		"Costs are in USD.\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "no cost for machine 2: instance types not found\n")
+	s.api.CheckCallNames(c, "Status", "InstanceTypes", "Close")
+}
+
+func (s *EstimateCostSuite) TestModelJSON(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, machine.NewEstimateCostCommandForTest(s.api), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+
+	var out struct {
+		Currency string `json:"currency"`
+		Machines map[string]struct {
+			InstanceType string   `json:"instance-type"`
+			Applications []string `json:"applications"`
+			Error        string   `json:"error"`
+		} `json:"machines"`
+		Applications map[string]struct {
+			Machines int     `json:"machines"`
+			Hourly   float64 `json:"hourly"`
+		} `json:"applications"`
+		Total struct {
+			Machines int     `json:"machines"`
+			Hourly   float64 `json:"hourly"`
+			Monthly  float64 `json:"monthly"`
+		} `json:"total"`
+	}
+	err = json.Unmarshal([]byte(cmdtesting.Stdout(ctx)), &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Currency, gc.Equals, "USD")
+	c.Assert(out.Machines["0"].InstanceType, gc.Equals, "medium")
+	c.Assert(out.Machines["0"].Applications, jc.DeepEquals, []string{"mysql", "wordpress"})
+	c.Assert(out.Machines["2"].Error, gc.Equals, "instance types not found")
+	c.Assert(out.Applications["wordpress"].Machines, gc.Equals, 2)
+	c.Assert(out.Applications["mysql"].Hourly, jc.Almost, 0.04)
+	c.Assert(out.Total.Machines, gc.Equals, 2)
+	c.Assert(out.Total.Hourly, jc.Almost, 0.4)
+	c.Assert(out.Total.Monthly, jc.Almost, 292.0)
+}
+
+const costBundle = `
+applications:
+  mysql:
+    charm: cs:mysql
+    num_units: 1
+    to: ["0"]
+  wordpress:
+    charm: cs:wordpress
+    num_units: 2
+    constraints: cores=8
+    to: ["lxd:0"]
+  varnish:
+    charm: cs:varnish
+    num_units: 1
+    to: ["wordpress/0"]
+machines:
+  "0":
+    constraints: mem=16G
+`
+
+func (s *EstimateCostSuite) TestBundle(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(costBundle), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := cmdtesting.RunCommand(c, machine.NewEstimateCostCommandForTest(s.api), path, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"currency: USD\n"+
+		"machines:\n"+
+		"  \"0\":\n"+
+		"    constraints: mem=16384M\n"+
+		"    instance-type: large\n"+
+		"    applications:\n"+
+		"    - mysql\n"+
+		"    - wordpress\n"+
+		"    hourly: 0.32\n"+
+		"    monthly: 233.6\n"+
+		"  new-0:\n"+
+		"    constraints: cores=8 mem=4096M\n"+
+		"    instance-type: free\n"+
+		"    applications:\n"+
+		"    - wordpress\n"+
+		"    hourly: 0\n"+
+		"    monthly: 0\n"+
+		"    error: cloud provides no cost for instance type\n"+
+		"applications:\n"+
+		"  mysql:\n"+
+		"    machines: 1\n"+
+		"    hourly: 0.16\n"+
+		"    monthly: 116.8\n"+
+		"  wordpress:\n"+
+		"    machines: 1\n"+
+		"    hourly: 0.16\n"+
+		"    monthly: 116.8\n"+
+		"total:\n"+
+		"  machines: 1\n"+
+		"  hourly: 0.32\n"+
+		"  monthly: 233.6\n")
+	s.api.CheckCallNames(c, "GetModelConstraints", "InstanceTypes", "Close")
+}
+
+func (s *EstimateCostSuite) TestBundleUnknownMachine(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(`
+applications:
+  mysql:
+    charm: cs:mysql
+    num_units: 1
+    to: ["0"]
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = cmdtesting.RunCommand(c, machine.NewEstimateCostCommandForTest(s.api), path)
+	c.Assert(err, gc.ErrorMatches, `machine 0 for application mysql not found`)
+}
+
+func (s *EstimateCostSuite) TestBundleNotFound(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, machine.NewEstimateCostCommandForTest(s.api), "/no/such/bundle")
+	c.Assert(err, gc.ErrorMatches, `cannot read bundle "/no/such/bundle": .*`)
+	s.api.CheckNoCalls(c)
+}
+
+type fakeEstimateCostAPI struct {
+	jujutesting.Stub
+	status        *params.FullStatus
+	modelCons     constraints.Value
+	instanceTypes map[string]params.InstanceTypesResult
+}
+
+func (f *fakeEstimateCostAPI) Status(pattern []string) (*params.FullStatus, error) {
+	f.MethodCall(f, "Status", pattern)
+	return f.status, f.NextErr()
+}
+
+func (f *fakeEstimateCostAPI) GetModelConstraints() (constraints.Value, error) {
+	f.MethodCall(f, "GetModelConstraints")
+	return f.modelCons, f.NextErr()
+}
+
+func (f *fakeEstimateCostAPI) InstanceTypes(cons []constraints.Value) ([]params.InstanceTypesResult, error) {
+	f.MethodCall(f, "InstanceTypes", cons)
+	results := make([]params.InstanceTypesResult, len(cons))
+	for i, value := range cons {
+		result, ok := f.instanceTypes[value.String()]
+		if !ok {
+			result.Error = &params.Error{Message: "instance types not found", Code: params.CodeNotFound}
+		}
+		results[i] = result
+	}
+	return results, f.NextErr()
+}
+
+func (f *fakeEstimateCostAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
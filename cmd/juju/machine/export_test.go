@@ -12,7 +12,8 @@ import (
 )
 
 var (
-	SSHProvisioner = &sshProvisioner
+	SSHProvisioner   = &sshProvisioner
+	UninstallMachine = &uninstallMachine
 )
 
 type AddCommand struct {
@@ -54,6 +55,27 @@ func NewRemoveCommandForTest(apiRoot api.Connection, machineAPI RemoveMachineAPI
 	return modelcmd.Wrap(cmd), &RemoveCommand{cmd}
 }
 
+// NewRemoveCommandWithStatusForTest returns a RemoveCommand with the apis
+// provided as specified.
+func NewRemoveCommandWithStatusForTest(apiRoot api.Connection, machineAPI RemoveMachineAPI, statusAPI statusAPI) cmd.Command {
+	return modelcmd.Wrap(&removeCommand{
+		apiRoot:    apiRoot,
+		machineAPI: machineAPI,
+		statusAPI:  statusAPI,
+	})
+}
+
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
+
+// NewEstimateCostCommandForTest returns an estimateCostCommand with the
+// specified api.
+func NewEstimateCostCommandForTest(api EstimateCostAPI) cmd.Command {
+	return modelcmd.Wrap(&estimateCostCommand{api: api})
+}
+
+// NewMoveCommandForTest returns a moveCommand with the specified api.
+func NewMoveCommandForTest(api MoveMachineAPI) cmd.Command {
+	return modelcmd.Wrap(&moveCommand{api: api})
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/instance"
+)
+
+const moveMachineCommandDoc = `
+Moves an LXD container to another machine of the model, keeping its
+machine number, units and data.
+
+Unless --live is given, the container is stopped while it is copied,
+and started again on the new host. Live moves copy the running
+container, and require CRIU on both hosts.
+
+The LXD daemons of both hosts must be able to reach each other over
+the network; the current host's LXD must be listening on an address
+set with core.https_address. The target machine must support LXD
+containers, and have the network bridges used by the container.
+
+The move happens in the background. Use 'juju status' or
+'juju show-machine' to follow its progress. If the move fails, the
+container is restarted on its current host.
+
+Examples:
+    juju move-machine 0/lxd/1 --to 2
+    juju move-machine 0/lxd/1 --to 2 --live
+
+See also:
+    add-machine
+    remove-machine
+`
+
+// MoveMachineAPI defines the API methods used by the move-machine
+// command.
+type MoveMachineAPI interface {
+	MoveMachine(machineId, hostId string, live bool) error
+	Close() error
+}
+
+// NewMoveCommand returns a command that moves a container to another
+// machine.
+func NewMoveCommand() cmd.Command {
+	return modelcmd.Wrap(&moveCommand{})
+}
+
+// moveCommand moves a container to another machine.
+type moveCommand struct {
+	modelcmd.ModelCommandBase
+	api       MoveMachineAPI
+	machineId string
+	hostId    string
+	live      bool
+}
+
+// Info implements Command.Info.
+func (c *moveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "move-machine",
+		Args:    "<container> --to <machine>",
+		Purpose: "Moves an LXD container to another machine.",
+		Doc:     moveMachineCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *moveCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.hostId, "to", "", "The machine to move the container to")
+	f.BoolVar(&c.live, "live", false, "Move the container without stopping it")
+}
+
+// Init implements Command.Init.
+func (c *moveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no container specified")
+	}
+	c.machineId, args = args[0], args[1:]
+	if !names.IsValidMachine(c.machineId) {
+		return errors.Errorf("invalid machine id %q", c.machineId)
+	}
+	if !names.IsContainerMachine(c.machineId) {
+		return errors.Errorf("machine %s is not a container", c.machineId)
+	}
+	parts := strings.Split(c.machineId, "/")
+	if parts[len(parts)-2] != string(instance.LXD) {
+		return errors.Errorf("only LXD containers can be moved")
+	}
+	if c.hostId == "" {
+		return errors.New("no target machine specified, use --to")
+	}
+	if !names.IsValidMachine(c.hostId) {
+		return errors.Errorf("invalid machine id %q", c.hostId)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *moveCommand) getAPI() (MoveMachineAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *moveCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	err = client.MoveMachine(c.machineId, c.hostId, c.live)
+	if errors.IsNotSupported(err) {
+		return errors.New("this version of Juju doesn't support moving machines")
+	}
+	if err := block.ProcessBlockedError(err, block.BlockChange); err != nil {
+		return err
+	}
+	ctx.Infof("moving machine %s to machine %s", c.machineId, c.hostId)
+	return nil
+}
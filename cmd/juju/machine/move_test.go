@@ -0,0 +1,107 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type MoveMachineSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api *fakeMoveMachineAPI
+}
+
+var _ = gc.Suite(&MoveMachineSuite{})
+
+func (s *MoveMachineSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeMoveMachineAPI{}
+}
+
+func (s *MoveMachineSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		errorString string
+	}{{
+		errorString: "no container specified",
+	}, {
+		args:        []string{"0/lxd/1"},
+		errorString: "no target machine specified, use --to",
+	}, {
+		args:        []string{"lxd", "--to", "1"},
+		errorString: `invalid machine id "lxd"`,
+	}, {
+		args:        []string{"0", "--to", "1"},
+		errorString: "machine 0 is not a container",
+	}, {
+		args:        []string{"0/kvm/1", "--to", "1"},
+		errorString: "only LXD containers can be moved",
+	}, {
+		args:        []string{"0/lxd/1", "--to", "lxd"},
+		errorString: `invalid machine id "lxd"`,
+	}, {
+		args:        []string{"0/lxd/1", "--to", "1", "extra"},
+		errorString: `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"0/lxd/1", "--to", "1/lxd/0", "--live"},
+	}} {
+		c.Logf("test %d", i)
+		err := cmdtesting.InitCommand(machine.NewMoveCommandForTest(s.api), test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *MoveMachineSuite) TestMove(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, machine.NewMoveCommandForTest(s.api), "0/lxd/1", "--to", "2", "--live")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"MoveMachine", []interface{}{"0/lxd/1", "2", true}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "moving machine 0/lxd/1 to machine 2\n")
+}
+
+func (s *MoveMachineSuite) TestMoveError(c *gc.C) {
+	s.api.SetErrors(errors.New("cannot move machine 0/lxd/1 to machine 2: machine 2 does not support lxd containers"))
+	_, err := cmdtesting.RunCommand(c, machine.NewMoveCommandForTest(s.api), "0/lxd/1", "--to", "2")
+	c.Assert(err, gc.ErrorMatches, "cannot move machine 0/lxd/1 to machine 2: machine 2 does not support lxd containers")
+}
+
+func (s *MoveMachineSuite) TestMoveNotSupported(c *gc.C) {
+	s.api.SetErrors(errors.NotSupportedf("moving machines"))
+	_, err := cmdtesting.RunCommand(c, machine.NewMoveCommandForTest(s.api), "0/lxd/1", "--to", "2")
+	c.Assert(err, gc.ErrorMatches, "this version of Juju doesn't support moving machines")
+}
+
+func (s *MoveMachineSuite) TestBlockedError(c *gc.C) {
+	s.api.SetErrors(common.OperationBlockedError("TestBlockedError"))
+	_, err := cmdtesting.RunCommand(c, machine.NewMoveCommandForTest(s.api), "0/lxd/1", "--to", "2")
+	testing.AssertOperationWasBlocked(c, err, ".*TestBlockedError.*")
+}
+
+type fakeMoveMachineAPI struct {
+	jujutesting.Stub
+}
+
+func (f *fakeMoveMachineAPI) MoveMachine(machineId, hostId string, live bool) error {
+	f.MethodCall(f, "MoveMachine", machineId, hostId, live)
+	return f.NextErr()
+}
+
+func (f *fakeMoveMachineAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
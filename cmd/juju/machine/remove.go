@@ -4,6 +4,8 @@
 package machine
 
 import (
+	"strings"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
@@ -14,6 +16,8 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/environs/manual/sshprovisioner"
 )
 
 // NewRemoveCommand returns a command used to remove a specified machine.
@@ -26,9 +30,11 @@ type removeCommand struct {
 	modelcmd.ModelCommandBase
 	apiRoot      api.Connection
 	machineAPI   RemoveMachineAPI
+	statusAPI    statusAPI
 	MachineIds   []string
 	Force        bool
 	KeepInstance bool
+	Uninstall    bool
 }
 
 const destroyMachineDoc = `
@@ -54,6 +60,17 @@ the corresponding cloud instance:
 
     juju remove-machine 7 --keep-instance
 
+Remove manually provisioned machine 8, and uninstall its agent over SSH
+so the machine can be provisioned again. The agent is given time to stop
+cleanly before anything it leaves behind is removed:
+
+    juju remove-machine 8 --uninstall
+
+Remove manually provisioned machine 9, along with any running units or
+containers, and uninstall its agent:
+
+    juju remove-machine 9 --uninstall --force
+
 See also:
     add-machine
 `
@@ -73,6 +90,7 @@ func (c *removeCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.Force, "force", false, "Completely remove a machine and all its dependencies")
 	f.BoolVar(&c.KeepInstance, "keep-instance", false, "Do not stop the running cloud instance")
+	f.BoolVar(&c.Uninstall, "uninstall", false, "Uninstall the agents from manually provisioned machines over SSH")
 }
 
 func (c *removeCommand) Init(args []string) error {
@@ -84,6 +102,9 @@ func (c *removeCommand) Init(args []string) error {
 			return errors.Errorf("invalid machine id %q", id)
 		}
 	}
+	if c.Uninstall && c.KeepInstance {
+		return errors.New("--uninstall and --keep-instance cannot be used together")
+	}
 	c.MachineIds = args
 	return nil
 }
@@ -148,8 +169,59 @@ func (c *removeCommand) getRemoveMachineAPI() (RemoveMachineAPI, error) {
 	return removeMachineAdapter{root.Client()}, nil
 }
 
+func (c *removeCommand) getStatusAPI() (statusAPI, error) {
+	if c.statusAPI != nil {
+		return c.statusAPI, nil
+	}
+	root, err := c.getAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return root.Client(), nil
+}
+
+var uninstallMachine = sshprovisioner.UninstallMachine
+
+// manualHosts returns the hosts of the machines being removed, which
+// must all have been manually provisioned.
+func (c *removeCommand) manualHosts() (map[string]string, error) {
+	client, err := c.getStatusAPI()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer client.Close()
+	status, err := client.Status(nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hosts := make(map[string]string)
+	for _, id := range c.MachineIds {
+		if names.IsContainerMachine(id) {
+			return nil, errors.Errorf("cannot uninstall container %s", id)
+		}
+		machine, ok := status.Machines[id]
+		if !ok {
+			return nil, errors.NotFoundf("machine %s", id)
+		}
+		instanceId := string(machine.InstanceId)
+		if !strings.HasPrefix(instanceId, manual.ManualInstancePrefix) {
+			return nil, errors.Errorf("cannot uninstall machine %s: not manually provisioned", id)
+		}
+		hosts[id] = strings.TrimPrefix(instanceId, manual.ManualInstancePrefix)
+	}
+	return hosts, nil
+}
+
 // Run implements Command.Run.
 func (c *removeCommand) Run(ctx *cmd.Context) error {
+	var hosts map[string]string
+	if c.Uninstall {
+		var err error
+		if hosts, err = c.manualHosts(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	client, err := c.getRemoveMachineAPI()
 	if err != nil {
 		return err
@@ -207,6 +279,13 @@ func (c *removeCommand) Run(ctx *cmd.Context) error {
 			}
 			ctx.Infof("- will detach %s", names.ReadableString(storageTag))
 		}
+		if host, ok := hosts[id]; ok {
+			ctx.Infof("uninstalling agent from %s", host)
+			if err := uninstallMachine(host); err != nil {
+				anyFailed = true
+				ctx.Infof("uninstalling machine %s failed: %s", id, err)
+			}
+		}
 	}
 
 	if anyFailed {
@@ -6,6 +6,7 @@ package machine_test
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -164,6 +165,103 @@ func (s *RemoveMachineSuite) TestOldFacadeRemoveKeep(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "this version of Juju doesn't support --keep-instance")
 }
 
+func (s *RemoveMachineSuite) TestInitUninstall(c *gc.C) {
+	wrappedCommand, removeCmd := machine.NewRemoveCommandForTest(s.apiConnection, s.fake)
+	err := cmdtesting.InitCommand(wrappedCommand, []string{"--uninstall", "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removeCmd.Uninstall, jc.IsTrue)
+
+	wrappedCommand, _ = machine.NewRemoveCommandForTest(s.apiConnection, s.fake)
+	err = cmdtesting.InitCommand(wrappedCommand, []string{"--uninstall", "--keep-instance", "1"})
+	c.Assert(err, gc.ErrorMatches, "--uninstall and --keep-instance cannot be used together")
+}
+
+func (s *RemoveMachineSuite) runUninstall(c *gc.C, args ...string) (*cmd.Context, error) {
+	status := &fakeRemoveStatusAPI{
+		result: &params.FullStatus{
+			Machines: map[string]params.MachineStatus{
+				"1": {InstanceId: "manual:10.0.0.1"},
+				"2": {InstanceId: "manual:10.0.0.2"},
+				"3": {InstanceId: "i-cloudy"},
+			},
+		},
+	}
+	remove := machine.NewRemoveCommandWithStatusForTest(s.apiConnection, s.fake, status)
+	return cmdtesting.RunCommand(c, remove, append([]string{"--uninstall"}, args...)...)
+}
+
+func (s *RemoveMachineSuite) TestRemoveUninstall(c *gc.C) {
+	var uninstalled []string
+	s.PatchValue(machine.UninstallMachine, func(host string) error {
+		uninstalled = append(uninstalled, host)
+		if host == "10.0.0.2" {
+			return errors.New("jujud did not stop")
+		}
+		return nil
+	})
+	ctx, err := s.runUninstall(c, "1", "2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(s.fake.forced, jc.IsFalse)
+	c.Assert(s.fake.machines, jc.DeepEquals, []string{"1", "2"})
+	c.Assert(uninstalled, jc.DeepEquals, []string{"10.0.0.1", "10.0.0.2"})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+removing machine 1
+uninstalling agent from 10.0.0.1
+removing machine 2
+uninstalling agent from 10.0.0.2
+uninstalling machine 2 failed: jujud did not stop
+`[1:])
+}
+
+func (s *RemoveMachineSuite) TestRemoveUninstallForce(c *gc.C) {
+	var uninstalled []string
+	s.PatchValue(machine.UninstallMachine, func(host string) error {
+		uninstalled = append(uninstalled, host)
+		return nil
+	})
+	_, err := s.runUninstall(c, "--force", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.forced, jc.IsTrue)
+	c.Assert(s.fake.machines, jc.DeepEquals, []string{"1"})
+	c.Assert(uninstalled, jc.DeepEquals, []string{"10.0.0.1"})
+}
+
+func (s *RemoveMachineSuite) TestRemoveUninstallNotManual(c *gc.C) {
+	s.PatchValue(machine.UninstallMachine, func(host string) error {
+		c.Fatalf("unexpected uninstall of %s", host)
+		return nil
+	})
+	for _, test := range []struct {
+		id          string
+		errorString string
+	}{{
+		id:          "3",
+		errorString: "cannot uninstall machine 3: not manually provisioned",
+	}, {
+		id:          "4",
+		errorString: "machine 4 not found",
+	}, {
+		id:          "1/lxd/0",
+		errorString: "cannot uninstall container 1/lxd/0",
+	}} {
+		_, err := s.runUninstall(c, test.id)
+		c.Check(err, gc.ErrorMatches, test.errorString)
+	}
+	c.Assert(s.fake.machines, gc.IsNil)
+}
+
+type fakeRemoveStatusAPI struct {
+	result *params.FullStatus
+}
+
+func (f *fakeRemoveStatusAPI) Status(pattern []string) (*params.FullStatus, error) {
+	return f.result, nil
+}
+
+func (f *fakeRemoveStatusAPI) Close() error {
+	return nil
+}
+
 type fakeRemoveMachineAPI struct {
 	forced      bool
 	keep        bool
@@ -0,0 +1,198 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/secrets"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/juju/osenv"
+)
+
+var listSecretsHelpSummary = `
+Lists the secrets created by charms in a model.`[1:]
+
+var listSecretsHelpDetails = `
+Lists the secrets created by the charms in a model with secret-add, along
+with the relations over which each secret has been granted to other
+applications. Secret values are never shown.
+
+Examples:
+    juju list-secrets
+    juju secrets --format yaml
+
+See also:
+    status`
+
+// NewListSecretsCommand returns a command to list the secrets in a model.
+func NewListSecretsCommand() cmd.Command {
+	cmd := &listSecretsCommand{}
+	cmd.newAPIFunc = func() (ListSecretsAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return secrets.NewClient(root), nil
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+type listSecretsCommand struct {
+	modelcmd.ModelCommandBase
+	out     cmd.Output
+	isoTime bool
+
+	newAPIFunc func() (ListSecretsAPI, error)
+}
+
+// ListSecretsAPI defines the API methods that the list secrets command uses.
+type ListSecretsAPI interface {
+	Close() error
+	ListSecrets() ([]params.SecretMetadata, error)
+}
+
+// Info implements cmd.Command.
+func (c *listSecretsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-secrets",
+		Purpose: listSecretsHelpSummary,
+		Doc:     listSecretsHelpDetails,
+		Aliases: []string{"secrets"},
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *listSecretsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": c.formatTabular,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *listSecretsCommand) Init(args []string) error {
+	if !c.isoTime {
+		// If use of ISO time not specified on command line,
+		// check env var.
+		if value := os.Getenv(osenv.JujuStatusIsoTimeEnvKey); value != "" {
+			var err error
+			if c.isoTime, err = strconv.ParseBool(value); err != nil {
+				return errors.Annotatef(err, "invalid %s env var, expected true|false", osenv.JujuStatusIsoTimeEnvKey)
+			}
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements cmd.Command.
+func (c *listSecretsCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	results, err := client.ListSecrets()
+	if err != nil {
+		return err
+	}
+
+	secrets := make([]secretInfo, len(results))
+	for i, r := range results {
+		secrets[i] = secretInfo{
+			Id:         r.Id,
+			Owner:      r.Owner,
+			Label:      r.Label,
+			Revision:   r.Revision,
+			CreateTime: r.CreateTime,
+			UpdateTime: r.UpdateTime,
+		}
+		for _, grant := range r.Grants {
+			secrets[i].Grants = append(secrets[i].Grants, secretGrant{
+				Relation: grant.Relation,
+				Unit:     grant.Unit,
+			})
+		}
+	}
+	sort.Sort(secretInfos(secrets))
+	return c.out.Write(ctx, secrets)
+}
+
+type secretInfo struct {
+	Id         string        `yaml:"id" json:"id"`
+	Owner      string        `yaml:"owner" json:"owner"`
+	Label      string        `yaml:"label,omitempty" json:"label,omitempty"`
+	Revision   int           `yaml:"revision" json:"revision"`
+	CreateTime time.Time     `yaml:"created" json:"created"`
+	UpdateTime time.Time     `yaml:"updated" json:"updated"`
+	Grants     []secretGrant `yaml:"grants,omitempty" json:"grants,omitempty"`
+}
+
+type secretGrant struct {
+	Relation string `yaml:"relation" json:"relation"`
+	Unit     string `yaml:"unit,omitempty" json:"unit,omitempty"`
+}
+
+// String returns the grant as displayed in tabular output.
+func (g secretGrant) String() string {
+	if g.Unit != "" {
+		return g.Unit + " (" + g.Relation + ")"
+	}
+	return g.Relation
+}
+
+type secretInfos []secretInfo
+
+func (s secretInfos) Len() int      { return len(s) }
+func (s secretInfos) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s secretInfos) Less(i, j int) bool {
+	if s[i].Owner != s[j].Owner {
+		return s[i].Owner < s[j].Owner
+	}
+	return s[i].Id < s[j].Id
+}
+
+func (c *listSecretsCommand) formatTabular(writer io.Writer, value interface{}) error {
+	secrets, ok := value.([]secretInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", secrets, value)
+	}
+	if len(secrets) == 0 {
+		return nil
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("ID", "Owner", "Label", "Revision", "Updated", "Granted to")
+	for _, secret := range secrets {
+		grants := make([]string, len(secret.Grants))
+		for i, grant := range secret.Grants {
+			grants[i] = grant.String()
+		}
+		w.Println(
+			secret.Id,
+			secret.Owner,
+			secret.Label,
+			secret.Revision,
+			common.FormatTime(&secret.UpdateTime, c.isoTime),
+			strings.Join(grants, ", "),
+		)
+	}
+	tw.Flush()
+	return nil
+}
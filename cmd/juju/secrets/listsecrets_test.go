@@ -0,0 +1,121 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/secrets"
+	"github.com/juju/juju/testing"
+)
+
+type ListSecretsSuite struct {
+	testing.BaseSuite
+
+	mockAPI *mockListSecretsAPI
+}
+
+var _ = gc.Suite(&ListSecretsSuite{})
+
+func (s *ListSecretsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	created := time.Date(2017, 11, 1, 12, 0, 0, 0, time.UTC)
+	s.mockAPI = &mockListSecretsAPI{
+		secrets: []params.SecretMetadata{{
+			Id:         "secret-1",
+			Owner:      "wordpress",
+			Revision:   1,
+			CreateTime: created,
+			UpdateTime: created,
+		}, {
+			Id:         "secret-0",
+			Owner:      "mysql",
+			Label:      "admin",
+			Revision:   2,
+			CreateTime: created,
+			UpdateTime: created.Add(time.Hour),
+			Grants: []params.SecretGrant{
+				{Relation: "wordpress:db mysql:server"},
+				{Relation: "mediawiki:db mysql:server", Unit: "mediawiki/1"},
+			},
+		}},
+	}
+}
+
+func (s *ListSecretsSuite) runList(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, secrets.NewListSecretsCommandForTest(s.mockAPI), args...)
+}
+
+func (s *ListSecretsSuite) TestListTabular(c *gc.C) {
+	ctx, err := s.runList(c, "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+ID        Owner      Label  Revision  Updated               Granted to
+secret-0  mysql      admin  2         2017-11-01 13:00:00Z  wordpress:db mysql:server, mediawiki/1 (mediawiki:db mysql:server)
+secret-1  wordpress         1         2017-11-01 12:00:00Z  
+`[1:])
+}
+
+func (s *ListSecretsSuite) TestListYAML(c *gc.C) {
+	ctx, err := s.runList(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- id: secret-0
+  owner: mysql
+  label: admin
+  revision: 2
+  created: 2017-11-01T12:00:00Z
+  updated: 2017-11-01T13:00:00Z
+  grants:
+  - relation: wordpress:db mysql:server
+  - relation: mediawiki:db mysql:server
+    unit: mediawiki/1
+- id: secret-1
+  owner: wordpress
+  revision: 1
+  created: 2017-11-01T12:00:00Z
+  updated: 2017-11-01T12:00:00Z
+`[1:])
+}
+
+func (s *ListSecretsSuite) TestListEmpty(c *gc.C) {
+	s.mockAPI.secrets = nil
+	ctx, err := s.runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+}
+
+func (s *ListSecretsSuite) TestListError(c *gc.C) {
+	s.mockAPI.err = errors.New("fail")
+	_, err := s.runList(c)
+	c.Assert(err, gc.ErrorMatches, "fail")
+}
+
+func (s *ListSecretsSuite) TestListTooManyArgs(c *gc.C) {
+	_, err := s.runList(c, "foo")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+type mockListSecretsAPI struct {
+	secrets []params.SecretMetadata
+	err     error
+}
+
+func (s *mockListSecretsAPI) Close() error {
+	return nil
+}
+
+func (s *mockListSecretsAPI) ListSecrets() ([]params.SecretMetadata, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.secrets, nil
+}
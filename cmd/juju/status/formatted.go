@@ -41,6 +41,7 @@ type modelStatus struct {
 	Status           statusInfoContents `json:"model-status,omitempty" yaml:"model-status,omitempty"`
 	MeterStatus      *meterStatus       `json:"meter-status,omitempty" yaml:"meter-status,omitempty"`
 	SLA              string             `json:"sla,omitempty" yaml:"sla,omitempty"`
+	HAWarning        string             `json:"controller-ha-warning,omitempty" yaml:"controller-ha-warning,omitempty"`
 }
 
 type networkInterface struct {
@@ -66,6 +67,7 @@ type machineStatus struct {
 	Constraints       string                      `json:"constraints,omitempty" yaml:"constraints,omitempty"`
 	Hardware          string                      `json:"hardware,omitempty" yaml:"hardware,omitempty"`
 	HAStatus          string                      `json:"controller-member-status,omitempty" yaml:"controller-member-status,omitempty"`
+	LXDProfiles       []string                    `json:"lxd-profiles,omitempty" yaml:"lxd-profiles,omitempty"`
 }
 
 // A goyaml bug means we can't declare these types
@@ -184,9 +186,11 @@ type meterStatus struct {
 
 type unitStatus struct {
 	// New Juju Health Status fields.
-	WorkloadStatusInfo statusInfoContents `json:"workload-status,omitempty" yaml:"workload-status"`
-	JujuStatusInfo     statusInfoContents `json:"juju-status,omitempty" yaml:"juju-status"`
-	MeterStatus        *meterStatus       `json:"meter-status,omitempty" yaml:"meter-status,omitempty"`
+	WorkloadStatusInfo  statusInfoContents  `json:"workload-status,omitempty" yaml:"workload-status"`
+	JujuStatusInfo      statusInfoContents  `json:"juju-status,omitempty" yaml:"juju-status"`
+	HealthStatusInfo    *statusInfoContents `json:"health-status,omitempty" yaml:"health-status,omitempty"`
+	ContainerStatusInfo *statusInfoContents `json:"container-status,omitempty" yaml:"container-status,omitempty"`
+	MeterStatus         *meterStatus        `json:"meter-status,omitempty" yaml:"meter-status,omitempty"`
 
 	Leader        bool                  `json:"leader,omitempty" yaml:"leader,omitempty"`
 	Charm         string                `json:"upgrading-from,omitempty" yaml:"upgrading-from,omitempty"`
@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/status"
 )
@@ -77,6 +78,7 @@ func (sf *statusFormatter) format() (formattedStatus, error) {
 	for k, m := range sf.status.Machines {
 		out.Machines[k] = sf.formatMachine(m)
 	}
+	out.Model.HAWarning = controllerZoneWarning(sf.status.Machines)
 	for sn, s := range sf.status.Applications {
 		out.Applications[sn] = sf.formatApplication(sn, s)
 	}
@@ -132,6 +134,7 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 		Containers:        make(map[string]machineStatus),
 		Constraints:       machine.Constraints,
 		Hardware:          machine.Hardware,
+		LXDProfiles:       machine.LXDProfiles,
 	}
 
 	for k, d := range machine.NetworkInterfaces {
@@ -329,6 +332,16 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 		Leader:             info.unit.Leader,
 	}
 
+	if info.unit.HealthStatus.Status != "" {
+		health := sf.getStatusInfoContents(info.unit.HealthStatus)
+		out.HealthStatusInfo = &health
+	}
+
+	if info.unit.ContainerStatus.Status != "" {
+		container := sf.getStatusInfoContents(info.unit.ContainerStatus)
+		out.ContainerStatusInfo = &container
+	}
+
 	if ms, ok := info.meterStatuses[info.unitName]; ok {
 		out.MeterStatus = &meterStatus{
 			Color:   ms.Color,
@@ -417,6 +430,35 @@ func makeHAStatus(hasVote, wantsVote bool) string {
 	return s
 }
 
+// controllerZoneWarning returns a warning if the voting controller
+// machines in a single availability zone hold enough votes to form a
+// quorum, in which case the loss of that zone would leave the
+// controller without quorum.
+func controllerZoneWarning(machines map[string]params.MachineStatus) string {
+	zoneVotes := make(map[string]int)
+	total := 0
+	for _, m := range machines {
+		if !m.HasVote {
+			continue
+		}
+		total++
+		hw, err := instance.ParseHardware(m.Hardware)
+		if err != nil || hw.AvailabilityZone == nil || *hw.AvailabilityZone == "" {
+			continue
+		}
+		zoneVotes[*hw.AvailabilityZone]++
+	}
+	if total < 3 {
+		return ""
+	}
+	for zone, n := range zoneVotes {
+		if n > total/2 {
+			return fmt.Sprintf("%d of %d controller votes are in availability zone %q", n, total, zone)
+		}
+	}
+	return ""
+}
+
 func getRelationIdFromData(unit *params.UnitStatus) int {
 	if relationId_, ok := unit.WorkloadStatus.Data["relation-id"]; ok {
 		if relationId, ok := relationId_.(float64); ok {
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/ansiterm"
 	"github.com/juju/errors"
 	"github.com/juju/utils"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
@@ -28,6 +29,12 @@ import (
 // units. Any subordinate items are indented by two spaces beneath
 // their superior.
 func FormatTabular(writer io.Writer, forceColor bool, value interface{}) error {
+	return formatTabular(writer, forceColor, columnSelection{}, value)
+}
+
+// formatTabular writes the tabular summary, only including the selected
+// columns in the application, unit and machine tables.
+func formatTabular(writer io.Writer, forceColor bool, cols columnSelection, value interface{}) error {
 	const maxVersionWidth = 15
 	const ellipsis = "..."
 	const truncatedWidth = maxVersionWidth - len(ellipsis)
@@ -96,9 +103,13 @@ func FormatTabular(writer io.Writer, forceColor bool, value interface{}) error {
 	}
 
 	units := make(map[string]unitStatus)
-	outputHeaders("App", "Version", "Status", "Scale", "Charm", "Store", "Rev", "OS", "Notes")
-	tw.SetColumnAlignRight(3)
-	tw.SetColumnAlignRight(6)
+	appHeaders := cols.headers("App", "Version", "Status", "Scale", "Charm", "Store", "Rev", "OS", "Notes")
+	outputHeaders(appHeaders...)
+	for _, header := range []string{"Scale", "Rev"} {
+		if i := headerIndex(appHeaders, header); i >= 0 {
+			tw.SetColumnAlignRight(i)
+		}
+	}
 	for _, appName := range utils.SortStringsNaturally(stringKeysFromMap(fs.Applications)) {
 		app := fs.Applications[appName]
 		version := app.Version
@@ -111,19 +122,28 @@ func FormatTabular(writer io.Writer, forceColor bool, value interface{}) error {
 		if app.Exposed {
 			notes = "exposed"
 		}
-		w.Print(appName, version)
-		w.PrintStatus(app.StatusInfo.Current)
-		scale, warn := fs.applicationScale(appName)
-		if warn {
-			w.PrintColor(output.WarningHighlight, scale)
-		} else {
-			w.Print(scale)
+		w.Print(appName)
+		if cols.show("Version") {
+			w.Print(version)
+		}
+		if cols.show("Status") {
+			w.PrintStatus(app.StatusInfo.Current)
 		}
-		p(app.CharmName,
+		if cols.show("Scale") {
+			scale, warn := fs.applicationScale(appName)
+			if warn {
+				w.PrintColor(output.WarningHighlight, scale)
+			} else {
+				w.Print(scale)
+			}
+		}
+		p(cols.values(
+			[]string{"Charm", "Store", "Rev", "OS", "Notes"},
+			app.CharmName,
 			app.CharmOrigin,
 			app.CharmRev,
 			app.OS,
-			notes)
+			notes)...)
 
 		for un, u := range app.Units {
 			units[un] = u
@@ -143,17 +163,22 @@ func FormatTabular(writer io.Writer, forceColor bool, value interface{}) error {
 			name += "*"
 		}
 		w.Print(indent("", level*2, name))
-		w.PrintStatus(u.WorkloadStatusInfo.Current)
-		w.PrintStatus(u.JujuStatusInfo.Current)
-		p(
+		if cols.show("Workload") {
+			w.PrintStatus(u.WorkloadStatusInfo.Current)
+		}
+		if cols.show("Agent") {
+			w.PrintStatus(u.JujuStatusInfo.Current)
+		}
+		p(cols.values(
+			[]string{"Machine", "Public address", "Ports", "Message"},
 			u.Machine,
 			u.PublicAddress,
 			strings.Join(u.OpenedPorts, ","),
 			message,
-		)
+		)...)
 	}
 
-	outputHeaders("Unit", "Workload", "Agent", "Machine", "Public address", "Ports", "Message")
+	outputHeaders(cols.headers("Unit", "Workload", "Agent", "Machine", "Public address", "Ports", "Message")...)
 	for _, name := range utils.SortStringsNaturally(stringKeysFromMap(units)) {
 		u := units[name]
 		pUnit(name, u, 0)
@@ -183,7 +208,7 @@ func FormatTabular(writer io.Writer, forceColor bool, value interface{}) error {
 	}
 
 	p()
-	printMachines(tw, fs.Machines)
+	printMachineColumns(tw, cols, fs.Machines)
 
 	if err := printOffers(tw, fs.Offers); err != nil {
 		w.Println(err.Error())
@@ -275,6 +300,8 @@ func getModelMessage(model modelStatus) string {
 	switch {
 	case model.Status.Message != "":
 		return model.Status.Message
+	case model.HAWarning != "":
+		return model.HAWarning
 	case model.AvailableVersion != "":
 		return "upgrade available: " + model.AvailableVersion
 	default:
@@ -283,14 +310,18 @@ func getModelMessage(model modelStatus) string {
 }
 
 func printMachines(tw *ansiterm.TabWriter, machines map[string]machineStatus) {
+	printMachineColumns(tw, columnSelection{}, machines)
+}
+
+func printMachineColumns(tw *ansiterm.TabWriter, cols columnSelection, machines map[string]machineStatus) {
 	w := output.Wrapper{tw}
-	w.Println("Machine", "State", "DNS", "Inst id", "Series", "AZ", "Message")
+	w.Println(cols.headers("Machine", "State", "DNS", "Inst id", "Series", "AZ", "Message")...)
 	for _, name := range utils.SortStringsNaturally(stringKeysFromMap(machines)) {
-		printMachine(w, machines[name])
+		printMachine(w, cols, machines[name])
 	}
 }
 
-func printMachine(w output.Wrapper, m machineStatus) {
+func printMachine(w output.Wrapper, cols columnSelection, m machineStatus) {
 	// We want to display availability zone so extract from hardware info".
 	hw, err := instance.ParseHardware(m.Hardware)
 	if err != nil {
@@ -301,11 +332,93 @@ func printMachine(w output.Wrapper, m machineStatus) {
 		az = *hw.AvailabilityZone
 	}
 	w.Print(m.Id)
-	w.PrintStatus(m.JujuStatus.Current)
-	w.Println(m.DNSName, m.InstanceId, m.Series, az, m.MachineStatus.Message)
+	if cols.show("State") {
+		w.PrintStatus(m.JujuStatus.Current)
+	}
+	w.Println(cols.values(
+		[]string{"DNS", "Inst id", "Series", "AZ", "Message"},
+		m.DNSName, m.InstanceId, m.Series, az, m.MachineStatus.Message,
+	)...)
 	for _, name := range utils.SortStringsNaturally(stringKeysFromMap(m.Containers)) {
-		printMachine(w, m.Containers[name])
+		printMachine(w, cols, m.Containers[name])
+	}
+}
+
+// tabularColumns holds the names of the columns of the application, unit
+// and machine tables which may be selected with --columns. The first
+// column of each table identifies the row, and is always shown.
+var tabularColumns = []string{
+	"version", "status", "scale", "charm", "store", "rev", "os", "notes",
+	"workload", "agent", "machine", "public-address", "ports", "message",
+	"state", "dns", "inst-id", "series", "az",
+}
+
+// columnSelection records which columns of the application, unit and
+// machine tables to show. If no columns are selected, all are shown.
+type columnSelection struct {
+	names set.Strings
+}
+
+// parseColumns returns the selection of the comma separated column
+// names, as given with --columns.
+func parseColumns(value string) (columnSelection, error) {
+	valid := set.NewStrings(tabularColumns...)
+	names := set.NewStrings()
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !valid.Contains(name) {
+			return columnSelection{}, errors.Errorf(
+				"unknown column %q, expected one of: %s", name, strings.Join(tabularColumns, ", "),
+			)
+		}
+		names.Add(name)
+	}
+	return columnSelection{names}, nil
+}
+
+// columnName returns the name used to select the column with the
+// given header.
+func columnName(header string) string {
+	return strings.Replace(strings.ToLower(header), " ", "-", -1)
+}
+
+func (c columnSelection) show(header string) bool {
+	return c.names.IsEmpty() || c.names.Contains(columnName(header))
+}
+
+// headers returns the selected headers, always including the first.
+func (c columnSelection) headers(headers ...string) []interface{} {
+	result := []interface{}{headers[0]}
+	for _, header := range headers[1:] {
+		if c.show(header) {
+			result = append(result, header)
+		}
+	}
+	return result
+}
+
+// values returns the values of the selected columns among those with
+// the given headers.
+func (c columnSelection) values(headers []string, values ...interface{}) []interface{} {
+	var result []interface{}
+	for i, header := range headers {
+		if c.show(header) {
+			result = append(result, values[i])
+		}
+	}
+	return result
+}
+
+func headerIndex(headers []interface{}, header string) int {
+	for i, h := range headers {
+		if h == header {
+			return i
+		}
 	}
+	return -1
 }
 
 // FormatMachineTabular writes a tabular summary of machine
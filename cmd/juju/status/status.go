@@ -16,6 +16,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
 	"github.com/juju/juju/juju/osenv"
 )
 
@@ -40,6 +41,10 @@ type statusCommand struct {
 	api      statusAPI
 
 	color bool
+	watch bool
+
+	columnsValue string
+	columns      columnSelection
 }
 
 var usageSummary = `
@@ -55,6 +60,25 @@ machines, applications, and units will also be displayed. If a subordinate unit
 is matched, then its principal unit will be displayed. If a principal unit is
 matched, then all of its subordinates will be displayed.
 
+Filters may also be expressions of the form <field><operator><value>. The
+operator is one of "=", "!=" (where the value may contain the '*' wildcard)
+or "~" (where the value is a regular expression). The fields are:
+
+    unit, app          the unit or application name
+    workload, agent    the workload or agent status of a unit
+    machine            the machine id
+    machine.status     the agent status of a machine
+    machine.zone       the availability zone of a machine
+    machine.series     the series of a machine
+
+Units are matched on the fields of the machine they are deployed to. All
+expressions must match for an entity to be displayed.
+
+The --columns option selects the columns shown in the application, unit and
+machine tables of the tabular format, as a comma separated list of column
+names. The column names are the headers in lower case, with spaces replaced
+by hyphens, such as "public-address".
+
 The available output formats are:
 
 - tabular (default): Displays status in a tabular format with a separate table
@@ -72,11 +96,25 @@ The available output formats are:
       in structured YAML format.
 - json: Displays information about the model, machines, applications, and units
       in structured JSON format.
+- csv: Displays the machines, applications, units and the other parts of the
+      model as comma separated values, one table for each.
+- markdown: Displays the same tables as Markdown.
+- template: Renders the status with the Go template given by --template, using
+      the keys of the JSON format.
+
+With --watch, the tabular status is kept up to date as the model changes,
+until interrupted. Rows which changed at the last update are highlighted.
 
 Examples:
     juju show-status
     juju show-status mysql
     juju show-status nova-*
+    juju show-status workload=blocked
+    juju show-status app~^db agent!=idle
+    juju show-status --columns workload,message
+    juju show-status --watch
+    juju show-status --format=csv
+    juju show-status --format=template --template '{{range $name, $app := .applications}}{{$name}} {{$app.charm}}{{"\n"}}{{end}}'
 
 See also:
     machines
@@ -99,10 +137,12 @@ func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
 	f.BoolVar(&c.color, "color", false, "Force use of ANSI color codes")
+	f.BoolVar(&c.watch, "watch", false, "Keep the tabular status up to date as the model changes")
+	f.StringVar(&c.columnsValue, "columns", "", "Comma separated list of columns to show in tabular output")
 
 	defaultFormat := "tabular"
 
-	c.out.AddFlags(f, defaultFormat, map[string]cmd.Formatter{
+	c.out.AddFlags(f, defaultFormat, output.AddReportFormatters(f, map[string]cmd.Formatter{
 		"yaml":    cmd.FormatYaml,
 		"json":    cmd.FormatJson,
 		"short":   FormatOneline,
@@ -110,11 +150,23 @@ func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 		"line":    FormatOneline,
 		"tabular": c.FormatTabular,
 		"summary": FormatSummary,
-	})
+	}))
 }
 
 func (c *statusCommand) Init(args []string) error {
 	c.patterns = args
+	if c.watch && c.out.Name() != "tabular" {
+		return errors.Errorf("--watch is only supported with the tabular format")
+	}
+	if c.columnsValue != "" {
+		if c.out.Name() != "tabular" {
+			return errors.Errorf("--columns is only supported with the tabular format")
+		}
+		var err error
+		if c.columns, err = parseColumns(c.columnsValue); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	// If use of ISO time not specified on command line,
 	// check env var.
 	if !c.isoTime {
@@ -156,6 +208,9 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if c.watch {
+		return c.runWatch(ctx, apiclient, status, controllerName)
+	}
 	formatter := newStatusFormatter(status, controllerName, c.isoTime)
 	formatted, err := formatter.format()
 	if err != nil {
@@ -165,5 +220,5 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 }
 
 func (c *statusCommand) FormatTabular(writer io.Writer, value interface{}) error {
-	return FormatTabular(writer, c.color, value)
+	return formatTabular(writer, c.color, c.columns, value)
 }
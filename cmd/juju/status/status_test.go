@@ -4169,6 +4169,50 @@ Machine  State  DNS  Inst id  Series  AZ  Message
 `[1:])
 }
 
+func (s *StatusSuite) TestFormatTabularColumns(c *gc.C) {
+	status := formattedStatus{
+		Applications: map[string]applicationStatus{
+			"foo": {
+				Units: map[string]unitStatus{
+					"foo/0": {
+						JujuStatusInfo: statusInfoContents{
+							Current: status.Executing,
+							Message: "running config-changed hook",
+						},
+						WorkloadStatusInfo: statusInfoContents{
+							Current: status.Maintenance,
+							Message: "doing some work",
+						},
+						Machine: "0",
+					},
+				},
+			},
+		},
+	}
+	cols, err := parseColumns("workload, Message,state")
+	c.Assert(err, jc.ErrorIsNil)
+	out := &bytes.Buffer{}
+	err = formatTabular(out, false, cols, status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.String(), gc.Equals, `
+Model  Controller  Cloud/Region  Version
+                                 
+
+App
+foo  
+
+Unit   Workload     Message
+foo/0  maintenance  (config-changed) doing some work
+
+Machine  State  Message
+`[1:])
+}
+
+func (s *StatusSuite) TestParseColumnsUnknown(c *gc.C) {
+	_, err := parseColumns("workload,colour")
+	c.Assert(err, gc.ErrorMatches, `unknown column "colour", expected one of: .*`)
+}
+
 func (s *StatusSuite) TestStatusWithNilStatusAPI(c *gc.C) {
 	ctx := s.newContext(c)
 	defer s.resetContext(c, ctx)
@@ -4782,3 +4826,66 @@ func (s *StatusSuite) TestFormatProvisioningError(c *gc.C) {
 		Offers:             map[string]offerStatus{},
 	})
 }
+
+func (s *StatusSuite) TestFormatLXDProfiles(c *gc.C) {
+	status := &params.FullStatus{
+		Model: params.ModelStatusInfo{
+			CloudTag: "cloud-dummy",
+		},
+		Machines: map[string]params.MachineStatus{
+			"1": {
+				InstanceId: "juju-badd06-1",
+				Series:     "xenial",
+				Id:         "1",
+				Jobs:       []multiwatcher.MachineJob{"JobHostUnits"},
+				Containers: map[string]params.MachineStatus{
+					"1/lxd/0": {
+						InstanceId:  "juju-badd06-1-lxd-0",
+						Series:      "xenial",
+						Id:          "1/lxd/0",
+						Jobs:        []multiwatcher.MachineJob{"JobHostUnits"},
+						LXDProfiles: []string{"juju-default-lxd-profile-1"},
+					},
+				},
+			},
+		},
+	}
+	formatter := NewStatusFormatter(status, true)
+	formatted, err := formatter.format()
+	c.Assert(err, jc.ErrorIsNil)
+
+	machine := formatted.Machines["1"]
+	c.Check(machine.LXDProfiles, gc.HasLen, 0)
+	c.Check(machine.Containers["1/lxd/0"].LXDProfiles, jc.DeepEquals, []string{"juju-default-lxd-profile-1"})
+}
+
+func (s *StatusSuite) TestFormatControllerZoneWarning(c *gc.C) {
+	controller := func(id, zone string) params.MachineStatus {
+		return params.MachineStatus{
+			Id:        id,
+			Hardware:  "availability-zone=" + zone,
+			Jobs:      []multiwatcher.MachineJob{multiwatcher.JobManageModel},
+			HasVote:   true,
+			WantsVote: true,
+		}
+	}
+	status := &params.FullStatus{
+		Model: params.ModelStatusInfo{
+			CloudTag: "cloud-dummy",
+		},
+		Machines: map[string]params.MachineStatus{
+			"0": controller("0", "az1"),
+			"1": controller("1", "az2"),
+			"2": controller("2", "az1"),
+		},
+	}
+	formatter := NewStatusFormatter(status, true)
+	formatted, err := formatter.format()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(formatted.Model.HAWarning, gc.Equals, `2 of 3 controller votes are in availability zone "az1"`)
+
+	status.Machines["2"] = controller("2", "az3")
+	formatted, err = formatter.format()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(formatted.Model.HAWarning, gc.Equals, "")
+}
@@ -0,0 +1,344 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// statusWatcher is the part of the API AllWatcher used to follow
+// changes to the model's status.
+type statusWatcher interface {
+	Next() ([]multiwatcher.Delta, error)
+	Stop() error
+}
+
+type watchAllAPI interface {
+	WatchAll() (*api.AllWatcher, error)
+}
+
+var newStatusWatcher = func(client statusAPI) (statusWatcher, error) {
+	watchAll, ok := client.(watchAllAPI)
+	if !ok {
+		return nil, errors.NotSupportedf("watching status")
+	}
+	w, err := watchAll.WatchAll()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// runWatch renders the status and then keeps it up to date by applying
+// the changes reported by the AllWatcher, until interrupted.
+func (c *statusCommand) runWatch(ctx *cmd.Context, client statusAPI, fullStatus *params.FullStatus, controllerName string) error {
+	watcher, err := newStatusWatcher(client)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	screen := newStatusScreen(ctx.Stdout)
+	render := func() error {
+		formatted, err := newStatusFormatter(fullStatus, controllerName, c.isoTime).format()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var buf bytes.Buffer
+		if err := c.FormatTabular(&buf, formatted); err != nil {
+			return errors.Trace(err)
+		}
+		return screen.update(buf.String())
+	}
+	if err := render(); err != nil {
+		watcher.Stop()
+		return err
+	}
+
+	type nextResult struct {
+		deltas []multiwatcher.Delta
+		err    error
+	}
+	results := make(chan nextResult)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			deltas, err := watcher.Next()
+			select {
+			case results <- nextResult{deltas, err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+	for {
+		select {
+		case <-interrupted:
+			fmt.Fprintln(ctx.Stdout)
+			return errors.Trace(watcher.Stop())
+		case result := <-results:
+			if result.err != nil {
+				return errors.Annotate(result.err, "watching status")
+			}
+			if !applyStatusDeltas(fullStatus, result.deltas) {
+				// The changes cannot be applied in place, so
+				// fetch the whole status again.
+				updated, err := client.Status(c.patterns)
+				if updated == nil {
+					watcher.Stop()
+					return errors.Trace(err)
+				}
+				fullStatus = updated
+			}
+			if err := render(); err != nil {
+				watcher.Stop()
+				return err
+			}
+		}
+	}
+}
+
+// applyStatusDeltas updates the status with the changes to machines,
+// applications and units described by the deltas. It returns false if
+// any of the deltas could not be applied, for instance because they
+// describe an entity which was not in the status, in which case the
+// status must be fetched again.
+func applyStatusDeltas(fullStatus *params.FullStatus, deltas []multiwatcher.Delta) bool {
+	applied := true
+	for _, delta := range deltas {
+		switch info := delta.Entity.(type) {
+		case *multiwatcher.MachineInfo:
+			applied = applyMachineDelta(fullStatus.Machines, info, delta.Removed) && applied
+		case *multiwatcher.ApplicationInfo:
+			applied = applyApplicationDelta(fullStatus.Applications, info, delta.Removed) && applied
+		case *multiwatcher.UnitInfo:
+			applied = applyUnitDelta(fullStatus.Applications, info, delta.Removed) && applied
+		case *multiwatcher.RelationInfo, *multiwatcher.RemoteApplicationInfo:
+			// Relations and remote applications are shown
+			// with details not held by the deltas.
+			applied = false
+		}
+	}
+	return applied
+}
+
+func applyMachineDelta(machines map[string]params.MachineStatus, info *multiwatcher.MachineInfo, removed bool) bool {
+	parentId := info.Id
+	var path []string
+	for {
+		path = append([]string{parentId}, path...)
+		if !strings.Contains(parentId, "/") {
+			break
+		}
+		parentId = parentId[:strings.LastIndex(parentId, "/")]
+		parentId = parentId[:strings.LastIndex(parentId, "/")]
+	}
+	// Walk down to the map holding the machine.
+	for _, id := range path[:len(path)-1] {
+		parent, ok := machines[id]
+		if !ok {
+			return removed
+		}
+		machines = parent.Containers
+	}
+	m, ok := machines[info.Id]
+	if removed {
+		delete(machines, info.Id)
+		return true
+	}
+	if !ok {
+		return false
+	}
+	m.InstanceId = instance.Id(info.InstanceId)
+	m.Series = info.Series
+	m.HasVote = info.HasVote
+	m.WantsVote = info.WantsVote
+	updateDetailedStatus(&m.AgentStatus, info.AgentStatus)
+	updateDetailedStatus(&m.InstanceStatus, info.InstanceStatus)
+	if len(info.Addresses) > 0 {
+		addrs := make([]network.Address, len(info.Addresses))
+		for i, addr := range info.Addresses {
+			addrs[i] = network.Address{
+				Value: addr.Value,
+				Type:  network.AddressType(addr.Type),
+				Scope: network.Scope(addr.Scope),
+			}
+		}
+		if addr, ok := network.SelectPublicAddress(addrs); ok {
+			m.DNSName = addr.Value
+		}
+	}
+	machines[info.Id] = m
+	return true
+}
+
+func applyApplicationDelta(applications map[string]params.ApplicationStatus, info *multiwatcher.ApplicationInfo, removed bool) bool {
+	app, ok := applications[info.Name]
+	if removed {
+		delete(applications, info.Name)
+		return true
+	}
+	if !ok {
+		return false
+	}
+	app.Charm = info.CharmURL
+	app.Exposed = info.Exposed
+	app.WorkloadVersion = info.WorkloadVersion
+	updateDetailedStatus(&app.Status, info.Status)
+	applications[info.Name] = app
+	return true
+}
+
+func applyUnitDelta(applications map[string]params.ApplicationStatus, info *multiwatcher.UnitInfo, removed bool) bool {
+	units, ok := findUnits(applications, info.Name)
+	if !ok {
+		// A new unit; its application's status shows
+		// details not held by the delta.
+		return removed
+	}
+	if removed {
+		delete(units, info.Name)
+		return true
+	}
+	unit := units[info.Name]
+	unit.PublicAddress = info.PublicAddress
+	if !info.Subordinate {
+		unit.Machine = info.MachineId
+	}
+	unit.OpenedPorts = nil
+	for _, pr := range info.PortRanges {
+		unit.OpenedPorts = append(unit.OpenedPorts, network.PortRange{
+			FromPort: pr.FromPort,
+			ToPort:   pr.ToPort,
+			Protocol: pr.Protocol,
+		}.String())
+	}
+	updateDetailedStatus(&unit.WorkloadStatus, info.WorkloadStatus)
+	updateDetailedStatus(&unit.AgentStatus, info.AgentStatus)
+	units[info.Name] = unit
+	return true
+}
+
+// findUnits returns the map of units holding the named unit, which may
+// be a principal unit of an application or a subordinate of any unit.
+func findUnits(applications map[string]params.ApplicationStatus, name string) (map[string]params.UnitStatus, bool) {
+	var search func(units map[string]params.UnitStatus) (map[string]params.UnitStatus, bool)
+	search = func(units map[string]params.UnitStatus) (map[string]params.UnitStatus, bool) {
+		if _, ok := units[name]; ok {
+			return units, true
+		}
+		for _, unit := range units {
+			if found, ok := search(unit.Subordinates); ok {
+				return found, true
+			}
+		}
+		return nil, false
+	}
+	for _, app := range applications {
+		if units, ok := search(app.Units); ok {
+			return units, true
+		}
+	}
+	return nil, false
+}
+
+func updateDetailedStatus(out *params.DetailedStatus, in multiwatcher.StatusInfo) {
+	out.Err = in.Err
+	out.Status = in.Current.String()
+	out.Info = in.Message
+	out.Since = in.Since
+	if in.Version != "" {
+		out.Version = in.Version
+	}
+}
+
+const (
+	ansiBold      = "\x1b[1m"
+	ansiReset     = "\x1b[0m"
+	ansiClear     = "\x1b[H\x1b[2J"
+	ansiClearLine = "\x1b[K"
+)
+
+// statusScreen draws successive renderings of the status on a terminal,
+// only redrawing the lines that differ from the previous rendering.
+// Lines whose content is new are highlighted until the next update.
+type statusScreen struct {
+	out         io.Writer
+	lines       []string
+	highlighted map[int]bool
+}
+
+func newStatusScreen(out io.Writer) *statusScreen {
+	return &statusScreen{out: out}
+}
+
+// update draws the given text, which replaces what was drawn before.
+func (s *statusScreen) update(text string) error {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	var buf bytes.Buffer
+	if s.lines == nil {
+		buf.WriteString(ansiClear)
+		for _, line := range lines {
+			buf.WriteString(line + "\n")
+		}
+		s.lines = lines
+		s.highlighted = make(map[int]bool)
+		_, err := s.out.Write(buf.Bytes())
+		return errors.Trace(err)
+	}
+
+	previous := make(map[string]bool)
+	for _, line := range s.lines {
+		previous[line] = true
+	}
+	highlighted := make(map[int]bool)
+	for i, line := range lines {
+		moved := i >= len(s.lines) || s.lines[i] != line
+		isNew := !previous[line]
+		if !moved && !s.highlighted[i] {
+			continue
+		}
+		fmt.Fprintf(&buf, "\x1b[%d;1H", i+1)
+		if isNew {
+			highlighted[i] = true
+			buf.WriteString(highlight(line))
+		} else {
+			buf.WriteString(line)
+		}
+		buf.WriteString(ansiClearLine)
+	}
+	for i := len(lines); i < len(s.lines); i++ {
+		fmt.Fprintf(&buf, "\x1b[%d;1H%s", i+1, ansiClearLine)
+	}
+	fmt.Fprintf(&buf, "\x1b[%d;1H", len(lines)+1)
+	s.lines = lines
+	s.highlighted = highlighted
+	_, err := s.out.Write(buf.Bytes())
+	return errors.Trace(err)
+}
+
+// highlight returns the line in bold, keeping it bold after any resets
+// of colour within the line.
+func highlight(line string) string {
+	return ansiBold + strings.Replace(line, ansiReset, ansiReset+ansiBold, -1) + ansiReset
+}
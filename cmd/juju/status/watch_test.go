@@ -0,0 +1,183 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"bytes"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type watchSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&watchSuite{})
+
+func watchTestStatus() *params.FullStatus {
+	return &params.FullStatus{
+		Machines: map[string]params.MachineStatus{
+			"0": {
+				Id:          "0",
+				AgentStatus: params.DetailedStatus{Status: "pending"},
+				Containers: map[string]params.MachineStatus{
+					"0/lxd/0": {Id: "0/lxd/0"},
+				},
+			},
+		},
+		Applications: map[string]params.ApplicationStatus{
+			"mysql": {
+				Charm: "cs:mysql-1",
+				Units: map[string]params.UnitStatus{
+					"mysql/0": {
+						Machine: "0",
+						Subordinates: map[string]params.UnitStatus{
+							"logging/0": {},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *watchSuite) TestApplyStatusDeltas(c *gc.C) {
+	fs := watchTestStatus()
+	applied := applyStatusDeltas(fs, []multiwatcher.Delta{{
+		Entity: &multiwatcher.MachineInfo{
+			Id:          "0",
+			InstanceId:  "inst-0",
+			AgentStatus: multiwatcher.StatusInfo{Current: status.Started},
+			Addresses: []multiwatcher.Address{{
+				Value: "10.0.0.1", Type: "ipv4", Scope: "public",
+			}},
+		},
+	}, {
+		Entity: &multiwatcher.MachineInfo{
+			Id:          "0/lxd/0",
+			AgentStatus: multiwatcher.StatusInfo{Current: status.Down},
+		},
+	}, {
+		Entity: &multiwatcher.ApplicationInfo{
+			Name:     "mysql",
+			CharmURL: "cs:mysql-2",
+			Exposed:  true,
+			Status:   multiwatcher.StatusInfo{Current: status.Active},
+		},
+	}, {
+		Entity: &multiwatcher.UnitInfo{
+			Name:           "mysql/0",
+			Application:    "mysql",
+			MachineId:      "0",
+			PublicAddress:  "10.0.0.1",
+			PortRanges:     []multiwatcher.PortRange{{FromPort: 3306, ToPort: 3306, Protocol: "tcp"}},
+			WorkloadStatus: multiwatcher.StatusInfo{Current: status.Active, Message: "ready"},
+			AgentStatus:    multiwatcher.StatusInfo{Current: status.Idle},
+		},
+	}, {
+		Entity: &multiwatcher.UnitInfo{
+			Name:           "logging/0",
+			Application:    "logging",
+			Subordinate:    true,
+			WorkloadStatus: multiwatcher.StatusInfo{Current: status.Blocked},
+		},
+	}})
+	c.Assert(applied, jc.IsTrue)
+
+	m := fs.Machines["0"]
+	c.Check(m.InstanceId, gc.Equals, instance.Id("inst-0"))
+	c.Check(m.AgentStatus.Status, gc.Equals, "started")
+	c.Check(m.DNSName, gc.Equals, "10.0.0.1")
+	c.Check(m.Containers["0/lxd/0"].AgentStatus.Status, gc.Equals, "down")
+
+	app := fs.Applications["mysql"]
+	c.Check(app.Charm, gc.Equals, "cs:mysql-2")
+	c.Check(app.Exposed, jc.IsTrue)
+	c.Check(app.Status.Status, gc.Equals, "active")
+
+	unit := app.Units["mysql/0"]
+	c.Check(unit.PublicAddress, gc.Equals, "10.0.0.1")
+	c.Check(unit.OpenedPorts, jc.DeepEquals, []string{"3306/tcp"})
+	c.Check(unit.WorkloadStatus.Status, gc.Equals, "active")
+	c.Check(unit.WorkloadStatus.Info, gc.Equals, "ready")
+	c.Check(unit.AgentStatus.Status, gc.Equals, "idle")
+	c.Check(unit.Subordinates["logging/0"].WorkloadStatus.Status, gc.Equals, "blocked")
+}
+
+func (s *watchSuite) TestApplyStatusDeltasRemoved(c *gc.C) {
+	fs := watchTestStatus()
+	applied := applyStatusDeltas(fs, []multiwatcher.Delta{{
+		Removed: true,
+		Entity:  &multiwatcher.MachineInfo{Id: "0/lxd/0"},
+	}, {
+		Removed: true,
+		Entity:  &multiwatcher.UnitInfo{Name: "logging/0"},
+	}, {
+		Removed: true,
+		Entity:  &multiwatcher.UnitInfo{Name: "wordpress/0"},
+	}})
+	c.Assert(applied, jc.IsTrue)
+	c.Check(fs.Machines["0"].Containers, gc.HasLen, 0)
+	c.Check(fs.Applications["mysql"].Units["mysql/0"].Subordinates, gc.HasLen, 0)
+}
+
+func (s *watchSuite) TestApplyStatusDeltasUnknownEntity(c *gc.C) {
+	for i, delta := range []multiwatcher.Delta{{
+		Entity: &multiwatcher.MachineInfo{Id: "1"},
+	}, {
+		Entity: &multiwatcher.ApplicationInfo{Name: "wordpress"},
+	}, {
+		Entity: &multiwatcher.UnitInfo{Name: "mysql/1", Application: "mysql"},
+	}, {
+		Entity: &multiwatcher.RelationInfo{Key: "mysql:cluster"},
+	}} {
+		c.Logf("test %d", i)
+		applied := applyStatusDeltas(watchTestStatus(), []multiwatcher.Delta{delta})
+		c.Check(applied, jc.IsFalse)
+	}
+}
+
+func (s *watchSuite) TestStatusScreenRedrawsChangedLines(c *gc.C) {
+	var buf bytes.Buffer
+	screen := newStatusScreen(&buf)
+	err := screen.update("Unit     Workload\nmysql/0  waiting\nmysql/1  active\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, ansiClear+
+		"Unit     Workload\nmysql/0  waiting\nmysql/1  active\n")
+
+	buf.Reset()
+	err = screen.update("Unit     Workload\nmysql/0  active\nmysql/1  active\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"\x1b[2;1H"+ansiBold+"mysql/0  active"+ansiReset+ansiClearLine+
+		"\x1b[4;1H")
+
+	// The highlight is removed at the next update, and lines which
+	// only moved are redrawn without highlighting.
+	buf.Reset()
+	err = screen.update("Unit     Workload\nmysql/1  active\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"\x1b[2;1Hmysql/1  active"+ansiClearLine+
+		"\x1b[3;1H"+ansiClearLine+
+		"\x1b[3;1H")
+}
+
+func (s *watchSuite) TestHighlightKeepsBoldAfterReset(c *gc.C) {
+	c.Assert(highlight("a"+ansiReset+"b"), gc.Equals,
+		ansiBold+"a"+ansiReset+ansiBold+"b"+ansiReset)
+}
+
+func (s *watchSuite) TestWatchRequiresTabular(c *gc.C) {
+	err := cmdtesting.InitCommand(&statusCommand{}, []string{"--watch", "--format", "yaml"})
+	c.Assert(err, gc.ErrorMatches, "--watch is only supported with the tabular format")
+}
@@ -0,0 +1,166 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/voyeur"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/cmd/jujud/agent/caasoperator"
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
+	jworker "github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/logsender"
+)
+
+var (
+	// should be an explicit dependency, can't do it cleanly yet
+	caasOperatorManifolds = caasoperator.Manifolds
+)
+
+// CaasOperatorAgent is a cmd.Command responsible for running a CAAS
+// operator agent, which runs the hooks of all the units of a single
+// CAAS application.
+type CaasOperatorAgent struct {
+	cmd.CommandBase
+	tomb tomb.Tomb
+	AgentConf
+	configChangedVal *voyeur.Value
+	ApplicationName  string
+	runner           *worker.Runner
+	bufferedLogger   *logsender.BufferedLogWriter
+	logToStdErr      bool
+	ctx              *cmd.Context
+}
+
+// NewCaasOperatorAgent creates a new CaasOperatorAgent value
+// properly initialized.
+func NewCaasOperatorAgent(ctx *cmd.Context, bufferedLogger *logsender.BufferedLogWriter) *CaasOperatorAgent {
+	return &CaasOperatorAgent{
+		AgentConf:        NewAgentConf(""),
+		configChangedVal: voyeur.NewValue(true),
+		ctx:              ctx,
+		bufferedLogger:   bufferedLogger,
+	}
+}
+
+// Info returns usage information for the command.
+func (op *CaasOperatorAgent) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "caasoperator",
+		Purpose: "run a juju CAAS operator agent",
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (op *CaasOperatorAgent) SetFlags(f *gnuflag.FlagSet) {
+	op.AgentConf.AddFlags(f)
+	f.StringVar(&op.ApplicationName, "application-name", "", "name of the application to run hooks for")
+	f.BoolVar(&op.logToStdErr, "log-to-stderr", false, "whether to log to standard error instead of log files")
+}
+
+// Init initializes the command for running.
+func (op *CaasOperatorAgent) Init(args []string) error {
+	if op.ApplicationName == "" {
+		return cmdutil.RequiredError("application-name")
+	}
+	if !names.IsValidApplication(op.ApplicationName) {
+		return errors.Errorf(`--application-name option expects "<application>" argument`)
+	}
+	if err := op.AgentConf.CheckArgs(args); err != nil {
+		return err
+	}
+	op.runner = worker.NewRunner(worker.RunnerParams{
+		IsFatal:       cmdutil.IsFatal,
+		MoreImportant: cmdutil.MoreImportant,
+		RestartDelay:  jworker.RestartDelay,
+	})
+
+	if err := op.ReadConfig(op.Tag().String()); err != nil {
+		return err
+	}
+	agentConfig := op.CurrentConfig()
+
+	if !op.logToStdErr {
+		// the writer in ctx.stderr gets set as the loggo writer in github.com/juju/cmd/logging.go
+		op.ctx.Stderr = &lumberjack.Logger{
+			Filename:   agent.LogFilename(agentConfig),
+			MaxSize:    300, // megabytes
+			MaxBackups: 2,
+			Compress:   true,
+		}
+	}
+	return nil
+}
+
+// Stop stops the operator agent.
+func (op *CaasOperatorAgent) Stop() error {
+	op.runner.Kill()
+	return op.tomb.Wait()
+}
+
+// Run runs a CAAS operator agent.
+func (op *CaasOperatorAgent) Run(ctx *cmd.Context) error {
+	defer op.tomb.Done()
+	if err := op.ReadConfig(op.Tag().String()); err != nil {
+		return err
+	}
+	setupAgentLogging(op.CurrentConfig())
+
+	op.runner.StartWorker("api", op.Workers)
+	err := cmdutil.AgentDone(logger, op.runner.Wait())
+	op.tomb.Kill(err)
+	return err
+}
+
+// Workers returns a dependency.Engine running the operator's responsibilities.
+func (op *CaasOperatorAgent) Workers() (worker.Worker, error) {
+	manifolds := caasOperatorManifolds(caasoperator.ManifoldsConfig{
+		Agent:               agent.APIHostPortsSetter{op},
+		AgentConfigChanged:  op.configChangedVal,
+		Clock:               clock.WallClock,
+		LogSource:           op.bufferedLogger.Logs(),
+		LeadershipGuarantee: 30 * time.Second,
+	})
+
+	config := dependency.EngineConfig{
+		IsFatal:     cmdutil.IsFatal,
+		WorstError:  cmdutil.MoreImportantError,
+		ErrorDelay:  3 * time.Second,
+		BounceDelay: 10 * time.Millisecond,
+	}
+	engine, err := dependency.NewEngine(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := dependency.Install(engine, manifolds); err != nil {
+		if err := worker.Stop(engine); err != nil {
+			logger.Errorf("while stopping engine with bad manifolds: %v", err)
+		}
+		return nil, err
+	}
+	return engine, nil
+}
+
+// Tag returns the tag of the application the operator runs hooks for.
+func (op *CaasOperatorAgent) Tag() names.Tag {
+	return names.NewApplicationTag(op.ApplicationName)
+}
+
+// ChangeConfig is part of the agent.Agent interface.
+func (op *CaasOperatorAgent) ChangeConfig(mutate agent.ConfigMutator) error {
+	err := op.AgentConf.ChangeConfig(mutate)
+	op.configChangedVal.Set(true)
+	return errors.Trace(err)
+}
@@ -0,0 +1,132 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/voyeur"
+	worker "gopkg.in/juju/worker.v1"
+
+	coreagent "github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+	jworker "github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/agent"
+	"github.com/juju/juju/worker/apicaller"
+	"github.com/juju/juju/worker/apiconfigwatcher"
+	"github.com/juju/juju/worker/caasoperator"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/logsender"
+)
+
+// ManifoldsConfig allows specialisation of the result of Manifolds.
+type ManifoldsConfig struct {
+
+	// Agent contains the agent that will be wrapped and made available to
+	// its dependencies via a dependency.Engine.
+	Agent coreagent.Agent
+
+	// AgentConfigChanged is set whenever the operator agent's config
+	// is updated.
+	AgentConfigChanged *voyeur.Value
+
+	// Clock is supplied to the workers that need it.
+	Clock clock.Clock
+
+	// LogSource will be read from by the logsender component.
+	LogSource logsender.LogRecordCh
+
+	// LeadershipGuarantee controls the behaviour of the leadership
+	// trackers of the application's units.
+	LeadershipGuarantee time.Duration
+}
+
+// Manifolds returns a set of co-configured manifolds covering the various
+// responsibilities of a CAAS operator agent, which runs the hooks of all
+// the units of a CAAS application.
+//
+// Thou Shalt Not Use String Literals In This Function. Or Else.
+func Manifolds(config ManifoldsConfig) dependency.Manifolds {
+
+	// connectFilter exists to let us retry api connections immediately
+	// on password change, rather than causing the dependency engine to
+	// wait for a while.
+	connectFilter := func(err error) error {
+		cause := errors.Cause(err)
+		if cause == apicaller.ErrChangedPassword {
+			return dependency.ErrBounce
+		} else if cause == apicaller.ErrConnectImpossible {
+			return jworker.ErrTerminateAgent
+		}
+		return err
+	}
+
+	return dependency.Manifolds{
+
+		// The agent manifold references the enclosing agent, and is the
+		// foundation stone on which most other manifolds ultimately depend.
+		agentName: agent.Manifold(config.Agent),
+
+		// The api-config-watcher manifold monitors the API server
+		// addresses in the agent config and bounces when they
+		// change.
+		apiConfigWatcherName: apiconfigwatcher.Manifold(apiconfigwatcher.ManifoldConfig{
+			AgentName:          agentName,
+			AgentConfigChanged: config.AgentConfigChanged,
+		}),
+
+		// The api caller is the operator's own connection to the API,
+		// authenticated as the application.
+		apiCallerName: apicaller.Manifold(apicaller.ManifoldConfig{
+			AgentName:            agentName,
+			APIConfigWatcherName: apiConfigWatcherName,
+			APIOpen:              api.Open,
+			NewConnection:        apicaller.ScaryConnect,
+			Filter:               connectFilter,
+		}),
+
+		clockName: clockManifold(config.Clock),
+
+		// The log sender is a leaf worker that sends log messages to some
+		// API server, when configured so to do.
+		logSenderName: logsender.Manifold(logsender.ManifoldConfig{
+			APICallerName: apiCallerName,
+			LogSource:     config.LogSource,
+		}),
+
+		// The operator runs a uniter for each of the application's
+		// units, each connected to the API as that unit.
+		operatorName: caasoperator.Manifold(caasoperator.ManifoldConfig{
+			AgentName:           agentName,
+			APICallerName:       apiCallerName,
+			ClockName:           clockName,
+			MachineLockName:     coreagent.MachineLockName,
+			LeadershipGuarantee: config.LeadershipGuarantee,
+			NewFacade:           caasoperator.NewFacade,
+			NewWorker:           caasoperator.NewWorker,
+			NewUnitWorker:       caasoperator.NewUnitWorker,
+		}),
+	}
+}
+
+func clockManifold(clock clock.Clock) dependency.Manifold {
+	return dependency.Manifold{
+		Start: func(_ dependency.Context) (worker.Worker, error) {
+			return engine.NewValueWorker(clock)
+		},
+		Output: engine.ValueWorkerOutput,
+	}
+}
+
+const (
+	agentName            = "agent"
+	apiConfigWatcherName = "api-config-watcher"
+	apiCallerName        = "api-caller"
+	clockName            = "clock"
+	logSenderName        = "log-sender"
+	operatorName         = "operator"
+)
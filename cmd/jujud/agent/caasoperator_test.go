@@ -0,0 +1,52 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/logsender"
+)
+
+type CaasOperatorSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&CaasOperatorSuite{})
+
+func (s *CaasOperatorSuite) newBufferedLogWriter() *logsender.BufferedLogWriter {
+	logger := logsender.NewBufferedLogWriter(1024)
+	s.AddCleanup(func(*gc.C) { logger.Close() })
+	return logger
+}
+
+func (s *CaasOperatorSuite) TestParseMissing(c *gc.C) {
+	a := NewCaasOperatorAgent(nil, s.newBufferedLogWriter())
+	err := cmdtesting.InitCommand(a, []string{
+		"--data-dir", "jc",
+	})
+	c.Assert(err, gc.ErrorMatches, "--application-name option must be set")
+}
+
+func (s *CaasOperatorSuite) TestParseNonsense(c *gc.C) {
+	for _, args := range [][]string{
+		{"--application-name", "wordpress/0"},
+		{"--application-name", "20"},
+	} {
+		a := NewCaasOperatorAgent(nil, s.newBufferedLogWriter())
+		err := cmdtesting.InitCommand(a, append(args, "--data-dir", "jc"))
+		c.Check(err, gc.ErrorMatches, `--application-name option expects "<application>" argument`)
+	}
+}
+
+func (s *CaasOperatorSuite) TestParseUnknown(c *gc.C) {
+	a := NewCaasOperatorAgent(nil, s.newBufferedLogWriter())
+	err := cmdtesting.InitCommand(a, []string{
+		"--application-name", "wordpress",
+		"thundering typhoons",
+	})
+	c.Check(err, gc.ErrorMatches, `unrecognized args: \["thundering typhoons"\]`)
+}
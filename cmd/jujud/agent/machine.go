@@ -923,6 +923,7 @@ func (a *MachineAgent) openStateForUpgrade() (*state.State, error) {
 		// to pass in the max-txn-log-size value.
 		InitDatabaseFunc:       state.InitDatabase,
 		RunTransactionObserver: a.mongoTxnCollector.AfterRunTransaction,
+		SecretsKey:             controllerSecretsKey(agentConfig),
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -999,6 +1000,7 @@ func (a *MachineAgent) updateSupportedContainers(
 		SupportedContainers: containers,
 		Machine:             machine,
 		Provisioner:         pr,
+		APICaller:           st,
 		Config:              agentConfig,
 		InitLockName:        agent.MachineLockName,
 	}
@@ -1066,6 +1068,7 @@ func (a *MachineAgent) initController(agentConfig agent.Config) (*state.Controll
 			stateenvirons.GetNewEnvironFunc(environs.New),
 		),
 		RunTransactionObserver: a.mongoTxnCollector.AfterRunTransaction,
+		SecretsKey:             controllerSecretsKey(agentConfig),
 	})
 	return ctlr, nil
 }
@@ -1713,6 +1716,13 @@ func (a *MachineAgent) ensureMongoServer(agentConfig agent.Config) (err error) {
 	return nil
 }
 
+// controllerSecretsKey returns the key from which the keys that
+// encrypt charm secrets are derived, as recorded in the agent config.
+func controllerSecretsKey(agentConfig agent.Config) string {
+	info, _ := agentConfig.StateServingInfo()
+	return info.SecretsKey
+}
+
 func openState(
 	agentConfig agent.Config,
 	dialOpts mongo.DialOpts,
@@ -1732,6 +1742,7 @@ func openState(
 			stateenvirons.GetNewEnvironFunc(environs.New),
 		),
 		RunTransactionObserver: runTransactionObserver,
+		SecretsKey:             controllerSecretsKey(agentConfig),
 	})
 	if err != nil {
 		return nil, nil, err
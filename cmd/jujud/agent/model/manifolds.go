@@ -7,20 +7,24 @@ import (
 	"time"
 
 	"github.com/juju/utils/clock"
+	"github.com/juju/utils/featureflag"
 	"github.com/juju/utils/voyeur"
 	"gopkg.in/juju/worker.v1"
 
 	coreagent "github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas/kubernetes/provider"
 	"github.com/juju/juju/cmd/jujud/agent/engine"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/worker/actionpruner"
 	"github.com/juju/juju/worker/agent"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/apiconfigwatcher"
 	"github.com/juju/juju/worker/applicationscaler"
+	"github.com/juju/juju/worker/caasunitprovisioner"
 	"github.com/juju/juju/worker/charmrevision"
 	"github.com/juju/juju/worker/charmrevision/charmrevisionmanifold"
 	"github.com/juju/juju/worker/cleaner"
@@ -333,6 +337,15 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 		NewRemoteRelationsFacade: remoterelations.NewRemoteRelationsFacade,
 		NewWorker:                remoterelations.NewWorker,
 	}))
+	if featureflag.Enabled(feature.CAAS) {
+		result[caasUnitProvisionerName] = ifNotMigrating(caasunitprovisioner.Manifold(caasunitprovisioner.ManifoldConfig{
+			APICallerName: apiCallerName,
+			ClockName:     clockName,
+			NewFacade:     caasunitprovisioner.NewFacade,
+			NewBroker:     provider.NewK8sBroker,
+			NewWorker:     caasunitprovisioner.NewWorker,
+		}))
+	}
 	return result
 }
 
@@ -439,4 +452,6 @@ const (
 	machineUndertakerName    = "machine-undertaker"
 	remoteRelationsName      = "remote-relations"
 	logForwarderName         = "log-forwarder"
+
+	caasUnitProvisionerName = "caas-unit-provisioner"
 )
@@ -10,6 +10,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/cmd/jujud/agent/model"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/workertest"
 )
@@ -63,6 +64,14 @@ func (s *ManifoldsSuite) TestNames(c *gc.C) {
 	})
 }
 
+func (s *ManifoldsSuite) TestNamesCAAS(c *gc.C) {
+	s.SetFeatureFlags(feature.CAAS)
+	manifolds := model.Manifolds(model.ManifoldsConfig{
+		Agent: &mockAgent{},
+	})
+	c.Check(manifolds["caas-unit-provisioner"].Start, gc.NotNil)
+}
+
 func (s *ManifoldsSuite) TestFlagDependencies(c *gc.C) {
 	exclusions := set.NewStrings(
 		"agent",
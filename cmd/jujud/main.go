@@ -17,6 +17,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils/exec"
+	"github.com/juju/utils/featureflag"
 	proxyutils "github.com/juju/utils/proxy"
 
 	"github.com/juju/juju/agent"
@@ -25,6 +26,7 @@ import (
 	"github.com/juju/juju/cmd/jujud/dumplogs"
 	"github.com/juju/juju/cmd/jujud/introspect"
 	components "github.com/juju/juju/component/all"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/names"
 	"github.com/juju/juju/juju/sockets"
 	// Import the providers.
@@ -180,6 +182,10 @@ func jujuDMain(args []string, ctx *cmd.Context) (code int, err error) {
 	}
 	jujud.Register(unitAgent)
 
+	if featureflag.Enabled(feature.CAAS) {
+		jujud.Register(agentcmd.NewCaasOperatorAgent(ctx, bufferedLogger))
+	}
+
 	jujud.Register(NewUpgradeMongoCommand())
 	jujud.Register(agentcmd.NewCheckConnectionCommand(agentConf, agentcmd.ConnectAsAgent))
 
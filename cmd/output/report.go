@@ -0,0 +1,508 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package output
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils"
+)
+
+// AddReportFormatters adds the csv, markdown and template formatters to
+// the given formatters, and adds the --template flag used by the
+// template format to the flag set. It returns the formatters so that
+// it can wrap the map passed to cmd.Output.AddFlags.
+func AddReportFormatters(f *gnuflag.FlagSet, formatters map[string]cmd.Formatter) map[string]cmd.Formatter {
+	var text string
+	f.StringVar(&text, "template", "", "Go template used to render the output with --format=template")
+	formatters["csv"] = FormatCSV
+	formatters["markdown"] = FormatMarkdown
+	formatters["template"] = func(writer io.Writer, value interface{}) error {
+		return FormatTemplate(writer, text, value)
+	}
+	return formatters
+}
+
+// FormatCSV writes the value as comma separated values. Maps of records,
+// such as the machines or applications in the status, become rows with
+// the map key in the first column; nested records are flattened into
+// columns named after their path, joined with ".". When the value holds
+// more than one table, such as the units of each application, each
+// table is preceded by a row holding its name and followed by an empty
+// line.
+func FormatCSV(writer io.Writer, value interface{}) error {
+	tables := reportTablesFor(value)
+	titled := len(tables) > 1
+	for i, t := range tables {
+		if i > 0 {
+			if _, err := fmt.Fprintln(writer); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		w := csv.NewWriter(writer)
+		if titled && t.name != "" {
+			w.Write([]string{"# " + t.name})
+		}
+		w.Write(t.columns)
+		for _, row := range t.rows {
+			w.Write(t.values(row))
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// FormatMarkdown writes the value as Markdown tables, laid out as for
+// FormatCSV. When the value holds more than one table, each table is
+// preceded by a heading holding its name.
+func FormatMarkdown(writer io.Writer, value interface{}) error {
+	tables := reportTablesFor(value)
+	titled := len(tables) > 1
+	var buf bytes.Buffer
+	for i, t := range tables {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if titled && t.name != "" {
+			fmt.Fprintf(&buf, "### %s\n\n", t.name)
+		}
+		separators := make([]string, len(t.columns))
+		for i := range separators {
+			separators[i] = "---"
+		}
+		writeMarkdownRow(&buf, t.columns)
+		writeMarkdownRow(&buf, separators)
+		for _, row := range t.rows {
+			writeMarkdownRow(&buf, t.values(row))
+		}
+	}
+	_, err := io.WriteString(writer, buf.String())
+	return errors.Trace(err)
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", "<br>")
+
+func writeMarkdownRow(buf *bytes.Buffer, cells []string) {
+	buf.WriteString("|")
+	for _, cell := range cells {
+		buf.WriteString(" " + markdownEscaper.Replace(cell) + " |")
+	}
+	buf.WriteString("\n")
+}
+
+// FormatTemplate writes the value rendered with the given Go template.
+// The template is executed with the value as it would be written in
+// JSON, so that the keys used in the template are those of the json
+// and yaml formats, e.g. {{range $name, $m := .machines}}.
+func FormatTemplate(writer io.Writer, text string, value interface{}) error {
+	if text == "" {
+		return errors.New("no template specified, use --template with --format=template")
+	}
+	tmpl, err := template.New("output").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return errors.Annotate(err, "invalid template")
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(tmpl.Execute(writer, generic))
+}
+
+// reportNode is a value to be laid out in tables: a record, a
+// collection, a list or a scalar.
+type reportNode struct {
+	kind   reportNodeKind
+	keys   []string
+	fields map[string]*reportNode
+	items  []*reportNode
+	scalar string
+}
+
+type reportNodeKind int
+
+const (
+	scalarNode reportNodeKind = iota
+	recordNode
+	collectionNode
+	listNode
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// reportNodeFor converts the value into a report node. Structs become
+// records holding the fields that would be written in JSON, and maps
+// become collections sorted on their keys, so that records and
+// collections can be told apart even though both are objects in JSON.
+// It returns nil for values which are omitted.
+func reportNodeFor(v reflect.Value) *reportNode {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct && v.Kind() != reflect.Map {
+		if node, ok := marshaledNode(v); ok {
+			return node
+		}
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if implements(v, textMarshalerType) {
+			// Structs such as time.Time are written as text.
+			if node, ok := marshaledNode(v); ok {
+				return node
+			}
+		}
+		node := &reportNode{kind: recordNode, fields: make(map[string]*reportNode)}
+		addStructFields(node, v)
+		return node
+	case reflect.Map:
+		node := &reportNode{kind: collectionNode, fields: make(map[string]*reportNode)}
+		for _, key := range v.MapKeys() {
+			name := fmt.Sprint(key.Interface())
+			if child := reportNodeFor(v.MapIndex(key)); child != nil {
+				node.keys = append(node.keys, name)
+				node.fields[name] = child
+			}
+		}
+		utils.SortStringsNaturally(node.keys)
+		return node
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return &reportNode{scalar: string(v.Bytes())}
+		}
+		node := &reportNode{kind: listNode}
+		for i := 0; i < v.Len(); i++ {
+			if child := reportNodeFor(v.Index(i)); child != nil {
+				node.items = append(node.items, child)
+			}
+		}
+		return node
+	}
+	return &reportNode{scalar: fmt.Sprint(v.Interface())}
+}
+
+// marshaledNode returns the node for values which define how they
+// are marshalled. Values which marshal as objects or arrays are
+// returned as records or lists of scalars.
+func marshaledNode(v reflect.Value) (*reportNode, bool) {
+	if !implements(v, jsonMarshalerType) && !implements(v, textMarshalerType) {
+		return nil, false
+	}
+	if v.CanAddr() {
+		v = v.Addr()
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return &reportNode{scalar: fmt.Sprint(v.Interface())}, true
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, false
+	}
+	return genericNode(generic), true
+}
+
+// implements reports whether the value, or a pointer to it when it is
+// addressable, implements the interface.
+func implements(v reflect.Value, iface reflect.Type) bool {
+	if v.Type().Implements(iface) {
+		return true
+	}
+	return v.CanAddr() && reflect.PtrTo(v.Type()).Implements(iface)
+}
+
+func genericNode(value interface{}) *reportNode {
+	switch value := value.(type) {
+	case nil:
+		return &reportNode{}
+	case map[string]interface{}:
+		node := &reportNode{kind: recordNode, fields: make(map[string]*reportNode)}
+		for key, field := range value {
+			node.keys = append(node.keys, key)
+			node.fields[key] = genericNode(field)
+		}
+		utils.SortStringsNaturally(node.keys)
+		return node
+	case []interface{}:
+		node := &reportNode{kind: listNode}
+		for _, item := range value {
+			node.items = append(node.items, genericNode(item))
+		}
+		return node
+	}
+	return &reportNode{scalar: fmt.Sprint(value)}
+}
+
+// addStructFields adds the fields of the struct to the record, named
+// and omitted as encoding/json would.
+func addStructFields(node *reportNode, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		name, opts := field.Name, ""
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			if i := strings.Index(tag, ","); i >= 0 {
+				name, opts = tag[:i], tag[i:]
+			} else {
+				name = tag
+			}
+		}
+		fv := v.Field(i)
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv, ft = fv.Elem(), ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addStructFields(node, fv)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+		child := reportNodeFor(fv)
+		if child == nil {
+			continue
+		}
+		if _, ok := node.fields[name]; !ok {
+			node.keys = append(node.keys, name)
+		}
+		node.fields[name] = child
+	}
+}
+
+// isEmptyValue reports whether the value is omitted by encoding/json
+// when its field is tagged with omitempty.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// isFlat reports whether the node can be written in a single cell.
+func (n *reportNode) isFlat() bool {
+	switch n.kind {
+	case scalarNode:
+		return true
+	case listNode:
+		for _, item := range n.items {
+			if item.kind != scalarNode {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// cell returns the text of a flat node.
+func (n *reportNode) cell() string {
+	if n.kind == listNode {
+		values := make([]string, len(n.items))
+		for i, item := range n.items {
+			values[i] = item.scalar
+		}
+		return strings.Join(values, ",")
+	}
+	return n.scalar
+}
+
+// reportTable is a table of the rows of a report.
+type reportTable struct {
+	name    string
+	columns []string
+	rows    []map[string]string
+}
+
+func (t *reportTable) addColumn(column string) {
+	for _, c := range t.columns {
+		if c == column {
+			return
+		}
+	}
+	t.columns = append(t.columns, column)
+}
+
+// values returns the cells of the row in the order of the columns.
+func (t *reportTable) values(row map[string]string) []string {
+	values := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		values[i] = row[column]
+	}
+	return values
+}
+
+// reportCell is a column and value of a row.
+type reportCell struct {
+	column string
+	value  string
+}
+
+// reportTables lays out values in tables.
+type reportTables struct {
+	tables []*reportTable
+	byName map[string]*reportTable
+}
+
+// reportTablesFor returns the tables laying out the value, leaving
+// out tables without columns.
+func reportTablesFor(value interface{}) []*reportTable {
+	r := &reportTables{byName: make(map[string]*reportTable)}
+	if node := reportNodeFor(reflect.ValueOf(value)); node != nil {
+		r.add("", nil, node)
+	}
+	var tables []*reportTable
+	for _, t := range r.tables {
+		if len(t.columns) > 0 {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+func (r *reportTables) table(name string) *reportTable {
+	t, ok := r.byName[name]
+	if !ok {
+		t = &reportTable{name: name}
+		r.byName[name] = t
+		r.tables = append(r.tables, t)
+	}
+	return t
+}
+
+// add adds the rows for the node to the named table. Each row starts
+// with the key cells, which identify the records holding the node.
+func (r *reportTables) add(name string, keys []reportCell, node *reportNode) {
+	switch node.kind {
+	case recordNode:
+		r.addRecord(name, keys, node)
+	case collectionNode:
+		column := "name"
+		if name != "" {
+			column = singular(name[strings.LastIndex(name, ".")+1:])
+		}
+		for _, key := range node.keys {
+			rowKeys := append(append([]reportCell(nil), keys...), reportCell{column, key})
+			r.add(name, rowKeys, node.fields[key])
+		}
+	case listNode:
+		for _, item := range node.items {
+			r.add(name, keys, item)
+		}
+	default:
+		r.addRecord(name, keys, &reportNode{
+			kind:   recordNode,
+			keys:   []string{"value"},
+			fields: map[string]*reportNode{"value": node},
+		})
+	}
+}
+
+// addRecord adds a row for the record to the named table. Nested
+// records are flattened into the row, while nested collections and
+// lists of records are added to tables of their own.
+func (r *reportTables) addRecord(name string, keys []reportCell, node *reportNode) {
+	t := r.table(name)
+	row := make(map[string]string)
+	for _, key := range keys {
+		t.addColumn(key.column)
+		row[key.column] = key.value
+	}
+	var flatten func(prefix string, node *reportNode)
+	flatten = func(prefix string, node *reportNode) {
+		for _, field := range node.keys {
+			child := node.fields[field]
+			column := prefix + field
+			switch {
+			case child.isFlat():
+				t.addColumn(column)
+				row[column] = child.cell()
+			case child.kind == recordNode:
+				flatten(column+".", child)
+			case child.kind == collectionNode && allFlat(child):
+				flatten(column+".", child)
+			default:
+				childName := field
+				if name != "" {
+					childName = name + "." + field
+				}
+				r.add(childName, keys, child)
+			}
+		}
+	}
+	flatten("", node)
+	t.rows = append(t.rows, row)
+}
+
+// allFlat reports whether all the values of the collection can be
+// written in a single cell.
+func allFlat(node *reportNode) bool {
+	for _, key := range node.keys {
+		if !node.fields[key].isFlat() {
+			return false
+		}
+	}
+	return true
+}
+
+// singular returns the column name for the keys of a collection with
+// the given name, e.g. "machine" for "machines".
+func singular(name string) string {
+	if strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && len(name) > 1 {
+		return name[:len(name)-1]
+	}
+	return name
+}
@@ -0,0 +1,162 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package output_test
+
+import (
+	"bytes"
+
+	"github.com/juju/cmd"
+	"github.com/juju/gnuflag"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/output"
+)
+
+type reportSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&reportSuite{})
+
+type testUnit struct {
+	Machine      string              `json:"machine"`
+	OpenPorts    []string            `json:"open-ports,omitempty"`
+	Subordinates map[string]testUnit `json:"subordinates,omitempty"`
+}
+
+type testStatus struct {
+	Current string `json:"current"`
+	Message string `json:"message,omitempty"`
+}
+
+type testApplication struct {
+	Charm     string              `json:"charm"`
+	Status    testStatus          `json:"application-status"`
+	Relations map[string][]string `json:"relations,omitempty"`
+	Units     map[string]testUnit `json:"units,omitempty"`
+	internal  string
+}
+
+type testModel struct {
+	Name string `json:"name"`
+}
+
+type testFormattedStatus struct {
+	Model        testModel                  `json:"model"`
+	Applications map[string]testApplication `json:"applications"`
+	Hidden       string                     `json:"-"`
+}
+
+func testReportValue() testFormattedStatus {
+	return testFormattedStatus{
+		Model: testModel{Name: "default"},
+		Applications: map[string]testApplication{
+			"mysql": {
+				Charm:     "cs:mysql-1",
+				Status:    testStatus{Current: "active", Message: "ready, clustered"},
+				Relations: map[string][]string{"cluster": {"mysql"}},
+				Units: map[string]testUnit{
+					"mysql/10": {Machine: "10"},
+					"mysql/2": {
+						Machine:   "2",
+						OpenPorts: []string{"3306/tcp", "4567/tcp"},
+						Subordinates: map[string]testUnit{
+							"logging/0": {},
+						},
+					},
+				},
+			},
+			"wordpress": {Charm: "cs:wordpress|2", internal: "x"},
+		},
+		Hidden: "hidden",
+	}
+}
+
+func (s *reportSuite) TestFormatCSV(c *gc.C) {
+	var buf bytes.Buffer
+	err := output.FormatCSV(&buf, testReportValue())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, `
+model.name
+default
+
+# applications
+application,charm,application-status.current,application-status.message,relations.cluster
+mysql,cs:mysql-1,active,"ready, clustered",mysql
+wordpress,cs:wordpress|2,,,
+
+# applications.units
+application,unit,machine,open-ports
+mysql,mysql/2,2,"3306/tcp,4567/tcp"
+mysql,mysql/10,10,
+
+# applications.units.subordinates
+application,unit,subordinate,machine
+mysql,mysql/2,logging/0,
+`[1:])
+}
+
+func (s *reportSuite) TestFormatCSVSingleTable(c *gc.C) {
+	var buf bytes.Buffer
+	err := output.FormatCSV(&buf, map[string]string{
+		"backup":  "Take a backup",
+		"restore": "Restore a backup",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, `
+name,value
+backup,Take a backup
+restore,Restore a backup
+`[1:])
+}
+
+func (s *reportSuite) TestFormatMarkdown(c *gc.C) {
+	var buf bytes.Buffer
+	err := output.FormatMarkdown(&buf, map[string]testApplication{
+		"wordpress": {Charm: "cs:wordpress|2", Status: testStatus{Current: "active"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, `
+| name | charm | application-status.current |
+| --- | --- | --- |
+| wordpress | cs:wordpress\|2 | active |
+`[1:])
+}
+
+func (s *reportSuite) TestFormatTemplate(c *gc.C) {
+	var buf bytes.Buffer
+	err := output.FormatTemplate(&buf,
+		`{{.model.name}}:{{range $name, $app := .applications}} {{$name}}={{$app.charm}}{{end}}`,
+		testReportValue(),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, "default: mysql=cs:mysql-1 wordpress=cs:wordpress|2")
+}
+
+func (s *reportSuite) TestFormatTemplateErrors(c *gc.C) {
+	var buf bytes.Buffer
+	err := output.FormatTemplate(&buf, "", testReportValue())
+	c.Assert(err, gc.ErrorMatches, "no template specified, use --template with --format=template")
+	err = output.FormatTemplate(&buf, "{{.model", testReportValue())
+	c.Assert(err, gc.ErrorMatches, "invalid template: .*")
+}
+
+func (s *reportSuite) TestAddReportFormatters(c *gc.C) {
+	f := gnuflag.NewFlagSet("test", gnuflag.ContinueOnError)
+	formatters := output.AddReportFormatters(f, map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+	})
+	c.Assert(formatters, gc.HasLen, 4)
+	c.Assert(formatters["csv"], gc.NotNil)
+	c.Assert(formatters["markdown"], gc.NotNil)
+
+	err := f.Parse(false, []string{"--template", "{{.model.name}}"})
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = formatters["template"](&buf, testReportValue())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, "default")
+}
@@ -6,6 +6,7 @@ package container
 import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 )
@@ -14,6 +15,7 @@ const (
 	ConfigModelUUID        = "model-uuid"
 	ConfigLogDir           = "log-dir"
 	ConfigAvailabilityZone = "availability-zone"
+	ConfigNICModel         = "nic-model"
 )
 
 // ManagerConfig contains the initialization parameters for the ContainerManager.
@@ -50,6 +52,91 @@ type Manager interface {
 	Namespace() instance.Namespace
 }
 
+// LXDProfileManager is implemented by container managers that can
+// maintain the charm LXD profiles of their containers.
+type LXDProfileManager interface {
+	// WriteLXDProfile creates the named LXD profile, or replaces the
+	// content of an existing profile with the same name.
+	WriteLXDProfile(name string, profile *lxdprofile.Profile) error
+
+	// ReplaceLXDProfiles removes the old profiles from the container
+	// identified by instance id, and applies the new ones.
+	ReplaceLXDProfiles(id instance.Id, oldProfiles, newProfiles []string) error
+
+	// DeleteLXDProfile deletes the named LXD profile, which must not
+	// be in use by any container.
+	DeleteLXDProfile(name string) error
+}
+
+// Migrator is implemented by container managers that can move their
+// containers to, and receive containers from, another host.
+type Migrator interface {
+	// MigrationSource prepares the container identified by instance id
+	// to be pulled by another host. Unless live is true, the container
+	// is stopped first.
+	MigrationSource(id instance.Id, live bool) (*MigrationSource, error)
+
+	// MigrateContainer pulls the container described by source from
+	// the host holding it, starting it afterwards if start is true.
+	MigrateContainer(id instance.Id, source MigrationSource, start bool) error
+
+	// StartContainer starts the container identified by instance id,
+	// such as after a failed move.
+	StartContainer(id instance.Id) error
+}
+
+// MigrationSource holds what a host needs to pull a container from
+// the host holding it.
+type MigrationSource struct {
+	// Addresses holds the host:port addresses of the source server.
+	Addresses []string
+
+	// Operation is the path of the source server's migration
+	// operation, and Secrets holds the secrets for its websockets.
+	Operation string
+	Secrets   map[string]string
+
+	// Certificate is the source server's certificate.
+	Certificate string
+
+	// Architecture, Config, Devices and Profiles describe the
+	// container being moved.
+	Architecture string
+	Config       map[string]string
+	Devices      map[string]map[string]string
+	Profiles     []string
+}
+
+// VolumeAttacher is implemented by container managers that can hotplug
+// volumes into, and out of, running containers.
+type VolumeAttacher interface {
+	// CreateVolume creates the volume described by params for the
+	// container identified by instance id, unless it already exists.
+	CreateVolume(id instance.Id, params VolumeParams) error
+
+	// AttachVolume attaches the named volume to the container
+	// identified by instance id, unless it is already attached. It
+	// returns the name of the volume's device in the container.
+	AttachVolume(id instance.Id, name string) (string, error)
+
+	// DetachVolume detaches the named volume from the container
+	// identified by instance id, if it is attached.
+	DetachVolume(id instance.Id, name string) error
+
+	// RemoveVolume removes the named volume of the container
+	// identified by instance id. The volume must be detached first.
+	RemoveVolume(id instance.Id, name string) error
+}
+
+// VolumeParams describes a volume to be hotplugged into a container.
+type VolumeParams struct {
+	// Name identifies the volume amongst those of the container.
+	Name string
+
+	// Size is the size of the volume in MiB.
+	Size uint64
+}
+
 // Initialiser is responsible for performing the steps required to initialise
 // a host machine so it can run containers.
 type Initialiser interface {
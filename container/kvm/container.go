@@ -92,8 +92,10 @@ func (c *kvmContainer) Start(params StartParams) error {
 		NetworkBridge:     bridge,
 		Memory:            params.Memory,
 		CpuCores:          params.CpuCores,
+		CpuPower:          params.CpuPower,
 		RootDisk:          params.RootDisk,
 		Interfaces:        interfaces,
+		InterfaceModel:    params.NICModel,
 	}); err != nil {
 		return err
 	}
@@ -17,7 +17,9 @@ type StartParams struct {
 	Network           *container.NetworkConfig
 	Memory            uint64 // MB
 	CpuCores          uint64
+	CpuPower          uint64 // 100 is one core's worth
 	RootDisk          uint64 // GB
+	NICModel          string
 	ImageDownloadURL  string
 	StatusCallback    func(status status.Status, info string, data map[string]interface{}) error
 }
@@ -103,8 +103,15 @@ func NewContainerManager(conf container.ManagerConfig) (container.Manager, error
 		logger.Infof("Availability zone will be empty for this container manager")
 	}
 
+	nicModel := conf.PopValue(container.ConfigNICModel)
+
 	conf.WarnAboutUnused()
-	return &containerManager{namespace: namespace, logdir: logDir, availabilityZone: availabilityZone}, nil
+	return &containerManager{
+		namespace:        namespace,
+		logdir:           logDir,
+		availabilityZone: availabilityZone,
+		nicModel:         nicModel,
+	}, nil
 }
 
 // containerManager handles all of the business logic at the juju specific
@@ -114,9 +121,13 @@ type containerManager struct {
 	namespace        instance.Namespace
 	logdir           string
 	availabilityZone string
+	nicModel         string
 }
 
-var _ container.Manager = (*containerManager)(nil)
+var (
+	_ container.Manager        = (*containerManager)(nil)
+	_ container.VolumeAttacher = (*containerManager)(nil)
+)
 
 // Namespace implements container.Manager.
 func (manager *containerManager) Namespace() instance.Namespace {
@@ -176,6 +187,7 @@ func (manager *containerManager) CreateContainer(
 	startParams.Network = networkConfig
 	startParams.UserDataFile = userDataFilename
 	startParams.NetworkConfigData = containerinit.CloudInitNetworkConfigDisabled
+	startParams.NICModel = manager.nicModel
 	startParams.StatusCallback = callback
 
 	// If the Simplestream requested is anything but released, update
@@ -184,10 +196,13 @@ func (manager *containerManager) CreateContainer(
 		startParams.ImageDownloadURL = imagemetadata.UbuntuCloudImagesURL + "/" + instanceConfig.ImageStream
 	}
 
+	hardwareSpec := fmt.Sprintf("arch=%s mem=%vM root-disk=%vG cores=%v",
+		startParams.Arch, startParams.Memory, startParams.RootDisk, startParams.CpuCores)
+	if startParams.CpuPower != 0 {
+		hardwareSpec += fmt.Sprintf(" cpu-power=%v", startParams.CpuPower)
+	}
 	var hardware instance.HardwareCharacteristics
-	hardware, err = instance.ParseHardware(
-		fmt.Sprintf("arch=%s mem=%vM root-disk=%vG cores=%v",
-			startParams.Arch, startParams.Memory, startParams.RootDisk, startParams.CpuCores))
+	hardware, err = instance.ParseHardware(hardwareSpec)
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "failed to parse hardware")
 	}
@@ -226,6 +241,27 @@ func (manager *containerManager) DestroyContainer(id instance.Id) error {
 	return container.RemoveDirectory(name)
 }
 
+// CreateVolume implements container.VolumeAttacher.
+func (manager *containerManager) CreateVolume(id instance.Id, params container.VolumeParams) error {
+	return errors.Trace(CreateVolume(&kvmContainer{name: string(id)}, params))
+}
+
+// AttachVolume implements container.VolumeAttacher.
+func (manager *containerManager) AttachVolume(id instance.Id, name string) (string, error) {
+	device, err := AttachVolume(&kvmContainer{name: string(id)}, name)
+	return device, errors.Trace(err)
+}
+
+// DetachVolume implements container.VolumeAttacher.
+func (manager *containerManager) DetachVolume(id instance.Id, name string) error {
+	return errors.Trace(DetachVolume(&kvmContainer{name: string(id)}, name))
+}
+
+// RemoveVolume implements container.VolumeAttacher.
+func (manager *containerManager) RemoveVolume(id instance.Id, name string) error {
+	return errors.Trace(RemoveVolume(&kvmContainer{name: string(id)}, name))
+}
+
 func (manager *containerManager) ListContainers() (result []instance.Instance, err error) {
 	containers, err := KvmObjectFactory.List()
 	if err != nil {
@@ -247,9 +283,9 @@ func (manager *containerManager) ListContainers() (result []instance.Instance, e
 }
 
 // ParseConstraintsToStartParams takes a constrants object and returns a bare
-// StartParams object that has Memory, Cpu, and Disk populated.  If there are
-// no defined values in the constraints for those fields, default values are
-// used.  Other constrains cause a warning to be emitted.
+// StartParams object that has Memory, Cpu, CpuPower and Disk populated.  If
+// there are no defined values in the constraints for those fields, default
+// values are used.  Other constrains cause a warning to be emitted.
 func ParseConstraintsToStartParams(cons constraints.Value) StartParams {
 	params := StartParams{
 		Memory:   DefaultMemory,
@@ -288,7 +324,7 @@ func ParseConstraintsToStartParams(cons constraints.Value) StartParams {
 		logger.Infof("container constraint of %q being ignored as not supported", *cons.Container)
 	}
 	if cons.CpuPower != nil {
-		logger.Infof("cpu-power constraint of %v being ignored as not supported", *cons.CpuPower)
+		params.CpuPower = *cons.CpuPower
 	}
 	if cons.Tags != nil {
 		logger.Infof("tags constraint of %q being ignored as not supported", strings.Join(*cons.Tags, ","))
@@ -140,6 +140,18 @@ func (s *KVMSuite) TestCreateContainerUtilizesDailySimpleStream(c *gc.C) {
 	c.Assert(kvm.TestStartParams.ImageDownloadURL, gc.Equals, "http://cloud-images.ubuntu.com/daily")
 }
 
+func (s *KVMSuite) TestCreateContainerUtilizesNICModel(c *gc.C) {
+	manager, err := kvm.NewContainerManager(container.ManagerConfig{
+		container.ConfigModelUUID: coretesting.ModelTag.Id(),
+		container.ConfigNICModel:  "e1000",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	containertesting.CreateContainer(c, manager, "1/kvm/0")
+
+	c.Assert(kvm.TestStartParams.NICModel, gc.Equals, "e1000")
+	c.Assert(c.GetTestLog(), gc.Not(jc.Contains), "unused config option")
+}
+
 func (s *KVMSuite) TestStartContainerUtilizesSimpleStream(c *gc.C) {
 
 	startParams := kvm.StartParams{
@@ -244,11 +256,9 @@ func (s *ConstraintsSuite) TestDefaults(c *gc.C) {
 		expected: kvm.StartParams{
 			Memory:   kvm.DefaultMemory,
 			CpuCores: kvm.DefaultCpu,
+			CpuPower: 100,
 			RootDisk: kvm.DefaultDisk,
 		},
-		infoLog: []string{
-			`cpu-power constraint of 100 being ignored as not supported`,
-		},
 	}, {
 		cons: "tags=foo,bar",
 		expected: kvm.StartParams{
@@ -264,12 +274,12 @@ func (s *ConstraintsSuite) TestDefaults(c *gc.C) {
 		expected: kvm.StartParams{
 			Memory:   4 * 1024,
 			CpuCores: 4,
+			CpuPower: 100,
 			RootDisk: 20,
 		},
 		infoLog: []string{
 			`arch constraint of "armhf" being ignored as not supported`,
 			`container constraint of "lxd" being ignored as not supported`,
-			`cpu-power constraint of 100 being ignored as not supported`,
 			`tags constraint of "foo,bar" being ignored as not supported`,
 		},
 	}} {
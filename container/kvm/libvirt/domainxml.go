@@ -39,6 +39,9 @@ type domainParams interface {
 	Arch() string
 	// CPUs returns the number of CPUs to use.
 	CPUs() uint64
+	// CPUShares returns the relative share of host CPU time the domain
+	// receives. Zero means the libvirt default.
+	CPUShares() uint64
 	// DiskInfo returns the disk information for the domain.
 	DiskInfo() []DiskInfo
 	// Host returns the host name.
@@ -48,6 +51,9 @@ type domainParams interface {
 	Loader() string
 	// NetworkInfo contains the network interfaces to create in the domain.
 	NetworkInfo() []InterfaceInfo
+	// NICModel returns the device model of the network interfaces, e.g.
+	// virtio or e1000.
+	NICModel() string
 	// RAM returns the amount of RAM to use.
 	RAM() uint64
 	// ValidateDomainParams returns nil if the domainParams are valid.
@@ -70,6 +76,7 @@ func NewDomain(p domainParams) (Domain, error) {
 		OS:            generateOSElement(p),
 		Features:      generateFeaturesElement(p),
 		CPU:           generateCPU(p),
+		CPUTune:       generateCPUTune(p),
 		Disk:          []Disk{},
 		Interface:     []Interface{},
 		Serial: Serial{
@@ -125,7 +132,7 @@ func NewDomain(p domainParams) (Domain, error) {
 		d.Interface = append(d.Interface, Interface{
 			Type:   "bridge",
 			MAC:    InterfaceMAC{Address: iface.MACAddress()},
-			Model:  Model{Type: "virtio"},
+			Model:  Model{Type: p.NICModel()},
 			Source: InterfaceSource{Bridge: iface.ParentInterfaceName()},
 			Guest:  InterfaceGuest{Dev: iface.InterfaceName()},
 		})
@@ -179,6 +186,15 @@ func generateCPU(p domainParams) *CPU {
 	return nil
 }
 
+// generateCPUTune returns the CPU tuning element, or nil if the domain
+// should receive the libvirt default share of host CPU time.
+func generateCPUTune(p domainParams) *CPUTune {
+	if p.CPUShares() == 0 {
+		return nil
+	}
+	return &CPUTune{Shares: p.CPUShares()}
+}
+
 // deviceID generates a device id from and int. The limit of 26 is arbitrary,
 // but it seems unlikely we'll need more than a couple for our use case.
 func deviceID(i int) (string, error) {
@@ -196,12 +212,14 @@ type Domain struct {
 	XMLName       xml.Name    `xml:"domain"`
 	Type          string      `xml:"type,attr"`
 	Name          string      `xml:"name"`
+	Description   string      `xml:"description,omitempty"`
 	VCPU          uint64      `xml:"vcpu"`
 	CurrentMemory Memory      `xml:"currentMemory"`
 	Memory        Memory      `xml:"memory"`
 	OS            OS          `xml:"os"`
 	Features      *Features   `xml:"features,omitempty"`
 	CPU           *CPU        `xml:"cpu,omitempty"`
+	CPUTune       *CPUTune    `xml:"cputune,omitempty"`
 	Disk          []Disk      `xml:"devices>disk"`
 	Interface     []Interface `xml:"devices>interface"`
 	Serial        Serial      `xml:"devices>serial,omitempty"`
@@ -272,6 +290,13 @@ type CPU struct {
 	Model Model  `xml:"model,omitempty"`
 }
 
+// CPUTune holds the CPU tunables of the domain. We only set the shares, which
+// weigh the domain's host CPU time against other domains on the host.
+// See: https://libvirt.org/formatdomain.html#elementsCPUTuning
+type CPUTune struct {
+	Shares uint64 `xml:"shares"`
+}
+
 // Address is static. We generate a default value for it.
 // See: Controller, Video
 type Address struct {
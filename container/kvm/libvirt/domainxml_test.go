@@ -120,7 +120,7 @@ func (domainXMLSuite) TestNewDomain(c *gc.C) {
 			dummyDisk{driver: "qcow2", source: "/some/path"},
 			dummyDisk{driver: "raw", source: "/another/path"},
 		}
-		params := dummyParams{ifaceInfo: ifaces, diskInfo: disks, memory: 1024, cpuCores: 2, hostname: "juju-someid", arch: test.arch}
+		params := dummyParams{ifaceInfo: ifaces, diskInfo: disks, memory: 1024, cpuCores: 2, hostname: "juju-someid", arch: test.arch, nicModel: "virtio"}
 
 		if test.arch == "arm64" {
 			params.loader = "/shared/readonly.fd"
@@ -134,6 +134,89 @@ func (domainXMLSuite) TestNewDomain(c *gc.C) {
 	}
 }
 
+var tunedDomainStr = `
+<domain type="kvm">
+    <name>juju-someid</name>
+    <vcpu>4</vcpu>
+    <currentMemory unit="MiB">2048</currentMemory>
+    <memory unit="MiB">2048</memory>
+    <os>
+        <type>hvm</type>
+    </os>
+    <cputune>
+        <shares>2048</shares>
+    </cputune>
+    <devices>
+        <disk device="disk" type="file">
+            <driver type="qcow2" name="qemu"></driver>
+            <source file="/some/path"></source>
+            <target dev="vda"></target>
+        </disk>
+        <disk device="disk" type="file">
+            <driver type="raw" name="qemu"></driver>
+            <source file="/another/path"></source>
+            <target dev="vdb"></target>
+        </disk>
+        <disk device="disk" type="file">
+            <driver type="qcow2" name="qemu"></driver>
+            <source file="/data/path"></source>
+            <target dev="vdc"></target>
+        </disk>
+        <interface type="bridge">
+            <mac address="00:00:00:00:00:00"></mac>
+            <model type="e1000"></model>
+            <source bridge="parent-dev"></source>
+            <guest dev="device-name"></guest>
+        </interface>
+        <serial type="pty">
+            <source path="/dev/pts/2"></source>
+            <target port="0"></target>
+        </serial>
+        <console type="pty" tty="/dev/pts/2">
+            <source path="/dev/pts/2"></source>
+            <target port="0"></target>
+        </console>
+    </devices>
+</domain>`[1:]
+
+func (domainXMLSuite) TestNewDomainTuned(c *gc.C) {
+	params := dummyParams{
+		arch:      "amd64",
+		cpuCores:  4,
+		cpuShares: 2048,
+		hostname:  "juju-someid",
+		memory:    2048,
+		nicModel:  "e1000",
+		diskInfo: []DiskInfo{
+			dummyDisk{driver: "qcow2", source: "/some/path"},
+			dummyDisk{driver: "raw", source: "/another/path"},
+			dummyDisk{driver: "qcow2", source: "/data/path"},
+		},
+		ifaceInfo: []InterfaceInfo{
+			dummyInterface{
+				mac:    "00:00:00:00:00:00",
+				parent: "parent-dev",
+				name:   "device-name"}},
+	}
+	d, err := NewDomain(params)
+	c.Assert(err, jc.ErrorIsNil)
+	ml, err := xml.MarshalIndent(&d, "", "    ")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(ml), gc.Equals, tunedDomainStr)
+}
+
+func (domainXMLSuite) TestDomainDescription(c *gc.C) {
+	d := Domain{Name: "juju-someid", Description: `{"juju-is-controller":"true"}`}
+	ml, err := xml.Marshal(&d)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(ml), jc.Contains, `<name>juju-someid</name><description>{&#34;juju-is-controller&#34;:&#34;true&#34;}</description>`)
+
+	var got Domain
+	err = xml.Unmarshal(ml, &got)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got.Description, gc.Equals, d.Description)
+}
+
 func (domainXMLSuite) TestNewDomainError(c *gc.C) {
 	d, err := NewDomain(dummyParams{err: errors.Errorf("boom")})
 	c.Check(d, jc.DeepEquals, Domain{})
@@ -144,21 +227,25 @@ type dummyParams struct {
 	err       error
 	arch      string
 	cpuCores  uint64
+	cpuShares uint64
 	diskInfo  []DiskInfo
 	hostname  string
 	ifaceInfo []InterfaceInfo
 	loader    string
 	memory    uint64
+	nicModel  string
 	nvram     string
 }
 
 func (p dummyParams) Arch() string                 { return p.arch }
 func (p dummyParams) CPUs() uint64                 { return p.cpuCores }
+func (p dummyParams) CPUShares() uint64            { return p.cpuShares }
 func (p dummyParams) DiskInfo() []DiskInfo         { return p.diskInfo }
 func (p dummyParams) Host() string                 { return p.hostname }
 func (p dummyParams) Loader() string               { return p.loader }
 func (p dummyParams) NVRAM() string                { return p.nvram }
 func (p dummyParams) NetworkInfo() []InterfaceInfo { return p.ifaceInfo }
+func (p dummyParams) NICModel() string             { return p.nicModel }
 func (p dummyParams) RAM() uint64                  { return p.memory }
 func (p dummyParams) ValidateDomainParams() error  { return p.err }
 
@@ -0,0 +1,160 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package kvm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
+)
+
+// NewVolumeSource returns a storage.VolumeSource that hotplugs volumes
+// into the KVM containers of the host machine, using the given
+// VolumeAttacher. It is the volume source of the "kvm" storage provider
+// on the host machine.
+func NewVolumeSource(attacher container.VolumeAttacher) storage.VolumeSource {
+	return &kvmVolumeSource{attacher}
+}
+
+// kvmVolumeSource creates volumes in the guest pool of the host machine,
+// and attaches them to KVM containers. The volume ID records both the
+// container's instance ID and the volume's name amongst those of the
+// container, so that the volume can be removed without reference to the
+// container.
+type kvmVolumeSource struct {
+	attacher container.VolumeAttacher
+}
+
+var _ storage.VolumeSource = (*kvmVolumeSource)(nil)
+
+// CreateVolumes is defined on the VolumeSource interface.
+func (s *kvmVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	results := make([]storage.CreateVolumesResult, len(args))
+	for i, arg := range args {
+		volume, err := s.createVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotate(err, "creating volume")
+			continue
+		}
+		results[i].Volume = volume
+	}
+	return results, nil
+}
+
+func (s *kvmVolumeSource) createVolume(arg storage.VolumeParams) (*storage.Volume, error) {
+	if arg.Attachment == nil || arg.Attachment.InstanceId == "" {
+		return nil, errors.NotValidf("volume %q without container", arg.Tag.Id())
+	}
+	instanceId := arg.Attachment.InstanceId
+	name := arg.Tag.String()
+	if err := s.attacher.CreateVolume(instanceId, container.VolumeParams{
+		Name: name,
+		Size: arg.Size,
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storage.Volume{
+		arg.Tag,
+		storage.VolumeInfo{
+			VolumeId: makeVolumeId(instanceId, name),
+			Size:     arg.Size,
+		},
+	}, nil
+}
+
+// ListVolumes is defined on the VolumeSource interface.
+func (s *kvmVolumeSource) ListVolumes() ([]string, error) {
+	return nil, errors.NotImplementedf("ListVolumes")
+}
+
+// DescribeVolumes is defined on the VolumeSource interface.
+func (s *kvmVolumeSource) DescribeVolumes(volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	return nil, errors.NotImplementedf("DescribeVolumes")
+}
+
+// DestroyVolumes is defined on the VolumeSource interface.
+func (s *kvmVolumeSource) DestroyVolumes(volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		instanceId, name, err := parseVolumeId(volumeId)
+		if err == nil {
+			err = s.attacher.RemoveVolume(instanceId, name)
+		}
+		if err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	return results, nil
+}
+
+// ReleaseVolumes is defined on the VolumeSource interface.
+func (s *kvmVolumeSource) ReleaseVolumes(volumeIds []string) ([]error, error) {
+	return make([]error, len(volumeIds)), nil
+}
+
+// ValidateVolumeParams is defined on the VolumeSource interface.
+func (s *kvmVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	return nil
+}
+
+// AttachVolumes is defined on the VolumeSource interface.
+func (s *kvmVolumeSource) AttachVolumes(args []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(args))
+	for i, arg := range args {
+		attachment, err := s.attachVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "attaching volume %v", arg.Volume.Id())
+			continue
+		}
+		results[i].VolumeAttachment = attachment
+	}
+	return results, nil
+}
+
+func (s *kvmVolumeSource) attachVolume(arg storage.VolumeAttachmentParams) (*storage.VolumeAttachment, error) {
+	if arg.ReadOnly {
+		return nil, errors.NotSupportedf("read-only kvm volumes")
+	}
+	name := arg.Volume.String()
+	if _, err := s.attacher.AttachVolume(arg.InstanceId, name); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The guest may not name the device as the host asked, but it
+	// links the device to its serial.
+	return &storage.VolumeAttachment{
+		arg.Volume,
+		arg.Machine,
+		storage.VolumeAttachmentInfo{
+			DeviceLink: "/dev/disk/by-id/virtio-" + VolumeSerial(name),
+		},
+	}, nil
+}
+
+// DetachVolumes is defined on the VolumeSource interface.
+func (s *kvmVolumeSource) DetachVolumes(args []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		if err := s.attacher.DetachVolume(arg.InstanceId, arg.Volume.String()); err != nil {
+			results[i] = errors.Annotatef(err, "detaching volume %s", arg.Volume.Id())
+		}
+	}
+	return results, nil
+}
+
+func makeVolumeId(instanceId instance.Id, name string) string {
+	return fmt.Sprintf("%s/%s", instanceId, name)
+}
+
+func parseVolumeId(volumeId string) (instance.Id, string, error) {
+	parts := strings.SplitN(volumeId, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.NotValidf("kvm volume ID %q", volumeId)
+	}
+	return instance.Id(parts[0]), parts[1], nil
+}
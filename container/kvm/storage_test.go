@@ -0,0 +1,144 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package kvm_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/kvm"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type storageSuite struct {
+	coretesting.BaseSuite
+	attacher *fakeVolumeAttacher
+	source   storage.VolumeSource
+}
+
+var _ = gc.Suite(&storageSuite{})
+
+func (s *storageSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.attacher = &fakeVolumeAttacher{}
+	s.source = kvm.NewVolumeSource(s.attacher)
+}
+
+func (s *storageSuite) attachmentParams() storage.VolumeAttachmentParams {
+	return storage.VolumeAttachmentParams{
+		AttachmentParams: storage.AttachmentParams{
+			Provider:   "kvm",
+			Machine:    names.NewMachineTag("0/kvm/0"),
+			InstanceId: "juju-0-kvm-0",
+		},
+		Volume:   names.NewVolumeTag("0/kvm/0/1"),
+		VolumeId: "juju-0-kvm-0/volume-0-kvm-0-1",
+	}
+}
+
+func (s *storageSuite) TestCreateVolumes(c *gc.C) {
+	attachment := s.attachmentParams()
+	results, err := s.source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0/kvm/0/1"),
+		Size:       1024,
+		Provider:   "kvm",
+		Attachment: &attachment,
+	}, {
+		Tag:      names.NewVolumeTag("0/kvm/0/2"),
+		Size:     1024,
+		Provider: "kvm",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume, jc.DeepEquals, &storage.Volume{
+		names.NewVolumeTag("0/kvm/0/1"),
+		storage.VolumeInfo{
+			VolumeId: "juju-0-kvm-0/volume-0-kvm-0-1",
+			Size:     1024,
+		},
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `creating volume: volume "0/kvm/0/2" without container not valid`)
+	s.attacher.CheckCalls(c, []testing.StubCall{
+		{"CreateVolume", []interface{}{instance.Id("juju-0-kvm-0"), container.VolumeParams{
+			Name: "volume-0-kvm-0-1",
+			Size: 1024,
+		}}},
+	})
+}
+
+func (s *storageSuite) TestAttachVolumes(c *gc.C) {
+	results, err := s.source.AttachVolumes([]storage.VolumeAttachmentParams{s.attachmentParams()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeAttachment, jc.DeepEquals, &storage.VolumeAttachment{
+		names.NewVolumeTag("0/kvm/0/1"),
+		names.NewMachineTag("0/kvm/0"),
+		storage.VolumeAttachmentInfo{
+			DeviceLink: "/dev/disk/by-id/virtio-volume-0-kvm-0-1",
+		},
+	})
+	s.attacher.CheckCalls(c, []testing.StubCall{
+		{"AttachVolume", []interface{}{instance.Id("juju-0-kvm-0"), "volume-0-kvm-0-1"}},
+	})
+}
+
+func (s *storageSuite) TestAttachVolumesError(c *gc.C) {
+	s.attacher.SetErrors(errors.New("boom"))
+	results, err := s.source.AttachVolumes([]storage.VolumeAttachmentParams{s.attachmentParams()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, "attaching volume 0/kvm/0/1: boom")
+}
+
+func (s *storageSuite) TestDetachVolumes(c *gc.C) {
+	results, err := s.source.DetachVolumes([]storage.VolumeAttachmentParams{s.attachmentParams()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []error{nil})
+	s.attacher.CheckCalls(c, []testing.StubCall{
+		{"DetachVolume", []interface{}{instance.Id("juju-0-kvm-0"), "volume-0-kvm-0-1"}},
+	})
+}
+
+func (s *storageSuite) TestDestroyVolumes(c *gc.C) {
+	results, err := s.source.DestroyVolumes([]string{"juju-0-kvm-0/volume-0-kvm-0-1", "volume-0-kvm-0-2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.ErrorIsNil)
+	c.Assert(results[1], gc.ErrorMatches, `destroying "volume-0-kvm-0-2": kvm volume ID "volume-0-kvm-0-2" not valid`)
+	s.attacher.CheckCalls(c, []testing.StubCall{
+		{"RemoveVolume", []interface{}{instance.Id("juju-0-kvm-0"), "volume-0-kvm-0-1"}},
+	})
+}
+
+type fakeVolumeAttacher struct {
+	testing.Stub
+}
+
+func (a *fakeVolumeAttacher) CreateVolume(id instance.Id, params container.VolumeParams) error {
+	a.MethodCall(a, "CreateVolume", id, params)
+	return a.NextErr()
+}
+
+func (a *fakeVolumeAttacher) AttachVolume(id instance.Id, name string) (string, error) {
+	a.MethodCall(a, "AttachVolume", id, name)
+	return "vdc", a.NextErr()
+}
+
+func (a *fakeVolumeAttacher) DetachVolume(id instance.Id, name string) error {
+	a.MethodCall(a, "DetachVolume", id, name)
+	return a.NextErr()
+}
+
+func (a *fakeVolumeAttacher) RemoveVolume(id instance.Id, name string) error {
+	a.MethodCall(a, "RemoveVolume", id, name)
+	return a.NextErr()
+}
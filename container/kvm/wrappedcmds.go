@@ -30,6 +30,7 @@ import (
 	"github.com/juju/utils/series"
 	"gopkg.in/yaml.v2"
 
+	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm/libvirt"
 	"github.com/juju/juju/juju/paths"
 )
@@ -66,8 +67,10 @@ type CreateMachineParams struct {
 	NetworkBridge     string
 	Memory            uint64
 	CpuCores          uint64
+	CpuPower          uint64
 	RootDisk          uint64
 	Interfaces        []libvirt.InterfaceInfo
+	InterfaceModel    string
 
 	disks    []libvirt.DiskInfo
 	findPath func(string) (string, error)
@@ -104,6 +107,13 @@ func (p CreateMachineParams) CPUs() uint64 {
 	return p.CpuCores
 }
 
+// CPUShares implements libvirt.domainParams. libvirt gives each domain 1024
+// shares per vcpu by default, so we scale cpu-power (where 100 is one core's
+// worth) to match.
+func (p CreateMachineParams) CPUShares() uint64 {
+	return p.CpuPower * 1024 / 100
+}
+
 // DiskInfo implements libvirt.domainParams.
 func (p CreateMachineParams) DiskInfo() []libvirt.DiskInfo {
 	return p.disks
@@ -122,11 +132,24 @@ func (p CreateMachineParams) NetworkInfo() []libvirt.InterfaceInfo {
 	return p.Interfaces
 }
 
+// NICModel implements libvirt.domainParams.
+func (p CreateMachineParams) NICModel() string {
+	if p.InterfaceModel == "" {
+		return "virtio"
+	}
+	return p.InterfaceModel
+}
+
 // ValidateDomainParams implements libvirt.domainParams.
 func (p CreateMachineParams) ValidateDomainParams() error {
 	if p.Hostname == "" {
 		return errors.Errorf("missing required hostname")
 	}
+	switch p.NICModel() {
+	case "virtio", "e1000", "rtl8139":
+	default:
+		return errors.NotValidf("network interface model %q", p.NICModel())
+	}
 	if len(p.disks) < 2 {
 		// We need at least the drive and the data source disk.
 		return errors.Errorf("got %d disks, need at least 2", len(p.disks))
@@ -266,6 +289,12 @@ func DestroyMachine(c *kvmContainer) error {
 	if err != nil {
 		logger.Errorf("failed to remove cloud-init data disk for %q: %s", c.Name(), err)
 	}
+	volumes, _ := filepath.Glob(filepath.Join(guestBase, fmt.Sprintf("%s-vol-*.qcow", c.Name())))
+	for _, volume := range volumes {
+		if err := os.Remove(volume); err != nil {
+			logger.Errorf("failed to remove volume %q for %q: %s", volume, c.Name(), err)
+		}
+	}
 
 	return nil
 }
@@ -280,6 +309,171 @@ func AutostartMachine(c *kvmContainer) error {
 	return errors.Annotatef(err, "failed to autostart domain %q", c.Name())
 }
 
+// CreateVolume creates a qcow2 volume in the guest pool for the virtual
+// machine represented by the kvmContainer, unless it already exists.
+func CreateVolume(c *kvmContainer, params container.VolumeParams) error {
+	if c.runCmd == nil {
+		c.runCmd = run
+	}
+	if params.Name == "" {
+		return errors.NotValidf("volume without name")
+	}
+	volumePath, err := volumePath(c, params.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := os.Stat(volumePath); err == nil {
+		// The volume was created by an earlier attempt.
+		return nil
+	} else if !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	out, err := c.runCmd(
+		"qemu-img",
+		"create",
+		"-f", "qcow2",
+		volumePath,
+		fmt.Sprintf("%dM", params.Size))
+	logger.Debugf("create volume image: %s", out)
+	return errors.Annotatef(err, "failed to create volume %q for %q", params.Name, c.Name())
+}
+
+// AttachVolume hotplugs the named volume into the running virtual machine
+// represented by the kvmContainer, as the first free virtio device. The
+// volume is also added to the persisted domain, so it survives a restart.
+// If the volume is already attached, AttachVolume does nothing. It returns
+// the name of the volume's device, e.g. vdc. As the guest may name the
+// device differently, the device is given the serial VolumeSerial(name).
+func AttachVolume(c *kvmContainer, name string) (string, error) {
+	if c.runCmd == nil {
+		c.runCmd = run
+	}
+	volumePath, err := volumePath(c, name)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	disks, err := attachedDisks(c)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for device, source := range disks {
+		if source == volumePath {
+			return device, nil
+		}
+	}
+	device, err := freeDevice(disks)
+	if err != nil {
+		return "", errors.Annotatef(err, "failed to attach volume %q to %q", name, c.Name())
+	}
+	_, err = c.runCmd(
+		"virsh", "attach-disk", c.Name(), volumePath, device,
+		"--driver", "qemu",
+		"--subdriver", "qcow2",
+		"--targetbus", "virtio",
+		"--serial", VolumeSerial(name),
+		"--live", "--persistent")
+	if err != nil {
+		return "", errors.Annotatef(err, "failed to attach volume %q to %q", name, c.Name())
+	}
+	return device, nil
+}
+
+// DetachVolume unplugs the named volume from the running virtual machine
+// represented by the kvmContainer, and from its persisted domain. If the
+// volume is not attached, DetachVolume does nothing. The volume's image is
+// left in place.
+func DetachVolume(c *kvmContainer, name string) error {
+	if c.runCmd == nil {
+		c.runCmd = run
+	}
+	volumePath, err := volumePath(c, name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	disks, err := attachedDisks(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for device, source := range disks {
+		if source != volumePath {
+			continue
+		}
+		_, err := c.runCmd("virsh", "detach-disk", c.Name(), device, "--live", "--persistent")
+		return errors.Annotatef(err, "failed to detach volume %q from %q", name, c.Name())
+	}
+	return nil
+}
+
+// RemoveVolume removes the image of the named volume of the virtual machine
+// represented by the kvmContainer. The volume must be detached first.
+func RemoveVolume(c *kvmContainer, name string) error {
+	volumePath, err := volumePath(c, name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.Remove(volumePath); err != nil && !os.IsNotExist(err) {
+		return errors.Annotatef(err, "failed to remove volume %q for %q", name, c.Name())
+	}
+	return nil
+}
+
+// VolumeSerial returns the serial of the device of the named volume. The
+// guest links /dev/disk/by-id/virtio-<serial> to the device. virtio limits
+// serials to 20 characters, so only the end of a longer name is used.
+func VolumeSerial(name string) string {
+	if len(name) > maxVolumeSerial {
+		return name[len(name)-maxVolumeSerial:]
+	}
+	return name
+}
+
+const maxVolumeSerial = 20
+
+// volumePath returns the path of the image of the named volume of the
+// virtual machine represented by the kvmContainer.
+func volumePath(c *kvmContainer, name string) (string, error) {
+	if c.pathfinder == nil {
+		c.pathfinder = paths.DataDir
+	}
+	guestBase, err := guestPath(c.pathfinder)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return filepath.Join(guestBase, fmt.Sprintf("%s-vol-%s.qcow", c.Name(), name)), nil
+}
+
+// attachedDisks returns a map of device name to source path of the disks
+// attached to the virtual machine represented by the kvmContainer.
+func attachedDisks(c *kvmContainer) (map[string]string, error) {
+	output, err := c.runCmd("virsh", "domblklist", c.Name())
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to list disks of %q", c.Name())
+	}
+	// The output has a header, followed by lines with the format:
+	//    "vda        /var/lib/juju/kvm/guests/name.qcow"
+	disks := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] == "Target" {
+			continue
+		}
+		disks[fields[0]] = fields[1]
+	}
+	return disks, nil
+}
+
+// freeDevice returns the first virtio device name not in use by the given
+// disks.
+func freeDevice(disks map[string]string) (string, error) {
+	for letter := 'a'; letter <= 'z'; letter++ {
+		device := fmt.Sprintf("vd%c", letter)
+		if _, ok := disks[device]; !ok {
+			return device, nil
+		}
+	}
+	return "", errors.New("no free virtio devices")
+}
+
 // ListMachines returns a map of machine name to state, where state is one of:
 // running, idle, paused, shutdown, shut off, crashed, dying, pmsuspended.
 func ListMachines(runCmd runFunc) (map[string]string, error) {
@@ -135,6 +135,37 @@ func (libvirtInternalSuite) TestWriteDomainXMLNoHostname(c *gc.C) {
 	c.Assert(got, gc.Matches, "")
 }
 
+func (libvirtInternalSuite) TestWriteDomainXMLInvalidNICModel(c *gc.C) {
+	d := c.MkDir()
+
+	p := CreateMachineParams{
+		Hostname:       "host00",
+		InterfaceModel: "ne2k_pci",
+		disks: []libvirt.DiskInfo{
+			diskInfo{
+				source: "/path-ds",
+				driver: "raw"},
+			diskInfo{
+				source: "/path",
+				driver: "qcow2"},
+		},
+	}
+
+	got, err := writeDomainXML(d, p)
+	c.Assert(err, gc.ErrorMatches, `network interface model "ne2k_pci" not valid`)
+	c.Assert(got, gc.Matches, "")
+}
+
+func (libvirtInternalSuite) TestCreateMachineParamsTuning(c *gc.C) {
+	p := CreateMachineParams{}
+	c.Assert(p.NICModel(), gc.Equals, "virtio")
+	c.Assert(p.CPUShares(), gc.Equals, uint64(0))
+
+	p = CreateMachineParams{InterfaceModel: "e1000", CpuPower: 250}
+	c.Assert(p.NICModel(), gc.Equals, "e1000")
+	c.Assert(p.CPUShares(), gc.Equals, uint64(2560))
+}
+
 func (libvirtInternalSuite) TestPoolInfoSuccess(c *gc.C) {
 	output := `
 Name:           juju-pool
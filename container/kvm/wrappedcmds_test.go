@@ -4,6 +4,7 @@
 package kvm_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	"github.com/pkg/errors"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/container"
 	. "github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/environs/imagedownloads"
 	"github.com/juju/juju/environs/simplestreams"
@@ -213,6 +215,157 @@ func (commandWrapperSuite) TestAutostartMachineFails(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `failed to autostart domain "aname": Boom`)
 }
 
+func (commandWrapperSuite) TestCreateVolume(c *gc.C) {
+	tmpDir := c.MkDir()
+	pathfinder := func(_ string) (string, error) {
+		return tmpDir, nil
+	}
+	stub := NewRunStub("success", nil)
+	machine := NewTestContainer("aname", stub.Run, pathfinder)
+	err := CreateVolume(machine, container.VolumeParams{Name: "data0", Size: 2048})
+	c.Assert(err, jc.ErrorIsNil)
+
+	volumePath := filepath.Join(tmpDir, "kvm", "guests", "aname-vol-data0.qcow")
+	c.Assert(stub.Calls(), jc.DeepEquals, []string{
+		"qemu-img create -f qcow2 " + volumePath + " 2048M",
+	})
+}
+
+func (commandWrapperSuite) TestCreateVolumeExists(c *gc.C) {
+	tmpDir := c.MkDir()
+	pathfinder := func(_ string) (string, error) {
+		return tmpDir, nil
+	}
+	err := os.MkdirAll(filepath.Join(tmpDir, "kvm", "guests"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	volumePath := filepath.Join(tmpDir, "kvm", "guests", "aname-vol-data0.qcow")
+	err = ioutil.WriteFile(volumePath, []byte("data"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stub := NewRunStub("success", nil)
+	machine := NewTestContainer("aname", stub.Run, pathfinder)
+	err = CreateVolume(machine, container.VolumeParams{Name: "data0", Size: 2048})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stub.Calls(), gc.HasLen, 0)
+}
+
+func (commandWrapperSuite) TestCreateVolumeFails(c *gc.C) {
+	pathfinder := func(_ string) (string, error) {
+		return c.MkDir(), nil
+	}
+	stub := NewRunStub("", errors.Errorf("Boom"))
+	machine := NewTestContainer("aname", stub.Run, pathfinder)
+	err := CreateVolume(machine, container.VolumeParams{Name: "data0", Size: 2048})
+	c.Assert(err, gc.ErrorMatches, `failed to create volume "data0" for "aname": Boom`)
+	c.Assert(stub.Calls(), gc.HasLen, 1)
+
+	err = CreateVolume(machine, container.VolumeParams{Size: 2048})
+	c.Assert(err, gc.ErrorMatches, "volume without name not valid")
+}
+
+func domblklistOutput(guestDir string, volumes ...string) string {
+	output := `
+ Target     Source
+------------------------------------------------
+ vda        ` + filepath.Join(guestDir, "aname.qcow") + `
+ vdb        ` + filepath.Join(guestDir, "aname-ds.iso") + `
+`
+	for i, volume := range volumes {
+		output += fmt.Sprintf(" vd%c        %s\n", 'c'+i, filepath.Join(guestDir, "aname-vol-"+volume+".qcow"))
+	}
+	return output
+}
+
+func (commandWrapperSuite) TestAttachVolume(c *gc.C) {
+	tmpDir := c.MkDir()
+	pathfinder := func(_ string) (string, error) {
+		return tmpDir, nil
+	}
+	guestDir := filepath.Join(tmpDir, "kvm", "guests")
+	stub := NewRunStub(domblklistOutput(guestDir, "data0"), nil)
+	machine := NewTestContainer("aname", stub.Run, pathfinder)
+	device, err := AttachVolume(machine, "data1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(device, gc.Equals, "vdd")
+
+	volumePath := filepath.Join(guestDir, "aname-vol-data1.qcow")
+	c.Assert(stub.Calls(), jc.DeepEquals, []string{
+		"virsh domblklist aname",
+		"virsh attach-disk aname " + volumePath + " vdd --driver qemu --subdriver qcow2 --targetbus virtio --serial data1 --live --persistent",
+	})
+}
+
+func (commandWrapperSuite) TestAttachVolumeAlreadyAttached(c *gc.C) {
+	tmpDir := c.MkDir()
+	pathfinder := func(_ string) (string, error) {
+		return tmpDir, nil
+	}
+	stub := NewRunStub(domblklistOutput(filepath.Join(tmpDir, "kvm", "guests"), "data0"), nil)
+	machine := NewTestContainer("aname", stub.Run, pathfinder)
+	device, err := AttachVolume(machine, "data0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(device, gc.Equals, "vdc")
+	c.Assert(stub.Calls(), jc.DeepEquals, []string{"virsh domblklist aname"})
+}
+
+func (commandWrapperSuite) TestAttachVolumeFails(c *gc.C) {
+	pathfinder := func(_ string) (string, error) {
+		return c.MkDir(), nil
+	}
+	stub := NewRunStub("", errors.Errorf("Boom"))
+	machine := NewTestContainer("aname", stub.Run, pathfinder)
+	_, err := AttachVolume(machine, "data0")
+	c.Assert(err, gc.ErrorMatches, `failed to list disks of "aname": Boom`)
+}
+
+func (commandWrapperSuite) TestVolumeSerial(c *gc.C) {
+	c.Assert(VolumeSerial("volume-0-kvm-0-1"), gc.Equals, "volume-0-kvm-0-1")
+	c.Assert(VolumeSerial("volume-10-kvm-12-1234"), gc.Equals, "olume-10-kvm-12-1234")
+}
+
+func (commandWrapperSuite) TestDetachVolume(c *gc.C) {
+	tmpDir := c.MkDir()
+	pathfinder := func(_ string) (string, error) {
+		return tmpDir, nil
+	}
+	stub := NewRunStub(domblklistOutput(filepath.Join(tmpDir, "kvm", "guests"), "data0"), nil)
+	machine := NewTestContainer("aname", stub.Run, pathfinder)
+	err := DetachVolume(machine, "data0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stub.Calls(), jc.DeepEquals, []string{
+		"virsh domblklist aname",
+		"virsh detach-disk aname vdc --live --persistent",
+	})
+
+	// Detaching a volume that is not attached does nothing.
+	stub = NewRunStub(domblklistOutput(filepath.Join(tmpDir, "kvm", "guests")), nil)
+	machine = NewTestContainer("aname", stub.Run, pathfinder)
+	err = DetachVolume(machine, "data0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stub.Calls(), jc.DeepEquals, []string{"virsh domblklist aname"})
+}
+
+func (commandWrapperSuite) TestRemoveVolume(c *gc.C) {
+	tmpDir := c.MkDir()
+	pathfinder := func(_ string) (string, error) {
+		return tmpDir, nil
+	}
+	err := os.MkdirAll(filepath.Join(tmpDir, "kvm", "guests"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	volumePath := filepath.Join(tmpDir, "kvm", "guests", "aname-vol-data0.qcow")
+	err = ioutil.WriteFile(volumePath, []byte("data"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machine := NewTestContainer("aname", nil, pathfinder)
+	err = RemoveVolume(machine, "data0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumePath, jc.DoesNotExist)
+
+	// Removing a volume that does not exist is not an error.
+	err = RemoveVolume(machine, "data0")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (commandWrapperSuite) TestListMachinesSuccess(c *gc.C) {
 	output := `
  Id    Name                           State
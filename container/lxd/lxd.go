@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
@@ -42,8 +43,13 @@ type containerManager struct {
 	availabilityZone string
 }
 
-// containerManager implements container.Manager.
-var _ container.Manager = (*containerManager)(nil)
+// containerManager implements container.Manager,
+// container.LXDProfileManager and container.Migrator.
+var (
+	_ container.Manager           = (*containerManager)(nil)
+	_ container.LXDProfileManager = (*containerManager)(nil)
+	_ container.Migrator          = (*containerManager)(nil)
+)
 
 func ConnectLocal() (*lxdclient.Client, error) {
 	cfg := lxdclient.Config{
@@ -175,6 +181,10 @@ func (manager *containerManager) CreateContainer(
 	} else {
 		logger.Infof("instance %q configured with %v network devices", name, nics)
 	}
+	if len(instanceConfig.CharmLXDProfiles) > 0 {
+		logger.Infof("instance %q configured with charm profiles %v", name, instanceConfig.CharmLXDProfiles)
+		profiles = append(profiles, instanceConfig.CharmLXDProfiles...)
+	}
 
 	spec := lxdclient.InstanceSpec{
 		Name:     name,
@@ -228,6 +238,88 @@ func (manager *containerManager) ListContainers() (result []instance.Instance, e
 	return
 }
 
+// WriteLXDProfile implements container.LXDProfileManager.
+func (manager *containerManager) WriteLXDProfile(name string, profile *lxdprofile.Profile) error {
+	if manager.client == nil {
+		var err error
+		manager.client, err = ConnectLocal()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	devices := make(lxdclient.Devices)
+	for deviceName, device := range profile.Devices {
+		devices[deviceName] = device
+	}
+	logger.Debugf("writing lxd profile %q", name)
+	return errors.Trace(manager.client.WriteProfile(name, profile.Description, profile.Config, devices))
+}
+
+// ReplaceLXDProfiles implements container.LXDProfileManager.
+func (manager *containerManager) ReplaceLXDProfiles(id instance.Id, oldProfiles, newProfiles []string) error {
+	if manager.client == nil {
+		var err error
+		manager.client, err = ConnectLocal()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	logger.Infof("replacing profiles %v with %v on instance %q", oldProfiles, newProfiles, id)
+	return errors.Trace(manager.client.ReplaceProfiles(string(id), oldProfiles, newProfiles))
+}
+
+// DeleteLXDProfile implements container.LXDProfileManager.
+func (manager *containerManager) DeleteLXDProfile(name string) error {
+	if manager.client == nil {
+		var err error
+		manager.client, err = ConnectLocal()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	logger.Debugf("deleting lxd profile %q", name)
+	return errors.Trace(manager.client.ProfileDelete(name))
+}
+
+// MigrationSource implements container.Migrator.
+func (manager *containerManager) MigrationSource(id instance.Id, live bool) (*container.MigrationSource, error) {
+	if manager.client == nil {
+		var err error
+		manager.client, err = ConnectLocal()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	logger.Infof("preparing instance %q to be moved (live: %v)", id, live)
+	source, err := manager.client.MigrationSource(string(id), live)
+	return source, errors.Trace(err)
+}
+
+// MigrateContainer implements container.Migrator.
+func (manager *containerManager) MigrateContainer(id instance.Id, source container.MigrationSource, start bool) error {
+	if manager.client == nil {
+		var err error
+		manager.client, err = ConnectLocal()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	logger.Infof("moving instance %q from %v", id, source.Addresses)
+	return errors.Trace(manager.client.MigrateInstance(string(id), source, start))
+}
+
+// StartContainer implements container.Migrator.
+func (manager *containerManager) StartContainer(id instance.Id) error {
+	if manager.client == nil {
+		var err error
+		manager.client, err = ConnectLocal()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(manager.client.StartInstance(string(id)))
+}
+
 func (manager *containerManager) IsInitialized() bool {
 	if manager.client != nil {
 		return true
@@ -0,0 +1,175 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package lxdprofile holds the LXD profiles that charms may declare
+// for the containers hosting their units.
+package lxdprofile
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/yaml.v2"
+)
+
+// Filename is the name of the file, at the root of a charm, that
+// holds the charm's LXD profile.
+const Filename = "lxd-profile.yaml"
+
+// allowedConfig holds the LXD container config keys that a charm
+// profile may set.
+var allowedConfig = map[string]bool{
+	"linux.kernel_modules": true,
+	"security.nesting":     true,
+}
+
+// allowedConfigPrefixes holds the LXD container config key prefixes
+// that a charm profile may set.
+var allowedConfigPrefixes = []string{
+	"environment.",
+}
+
+// allowedDevices holds the LXD device types that a charm profile
+// may pass through to a container. Privileged containers, and unix
+// block and character devices, which may name any host device, are
+// not allowed: they would give the charm's units root on the host.
+var allowedDevices = map[string]bool{
+	"gpu": true,
+	"usb": true,
+}
+
+// Profile is an LXD profile declared by a charm.
+type Profile struct {
+	Config      map[string]string            `yaml:"config,omitempty"`
+	Description string                       `yaml:"description,omitempty"`
+	Devices     map[string]map[string]string `yaml:"devices,omitempty"`
+}
+
+// Empty reports whether the profile neither sets any config nor
+// declares any devices.
+func (p *Profile) Empty() bool {
+	return p == nil || len(p.Config) == 0 && len(p.Devices) == 0
+}
+
+// Validate returns an error if the profile sets config keys or
+// declares device types outside the allow-list.
+func (p *Profile) Validate() error {
+	if p == nil {
+		return nil
+	}
+	var problems []string
+	for _, key := range sortedKeys(p.Config) {
+		if !configAllowed(key) {
+			problems = append(problems, fmt.Sprintf("config %q not allowed", key))
+		}
+	}
+	devices := make([]string, 0, len(p.Devices))
+	for name := range p.Devices {
+		devices = append(devices, name)
+	}
+	sort.Strings(devices)
+	for _, name := range devices {
+		deviceType := p.Devices[name]["type"]
+		switch {
+		case deviceType == "":
+			problems = append(problems, fmt.Sprintf("device %q has no type", name))
+		case !allowedDevices[deviceType]:
+			problems = append(problems, fmt.Sprintf("device %q of type %q not allowed", name, deviceType))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.NotValidf("lxd profile: %s", strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+func configAllowed(key string) bool {
+	if allowedConfig[key] {
+		return true
+	}
+	for _, prefix := range allowedConfigPrefixes {
+		if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Parse parses the YAML content of a charm's LXD profile. Unknown
+// fields are rejected.
+func Parse(data []byte) (*Profile, error) {
+	var profile Profile
+	if err := yaml.UnmarshalStrict(data, &profile); err != nil {
+		return nil, errors.Annotate(err, "cannot parse lxd profile")
+	}
+	return &profile, nil
+}
+
+// ReadCharm returns the LXD profile declared by the given charm, or
+// nil if the charm does not declare one. Only charm archives and
+// charm directories carry a profile; other charms are treated as
+// declaring none.
+func ReadCharm(ch charm.Charm) (*Profile, error) {
+	var data []byte
+	var err error
+	switch ch := ch.(type) {
+	case *charm.CharmArchive:
+		data, err = readArchiveFile(ch.Path, Filename)
+	case *charm.CharmDir:
+		data, err = ioutil.ReadFile(filepath.Join(ch.Path, Filename))
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read %s", Filename)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return Parse(data)
+}
+
+func readArchiveFile(path, name string) ([]byte, error) {
+	zipr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer zipr.Close()
+	for _, f := range zipr.File {
+		if f.Name != name {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	}
+	return nil, nil
+}
+
+// Name returns the name of the LXD profile that holds the profile
+// declared by the given revision of an application's charm.
+func Name(modelName, appName string, revision int) string {
+	return fmt.Sprintf("juju-%s-%s-%d", modelName, appName, revision)
+}
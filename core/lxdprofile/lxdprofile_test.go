@@ -0,0 +1,150 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxdprofile_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/core/lxdprofile"
+)
+
+type LXDProfileSuite struct{}
+
+var _ = gc.Suite(&LXDProfileSuite{})
+
+const profileYAML = `
+description: profile for testing
+config:
+  security.nesting: "true"
+  linux.kernel_modules: openvswitch,nbd
+  environment.http_proxy: ""
+devices:
+  gpu:
+    type: gpu
+`
+
+func (*LXDProfileSuite) TestParse(c *gc.C) {
+	profile, err := lxdprofile.Parse([]byte(profileYAML))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile, jc.DeepEquals, &lxdprofile.Profile{
+		Description: "profile for testing",
+		Config: map[string]string{
+			"security.nesting":       "true",
+			"linux.kernel_modules":   "openvswitch,nbd",
+			"environment.http_proxy": "",
+		},
+		Devices: map[string]map[string]string{
+			"gpu": {"type": "gpu"},
+		},
+	})
+	c.Assert(profile.Validate(), jc.ErrorIsNil)
+	c.Assert(profile.Empty(), jc.IsFalse)
+}
+
+func (*LXDProfileSuite) TestParseUnknownField(c *gc.C) {
+	_, err := lxdprofile.Parse([]byte("name: foo\n"))
+	c.Assert(err, gc.ErrorMatches, `cannot parse lxd profile: .*field name not found.*`)
+}
+
+func (*LXDProfileSuite) TestValidateRejectsDisallowed(c *gc.C) {
+	profile := &lxdprofile.Profile{
+		Config: map[string]string{
+			"boot.autostart":      "false",
+			"security.nesting":    "true",
+			"security.privileged": "true",
+			"limits.memory":       "1GB",
+			"environment.":        "x",
+			"user.user-data":      "",
+			"security.syscalls.":  "",
+		},
+		Devices: map[string]map[string]string{
+			"root": {"type": "disk", "path": "/"},
+			"gpu":  {"type": "gpu"},
+			"odd":  {"path": "/dev/odd"},
+			"sda":  {"type": "unix-block", "path": "/dev/sda"},
+			"tun":  {"type": "unix-char", "path": "/dev/net/tun"},
+		},
+	}
+	err := profile.Validate()
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `lxd profile: `+
+		`config "boot.autostart" not allowed, `+
+		`config "environment." not allowed, `+
+		`config "limits.memory" not allowed, `+
+		`config "security.privileged" not allowed, `+
+		`config "security.syscalls." not allowed, `+
+		`config "user.user-data" not allowed, `+
+		`device "odd" has no type, `+
+		`device "root" of type "disk" not allowed, `+
+		`device "sda" of type "unix-block" not allowed, `+
+		`device "tun" of type "unix-char" not allowed not valid`)
+}
+
+func (*LXDProfileSuite) TestEmpty(c *gc.C) {
+	var profile *lxdprofile.Profile
+	c.Assert(profile.Empty(), jc.IsTrue)
+	c.Assert(profile.Validate(), jc.ErrorIsNil)
+	c.Assert((&lxdprofile.Profile{Description: "nothing"}).Empty(), jc.IsTrue)
+}
+
+func (*LXDProfileSuite) TestName(c *gc.C) {
+	c.Assert(lxdprofile.Name("default", "ubuntu", 7), gc.Equals, "juju-default-ubuntu-7")
+}
+
+func (s *LXDProfileSuite) writeCharmDir(c *gc.C, withProfile bool) string {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte("name: test\nsummary: test\ndescription: test\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	if withProfile {
+		err = ioutil.WriteFile(filepath.Join(dir, lxdprofile.Filename), []byte(profileYAML), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return dir
+}
+
+func (s *LXDProfileSuite) TestReadCharmDir(c *gc.C) {
+	ch, err := charm.ReadCharmDir(s.writeCharmDir(c, true))
+	c.Assert(err, jc.ErrorIsNil)
+	profile, err := lxdprofile.ReadCharm(ch)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile.Description, gc.Equals, "profile for testing")
+}
+
+func (s *LXDProfileSuite) TestReadCharmArchive(c *gc.C) {
+	for _, withProfile := range []bool{true, false} {
+		ch, err := charm.ReadCharmDir(s.writeCharmDir(c, withProfile))
+		c.Assert(err, jc.ErrorIsNil)
+		path := filepath.Join(c.MkDir(), "test.charm")
+		f, err := os.Create(path)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ch.ArchiveTo(f)
+		f.Close()
+		c.Assert(err, jc.ErrorIsNil)
+		archive, err := charm.ReadCharmArchive(path)
+		c.Assert(err, jc.ErrorIsNil)
+
+		profile, err := lxdprofile.ReadCharm(archive)
+		c.Assert(err, jc.ErrorIsNil)
+		if withProfile {
+			c.Assert(profile.Devices["gpu"]["type"], gc.Equals, "gpu")
+		} else {
+			c.Assert(profile, gc.IsNil)
+		}
+	}
+}
+
+func (s *LXDProfileSuite) TestReadCharmDirWithoutProfile(c *gc.C) {
+	ch, err := charm.ReadCharmDir(s.writeCharmDir(c, false))
+	c.Assert(err, jc.ErrorIsNil)
+	profile, err := lxdprofile.ReadCharm(ch)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile, gc.IsNil)
+}
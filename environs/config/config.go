@@ -110,6 +110,10 @@ const (
 	// networking method for containers.
 	ContainerNetworkingMethod = "container-networking-method"
 
+	// KVMNICModel is the key for setting the model of the virtual
+	// network interfaces given to KVM containers.
+	KVMNICModel = "kvm-nic-model"
+
 	// The default block storage source.
 	StorageDefaultBlockSourceKey = "storage-default-block-source"
 
@@ -339,6 +343,14 @@ const (
 	DefaultActionResultsAge = "336h" // 2 weeks
 
 	DefaultActionResultsSize = "5G"
+
+	// MinUpdateStatusHookInterval is the shortest allowed interval
+	// between runs of the update-status hook.
+	MinUpdateStatusHookInterval = 1 * time.Minute
+
+	// MaxUpdateStatusHookInterval is the longest allowed interval
+	// between runs of the update-status hook.
+	MaxUpdateStatusHookInterval = 60 * time.Minute
 )
 
 var defaultConfigValues = map[string]interface{}{
@@ -368,6 +380,7 @@ var defaultConfigValues = map[string]interface{}{
 	// $ juju model-config net-bond-reconfigure-delay=30
 	NetBondReconfigureDelayKey: 17,
 	ContainerNetworkingMethod:  "",
+	KVMNICModel:                "",
 
 	"default-series":           series.LatestLts(),
 	ProvisionerHarvestModeKey:  HarvestDestroyed.String(),
@@ -554,10 +567,10 @@ func Validate(cfg, old *Config) error {
 		if f, err := time.ParseDuration(v); err != nil {
 			return errors.Annotate(err, "invalid update status hook interval in model configuration")
 		} else {
-			if f < 1*time.Minute {
+			if f < MinUpdateStatusHookInterval {
 				return errors.Annotatef(err, "update status hook frequency %v cannot be less than 1m", f)
 			}
-			if f > 60*time.Minute {
+			if f > MaxUpdateStatusHookInterval {
 				return errors.Annotatef(err, "update status hook frequency %v cannot be greater than 60m", f)
 			}
 		}
@@ -595,6 +608,14 @@ func Validate(cfg, old *Config) error {
 			return fmt.Errorf("Invalid value for container-networking-method - %v", v)
 		}
 	}
+
+	if v, ok := cfg.defined[KVMNICModel].(string); ok {
+		switch v {
+		case "", "virtio", "e1000", "rtl8139":
+		default:
+			return errors.Errorf("invalid %s %q: expected one of virtio, e1000, rtl8139", KVMNICModel, v)
+		}
+	}
 	// Check the immutable config values.  These can't change
 	if old != nil {
 		for _, attr := range immutableAttributes {
@@ -713,6 +734,13 @@ func (c *Config) ContainerNetworkingMethod() string {
 	return c.asString(ContainerNetworkingMethod)
 }
 
+// KVMNICModel returns the model of virtual network interface to use
+// for KVM containers. An empty value means the libvirt default used
+// by Juju (virtio).
+func (c *Config) KVMNICModel() string {
+	return c.asString(KVMNICModel)
+}
+
 // ProxySettings returns all four proxy settings; http, https, ftp, and no
 // proxy.
 func (c *Config) ProxySettings() proxy.Settings {
@@ -1202,6 +1230,7 @@ var alwaysOptional = schema.Defaults{
 	TransmitVendorMetricsKey:     schema.Omit,
 	NetBondReconfigureDelayKey:   schema.Omit,
 	ContainerNetworkingMethod:    schema.Omit,
+	KVMNICModel:                  schema.Omit,
 	MaxStatusHistoryAge:          schema.Omit,
 	MaxStatusHistorySize:         schema.Omit,
 	MaxActionResultsAge:          schema.Omit,
@@ -1590,6 +1619,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	KVMNICModel: {
+		Description: "Model of the network interfaces of KVM containers - one of virtio, e1000, rtl8139",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	MaxStatusHistoryAge: {
 		Description: "The maximum age for status history entries before they are pruned, in human-readable time format",
 		Type:        environschema.Tstring,
@@ -526,6 +526,19 @@ var configTests = []configTest{
 		attrs: minimalConfigAttrs.Merge(testing.Attrs{
 			config.NetBondReconfigureDelayKey: 1234,
 		}),
+	}, {
+		about:       "kvm-nic-model value",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			config.KVMNICModel: "e1000",
+		}),
+	}, {
+		about:       "Invalid kvm-nic-model value",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			config.KVMNICModel: "ne2k_pci",
+		}),
+		err: `invalid kvm-nic-model "ne2k_pci": expected one of virtio, e1000, rtl8139`,
 	}, {
 		about:       "transmit-vendor-metrics asserted with default value",
 		useDefaults: config.UseDefaults,
@@ -408,6 +408,28 @@ type Firewaller interface {
 	IngressRules() ([]network.IngressRule, error)
 }
 
+// EgressFirewaller is an interface that can be implemented by an
+// Environ that supports restricting the traffic sent by instances.
+// Egress rules are always applied to individual instances, regardless
+// of the model's firewall mode. An instance without egress rules is
+// not restricted; once any rule is opened, only traffic matching the
+// opened rules is allowed.
+type EgressFirewaller interface {
+	// OpenEgressPorts allows traffic from the given instance to the
+	// destinations described by the rules, adding to any destinations
+	// already allowed for the same port range.
+	OpenEgressPorts(instId instance.Id, machineId string, rules []network.EgressRule) error
+
+	// CloseEgressPorts stops allowing traffic from the given instance
+	// to the destinations described by the rules.
+	CloseEgressPorts(instId instance.Id, machineId string, rules []network.EgressRule) error
+
+	// EgressRules returns the egress rules applied to the given instance.
+	// There is only one rule result for a given port range - the rule's
+	// DestinationCIDRs contain all of the destinations allowed for it.
+	EgressRules(instId instance.Id, machineId string) ([]network.EgressRule, error)
+}
+
 // InstanceTagger is an interface that can be used for tagging instances.
 type InstanceTagger interface {
 	// TagInstance tags the given instance with the specified tags.
@@ -0,0 +1,51 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual
+
+import (
+	"sync"
+)
+
+// ProvisionResult holds the outcome of provisioning a single host
+// with ProvisionMachines.
+type ProvisionResult struct {
+	// Host is the host that was provisioned.
+	Host string
+
+	// MachineId is the id of the machine recorded for the host,
+	// if provisioning succeeded.
+	MachineId string
+
+	// Error holds the reason provisioning failed, if it did.
+	Error error
+}
+
+// ProvisionMachines provisions each of the hosts described by args with
+// provision, running no more than parallel provisioners at once. The
+// results are returned in the same order as args.
+func ProvisionMachines(provision ProvisionMachineFunc, args []ProvisionMachineArgs, parallel int) []ProvisionResult {
+	if parallel < 1 {
+		parallel = 1
+	}
+	results := make([]ProvisionResult, len(args))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, arg := range args {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, arg ProvisionMachineArgs) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			logger.Infof("provisioning %s", arg.Host)
+			machineId, err := provision(arg)
+			results[i] = ProvisionResult{
+				Host:      arg.Host,
+				MachineId: machineId,
+				Error:     err,
+			}
+		}(i, arg)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual_test
+
+import (
+	"errors"
+	"sync"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/testing"
+)
+
+type bulkSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&bulkSuite{})
+
+func (s *bulkSuite) TestProvisionMachines(c *gc.C) {
+	var (
+		mu                sync.Mutex
+		running, maxCount int
+	)
+	release := make(chan struct{})
+	provision := func(args manual.ProvisionMachineArgs) (string, error) {
+		mu.Lock()
+		running++
+		if running > maxCount {
+			maxCount = running
+		}
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if args.Host == "bad" {
+			return "", errors.New("no route to host")
+		}
+		return "machine-for-" + args.Host, nil
+	}
+	go func() {
+		for i := 0; i < 4; i++ {
+			release <- struct{}{}
+		}
+	}()
+
+	results := manual.ProvisionMachines(provision, []manual.ProvisionMachineArgs{
+		{Host: "one"}, {Host: "bad"}, {Host: "three"}, {Host: "four"},
+	}, 2)
+	c.Assert(maxCount <= 2, jc.IsTrue)
+	c.Assert(results, gc.HasLen, 4)
+	c.Assert(results[0], jc.DeepEquals, manual.ProvisionResult{Host: "one", MachineId: "machine-for-one"})
+	c.Assert(results[1].Host, gc.Equals, "bad")
+	c.Assert(results[1].Error, gc.ErrorMatches, "no route to host")
+	c.Assert(results[2], jc.DeepEquals, manual.ProvisionResult{Host: "three", MachineId: "machine-for-three"})
+	c.Assert(results[3], jc.DeepEquals, manual.ProvisionResult{Host: "four", MachineId: "machine-for-four"})
+}
+
+func (s *bulkSuite) TestProvisionMachinesNoHosts(c *gc.C) {
+	results := manual.ProvisionMachines(nil, nil, 10)
+	c.Assert(results, gc.HasLen, 0)
+}
@@ -6,4 +6,5 @@ package sshprovisioner
 
 const (
 	DetectionScript = detectionScript
+	DiskSpaceScript = diskSpaceScript
 )
@@ -115,6 +115,10 @@ type fakeSSH struct {
 	// exit code for the checkProvisioned script.
 	CheckProvisionedExitCode int
 
+	// DiskSpace is the free disk space, in MiB, reported to
+	// checkDiskSpace. If empty, there is plenty.
+	DiskSpace string
+
 	// exit code for the machine agent provisioning script.
 	ProvisionAgentExitCode int
 
@@ -146,6 +150,11 @@ func (r fakeSSH) install(c *gc.C) testing.Restorer {
 	}
 	if !r.SkipDetection {
 		restore.Add(installDetectionFakeSSH(c, r.Series, r.Arch))
+		diskSpace := r.DiskSpace
+		if diskSpace == "" {
+			diskSpace = "10240"
+		}
+		add(sshprovisioner.DiskSpaceScript, diskSpace, 0)
 	}
 	var checkProvisionedOutput interface{}
 	if r.Provisioned {
@@ -151,6 +151,34 @@ func (s *initialisationSuite) TestCheckProvisioned(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 255 \\(non-empty-stderr\\)")
 }
 
+func (s *initialisationSuite) TestCheckDiskSpace(c *gc.C) {
+	defer installFakeSSH(c, sshprovisioner.DiskSpaceScript, "4096", 0)()
+	err := sshprovisioner.CheckDiskSpace("example.com")
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer installFakeSSH(c, sshprovisioner.DiskSpaceScript, "512", 0)()
+	err = sshprovisioner.CheckDiskSpace("example.com")
+	c.Assert(err, gc.ErrorMatches, "not enough free disk space on example.com: 512MiB available, 2048MiB required")
+
+	defer installFakeSSH(c, sshprovisioner.DiskSpaceScript, "", 0)()
+	err = sshprovisioner.CheckDiskSpace("example.com")
+	c.Assert(err, gc.ErrorMatches, "cannot parse free disk space: .*")
+
+	defer installFakeSSH(c, sshprovisioner.DiskSpaceScript, []string{"", "df: no such file"}, 1)()
+	err = sshprovisioner.CheckDiskSpace("example.com")
+	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 1 \\(df: no such file\\)")
+}
+
+func (s *initialisationSuite) TestUninstallMachine(c *gc.C) {
+	defer installFakeSSH(c, nil, "", 0)()
+	err := sshprovisioner.UninstallMachine("example.com")
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer installFakeSSH(c, nil, []string{"", "jujud did not stop"}, 1)()
+	err = sshprovisioner.UninstallMachine("example.com")
+	c.Assert(err, gc.ErrorMatches, "uninstalling machine agent from example.com: subprocess encountered error code 1 \\(jujud did not stop\\)")
+}
+
 func (s *initialisationSuite) TestInitUbuntuUserNonExisting(c *gc.C) {
 	defer installFakeSSH(c, "", "", 0)() // successful creation of ubuntu user
 	defer installFakeSSH(c, "", "", 1)() // simulate failure of ubuntu@ login
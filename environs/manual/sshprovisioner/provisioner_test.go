@@ -127,6 +127,29 @@ func (s *provisionerSuite) TestProvisionMachine(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "error checking if provisioned: subprocess encountered error code 255")
 }
 
+func (s *provisionerSuite) TestProvisionMachinePreflightChecks(c *gc.C) {
+	args := s.getArgs(c)
+
+	defer fakeSSH{
+		Series:             "precise",
+		Arch:               "amd64",
+		DiskSpace:          "100",
+		InitUbuntuUser:     true,
+		SkipProvisionAgent: true,
+	}.install(c).Restore()
+	_, err := sshprovisioner.ProvisionMachine(args)
+	c.Assert(err, gc.ErrorMatches, "error checking disk space: not enough free disk space on .*: 100MiB available, 2048MiB required")
+
+	defer fakeSSH{
+		Series:             "no-such-series",
+		Arch:               "amd64",
+		InitUbuntuUser:     true,
+		SkipProvisionAgent: true,
+	}.install(c).Restore()
+	_, err = sshprovisioner.ProvisionMachine(args)
+	c.Assert(err, gc.ErrorMatches, `series "no-such-series" detected on .* is not supported`)
+}
+
 func (s *provisionerSuite) TestFinishInstancConfig(c *gc.C) {
 	var series = series.LatestLts()
 	const arch = "amd64"
@@ -8,15 +8,19 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
 	"github.com/juju/utils/arch"
+	jujuseries "github.com/juju/utils/series"
 	"github.com/juju/utils/shell"
 	"github.com/juju/utils/ssh"
 
+	"github.com/juju/juju/agent"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig"
 	"github.com/juju/juju/cloudconfig/cloudinit"
@@ -185,6 +189,93 @@ func checkProvisioned(host string) (bool, error) {
 	return provisioned, nil
 }
 
+// minDiskSpace is the free space, in MiB, a host needs under /var/lib
+// to hold the agent, its tools and the charms it will run.
+const minDiskSpace = 2048
+
+// CheckDiskSpace checks that the host has enough free space
+// for the machine agent.
+var CheckDiskSpace = checkDiskSpace
+
+func checkDiskSpace(host string) error {
+	logger.Infof("Checking free disk space on %s", host)
+	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, nil)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Stdin = strings.NewReader(diskSpaceScript)
+	if err := cmd.Run(); err != nil {
+		if stderr.Len() != 0 {
+			err = fmt.Errorf("%v (%v)", err, strings.TrimSpace(stderr.String()))
+		}
+		return err
+	}
+	available, err := strconv.ParseUint(strings.TrimSpace(stdout.String()), 10, 64)
+	if err != nil {
+		return errors.Annotate(err, "cannot parse free disk space")
+	}
+	if available < minDiskSpace {
+		return errors.Errorf(
+			"not enough free disk space on %s: %dMiB available, %dMiB required",
+			host, available, minDiskSpace,
+		)
+	}
+	return nil
+}
+
+// diskSpaceScript is the script to run on the remote machine to
+// report the free space, in MiB, under /var/lib.
+const diskSpaceScript = `df -Pm /var/lib | awk 'NR == 2 { print $4 }'`
+
+// UninstallMachine removes the machine agent from the host,
+// and any trace of Juju it leaves behind.
+var UninstallMachine = uninstallMachine
+
+func uninstallMachine(host string) error {
+	logger.Infof("Uninstalling machine agent from %s", host)
+	script := fmt.Sprintf(
+		uninstallScript,
+		// The agent uninstalls itself when it terminates,
+		// if the uninstall file is present.
+		utils.ShQuote(path.Join(agent.DefaultPaths.DataDir, agent.UninstallFile)),
+		int(uninstallTimeout/time.Second),
+		utils.ShQuote(agent.DefaultPaths.DataDir),
+		utils.ShQuote(agent.DefaultPaths.LogDir),
+	)
+	cmd := ssh.Command("ubuntu@"+host, []string{"sudo", "/bin/bash"}, nil)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.Stdin = strings.NewReader(script)
+	if err := cmd.Run(); err != nil {
+		if stderr.Len() != 0 {
+			err = fmt.Errorf("%v (%v)", err, strings.TrimSpace(stderr.String()))
+		}
+		return errors.Annotatef(err, "uninstalling machine agent from %s", host)
+	}
+	return nil
+}
+
+// uninstallTimeout is how long to wait for the machine agent to notice
+// that its machine has been removed, and stop.
+const uninstallTimeout = 10 * time.Minute
+
+// uninstallScript waits for the machine agent to stop and uninstall
+// itself, then removes anything it left behind. The agent is never
+// killed, so its units are stopped cleanly.
+const uninstallScript = `
+touch %[1]s
+for i in $(seq %[2]d); do
+    pgrep jujud > /dev/null || break
+    sleep 1
+done
+pgrep jujud > /dev/null && { echo jujud did not stop >&2; exit 1; }
+rm -f /etc/init/jujud-*
+rm -f /etc/systemd/system{,/multi-user.target.wants}/jujud-*
+rm -f /usr/bin/juju-run
+rm -fr %[3]s %[4]s
+exit 0
+`
+
 // detectionScript is the script to run on the remote machine to
 // detect the OS series and hardware characteristics.
 const detectionScript = `#!/bin/bash
@@ -231,10 +322,17 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 		return nil, manual.ErrProvisioned
 	}
 
+	if err := CheckDiskSpace(hostname); err != nil {
+		return nil, errors.Annotatef(err, "error checking disk space")
+	}
+
 	hc, series, err := DetectSeriesAndHardwareCharacteristics(hostname)
 	if err != nil {
 		return nil, errors.Annotatef(err, "error detecting linux hardware characteristics")
 	}
+	if _, err := jujuseries.GetOSFromSeries(series); err != nil {
+		return nil, errors.Errorf("series %q detected on %s is not supported", series, hostname)
+	}
 
 	// There will never be a corresponding "instance" that any provider
 	// knows about. This is fine, and works well with the provisioner
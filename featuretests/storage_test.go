@@ -240,6 +240,8 @@ block:
   provider: loop
   attrs:
     it: works
+kvm:
+  provider: kvm
 loop:
   provider: loop
 machinescoped:
@@ -266,6 +268,7 @@ func (s *cmdStorageSuite) TestListPoolsTabular(c *gc.C) {
 	expected := `
 Name                      Provider                  Attrs
 block                     loop                      it=works
+kvm                       kvm                       
 loop                      loop                      
 machinescoped             machinescoped             
 modelscoped               modelscoped               
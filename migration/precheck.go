@@ -5,6 +5,7 @@ package migration
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/version"
@@ -14,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/cloud"
 	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/status"
@@ -25,6 +27,7 @@ import (
 type PrecheckBackend interface {
 	AgentVersion() (version.Number, error)
 	NeedsCleanup() (bool, error)
+	HasSecrets() (bool, error)
 	Model() (PrecheckModel, error)
 	AllModelUUIDs() ([]string, error)
 	IsUpgrading() (bool, error)
@@ -80,6 +83,11 @@ type PrecheckApplication interface {
 	CharmURL() (*charm.URL, bool)
 	AllUnits() ([]PrecheckUnit, error)
 	MinUnits() int
+	ExposedEndpoints() map[string]state.ExposedEndpoint
+	EgressRules() ([]network.EgressRule, error)
+	UpdateStatusInterval() time.Duration
+	HookTimeoutPolicy() state.HookTimeoutPolicy
+	HasPassword() bool
 }
 
 // PrecheckUnit describes state interface for a unit needed by
@@ -116,6 +124,15 @@ func SourcePrecheck(backend PrecheckBackend) error {
 		return errors.New("cleanup needed")
 	}
 
+	// Charm secrets are encrypted with a key derived from the source
+	// controller's secrets key, and the model description cannot yet
+	// carry them.
+	if hasSecrets, err := backend.HasSecrets(); err != nil {
+		return errors.Annotate(err, "checking secrets")
+	} else if hasSecrets {
+		return errors.New("model has charm secrets, which cannot be migrated")
+	}
+
 	// Check the source controller.
 	controllerBackend, err := backend.ControllerBackend()
 	if err != nil {
@@ -307,6 +324,9 @@ func checkApplications(backend PrecheckBackend) error {
 		if app.Life() != state.Alive {
 			return errors.Errorf("application %s is %s", app.Name(), app.Life())
 		}
+		if err := checkApplicationSettings(app); err != nil {
+			return errors.Trace(err)
+		}
 		err := checkUnits(app, modelVersion)
 		if err != nil {
 			return errors.Trace(err)
@@ -315,6 +335,32 @@ func checkApplications(backend PrecheckBackend) error {
 	return nil
 }
 
+// checkApplicationSettings refuses to migrate applications using
+// settings that the model description cannot yet carry, rather than
+// silently dropping them.
+func checkApplicationSettings(app PrecheckApplication) error {
+	if len(app.ExposedEndpoints()) > 0 {
+		return errors.Errorf("application %s has endpoint specific expose settings, which cannot be migrated", app.Name())
+	}
+	egressRules, err := app.EgressRules()
+	if err != nil {
+		return errors.Annotatef(err, "retrieving egress rules for %s", app.Name())
+	}
+	if len(egressRules) > 0 {
+		return errors.Errorf("application %s has egress rules, which cannot be migrated", app.Name())
+	}
+	if app.UpdateStatusInterval() != 0 {
+		return errors.Errorf("application %s has an update-status interval, which cannot be migrated", app.Name())
+	}
+	if policy := app.HookTimeoutPolicy(); policy.Timeout != 0 || !policy.Retry {
+		return errors.Errorf("application %s has a hook timeout policy, which cannot be migrated", app.Name())
+	}
+	if app.HasPassword() {
+		return errors.Errorf("application %s has an operator agent, which cannot be migrated", app.Name())
+	}
+	return nil
+}
+
 func checkUnits(app PrecheckApplication, modelVersion version.Number) error {
 	units, err := app.AllUnits()
 	if err != nil {
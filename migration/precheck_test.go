@@ -4,6 +4,8 @@
 package migration_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
@@ -14,6 +16,7 @@ import (
 	"github.com/juju/juju/cloud"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/resource/resourcetesting"
 	"github.com/juju/juju/state"
@@ -107,6 +110,20 @@ func (*SourcePrecheckSuite) TestCleanupsNeeded(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cleanup needed")
 }
 
+func (*SourcePrecheckSuite) TestSecretsError(c *gc.C) {
+	backend := newFakeBackend()
+	backend.hasSecretsErr = errors.New("boom")
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "checking secrets: boom")
+}
+
+func (*SourcePrecheckSuite) TestSecrets(c *gc.C) {
+	backend := newFakeBackend()
+	backend.hasSecrets = true
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "model has charm secrets, which cannot be migrated")
+}
+
 func (s *SourcePrecheckSuite) TestIsUpgradingError(c *gc.C) {
 	backend := newFakeBackend()
 	backend.controllerBackend.isUpgradingErr = errors.New("boom")
@@ -168,6 +185,88 @@ func (s *SourcePrecheckSuite) TestDyingApplication(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "application foo is dying")
 }
 
+func (s *SourcePrecheckSuite) TestApplicationWithExposedEndpoints(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name: "foo",
+				exposedEndpoints: map[string]state.ExposedEndpoint{
+					"website": {ExposeToCIDRs: []string{"10.0.0.0/24"}},
+				},
+			},
+		},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has endpoint specific expose settings, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestApplicationWithEgressRules(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name: "foo",
+				egressRules: []network.EgressRule{
+					network.MustNewEgressRule("tcp", 443, 443, "0.0.0.0/0"),
+				},
+			},
+		},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has egress rules, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestApplicationWithUpdateStatusInterval(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name:         "foo",
+				updateStatus: time.Minute,
+			},
+		},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has an update-status interval, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestApplicationWithHookTimeout(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name:        "foo",
+				hookTimeout: &state.HookTimeoutPolicy{Timeout: time.Hour, Retry: true},
+			},
+		},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has a hook timeout policy, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestApplicationWithHookTimeoutNoRetry(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name:        "foo",
+				hookTimeout: &state.HookTimeoutPolicy{},
+			},
+		},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has a hook timeout policy, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestApplicationWithOperator(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name:        "foo",
+				hasPassword: true,
+			},
+		},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has an operator agent, which cannot be migrated")
+}
+
 func (s *SourcePrecheckSuite) TestWithPendingMinUnits(c *gc.C) {
 	backend := &fakeBackend{
 		apps: []migration.PrecheckApplication{
@@ -649,6 +748,9 @@ type fakeBackend struct {
 	cleanupNeeded bool
 	cleanupErr    error
 
+	hasSecrets    bool
+	hasSecretsErr error
+
 	isUpgrading    bool
 	isUpgradingErr error
 
@@ -686,6 +788,10 @@ func (b *fakeBackend) NeedsCleanup() (bool, error) {
 	return b.cleanupNeeded, b.cleanupErr
 }
 
+func (b *fakeBackend) HasSecrets() (bool, error) {
+	return b.hasSecrets, b.hasSecretsErr
+}
+
 func (b *fakeBackend) AgentVersion() (version.Number, error) {
 	return backendVersion, b.agentVersionErr
 }
@@ -839,11 +945,16 @@ func (m *fakeMachine) ShouldRebootOrShutdown() (state.RebootAction, error) {
 }
 
 type fakeApp struct {
-	name     string
-	life     state.Life
-	charmURL string
-	units    []migration.PrecheckUnit
-	minunits int
+	name             string
+	life             state.Life
+	charmURL         string
+	units            []migration.PrecheckUnit
+	minunits         int
+	exposedEndpoints map[string]state.ExposedEndpoint
+	egressRules      []network.EgressRule
+	updateStatus     time.Duration
+	hookTimeout      *state.HookTimeoutPolicy
+	hasPassword      bool
 }
 
 func (a *fakeApp) Name() string {
@@ -870,6 +981,29 @@ func (a *fakeApp) MinUnits() int {
 	return a.minunits
 }
 
+func (a *fakeApp) ExposedEndpoints() map[string]state.ExposedEndpoint {
+	return a.exposedEndpoints
+}
+
+func (a *fakeApp) EgressRules() ([]network.EgressRule, error) {
+	return a.egressRules, nil
+}
+
+func (a *fakeApp) UpdateStatusInterval() time.Duration {
+	return a.updateStatus
+}
+
+func (a *fakeApp) HookTimeoutPolicy() state.HookTimeoutPolicy {
+	if a.hookTimeout == nil {
+		return state.HookTimeoutPolicy{Retry: true}
+	}
+	return *a.hookTimeout
+}
+
+func (a *fakeApp) HasPassword() bool {
+	return a.hasPassword
+}
+
 type fakeUnit struct {
 	name        string
 	version     version.Binary
@@ -72,3 +72,34 @@ func (*FirewallSuite) TestNewIngressRuleBadCIDR(c *gc.C) {
 	_, err := network.NewIngressRule("tcp", 80, 100, "0.0.0.0/0", "192.168.0/24")
 	c.Assert(err, gc.ErrorMatches, "invalid CIDR address: 192.168.0/24")
 }
+
+func (*FirewallSuite) TestEgressRuleStrings(c *gc.C) {
+	rule := network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8")
+	c.Assert(rule.String(), gc.Equals, "443/tcp to 10.0.0.0/8")
+	c.Assert(rule.GoString(), gc.Equals, "443/tcp to 10.0.0.0/8")
+
+	rule = network.MustNewEgressRule("udp", 53, 60, "10.0.0.0/8", "192.168.1.0/24")
+	c.Assert(rule.String(), gc.Equals, "53-60/udp to 10.0.0.0/8,192.168.1.0/24")
+}
+
+func (*FirewallSuite) TestNewEgressRuleInvalid(c *gc.C) {
+	_, err := network.NewEgressRule("tcp", 443, 443)
+	c.Assert(err, gc.ErrorMatches, `egress rule for 443/tcp without destinations not valid`)
+
+	_, err = network.NewEgressRule("tcp", 443, 80, "10.0.0.0/8")
+	c.Assert(err, gc.ErrorMatches, `invalid port range 443-80/tcp`)
+
+	_, err = network.NewEgressRule("tcp", 443, 443, "10.0.0")
+	c.Assert(err, gc.ErrorMatches, `invalid CIDR address: 10.0.0`)
+}
+
+func (*FirewallSuite) TestSortEgressRules(c *gc.C) {
+	rule1 := network.MustNewEgressRule("udp", 10, 100, "10.0.0.0/8")
+	rule2 := network.MustNewEgressRule("tcp", 80, 90, "10.0.0.0/8")
+	rule3 := network.MustNewEgressRule("tcp", 80, 80, "192.168.1.0/24")
+	rule4 := network.MustNewEgressRule("tcp", 80, 80, "10.0.0.0/8")
+
+	rules := []network.EgressRule{rule1, rule2, rule3, rule4}
+	network.SortEgressRules(rules)
+	c.Assert(rules, gc.DeepEquals, []network.EgressRule{rule4, rule3, rule2, rule1})
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/juju/utils/arch"
 	"github.com/juju/utils/clock"
 	"github.com/juju/utils/series"
+	"github.com/juju/utils/set"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/environschema.v1"
@@ -267,6 +268,7 @@ type environ struct {
 
 var _ environs.Environ = (*environ)(nil)
 var _ environs.Networking = (*environ)(nil)
+var _ environs.EgressFirewaller = (*environ)(nil)
 
 // discardOperations discards all Operations written to it.
 var discardOperations = make(chan Operation)
@@ -797,6 +799,7 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 				MongoInfo:        info,
 				MongoDialOpts:    mongotest.DialOpts(),
 				NewPolicy:        estate.newStatePolicy,
+				SecretsKey:       testing.SecretsKey,
 			})
 			if err != nil {
 				return err
@@ -1508,6 +1511,111 @@ func (e *environ) IngressRules() (rules []network.IngressRule, err error) {
 	return
 }
 
+// egressInstance returns the instance with the given id, checking that
+// it is hosting the given machine. The environ state lock must be held.
+func (estate *environState) egressInstance(instId instance.Id, machineId string) (*dummyInstance, error) {
+	inst := estate.insts[instId]
+	if inst == nil {
+		return nil, errors.NotFoundf("instance %q", instId)
+	}
+	if inst.machineId != machineId {
+		panic(fmt.Errorf("egress rules with mismatched machine id, expected %q got %q", inst.machineId, machineId))
+	}
+	return inst, nil
+}
+
+// OpenEgressPorts is specified in environs.EgressFirewaller.
+func (e *environ) OpenEgressPorts(instId instance.Id, machineId string, rules []network.EgressRule) error {
+	defer delay()
+	if err := e.checkBroken("OpenEgressPorts"); err != nil {
+		return err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	inst, err := estate.egressInstance(instId, machineId)
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		found := false
+		for i, rule := range inst.egressRules {
+			if r.PortRange != rule.PortRange {
+				continue
+			}
+			cidrs := set.NewStrings(rule.DestinationCIDRs...).Union(set.NewStrings(r.DestinationCIDRs...))
+			inst.egressRules[i].DestinationCIDRs = cidrs.SortedValues()
+			found = true
+			break
+		}
+		if !found {
+			r.DestinationCIDRs = set.NewStrings(r.DestinationCIDRs...).SortedValues()
+			inst.egressRules = append(inst.egressRules, r)
+		}
+	}
+	return nil
+}
+
+// CloseEgressPorts is specified in environs.EgressFirewaller.
+func (e *environ) CloseEgressPorts(instId instance.Id, machineId string, rules []network.EgressRule) error {
+	defer delay()
+	if err := e.checkBroken("CloseEgressPorts"); err != nil {
+		return err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	inst, err := estate.egressInstance(instId, machineId)
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		for i, rule := range inst.egressRules {
+			if r.PortRange != rule.PortRange {
+				continue
+			}
+			cidrs := set.NewStrings(rule.DestinationCIDRs...).Difference(set.NewStrings(r.DestinationCIDRs...))
+			if cidrs.IsEmpty() {
+				inst.egressRules = inst.egressRules[:i+copy(inst.egressRules[i:], inst.egressRules[i+1:])]
+			} else {
+				inst.egressRules[i].DestinationCIDRs = cidrs.SortedValues()
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// EgressRules is specified in environs.EgressFirewaller.
+func (e *environ) EgressRules(instId instance.Id, machineId string) (rules []network.EgressRule, err error) {
+	defer delay()
+	if err := e.checkBroken("EgressRules"); err != nil {
+		return nil, err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	inst, err := estate.egressInstance(instId, machineId)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range inst.egressRules {
+		r.DestinationCIDRs = append([]string(nil), r.DestinationCIDRs...)
+		rules = append(rules, r)
+	}
+	network.SortEgressRules(rules)
+	return rules, nil
+}
+
 func (*environ) Provider() environs.EnvironProvider {
 	return &dummy
 }
@@ -1515,6 +1623,7 @@ func (*environ) Provider() environs.EnvironProvider {
 type dummyInstance struct {
 	state        *environState
 	rules        network.IngressRuleSlice
+	egressRules  network.EgressRuleSlice
 	id           instance.Id
 	status       string
 	machineId    string
@@ -210,6 +210,52 @@ func (s *suite) TestSupportsSpaceDiscovery(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *suite) TestEgressRules(c *gc.C) {
+	e := s.bootstrapTestEnviron(c)
+	defer func() {
+		err := e.Destroy()
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+
+	inst, _ := jujutesting.AssertStartInstance(c, e, s.ControllerUUID, "0")
+	fw, ok := e.(environs.EgressFirewaller)
+	c.Assert(ok, jc.IsTrue)
+
+	rules, err := fw.EgressRules(inst.Id(), "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	err = fw.OpenEgressPorts(inst.Id(), "0", []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+		network.MustNewEgressRule("tcp", 80, 80, "10.0.0.0/8"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = fw.OpenEgressPorts(inst.Id(), "0", []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "192.168.0.0/24"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fw.EgressRules(inst.Id(), "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 80, 80, "10.0.0.0/8"),
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8", "192.168.0.0/24"),
+	})
+
+	err = fw.CloseEgressPorts(inst.Id(), "0", []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+		network.MustNewEgressRule("tcp", 80, 80, "10.0.0.0/8"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fw.EgressRules(inst.Id(), "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "192.168.0.0/24"),
+	})
+
+	_, err = fw.EgressRules("bogus", "0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *suite) breakMethods(c *gc.C, e environs.NetworkingEnviron, names ...string) {
 	cfg := e.Config()
 	brokenCfg, err := cfg.Apply(map[string]interface{}{
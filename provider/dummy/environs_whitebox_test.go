@@ -9,6 +9,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 )
 
@@ -28,3 +29,19 @@ func (s *environWhiteboxSuite) TestSupportsContainerAddresses(c *gc.C) {
 	c.Check(supported, jc.IsFalse)
 	c.Check(env, gc.Not(jc.Satisfies), environs.SupportsContainerAddresses)
 }
+
+func (s *environWhiteboxSuite) TestInstanceTypes(c *gc.C) {
+	// The synthetic instance types do not depend on the environ.
+	var env *environ
+	result, err := env.InstanceTypes(constraints.MustParse("cores=2"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.CostUnit, gc.Equals, "$USD/hour")
+	c.Assert(result.CostDivisor, gc.Equals, uint64(1000))
+	c.Assert(result.InstanceTypes, gc.HasLen, 2)
+	c.Assert(result.InstanceTypes[0].Name, gc.Equals, "dummy-medium")
+	c.Assert(result.InstanceTypes[0].Cost, gc.Equals, uint64(80))
+	c.Assert(result.InstanceTypes[1].Name, gc.Equals, "dummy-large")
+
+	_, err = env.InstanceTypes(constraints.MustParse("cores=64"))
+	c.Assert(err, gc.ErrorMatches, `no instance types in dummy matching constraints "cores=64"`)
+}
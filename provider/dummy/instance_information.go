@@ -5,6 +5,7 @@ package dummy
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
@@ -13,8 +14,44 @@ import (
 
 var _ environs.InstanceTypesFetcher = (*environ)(nil)
 
+// instanceTypes holds the synthetic instance types offered by the
+// dummy provider. Costs are in thousandths of a US dollar per hour.
+var instanceTypes = []instances.InstanceType{{
+	Id:       "dummy-small",
+	Name:     "dummy-small",
+	Arches:   arch.AllSupportedArches,
+	CpuCores: 1,
+	Mem:      1024,
+	RootDisk: 8192,
+	Cost:     20,
+}, {
+	Id:       "dummy-medium",
+	Name:     "dummy-medium",
+	Arches:   arch.AllSupportedArches,
+	CpuCores: 2,
+	Mem:      4096,
+	RootDisk: 16384,
+	Cost:     80,
+}, {
+	Id:       "dummy-large",
+	Name:     "dummy-large",
+	Arches:   arch.AllSupportedArches,
+	CpuCores: 4,
+	Mem:      16384,
+	RootDisk: 32768,
+	Cost:     320,
+}}
+
 // InstanceTypes implements InstanceTypesFetcher
 func (e *environ) InstanceTypes(c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
-	result := instances.InstanceTypesWithCostMetadata{}
-	return result, errors.NotSupportedf("InstanceTypes")
+	iTypes, err := instances.MatchingInstanceTypes(instanceTypes, "dummy", c)
+	if err != nil {
+		return instances.InstanceTypesWithCostMetadata{}, errors.Trace(err)
+	}
+	return instances.InstanceTypesWithCostMetadata{
+		InstanceTypes: iTypes,
+		CostUnit:      "$USD/hour",
+		CostDivisor:   1000,
+		CostCurrency:  "USD",
+	}, nil
 }
@@ -0,0 +1,83 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/provider/libvirt"
+	"github.com/juju/juju/testing"
+)
+
+func fakeConfig(c *gc.C, attrs ...testing.Attrs) *config.Config {
+	cfg, err := testing.ModelConfig(c).Apply(fakeConfigAttrs(attrs...))
+	c.Assert(err, jc.ErrorIsNil)
+	return cfg
+}
+
+func fakeConfigAttrs(attrs ...testing.Attrs) testing.Attrs {
+	merged := testing.FakeConfig().Merge(testing.Attrs{
+		"type": "libvirt",
+		"uuid": "2d02eeac-9dbb-11e4-89d3-123b93f75cba",
+	})
+	for _, attrs := range attrs {
+		merged = merged.Merge(attrs)
+	}
+	return merged
+}
+
+func fakeCloudSpec() environs.CloudSpec {
+	cred := cloud.NewEmptyCredential()
+	return environs.CloudSpec{
+		Type:       "libvirt",
+		Name:       "libvirt",
+		Region:     "host1",
+		Endpoint:   "qemu+ssh://ubuntu@10.0.0.1/system",
+		Credential: &cred,
+	}
+}
+
+type ConfigSuite struct {
+	testing.BaseSuite
+	provider environs.EnvironProvider
+}
+
+var _ = gc.Suite(&ConfigSuite{})
+
+func (s *ConfigSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.provider = libvirt.NewEnvironProvider(libvirt.EnvironProviderConfig{})
+}
+
+func (s *ConfigSuite) TestValidateDefaults(c *gc.C) {
+	cfg, err := s.provider.Validate(fakeConfig(c), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	attrs := cfg.UnknownAttrs()
+	c.Check(attrs["libvirt-pool"], gc.Equals, "default")
+	c.Check(attrs["network-bridge"], gc.Equals, "br0")
+}
+
+func (s *ConfigSuite) TestValidateEmpty(c *gc.C) {
+	for _, key := range []string{"libvirt-pool", "network-bridge"} {
+		_, err := s.provider.Validate(fakeConfig(c, testing.Attrs{key: ""}), nil)
+		c.Check(err, gc.ErrorMatches, "invalid config: "+key+": must not be empty")
+	}
+}
+
+func (s *ConfigSuite) TestValidateChange(c *gc.C) {
+	old := fakeConfig(c)
+	cfg, err := s.provider.Validate(fakeConfig(c, testing.Attrs{"network-bridge": "virbr0"}), old)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.UnknownAttrs()["network-bridge"], gc.Equals, "virbr0")
+}
+
+func (s *ConfigSuite) TestValidateImmutablePool(c *gc.C) {
+	old := fakeConfig(c)
+	_, err := s.provider.Validate(fakeConfig(c, testing.Attrs{"libvirt-pool": "other"}), old)
+	c.Assert(err, gc.ErrorMatches, "invalid config change: libvirt-pool: cannot change from default to other")
+}
@@ -0,0 +1,106 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+)
+
+// Connection is an interface for managing the domains and storage
+// volumes of a libvirt host.
+type Connection interface {
+	// Close releases the connection.
+	Close() error
+
+	// DefineDomain defines a persistent domain on the host.
+	DefineDomain(kvmlibvirt.Domain) error
+
+	// StartDomain boots the named domain.
+	StartDomain(name string) error
+
+	// DestroyDomain stops the named domain, if it is running, and
+	// removes its definition. Destroying a domain that does not exist
+	// is not an error.
+	DestroyDomain(name string) error
+
+	// SetDomainDescription replaces the description of the named
+	// domain.
+	SetDomainDescription(name, description string) error
+
+	// Domains returns the domains whose names start with prefix.
+	Domains(prefix string) ([]DomainInfo, error)
+
+	// DomainAddresses returns the IP addresses of the named domain's
+	// network interfaces.
+	DomainAddresses(name string) ([]string, error)
+
+	// Volume returns the named volume in the storage pool. An error
+	// satisfying errors.IsNotFound is returned if there is no such
+	// volume.
+	Volume(pool, name string) (VolumeInfo, error)
+
+	// Volumes returns the volumes in the storage pool whose names
+	// start with prefix. The sizes of the volumes are not reported.
+	Volumes(pool, prefix string) ([]VolumeInfo, error)
+
+	// CreateVolume creates a volume in the storage pool.
+	CreateVolume(pool string, params VolumeParams) (VolumeInfo, error)
+
+	// UploadVolume replaces the content of the named volume with that
+	// of the local file at path.
+	UploadVolume(pool, name, path string) error
+
+	// DeleteVolume deletes the named volume from the storage pool.
+	// Deleting a volume that does not exist is not an error.
+	DeleteVolume(pool, name string) error
+
+	// AttachDisk attaches the volume at path to the named domain,
+	// as a virtio disk with the given serial number. Attaching a
+	// volume that is already attached is not an error.
+	AttachDisk(domain, path, serial string) error
+
+	// DetachDisk detaches the volume at path from the named domain.
+	DetachDisk(domain, path string) error
+}
+
+// DomainInfo describes a libvirt domain.
+type DomainInfo struct {
+	// Name is the unique name of the domain.
+	Name string
+
+	// State is the state of the domain, e.g. "running" or "shut off".
+	State string
+
+	// Description is the domain's free-form description. Juju records
+	// the domain's tags there.
+	Description string
+}
+
+// VolumeInfo describes a volume in a libvirt storage pool.
+type VolumeInfo struct {
+	// Name is the name of the volume, unique within its pool.
+	Name string
+
+	// Path is the location of the volume on the host.
+	Path string
+
+	// Size is the capacity of the volume in MiB.
+	Size uint64
+}
+
+// VolumeParams holds the parameters for creating a volume.
+type VolumeParams struct {
+	// Name is the name of the volume.
+	Name string
+
+	// Size is the capacity of the volume in MiB.
+	Size uint64
+
+	// Format is the format of the volume, e.g. qcow2 or raw.
+	Format string
+
+	// BackingPath, if set, is the path of a qcow2 volume that the new
+	// volume is a copy-on-write overlay of.
+	BackingPath string
+}
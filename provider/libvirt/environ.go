@@ -0,0 +1,213 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+)
+
+type environ struct {
+	name     string
+	cloud    environs.CloudSpec
+	provider *environProvider
+
+	// namespace is used to create the machine and volume names.
+	namespace instance.Namespace
+
+	// imageMutex serialises the download of cloud images, so that
+	// instances started together share the one copy.
+	imageMutex sync.Mutex
+
+	lock sync.Mutex // lock protects access the following fields.
+	ecfg *environConfig
+}
+
+func newEnviron(
+	provider *environProvider,
+	cloud environs.CloudSpec,
+	cfg *config.Config,
+) (*environ, error) {
+	ecfg, err := newValidConfig(cfg)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid config")
+	}
+
+	namespace, err := instance.NewNamespace(cfg.UUID())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	env := &environ{
+		name:      ecfg.Name(),
+		cloud:     cloud,
+		provider:  provider,
+		ecfg:      ecfg,
+		namespace: namespace,
+	}
+	return env, nil
+}
+
+func (env *environ) withConnection(f func(Connection) error) error {
+	conn, err := env.provider.dial(env.cloud.Endpoint)
+	if err != nil {
+		return errors.Annotate(err, "connecting to libvirt")
+	}
+	defer conn.Close()
+	return f(conn)
+}
+
+func (env *environ) envConfig() *environConfig {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+	return env.ecfg
+}
+
+// Name is part of the environs.Environ interface.
+func (env *environ) Name() string {
+	return env.name
+}
+
+// Provider is part of the environs.Environ interface.
+func (env *environ) Provider() environs.EnvironProvider {
+	return env.provider
+}
+
+// SetConfig is part of the environs.Environ interface.
+func (env *environ) SetConfig(cfg *config.Config) error {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
+	if env.ecfg == nil {
+		return errors.New("cannot set config on uninitialized env")
+	}
+
+	if err := env.ecfg.update(cfg); err != nil {
+		return errors.Annotate(err, "invalid config change")
+	}
+	return nil
+}
+
+// Config is part of the environs.Environ interface.
+func (env *environ) Config() *config.Config {
+	return env.envConfig().Config
+}
+
+// PrepareForBootstrap implements environs.Environ.
+func (env *environ) PrepareForBootstrap(ctx environs.BootstrapContext) error {
+	return env.checkStoragePool()
+}
+
+// Create implements environs.Environ.
+func (env *environ) Create(args environs.CreateParams) error {
+	return env.checkStoragePool()
+}
+
+// checkStoragePool checks that the host can be reached, and has the
+// storage pool named in the model config.
+func (env *environ) checkStoragePool() error {
+	pool := env.envConfig().storagePool()
+	return env.withConnection(func(conn Connection) error {
+		_, err := conn.Volumes(pool, env.namespace.Prefix())
+		return errors.Annotatef(err, "checking storage pool %q", pool)
+	})
+}
+
+// Bootstrap is part of the environs.Environ interface.
+func (env *environ) Bootstrap(
+	ctx environs.BootstrapContext,
+	args environs.BootstrapParams,
+) (*environs.BootstrapResult, error) {
+	return common.Bootstrap(ctx, env, args)
+}
+
+// AdoptResources is part of the Environ interface.
+func (env *environ) AdoptResources(controllerUUID string, fromVersion version.Number) error {
+	return env.withConnection(func(conn Connection) error {
+		domains, err := conn.Domains(env.namespace.Prefix())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, domain := range domains {
+			domainTags := parseDomainTags(domain.Description)
+			if domainTags[tags.JujuController] == controllerUUID {
+				continue
+			}
+			domainTags[tags.JujuController] = controllerUUID
+			if err := conn.SetDomainDescription(domain.Name, formatDomainTags(domainTags)); err != nil {
+				return errors.Annotatef(err, "updating tags of %q", domain.Name)
+			}
+		}
+		return nil
+	})
+}
+
+// Destroy is part of the environs.Environ interface.
+func (env *environ) Destroy() error {
+	return errors.Trace(common.Destroy(env))
+}
+
+// DestroyController implements the Environ interface.
+func (env *environ) DestroyController(controllerUUID string) error {
+	if err := env.Destroy(); err != nil {
+		return errors.Trace(err)
+	}
+	// Destroy the domains of the controller's hosted models, along
+	// with all the volumes of those models.
+	pool := env.envConfig().storagePool()
+	return env.withConnection(func(conn Connection) error {
+		domains, err := conn.Domains(namespacePrefix)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, domain := range domains {
+			if parseDomainTags(domain.Description)[tags.JujuController] != controllerUUID {
+				continue
+			}
+			if err := env.destroyDomain(conn, pool, domain.Name); err != nil {
+				return errors.Trace(err)
+			}
+			prefix := modelPrefix(domain.Name)
+			if prefix == "" {
+				continue
+			}
+			volumes, err := conn.Volumes(pool, prefix)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			for _, volume := range volumes {
+				if err := conn.DeleteVolume(pool, volume.Name); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// namespacePrefix is the prefix of the names of all domains and
+// volumes created by Juju, whatever their model.
+const namespacePrefix = "juju-"
+
+// modelPrefix returns the model-specific namespace prefix of the
+// given domain or volume name, or "" if it is not Juju's.
+func modelPrefix(name string) string {
+	if !strings.HasPrefix(name, namespacePrefix) {
+		return ""
+	}
+	i := strings.Index(name[len(namespacePrefix):], "-")
+	if i < 0 {
+		return ""
+	}
+	return name[:len(namespacePrefix)+i+1]
+}
@@ -0,0 +1,463 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/cloudconfig/providerinit"
+	"github.com/juju/juju/constraints"
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/tools"
+)
+
+const (
+	// defaultMemory is the memory given to instances, in MiB, when
+	// there is no mem constraint.
+	defaultMemory = 1024
+
+	// defaultNICModel is the model of the instances' network
+	// interfaces when the kvm-nic-model model config is not set.
+	defaultNICModel = "virtio"
+
+	// uefiLoader is the path, on the host, of the UEFI firmware that
+	// ARM64 instances boot with.
+	uefiLoader = "/usr/share/AAVMF/AAVMF_CODE.fd"
+)
+
+// rootVolumeName returns the name of the volume holding the root disk
+// of the named instance.
+func rootVolumeName(name string) string {
+	return name + ".qcow2"
+}
+
+// dataSourceVolumeName returns the name of the volume holding the
+// cloud-init data source of the named instance.
+func dataSourceVolumeName(name string) string {
+	return name + "-ds.iso"
+}
+
+// MaintainInstance is specified in the InstanceBroker interface.
+func (*environ) MaintainInstance(args environs.StartInstanceParams) error {
+	return nil
+}
+
+// StartInstance implements environs.InstanceBroker.
+func (env *environ) StartInstance(args environs.StartInstanceParams) (result *environs.StartInstanceResult, err error) {
+	err = env.withConnection(func(conn Connection) error {
+		result, err = env.startInstance(conn, args)
+		return err
+	})
+	if err != nil {
+		args.StatusCallback(status.ProvisioningError, fmt.Sprint(err), nil)
+		return nil, errors.Trace(err)
+	}
+	return result, nil
+}
+
+func (env *environ) startInstance(conn Connection, args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	series := args.Tools.OneSeries()
+	instanceArch, err := selectArch(args.Tools.Arches(), args.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := env.finishInstanceConfig(args, instanceArch); err != nil {
+		return nil, errors.Trace(err)
+	}
+	name, err := env.namespace.Hostname(args.InstanceConfig.MachineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	updateProgress := func(message string) {
+		args.StatusCallback(status.Provisioning, message, nil)
+	}
+	image, err := env.ensureImage(conn, series, instanceArch, updateProgress)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	userData, err := env.userData(args, series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Obtain the final constraints by merging with defaults.
+	cons := args.Constraints
+	minRootDisk := common.MinRootDiskSizeGiB(series) * 1024
+	if cons.RootDisk == nil || *cons.RootDisk < minRootDisk {
+		cons.RootDisk = &minRootDisk
+	}
+
+	updateProgress("creating instance")
+	params, err := env.newDomainParams(name, instanceArch, cons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := env.createDomain(conn, params, image, userData, args.InstanceConfig.Tags); err != nil {
+		if err := env.destroyDomain(conn, env.envConfig().storagePool(), name); err != nil {
+			logger.Errorf("failed to clean up instance %q: %v", name, err)
+		}
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("started instance %q", name)
+
+	cpuCores, mem := params.CPUs(), params.RAM()
+	hw := &instance.HardwareCharacteristics{
+		Arch:     &instanceArch,
+		Mem:      &mem,
+		CpuCores: &cpuCores,
+		CpuPower: cons.CpuPower,
+		RootDisk: cons.RootDisk,
+	}
+	domain := DomainInfo{
+		Name:        name,
+		State:       domainStateRunning,
+		Description: formatDomainTags(args.InstanceConfig.Tags),
+	}
+	return &environs.StartInstanceResult{
+		Instance: newInstance(domain, env),
+		Hardware: hw,
+	}, nil
+}
+
+// selectArch returns the architecture to start an instance with, from
+// those that tools are available for.
+func selectArch(available []string, cons constraints.Value) (string, error) {
+	if cons.HasArch() {
+		for _, a := range available {
+			if a == *cons.Arch {
+				return a, nil
+			}
+		}
+		return "", errors.Errorf("no tools available for architecture %q", *cons.Arch)
+	}
+	for _, a := range available {
+		if a == arch.AMD64 {
+			return a, nil
+		}
+	}
+	if len(available) == 0 {
+		return "", errors.New("no tools available")
+	}
+	return available[0], nil
+}
+
+// FinishInstanceConfig is exported, because it has to be rewritten in
+// external unit tests.
+var FinishInstanceConfig = instancecfg.FinishInstanceConfig
+
+// finishInstanceConfig updates args.InstanceConfig in place, setting up
+// the tools for the instance's architecture, the API, StateServing, and
+// SSH keys information.
+func (env *environ) finishInstanceConfig(args environs.StartInstanceParams, instanceArch string) error {
+	envTools, err := args.Tools.Match(tools.Filter{Arch: instanceArch})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := args.InstanceConfig.SetTools(envTools); err != nil {
+		return errors.Trace(err)
+	}
+	return FinishInstanceConfig(args.InstanceConfig, env.Config())
+}
+
+// userData returns the cloud-init user data of the instance.
+func (env *environ) userData(args environs.StartInstanceParams, series string) ([]byte, error) {
+	cloudcfg, err := cloudinit.New(series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Make sure the hostname is resolvable by adding it to /etc/hosts.
+	cloudcfg.ManageEtcHosts(true)
+	if args.InstanceConfig.Controller != nil {
+		// The controller's provisioner manages the hosts with virsh,
+		// and writes the instances' data source images.
+		cloudcfg.AddPackage(libvirtClientPackage(series))
+		cloudcfg.AddPackage("genisoimage")
+	}
+	userData, err := providerinit.ComposeUserData(args.InstanceConfig, cloudcfg, LibvirtRenderer{})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot make user data")
+	}
+	logger.Debugf("libvirt user data; %d bytes", len(userData))
+	return userData, nil
+}
+
+// libvirtClientPackage returns the name of the package providing virsh
+// on series.
+func libvirtClientPackage(series string) string {
+	switch series {
+	case "trusty", "xenial":
+		return "libvirt-bin"
+	}
+	return "libvirt-clients"
+}
+
+// newDomainParams returns the parameters of the domain for the named
+// instance, relative to the constraints.
+func (env *environ) newDomainParams(name, instanceArch string, cons constraints.Value) (*domainParams, error) {
+	mac, err := generateMACAddress()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := &domainParams{
+		name:     name,
+		arch:     instanceArch,
+		cpus:     1,
+		memory:   defaultMemory,
+		rootDisk: *cons.RootDisk,
+		nicModel: env.Config().KVMNICModel(),
+		interfaces: []kvmlibvirt.InterfaceInfo{interfaceInfo{
+			mac:    mac,
+			parent: env.envConfig().networkBridge(),
+			name:   "eth0",
+		}},
+	}
+	if cons.HasCpuCores() {
+		params.cpus = *cons.CpuCores
+	}
+	if cons.HasCpuPower() {
+		params.cpuShares = *cons.CpuPower * 1024 / 100
+	}
+	if cons.HasMem() {
+		params.memory = *cons.Mem
+	}
+	if params.nicModel == "" {
+		params.nicModel = defaultNICModel
+	}
+	return params, nil
+}
+
+// createDomain creates the volumes of the domain described by params
+// in the storage pool, then defines and starts the domain.
+func (env *environ) createDomain(
+	conn Connection,
+	params *domainParams,
+	image VolumeInfo,
+	userData []byte,
+	domainTags map[string]string,
+) error {
+	pool := env.envConfig().storagePool()
+	root, err := conn.CreateVolume(pool, VolumeParams{
+		Name:        rootVolumeName(params.name),
+		Size:        params.rootDisk,
+		Format:      "qcow2",
+		BackingPath: image.Path,
+	})
+	if err != nil {
+		return errors.Annotate(err, "creating root disk")
+	}
+	dataSource, err := createDataSourceVolume(conn, pool, params.name, userData)
+	if err != nil {
+		return errors.Annotate(err, "creating data source volume")
+	}
+	params.disks = []kvmlibvirt.DiskInfo{
+		diskInfo{source: root.Path, driver: "qcow2"},
+		diskInfo{source: dataSource.Path, driver: "raw"},
+	}
+
+	domain, err := kvmlibvirt.NewDomain(params)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	domain.Description = formatDomainTags(domainTags)
+	if err := conn.DefineDomain(domain); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(conn.StartDomain(params.name))
+}
+
+// AllInstances implements environs.InstanceBroker.
+func (env *environ) AllInstances() (instances []instance.Instance, err error) {
+	err = env.withConnection(func(conn Connection) error {
+		domains, err := conn.Domains(env.namespace.Prefix())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		instances = make([]instance.Instance, len(domains))
+		for i, domain := range domains {
+			instances[i] = newInstance(domain, env)
+		}
+		return nil
+	})
+	return instances, err
+}
+
+// StopInstances implements environs.InstanceBroker.
+func (env *environ) StopInstances(ids ...instance.Id) error {
+	pool := env.envConfig().storagePool()
+	return env.withConnection(func(conn Connection) error {
+		results := make([]error, len(ids))
+		var wg sync.WaitGroup
+		for i, id := range ids {
+			wg.Add(1)
+			go func(i int, id instance.Id) {
+				defer wg.Done()
+				results[i] = env.destroyDomain(conn, pool, string(id))
+			}(i, id)
+		}
+		wg.Wait()
+
+		var errIds []instance.Id
+		var errs []error
+		for i, err := range results {
+			if err != nil {
+				errIds = append(errIds, ids[i])
+				errs = append(errs, err)
+			}
+		}
+		switch len(errs) {
+		case 0:
+			return nil
+		case 1:
+			return errors.Annotatef(errs[0], "failed to stop instance %s", errIds[0])
+		default:
+			return errors.Errorf(
+				"failed to stop instances %s: %s",
+				errIds, errs,
+			)
+		}
+	})
+}
+
+// destroyDomain destroys the named domain, and deletes its root disk
+// and data source volumes. Volumes attached as storage are left for the
+// storage provisioner.
+func (env *environ) destroyDomain(conn Connection, pool, name string) error {
+	if err := conn.DestroyDomain(name); err != nil {
+		return errors.Trace(err)
+	}
+	for _, volume := range []string{rootVolumeName(name), dataSourceVolumeName(name)} {
+		if err := conn.DeleteVolume(pool, volume); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// generateMACAddress returns a random MAC address in the range used by
+// QEMU/KVM.
+func generateMACAddress() (string, error) {
+	buf := make([]byte, 3)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Trace(err)
+	}
+	return fmt.Sprintf("52:54:00:%02x:%02x:%02x", buf[0], buf[1], buf[2]), nil
+}
+
+// domainParams implements the domain parameters of
+// container/kvm/libvirt.NewDomain.
+type domainParams struct {
+	name       string
+	arch       string
+	cpus       uint64
+	cpuShares  uint64
+	memory     uint64
+	rootDisk   uint64
+	nicModel   string
+	disks      []kvmlibvirt.DiskInfo
+	interfaces []kvmlibvirt.InterfaceInfo
+}
+
+// Arch implements libvirt.domainParams.
+func (p *domainParams) Arch() string {
+	return p.arch
+}
+
+// CPUs implements libvirt.domainParams.
+func (p *domainParams) CPUs() uint64 {
+	return p.cpus
+}
+
+// CPUShares implements libvirt.domainParams.
+func (p *domainParams) CPUShares() uint64 {
+	return p.cpuShares
+}
+
+// DiskInfo implements libvirt.domainParams.
+func (p *domainParams) DiskInfo() []kvmlibvirt.DiskInfo {
+	return p.disks
+}
+
+// Host implements libvirt.domainParams.
+func (p *domainParams) Host() string {
+	return p.name
+}
+
+// Loader implements libvirt.domainParams.
+func (p *domainParams) Loader() string {
+	return uefiLoader
+}
+
+// NetworkInfo implements libvirt.domainParams.
+func (p *domainParams) NetworkInfo() []kvmlibvirt.InterfaceInfo {
+	return p.interfaces
+}
+
+// NICModel implements libvirt.domainParams.
+func (p *domainParams) NICModel() string {
+	return p.nicModel
+}
+
+// RAM implements libvirt.domainParams.
+func (p *domainParams) RAM() uint64 {
+	return p.memory
+}
+
+// ValidateDomainParams implements libvirt.domainParams.
+func (p *domainParams) ValidateDomainParams() error {
+	if p.name == "" {
+		return errors.New("missing required name")
+	}
+	if len(p.disks) != 2 {
+		return errors.Errorf("got %d disks, need the root disk and data source", len(p.disks))
+	}
+	return nil
+}
+
+// diskInfo implements libvirt.DiskInfo.
+type diskInfo struct {
+	driver, source string
+}
+
+// Driver implements libvirt.DiskInfo.
+func (d diskInfo) Driver() string {
+	return d.driver
+}
+
+// Source implements libvirt.DiskInfo.
+func (d diskInfo) Source() string {
+	return d.source
+}
+
+// interfaceInfo implements libvirt.InterfaceInfo.
+type interfaceInfo struct {
+	mac, parent, name string
+}
+
+// MACAddress implements libvirt.InterfaceInfo.
+func (i interfaceInfo) MACAddress() string {
+	return i.mac
+}
+
+// ParentInterfaceName implements libvirt.InterfaceInfo.
+func (i interfaceInfo) ParentInterfaceName() string {
+	return i.parent
+}
+
+// InterfaceName implements libvirt.InterfaceInfo.
+func (i interfaceInfo) InterfaceName() string {
+	return i.name
+}
@@ -0,0 +1,248 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"errors"
+	"io/ioutil"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/arch"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/constraints"
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/libvirt"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+)
+
+type environBrokerSuite struct {
+	EnvironFixture
+	statusCallbackStub testing.Stub
+}
+
+var _ = gc.Suite(&environBrokerSuite{})
+
+func (s *environBrokerSuite) SetUpTest(c *gc.C) {
+	s.EnvironFixture.SetUpTest(c)
+	s.statusCallbackStub.ResetCalls()
+	s.PatchValue(libvirt.WriteDataSourceImage, func(path, dir string) error {
+		return ioutil.WriteFile(path, []byte("fake data source"), 0600)
+	})
+}
+
+func (s *environBrokerSuite) createStartInstanceArgs(c *gc.C) environs.StartInstanceParams {
+	var cons constraints.Value
+	instanceConfig, err := instancecfg.NewBootstrapInstanceConfig(
+		coretesting.FakeControllerConfig(), cons, cons, "xenial", "",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.AuthorizedKeys = fakeConfig(c).AuthorizedKeys()
+	instanceConfig.Tags = map[string]string{
+		"juju-is-controller": "true",
+	}
+
+	tools := coretools.List{{
+		Version: version.Binary{
+			Number: version.MustParse("1.2.3"),
+			Arch:   arch.AMD64,
+			Series: "xenial",
+		},
+		URL: "https://example.org",
+	}}
+	err = instanceConfig.SetTools(tools)
+	c.Assert(err, jc.ErrorIsNil)
+
+	return environs.StartInstanceParams{
+		ControllerUUID: instanceConfig.Controller.Config.ControllerUUID(),
+		InstanceConfig: instanceConfig,
+		Tools:          tools,
+		Constraints:    cons,
+		StatusCallback: func(status status.Status, info string, data map[string]interface{}) error {
+			s.statusCallbackStub.AddCall("StatusCallback", status, info, data)
+			return s.statusCallbackStub.NextErr()
+		},
+	}
+}
+
+func (s *environBrokerSuite) TestStartInstance(c *gc.C) {
+	result, err := s.env.StartInstance(s.createStartInstanceArgs(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Instance.Id(), gc.Equals, instance.Id("juju-f75cba-0"))
+	c.Assert(result.Instance.Status().Status, gc.Equals, status.Running)
+
+	amd64, mem, cores, rootDisk := arch.AMD64, uint64(1024), uint64(1), uint64(8192)
+	c.Assert(result.Hardware, jc.DeepEquals, &instance.HardwareCharacteristics{
+		Arch:     &amd64,
+		Mem:      &mem,
+		CpuCores: &cores,
+		RootDisk: &rootDisk,
+	})
+
+	s.dialStub.CheckCall(c, 0, "Dial", "qemu+ssh://ubuntu@10.0.0.1/system")
+	s.findImageStub.CheckCall(c, 0, "FindImage", "amd64", "xenial", "disk1.img")
+	s.conn.CheckCallNames(c,
+		"Volume", "CreateVolume", "UploadVolume", // image
+		"CreateVolume",                 // root disk
+		"CreateVolume", "UploadVolume", // data source
+		"DefineDomain", "StartDomain", "Close",
+	)
+	calls := s.conn.Calls()
+	c.Assert(calls[0].Args, jc.DeepEquals, []interface{}{"default", "juju-image-xenial-amd64.img"})
+	c.Assert(calls[1].Args[1], jc.DeepEquals, libvirt.VolumeParams{
+		Name:   "juju-image-xenial-amd64.img",
+		Size:   1,
+		Format: "qcow2",
+	})
+	c.Assert(calls[3].Args[1], jc.DeepEquals, libvirt.VolumeParams{
+		Name:        "juju-f75cba-0.qcow2",
+		Size:        8192,
+		Format:      "qcow2",
+		BackingPath: "/var/lib/libvirt/images/juju-image-xenial-amd64.img",
+	})
+	c.Assert(calls[4].Args[1], jc.DeepEquals, libvirt.VolumeParams{
+		Name:   "juju-f75cba-0-ds.iso",
+		Size:   1,
+		Format: "raw",
+	})
+
+	domain := calls[6].Args[0].(kvmlibvirt.Domain)
+	c.Assert(domain.Name, gc.Equals, "juju-f75cba-0")
+	c.Assert(domain.Description, gc.Equals, `{"juju-is-controller":"true"}`)
+	c.Assert(domain.Memory.Text, gc.Equals, uint64(1024))
+	c.Assert(domain.Disk, gc.HasLen, 2)
+	c.Assert(domain.Disk[0].Source.File, gc.Equals, "/var/lib/libvirt/images/juju-f75cba-0.qcow2")
+	c.Assert(domain.Disk[1].Source.File, gc.Equals, "/var/lib/libvirt/images/juju-f75cba-0-ds.iso")
+	c.Assert(domain.Interface, gc.HasLen, 1)
+	c.Assert(domain.Interface[0].Source.Bridge, gc.Equals, "br0")
+	c.Assert(domain.Interface[0].Model.Type, gc.Equals, "virtio")
+	s.conn.CheckCall(c, 7, "StartDomain", "juju-f75cba-0")
+
+	s.statusCallbackStub.CheckCallNames(c, "StatusCallback", "StatusCallback", "StatusCallback")
+	c.Assert(s.statusCallbackStub.Calls()[1].Args[1], gc.Equals, `uploading image to storage pool "default"`)
+	c.Assert(s.statusCallbackStub.Calls()[2].Args[1], gc.Equals, "creating instance")
+}
+
+func (s *environBrokerSuite) TestStartInstanceExistingImage(c *gc.C) {
+	s.conn.volumes["juju-image-xenial-amd64.img"] = libvirt.VolumeInfo{
+		Name: "juju-image-xenial-amd64.img",
+		Path: "/pool/juju-image-xenial-amd64.img",
+	}
+	_, err := s.env.StartInstance(s.createStartInstanceArgs(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.findImageStub.CheckNoCalls(c)
+	s.conn.CheckCallNames(c,
+		"Volume", "CreateVolume", "CreateVolume", "UploadVolume",
+		"DefineDomain", "StartDomain", "Close",
+	)
+	c.Assert(s.conn.Calls()[1].Args[1].(libvirt.VolumeParams).BackingPath, gc.Equals, "/pool/juju-image-xenial-amd64.img")
+}
+
+func (s *environBrokerSuite) TestStartInstanceConstraints(c *gc.C) {
+	args := s.createStartInstanceArgs(c)
+	args.Constraints = constraints.MustParse("cores=4 cpu-power=50 mem=4G root-disk=20G")
+	result, err := s.env.StartInstance(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(*result.Hardware.CpuCores, gc.Equals, uint64(4))
+	c.Assert(*result.Hardware.Mem, gc.Equals, uint64(4096))
+	c.Assert(*result.Hardware.RootDisk, gc.Equals, uint64(20480))
+
+	calls := s.conn.Calls()
+	c.Assert(calls[3].Args[1].(libvirt.VolumeParams).Size, gc.Equals, uint64(20480))
+	domain := calls[6].Args[0].(kvmlibvirt.Domain)
+	c.Assert(domain.VCPU, gc.Equals, uint64(4))
+	c.Assert(domain.Memory.Text, gc.Equals, uint64(4096))
+	c.Assert(domain.CPUTune, jc.DeepEquals, &kvmlibvirt.CPUTune{Shares: 512})
+}
+
+func (s *environBrokerSuite) TestStartInstanceDefineDomainFails(c *gc.C) {
+	s.conn.SetErrors(nil, nil, nil, nil, nil, nil, errors.New("boom"))
+	_, err := s.env.StartInstance(s.createStartInstanceArgs(c))
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	s.conn.CheckCallNames(c,
+		"Volume", "CreateVolume", "UploadVolume",
+		"CreateVolume", "CreateVolume", "UploadVolume",
+		"DefineDomain",
+		"DestroyDomain", "DeleteVolume", "DeleteVolume",
+		"Close",
+	)
+	s.conn.CheckCall(c, 7, "DestroyDomain", "juju-f75cba-0")
+	s.conn.CheckCall(c, 8, "DeleteVolume", "default", "juju-f75cba-0.qcow2")
+	s.conn.CheckCall(c, 9, "DeleteVolume", "default", "juju-f75cba-0-ds.iso")
+	s.statusCallbackStub.CheckCall(c, 3, "StatusCallback", status.ProvisioningError, "boom", map[string]interface{}(nil))
+}
+
+func (s *environBrokerSuite) TestStartInstanceDialFails(c *gc.C) {
+	s.dialStub.SetErrors(errors.New("permission denied"))
+	_, err := s.env.StartInstance(s.createStartInstanceArgs(c))
+	c.Assert(err, gc.ErrorMatches, "connecting to libvirt: permission denied")
+	s.conn.CheckNoCalls(c)
+}
+
+func (s *environBrokerSuite) TestStopInstances(c *gc.C) {
+	err := s.env.StopInstances("juju-f75cba-0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.conn.CheckCalls(c, []testing.StubCall{
+		{"DestroyDomain", []interface{}{"juju-f75cba-0"}},
+		{"DeleteVolume", []interface{}{"default", "juju-f75cba-0.qcow2"}},
+		{"DeleteVolume", []interface{}{"default", "juju-f75cba-0-ds.iso"}},
+		{"Close", nil},
+	})
+}
+
+func (s *environBrokerSuite) TestStopInstancesError(c *gc.C) {
+	s.conn.SetErrors(errors.New("boom"))
+	err := s.env.StopInstances("juju-f75cba-0")
+	c.Assert(err, gc.ErrorMatches, "failed to stop instance juju-f75cba-0: boom")
+}
+
+func (s *environBrokerSuite) TestAllInstances(c *gc.C) {
+	s.conn.domains = []libvirt.DomainInfo{
+		{Name: "juju-f75cba-0", State: "running"},
+		{Name: "juju-f75cba-1", State: "shut off"},
+	}
+	instances, err := s.env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 2)
+	c.Assert(instances[0].Id(), gc.Equals, instance.Id("juju-f75cba-0"))
+	c.Assert(instances[0].Status(), jc.DeepEquals, instance.InstanceStatus{
+		Status:  status.Running,
+		Message: "running",
+	})
+	c.Assert(instances[1].Id(), gc.Equals, instance.Id("juju-f75cba-1"))
+	c.Assert(instances[1].Status(), jc.DeepEquals, instance.InstanceStatus{
+		Status:  status.Empty,
+		Message: "shut off",
+	})
+	s.conn.CheckCall(c, 0, "Domains", "juju-f75cba-")
+}
+
+func (s *environBrokerSuite) TestControllerInstances(c *gc.C) {
+	s.conn.domains = []libvirt.DomainInfo{{
+		Name:        "juju-f75cba-0",
+		Description: `{"juju-controller-uuid":"foo","juju-is-controller":"true"}`,
+	}, {
+		Name:        "juju-f75cba-1",
+		Description: `{"juju-controller-uuid":"foo"}`,
+	}, {
+		Name:        "juju-f75cba-2",
+		Description: `{"juju-controller-uuid":"bar","juju-is-controller":"true"}`,
+	}}
+	ids, err := s.env.ControllerInstances("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []instance.Id{"juju-f75cba-0"})
+
+	_, err = s.env.ControllerInstances("baz")
+	c.Assert(err, gc.Equals, environs.ErrNotBootstrapped)
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	envtesting "github.com/juju/juju/environs/testing"
+	"github.com/juju/juju/provider/libvirt"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type environSuite struct {
+	EnvironFixture
+}
+
+var _ = gc.Suite(&environSuite{})
+
+func (s *environSuite) TestPrepareForBootstrap(c *gc.C) {
+	err := s.env.PrepareForBootstrap(envtesting.BootstrapContext(c))
+	c.Assert(err, jc.ErrorIsNil)
+	s.conn.CheckCallNames(c, "Volumes", "Close")
+	s.conn.CheckCall(c, 0, "Volumes", "default", "juju-f75cba-")
+}
+
+func (s *environSuite) TestPrepareForBootstrapMissingPool(c *gc.C) {
+	s.conn.SetErrors(errors.New("Storage pool not found"))
+	err := s.env.PrepareForBootstrap(envtesting.BootstrapContext(c))
+	c.Assert(err, gc.ErrorMatches, `checking storage pool "default": Storage pool not found`)
+}
+
+func (s *environSuite) TestCreate(c *gc.C) {
+	err := s.env.Create(environs.CreateParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.conn.CheckCallNames(c, "Volumes", "Close")
+}
+
+func (s *environSuite) TestAdoptResources(c *gc.C) {
+	s.conn.domains = []libvirt.DomainInfo{{
+		Name:        "juju-f75cba-0",
+		Description: `{"juju-controller-uuid":"old","juju-model-uuid":"2d02eeac-9dbb-11e4-89d3-123b93f75cba"}`,
+	}, {
+		Name:        "juju-f75cba-1",
+		Description: `{"juju-controller-uuid":"new"}`,
+	}}
+	err := s.env.AdoptResources("new", coretesting.FakeVersionNumber)
+	c.Assert(err, jc.ErrorIsNil)
+	s.conn.CheckCallNames(c, "Domains", "SetDomainDescription", "Close")
+	s.conn.CheckCall(c, 1, "SetDomainDescription", "juju-f75cba-0",
+		`{"juju-controller-uuid":"new","juju-model-uuid":"2d02eeac-9dbb-11e4-89d3-123b93f75cba"}`,
+	)
+}
+
+func (s *environSuite) TestDestroyController(c *gc.C) {
+	s.conn.domains = []libvirt.DomainInfo{{
+		Name:        "juju-abcdef-0",
+		Description: `{"juju-controller-uuid":"foo"}`,
+	}, {
+		Name:        "juju-fedcba-0",
+		Description: `{"juju-controller-uuid":"bar"}`,
+	}}
+	s.conn.volumes["juju-abcdef-volume-0.qcow2"] = libvirt.VolumeInfo{
+		Name: "juju-abcdef-volume-0.qcow2",
+	}
+	err := s.env.DestroyController("foo")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only the domains and volumes of the controller's models are
+	// destroyed.
+	var destroyed []string
+	for _, call := range s.conn.Calls() {
+		switch call.FuncName {
+		case "DestroyDomain":
+			destroyed = append(destroyed, call.Args[0].(string))
+		}
+	}
+	c.Assert(destroyed, jc.DeepEquals, []string{"juju-abcdef-0"})
+	s.conn.CheckCall(c, len(s.conn.Calls())-3, "Volumes", "default", "juju-abcdef-")
+	s.conn.CheckCall(c, len(s.conn.Calls())-2, "DeleteVolume", "default", "juju-abcdef-volume-0.qcow2")
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/imagedownloads"
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/provider/libvirt"
+)
+
+const fakeImageContents = "fake cloud image"
+
+type ProviderFixture struct {
+	testing.IsolationSuite
+	dialStub      testing.Stub
+	findImageStub testing.Stub
+	conn          *mockConnection
+	imageServer   *httptest.Server
+	provider      environs.EnvironProvider
+}
+
+func (s *ProviderFixture) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dialStub.ResetCalls()
+	s.findImageStub.ResetCalls()
+	s.conn = &mockConnection{
+		volumes: make(map[string]libvirt.VolumeInfo),
+	}
+	s.imageServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, fakeImageContents)
+	}))
+	s.AddCleanup(func(*gc.C) {
+		s.imageServer.Close()
+	})
+	s.provider = libvirt.NewEnvironProvider(libvirt.EnvironProviderConfig{
+		Dial:      newMockDialFunc(&s.dialStub, s.conn),
+		FindImage: s.findImage,
+	})
+}
+
+func (s *ProviderFixture) findImage(arch, series, ftype string, src func() simplestreams.DataSource) (*imagedownloads.Metadata, error) {
+	s.findImageStub.AddCall("FindImage", arch, series, ftype)
+	if err := s.findImageStub.NextErr(); err != nil {
+		return nil, err
+	}
+	return &imagedownloads.Metadata{
+		Arch:    arch,
+		Release: series,
+		FType:   ftype,
+		BaseURL: s.imageServer.URL,
+		Path:    fmt.Sprintf("server/releases/%s/%s-server-cloudimg-%s-%s", series, series, arch, ftype),
+		SHA256:  fmt.Sprintf("%x", sha256.Sum256([]byte(fakeImageContents))),
+	}, nil
+}
+
+type EnvironFixture struct {
+	ProviderFixture
+	env environs.Environ
+}
+
+func (s *EnvironFixture) SetUpTest(c *gc.C) {
+	s.ProviderFixture.SetUpTest(c)
+	env, err := s.provider.Open(environs.OpenParams{
+		Cloud:  fakeCloudSpec(),
+		Config: fakeConfig(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.env = env
+}
@@ -0,0 +1,140 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/arch"
+
+	"github.com/juju/juju/environs/imagedownloads"
+	"github.com/juju/juju/environs/simplestreams"
+)
+
+const (
+	// biosImageType is the simplestreams file type of the images that
+	// instances booting with a legacy BIOS are started from.
+	biosImageType = "disk1.img"
+
+	// uefiImageType is the simplestreams file type of the images that
+	// instances booting with UEFI, i.e. ARM64 ones, are started from.
+	uefiImageType = "uefi1.img"
+)
+
+// imageVolumeName returns the name of the volume holding the cloud image
+// for series and architecture. The volume is shared by the instances of
+// all models using the storage pool.
+func imageVolumeName(series, arch string) string {
+	return fmt.Sprintf("%simage-%s-%s.img", namespacePrefix, series, arch)
+}
+
+// imageSource returns the simplestreams source to find cloud images in,
+// or nil to use the default source.
+func (env *environ) imageSource() func() simplestreams.DataSource {
+	baseURL, ok := env.Config().ImageMetadataURL()
+	if !ok {
+		return nil
+	}
+	return func() simplestreams.DataSource {
+		return imagedownloads.NewDataSource(baseURL)
+	}
+}
+
+// ensureImage returns the volume holding the cloud image for the series
+// and architecture, downloading the image into the storage pool if it is
+// not there yet.
+func (env *environ) ensureImage(
+	conn Connection,
+	series, imageArch string,
+	updateProgress func(string),
+) (VolumeInfo, error) {
+	env.imageMutex.Lock()
+	defer env.imageMutex.Unlock()
+
+	pool := env.envConfig().storagePool()
+	name := imageVolumeName(series, imageArch)
+	volume, err := conn.Volume(pool, name)
+	if err == nil {
+		return volume, nil
+	} else if !errors.IsNotFound(err) {
+		return VolumeInfo{}, errors.Trace(err)
+	}
+
+	ftype := biosImageType
+	if imageArch == arch.ARM64 {
+		ftype = uefiImageType
+	}
+	metadata, err := env.provider.findImage(imageArch, series, ftype, env.imageSource())
+	if err != nil {
+		return VolumeInfo{}, errors.Annotatef(err, "finding %s image for %s/%s", ftype, series, imageArch)
+	}
+	imageURL, err := metadata.DownloadURL()
+	if err != nil {
+		return VolumeInfo{}, errors.Trace(err)
+	}
+
+	updateProgress(fmt.Sprintf("downloading image %s", imageURL))
+	path, err := downloadImage(imageURL.String(), metadata.SHA256, env.Config().SSLHostnameVerification())
+	if err != nil {
+		return VolumeInfo{}, errors.Annotatef(err, "downloading image %s", imageURL)
+	}
+	defer os.Remove(path)
+
+	updateProgress(fmt.Sprintf("uploading image to storage pool %q", pool))
+	return uploadVolume(conn, pool, name, "qcow2", path)
+}
+
+// imageDownloadTimeout is how long downloading a cloud image may take.
+const imageDownloadTimeout = 30 * time.Minute
+
+// downloadImage downloads the image at imageURL to a temporary file,
+// verifying its SHA256 checksum, and returns the path of the file. The
+// download goes through the proxy set in the environment, which the
+// agents keep up to date with the model's proxy settings.
+func downloadImage(imageURL, sha256sum string, verifyHostnames bool) (_ string, err error) {
+	hostnameVerification := utils.NoVerifySSLHostnames
+	if verifyHostnames {
+		hostnameVerification = utils.VerifySSLHostnames
+	}
+	client := utils.GetHTTPClient(hostnameVerification)
+	client.Timeout = imageDownloadTimeout
+	resp, err := client.Get(imageURL)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("got %s", resp.Status)
+	}
+
+	f, err := ioutil.TempFile("", "juju-image-")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = errors.Trace(closeErr)
+		}
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), resp.Body); err != nil {
+		return "", errors.Trace(err)
+	}
+	if actual := fmt.Sprintf("%x", hash.Sum(nil)); actual != sha256sum {
+		return "", errors.Errorf("SHA256 mismatch: expected %s, got %s", sha256sum, actual)
+	}
+	return f.Name(), nil
+}
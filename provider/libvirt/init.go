@@ -0,0 +1,20 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/imagedownloads"
+)
+
+const (
+	providerType = "libvirt"
+)
+
+func init() {
+	environs.RegisterProvider(providerType, NewEnvironProvider(EnvironProviderConfig{
+		Dial:      dialVirsh,
+		FindImage: imagedownloads.One,
+	}))
+}
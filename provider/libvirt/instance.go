@@ -0,0 +1,85 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"encoding/json"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/status"
+)
+
+type environInstance struct {
+	domain DomainInfo
+	env    *environ
+}
+
+var _ instance.Instance = (*environInstance)(nil)
+
+func newInstance(domain DomainInfo, env *environ) *environInstance {
+	return &environInstance{
+		domain: domain,
+		env:    env,
+	}
+}
+
+// Id implements instance.Instance.
+func (inst *environInstance) Id() instance.Id {
+	return instance.Id(inst.domain.Name)
+}
+
+// Status implements instance.Instance.
+func (inst *environInstance) Status() instance.InstanceStatus {
+	instanceStatus := instance.InstanceStatus{
+		Status:  status.Empty,
+		Message: inst.domain.State,
+	}
+	switch inst.domain.State {
+	case domainStateRunning:
+		instanceStatus.Status = status.Running
+	}
+	return instanceStatus
+}
+
+// Addresses implements instance.Instance.
+func (inst *environInstance) Addresses() ([]network.Address, error) {
+	var addresses []network.Address
+	err := inst.env.withConnection(func(conn Connection) error {
+		values, err := conn.DomainAddresses(inst.domain.Name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		addresses = network.NewAddresses(values...)
+		return nil
+	})
+	return addresses, errors.Trace(err)
+}
+
+// parseDomainTags returns the tags recorded in a domain's description,
+// or an empty map if there are none.
+func parseDomainTags(description string) map[string]string {
+	domainTags := make(map[string]string)
+	if description == "" {
+		return domainTags
+	}
+	if err := json.Unmarshal([]byte(description), &domainTags); err != nil {
+		logger.Debugf("ignoring description %q: %v", description, err)
+		return make(map[string]string)
+	}
+	return domainTags
+}
+
+// formatDomainTags returns the description of a domain recording the
+// given tags.
+func formatDomainTags(domainTags map[string]string) string {
+	data, err := json.Marshal(domainTags)
+	if err != nil {
+		// This cannot happen: string maps always marshal.
+		panic(err)
+	}
+	return string(data)
+}
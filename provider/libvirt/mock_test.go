@@ -0,0 +1,162 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/provider/libvirt"
+)
+
+func newMockDialFunc(dialStub *testing.Stub, conn libvirt.Connection) libvirt.DialFunc {
+	return func(uri string) (libvirt.Connection, error) {
+		dialStub.AddCall("Dial", uri)
+		if err := dialStub.NextErr(); err != nil {
+			return nil, err
+		}
+		return conn, nil
+	}
+}
+
+type mockConnection struct {
+	// mu guards testing.Stub access, to ensure that the recorded
+	// method calls correspond to the errors returned.
+	mu sync.Mutex
+	testing.Stub
+
+	domains   []libvirt.DomainInfo
+	addresses []string
+	volumes   map[string]libvirt.VolumeInfo
+}
+
+func (c *mockConnection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Close")
+	return c.NextErr()
+}
+
+func (c *mockConnection) DefineDomain(domain kvmlibvirt.Domain) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DefineDomain", domain)
+	return c.NextErr()
+}
+
+func (c *mockConnection) StartDomain(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "StartDomain", name)
+	return c.NextErr()
+}
+
+func (c *mockConnection) DestroyDomain(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DestroyDomain", name)
+	return c.NextErr()
+}
+
+func (c *mockConnection) SetDomainDescription(name, description string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "SetDomainDescription", name, description)
+	return c.NextErr()
+}
+
+func (c *mockConnection) Domains(prefix string) ([]libvirt.DomainInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Domains", prefix)
+	var domains []libvirt.DomainInfo
+	for _, domain := range c.domains {
+		if strings.HasPrefix(domain.Name, prefix) {
+			domains = append(domains, domain)
+		}
+	}
+	return domains, c.NextErr()
+}
+
+func (c *mockConnection) DomainAddresses(name string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DomainAddresses", name)
+	return c.addresses, c.NextErr()
+}
+
+func (c *mockConnection) Volume(pool, name string) (libvirt.VolumeInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Volume", pool, name)
+	if err := c.NextErr(); err != nil {
+		return libvirt.VolumeInfo{}, err
+	}
+	volume, ok := c.volumes[name]
+	if !ok {
+		return libvirt.VolumeInfo{}, errors.NotFoundf("volume %q", name)
+	}
+	return volume, nil
+}
+
+func (c *mockConnection) Volumes(pool, prefix string) ([]libvirt.VolumeInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Volumes", pool, prefix)
+	var volumes []libvirt.VolumeInfo
+	for name, volume := range c.volumes {
+		if strings.HasPrefix(name, prefix) {
+			volumes = append(volumes, volume)
+		}
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Name < volumes[j].Name
+	})
+	return volumes, c.NextErr()
+}
+
+func (c *mockConnection) CreateVolume(pool string, params libvirt.VolumeParams) (libvirt.VolumeInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "CreateVolume", pool, params)
+	return libvirt.VolumeInfo{
+		Name: params.Name,
+		Path: path.Join("/var/lib/libvirt/images", params.Name),
+		Size: params.Size,
+	}, c.NextErr()
+}
+
+func (c *mockConnection) UploadVolume(pool, name, path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "UploadVolume", pool, name, path)
+	return c.NextErr()
+}
+
+func (c *mockConnection) DeleteVolume(pool, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DeleteVolume", pool, name)
+	return c.NextErr()
+}
+
+func (c *mockConnection) AttachDisk(domain, path, serial string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "AttachDisk", domain, path, serial)
+	return c.NextErr()
+}
+
+func (c *mockConnection) DetachDisk(domain, path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DetachDisk", domain, path)
+	return c.NextErr()
+}
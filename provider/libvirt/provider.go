@@ -0,0 +1,194 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package libvirt implements a provider for clouds made of plain libvirt
+// hypervisors. Each region of the cloud is a libvirt host, addressed by its
+// libvirt URI, e.g. qemu+ssh://ubuntu@10.0.0.1/system. Instances are KVM
+// domains booted from Ubuntu cloud images, with their disks held in a
+// libvirt storage pool on the host.
+//
+// The provider manages the hosts with virsh, and writes the instances'
+// cloud-init data source images with genisoimage. Controllers install
+// both when they are started; the client bootstrapping a controller
+// needs the libvirt-clients (libvirt-bin on trusty and xenial) and
+// genisoimage packages installed.
+package libvirt
+
+import (
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/juju/jsonschema"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/imagedownloads"
+	"github.com/juju/juju/environs/simplestreams"
+)
+
+var logger = loggo.GetLogger("juju.provider.libvirt")
+
+// DialFunc is a function type for connecting to the libvirt daemon
+// at the given URI.
+type DialFunc func(uri string) (Connection, error)
+
+// FindImageFunc is a function type for finding the cloud image for
+// the given architecture, series and file type, as found in the
+// "image-downloads" simplestreams data. If src is nil, the default
+// cloud images source is used.
+type FindImageFunc func(arch, series, ftype string, src func() simplestreams.DataSource) (*imagedownloads.Metadata, error)
+
+type environProvider struct {
+	environProviderCredentials
+	dial      DialFunc
+	findImage FindImageFunc
+}
+
+// EnvironProviderConfig contains configuration for the EnvironProvider.
+type EnvironProviderConfig struct {
+	// Dial is a function used for connecting to libvirt hosts.
+	Dial DialFunc
+
+	// FindImage is a function used for finding the cloud images
+	// that instances are booted from.
+	FindImage FindImageFunc
+}
+
+// NewEnvironProvider returns a new environs.EnvironProvider that will
+// connect to libvirt hosts with the given dial function.
+func NewEnvironProvider(config EnvironProviderConfig) environs.EnvironProvider {
+	return &environProvider{
+		dial:      config.Dial,
+		findImage: config.FindImage,
+	}
+}
+
+// Version implements environs.EnvironProvider.
+func (p *environProvider) Version() int {
+	return 0
+}
+
+// Open implements environs.EnvironProvider.
+func (p *environProvider) Open(args environs.OpenParams) (environs.Environ, error) {
+	if err := validateCloudSpec(args.Cloud); err != nil {
+		return nil, errors.Annotate(err, "validating cloud spec")
+	}
+	env, err := newEnviron(p, args.Cloud, args.Config)
+	return env, errors.Trace(err)
+}
+
+var cloudSchema = &jsonschema.Schema{
+	Type:     []jsonschema.Type{jsonschema.ObjectType},
+	Required: []string{cloud.EndpointKey, cloud.AuthTypesKey},
+	Order:    []string{cloud.EndpointKey, cloud.AuthTypesKey, cloud.RegionsKey},
+	Properties: map[string]*jsonschema.Schema{
+		cloud.EndpointKey: {
+			Singular: "the libvirt URI of the host",
+			Type:     []jsonschema.Type{jsonschema.StringType},
+		},
+		cloud.AuthTypesKey: &jsonschema.Schema{
+			// don't need a prompt, since there's only one choice.
+			Type: []jsonschema.Type{jsonschema.ArrayType},
+			Enum: []interface{}{[]string{string(cloud.EmptyAuthType)}},
+		},
+		cloud.RegionsKey: {
+			Type:     []jsonschema.Type{jsonschema.ObjectType},
+			Singular: "host",
+			Plural:   "hosts",
+			AdditionalProperties: &jsonschema.Schema{
+				Type:     []jsonschema.Type{jsonschema.ObjectType},
+				Required: []string{cloud.EndpointKey},
+				Properties: map[string]*jsonschema.Schema{
+					cloud.EndpointKey: &jsonschema.Schema{
+						Singular: "the libvirt URI of the host",
+						Type:     []jsonschema.Type{jsonschema.StringType},
+					},
+				},
+			},
+		},
+	},
+}
+
+// CloudSchema returns the schema for adding new clouds of this type.
+func (p *environProvider) CloudSchema() *jsonschema.Schema {
+	return cloudSchema
+}
+
+// Ping tests the connection to the cloud, to verify the endpoint is valid.
+func (p *environProvider) Ping(endpoint string) error {
+	if err := validateEndpoint(endpoint); err != nil {
+		return errors.Trace(err)
+	}
+	conn, err := p.dial(endpoint)
+	if err != nil {
+		logger.Errorf("unexpected error connecting to libvirt: %v", err)
+		return errors.Errorf("no libvirt daemon available at %s", endpoint)
+	}
+	return conn.Close()
+}
+
+// PrepareConfig implements environs.EnvironProvider.
+func (p *environProvider) PrepareConfig(args environs.PrepareConfigParams) (*config.Config, error) {
+	if err := validateCloudSpec(args.Cloud); err != nil {
+		return nil, errors.Annotate(err, "validating cloud spec")
+	}
+	return args.Config, nil
+}
+
+// Validate implements environs.EnvironProvider.
+func (*environProvider) Validate(cfg, old *config.Config) (valid *config.Config, err error) {
+	if old == nil {
+		ecfg, err := newValidConfig(cfg)
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid config")
+		}
+		return ecfg.Config, nil
+	}
+
+	ecfg, err := newValidConfig(old)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid base config")
+	}
+
+	if err := ecfg.update(cfg); err != nil {
+		return nil, errors.Annotate(err, "invalid config change")
+	}
+
+	return ecfg.Config, nil
+}
+
+func validateCloudSpec(spec environs.CloudSpec) error {
+	if err := spec.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateEndpoint(spec.Endpoint); err != nil {
+		return errors.Annotate(err, "validating endpoint")
+	}
+	if spec.Credential == nil {
+		return errors.NotValidf("missing credential")
+	}
+	if authType := spec.Credential.AuthType(); authType != cloud.EmptyAuthType {
+		return errors.NotSupportedf("%q auth-type", authType)
+	}
+	return nil
+}
+
+// validateEndpoint checks that endpoint is a libvirt URI for a
+// QEMU/KVM hypervisor.
+func validateEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return errors.New("libvirt URI not specified")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return errors.NotValidf("libvirt URI %q", endpoint)
+	}
+	switch u.Scheme {
+	case "qemu", "qemu+ssh", "qemu+tcp", "qemu+tls":
+	default:
+		return errors.NotValidf("libvirt URI %q: expected a qemu, qemu+ssh, qemu+tcp or qemu+tls URI", endpoint)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+)
+
+type providerSuite struct {
+	ProviderFixture
+}
+
+var _ = gc.Suite(&providerSuite{})
+
+func (s *providerSuite) TestRegistered(c *gc.C) {
+	provider, err := environs.Provider("libvirt")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(provider, gc.NotNil)
+}
+
+func (s *providerSuite) TestOpen(c *gc.C) {
+	env, err := s.provider.Open(environs.OpenParams{
+		Cloud:  fakeCloudSpec(),
+		Config: fakeConfig(c),
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(env.Config().Name(), gc.Equals, "testenv")
+	// Opening an environ does not connect to the host.
+	s.dialStub.CheckNoCalls(c)
+}
+
+func (s *providerSuite) TestOpenMissingCredential(c *gc.C) {
+	spec := fakeCloudSpec()
+	spec.Credential = nil
+	s.testOpenError(c, spec, `validating cloud spec: missing credential not valid`)
+}
+
+func (s *providerSuite) TestOpenUnsupportedCredential(c *gc.C) {
+	credential := cloud.NewCredential(cloud.UserPassAuthType, map[string]string{})
+	spec := fakeCloudSpec()
+	spec.Credential = &credential
+	s.testOpenError(c, spec, `validating cloud spec: "userpass" auth-type not supported`)
+}
+
+func (s *providerSuite) TestOpenInvalidEndpoint(c *gc.C) {
+	spec := fakeCloudSpec()
+	spec.Endpoint = "xen:///system"
+	s.testOpenError(c, spec, `validating cloud spec: validating endpoint: libvirt URI "xen:///system": expected a qemu, qemu\+ssh, qemu\+tcp or qemu\+tls URI not valid`)
+}
+
+func (s *providerSuite) testOpenError(c *gc.C, spec environs.CloudSpec, expect string) {
+	_, err := s.provider.Open(environs.OpenParams{
+		Cloud:  spec,
+		Config: fakeConfig(c),
+	})
+	c.Assert(err, gc.ErrorMatches, expect)
+}
+
+func (s *providerSuite) TestPrepareConfig(c *gc.C) {
+	cfg, err := s.provider.PrepareConfig(environs.PrepareConfigParams{
+		Config: fakeConfig(c),
+		Cloud:  fakeCloudSpec(),
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(cfg, gc.NotNil)
+}
+
+func (s *providerSuite) TestCredentialSchemas(c *gc.C) {
+	schemas := s.provider.CredentialSchemas()
+	c.Assert(schemas, gc.HasLen, 1)
+	_, ok := schemas[cloud.EmptyAuthType]
+	c.Assert(ok, jc.IsTrue)
+}
+
+func (s *providerSuite) TestPing(c *gc.C) {
+	err := s.provider.Ping("qemu+ssh://ubuntu@10.0.0.1/system")
+	c.Assert(err, jc.ErrorIsNil)
+	s.dialStub.CheckCall(c, 0, "Dial", "qemu+ssh://ubuntu@10.0.0.1/system")
+	s.conn.CheckCallNames(c, "Close")
+}
+
+func (s *providerSuite) TestPingFailure(c *gc.C) {
+	s.dialStub.SetErrors(errors.New("permission denied"))
+	err := s.provider.Ping("qemu+ssh://ubuntu@10.0.0.1/system")
+	c.Assert(err, gc.ErrorMatches, "no libvirt daemon available at qemu\\+ssh://ubuntu@10.0.0.1/system")
+}
+
+func (s *providerSuite) TestPingInvalidEndpoint(c *gc.C) {
+	err := s.provider.Ping("")
+	c.Assert(err, gc.ErrorMatches, "libvirt URI not specified")
+	s.dialStub.CheckNoCalls(c)
+}
@@ -0,0 +1,288 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/storage"
+)
+
+const (
+	storageProviderType = storage.ProviderType("libvirt")
+
+	// storagePoolAttr is the storage pool config attribute naming the
+	// libvirt storage pool to create volumes in. It defaults to the
+	// model's libvirt-pool.
+	storagePoolAttr = "pool"
+)
+
+var storageConfigFields = schema.Fields{
+	storagePoolAttr: schema.String(),
+}
+
+var storageConfigChecker = schema.FieldMap(
+	storageConfigFields,
+	schema.Defaults{
+		storagePoolAttr: schema.Omit,
+	},
+)
+
+// StorageProviderTypes implements storage.ProviderRegistry.
+func (env *environ) StorageProviderTypes() ([]storage.ProviderType, error) {
+	return []storage.ProviderType{storageProviderType}, nil
+}
+
+// StorageProvider implements storage.ProviderRegistry.
+func (env *environ) StorageProvider(t storage.ProviderType) (storage.Provider, error) {
+	if t == storageProviderType {
+		return &storageProvider{env}, nil
+	}
+	return nil, errors.NotFoundf("storage provider %q", t)
+}
+
+// storageProvider creates volumes in the libvirt storage pools of the
+// host, and attaches them to instances as virtio disks.
+type storageProvider struct {
+	env *environ
+}
+
+var _ storage.Provider = (*storageProvider)(nil)
+
+// ValidateConfig is part of the storage.Provider interface.
+func (p *storageProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := storageConfigChecker.Coerce(cfg.Attrs(), nil)
+	return errors.Annotate(err, "validating libvirt storage config")
+}
+
+// Supports is part of the storage.Provider interface.
+func (p *storageProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is part of the storage.Provider interface.
+func (p *storageProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is part of the storage.Provider interface.
+func (p *storageProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is part of the storage.Provider interface.
+func (p *storageProvider) Releasable() bool {
+	// Volumes have no tags recording the model they belong to,
+	// only the model's namespace prefix in their names.
+	return false
+}
+
+// DefaultPools is part of the storage.Provider interface.
+func (p *storageProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// FilesystemSource is part of the storage.Provider interface.
+func (p *storageProvider) FilesystemSource(providerConfig *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// VolumeSource is part of the storage.Provider interface.
+func (p *storageProvider) VolumeSource(cfg *storage.Config) (storage.VolumeSource, error) {
+	attrs, err := storageConfigChecker.Coerce(cfg.Attrs(), nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating libvirt storage config")
+	}
+	pool, _ := attrs.(map[string]interface{})[storagePoolAttr].(string)
+	if pool == "" {
+		pool = p.env.envConfig().storagePool()
+	}
+	return &volumeSource{env: p.env, pool: pool}, nil
+}
+
+type volumeSource struct {
+	env  *environ
+	pool string
+}
+
+var _ storage.VolumeSource = (*volumeSource)(nil)
+
+// volumeName returns the name of the libvirt volume for the Juju volume.
+func (v *volumeSource) volumeName(tag names.VolumeTag) string {
+	id := strings.Replace(tag.Id(), "/", "-", -1)
+	return v.env.namespace.Value("volume-" + id + ".qcow2")
+}
+
+// volumeSerial returns the serial number of the disk that the volume
+// is attached as. The guest links /dev/disk/by-id/virtio-<serial> to
+// the disk; virtio serial numbers are limited to 20 characters.
+func volumeSerial(tag names.VolumeTag) string {
+	serial := "juju-" + strings.Replace(tag.Id(), "/", "-", -1)
+	if len(serial) > 20 {
+		serial = serial[len(serial)-20:]
+	}
+	return serial
+}
+
+// CreateVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) CreateVolumes(params []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	results := make([]storage.CreateVolumesResult, len(params))
+	err := v.env.withConnection(func(conn Connection) error {
+		for i, p := range params {
+			if err := v.ValidateVolumeParams(p); err != nil {
+				results[i].Error = err
+				continue
+			}
+			volume, err := conn.CreateVolume(v.pool, VolumeParams{
+				Name:   v.volumeName(p.Tag),
+				Size:   p.Size,
+				Format: "qcow2",
+			})
+			if err != nil {
+				results[i].Error = errors.Trace(err)
+				continue
+			}
+			results[i].Volume = &storage.Volume{
+				p.Tag,
+				storage.VolumeInfo{
+					VolumeId:   volume.Name,
+					Size:       volume.Size,
+					Persistent: true,
+				},
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+// ListVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) ListVolumes() ([]string, error) {
+	var volumeIds []string
+	err := v.env.withConnection(func(conn Connection) error {
+		volumes, err := conn.Volumes(v.pool, v.env.namespace.Value("volume-"))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, volume := range volumes {
+			volumeIds = append(volumeIds, volume.Name)
+		}
+		return nil
+	})
+	return volumeIds, errors.Trace(err)
+}
+
+// DescribeVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DescribeVolumes(volIds []string) ([]storage.DescribeVolumesResult, error) {
+	results := make([]storage.DescribeVolumesResult, len(volIds))
+	err := v.env.withConnection(func(conn Connection) error {
+		for i, volId := range volIds {
+			volume, err := conn.Volume(v.pool, volId)
+			if err != nil {
+				results[i].Error = errors.Trace(err)
+				continue
+			}
+			results[i].VolumeInfo = &storage.VolumeInfo{
+				VolumeId:   volume.Name,
+				Size:       volume.Size,
+				Persistent: true,
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+// DestroyVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DestroyVolumes(volIds []string) ([]error, error) {
+	results := make([]error, len(volIds))
+	err := v.env.withConnection(func(conn Connection) error {
+		for i, volId := range volIds {
+			results[i] = conn.DeleteVolume(v.pool, volId)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+// ReleaseVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) ReleaseVolumes(volIds []string) ([]error, error) {
+	results := make([]error, len(volIds))
+	for i := range volIds {
+		results[i] = errors.NotSupportedf("releasing volumes")
+	}
+	return results, nil
+}
+
+// ValidateVolumeParams is specified on the storage.VolumeSource interface.
+func (v *volumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	if params.Size == 0 {
+		return errors.NotValidf("volume %s with zero size", params.Tag.Id())
+	}
+	return nil
+}
+
+// AttachVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) AttachVolumes(attachParams []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(attachParams))
+	err := v.env.withConnection(func(conn Connection) error {
+		for i, p := range attachParams {
+			volume, err := conn.Volume(v.pool, p.VolumeId)
+			if err != nil {
+				results[i].Error = errors.Trace(err)
+				continue
+			}
+			serial := volumeSerial(p.Volume)
+			if err := conn.AttachDisk(string(p.InstanceId), volume.Path, serial); err != nil {
+				results[i].Error = errors.Trace(err)
+				continue
+			}
+			results[i].VolumeAttachment = &storage.VolumeAttachment{
+				p.Volume,
+				p.Machine,
+				storage.VolumeAttachmentInfo{
+					DeviceLink: "/dev/disk/by-id/virtio-" + serial,
+				},
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+// DetachVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DetachVolumes(attachParams []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(attachParams))
+	err := v.env.withConnection(func(conn Connection) error {
+		for i, p := range attachParams {
+			volume, err := conn.Volume(v.pool, p.VolumeId)
+			if err != nil {
+				results[i] = errors.Trace(err)
+				continue
+			}
+			results[i] = conn.DetachDisk(string(p.InstanceId), volume.Path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
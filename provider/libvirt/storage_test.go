@@ -0,0 +1,182 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/libvirt"
+	"github.com/juju/juju/storage"
+)
+
+type storageSuite struct {
+	EnvironFixture
+	provider storage.Provider
+	source   storage.VolumeSource
+}
+
+var _ = gc.Suite(&storageSuite{})
+
+func (s *storageSuite) SetUpTest(c *gc.C) {
+	s.EnvironFixture.SetUpTest(c)
+	var err error
+	s.provider, err = s.env.(storage.ProviderRegistry).StorageProvider("libvirt")
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := storage.NewConfig("libvirt", "libvirt", map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.source, err = s.provider.VolumeSource(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestStorageProviderTypes(c *gc.C) {
+	types, err := s.env.(storage.ProviderRegistry).StorageProviderTypes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(types, jc.DeepEquals, []storage.ProviderType{"libvirt"})
+}
+
+func (s *storageSuite) TestProvider(c *gc.C) {
+	c.Assert(s.provider.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(s.provider.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Assert(s.provider.Scope(), gc.Equals, storage.ScopeEnviron)
+	c.Assert(s.provider.Dynamic(), jc.IsTrue)
+	c.Assert(s.provider.Releasable(), jc.IsFalse)
+}
+
+func (s *storageSuite) TestValidateConfig(c *gc.C) {
+	cfg, err := storage.NewConfig("fast", "libvirt", map[string]interface{}{"pool": "ssd"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.provider.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("fast", "libvirt", map[string]interface{}{"pool": 123})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.provider.ValidateConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, "validating libvirt storage config: pool: expected string, got int\\(123\\)")
+}
+
+func (s *storageSuite) TestCreateVolumes(c *gc.C) {
+	results, err := s.source.CreateVolumes([]storage.VolumeParams{{
+		Tag:      names.NewVolumeTag("0/1"),
+		Size:     1024,
+		Provider: "libvirt",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume, jc.DeepEquals, &storage.Volume{
+		names.NewVolumeTag("0/1"),
+		storage.VolumeInfo{
+			VolumeId:   "juju-f75cba-volume-0-1.qcow2",
+			Size:       1024,
+			Persistent: true,
+		},
+	})
+	s.conn.CheckCall(c, 0, "CreateVolume", "default", libvirt.VolumeParams{
+		Name:   "juju-f75cba-volume-0-1.qcow2",
+		Size:   1024,
+		Format: "qcow2",
+	})
+}
+
+func (s *storageSuite) TestCreateVolumesPoolAttribute(c *gc.C) {
+	cfg, err := storage.NewConfig("fast", "libvirt", map[string]interface{}{"pool": "ssd"})
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := s.provider.VolumeSource(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.conn.Calls()[0].Args[0], gc.Equals, "ssd")
+}
+
+func (s *storageSuite) TestListVolumes(c *gc.C) {
+	s.conn.volumes["juju-f75cba-volume-0.qcow2"] = libvirt.VolumeInfo{
+		Name: "juju-f75cba-volume-0.qcow2",
+	}
+	volumeIds, err := s.source.ListVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.DeepEquals, []string{"juju-f75cba-volume-0.qcow2"})
+	s.conn.CheckCall(c, 0, "Volumes", "default", "juju-f75cba-volume-")
+}
+
+func (s *storageSuite) TestDescribeVolumes(c *gc.C) {
+	s.conn.volumes["juju-f75cba-volume-0.qcow2"] = libvirt.VolumeInfo{
+		Name: "juju-f75cba-volume-0.qcow2",
+		Size: 2048,
+	}
+	results, err := s.source.DescribeVolumes([]string{"juju-f75cba-volume-0.qcow2", "juju-f75cba-volume-1.qcow2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].VolumeInfo, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId:   "juju-f75cba-volume-0.qcow2",
+		Size:       2048,
+		Persistent: true,
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `volume "juju-f75cba-volume-1.qcow2" not found`)
+}
+
+func (s *storageSuite) TestDestroyVolumes(c *gc.C) {
+	s.conn.SetErrors(nil, errors.New("boom"))
+	results, err := s.source.DestroyVolumes([]string{"vol-0", "vol-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.ErrorIsNil)
+	c.Assert(results[1], gc.ErrorMatches, "boom")
+	s.conn.CheckCallNames(c, "DeleteVolume", "DeleteVolume", "Close")
+}
+
+func (s *storageSuite) TestAttachVolumes(c *gc.C) {
+	s.conn.volumes["juju-f75cba-volume-0.qcow2"] = libvirt.VolumeInfo{
+		Name: "juju-f75cba-volume-0.qcow2",
+		Path: "/pool/juju-f75cba-volume-0.qcow2",
+	}
+	results, err := s.source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: instance.Id("juju-f75cba-0"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "juju-f75cba-volume-0.qcow2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeAttachment, jc.DeepEquals, &storage.VolumeAttachment{
+		names.NewVolumeTag("0"),
+		names.NewMachineTag("0"),
+		storage.VolumeAttachmentInfo{
+			DeviceLink: "/dev/disk/by-id/virtio-juju-0",
+		},
+	})
+	s.conn.CheckCall(c, 1, "AttachDisk", "juju-f75cba-0", "/pool/juju-f75cba-volume-0.qcow2", "juju-0")
+}
+
+func (s *storageSuite) TestDetachVolumes(c *gc.C) {
+	s.conn.volumes["juju-f75cba-volume-0.qcow2"] = libvirt.VolumeInfo{
+		Name: "juju-f75cba-volume-0.qcow2",
+		Path: "/pool/juju-f75cba-volume-0.qcow2",
+	}
+	results, err := s.source.DetachVolumes([]storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: instance.Id("juju-f75cba-0"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "juju-f75cba-volume-0.qcow2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []error{nil})
+	s.conn.CheckCall(c, 1, "DetachDisk", "juju-f75cba-0", "/pool/juju-f75cba-volume-0.qcow2")
+}
+
+func (s *storageSuite) TestFilesystemSource(c *gc.C) {
+	_, err := s.provider.FilesystemSource(nil)
+	c.Assert(err, gc.ErrorMatches, "filesystems not supported")
+}
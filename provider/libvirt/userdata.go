@@ -0,0 +1,105 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jujuos "github.com/juju/utils/os"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/providerinit/renderers"
+)
+
+// LibvirtRenderer renders the user data of libvirt instances, which
+// cloud-init reads from a NoCloud data source volume.
+type LibvirtRenderer struct{}
+
+// Render implements renderers.ProviderRenderer.
+func (LibvirtRenderer) Render(cfg cloudinit.CloudConfig, os jujuos.OSType) ([]byte, error) {
+	switch os {
+	case jujuos.Ubuntu, jujuos.CentOS:
+		return renderers.RenderYAML(cfg)
+	default:
+		return nil, errors.Errorf("cannot encode userdata for OS %q", os)
+	}
+}
+
+// writeDataSourceImage writes the ISO image of a cloud-init NoCloud data
+// source, holding the user-data and meta-data files found in dir, to path.
+var writeDataSourceImage = func(path, dir string) error {
+	// NoCloud requires the files to be at the root of a volume labelled
+	// "cidata"; see
+	// http://cloudinit.readthedocs.io/en/latest/topics/datasources/nocloud.html
+	output, err := runCommand(
+		"genisoimage",
+		"-output", path,
+		"-volid", "cidata",
+		"-joliet", "-rock",
+		"-graft-points",
+		"user-data="+filepath.Join(dir, "user-data"),
+		"meta-data="+filepath.Join(dir, "meta-data"),
+	)
+	if err != nil {
+		return errors.Annotatef(err, "creating data source image: %s", output)
+	}
+	return nil
+}
+
+// createDataSourceVolume creates the volume holding the cloud-init data
+// source of the named instance in the storage pool.
+func createDataSourceVolume(conn Connection, pool, name string, userData []byte) (VolumeInfo, error) {
+	dir, err := ioutil.TempDir("", "juju-libvirt-")
+	if err != nil {
+		return VolumeInfo{}, errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+
+	metaData := fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", name, name)
+	if err := ioutil.WriteFile(filepath.Join(dir, "meta-data"), []byte(metaData), 0600); err != nil {
+		return VolumeInfo{}, errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "user-data"), userData, 0600); err != nil {
+		return VolumeInfo{}, errors.Trace(err)
+	}
+	imagePath := filepath.Join(dir, "cidata.iso")
+	if err := writeDataSourceImage(imagePath, dir); err != nil {
+		return VolumeInfo{}, errors.Trace(err)
+	}
+	return uploadVolume(conn, pool, dataSourceVolumeName(name), "raw", imagePath)
+}
+
+// uploadVolume creates a volume in the storage pool with the content
+// of the local file at path.
+func uploadVolume(conn Connection, pool, name, format, path string) (VolumeInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return VolumeInfo{}, errors.Trace(err)
+	}
+	volume, err := conn.CreateVolume(pool, VolumeParams{
+		Name:   name,
+		Size:   sizeMiB(info.Size()),
+		Format: format,
+	})
+	if err != nil {
+		return VolumeInfo{}, errors.Trace(err)
+	}
+	if err := conn.UploadVolume(pool, name, path); err != nil {
+		if err := conn.DeleteVolume(pool, name); err != nil {
+			logger.Errorf("failed to delete volume %q: %v", name, err)
+		}
+		return VolumeInfo{}, errors.Trace(err)
+	}
+	return volume, nil
+}
+
+// sizeMiB returns the number of bytes in MiB, rounded up.
+func sizeMiB(bytes int64) uint64 {
+	const mib = 1024 * 1024
+	return uint64((bytes + mib - 1) / mib)
+}
@@ -0,0 +1,412 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+// This file implements Connection by running virsh against the libvirt
+// URI of the host. virsh speaks the libvirt remote protocol, so the
+// client and controllers only need the libvirt client tools installed,
+// and access to the host by the URI's transport. Controllers started by
+// the provider have the tools installed by their cloud-init user data;
+// the client must install them itself.
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+)
+
+const domainStateRunning = "running"
+
+// runFunc provides the signature for running an external command and
+// returning the combined output.
+type runFunc func(string, ...string) (string, error)
+
+// runCommand runs the command and returns the combined output.
+func runCommand(command string, args ...string) (string, error) {
+	logger.Tracef("%s %v", command, args)
+	output, err := utils.RunCommand(command, args...)
+	logger.Tracef("output: %v", output)
+	return output, err
+}
+
+// lookPath is patched out in tests.
+var lookPath = exec.LookPath
+
+type virshConnection struct {
+	uri string
+	run runFunc
+}
+
+var _ Connection = (*virshConnection)(nil)
+
+// dialVirsh returns a Connection to the libvirt daemon at uri,
+// after checking that the daemon can be reached.
+func dialVirsh(uri string) (Connection, error) {
+	if _, err := lookPath("virsh"); err != nil {
+		return nil, errors.Annotate(err,
+			"virsh is required to manage libvirt hosts; "+
+				"install the libvirt-clients package (libvirt-bin on trusty and xenial)",
+		)
+	}
+	conn := &virshConnection{uri: uri, run: runCommand}
+	if _, err := conn.virsh("version"); err != nil {
+		return nil, errors.Annotatef(err, "connecting to %s", uri)
+	}
+	return conn, nil
+}
+
+// virsh runs the virsh command with args against the connection's URI.
+func (c *virshConnection) virsh(args ...string) (string, error) {
+	output, err := c.run("virsh", append([]string{"--connect", c.uri}, args...)...)
+	if err != nil {
+		if output = strings.TrimSpace(output); output != "" {
+			return "", errors.Annotatef(err, "virsh %s: %s", args[0], output)
+		}
+		return "", errors.Annotatef(err, "virsh %s", args[0])
+	}
+	return output, nil
+}
+
+// Close implements Connection.
+func (c *virshConnection) Close() error {
+	return nil
+}
+
+// DefineDomain implements Connection.
+func (c *virshConnection) DefineDomain(domain kvmlibvirt.Domain) error {
+	data, err := xml.MarshalIndent(&domain, "", "    ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	f, err := ioutil.TempFile("", "juju-domain-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = c.virsh("define", f.Name())
+	return errors.Annotatef(err, "defining domain %q", domain.Name)
+}
+
+// StartDomain implements Connection.
+func (c *virshConnection) StartDomain(name string) error {
+	_, err := c.virsh("start", name)
+	return errors.Annotatef(err, "starting domain %q", name)
+}
+
+// DestroyDomain implements Connection.
+func (c *virshConnection) DestroyDomain(name string) error {
+	state, err := c.domainState(name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if state != "shut off" {
+		if _, err := c.virsh("destroy", name); err != nil {
+			return errors.Annotatef(err, "stopping domain %q", name)
+		}
+	}
+	_, err = c.virsh("undefine", "--nvram", name)
+	return errors.Annotatef(err, "undefining domain %q", name)
+}
+
+// SetDomainDescription implements Connection.
+func (c *virshConnection) SetDomainDescription(name, description string) error {
+	_, err := c.virsh("desc", name, "--config", "--new-desc", description)
+	return errors.Annotatef(err, "setting description of domain %q", name)
+}
+
+// Domains implements Connection.
+func (c *virshConnection) Domains(prefix string) ([]DomainInfo, error) {
+	output, err := c.virsh("list", "--all", "--name")
+	if err != nil {
+		return nil, errors.Annotate(err, "listing domains")
+	}
+	var result []DomainInfo
+	for _, name := range strings.Split(output, "\n") {
+		name = strings.TrimSpace(name)
+		if name == "" || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		state, err := c.domainState(name)
+		if errors.IsNotFound(err) {
+			// The domain was removed after we listed it.
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		description, err := c.virsh("desc", name, "--config")
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting description of domain %q", name)
+		}
+		description = strings.TrimSpace(description)
+		if strings.HasPrefix(description, "No description for domain") {
+			description = ""
+		}
+		result = append(result, DomainInfo{
+			Name:        name,
+			State:       state,
+			Description: description,
+		})
+	}
+	return result, nil
+}
+
+// domainState returns the state of the named domain, or an error
+// satisfying errors.IsNotFound if there is no such domain.
+func (c *virshConnection) domainState(name string) (string, error) {
+	output, err := c.virsh("domstate", name)
+	if err != nil {
+		if strings.Contains(err.Error(), "failed to get domain") {
+			return "", errors.NotFoundf("domain %q", name)
+		}
+		return "", errors.Annotatef(err, "getting state of domain %q", name)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// DomainAddresses implements Connection. The addresses are taken from
+// the DHCP leases of libvirt's own networks where there are any, and
+// otherwise from the host's ARP table, which covers domains bridged onto
+// the host's network.
+func (c *virshConnection) DomainAddresses(name string) ([]string, error) {
+	var lastErr error
+	for _, source := range []string{"lease", "arp"} {
+		output, err := c.virsh("domifaddr", name, "--source", source)
+		if err != nil {
+			logger.Debugf("getting %s addresses of domain %q: %v", source, name, err)
+			lastErr = err
+			continue
+		}
+		if addresses := parseDomainAddresses(output); len(addresses) > 0 {
+			return addresses, nil
+		}
+		lastErr = nil
+	}
+	return nil, errors.Annotatef(lastErr, "getting addresses of domain %q", name)
+}
+
+// parseDomainAddresses parses the output of "virsh domifaddr":
+//
+//	 Name       MAC address          Protocol     Address
+//	-------------------------------------------------------------------
+//	 vnet0      52:54:00:85:3e:1f    ipv4         10.0.0.15/24
+//	 -          -                    ipv6         fe80::5054:ff:fe85:3e1f/64
+func parseDomainAddresses(output string) []string {
+	var addresses []string
+	for _, fields := range parseTable(output) {
+		if len(fields) < 4 {
+			continue
+		}
+		addresses = append(addresses, strings.SplitN(fields[3], "/", 2)[0])
+	}
+	return addresses
+}
+
+// parseTable returns the fields of each row of the tabular output of
+// virsh, which follow a header and a line of dashes.
+func parseTable(output string) [][]string {
+	var rows [][]string
+	inBody := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !inBody {
+			inBody = strings.HasPrefix(line, "---")
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		rows = append(rows, strings.Fields(line))
+	}
+	return rows
+}
+
+// Volume implements Connection.
+func (c *virshConnection) Volume(pool, name string) (VolumeInfo, error) {
+	output, err := c.virsh("vol-info", "--bytes", "--pool", pool, name)
+	if err != nil {
+		if isVolumeNotFound(err) {
+			return VolumeInfo{}, errors.NotFoundf("volume %q in pool %q", name, pool)
+		}
+		return VolumeInfo{}, errors.Annotatef(err, "getting volume %q", name)
+	}
+	var size uint64
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "Capacity:" {
+			continue
+		}
+		bytes, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return VolumeInfo{}, errors.Annotatef(err, "parsing capacity of volume %q", name)
+		}
+		size = bytes / (1024 * 1024)
+	}
+	path, err := c.volumePath(pool, name)
+	if err != nil {
+		return VolumeInfo{}, errors.Trace(err)
+	}
+	return VolumeInfo{Name: name, Path: path, Size: size}, nil
+}
+
+// Volumes implements Connection.
+func (c *virshConnection) Volumes(pool, prefix string) ([]VolumeInfo, error) {
+	output, err := c.virsh("vol-list", pool)
+	if err != nil {
+		return nil, errors.Annotatef(err, "listing volumes in pool %q", pool)
+	}
+	var result []VolumeInfo
+	for _, fields := range parseTable(output) {
+		if len(fields) < 2 || !strings.HasPrefix(fields[0], prefix) {
+			continue
+		}
+		result = append(result, VolumeInfo{Name: fields[0], Path: fields[1]})
+	}
+	return result, nil
+}
+
+// CreateVolume implements Connection.
+func (c *virshConnection) CreateVolume(pool string, params VolumeParams) (VolumeInfo, error) {
+	args := []string{
+		"vol-create-as", pool, params.Name,
+		fmt.Sprintf("%dM", params.Size),
+		"--format", params.Format,
+	}
+	if params.BackingPath != "" {
+		args = append(args,
+			"--backing-vol", params.BackingPath,
+			"--backing-vol-format", "qcow2",
+		)
+	}
+	if _, err := c.virsh(args...); err != nil {
+		return VolumeInfo{}, errors.Annotatef(err, "creating volume %q", params.Name)
+	}
+	path, err := c.volumePath(pool, params.Name)
+	if err != nil {
+		return VolumeInfo{}, errors.Trace(err)
+	}
+	return VolumeInfo{Name: params.Name, Path: path, Size: params.Size}, nil
+}
+
+func (c *virshConnection) volumePath(pool, name string) (string, error) {
+	output, err := c.virsh("vol-path", "--pool", pool, name)
+	if err != nil {
+		return "", errors.Annotatef(err, "getting path of volume %q", name)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// UploadVolume implements Connection.
+func (c *virshConnection) UploadVolume(pool, name, path string) error {
+	_, err := c.virsh("vol-upload", "--pool", pool, name, path)
+	return errors.Annotatef(err, "uploading volume %q", name)
+}
+
+// DeleteVolume implements Connection.
+func (c *virshConnection) DeleteVolume(pool, name string) error {
+	_, err := c.virsh("vol-delete", "--pool", pool, name)
+	if err != nil && !isVolumeNotFound(err) {
+		return errors.Annotatef(err, "deleting volume %q", name)
+	}
+	return nil
+}
+
+func isVolumeNotFound(err error) bool {
+	return strings.Contains(err.Error(), "Storage volume not found")
+}
+
+// AttachDisk implements Connection.
+func (c *virshConnection) AttachDisk(domain, path, serial string) error {
+	disks, err := c.domainDisks(domain)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var target string
+	for i := 0; i < 26; i++ {
+		dev := fmt.Sprintf("vd%c", 'a'+i)
+		if source, ok := disks[dev]; ok {
+			if source == path {
+				return nil
+			}
+			continue
+		}
+		if target == "" {
+			target = dev
+		}
+	}
+	if target == "" {
+		return errors.Errorf("no free disk devices on domain %q", domain)
+	}
+	args := []string{
+		"attach-disk", domain, path, target,
+		"--driver", "qemu",
+		"--subdriver", "qcow2",
+		"--targetbus", "virtio",
+		"--serial", serial,
+	}
+	args, err = c.appendLive(domain, args)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = c.virsh(args...)
+	return errors.Annotatef(err, "attaching %q to domain %q", path, domain)
+}
+
+// DetachDisk implements Connection.
+func (c *virshConnection) DetachDisk(domain, path string) error {
+	args, err := c.appendLive(domain, []string{"detach-disk", domain, path})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = c.virsh(args...)
+	return errors.Annotatef(err, "detaching %q from domain %q", path, domain)
+}
+
+// appendLive adds the flags to a disk command that change the domain's
+// persistent definition and, if it is running, the running domain.
+func (c *virshConnection) appendLive(domain string, args []string) ([]string, error) {
+	state, err := c.domainState(domain)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	args = append(args, "--persistent")
+	if state == domainStateRunning {
+		args = append(args, "--live")
+	}
+	return args, nil
+}
+
+// domainDisks returns the sources of the named domain's disks, keyed
+// by their target devices.
+func (c *virshConnection) domainDisks(domain string) (map[string]string, error) {
+	output, err := c.virsh("domblklist", domain)
+	if err != nil {
+		return nil, errors.Annotatef(err, "listing disks of domain %q", domain)
+	}
+	disks := make(map[string]string)
+	for _, fields := range parseTable(output) {
+		if len(fields) < 2 {
+			continue
+		}
+		disks[fields[0]] = fields[1]
+	}
+	return disks, nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type virshSuite struct {
+	testing.IsolationSuite
+	stub    testing.Stub
+	outputs map[string]string
+	conn    *virshConnection
+}
+
+var _ = gc.Suite(&virshSuite{})
+
+func (s *virshSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub.ResetCalls()
+	s.outputs = make(map[string]string)
+	s.conn = &virshConnection{uri: "qemu:///system", run: s.run}
+}
+
+// run is a runFunc returning the output registered for the virsh
+// subcommand and its first argument.
+func (s *virshSuite) run(command string, args ...string) (string, error) {
+	s.stub.AddCall(command, strings.Join(args, " "))
+	key := args[2]
+	if len(args) > 3 {
+		key += " " + args[3]
+	}
+	return s.outputs[key], s.stub.NextErr()
+}
+
+func (s *virshSuite) TestDialVirshNotInstalled(c *gc.C) {
+	s.PatchValue(&lookPath, func(file string) (string, error) {
+		c.Assert(file, gc.Equals, "virsh")
+		return "", errors.New("executable file not found in $PATH")
+	})
+	_, err := dialVirsh("qemu:///system")
+	c.Assert(err, gc.ErrorMatches, "virsh is required to manage libvirt hosts; install the libvirt-clients package \\(libvirt-bin on trusty and xenial\\): executable file not found in \\$PATH")
+}
+
+func (s *virshSuite) TestDomains(c *gc.C) {
+	s.outputs["list --all"] = "juju-f75cba-0\njuju-f75cba-1\nother\n\n"
+	s.outputs["domstate juju-f75cba-0"] = "running\n\n"
+	s.outputs["domstate juju-f75cba-1"] = "shut off\n\n"
+	s.outputs["desc juju-f75cba-0"] = `{"juju-is-controller":"true"}` + "\n"
+	s.outputs["desc juju-f75cba-1"] = "No description for domain: juju-f75cba-1\n"
+
+	domains, err := s.conn.Domains("juju-f75cba-")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(domains, jc.DeepEquals, []DomainInfo{{
+		Name:        "juju-f75cba-0",
+		State:       "running",
+		Description: `{"juju-is-controller":"true"}`,
+	}, {
+		Name:  "juju-f75cba-1",
+		State: "shut off",
+	}})
+	s.stub.CheckCall(c, 0, "virsh", "--connect qemu:///system list --all --name")
+	s.stub.CheckCall(c, 2, "virsh", "--connect qemu:///system desc juju-f75cba-0 --config")
+}
+
+func (s *virshSuite) TestDestroyDomain(c *gc.C) {
+	s.outputs["domstate juju-f75cba-0"] = "running\n"
+	err := s.conn.DestroyDomain("juju-f75cba-0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{"virsh", []interface{}{"--connect qemu:///system domstate juju-f75cba-0"}},
+		{"virsh", []interface{}{"--connect qemu:///system destroy juju-f75cba-0"}},
+		{"virsh", []interface{}{"--connect qemu:///system undefine --nvram juju-f75cba-0"}},
+	})
+}
+
+func (s *virshSuite) TestDestroyDomainNotFound(c *gc.C) {
+	s.outputs["domstate juju-f75cba-0"] = "error: failed to get domain 'juju-f75cba-0'"
+	s.stub.SetErrors(errors.New("exit status 1"))
+	err := s.conn.DestroyDomain("juju-f75cba-0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCallNames(c, "virsh")
+}
+
+func (s *virshSuite) TestDomainAddresses(c *gc.C) {
+	s.outputs["domifaddr juju-f75cba-0"] = `
+ Name       MAC address          Protocol     Address
+-------------------------------------------------------------------------------
+ vnet0      52:54:00:85:3e:1f    ipv4         10.0.0.15/24
+ -          -                    ipv6         fe80::5054:ff:fe85:3e1f/64
+`
+	addresses, err := s.conn.DomainAddresses("juju-f75cba-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []string{"10.0.0.15", "fe80::5054:ff:fe85:3e1f"})
+	s.stub.CheckCall(c, 0, "virsh", "--connect qemu:///system domifaddr juju-f75cba-0 --source lease")
+}
+
+func (s *virshSuite) TestVolume(c *gc.C) {
+	s.outputs["vol-info --bytes"] = `Name:           juju-f75cba-0.qcow2
+Type:           file
+Capacity:       8589934592 bytes
+Allocation:     2097152 bytes
+`
+	s.outputs["vol-path --pool"] = "/var/lib/libvirt/images/juju-f75cba-0.qcow2\n"
+	volume, err := s.conn.Volume("default", "juju-f75cba-0.qcow2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volume, jc.DeepEquals, VolumeInfo{
+		Name: "juju-f75cba-0.qcow2",
+		Path: "/var/lib/libvirt/images/juju-f75cba-0.qcow2",
+		Size: 8192,
+	})
+}
+
+func (s *virshSuite) TestVolumeNotFound(c *gc.C) {
+	s.outputs["vol-info --bytes"] = "error: Storage volume not found: no storage vol with matching path"
+	s.stub.SetErrors(errors.New("exit status 1"))
+	_, err := s.conn.Volume("default", "juju-f75cba-0.qcow2")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *virshSuite) TestVolumes(c *gc.C) {
+	s.outputs["vol-list default"] = `
+ Name                       Path
+------------------------------------------------------------------------------
+ juju-f75cba-0.qcow2        /var/lib/libvirt/images/juju-f75cba-0.qcow2
+ juju-image-xenial-amd64.img /var/lib/libvirt/images/juju-image-xenial-amd64.img
+`
+	volumes, err := s.conn.Volumes("default", "juju-f75cba-")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, jc.DeepEquals, []VolumeInfo{{
+		Name: "juju-f75cba-0.qcow2",
+		Path: "/var/lib/libvirt/images/juju-f75cba-0.qcow2",
+	}})
+}
+
+func (s *virshSuite) TestCreateVolume(c *gc.C) {
+	s.outputs["vol-path --pool"] = "/var/lib/libvirt/images/juju-f75cba-0.qcow2\n"
+	volume, err := s.conn.CreateVolume("default", VolumeParams{
+		Name:        "juju-f75cba-0.qcow2",
+		Size:        8192,
+		Format:      "qcow2",
+		BackingPath: "/var/lib/libvirt/images/juju-image-xenial-amd64.img",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volume.Path, gc.Equals, "/var/lib/libvirt/images/juju-f75cba-0.qcow2")
+	s.stub.CheckCall(c, 0, "virsh",
+		"--connect qemu:///system vol-create-as default juju-f75cba-0.qcow2 8192M --format qcow2"+
+			" --backing-vol /var/lib/libvirt/images/juju-image-xenial-amd64.img --backing-vol-format qcow2",
+	)
+}
+
+func (s *virshSuite) TestAttachDisk(c *gc.C) {
+	s.outputs["domblklist juju-f75cba-0"] = `
+ Target     Source
+------------------------------------------------
+ vda        /var/lib/libvirt/images/juju-f75cba-0.qcow2
+ vdb        /var/lib/libvirt/images/juju-f75cba-0-ds.iso
+`
+	s.outputs["domstate juju-f75cba-0"] = "running\n"
+	err := s.conn.AttachDisk("juju-f75cba-0", "/pool/juju-f75cba-volume-0.qcow2", "juju-0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCall(c, 2, "virsh",
+		"--connect qemu:///system attach-disk juju-f75cba-0 /pool/juju-f75cba-volume-0.qcow2 vdc"+
+			" --driver qemu --subdriver qcow2 --targetbus virtio --serial juju-0 --persistent --live",
+	)
+}
+
+func (s *virshSuite) TestAttachDiskAlreadyAttached(c *gc.C) {
+	s.outputs["domblklist juju-f75cba-0"] = `
+ Target     Source
+------------------------------------------------
+ vda        /var/lib/libvirt/images/juju-f75cba-0.qcow2
+ vdc        /pool/juju-f75cba-volume-0.qcow2
+`
+	err := s.conn.AttachDisk("juju-f75cba-0", "/pool/juju-f75cba-volume-0.qcow2", "juju-0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCallNames(c, "virsh")
+}
+
+func (s *virshSuite) TestDeleteVolumeNotFound(c *gc.C) {
+	s.outputs["vol-delete --pool"] = "error: Storage volume not found: no storage vol with matching name"
+	s.stub.SetErrors(errors.New("exit status 1"))
+	err := s.conn.DeleteVolume("default", "juju-f75cba-0.qcow2")
+	c.Assert(err, jc.ErrorIsNil)
+}
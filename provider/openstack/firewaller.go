@@ -5,6 +5,7 @@ package openstack
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 	"sync"
@@ -13,6 +14,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/retry"
 	"github.com/juju/utils/clock"
+	gooseerrors "gopkg.in/goose.v2/errors"
 	"gopkg.in/goose.v2/neutron"
 
 	"github.com/juju/juju/environs"
@@ -83,6 +85,22 @@ type Firewaller interface {
 	InstanceIngressRules(inst instance.Instance, machineId string) ([]network.IngressRule, error)
 }
 
+// egressFirewaller is implemented by Firewallers which support
+// restricting the traffic sent by instances.
+type egressFirewaller interface {
+	// OpenInstanceEgressPorts allows traffic from the specified
+	// instance to the destinations of the given rules.
+	OpenInstanceEgressPorts(inst instance.Instance, machineId string, rules []network.EgressRule) error
+
+	// CloseInstanceEgressPorts stops allowing traffic from the
+	// specified instance to the destinations of the given rules.
+	CloseInstanceEgressPorts(inst instance.Instance, machineId string, rules []network.EgressRule) error
+
+	// InstanceEgressRules returns the egress rules applied to the
+	// specified instance.
+	InstanceEgressRules(inst instance.Instance, machineId string) ([]network.EgressRule, error)
+}
+
 type firewallerFactory struct {
 }
 
@@ -205,6 +223,41 @@ func (f *switchingFirewaller) InstanceIngressRules(inst instance.Instance, machi
 	return f.fw.InstanceIngressRules(inst, machineId)
 }
 
+func (f *switchingFirewaller) egressFirewaller() (egressFirewaller, error) {
+	if err := f.initFirewaller(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	fw, ok := f.fw.(egressFirewaller)
+	if !ok {
+		return nil, errors.NotSupportedf("egress rules without Neutron")
+	}
+	return fw, nil
+}
+
+func (f *switchingFirewaller) OpenInstanceEgressPorts(inst instance.Instance, machineId string, rules []network.EgressRule) error {
+	fw, err := f.egressFirewaller()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return fw.OpenInstanceEgressPorts(inst, machineId, rules)
+}
+
+func (f *switchingFirewaller) CloseInstanceEgressPorts(inst instance.Instance, machineId string, rules []network.EgressRule) error {
+	fw, err := f.egressFirewaller()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return fw.CloseInstanceEgressPorts(inst, machineId, rules)
+}
+
+func (f *switchingFirewaller) InstanceEgressRules(inst instance.Instance, machineId string) ([]network.EgressRule, error) {
+	fw, err := f.egressFirewaller()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return fw.InstanceEgressRules(inst, machineId)
+}
+
 type firewallerBase struct {
 	environ *Environ
 }
@@ -450,6 +503,13 @@ func (c *neutronFirewaller) SetUpGroups(controllerUUID, machineId string, apiPor
 	switch c.environ.Config().FirewallMode() {
 	case config.FwInstance:
 		machineGroup, err = c.ensureGroup(c.machineGroupName(controllerUUID, machineId), nil)
+		if err == nil {
+			// Neutron allows all egress from new security groups.
+			// The machine's own group keeps those rules, so remove
+			// them from the group shared by all machines, allowing
+			// egress to be restricted per machine.
+			err = c.deleteDefaultEgressRules(jujuGroup)
+		}
 	case config.FwGlobal:
 		machineGroup, err = c.ensureGroup(c.globalGroupName(controllerUUID), nil)
 	}
@@ -946,6 +1006,225 @@ func (c *neutronFirewaller) ingressRulesInGroup(nameRegexp string) (rules []netw
 	return rules, nil
 }
 
+// checkEgressSupported returns an error satisfying errors.IsNotSupported
+// if egress rules cannot be enforced for the instance. Security groups
+// are additive, so egress can only be restricted when all of the
+// instance's groups are managed per machine by Juju.
+func (c *neutronFirewaller) checkEgressSupported(inst instance.Instance) error {
+	if mode := c.environ.Config().FirewallMode(); mode != config.FwInstance {
+		return errors.NotSupportedf("egress rules in firewall mode %q", mode)
+	}
+	if c.environ.ecfg().useDefaultSecurityGroup() {
+		return errors.NotSupportedf("egress rules with use-default-secgroup")
+	}
+	// For bug 1680787
+	// No security groups exist if the network used to boot the instance
+	// has PortSecurityEnabled set to false.
+	if securityGroups := inst.(*openstackInstance).getServerDetail().Groups; securityGroups == nil {
+		return errors.NotSupportedf("egress rules without port security")
+	}
+	// Only the machine's own group is changed, so the model group that
+	// all machines share must not allow all egress itself. This is only
+	// the case for model groups last set up before egress rules were
+	// supported.
+	modelGroup, err := c.matchingGroup("^" + c.jujuGroupRegexp() + "$")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, p := range modelGroup.Rules {
+		if isDefaultEgressRule(p) {
+			return errors.NotSupportedf("egress rules while security group %q allows all egress", modelGroup.Name)
+		}
+	}
+	return nil
+}
+
+// OpenInstanceEgressPorts implements egressFirewaller.
+func (c *neutronFirewaller) OpenInstanceEgressPorts(inst instance.Instance, machineId string, rules []network.EgressRule) error {
+	if err := c.checkEgressSupported(inst); err != nil {
+		return errors.Trace(err)
+	}
+	group, err := c.matchingGroup(c.machineGroupRegexp(machineId))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	neutronClient := c.environ.neutron()
+	for _, rule := range egressRulesToRuleInfo(group.Id, rules) {
+		if _, err := neutronClient.CreateSecurityGroupRuleV2(rule); err != nil && !gooseerrors.IsDuplicateValue(err) {
+			return errors.Annotatef(err, "creating egress rule in security group %q", group.Name)
+		}
+	}
+	// Neutron allows all egress from new security groups. Remove those
+	// rules from the machine's group, so that only the opened rules
+	// allow egress from the machine. Other machines are unaffected.
+	if err := c.deleteDefaultEgressRules(group); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("opened egress rules in security group %s-%s: %v", c.environ.Config().UUID(), machineId, rules)
+	return nil
+}
+
+// CloseInstanceEgressPorts implements egressFirewaller.
+func (c *neutronFirewaller) CloseInstanceEgressPorts(inst instance.Instance, machineId string, rules []network.EgressRule) error {
+	if err := c.checkEgressSupported(inst); err != nil {
+		return errors.Trace(err)
+	}
+	group, err := c.matchingGroup(c.machineGroupRegexp(machineId))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	neutronClient := c.environ.neutron()
+	remaining := 0
+	for _, p := range group.Rules {
+		if p.Direction != "egress" {
+			continue
+		}
+		if !secGroupMatchesEgressRules(p, rules) {
+			remaining++
+			continue
+		}
+		if err := neutronClient.DeleteSecurityGroupRuleV2(p.Id); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if remaining == 0 {
+		// No egress rules remain, so lift the restriction.
+		for _, rule := range defaultEgressRuleInfo(group.Id) {
+			if _, err := neutronClient.CreateSecurityGroupRuleV2(rule); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	logger.Infof("closed egress rules in security group %s-%s: %v", c.environ.Config().UUID(), machineId, rules)
+	return nil
+}
+
+// InstanceEgressRules implements egressFirewaller.
+func (c *neutronFirewaller) InstanceEgressRules(inst instance.Instance, machineId string) ([]network.EgressRule, error) {
+	if err := c.checkEgressSupported(inst); err != nil {
+		return nil, errors.Trace(err)
+	}
+	group, err := c.matchingGroup(c.machineGroupRegexp(machineId))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Keep track of all the RemoteIPPrefixes for each port range.
+	portDestinationCIDRs := make(map[network.PortRange][]string)
+	for _, p := range group.Rules {
+		// Skip ingress rules, and the default egress rules
+		// created by Neutron which allow all traffic.
+		if p.Direction != "egress" || p.IPProtocol == nil {
+			continue
+		}
+		portRange := network.PortRange{
+			Protocol: *p.IPProtocol,
+		}
+		if p.PortRangeMin != nil {
+			portRange.FromPort = *p.PortRangeMin
+		}
+		if p.PortRangeMax != nil {
+			portRange.ToPort = *p.PortRangeMax
+		}
+		remotePrefix := p.RemoteIPPrefix
+		if remotePrefix == "" {
+			remotePrefix = "0.0.0.0/0"
+		}
+		portDestinationCIDRs[portRange] = append(portDestinationCIDRs[portRange], remotePrefix)
+	}
+	var rules []network.EgressRule
+	for portRange, destinationCIDRs := range portDestinationCIDRs {
+		rule, err := network.NewEgressRule(
+			portRange.Protocol,
+			portRange.FromPort,
+			portRange.ToPort,
+			destinationCIDRs...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, rule)
+	}
+	network.SortEgressRules(rules)
+	return rules, nil
+}
+
+// deleteDefaultEgressRules deletes the rules allowing all egress,
+// which Neutron creates with every new security group, from the group.
+func (c *neutronFirewaller) deleteDefaultEgressRules(group neutron.SecurityGroupV2) error {
+	neutronClient := c.environ.neutron()
+	for _, p := range group.Rules {
+		if !isDefaultEgressRule(p) {
+			continue
+		}
+		if err := neutronClient.DeleteSecurityGroupRuleV2(p.Id); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// isDefaultEgressRule reports whether the rule is one of those allowing
+// all egress, which Neutron creates with every new security group.
+func isDefaultEgressRule(p neutron.SecurityGroupRuleV2) bool {
+	return p.Direction == "egress" && p.IPProtocol == nil && p.RemoteIPPrefix == "" && p.RemoteGroupId == ""
+}
+
+// defaultEgressRuleInfo returns the rules allowing all egress which
+// Neutron creates with every new security group.
+func defaultEgressRuleInfo(groupId string) []neutron.RuleInfoV2 {
+	return []neutron.RuleInfoV2{{
+		Direction:     "egress",
+		EthernetType:  "IPv4",
+		ParentGroupId: groupId,
+	}, {
+		Direction:     "egress",
+		EthernetType:  "IPv6",
+		ParentGroupId: groupId,
+	}}
+}
+
+// egressRulesToRuleInfo maps egress rules to neutron rules.
+func egressRulesToRuleInfo(groupId string, rules []network.EgressRule) []neutron.RuleInfoV2 {
+	var result []neutron.RuleInfoV2
+	for _, r := range rules {
+		for _, cidr := range r.DestinationCIDRs {
+			ruleInfo := neutron.RuleInfoV2{
+				Direction:      "egress",
+				ParentGroupId:  groupId,
+				PortRangeMin:   r.FromPort,
+				PortRangeMax:   r.ToPort,
+				IPProtocol:     r.Protocol,
+				RemoteIPPrefix: cidr,
+			}
+			if ip, _, err := net.ParseCIDR(cidr); err == nil && ip.To4() == nil {
+				ruleInfo.EthernetType = "IPv6"
+			}
+			result = append(result, ruleInfo)
+		}
+	}
+	return result
+}
+
+// secGroupMatchesEgressRules checks if the supplied neutron security
+// group rule allows egress to one of the destinations of the rules.
+func secGroupMatchesEgressRules(secGroupRule neutron.SecurityGroupRuleV2, rules []network.EgressRule) bool {
+	if secGroupRule.IPProtocol == nil || secGroupRule.PortRangeMin == nil || secGroupRule.PortRangeMax == nil {
+		return false
+	}
+	for _, rule := range rules {
+		if *secGroupRule.IPProtocol != rule.Protocol ||
+			*secGroupRule.PortRangeMin != rule.FromPort ||
+			*secGroupRule.PortRangeMax != rule.ToPort {
+			continue
+		}
+		for _, cidr := range rule.DestinationCIDRs {
+			if cidr == secGroupRule.RemoteIPPrefix {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func replaceControllerUUID(oldName, controllerUUID string) (string, error) {
 	if !extractControllerRe.MatchString(oldName) {
 		return "", errors.Errorf("unexpected security group name format for %q", oldName)
@@ -1669,6 +1669,124 @@ func (s *localServerSuite) TestMatchingGroup(c *gc.C) {
 	c.Assert(group2.Id, gc.Equals, groupMatched.Id)
 }
 
+// egressSecurityGroupRules returns the egress rules of the named
+// security group.
+func egressSecurityGroupRules(c *gc.C, env environs.Environ, name string) []neutron.RuleInfoV2 {
+	groups, err := openstack.GetNeutronClient(env).SecurityGroupByNameV2(name)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 1)
+	var egress []neutron.RuleInfoV2
+	for _, rule := range ruleToRuleInfo(groups[0].Rules) {
+		if rule.Direction == "egress" {
+			egress = append(egress, rule)
+		}
+	}
+	return egress
+}
+
+func (s *localServerSuite) TestEgressRules(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{
+		"firewall-mode": config.FwInstance,
+		"network":       "net",
+	})
+	inst, _ := testing.AssertStartInstance(c, env, s.ControllerUUID, "100")
+	modelGroup := fmt.Sprintf("juju-%v-%v", s.ControllerUUID, env.Config().UUID())
+	machineGroup := modelGroup + "-100"
+	defaultEgress := []neutron.RuleInfoV2{
+		{Direction: "egress", EthernetType: "IPv4"},
+		{Direction: "egress", EthernetType: "IPv6"},
+	}
+
+	// Only the machine's own group allows all egress.
+	c.Assert(egressSecurityGroupRules(c, env, modelGroup), gc.HasLen, 0)
+	c.Assert(egressSecurityGroupRules(c, env, machineGroup), jc.SameContents, defaultEgress)
+
+	fwEnv := env.(environs.EgressFirewaller)
+	rules, err := fwEnv.EgressRules(inst.Id(), "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	err = fwEnv.OpenEgressPorts(inst.Id(), "100", []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8", "192.168.0.0/16"),
+		network.MustNewEgressRule("udp", 53, 53, "10.0.0.1/32"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwEnv.EgressRules(inst.Id(), "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8", "192.168.0.0/16"),
+		network.MustNewEgressRule("udp", 53, 53, "10.0.0.1/32"),
+	})
+	// The machine's group no longer allows all egress.
+	egress := egressSecurityGroupRules(c, env, machineGroup)
+	c.Assert(egress, gc.HasLen, 3)
+	for _, rule := range egress {
+		c.Check(rule.IPProtocol, gc.Not(gc.Equals), "")
+	}
+
+	err = fwEnv.CloseEgressPorts(inst.Id(), "100", []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "192.168.0.0/16"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwEnv.EgressRules(inst.Id(), "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+		network.MustNewEgressRule("udp", 53, 53, "10.0.0.1/32"),
+	})
+
+	// Closing the last rules lifts the restriction.
+	err = fwEnv.CloseEgressPorts(inst.Id(), "100", rules)
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwEnv.EgressRules(inst.Id(), "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+	c.Assert(egressSecurityGroupRules(c, env, machineGroup), jc.SameContents, defaultEgress)
+	c.Assert(egressSecurityGroupRules(c, env, modelGroup), gc.HasLen, 0)
+}
+
+func (s *localServerSuite) TestEgressRulesModelGroupAllowsAllEgress(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{
+		"firewall-mode": config.FwInstance,
+		"network":       "net",
+	})
+	inst, _ := testing.AssertStartInstance(c, env, s.ControllerUUID, "100")
+
+	// Model groups set up before egress rules were supported allow
+	// all egress, so restricting the machine's group has no effect.
+	modelGroup := fmt.Sprintf("juju-%v-%v", s.ControllerUUID, env.Config().UUID())
+	neutronClient := openstack.GetNeutronClient(env)
+	groups, err := neutronClient.SecurityGroupByNameV2(modelGroup)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = neutronClient.CreateSecurityGroupRuleV2(neutron.RuleInfoV2{
+		Direction:     "egress",
+		EthernetType:  "IPv4",
+		ParentGroupId: groups[0].Id,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = env.(environs.EgressFirewaller).OpenEgressPorts(inst.Id(), "100", []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `egress rules while security group ".*" allows all egress not supported`)
+}
+
+func (s *localServerSuite) TestEgressRulesGlobalFirewallMode(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{
+		"firewall-mode": config.FwGlobal,
+		"network":       "net",
+	})
+	inst, _ := testing.AssertStartInstance(c, env, s.ControllerUUID, "100")
+	_, err := env.(environs.EgressFirewaller).EgressRules(inst.Id(), "100")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	// The model group keeps allowing all egress, as the global group
+	// is shared by all machines.
+	modelGroup := fmt.Sprintf("juju-%v-%v", s.ControllerUUID, env.Config().UUID())
+	c.Assert(egressSecurityGroupRules(c, env, modelGroup), gc.HasLen, 2)
+}
+
 // localHTTPSServerSuite contains tests that run against an Openstack service
 // double connected on an HTTPS port with a self-signed certificate. This
 // service is set up and torn down for every test.  This should only test
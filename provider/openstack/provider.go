@@ -300,6 +300,7 @@ var _ environs.NetworkingEnviron = (*Environ)(nil)
 var _ simplestreams.HasRegion = (*Environ)(nil)
 var _ instance.Distributor = (*Environ)(nil)
 var _ environs.InstanceTagger = (*Environ)(nil)
+var _ environs.EgressFirewaller = (*Environ)(nil)
 
 type openstackInstance struct {
 	e        *Environ
@@ -1671,6 +1672,50 @@ func (e *Environ) IngressRules() ([]network.IngressRule, error) {
 	return e.firewaller.IngressRules()
 }
 
+// egressInstance returns the egress firewaller and the instance
+// with the given id.
+func (e *Environ) egressInstance(instId instance.Id) (egressFirewaller, instance.Instance, error) {
+	fw, ok := e.firewaller.(egressFirewaller)
+	if !ok {
+		return nil, nil, errors.NotSupportedf("egress rules")
+	}
+	insts, err := e.Instances([]instance.Id{instId})
+	if err == environs.ErrNoInstances {
+		return nil, nil, errors.NotFoundf("instance %q", instId)
+	}
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return fw, insts[0], nil
+}
+
+// OpenEgressPorts is specified in environs.EgressFirewaller.
+func (e *Environ) OpenEgressPorts(instId instance.Id, machineId string, rules []network.EgressRule) error {
+	fw, inst, err := e.egressInstance(instId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return fw.OpenInstanceEgressPorts(inst, machineId, rules)
+}
+
+// CloseEgressPorts is specified in environs.EgressFirewaller.
+func (e *Environ) CloseEgressPorts(instId instance.Id, machineId string, rules []network.EgressRule) error {
+	fw, inst, err := e.egressInstance(instId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return fw.CloseInstanceEgressPorts(inst, machineId, rules)
+}
+
+// EgressRules is specified in environs.EgressFirewaller.
+func (e *Environ) EgressRules(instId instance.Id, machineId string) ([]network.EgressRule, error) {
+	fw, inst, err := e.egressInstance(instId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return fw.InstanceEgressRules(inst, machineId)
+}
+
 func (e *Environ) Provider() environs.EnvironProvider {
 	return providerInstance
 }
@@ -5,7 +5,9 @@ package state
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/replicaset"
@@ -818,6 +820,17 @@ func (st *State) maintainControllersOps(mdocs []*machineDoc, currentInfo *Contro
 func (st *State) EnableHA(
 	numControllers int, cons constraints.Value, series string, placement []string,
 ) (ControllersChanges, error) {
+	return st.EnableHAInZones(numControllers, cons, series, placement, nil)
+}
+
+// EnableHAInZones behaves like EnableHA, but also spreads the new
+// controller machines across the given availability zones. Each new
+// machine not started according to a placement directive is placed in
+// the zone hosting the fewest controllers, so that the loss of a single
+// zone is least likely to lose quorum.
+func (st *State) EnableHAInZones(
+	numControllers int, cons constraints.Value, series string, placement []string, zones []string,
+) (ControllersChanges, error) {
 
 	if numControllers < 0 || (numControllers != 0 && numControllers%2 != 1) {
 		return ControllersChanges{}, errors.New("number of controllers must be odd and non-negative")
@@ -827,58 +840,99 @@ func (st *State) EnableHA(
 	}
 	var change ControllersChanges
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		currentInfo, err := st.ControllerInfo()
-		if err != nil {
-			return nil, err
-		}
-		desiredControllerCount := numControllers
-		if desiredControllerCount == 0 {
-			desiredControllerCount = len(currentInfo.VotingMachineIds)
-			if desiredControllerCount <= 1 {
-				desiredControllerCount = 3
-			}
-		}
-		if len(currentInfo.VotingMachineIds) > desiredControllerCount {
-			return nil, errors.New("cannot reduce controller count")
-		}
+		var ops []txn.Op
+		var err error
+		ops, change, err = st.enableHAOps(numControllers, cons, series, placement, zones, nil)
+		return ops, err
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		err = errors.Annotate(err, "failed to create new controller machines")
+		return ControllersChanges{}, err
+	}
+	return change, nil
+}
 
-		intent, err := st.enableHAIntentions(currentInfo, placement)
-		if err != nil {
-			return nil, err
+// enableHAOps returns the operations to make the number of voting
+// controllers equal to numControllers, as described for EnableHAInZones.
+// If decommission is not nil, that controller machine is removed, and
+// its vote replaced if necessary, in the same transaction.
+func (st *State) enableHAOps(
+	numControllers int,
+	cons constraints.Value,
+	series string,
+	placement, zones []string,
+	decommission *Machine,
+) ([]txn.Op, ControllersChanges, error) {
+	currentInfo, err := st.ControllerInfo()
+	if err != nil {
+		return nil, ControllersChanges{}, err
+	}
+	desiredControllerCount := numControllers
+	if desiredControllerCount == 0 {
+		desiredControllerCount = len(currentInfo.VotingMachineIds)
+		if desiredControllerCount <= 1 {
+			desiredControllerCount = 3
 		}
-		voteCount := 0
-		for _, m := range intent.maintain {
-			if m.WantsVote() {
-				voteCount++
-			}
+	}
+
+	intent, err := st.enableHAIntentions(currentInfo, placement)
+	if err != nil {
+		return nil, ControllersChanges{}, err
+	}
+	if decommission != nil {
+		intent.decommissionMachine(decommission)
+	}
+	var voters []*Machine
+	for _, m := range intent.maintain {
+		if m.WantsVote() {
+			voters = append(voters, m)
 		}
-		if voteCount == desiredControllerCount && len(intent.remove) == 0 {
-			return nil, jujutxn.ErrNoOperations
+	}
+	voteCount := len(voters)
+	if len(currentInfo.VotingMachineIds) > desiredControllerCount {
+		// Reduce the controller count, removing unavailable
+		// controllers rather than keeping them without a vote.
+		for _, m := range intent.demote {
+			intent.decommissionMachine(m)
 		}
-		// Promote as many machines as we can to fulfil the shortfall.
-		if n := desiredControllerCount - voteCount; n < len(intent.promote) {
-			intent.promote = intent.promote[:n]
+	}
+	if voteCount > desiredControllerCount {
+		// Decommission the voters whose removal disrupts the
+		// replicaset least. Their votes are removed by the worker
+		// that maintains the replicaset, which then removes the
+		// machines.
+		sort.Sort(decommissionOrder{
+			machines:  voters,
+			primaryId: st.replicaSetPrimaryMachineId(),
+		})
+		for _, m := range voters[:voteCount-desiredControllerCount] {
+			intent.decommissionMachine(m)
 		}
-		voteCount += len(intent.promote)
+		voteCount = desiredControllerCount
+		intent.promote = nil
+		intent.convert = nil
+	}
+	if voteCount == desiredControllerCount && len(intent.remove) == 0 && len(intent.decommission) == 0 {
+		return nil, ControllersChanges{}, jujutxn.ErrNoOperations
+	}
+	// Promote as many machines as we can to fulfil the shortfall.
+	if n := desiredControllerCount - voteCount; n < len(intent.promote) {
+		intent.promote = intent.promote[:n]
+	}
+	voteCount += len(intent.promote)
 
-		if n := desiredControllerCount - voteCount; n < len(intent.convert) {
-			intent.convert = intent.convert[:n]
-		}
-		voteCount += len(intent.convert)
+	if n := desiredControllerCount - voteCount; n < len(intent.convert) {
+		intent.convert = intent.convert[:n]
+	}
+	voteCount += len(intent.convert)
 
-		intent.newCount = desiredControllerCount - voteCount
+	intent.newCount = desiredControllerCount - voteCount
+	intent.zones = zones
 
-		logger.Infof("%d new machines; promoting %v; converting %v", intent.newCount, intent.promote, intent.convert)
+	logger.Infof("%d new machines; promoting %v; converting %v; decommissioning %v",
+		intent.newCount, intent.promote, intent.convert, intent.decommission)
 
-		var ops []txn.Op
-		ops, change, err = st.enableHAIntentionOps(intent, currentInfo, cons, series)
-		return ops, err
-	}
-	if err := st.db().Run(buildTxn); err != nil {
-		err = errors.Annotate(err, "failed to create new controller machines")
-		return ControllersChanges{}, err
-	}
-	return change, nil
+	return st.enableHAIntentionOps(intent, currentInfo, cons, series)
 }
 
 // Change in controllers after the ensure availability txn has committed.
@@ -898,7 +952,18 @@ func (st *State) enableHAIntentionOps(
 	cons constraints.Value,
 	series string,
 ) ([]txn.Op, ControllersChanges, error) {
-	var ops []txn.Op
+	// Changes to the controllers are based on the current controller
+	// info, so assert that it is unchanged.
+	ops := []txn.Op{{
+		C:  controllersC,
+		Id: modelGlobalKey,
+		Assert: bson.D{{
+			"$and", []bson.D{
+				{{"machineids", bson.D{{"$size", len(currentInfo.MachineIds)}}}},
+				{{"votingmachineids", bson.D{{"$size", len(currentInfo.VotingMachineIds)}}}},
+			},
+		}},
+	}}
 	var change ControllersChanges
 	for _, m := range intent.promote {
 		ops = append(ops, promoteControllerOps(m)...)
@@ -908,6 +973,10 @@ func (st *State) enableHAIntentionOps(
 		ops = append(ops, demoteControllerOps(m)...)
 		change.Demoted = append(change.Demoted, m.doc.Id)
 	}
+	for _, m := range intent.decommission {
+		ops = append(ops, decommissionControllerOps(m)...)
+		change.Demoted = append(change.Demoted, m.doc.Id)
+	}
 	for _, m := range intent.convert {
 		ops = append(ops, convertControllerOps(m)...)
 		change.Converted = append(change.Converted, m.doc.Id)
@@ -916,9 +985,17 @@ func (st *State) enableHAIntentionOps(
 	// when adding new machines, until the directives have
 	// been all used up. Ignore constraints for provided machines.
 	// Set up a helper function to do the work required.
+	zoneCounts, err := controllerZoneCounts(intent)
+	if err != nil {
+		return nil, ControllersChanges{}, errors.Trace(err)
+	}
 	placementCount := 0
 	getPlacementConstraints := func() (string, constraints.Value) {
 		if placementCount >= len(intent.placement) {
+			if zone := leastPopulatedZone(intent.zones, zoneCounts); zone != "" {
+				zoneCounts[zone]++
+				return "zone=" + zone, cons
+			}
 			return "", cons
 		}
 		result := intent.placement[placementCount]
@@ -979,11 +1056,65 @@ var controllerAvailable = func(m *Machine) (bool, error) {
 	return m.AgentPresence()
 }
 
+// controllerZoneCounts returns the number of controllers which will
+// remain after the intent is fulfilled in each of the intent's zones.
+func controllerZoneCounts(intent *enableHAIntent) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(intent.zones) == 0 {
+		return counts, nil
+	}
+	var machines []*Machine
+	machines = append(machines, intent.maintain...)
+	machines = append(machines, intent.promote...)
+	machines = append(machines, intent.convert...)
+	for _, m := range machines {
+		zone, err := controllerZone(m)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if zone != "" {
+			counts[zone]++
+		}
+	}
+	return counts, nil
+}
+
+// controllerZone returns the availability zone of the controller
+// machine: the zone it was provisioned in or, if it has not yet been
+// provisioned, the zone it was placed in.
+func controllerZone(m *Machine) (string, error) {
+	zone, err := m.AvailabilityZone()
+	if errors.IsNotProvisioned(err) {
+		if strings.HasPrefix(m.Placement(), "zone=") {
+			return strings.TrimPrefix(m.Placement(), "zone="), nil
+		}
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot get availability zone of machine %q", m.Id())
+	}
+	return zone, nil
+}
+
+// leastPopulatedZone returns the zone with the lowest count, preferring
+// zones earlier in the list. It returns the empty string if there are
+// no zones.
+func leastPopulatedZone(zones []string, counts map[string]int) string {
+	var result string
+	for _, zone := range zones {
+		if result == "" || counts[zone] < counts[result] {
+			result = zone
+		}
+	}
+	return result
+}
+
 type enableHAIntent struct {
 	newCount  int
 	placement []string
+	zones     []string
 
-	promote, maintain, demote, remove, convert []*Machine
+	promote, maintain, demote, remove, convert, decommission []*Machine
 }
 
 // enableHAIntentions returns what we would like
@@ -1036,6 +1167,11 @@ func (st *State) enableHAIntentions(info *ControllerInfo, placement []string) (*
 			return nil, err
 		}
 		logger.Infof("machine %q, available %v, wants vote %v, has vote %v", m, available, m.WantsVote(), m.HasVote())
+		if m.IsDecommissioning() {
+			// The machine is being removed by the worker that
+			// maintains the replicaset, so leave it alone.
+			continue
+		}
 		if available {
 			if m.WantsVote() {
 				intent.maintain = append(intent.maintain, m)
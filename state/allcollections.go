@@ -413,6 +413,15 @@ func allCollections() collectionSchema {
 			}},
 		},
 
+		// This collection holds the most recent hook and action
+		// executions of each unit, with the tail of their output.
+		unitHookExecutionsC: {
+			rawAccess: true,
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "unit", "seq"},
+			}},
+		},
+
 		// This collection holds information about cloud image metadata.
 		cloudimagemetadataC: {
 			global: true,
@@ -446,6 +455,17 @@ func allCollections() collectionSchema {
 		// firewallRulesC holds firewall rules for defined service types.
 		firewallRulesC: {},
 
+		// secretsC holds the encrypted secrets defined by charms.
+		secretsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "owner"},
+			}},
+		},
+
+		// podSpecsC holds the pod specs set by the charms of
+		// applications deployed to CAAS models.
+		podSpecsC: {},
+
 		// ----------------------
 
 		// Raw-access collections
@@ -534,6 +554,7 @@ const (
 	txnLogC                  = "txns.log"
 	txnsC                    = "txns"
 	unitsC                   = "units"
+	unitHookExecutionsC      = "unithookexecutions"
 	upgradeInfoC             = "upgradeInfo"
 	userLastLoginC           = "userLastLogin"
 	usermodelnameC           = "usermodelname"
@@ -550,4 +571,10 @@ const (
 	externalControllersC = "externalControllers"
 	relationNetworksC    = "relationNetworks"
 	firewallRulesC       = "firewallRules"
+
+	// Charm secrets
+	secretsC = "secrets"
+
+	// CAAS
+	podSpecsC = "podspecs"
 )
@@ -9,9 +9,11 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils"
 	"github.com/juju/utils/series"
 	"gopkg.in/juju/charm.v6-unstable"
 	csparams "gopkg.in/juju/charmrepo.v2-unstable/csclient/params"
@@ -22,6 +24,7 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/status"
 )
 
@@ -50,6 +53,31 @@ type applicationDoc struct {
 	MinUnits             int        `bson:"minunits"`
 	TxnRevno             int64      `bson:"txn-revno"`
 	MetricCredentials    []byte     `bson:"metric-credentials"`
+
+	// PasswordHash is the hash of the password used by the
+	// application's operator agent, for applications deployed to a
+	// CAAS model.
+	PasswordHash string `bson:"passwordhash,omitempty"`
+
+	// ExposedEndpoints records the endpoint specific expose settings,
+	// keyed on endpoint name. See ExposedEndpoint.
+	ExposedEndpoints map[string]ExposedEndpoint `bson:"exposed-endpoints,omitempty"`
+
+	// EgressRules records the destinations to which the application's
+	// units may send traffic. See Application.EgressRules.
+	EgressRules []egressRuleDoc `bson:"egress-rules,omitempty"`
+
+	// UpdateStatusInterval overrides the model's update-status hook
+	// interval for the application's units, if non-zero.
+	UpdateStatusInterval time.Duration `bson:"update-status-interval,omitempty"`
+
+	// HookTimeout limits how long the hooks of the application's units
+	// may run, if non-zero. See Application.HookTimeoutPolicy.
+	HookTimeout time.Duration `bson:"hook-timeout,omitempty"`
+
+	// HookTimeoutNoRetry records that hooks that time out are not
+	// retried automatically.
+	HookTimeoutNoRetry bool `bson:"hook-timeout-no-retry,omitempty"`
 }
 
 func newApplication(st *State, doc *applicationDoc) *Application {
@@ -326,12 +354,23 @@ func (a *Application) removeOps(asserts bson.D) ([]txn.Op, error) {
 	globalKey := a.globalKey()
 	ops = append(ops,
 		removeEndpointBindingsOp(globalKey),
+		removePodSpecOp(globalKey),
 		removeConstraintsOp(globalKey),
 		annotationRemoveOp(a.st, globalKey),
 		removeLeadershipSettingsOp(name),
 		removeStatusOp(a.st, globalKey),
 		removeModelApplicationRefOp(a.st, name),
 	)
+	firewallRuleOps, err := removeApplicationFirewallRulesOps(a.st, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, firewallRuleOps...)
+	secretOps, err := removeApplicationSecretsOps(a.st, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, secretOps...)
 	return ops, nil
 }
 
@@ -348,23 +387,135 @@ func (a *Application) SetExposed() error {
 	return a.setExposed(true)
 }
 
-// ClearExposed removes the exposed flag from the application.
+// ClearExposed removes the exposed flag from the application, along
+// with any endpoint specific expose settings.
 // See SetExposed and IsExposed.
 func (a *Application) ClearExposed() error {
 	return a.setExposed(false)
 }
 
 func (a *Application) setExposed(exposed bool) (err error) {
+	update := bson.D{{"$set", bson.D{{"exposed", exposed}}}}
+	if !exposed {
+		update = append(update, bson.DocElem{"$unset", bson.D{{"exposed-endpoints", nil}}})
+	}
 	ops := []txn.Op{{
 		C:      applicationsC,
 		Id:     a.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: update,
 	}}
 	if err := a.st.db().RunTransaction(ops); err != nil {
 		return errors.Errorf("cannot set exposed flag for application %q to %v: %v", a, exposed, onAbort(err, errNotAlive))
 	}
 	a.doc.Exposed = exposed
+	if !exposed {
+		a.doc.ExposedEndpoints = nil
+	}
+	return nil
+}
+
+// UpdateStatusInterval returns how often the update-status hook runs
+// for the application's units, if the application overrides the
+// model's update-status-hook-interval; it returns zero otherwise.
+func (a *Application) UpdateStatusInterval() time.Duration {
+	return a.doc.UpdateStatusInterval
+}
+
+// SetUpdateStatusInterval sets how often the update-status hook runs
+// for the application's units. Setting a zero interval reverts to the
+// model's update-status-hook-interval.
+func (a *Application) SetUpdateStatusInterval(interval time.Duration) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set update status interval for application %q", a)
+	if interval != 0 {
+		if interval < config.MinUpdateStatusHookInterval {
+			return errors.NotValidf("interval %v less than %v", interval, config.MinUpdateStatusHookInterval)
+		}
+		if interval > config.MaxUpdateStatusHookInterval {
+			return errors.NotValidf("interval %v greater than %v", interval, config.MaxUpdateStatusHookInterval)
+		}
+	}
+	update := bson.D{{"$set", bson.D{{"update-status-interval", interval}}}}
+	if interval == 0 {
+		update = bson.D{{"$unset", bson.D{{"update-status-interval", nil}}}}
+	}
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: isAliveDoc,
+		Update: update,
+	}}
+	if err := a.st.db().RunTransaction(ops); err != nil {
+		return onAbort(err, errNotAlive)
+	}
+	a.doc.UpdateStatusInterval = interval
+	return nil
+}
+
+// MinHookTimeout is the shortest hook timeout that may be set for an
+// application.
+const MinHookTimeout = 10 * time.Second
+
+// HookTimeoutPolicy describes how long the hooks of an application's
+// units may run before they are killed, and what happens then.
+type HookTimeoutPolicy struct {
+	// Timeout is how long a hook may run. If it is zero, the timeout
+	// declared by the charm, if any, applies.
+	Timeout time.Duration
+
+	// Retry records whether hooks that time out are retried
+	// automatically, as other failed hooks are when the model's
+	// automatically-retry-hooks setting is true. If it is false, a
+	// unit whose hook timed out stays in error until it is resolved.
+	Retry bool
+}
+
+// HookTimeoutPolicy returns the hook timeout policy of the
+// application's units.
+func (a *Application) HookTimeoutPolicy() HookTimeoutPolicy {
+	return HookTimeoutPolicy{
+		Timeout: a.doc.HookTimeout,
+		Retry:   !a.doc.HookTimeoutNoRetry,
+	}
+}
+
+// SetHookTimeoutPolicy sets the hook timeout policy of the
+// application's units. A zero timeout reverts to the timeout declared
+// by the charm.
+func (a *Application) SetHookTimeoutPolicy(policy HookTimeoutPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set hook timeout for application %q", a)
+	if policy.Timeout != 0 && policy.Timeout < MinHookTimeout {
+		return errors.NotValidf("timeout %v less than %v", policy.Timeout, MinHookTimeout)
+	}
+	var set, unset bson.D
+	if policy.Timeout == 0 {
+		unset = append(unset, bson.DocElem{"hook-timeout", nil})
+	} else {
+		set = append(set, bson.DocElem{"hook-timeout", policy.Timeout})
+	}
+	if policy.Retry {
+		unset = append(unset, bson.DocElem{"hook-timeout-no-retry", nil})
+	} else {
+		set = append(set, bson.DocElem{"hook-timeout-no-retry", true})
+	}
+	var update bson.D
+	if len(set) > 0 {
+		update = append(update, bson.DocElem{"$set", set})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.DocElem{"$unset", unset})
+	}
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: isAliveDoc,
+		Update: update,
+	}}
+	if err := a.st.db().RunTransaction(ops); err != nil {
+		return onAbort(err, errNotAlive)
+	}
+	a.doc.HookTimeout = policy.Timeout
+	a.doc.HookTimeoutNoRetry = !policy.Retry
 	return nil
 }
 
@@ -1539,6 +1690,8 @@ func (a *Application) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 		removeMeterStatusOp(a.st, u.globalMeterStatusKey()),
 		removeStatusOp(a.st, u.globalAgentKey()),
 		removeStatusOp(a.st, u.globalKey()),
+		removeStatusOp(a.st, u.globalHealthKey()),
+		removeStatusOp(a.st, u.globalContainerKey()),
 		removeConstraintsOp(u.globalAgentKey()),
 		annotationRemoveOp(a.st, u.globalKey()),
 		newCleanupOp(cleanupRemovedUnit, u.doc.Name),
@@ -1845,6 +1998,40 @@ func (a *Application) MetricCredentials() []byte {
 	return a.doc.MetricCredentials
 }
 
+// SetPassword sets the password for the application's operator agent.
+func (a *Application) SetPassword(password string) error {
+	if len(password) < utils.MinAgentPasswordLength {
+		return errors.Errorf("password is only %d bytes long, and is not a valid Agent password", len(password))
+	}
+	passwordHash := utils.AgentPasswordHash(password)
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"passwordhash", passwordHash}}}},
+	}}
+	if err := a.st.db().RunTransaction(ops); err != nil {
+		return errors.Errorf("cannot set password of application %q: %v", a, onAbort(err, ErrDead))
+	}
+	a.doc.PasswordHash = passwordHash
+	return nil
+}
+
+// PasswordValid returns whether the given password is valid for the
+// application's operator agent.
+func (a *Application) PasswordValid(password string) bool {
+	if a.doc.PasswordHash == "" {
+		return false
+	}
+	return utils.AgentPasswordHash(password) == a.doc.PasswordHash
+}
+
+// HasPassword returns whether a password has been set for the
+// application's operator agent.
+func (a *Application) HasPassword() bool {
+	return a.doc.PasswordHash != ""
+}
+
 // SetMetricCredentials updates the metric credentials associated with this application.
 func (a *Application) SetMetricCredentials(b []byte) error {
 	buildTxn := func(attempt int) ([]txn.Op, error) {
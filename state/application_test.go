@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/resource/resourcetesting"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/testing"
@@ -1735,6 +1737,224 @@ func (s *ApplicationSuite) TestServiceExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ApplicationSuite) TestMergeExposeSettings(c *gc.C) {
+	_, err := s.State.AddSpace("public", "", nil, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {
+			ExposeToSpaces: []string{"public"},
+			ExposeToCIDRs:  []string{"10.0.0.0/8"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+
+	// Settings for other endpoints are merged, and those for an
+	// existing endpoint are replaced.
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server-admin": {ExposeToCIDRs: []string{"192.168.0.0/24"}},
+		"server":       {ExposeToSpaces: []string{"public"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"server":       {ExposeToSpaces: []string{"public"}},
+		"server-admin": {ExposeToCIDRs: []string{"192.168.0.0/24"}},
+	})
+}
+
+func (s *ApplicationSuite) TestMergeExposeSettingsInvalid(c *gc.C) {
+	err := s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"bogus": {},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot update expose settings for application "mysql": endpoint "bogus" not found`)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToSpaces: []string{"missing"}},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot update expose settings for application "mysql": space "missing" not found`)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToCIDRs: []string{"10.0.0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot update expose settings for application "mysql": CIDR "10.0.0" not valid`)
+
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+}
+
+func (s *ApplicationSuite) TestUnsetExposeSettings(c *gc.C) {
+	err := s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		state.WildcardEndpoint: {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		"server":               {ExposeToCIDRs: []string{"192.168.0.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.UnsetExposeSettings([]string{"server-admin"})
+	c.Assert(err, gc.ErrorMatches, `cannot update expose settings for application "mysql": expose settings for endpoint "server-admin" not found`)
+
+	err = s.mysql.UnsetExposeSettings([]string{"server"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		state.WildcardEndpoint: {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+
+	// Removing the last of the settings unexposes the application.
+	err = s.mysql.UnsetExposeSettings([]string{state.WildcardEndpoint})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+	c.Assert(s.mysql.ExposedEndpoints(), gc.HasLen, 0)
+}
+
+func (s *ApplicationSuite) TestClearExposedRemovesExposeSettings(c *gc.C) {
+	err := s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+	c.Assert(s.mysql.ExposedEndpoints(), gc.HasLen, 0)
+}
+
+func (s *ApplicationSuite) TestSetUpdateStatusInterval(c *gc.C) {
+	c.Assert(s.mysql.UpdateStatusInterval(), gc.Equals, time.Duration(0))
+
+	err := s.mysql.SetUpdateStatusInterval(15 * time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.UpdateStatusInterval(), gc.Equals, 15*time.Minute)
+
+	// A zero interval reverts to the model's interval.
+	err = s.mysql.SetUpdateStatusInterval(0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.UpdateStatusInterval(), gc.Equals, time.Duration(0))
+}
+
+func (s *ApplicationSuite) TestSetUpdateStatusIntervalInvalid(c *gc.C) {
+	err := s.mysql.SetUpdateStatusInterval(time.Second)
+	c.Assert(err, gc.ErrorMatches, `cannot set update status interval for application "mysql": interval 1s less than 1m0s not valid`)
+	err = s.mysql.SetUpdateStatusInterval(2 * time.Hour)
+	c.Assert(err, gc.ErrorMatches, `cannot set update status interval for application "mysql": interval 2h0m0s greater than 1h0m0s not valid`)
+}
+
+func (s *ApplicationSuite) TestSetUpdateStatusIntervalNotAlive(c *gc.C) {
+	err := s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetUpdateStatusInterval(15 * time.Minute)
+	c.Assert(err, gc.ErrorMatches, `cannot set update status interval for application "mysql": not found or not alive`)
+}
+
+func (s *ApplicationSuite) TestSetPassword(c *gc.C) {
+	testSetPassword(c, func() (state.Authenticator, error) {
+		return s.State.Application(s.mysql.Name())
+	})
+}
+
+func (s *ApplicationSuite) TestPasswordValidUnset(c *gc.C) {
+	c.Assert(s.mysql.PasswordValid(""), jc.IsFalse)
+}
+
+func (s *ApplicationSuite) TestHasPassword(c *gc.C) {
+	c.Assert(s.mysql.HasPassword(), jc.IsFalse)
+	err := s.mysql.SetPassword("foo-12345678901234567890")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.HasPassword(), jc.IsTrue)
+}
+
+func (s *ApplicationSuite) TestSetHookTimeoutPolicy(c *gc.C) {
+	c.Assert(s.mysql.HookTimeoutPolicy(), jc.DeepEquals, state.HookTimeoutPolicy{Retry: true})
+
+	policy := state.HookTimeoutPolicy{Timeout: 10 * time.Minute}
+	err := s.mysql.SetHookTimeoutPolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.HookTimeoutPolicy(), jc.DeepEquals, policy)
+
+	// A zero timeout reverts to the charm's timeout.
+	err = s.mysql.SetHookTimeoutPolicy(state.HookTimeoutPolicy{Retry: true})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.HookTimeoutPolicy(), jc.DeepEquals, state.HookTimeoutPolicy{Retry: true})
+}
+
+func (s *ApplicationSuite) TestSetHookTimeoutPolicyInvalid(c *gc.C) {
+	err := s.mysql.SetHookTimeoutPolicy(state.HookTimeoutPolicy{Timeout: time.Second})
+	c.Assert(err, gc.ErrorMatches, `cannot set hook timeout for application "mysql": timeout 1s less than 10s not valid`)
+}
+
+func (s *ApplicationSuite) TestSetHookTimeoutPolicyNotAlive(c *gc.C) {
+	err := s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetHookTimeoutPolicy(state.HookTimeoutPolicy{Timeout: time.Minute})
+	c.Assert(err, gc.ErrorMatches, `cannot set hook timeout for application "mysql": not found or not alive`)
+}
+
+func (s *ApplicationSuite) TestSetEgressRules(c *gc.C) {
+	rules, err := s.mysql.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	expected := []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+		network.MustNewEgressRule("udp", 53, 53, "10.0.0.2/32", "10.0.0.3/32"),
+	}
+	err = s.mysql.SetEgressRules(expected)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = s.mysql.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, expected)
+
+	// Setting no rules removes the restriction.
+	err = s.mysql.SetEgressRules(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = s.mysql.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+}
+
+func (s *ApplicationSuite) TestSetEgressRulesInvalid(c *gc.C) {
+	err := s.mysql.SetEgressRules([]network.EgressRule{{
+		PortRange: network.MustParsePortRange("443/tcp"),
+	}})
+	c.Assert(err, gc.ErrorMatches, `cannot set egress rules for application "mysql": egress rule for 443/tcp without destinations not valid`)
+
+	err = s.mysql.SetEgressRules([]network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+		network.MustNewEgressRule("tcp", 443, 443, "192.168.0.0/16"),
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot set egress rules for application "mysql": duplicate egress rule for 443/tcp not valid`)
+}
+
+func (s *ApplicationSuite) TestSetEgressRulesNotAlive(c *gc.C) {
+	_, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.SetEgressRules([]network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8"),
+	})
+	c.Assert(err, gc.ErrorMatches, notAliveErr)
+}
+
 func (s *ApplicationSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	unitZero, err := s.mysql.AddUnit(state.AddUnitParams{})
@@ -2521,6 +2741,33 @@ func (s *ApplicationSuite) TestWatchApplication(c *gc.C) {
 	testing.NewNotifyWatcherC(c, s.State, w).AssertOneChange()
 }
 
+func (s *ApplicationSuite) TestWatchApplicationCharms(c *gc.C) {
+	w := s.State.WatchApplicationCharms()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Changes that leave the charm alone are ignored.
+	err := s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Upgrading the charm triggers an event.
+	sch := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err = s.mysql.SetCharm(state.SetCharmConfig{Charm: sch})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// So does adding an application.
+	s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wc.AssertOneChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *ApplicationSuite) TestMetricCredentials(c *gc.C) {
 	err := s.mysql.SetMetricCredentials([]byte("hello there"))
 	c.Assert(err, jc.ErrorIsNil)
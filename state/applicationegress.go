@@ -0,0 +1,84 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// egressRuleDoc represents an application egress rule in MongoDB.
+type egressRuleDoc struct {
+	PortRange        string   `bson:"port-range"`
+	DestinationCIDRs []string `bson:"destination-cidrs"`
+}
+
+// EgressRules returns the rules describing the destinations to which
+// the application's units may send traffic. If there are no rules,
+// outbound traffic is not restricted.
+func (a *Application) EgressRules() ([]network.EgressRule, error) {
+	if len(a.doc.EgressRules) == 0 {
+		return nil, nil
+	}
+	rules := make([]network.EgressRule, len(a.doc.EgressRules))
+	for i, doc := range a.doc.EgressRules {
+		portRange, err := network.ParsePortRange(doc.PortRange)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid egress rule for application %q", a)
+		}
+		rules[i] = network.EgressRule{
+			PortRange:        portRange,
+			DestinationCIDRs: doc.DestinationCIDRs,
+		}
+	}
+	return rules, nil
+}
+
+// SetEgressRules replaces the application's egress rules with those
+// provided. Setting an empty list of rules removes any restriction on
+// the destinations to which the application's units may send traffic.
+func (a *Application) SetEgressRules(rules []network.EgressRule) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set egress rules for application %q", a)
+
+	docs := make([]egressRuleDoc, len(rules))
+	seen := make(map[string]bool)
+	for i, rule := range rules {
+		if _, err := network.NewEgressRule(
+			rule.Protocol, rule.FromPort, rule.ToPort, rule.DestinationCIDRs...,
+		); err != nil {
+			return errors.Trace(err)
+		}
+		portRange := rule.PortRange.String()
+		if seen[portRange] {
+			return errors.NotValidf("duplicate egress rule for %v", portRange)
+		}
+		seen[portRange] = true
+		docs[i] = egressRuleDoc{
+			PortRange:        portRange,
+			DestinationCIDRs: rule.DestinationCIDRs,
+		}
+	}
+
+	update := bson.D{{"$set", bson.D{{"egress-rules", docs}}}}
+	if len(docs) == 0 {
+		update = bson.D{{"$unset", bson.D{{"egress-rules", nil}}}}
+	}
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: isAliveDoc,
+		Update: update,
+	}}
+	if err := a.st.db().RunTransaction(ops); err != nil {
+		return onAbort(err, errNotAlive)
+	}
+	if len(docs) == 0 {
+		docs = nil
+	}
+	a.doc.EgressRules = docs
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// WildcardEndpoint is the endpoint name used to record expose
+// settings that apply to all of an application's endpoints.
+const WildcardEndpoint = ""
+
+// ExposedEndpoint encapsulates the expose-related settings for
+// a particular application endpoint.
+type ExposedEndpoint struct {
+	// ExposeToSpaces contains the names of the spaces whose subnets
+	// should be able to access the ports opened for the endpoint.
+	ExposeToSpaces []string `bson:"to-spaces,omitempty"`
+
+	// ExposeToCIDRs contains the CIDRs that should be able to access
+	// the ports opened for the endpoint.
+	ExposeToCIDRs []string `bson:"to-cidrs,omitempty"`
+}
+
+// ExposedEndpoints returns the endpoint specific expose settings for
+// the application, keyed on endpoint name. An application may be
+// exposed without having any endpoint specific settings, in which
+// case all of its opened ports are exposed.
+func (a *Application) ExposedEndpoints() map[string]ExposedEndpoint {
+	if len(a.doc.ExposedEndpoints) == 0 {
+		return nil
+	}
+	result := make(map[string]ExposedEndpoint, len(a.doc.ExposedEndpoints))
+	for name, exp := range a.doc.ExposedEndpoints {
+		result[name] = exp
+	}
+	return result
+}
+
+// MergeExposeSettings marks the application as exposed and merges the
+// provided endpoint expose settings into the existing ones, replacing
+// the settings of any endpoint that is already present.
+func (a *Application) MergeExposeSettings(exposedEndpoints map[string]ExposedEndpoint) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot update expose settings for application %q", a)
+
+	if err := a.validateExposeSettings(exposedEndpoints); err != nil {
+		return errors.Trace(err)
+	}
+	app := &Application{st: a.st, doc: a.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := app.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if app.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		merged := app.ExposedEndpoints()
+		if merged == nil {
+			merged = make(map[string]ExposedEndpoint)
+		}
+		for name, exp := range exposedEndpoints {
+			merged[name] = exp
+		}
+		return app.setExposeSettingsOps(true, merged), nil
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	return a.Refresh()
+}
+
+// UnsetExposeSettings removes the expose settings for the provided
+// endpoints. If no endpoint settings remain once they have been
+// removed, the application is no longer exposed.
+func (a *Application) UnsetExposeSettings(endpoints []string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot update expose settings for application %q", a)
+
+	app := &Application{st: a.st, doc: a.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := app.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if app.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		if len(endpoints) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		remaining := app.ExposedEndpoints()
+		for _, name := range endpoints {
+			if _, ok := remaining[name]; !ok {
+				return nil, errors.NotFoundf("expose settings for endpoint %q", name)
+			}
+			delete(remaining, name)
+		}
+		return app.setExposeSettingsOps(len(remaining) > 0, remaining), nil
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	return a.Refresh()
+}
+
+func (a *Application) setExposeSettingsOps(exposed bool, exposedEndpoints map[string]ExposedEndpoint) []txn.Op {
+	var update bson.D
+	if len(exposedEndpoints) > 0 {
+		update = bson.D{{"$set", bson.D{
+			{"exposed", exposed},
+			{"exposed-endpoints", exposedEndpoints},
+		}}}
+	} else {
+		update = bson.D{
+			{"$set", bson.D{{"exposed", exposed}}},
+			{"$unset", bson.D{{"exposed-endpoints", nil}}},
+		}
+	}
+	return []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: bson.D{{"life", Alive}, {"txn-revno", a.doc.TxnRevno}},
+		Update: update,
+	}}
+}
+
+// validateExposeSettings checks that the provided endpoints (relations
+// or extra-bindings) are defined by the application's charm, that any
+// referenced spaces exist and that any CIDRs are well formed.
+func (a *Application) validateExposeSettings(exposedEndpoints map[string]ExposedEndpoint) error {
+	if len(exposedEndpoints) == 0 {
+		return nil
+	}
+	ch, _, err := a.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	known := make(map[string]bool)
+	for name := range ch.Meta().CombinedRelations() {
+		known[name] = true
+	}
+	for name := range ch.Meta().ExtraBindings {
+		known[name] = true
+	}
+	for name, exp := range exposedEndpoints {
+		if name != WildcardEndpoint && !known[name] {
+			return errors.NotFoundf("endpoint %q", name)
+		}
+		for _, spaceName := range exp.ExposeToSpaces {
+			if _, err := a.st.Space(spaceName); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		for _, cidr := range exp.ExposeToCIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return errors.NotValidf("CIDR %q", cidr)
+			}
+		}
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/mongo"
 	mongoutils "github.com/juju/juju/mongo/utils"
 	"github.com/juju/juju/state/storage"
@@ -81,6 +82,73 @@ type charmDoc struct {
 	Config  *charm.Config  `bson:"config"`
 	Actions *charm.Actions `bson:"actions"`
 	Metrics *charm.Metrics `bson:"metrics"`
+
+	// LXDProfile holds the LXD profile declared by the charm, if any.
+	LXDProfile *lxdProfileDoc `bson:"lxd-profile,omitempty"`
+}
+
+// lxdProfileDoc holds a charm's LXD profile. LXD config keys contain
+// dots, so all map keys are escaped before being stored.
+type lxdProfileDoc struct {
+	Config      map[string]string            `bson:"config,omitempty"`
+	Description string                       `bson:"description,omitempty"`
+	Devices     map[string]map[string]string `bson:"devices,omitempty"`
+}
+
+func newLXDProfileDoc(profile *lxdprofile.Profile) *lxdProfileDoc {
+	if profile.Empty() {
+		return nil
+	}
+	doc := &lxdProfileDoc{
+		Config:      escapeStringMapKeys(profile.Config),
+		Description: profile.Description,
+	}
+	if len(profile.Devices) > 0 {
+		doc.Devices = make(map[string]map[string]string)
+		for name, device := range profile.Devices {
+			doc.Devices[escapeReplacer.Replace(name)] = escapeStringMapKeys(device)
+		}
+	}
+	return doc
+}
+
+func (doc *lxdProfileDoc) profile() *lxdprofile.Profile {
+	if doc == nil {
+		return nil
+	}
+	profile := &lxdprofile.Profile{
+		Config:      unescapeStringMapKeys(doc.Config),
+		Description: doc.Description,
+	}
+	if len(doc.Devices) > 0 {
+		profile.Devices = make(map[string]map[string]string)
+		for name, device := range doc.Devices {
+			profile.Devices[unescapeReplacer.Replace(name)] = unescapeStringMapKeys(device)
+		}
+	}
+	return profile
+}
+
+// escapeStringMapKeys returns a copy of the passed map with its keys
+// escaped for storage in mongo, or nil if the map is empty.
+func escapeStringMapKeys(in map[string]string) map[string]string {
+	return copyStringMapKeys(in, escapeReplacer)
+}
+
+// unescapeStringMapKeys is the inverse of escapeStringMapKeys.
+func unescapeStringMapKeys(in map[string]string) map[string]string {
+	return copyStringMapKeys(in, unescapeReplacer)
+}
+
+func copyStringMapKeys(in map[string]string, replacer *strings.Replacer) map[string]string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for key, value := range in {
+		out[replacer.Replace(key)] = value
+	}
+	return out
 }
 
 // CharmInfo contains all the data necessary to store a charm's metadata.
@@ -90,6 +158,9 @@ type CharmInfo struct {
 	StoragePath string
 	SHA256      string
 	Macaroon    macaroon.Slice
+
+	// LXDProfile holds the LXD profile declared by the charm, if any.
+	LXDProfile *lxdprofile.Profile
 }
 
 // insertCharmOps returns the txn operations necessary to insert the supplied
@@ -106,6 +177,7 @@ func insertCharmOps(mb modelBackend, info CharmInfo) ([]txn.Op, error) {
 		Config:       safeConfig(info.Charm),
 		Metrics:      info.Charm.Metrics(),
 		Actions:      info.Charm.Actions(),
+		LXDProfile:   newLXDProfileDoc(info.LXDProfile),
 		BundleSha256: info.SHA256,
 		StoragePath:  info.StoragePath,
 	}
@@ -210,6 +282,7 @@ func updateCharmOps(mb modelBackend, info CharmInfo, assert bson.D) ([]txn.Op, e
 		{"config", safeConfig(info.Charm)},
 		{"actions", info.Charm.Actions()},
 		{"metrics", info.Charm.Metrics()},
+		{"lxd-profile", newLXDProfileDoc(info.LXDProfile)},
 		{"storagepath", info.StoragePath},
 		{"bundlesha256", info.SHA256},
 		{"pendingupload", false},
@@ -454,6 +527,12 @@ func (c *Charm) Actions() *charm.Actions {
 	return c.doc.Actions
 }
 
+// LXDProfile returns the LXD profile declared by the charm, or nil
+// if the charm does not declare one.
+func (c *Charm) LXDProfile() *lxdprofile.Profile {
+	return c.doc.LXDProfile.profile()
+}
+
 // StoragePath returns the storage path of the charm bundle.
 func (c *Charm) StoragePath() string {
 	return c.doc.StoragePath
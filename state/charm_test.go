@@ -17,6 +17,7 @@ import (
 	"gopkg.in/macaroon.v1"
 	"gopkg.in/mgo.v2"
 
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/storage"
 	"github.com/juju/juju/testcharms"
@@ -281,6 +282,32 @@ func (s *CharmSuite) TestAddCharm(c *gc.C) {
 	c.Assert(doc.URL, gc.DeepEquals, info.ID)
 }
 
+func (s *CharmSuite) TestAddCharmWithLXDProfile(c *gc.C) {
+	info := s.dummyCharm(c, "")
+	info.LXDProfile = &lxdprofile.Profile{
+		Description: "test profile",
+		Config:      map[string]string{"security.nesting": "true"},
+		Devices: map[string]map[string]string{
+			"gpu": {"type": "gpu"},
+		},
+	}
+	dummy, err := s.State.AddCharm(info)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dummy.LXDProfile(), jc.DeepEquals, info.LXDProfile)
+
+	dummy, err = s.State.Charm(info.ID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dummy.LXDProfile(), jc.DeepEquals, info.LXDProfile)
+}
+
+func (s *CharmSuite) TestAddCharmWithoutLXDProfile(c *gc.C) {
+	info := s.dummyCharm(c, "")
+	info.LXDProfile = &lxdprofile.Profile{Description: "nothing to apply"}
+	dummy, err := s.State.AddCharm(info)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dummy.LXDProfile(), gc.IsNil)
+}
+
 func (s *CharmSuite) TestAddCharmWithAuth(c *gc.C) {
 	// Check that adding charms from scratch works correctly.
 	info := s.dummyCharm(c, "")
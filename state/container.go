@@ -44,15 +44,14 @@ func insertNewContainerRefOp(mb modelBackend, machineId string, children ...stri
 
 // removeContainerRefOps returns the txn.Op's necessary to remove a machine container record.
 // These include removing the record itself and updating the host machine's children property.
-func removeContainerRefOps(mb modelBackend, machineId string) []txn.Op {
+// The host machine is identified by parentId, which is empty if the machine is not a container.
+func removeContainerRefOps(mb modelBackend, machineId, parentId string) []txn.Op {
 	removeRefOp := txn.Op{
 		C:      containerRefsC,
 		Id:     mb.docID(machineId),
 		Assert: txn.DocExists,
 		Remove: true,
 	}
-	// If the machine is a container, figure out its parent host.
-	parentId := ParentId(machineId)
 	if parentId == "" {
 		return []txn.Op{removeRefOp}
 	}
@@ -43,6 +43,7 @@ type Controller struct {
 	policy                 Policy
 	newPolicy              NewPolicyFunc
 	runTransactionObserver RunTransactionObserverFunc
+	secretsKey             string
 }
 
 // Close the connection to the database.
@@ -67,6 +68,7 @@ func (ctlr *Controller) NewState(modelTag names.ModelTag) (*State, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	st.controllerSecretsKey = ctlr.secretsKey
 	if err := st.start(ctlr.controllerTag); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -7,9 +7,12 @@ import (
 	"net"
 
 	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
 )
 
 // FirewallRule instances describe the ingress networks
@@ -25,32 +28,90 @@ import (
 // - ssh
 // - juju-controller
 // - juju-application-offer
+// - application
+// The application service type is used for rules which restrict
+// ingress to the ports opened by the units of an exposed
+// application, optionally limited to a single port range.
 type FirewallRule struct {
 	// WellKnownService is the known service for the firewall rules entity.
 	WellKnownService WellKnownServiceType
 
+	// Application is the name of the application to which the rule
+	// applies. It is only set for application rules.
+	Application string
+
+	// PortRange, if set, limits an application rule to the
+	// specified port range. A zero value means the rule applies
+	// to all ports opened by the application's units.
+	PortRange network.PortRange
+
 	// WhitelistCIDRS is the whitelist CIDRs for the rule.
 	WhitelistCIDRs []string
 }
 
+// key returns the document id used to store the rule.
+func (r FirewallRule) key() string {
+	if r.WellKnownService != ApplicationRule {
+		return string(r.WellKnownService)
+	}
+	key := names.NewApplicationTag(r.Application).String()
+	if r.PortRange != (network.PortRange{}) {
+		key += "#" + r.PortRange.String()
+	}
+	return key
+}
+
+func (r FirewallRule) validate() error {
+	if err := r.WellKnownService.validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if r.WellKnownService != ApplicationRule {
+		if r.Application != "" || r.PortRange != (network.PortRange{}) {
+			return errors.NotValidf("application or port range for %q rule", r.WellKnownService)
+		}
+		return nil
+	}
+	if !names.IsValidApplication(r.Application) {
+		return errors.NotValidf("application name %q", r.Application)
+	}
+	if r.PortRange != (network.PortRange{}) {
+		if err := r.PortRange.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 type firewallRulesDoc struct {
 	Id               string   `bson:"_id"`
 	WellKnownService string   `bson:"known-service"`
+	Application      string   `bson:"application,omitempty"`
+	PortRange        string   `bson:"port-range,omitempty"`
 	WhitelistCIDRS   []string `bson:"whitelist-cidrs"`
 }
 
-func (r *firewallRulesDoc) toRule() *FirewallRule {
-	return &FirewallRule{
+func (r *firewallRulesDoc) toRule() (*FirewallRule, error) {
+	rule := &FirewallRule{
 		WellKnownService: WellKnownServiceType(r.WellKnownService),
+		Application:      r.Application,
 		WhitelistCIDRs:   r.WhitelistCIDRS,
 	}
+	if r.PortRange != "" {
+		portRange, err := network.ParsePortRange(r.PortRange)
+		if err != nil {
+			return nil, errors.Annotatef(err, "firewall rule %q", r.Id)
+		}
+		rule.PortRange = portRange
+	}
+	return rule, nil
 }
 
 // FirewallRuler instances provide access to firewall rules in state.
 type FirewallRuler interface {
-	Save(service WellKnownServiceType, whiteListCidrs []string) (FirewallRule, error)
-	Rule(service WellKnownServiceType) (FirewallRule, error)
-	AllRules() ([]FirewallRule, error)
+	Save(rule FirewallRule) error
+	Rule(service WellKnownServiceType) (*FirewallRule, error)
+	ApplicationRules(application string) ([]*FirewallRule, error)
+	AllRules() ([]*FirewallRule, error)
 }
 
 const (
@@ -62,6 +123,10 @@ const (
 
 	// JujuApplicationOfferRule is a rule for connections to a Juju offer.
 	JujuApplicationOfferRule = WellKnownServiceType("juju-application-offer")
+
+	// ApplicationRule is a rule for connections to the ports opened
+	// by the units of an exposed application.
+	ApplicationRule = WellKnownServiceType("application")
 )
 
 // WellKnownServiceType defines a service for which firewall rules may be applied.
@@ -69,7 +134,7 @@ type WellKnownServiceType string
 
 func (v WellKnownServiceType) validate() error {
 	switch v {
-	case SSHRule, JujuControllerRule, JujuApplicationOfferRule:
+	case SSHRule, JujuControllerRule, JujuApplicationOfferRule, ApplicationRule:
 		return nil
 	}
 	return errors.NotValidf("well known service type %q", v)
@@ -86,7 +151,7 @@ func NewFirewallRules(st *State) *firewallRulesState {
 
 // Save stores the specified firewall rule.
 func (fw *firewallRulesState) Save(rule FirewallRule) error {
-	if err := rule.WellKnownService.validate(); err != nil {
+	if err := rule.validate(); err != nil {
 		return errors.Trace(err)
 	}
 	for _, cidr := range rule.WhitelistCIDRs {
@@ -94,12 +159,16 @@ func (fw *firewallRulesState) Save(rule FirewallRule) error {
 			return errors.NotValidf("CIDR %q", cidr)
 		}
 	}
-	serviceStr := string(rule.WellKnownService)
+	key := rule.key()
 	doc := firewallRulesDoc{
-		Id:               serviceStr,
-		WellKnownService: serviceStr,
+		Id:               key,
+		WellKnownService: string(rule.WellKnownService),
+		Application:      rule.Application,
 		WhitelistCIDRS:   rule.WhitelistCIDRs,
 	}
+	if rule.PortRange != (network.PortRange{}) {
+		doc.PortRange = rule.PortRange.String()
+	}
 	buildTxn := func(int) ([]txn.Op, error) {
 		model, err := fw.st.Model()
 		if err != nil {
@@ -109,28 +178,41 @@ func (fw *firewallRulesState) Save(rule FirewallRule) error {
 			return nil, errors.Trace(err)
 		}
 
-		_, err = fw.Rule(rule.WellKnownService)
+		_, err = fw.ruleByKey(key)
 		if err != nil && !errors.IsNotFound(err) {
 			return nil, errors.Trace(err)
 		}
 		var ops []txn.Op
+		if rule.WellKnownService == ApplicationRule {
+			app, err := fw.st.Application(rule.Application)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if app.Life() != Alive {
+				return nil, errors.Errorf("application %q is not alive", rule.Application)
+			}
+			ops = append(ops, txn.Op{
+				C:      applicationsC,
+				Id:     app.doc.DocID,
+				Assert: isAliveDoc,
+			})
+		}
 		if err == nil {
-			ops = []txn.Op{{
+			ops = append(ops, txn.Op{
 				C:      firewallRulesC,
-				Id:     serviceStr,
+				Id:     key,
 				Assert: txn.DocExists,
 				Update: bson.D{
 					{"$set", bson.D{{"whitelist-cidrs", rule.WhitelistCIDRs}}},
 				},
-			}, model.assertActiveOp()}
+			}, model.assertActiveOp())
 		} else {
-			doc.WhitelistCIDRS = rule.WhitelistCIDRs
-			ops = []txn.Op{{
+			ops = append(ops, txn.Op{
 				C:      firewallRulesC,
 				Id:     doc.Id,
 				Assert: txn.DocMissing,
 				Insert: doc,
-			}, model.assertActiveOp()}
+			}, model.assertActiveOp())
 		}
 		return ops, nil
 	}
@@ -143,33 +225,75 @@ func (fw *firewallRulesState) Save(rule FirewallRule) error {
 
 // Rule returns the firewall rule for the specified service.
 func (fw *firewallRulesState) Rule(service WellKnownServiceType) (*FirewallRule, error) {
+	rule, err := fw.ruleByKey(string(service))
+	if errors.IsNotFound(err) {
+		return nil, errors.NotFoundf("firewall rules for service %v", service)
+	}
+	return rule, err
+}
+
+func (fw *firewallRulesState) ruleByKey(key string) (*FirewallRule, error) {
 	coll, closer := fw.st.db().GetCollection(firewallRulesC)
 	defer closer()
 
 	var doc firewallRulesDoc
-	err := coll.FindId(string(service)).One(&doc)
+	err := coll.FindId(key).One(&doc)
 	if err == mgo.ErrNotFound {
-		return nil, errors.NotFoundf("firewall rules for service %v", service)
+		return nil, errors.NotFoundf("firewall rule %v", key)
 	}
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return doc.toRule(), nil
+	return doc.toRule()
+}
+
+// ApplicationRules returns the firewall rules for the specified application.
+func (fw *firewallRulesState) ApplicationRules(application string) ([]*FirewallRule, error) {
+	return fw.rules(bson.D{
+		{"known-service", string(ApplicationRule)},
+		{"application", application},
+	})
 }
 
 // AllRules returns all the firewall rules.
 func (fw *firewallRulesState) AllRules() ([]*FirewallRule, error) {
+	return fw.rules(nil)
+}
+
+func (fw *firewallRulesState) rules(query bson.D) ([]*FirewallRule, error) {
 	coll, closer := fw.st.db().GetCollection(firewallRulesC)
 	defer closer()
 
 	var docs []firewallRulesDoc
-	err := coll.Find(nil).All(&docs)
+	err := coll.Find(query).All(&docs)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	result := make([]*FirewallRule, len(docs))
 	for i, doc := range docs {
-		result[i] = doc.toRule()
+		rule, err := doc.toRule()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[i] = rule
 	}
 	return result, nil
 }
+
+// removeApplicationFirewallRulesOps returns the operations required to
+// remove all firewall rules for the specified application.
+func removeApplicationFirewallRulesOps(st *State, application string) ([]txn.Op, error) {
+	rules, err := NewFirewallRules(st).ApplicationRules(application)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(rules))
+	for i, rule := range rules {
+		ops[i] = txn.Op{
+			C:      firewallRulesC,
+			Id:     rule.key(),
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
@@ -11,7 +11,9 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/mgo.v2/bson"
 
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 )
 
 type FirewallRulesSuite struct {
@@ -132,3 +134,121 @@ func (s *FirewallRulesSuite) TestUpdate(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	s.assertSavedRules(c, state.JujuApplicationOfferRule, []string{"192.168.2.0/16"})
 }
+
+func (s *FirewallRulesSuite) TestSaveApplicationRuleInvalidApplication(c *gc.C) {
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		WellKnownService: state.ApplicationRule,
+		Application:      "-foo",
+		WhitelistCIDRs:   []string{"192.168.1.0/16"},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `application name "-foo" not valid`)
+}
+
+func (s *FirewallRulesSuite) TestSaveApplicationRuleMissingApplication(c *gc.C) {
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		WellKnownService: state.ApplicationRule,
+		Application:      "mysql",
+		WhitelistCIDRs:   []string{"192.168.1.0/16"},
+	})
+	c.Assert(err, gc.ErrorMatches, `failed to create firewall rules: application "mysql" not found`)
+}
+
+func (s *FirewallRulesSuite) TestSaveKnownServiceWithApplication(c *gc.C) {
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		Application:      "mysql",
+		WhitelistCIDRs:   []string{"192.168.1.0/16"},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *FirewallRulesSuite) TestApplicationRules(c *gc.C) {
+	s.Factory.MakeApplication(c, nil)
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		WellKnownService: state.ApplicationRule,
+		Application:      "mysql",
+		WhitelistCIDRs:   []string{"192.168.1.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = rules.Save(state.FirewallRule{
+		WellKnownService: state.ApplicationRule,
+		Application:      "mysql",
+		PortRange:        network.MustParsePortRange("3306/tcp"),
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = rules.Save(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"192.168.2.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := rules.ApplicationRules("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 2)
+	var withPorts, withoutPorts *state.FirewallRule
+	for _, rule := range result {
+		c.Assert(rule.WellKnownService, gc.Equals, state.ApplicationRule)
+		c.Assert(rule.Application, gc.Equals, "mysql")
+		if rule.PortRange == (network.PortRange{}) {
+			withoutPorts = rule
+		} else {
+			withPorts = rule
+		}
+	}
+	c.Assert(withoutPorts, gc.NotNil)
+	c.Assert(withoutPorts.WhitelistCIDRs, jc.DeepEquals, []string{"192.168.1.0/16"})
+	c.Assert(withPorts, gc.NotNil)
+	c.Assert(withPorts.PortRange, jc.DeepEquals, network.MustParsePortRange("3306/tcp"))
+	c.Assert(withPorts.WhitelistCIDRs, jc.DeepEquals, []string{"10.0.0.0/8"})
+
+	all, err := rules.AllRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 3)
+}
+
+func (s *FirewallRulesSuite) TestApplicationRulesRemovedWithApplication(c *gc.C) {
+	app := s.Factory.MakeApplication(c, nil)
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		WellKnownService: state.ApplicationRule,
+		Application:      app.Name(),
+		WhitelistCIDRs:   []string{"192.168.1.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := rules.ApplicationRules(app.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 0)
+}
+
+func (s *FirewallRulesSuite) TestWatchFirewallRules(c *gc.C) {
+	w := s.State.WatchFirewallRules()
+	defer statetesting.AssertStop(c, w)
+
+	// Initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"192.168.1.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = rules.Save(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"192.168.2.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -0,0 +1,206 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// MaxHookExecutions is the number of hook executions recorded for each
+// unit. When a unit records more, the oldest are discarded.
+const MaxHookExecutions = 50
+
+const (
+	// HookExecutionHook is the kind of execution of a charm hook.
+	HookExecutionHook = "hook"
+
+	// HookExecutionAction is the kind of execution of an action.
+	HookExecutionAction = "action"
+)
+
+// HookExecution records the execution of a hook or action by a unit
+// agent.
+type HookExecution struct {
+	// Seq identifies the execution among those recorded for the unit;
+	// it is assigned when the execution is recorded.
+	Seq int
+
+	// Kind is either HookExecutionHook or HookExecutionAction.
+	Kind string
+
+	// Name is the name of the hook or action.
+	Name string
+
+	// Relation identifies the relation of a relation hook.
+	Relation string
+
+	// RemoteUnit is the remote unit of a relation hook, if any.
+	RemoteUnit string
+
+	// Started and Finished hold when the execution started and
+	// finished.
+	Started  time.Time
+	Finished time.Time
+
+	// ExitCode is the exit code of the hook or action process.
+	ExitCode int
+
+	// Error holds the error with which the execution failed, if any.
+	Error string
+
+	// Stdout and Stderr hold the tail of the output of the
+	// execution.
+	Stdout string
+	Stderr string
+}
+
+// hookExecutionDoc is the persistent representation of a HookExecution.
+type hookExecutionDoc struct {
+	DocID      string `bson:"_id"`
+	ModelUUID  string `bson:"model-uuid"`
+	Unit       string `bson:"unit"`
+	Seq        int    `bson:"seq"`
+	Kind       string `bson:"kind"`
+	Name       string `bson:"name"`
+	Relation   string `bson:"relation,omitempty"`
+	RemoteUnit string `bson:"remote-unit,omitempty"`
+	Started    int64  `bson:"started"`
+	Finished   int64  `bson:"finished"`
+	ExitCode   int    `bson:"exit-code"`
+	Error      string `bson:"error,omitempty"`
+	Stdout     string `bson:"stdout,omitempty"`
+	Stderr     string `bson:"stderr,omitempty"`
+}
+
+func (doc *hookExecutionDoc) execution() HookExecution {
+	return HookExecution{
+		Seq:        doc.Seq,
+		Kind:       doc.Kind,
+		Name:       doc.Name,
+		Relation:   doc.Relation,
+		RemoteUnit: doc.RemoteUnit,
+		Started:    unixNanoToTime0(doc.Started),
+		Finished:   unixNanoToTime0(doc.Finished),
+		ExitCode:   doc.ExitCode,
+		Error:      doc.Error,
+		Stdout:     doc.Stdout,
+		Stderr:     doc.Stderr,
+	}
+}
+
+// hookExecutionSequence returns the name of the sequence used to number
+// the hook executions of the named unit.
+func hookExecutionSequence(unitName string) string {
+	return "hookexecution-" + unitName
+}
+
+// RecordHookExecution records the execution of a hook or action by the
+// unit, and returns the sequence number assigned to it. Only the most
+// recent MaxHookExecutions executions of each unit are kept.
+func (u *Unit) RecordHookExecution(execution HookExecution) (_ int, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot record hook execution for unit %q", u)
+	switch execution.Kind {
+	case HookExecutionHook, HookExecutionAction:
+	default:
+		return 0, errors.NotValidf("execution kind %q", execution.Kind)
+	}
+	if execution.Name == "" {
+		return 0, errors.NotValidf("empty execution name")
+	}
+	seq, err := sequence(u.st, hookExecutionSequence(u.Name()))
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	// Number the executions from 1, as they're shown to users.
+	seq++
+	doc := &hookExecutionDoc{
+		DocID:      u.st.docID(fmt.Sprintf("%s#%d", u.globalKey(), seq)),
+		Unit:       u.Name(),
+		Seq:        seq,
+		Kind:       execution.Kind,
+		Name:       execution.Name,
+		Relation:   execution.Relation,
+		RemoteUnit: execution.RemoteUnit,
+		Started:    execution.Started.UnixNano(),
+		Finished:   execution.Finished.UnixNano(),
+		ExitCode:   execution.ExitCode,
+		Error:      execution.Error,
+		Stdout:     execution.Stdout,
+		Stderr:     execution.Stderr,
+	}
+	executions, closer := u.st.db().GetCollection(unitHookExecutionsC)
+	defer closer()
+	executionsW := executions.Writeable()
+	if err := executionsW.Insert(doc); err != nil {
+		return 0, errors.Trace(err)
+	}
+	if seq > MaxHookExecutions {
+		_, err := executionsW.RemoveAll(bson.D{
+			{"unit", u.Name()},
+			{"seq", bson.D{{"$lte", seq - MaxHookExecutions}}},
+		})
+		if err != nil {
+			return 0, errors.Annotate(err, "cannot discard old hook executions")
+		}
+	}
+	return seq, nil
+}
+
+// HookExecutions returns the most recent hook executions recorded for
+// the unit, newest first. If limit is positive, at most that many are
+// returned. The output of the executions is omitted; use HookExecution
+// to fetch it.
+func (u *Unit) HookExecutions(limit int) ([]HookExecution, error) {
+	executions, closer := u.st.db().GetCollection(unitHookExecutionsC)
+	defer closer()
+
+	query := executions.Find(bson.D{{"unit", u.Name()}}).Sort("-seq")
+	query = query.Select(bson.D{{"stdout", 0}, {"stderr", 0}})
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var docs []hookExecutionDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get hook executions for unit %q", u)
+	}
+	results := make([]HookExecution, len(docs))
+	for i, doc := range docs {
+		results[i] = doc.execution()
+	}
+	return results, nil
+}
+
+// HookExecution returns the hook execution of the unit with the given
+// sequence number, including its output.
+func (u *Unit) HookExecution(seq int) (HookExecution, error) {
+	executions, closer := u.st.db().GetCollection(unitHookExecutionsC)
+	defer closer()
+
+	var doc hookExecutionDoc
+	err := executions.Find(bson.D{{"unit", u.Name()}, {"seq", seq}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return HookExecution{}, errors.NotFoundf("hook execution %d for unit %q", seq, u)
+	} else if err != nil {
+		return HookExecution{}, errors.Annotatef(err, "cannot get hook execution %d for unit %q", seq, u)
+	}
+	return doc.execution(), nil
+}
+
+// eraseHookExecutions removes all the hook executions recorded for the
+// unit.
+func (u *Unit) eraseHookExecutions() error {
+	executions, closer := u.st.db().GetCollection(unitHookExecutionsC)
+	defer closer()
+	executionsW := executions.Writeable()
+
+	if _, err := executionsW.RemoveAll(bson.D{{"unit", u.Name()}}); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type HookExecutionSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&HookExecutionSuite{})
+
+func (s *HookExecutionSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.unit = factory.NewFactory(s.State).MakeUnit(c, nil)
+}
+
+func (s *HookExecutionSuite) record(c *gc.C, name string) int {
+	started := time.Date(2017, 10, 1, 12, 0, 0, 0, time.UTC)
+	seq, err := s.unit.RecordHookExecution(state.HookExecution{
+		Kind:     state.HookExecutionHook,
+		Name:     name,
+		Started:  started,
+		Finished: started.Add(time.Second),
+		Stdout:   name + " out",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return seq
+}
+
+func (s *HookExecutionSuite) TestRecordHookExecution(c *gc.C) {
+	started := time.Date(2017, 10, 1, 12, 0, 0, 0, time.UTC)
+	seq, err := s.unit.RecordHookExecution(state.HookExecution{
+		Kind:       state.HookExecutionHook,
+		Name:       "db-relation-changed",
+		Relation:   "wordpress:db mysql:server",
+		RemoteUnit: "mysql/0",
+		Started:    started,
+		Finished:   started.Add(3 * time.Second),
+		ExitCode:   1,
+		Error:      "exit status 1",
+		Stdout:     "some output",
+		Stderr:     "oops",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(seq, gc.Equals, 1)
+
+	execution, err := s.unit.HookExecution(seq)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(execution.Started.Equal(started), jc.IsTrue)
+	c.Check(execution.Finished.Equal(started.Add(3*time.Second)), jc.IsTrue)
+	execution.Started, execution.Finished = time.Time{}, time.Time{}
+	c.Assert(execution, jc.DeepEquals, state.HookExecution{
+		Seq:        1,
+		Kind:       state.HookExecutionHook,
+		Name:       "db-relation-changed",
+		Relation:   "wordpress:db mysql:server",
+		RemoteUnit: "mysql/0",
+		ExitCode:   1,
+		Error:      "exit status 1",
+		Stdout:     "some output",
+		Stderr:     "oops",
+	})
+}
+
+func (s *HookExecutionSuite) TestRecordHookExecutionInvalid(c *gc.C) {
+	_, err := s.unit.RecordHookExecution(state.HookExecution{Kind: "dance", Name: "install"})
+	c.Assert(err, gc.ErrorMatches, `cannot record hook execution for unit "mysql/0": execution kind "dance" not valid`)
+	_, err = s.unit.RecordHookExecution(state.HookExecution{Kind: state.HookExecutionAction})
+	c.Assert(err, gc.ErrorMatches, `cannot record hook execution for unit "mysql/0": empty execution name not valid`)
+}
+
+func (s *HookExecutionSuite) TestHookExecutionsNewestFirstWithoutOutput(c *gc.C) {
+	s.record(c, "install")
+	s.record(c, "config-changed")
+	s.record(c, "start")
+
+	executions, err := s.unit.HookExecutions(2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, 2)
+	c.Check(executions[0].Seq, gc.Equals, 3)
+	c.Check(executions[0].Name, gc.Equals, "start")
+	c.Check(executions[0].Stdout, gc.Equals, "")
+	c.Check(executions[1].Seq, gc.Equals, 2)
+	c.Check(executions[1].Name, gc.Equals, "config-changed")
+
+	executions, err = s.unit.HookExecutions(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, 3)
+}
+
+func (s *HookExecutionSuite) TestHookExecutionsBounded(c *gc.C) {
+	for i := 0; i < state.MaxHookExecutions+5; i++ {
+		s.record(c, "update-status")
+	}
+	executions, err := s.unit.HookExecutions(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, state.MaxHookExecutions)
+	c.Assert(executions[0].Seq, gc.Equals, state.MaxHookExecutions+5)
+	c.Assert(executions[len(executions)-1].Seq, gc.Equals, 6)
+
+	_, err = s.unit.HookExecution(5)
+	c.Assert(err, gc.ErrorMatches, `hook execution 5 for unit "mysql/0" not found`)
+}
+
+func (s *HookExecutionSuite) TestHookExecutionsErasedWithUnit(c *gc.C) {
+	s.record(c, "install")
+	err := s.unit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions, err := s.unit.HookExecutions(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, 0)
+}
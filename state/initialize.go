@@ -63,6 +63,10 @@ type InitializeParams struct {
 	// MongoDialOpts contains the dial options for connecting to
 	// Mongo.
 	MongoDialOpts mongo.DialOpts
+
+	// SecretsKey is the key from which the keys that encrypt the
+	// values of charm secrets are derived.
+	SecretsKey string
 }
 
 // Validate checks that the state initialization parameters are valid.
@@ -135,6 +139,7 @@ func Initialize(args InitializeParams) (_ *Controller, _ *State, err error) {
 		MongoDialOpts:      args.MongoDialOpts,
 		NewPolicy:          args.NewPolicy,
 		InitDatabaseFunc:   InitDatabase,
+		SecretsKey:         args.SecretsKey,
 	})
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "opening controller")
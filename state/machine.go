@@ -108,6 +108,10 @@ type machineDoc struct {
 	PasswordHash  string
 	Clean         bool
 
+	// Decommissioning is true when the controller machine is to be
+	// removed once it no longer has a vote.
+	Decommissioning bool `bson:",omitempty"`
+
 	// Volumes contains the names of volumes attached to the machine.
 	Volumes []string `bson:"volumes,omitempty"`
 	// Filesystems contains the names of filesystems attached to the machine.
@@ -140,6 +144,19 @@ type machineDoc struct {
 	// StopMongoUntilVersion holds the version that must be checked to
 	// know if mongo must be stopped.
 	StopMongoUntilVersion string `bson:",omitempty"`
+
+	// CharmProfiles holds the names of the charm LXD profiles that
+	// have been applied to the machine's container.
+	CharmProfiles []string `bson:"charm-profiles,omitempty"`
+
+	// HostId holds the id of the machine hosting this container, if
+	// the container has been moved away from the machine its id
+	// names as parent.
+	HostId string `bson:"host-id,omitempty"`
+
+	// Move holds the progress of moving this container to another
+	// host machine.
+	Move *containerMoveDoc `bson:"move,omitempty"`
 }
 
 func newMachine(st *State, doc *machineDoc) *Machine {
@@ -568,6 +585,9 @@ func (m *Machine) Containers() ([]string, error) {
 
 // ParentId returns the Id of the host machine if this machine is a container.
 func (m *Machine) ParentId() (string, bool) {
+	if m.doc.HostId != "" {
+		return m.doc.HostId, true
+	}
 	parentId := ParentId(m.Id())
 	return parentId, parentId != ""
 }
@@ -917,7 +937,8 @@ func (m *Machine) removeOps() ([]txn.Op, error) {
 	ops = append(ops, linkLayerDevicesOps...)
 	ops = append(ops, devicesAddressesOps...)
 	ops = append(ops, portsOps...)
-	ops = append(ops, removeContainerRefOps(m.st, m.Id())...)
+	parentId, _ := m.ParentId()
+	ops = append(ops, removeContainerRefOps(m.st, m.Id(), parentId)...)
 	ops = append(ops, filesystemOps...)
 	ops = append(ops, volumeOps...)
 	return ops, nil
@@ -1824,6 +1845,28 @@ func (m *Machine) markInvalidContainers() error {
 	return nil
 }
 
+// CharmProfiles returns the names of the charm LXD profiles that have
+// been applied to the machine's container.
+func (m *Machine) CharmProfiles() []string {
+	return m.doc.CharmProfiles
+}
+
+// SetCharmProfiles records the names of the charm LXD profiles that
+// have been applied to the machine's container.
+func (m *Machine) SetCharmProfiles(profiles []string) error {
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"charm-profiles", profiles}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, ErrDead), "cannot set charm profiles for machine %v", m)
+	}
+	m.doc.CharmProfiles = profiles
+	return nil
+}
+
 // SetMachineBlockDevices sets the block devices visible on the machine.
 func (m *Machine) SetMachineBlockDevices(info ...BlockDeviceInfo) error {
 	return setMachineBlockDevices(m.st, m.Id(), info)
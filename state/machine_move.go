@@ -0,0 +1,410 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/instance"
+)
+
+// ContainerMovePhase describes how far the move of a container to
+// another host machine has progressed.
+type ContainerMovePhase string
+
+const (
+	// ContainerMoveRequested means the move has been requested, and
+	// the container's current host has yet to prepare it.
+	ContainerMoveRequested ContainerMovePhase = "requested"
+
+	// ContainerMoveSourceReady means the current host has prepared
+	// the container, and the target host may now copy it.
+	ContainerMoveSourceReady ContainerMovePhase = "source-ready"
+
+	// ContainerMoveCopied means the target host has copied the
+	// container, and the previous host may now remove its copy.
+	ContainerMoveCopied ContainerMovePhase = "copied"
+
+	// ContainerMoveFailed means the target host could not copy the
+	// container, and the current host should restore it.
+	ContainerMoveFailed ContainerMovePhase = "failed"
+)
+
+// ContainerMoveSource holds what the target host needs in order to
+// copy a container from its current host.
+type ContainerMoveSource struct {
+	// Addresses holds the addresses of the container server on the
+	// current host.
+	Addresses []string
+
+	// Operation identifies the migration operation on the current
+	// host's container server.
+	Operation string
+
+	// Certificate holds the certificate of the current host's
+	// container server.
+	Certificate string
+
+	// Secrets holds the secrets needed to join the migration
+	// operation. They are stored encrypted, and are not returned by
+	// Machine.ContainerMove; use Machine.ContainerMoveSecrets.
+	Secrets map[string]string
+
+	// Architecture, Config, Devices and Profiles describe the
+	// container being moved.
+	Architecture string
+	Config       map[string]string
+	Devices      map[string]map[string]string
+	Profiles     []string
+}
+
+// ContainerMove describes a move of a container to another host machine.
+type ContainerMove struct {
+	// TargetId is the id of the machine the container is moving to.
+	TargetId string
+
+	// Live is true if the container is to keep running while moved.
+	Live bool
+
+	// Phase describes how far the move has progressed.
+	Phase ContainerMovePhase
+
+	// Source is set once the current host has prepared the container.
+	Source *ContainerMoveSource
+
+	// Message describes why the move failed, if it did.
+	Message string
+}
+
+type containerMoveDoc struct {
+	TargetId string                  `bson:"target-id"`
+	Live     bool                    `bson:"live"`
+	Phase    string                  `bson:"phase"`
+	Source   *containerMoveSourceDoc `bson:"source,omitempty"`
+	Message  string                  `bson:"message,omitempty"`
+}
+
+// containerMoveSourceDoc holds a ContainerMoveSource; config and device
+// keys may contain dots, so they are escaped. The secrets are encrypted
+// in the same way as charm secrets, and are removed once the target
+// host has copied the container.
+type containerMoveSourceDoc struct {
+	Addresses    []string                     `bson:"addresses"`
+	Operation    string                       `bson:"operation"`
+	Certificate  string                       `bson:"certificate"`
+	Secrets      []byte                       `bson:"secrets,omitempty"`
+	Architecture string                       `bson:"architecture,omitempty"`
+	Config       map[string]string            `bson:"config,omitempty"`
+	Devices      map[string]map[string]string `bson:"devices,omitempty"`
+	Profiles     []string                     `bson:"profiles,omitempty"`
+}
+
+func (st *State) newContainerMoveSourceDoc(source ContainerMoveSource) (*containerMoveSourceDoc, error) {
+	doc := &containerMoveSourceDoc{
+		Addresses:    source.Addresses,
+		Operation:    source.Operation,
+		Certificate:  source.Certificate,
+		Architecture: source.Architecture,
+		Config:       escapeStringMapKeys(source.Config),
+		Profiles:     source.Profiles,
+	}
+	if len(source.Devices) > 0 {
+		doc.Devices = make(map[string]map[string]string)
+		for name, device := range source.Devices {
+			doc.Devices[escapeReplacer.Replace(name)] = escapeStringMapKeys(device)
+		}
+	}
+	if len(source.Secrets) > 0 {
+		key, err := st.secretsKey()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		doc.Secrets, err = encryptSecretValue(key, source.Secrets)
+		if err != nil {
+			return nil, errors.Annotate(err, "encrypting secrets")
+		}
+	}
+	return doc, nil
+}
+
+func (doc *containerMoveSourceDoc) source() *ContainerMoveSource {
+	if doc == nil {
+		return nil
+	}
+	source := &ContainerMoveSource{
+		Addresses:    doc.Addresses,
+		Operation:    doc.Operation,
+		Certificate:  doc.Certificate,
+		Architecture: doc.Architecture,
+		Config:       unescapeStringMapKeys(doc.Config),
+		Profiles:     doc.Profiles,
+	}
+	if len(doc.Devices) > 0 {
+		source.Devices = make(map[string]map[string]string)
+		for name, device := range doc.Devices {
+			source.Devices[unescapeReplacer.Replace(name)] = unescapeStringMapKeys(device)
+		}
+	}
+	return source
+}
+
+// ContainerMove returns the move of the machine to another host, and
+// whether one is in progress.
+func (m *Machine) ContainerMove() (ContainerMove, bool) {
+	doc := m.doc.Move
+	if doc == nil {
+		return ContainerMove{}, false
+	}
+	return ContainerMove{
+		TargetId: doc.TargetId,
+		Live:     doc.Live,
+		Phase:    ContainerMovePhase(doc.Phase),
+		Source:   doc.Source.source(),
+		Message:  doc.Message,
+	}, true
+}
+
+// ContainerMoveSecrets returns the secrets of the source of the
+// machine's move, which are set by SetMoveSource and removed once the
+// target host has copied the container.
+func (m *Machine) ContainerMoveSecrets() (map[string]string, error) {
+	if m.doc.Move == nil || m.doc.Move.Source == nil || len(m.doc.Move.Source.Secrets) == 0 {
+		return nil, nil
+	}
+	key, err := m.st.secretsKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	secrets, err := decryptSecretValue(key, m.doc.Move.Source.Secrets)
+	if err != nil {
+		return nil, errors.Annotatef(err, "decrypting move secrets for machine %s", m)
+	}
+	return secrets, nil
+}
+
+// StartMove requests that the machine, which must be an LXD container,
+// be moved to the given host machine. If live is true the container
+// keeps running while it is copied; otherwise it is stopped first.
+// The hosts do the actual work, recording their progress with
+// SetMoveSource, SetMoveCopied and CompleteMove.
+func (m *Machine) StartMove(host *Machine, live bool) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if err := host.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if err := m.validateMove(host); err != nil {
+			return nil, errors.Trace(err)
+		}
+		move := &containerMoveDoc{
+			TargetId: host.Id(),
+			Live:     live,
+			Phase:    string(ContainerMoveRequested),
+		}
+		return []txn.Op{{
+			C:  machinesC,
+			Id: m.doc.DocID,
+			Assert: bson.D{
+				{"life", Alive},
+				{"move", bson.D{{"$exists", false}}},
+			},
+			Update: bson.D{{"$set", bson.D{{"move", move}}}},
+		}, {
+			C:      machinesC,
+			Id:     host.doc.DocID,
+			Assert: isAliveDoc,
+		}}, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot move machine %s to machine %s", m, host)
+	}
+	return nil
+}
+
+func (m *Machine) validateMove(host *Machine) error {
+	if m.ContainerType() != instance.LXD {
+		return errors.NotSupportedf("moving machines other than lxd containers")
+	}
+	if m.Life() != Alive {
+		return errors.Errorf("machine is not alive")
+	}
+	if host.Life() != Alive {
+		return errors.Errorf("machine %s is not alive", host)
+	}
+	if move, ok := m.ContainerMove(); ok {
+		return errors.Errorf("already moving to machine %s", move.TargetId)
+	}
+	if parentId, _ := m.ParentId(); parentId == host.Id() {
+		return errors.Errorf("already on machine %s", host)
+	}
+	if host.Id() == m.Id() || strings.HasPrefix(host.Id(), m.Id()+"/") {
+		return errors.Errorf("machine %s is hosted by the container", host)
+	}
+	if supported, known := host.SupportedContainers(); known && !containsContainerType(supported, instance.LXD) {
+		return errors.Errorf("machine %s does not support lxd containers", host)
+	}
+	if _, err := m.InstanceId(); errors.IsNotProvisioned(err) {
+		return errors.Errorf("machine is not provisioned")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+func containsContainerType(types []instance.ContainerType, ctype instance.ContainerType) bool {
+	for _, t := range types {
+		if t == ctype {
+			return true
+		}
+	}
+	return false
+}
+
+// SetMoveSource records that the machine's current host has prepared
+// the container to be copied by the target host.
+func (m *Machine) SetMoveSource(source ContainerMoveSource) error {
+	sourceDoc, err := m.st.newContainerMoveSourceDoc(source)
+	if err != nil {
+		return errors.Annotatef(err, "cannot set move source for machine %s", m)
+	}
+	err = m.advanceMove(ContainerMoveRequested, bson.D{
+		{"move.phase", string(ContainerMoveSourceReady)},
+		{"move.source", sourceDoc},
+	})
+	if err != nil {
+		return errors.Annotatef(err, "cannot set move source for machine %s", m)
+	}
+	return errors.Trace(m.Refresh())
+}
+
+// SetMoveCopied records that the target host has copied the container.
+func (m *Machine) SetMoveCopied() error {
+	err := m.advanceMove(ContainerMoveSourceReady, bson.D{
+		{"move.phase", string(ContainerMoveCopied)},
+		{"move.source", nil},
+	})
+	if err != nil {
+		return errors.Annotatef(err, "cannot record copy of machine %s", m)
+	}
+	return errors.Trace(m.Refresh())
+}
+
+// SetMoveFailed records that the target host could not copy the
+// container, so that the current host can restore it.
+func (m *Machine) SetMoveFailed(message string) error {
+	err := m.advanceMove(ContainerMoveSourceReady, bson.D{
+		{"move.phase", string(ContainerMoveFailed)},
+		{"move.source", nil},
+		{"move.message", message},
+	})
+	if err != nil {
+		return errors.Annotatef(err, "cannot record failed move of machine %s", m)
+	}
+	return errors.Trace(m.Refresh())
+}
+
+func (m *Machine) advanceMove(from ContainerMovePhase, set bson.D) error {
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: bson.D{{"move.phase", string(from)}},
+		Update: bson.D{{"$set", set}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("move is not in phase %q", from)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// AbortMove abandons the move of the machine. The container is left
+// on its current host.
+func (m *Machine) AbortMove() error {
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$unset", bson.D{{"move", nil}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, errors.NotFoundf("machine %s", m)), "cannot abort move of machine %s", m)
+	}
+	m.doc.Move = nil
+	return nil
+}
+
+// CompleteMove records that the container has been moved to the
+// target host. The target becomes the machine's parent, and the
+// machine's addresses and link-layer devices are removed; the machine
+// agent reports them afresh from the new host.
+func (m *Machine) CompleteMove() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		move, ok := m.ContainerMove()
+		if !ok || move.Phase != ContainerMoveCopied {
+			return nil, errors.Errorf("move is not in phase %q", ContainerMoveCopied)
+		}
+		oldParentId, _ := m.ParentId()
+		unset := bson.D{
+			{"move", nil},
+			{"addresses", nil},
+			{"machineaddresses", nil},
+			{"preferredpublicaddress", nil},
+			{"preferredprivateaddress", nil},
+		}
+		update := bson.D{{"$set", bson.D{{"host-id", move.TargetId}}}}
+		if move.TargetId == ParentId(m.Id()) {
+			// Moving back to the original host.
+			unset = append(unset, bson.DocElem{"host-id", nil})
+			update = nil
+		}
+		update = append(update, bson.DocElem{"$unset", unset})
+		ops := []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"move.phase", string(ContainerMoveCopied)}},
+			Update: update,
+		}, {
+			C:      containerRefsC,
+			Id:     m.st.docID(oldParentId),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$pull", bson.D{{"children", m.Id()}}}},
+		}, addChildToContainerRefOp(m.st, move.TargetId, m.Id())}
+
+		linkLayerDevicesOps, err := m.removeAllLinkLayerDevicesOps()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		devicesAddressesOps, err := m.removeAllAddressesOps()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, linkLayerDevicesOps...)
+		ops = append(ops, devicesAddressesOps...)
+		return ops, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot complete move of machine %s", m)
+	}
+	return errors.Trace(m.Refresh())
+}
+
+// WatchContainerMoves returns a StringsWatcher that notifies of changes
+// to the moves of containers to or from the machine.
+func (m *Machine) WatchContainerMoves() StringsWatcher {
+	return newContainerMovesWatcher(m)
+}
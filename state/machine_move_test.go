@@ -0,0 +1,253 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"bytes"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type MachineMoveSuite struct {
+	ConnSuite
+	source    *state.Machine
+	target    *state.Machine
+	container *state.Machine
+}
+
+var _ = gc.Suite(&MachineMoveSuite{})
+
+var moveTemplate = state.MachineTemplate{
+	Series: "quantal",
+	Jobs:   []state.MachineJob{state.JobHostUnits},
+}
+
+func (s *MachineMoveSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.source, err = s.State.AddOneMachine(moveTemplate)
+	c.Assert(err, jc.ErrorIsNil)
+	s.target, err = s.State.AddOneMachine(moveTemplate)
+	c.Assert(err, jc.ErrorIsNil)
+	s.container, err = s.State.AddMachineInsideMachine(moveTemplate, s.source.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.SetProvisioned("juju-0-lxd-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineMoveSuite) TestStartMove(c *gc.C) {
+	err := s.container.StartMove(s.target, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	move, ok := s.container.ContainerMove()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(move, jc.DeepEquals, state.ContainerMove{
+		TargetId: s.target.Id(),
+		Live:     true,
+		Phase:    state.ContainerMoveRequested,
+	})
+
+	err = s.container.StartMove(s.target, false)
+	c.Assert(err, gc.ErrorMatches, `cannot move machine 0/lxd/0 to machine 1: already moving to machine 1`)
+}
+
+func (s *MachineMoveSuite) TestStartMoveInvalid(c *gc.C) {
+	kvm, err := s.State.AddMachineInsideMachine(moveTemplate, s.source.Id(), instance.KVM)
+	c.Assert(err, jc.ErrorIsNil)
+	err = kvm.StartMove(s.target, false)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	unprovisioned, err := s.State.AddMachineInsideMachine(moveTemplate, s.source.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unprovisioned.StartMove(s.target, false)
+	c.Assert(err, gc.ErrorMatches, `cannot move machine 0/lxd/1 to machine 1: machine is not provisioned`)
+
+	err = s.container.StartMove(s.source, false)
+	c.Assert(err, gc.ErrorMatches, `cannot move machine 0/lxd/0 to machine 0: already on machine 0`)
+
+	err = s.target.SetSupportedContainers([]instance.ContainerType{instance.KVM})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.StartMove(s.target, false)
+	c.Assert(err, gc.ErrorMatches, `cannot move machine 0/lxd/0 to machine 1: machine 1 does not support lxd containers`)
+}
+
+func (s *MachineMoveSuite) TestMove(c *gc.C) {
+	err := s.container.SetProviderAddresses(network.NewAddress("10.0.0.2"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.StartMove(s.target, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+
+	source := state.ContainerMoveSource{
+		Addresses:    []string{"10.0.0.1:8443"},
+		Operation:    "/1.0/operations/a-b-c",
+		Certificate:  "cert",
+		Secrets:      map[string]string{"control": "control-secret", "fs": "fs-secret"},
+		Architecture: "x86_64",
+		Config:       map[string]string{"user.user-data": "data"},
+		Devices: map[string]map[string]string{
+			"eth0": {"type": "nic", "ipv4.address": "10.0.0.2"},
+		},
+		Profiles: []string{"default"},
+	}
+	err = s.container.SetMoveSource(source)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	move, ok := s.container.ContainerMove()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(move.Phase, gc.Equals, state.ContainerMoveSourceReady)
+	c.Assert(move.Source.Secrets, gc.IsNil)
+	secrets, err := s.container.ContainerMoveSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, jc.DeepEquals, source.Secrets)
+	move.Source.Secrets = secrets
+	c.Assert(move.Source, jc.DeepEquals, &source)
+	s.assertMoveSecretsNotStored(c, "control-secret", "fs-secret")
+
+	err = s.container.CompleteMove()
+	c.Assert(err, gc.ErrorMatches, `cannot complete move of machine 0/lxd/0: move is not in phase "copied"`)
+
+	err = s.container.SetMoveCopied()
+	c.Assert(err, jc.ErrorIsNil)
+	secrets, err = s.container.ContainerMoveSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.IsNil)
+	err = s.container.CompleteMove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, ok = s.container.ContainerMove()
+	c.Assert(ok, jc.IsFalse)
+	parentId, ok := s.container.ParentId()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(parentId, gc.Equals, s.target.Id())
+	c.Assert(s.container.Addresses(), gc.HasLen, 0)
+
+	containers, err := s.source.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+	containers, err = s.target.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, jc.DeepEquals, []string{s.container.Id()})
+}
+
+// assertMoveSecretsNotStored checks that none of the given secret
+// values appear in the container's machine document.
+func (s *MachineMoveSuite) assertMoveSecretsNotStored(c *gc.C, values ...string) {
+	machines := s.State.MongoSession().DB("juju").C("machines")
+	var doc bson.M
+	err := machines.FindId(state.DocID(s.State, s.container.Id())).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := bson.Marshal(doc)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, value := range values {
+		c.Assert(bytes.Contains(data, []byte(value)), jc.IsFalse)
+	}
+}
+
+func (s *MachineMoveSuite) TestMoveFailed(c *gc.C) {
+	err := s.container.StartMove(s.target, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.container.SetMoveFailed("boom")
+	c.Assert(err, gc.ErrorMatches, `cannot record failed move of machine 0/lxd/0: move is not in phase "source-ready"`)
+	err = s.container.SetMoveSource(state.ContainerMoveSource{Operation: "op"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.SetMoveFailed("boom")
+	c.Assert(err, jc.ErrorIsNil)
+
+	move, ok := s.container.ContainerMove()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(move.Phase, gc.Equals, state.ContainerMoveFailed)
+	c.Assert(move.Message, gc.Equals, "boom")
+
+	err = s.container.AbortMove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.container.ContainerMove()
+	c.Assert(ok, jc.IsFalse)
+	parentId, _ := s.container.ParentId()
+	c.Assert(parentId, gc.Equals, s.source.Id())
+}
+
+func (s *MachineMoveSuite) TestWatchContainerMoves(c *gc.C) {
+	sourceWatcher := s.source.WatchContainerMoves()
+	defer statetesting.AssertStop(c, sourceWatcher)
+	sourceC := statetesting.NewStringsWatcherC(c, s.State, sourceWatcher)
+	sourceC.AssertChange()
+	targetWatcher := s.target.WatchContainerMoves()
+	defer statetesting.AssertStop(c, targetWatcher)
+	targetC := statetesting.NewStringsWatcherC(c, s.State, targetWatcher)
+	targetC.AssertChange()
+
+	err := s.container.StartMove(s.target, false)
+	c.Assert(err, jc.ErrorIsNil)
+	sourceC.AssertChange("0/lxd/0")
+	targetC.AssertChange("0/lxd/0")
+
+	// Unrelated changes are not reported.
+	err = s.container.SetProviderAddresses(network.NewAddress("10.0.0.2"))
+	c.Assert(err, jc.ErrorIsNil)
+	sourceC.AssertNoChange()
+	targetC.AssertNoChange()
+
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.SetMoveSource(state.ContainerMoveSource{Operation: "op"})
+	c.Assert(err, jc.ErrorIsNil)
+	sourceC.AssertChange("0/lxd/0")
+	targetC.AssertChange("0/lxd/0")
+}
+
+func (s *MachineMoveSuite) TestWatchContainersFollowsMove(c *gc.C) {
+	sourceWatcher := s.source.WatchContainers(instance.LXD)
+	defer statetesting.AssertStop(c, sourceWatcher)
+	sourceC := statetesting.NewStringsWatcherC(c, s.State, sourceWatcher)
+	sourceC.AssertChange("0/lxd/0")
+	targetWatcher := s.target.WatchContainers(instance.LXD)
+	defer statetesting.AssertStop(c, targetWatcher)
+	targetC := statetesting.NewStringsWatcherC(c, s.State, targetWatcher)
+	targetC.AssertChange()
+	targetAllWatcher := s.target.WatchAllContainers()
+	defer statetesting.AssertStop(c, targetAllWatcher)
+	targetAllC := statetesting.NewStringsWatcherC(c, s.State, targetAllWatcher)
+	targetAllC.AssertChange()
+
+	// The target's container setup hears of the incoming container.
+	err := s.container.StartMove(s.target, false)
+	c.Assert(err, jc.ErrorIsNil)
+	targetAllC.AssertChange("0/lxd/0")
+	targetC.AssertNoChange()
+
+	err = s.container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.SetMoveSource(state.ContainerMoveSource{Operation: "op"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.SetMoveCopied()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.container.CompleteMove()
+	c.Assert(err, jc.ErrorIsNil)
+	targetC.AssertChange("0/lxd/0")
+	sourceC.AssertNoChange()
+
+	// Once moved, the container's lifecycle is reported to the target only.
+	err = s.container.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	targetC.AssertChange("0/lxd/0")
+	sourceC.AssertNoChange()
+}
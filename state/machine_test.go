@@ -2266,6 +2266,25 @@ func (s *MachineSuite) TestSupportsNoContainersOverwritesExisting(c *gc.C) {
 	assertSupportedContainers(c, machine, []instance.ContainerType{})
 }
 
+func (s *MachineSuite) TestSetCharmProfiles(c *gc.C) {
+	c.Assert(s.machine.CharmProfiles(), gc.HasLen, 0)
+	profiles := []string{"juju-testenv-mysql-1", "juju-testenv-logging-3"}
+	err := s.machine.SetCharmProfiles(profiles)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.CharmProfiles(), jc.DeepEquals, profiles)
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.CharmProfiles(), jc.DeepEquals, profiles)
+}
+
+func (s *MachineSuite) TestSetCharmProfilesDead(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetCharmProfiles([]string{"juju-testenv-mysql-1"})
+	c.Assert(err, gc.ErrorMatches, `cannot set charm profiles for machine 1: not found or dead`)
+}
+
 func (s *MachineSuite) TestSetSupportedContainersSingle(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
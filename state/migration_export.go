@@ -319,6 +319,9 @@ func (e *exporter) machines() error {
 	for _, machine := range machines {
 		e.logger.Debugf("export machine %s", machine.Id())
 
+		if machine.doc.HostId != "" || machine.doc.Move != nil {
+			return errors.NotSupportedf("exporting moved container %s", machine.Id())
+		}
 		var exParent description.Machine
 		if parentId := ParentId(machine.Id()); parentId != "" {
 			var found bool
@@ -176,6 +176,10 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// phase after the initial model migration.
 		charmsC,
 
+		// Hook executions are a diagnostic record of the unit agents'
+		// recent activity, like logs, and are not migrated.
+		unitHookExecutionsC,
+
 		// Metrics manager maintains controller specific state relating to
 		// the store and forward of charm metrics. Nothing to migrate here.
 		metricsManagerC,
@@ -192,6 +196,11 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		externalControllersC,
 		relationNetworksC,
 		firewallRulesC,
+		// Charm secrets are not migrated; the migration prechecks
+		// refuse models that have them.
+		secretsC,
+		// CAAS - TODO
+		podSpecsC,
 	)
 
 	envCollections := set.NewStrings()
@@ -288,13 +297,21 @@ func (s *MigrationSuite) TestMachineDocFields(c *gc.C) {
 		"ModelUUID",
 		// Life is always alive, confirmed by export precheck.
 		"Life",
-		// NoVote and HasVote only matter for machines with manage state job
-		// and we don't support migrating the controller model.
+		// NoVote, HasVote and Decommissioning only matter for machines
+		// with manage state job and we don't support migrating the
+		// controller model.
 		"NoVote",
 		"HasVote",
+		"Decommissioning",
 		// Ignored at this stage, could be an issue if mongo 3.0 isn't
 		// available.
 		"StopMongoUntilVersion",
+		// CharmProfiles is recorded again by the container's host
+		// once it has applied the charm profiles in the new model.
+		"CharmProfiles",
+		// Models with moved containers cannot be exported.
+		"HostId",
+		"Move",
 	)
 	migrated := set.NewStrings(
 		"Addresses",
@@ -360,6 +377,24 @@ func (s *MigrationSuite) TestApplicationDocFields(c *gc.C) {
 		// RelationCount is handled by the number of times the application name
 		// appears in relation endpoints.
 		"RelationCount",
+		// ExposedEndpoints and EgressRules are not yet supported by
+		// the model description; the migration prechecks refuse
+		// applications that use them.
+		"ExposedEndpoints",
+		"EgressRules",
+		// UpdateStatusInterval is not yet supported by the model
+		// description; the migration prechecks refuse applications
+		// that set it.
+		"UpdateStatusInterval",
+		// HookTimeout and HookTimeoutNoRetry are not yet supported
+		// by the model description; the migration prechecks refuse
+		// applications that set them.
+		"HookTimeout",
+		"HookTimeoutNoRetry",
+		// PasswordHash, for the application's operator agent, is
+		// not yet supported by the model description; the migration
+		// prechecks refuse applications that have one.
+		"PasswordHash",
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -389,6 +424,8 @@ func (s *MigrationSuite) TestUnitDocFields(c *gc.C) {
 		"Series",
 		"CharmURL",
 		"TxnRevno",
+		// CAAS - TODO
+		"ContainerAddress",
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -393,6 +393,7 @@ func (st *State) NewModel(args ModelArgs) (_ *Model, _ *State, err error) {
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "could not create state for new model")
 	}
+	newSt.controllerSecretsKey = st.controllerSecretsKey
 	defer func() {
 		if err != nil {
 			newSt.Close()
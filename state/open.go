@@ -52,6 +52,12 @@ type OpenParams struct {
 	// InitDatabaseFunc, if non-nil, is a function that will be called
 	// just after the state database is opened.
 	InitDatabaseFunc InitDatabaseFunc
+
+	// SecretsKey is the key from which the keys that encrypt the
+	// values of charm secrets are derived. It is kept in the
+	// controller agents' configuration rather than in the database.
+	// If it is empty, charm secrets cannot be used.
+	SecretsKey string
 }
 
 // Validate validates the OpenParams.
@@ -111,6 +117,7 @@ func OpenController(args OpenParams) (*Controller, error) {
 		session:                session,
 		newPolicy:              args.NewPolicy,
 		runTransactionObserver: args.RunTransactionObserver,
+		secretsKey:             args.SecretsKey,
 	}, nil
 }
 
@@ -136,6 +143,7 @@ func Open(args OpenParams) (*State, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	st.controllerSecretsKey = args.SecretsKey
 	if _, err := st.Model(); err != nil {
 		if err := st.Close(); err != nil {
 			logger.Errorf("closing State for %s: %v", args.ControllerModelTag, err)
@@ -0,0 +1,94 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// podSpecDoc records the pod spec set by the charm of an application
+// deployed to a CAAS model.
+type podSpecDoc struct {
+	DocID string `bson:"_id"`
+	Spec  string `bson:"spec"`
+}
+
+// SetPodSpec sets the pod spec for the application. The spec is stored
+// verbatim; it is up to the caller to check it is valid for the
+// model's substrate.
+func (a *Application) SetPodSpec(spec string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set pod spec for application %q", a.doc.Name)
+	key := a.globalKey()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if a.Life() != Alive {
+			return nil, errors.Errorf("application is not alive")
+		}
+		ops := []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		existing, err := a.PodSpec()
+		if errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      podSpecsC,
+				Id:     key,
+				Assert: txn.DocMissing,
+				Insert: podSpecDoc{Spec: spec},
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if existing == spec {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return append(ops, txn.Op{
+			C:      podSpecsC,
+			Id:     key,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"spec", spec}}}},
+		}), nil
+	}
+	return a.st.db().Run(buildTxn)
+}
+
+// PodSpec returns the pod spec set for the application. It returns an
+// error satisfying errors.IsNotFound if none has been set.
+func (a *Application) PodSpec() (string, error) {
+	podSpecs, closer := a.st.db().GetCollection(podSpecsC)
+	defer closer()
+
+	var doc podSpecDoc
+	if err := podSpecs.FindId(a.globalKey()).One(&doc); err == mgo.ErrNotFound {
+		return "", errors.NotFoundf("pod spec for application %q", a.doc.Name)
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	return doc.Spec, nil
+}
+
+// WatchPodSpec returns a watcher observing changes to the pod spec
+// of the application.
+func (a *Application) WatchPodSpec() NotifyWatcher {
+	return newEntityWatcher(a.st, podSpecsC, a.st.docID(a.globalKey()))
+}
+
+// removePodSpecOp returns an op removing the pod spec for the given
+// application key, without asserting it exists in the first place.
+func removePodSpecOp(key string) txn.Op {
+	return txn.Op{
+		C:      podSpecsC,
+		Id:     key,
+		Remove: true,
+	}
+}
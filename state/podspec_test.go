@@ -0,0 +1,82 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type PodSpecSuite struct {
+	ConnSuite
+	application *state.Application
+}
+
+var _ = gc.Suite(&PodSpecSuite{})
+
+func (s *PodSpecSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.application = s.Factory.MakeApplication(c, nil)
+}
+
+func (s *PodSpecSuite) TestPodSpecNotSet(c *gc.C) {
+	_, err := s.application.PodSpec()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *PodSpecSuite) TestSetPodSpec(c *gc.C) {
+	err := s.application.SetPodSpec("containers: []")
+	c.Assert(err, jc.ErrorIsNil)
+	spec, err := s.application.PodSpec()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, gc.Equals, "containers: []")
+
+	err = s.application.SetPodSpec("containers: [{name: foo}]")
+	c.Assert(err, jc.ErrorIsNil)
+	spec, err = s.application.PodSpec()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, gc.Equals, "containers: [{name: foo}]")
+}
+
+func (s *PodSpecSuite) TestSetPodSpecApplicationNotAlive(c *gc.C) {
+	_, err := s.application.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.application.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.application.SetPodSpec("containers: []")
+	c.Assert(err, gc.ErrorMatches, `cannot set pod spec for application ".*": application is not alive`)
+}
+
+func (s *PodSpecSuite) TestPodSpecRemovedWithApplication(c *gc.C) {
+	err := s.application.SetPodSpec("containers: []")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.application.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.application.PodSpec()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *PodSpecSuite) TestWatchPodSpec(c *gc.C) {
+	w := s.application.WatchPodSpec()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.application.SetPodSpec("containers: []")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Setting the same spec is a no-op.
+	err = s.application.SetPodSpec("containers: []")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = s.application.SetPodSpec("containers: [{name: foo}]")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -121,8 +121,10 @@ func (m *Machine) GetRebootFlag() (bool, error) {
 }
 
 func (m *Machine) machinesToCareAboutRebootsFor() []string {
-	var possibleIds []string
-	for currentId := m.Id(); currentId != ""; {
+	possibleIds := []string{m.Id()}
+	// A moved container's host is not the parent named by its id.
+	currentId, _ := m.ParentId()
+	for currentId != "" {
 		possibleIds = append(possibleIds, currentId)
 		currentId = ParentId(currentId)
 	}
@@ -0,0 +1,224 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/juju/replicaset"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+)
+
+// jujuMachineKey is the key for the replicaset member tag holding the
+// member's juju machine id, as set by the worker that maintains the
+// replicaset.
+const jujuMachineKey = "juju-machine-id"
+
+// RemoveControllerMachine arranges for the controller machine with the
+// given id to be removed. The machine loses its vote; once the worker
+// that maintains the replicaset has removed the machine's vote and
+// published the API server addresses without it, that worker calls
+// Decommission to remove the machine. The only voting controller cannot
+// be removed.
+//
+// If the machine wants a vote, it is replaced in the same transaction,
+// by promoting an existing controller or adding a new machine with the
+// given constraints and series, so that the number of voting controllers
+// is maintained. New machines are spread across the given zones, as for
+// EnableHAInZones.
+func (st *State) RemoveControllerMachine(
+	id string, cons constraints.Value, series string, zones []string,
+) (_ ControllersChanges, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove controller machine %v", id)
+
+	var change ControllersChanges
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		m, err := st.Machine(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !m.IsManager() {
+			return nil, errors.New("machine is not a controller")
+		}
+		if m.Life() != Alive {
+			return nil, errors.New("machine is not alive")
+		}
+		if m.IsDecommissioning() {
+			return nil, jujutxn.ErrNoOperations
+		}
+		info, err := st.ControllerInfo()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if m.WantsVote() && len(info.VotingMachineIds) <= 1 {
+			return nil, errors.New("machine is the only voting controller")
+		}
+		var ops []txn.Op
+		ops, change, err = st.enableHAOps(len(info.VotingMachineIds), cons, series, nil, zones, m)
+		return ops, err
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return ControllersChanges{}, errors.Trace(err)
+	}
+	return change, nil
+}
+
+// IsDecommissioning reports whether the controller machine is to be
+// removed once it no longer has a vote.
+func (m *Machine) IsDecommissioning() bool {
+	return m.doc.Decommissioning
+}
+
+// Decommission removes the controller job from the decommissioning
+// machine, which must no longer have a vote, and then destroys the
+// machine. It should only be called from the worker that maintains
+// the replicaset, once the machine has been removed from the API
+// server addresses.
+func (m *Machine) Decommission() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot decommission controller machine %v", m)
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if !m.IsManager() {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if !m.IsDecommissioning() {
+			return nil, errors.New("machine is not being decommissioned")
+		}
+		if m.HasVote() {
+			return nil, errors.New("machine still has a vote")
+		}
+		ops := removeControllerOps(m)
+		ops[0].Assert = append(ops[0].Assert.(bson.D), bson.DocElem{"decommissioning", true})
+		ops[0].Update = append(ops[0].Update.(bson.D),
+			bson.DocElem{"$unset", bson.D{{"decommissioning", nil}}},
+		)
+		return ops, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	if err := m.Refresh(); err != nil {
+		return errors.Trace(err)
+	}
+	return m.Destroy()
+}
+
+// decommissionControllerOps returns the operations to remove the vote
+// of the controller machine and to mark it for removal.
+func decommissionControllerOps(m *Machine) []txn.Op {
+	return []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: append(bson.D{{"jobs", JobManageModel}}, isAliveDoc...),
+		Update: bson.D{{"$set", bson.D{
+			{"novote", true},
+			{"decommissioning", true},
+		}}},
+	}, {
+		C:      controllersC,
+		Id:     modelGlobalKey,
+		Update: bson.D{{"$pull", bson.D{{"votingmachineids", m.doc.Id}}}},
+	}}
+}
+
+// decommissionMachine moves the controller machine from wherever it is
+// in the intent to the machines to be decommissioned.
+func (intent *enableHAIntent) decommissionMachine(m *Machine) {
+	remove := []*Machine{m}
+	intent.promote = withoutMachines(intent.promote, remove)
+	intent.maintain = withoutMachines(intent.maintain, remove)
+	intent.demote = withoutMachines(intent.demote, remove)
+	intent.remove = withoutMachines(intent.remove, remove)
+	intent.decommission = append(intent.decommission, m)
+}
+
+// withoutMachines returns the machines in ms which are not in remove.
+func withoutMachines(ms, remove []*Machine) []*Machine {
+	var result []*Machine
+outer:
+	for _, m := range ms {
+		for _, r := range remove {
+			if m.Id() == r.Id() {
+				continue outer
+			}
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+// replicaSetPrimaryMachineId returns the id of the controller machine
+// hosting the replicaset primary, or the empty string if it cannot be
+// determined.
+func (st *State) replicaSetPrimaryMachineId() string {
+	status, err := replicaset.CurrentStatus(st.session)
+	if err != nil {
+		logger.Debugf("cannot get replicaset status: %v", err)
+		return ""
+	}
+	members, err := replicaset.CurrentMembers(st.session)
+	if err != nil {
+		logger.Debugf("cannot get replicaset members: %v", err)
+		return ""
+	}
+	for _, memberStatus := range status.Members {
+		if memberStatus.State != replicaset.PrimaryState {
+			continue
+		}
+		for _, member := range members {
+			if member.Id == memberStatus.Id {
+				return member.Tags[jujuMachineKey]
+			}
+		}
+	}
+	return ""
+}
+
+// decommissionOrder sorts voting controller machines by how little
+// decommissioning them disrupts the replicaset: machines which do not
+// yet have a vote come first and the machine hosting the primary comes
+// last. Otherwise the most recently added machines come first.
+type decommissionOrder struct {
+	machines  []*Machine
+	primaryId string
+}
+
+func (o decommissionOrder) rank(m *Machine) int {
+	switch {
+	case !m.HasVote():
+		return 0
+	case m.Id() != o.primaryId:
+		return 1
+	}
+	return 2
+}
+
+func (o decommissionOrder) Len() int { return len(o.machines) }
+
+func (o decommissionOrder) Swap(i, j int) {
+	o.machines[i], o.machines[j] = o.machines[j], o.machines[i]
+}
+
+func (o decommissionOrder) Less(i, j int) bool {
+	mi, mj := o.machines[i], o.machines[j]
+	if ri, rj := o.rank(mi), o.rank(mj); ri != rj {
+		return ri < rj
+	}
+	idi, erri := strconv.Atoi(mi.Id())
+	idj, errj := strconv.Atoi(mj.Id())
+	if erri != nil || errj != nil {
+		return mi.Id() > mj.Id()
+	}
+	return idi > idj
+}
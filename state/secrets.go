@@ -0,0 +1,539 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// Secret represents a secret defined by a charm. A secret is owned by
+// an application, whose units may read and rotate it, and may be read
+// by the units of other applications to which it is granted.
+type Secret struct {
+	st  *State
+	doc secretDoc
+}
+
+// SecretGrant describes the access to a secret granted to the units
+// of an application related to the secret's owner.
+type SecretGrant struct {
+	// Relation is the key of the relation over which the secret is
+	// granted.
+	Relation string
+
+	// Unit, if set, restricts the grant to the named unit.
+	Unit string
+}
+
+// secretDoc is the persistent representation of a secret. The value
+// of the secret is encrypted with the model's secrets key.
+type secretDoc struct {
+	DocID      string           `bson:"_id"`
+	Id         string           `bson:"id"`
+	Owner      string           `bson:"owner"`
+	Label      string           `bson:"label,omitempty"`
+	Revision   int              `bson:"revision"`
+	Value      []byte           `bson:"value"`
+	Grants     []secretGrantDoc `bson:"grants,omitempty"`
+	CreateTime int64            `bson:"create-time"`
+	UpdateTime int64            `bson:"update-time"`
+}
+
+type secretGrantDoc struct {
+	Relation string `bson:"relation"`
+	Unit     string `bson:"unit,omitempty"`
+}
+
+// secretIdPrefix prefixes the sequence number of a secret to make its
+// id.
+const secretIdPrefix = "secret-"
+
+// IsValidSecretId returns whether id is a valid secret id.
+func IsValidSecretId(id string) bool {
+	var n int
+	if _, err := fmt.Sscanf(id, secretIdPrefix+"%d", &n); err != nil {
+		return false
+	}
+	return fmt.Sprintf("%s%d", secretIdPrefix, n) == id
+}
+
+// Id returns the id of the secret, by which charms refer to it.
+func (s *Secret) Id() string {
+	return s.doc.Id
+}
+
+// Owner returns the name of the application that owns the secret.
+func (s *Secret) Owner() string {
+	return s.doc.Owner
+}
+
+// Label returns the label given to the secret by its owner, if any.
+func (s *Secret) Label() string {
+	return s.doc.Label
+}
+
+// Revision returns the revision of the secret's value, which is
+// incremented each time the secret is rotated.
+func (s *Secret) Revision() int {
+	return s.doc.Revision
+}
+
+// CreateTime returns when the secret was added.
+func (s *Secret) CreateTime() time.Time {
+	return time.Unix(0, s.doc.CreateTime).UTC()
+}
+
+// UpdateTime returns when the secret was last rotated or granted.
+func (s *Secret) UpdateTime() time.Time {
+	return time.Unix(0, s.doc.UpdateTime).UTC()
+}
+
+// Grants returns the grants of access to the secret.
+func (s *Secret) Grants() []SecretGrant {
+	grants := make([]SecretGrant, len(s.doc.Grants))
+	for i, g := range s.doc.Grants {
+		grants[i] = SecretGrant{Relation: g.Relation, Unit: g.Unit}
+	}
+	return grants
+}
+
+// String returns the secret's id.
+func (s *Secret) String() string {
+	return s.doc.Id
+}
+
+// Value returns the decrypted value of the secret.
+func (s *Secret) Value() (map[string]string, error) {
+	key, err := s.st.secretsKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	value, err := decryptSecretValue(key, s.doc.Value)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read secret %q", s.doc.Id)
+	}
+	return value, nil
+}
+
+// Refresh refreshes the contents of the secret from the underlying
+// state.
+func (s *Secret) Refresh() error {
+	doc, err := s.st.secretDoc(s.doc.Id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc = *doc
+	return nil
+}
+
+// Rotate replaces the value of the secret, and increments its
+// revision.
+func (s *Secret) Rotate(value map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot rotate secret %q", s.doc.Id)
+	if err := validateSecretValue(value); err != nil {
+		return errors.Trace(err)
+	}
+	key, err := s.st.secretsKey()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	encrypted, err := encryptSecretValue(key, value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	now := s.st.clock().Now().UnixNano()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		return []txn.Op{{
+			C:      secretsC,
+			Id:     s.doc.DocID,
+			Assert: bson.D{{"revision", s.doc.Revision}},
+			Update: bson.D{{"$set", bson.D{
+				{"revision", s.doc.Revision + 1},
+				{"value", encrypted},
+				{"update-time", now},
+			}}},
+		}}, nil
+	}
+	if err := s.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.Revision++
+	s.doc.Value = encrypted
+	s.doc.UpdateTime = now
+	return nil
+}
+
+// Grant grants access to the secret to the units of the application
+// at the other end of the given relation from the secret's owner, or
+// to just one of those units if grant.Unit is set.
+func (s *Secret) Grant(grant SecretGrant) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot grant secret %q", s.doc.Id)
+	rel, err := s.st.KeyRelation(grant.Relation)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	related, err := rel.RelatedEndpoints(s.doc.Owner)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if grant.Unit != "" {
+		if !names.IsValidUnit(grant.Unit) {
+			return errors.NotValidf("unit name %q", grant.Unit)
+		}
+		appName, err := names.UnitApplication(grant.Unit)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(related) == 0 || related[0].ApplicationName != appName {
+			return errors.Errorf("unit %q is not related to %q over relation %q", grant.Unit, s.doc.Owner, rel)
+		}
+	}
+	doc := secretGrantDoc{Relation: grant.Relation, Unit: grant.Unit}
+	now := s.st.clock().Now().UnixNano()
+	ops := []txn.Op{{
+		C:      relationsC,
+		Id:     rel.doc.DocID,
+		Assert: isAliveDoc,
+	}, {
+		C:      secretsC,
+		Id:     s.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{
+			{"$addToSet", bson.D{{"grants", doc}}},
+			{"$set", bson.D{{"update-time", now}}},
+		},
+	}}
+	if err := s.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		if err := rel.Refresh(); errors.IsNotFound(err) {
+			return err
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if rel.Life() != Alive {
+			return errors.Errorf("relation %q is not alive", rel)
+		}
+		return errors.NotFoundf("secret %q", s.doc.Id)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if err := s.Refresh(); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// CanRead returns whether the named unit may read the secret: that is,
+// whether the unit belongs to the secret's owner, or has been granted
+// access to the secret over an existing relation.
+func (s *Secret) CanRead(unitName string) (bool, error) {
+	appName, err := names.UnitApplication(unitName)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if appName == s.doc.Owner {
+		return true, nil
+	}
+	return s.isGrantedTo(unitName, appName)
+}
+
+func (s *Secret) isGrantedTo(unitName, appName string) (bool, error) {
+	for _, grant := range s.doc.Grants {
+		if grant.Unit != "" && grant.Unit != unitName {
+			continue
+		}
+		rel, err := s.st.KeyRelation(grant.Relation)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return false, errors.Trace(err)
+		}
+		if _, err := rel.Endpoint(appName); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AddSecretArgs holds the arguments for adding a secret.
+type AddSecretArgs struct {
+	// Owner is the name of the application that owns the secret.
+	Owner string
+
+	// Label optionally identifies the secret among those of its
+	// owner.
+	Label string
+
+	// Value holds the contents of the secret.
+	Value map[string]string
+}
+
+// AddSecret adds a secret owned by an application, and returns it.
+func (st *State) AddSecret(args AddSecretArgs) (_ *Secret, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add secret for application %q", args.Owner)
+	if err := validateSecretValue(args.Value); err != nil {
+		return nil, errors.Trace(err)
+	}
+	app, err := st.Application(args.Owner)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if args.Label != "" {
+		if _, err := st.SecretByLabel(args.Owner, args.Label); err == nil {
+			return nil, errors.AlreadyExistsf("secret with label %q", args.Label)
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+	}
+	key, err := st.secretsKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	encrypted, err := encryptSecretValue(key, args.Value)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	seq, err := sequence(st, "secret")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	id := fmt.Sprintf("%s%d", secretIdPrefix, seq)
+	now := st.clock().Now().UnixNano()
+	doc := secretDoc{
+		DocID:      st.docID(id),
+		Id:         id,
+		Owner:      args.Owner,
+		Label:      args.Label,
+		Revision:   1,
+		Value:      encrypted,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     app.doc.DocID,
+		Assert: isAliveDoc,
+	}, {
+		C:      secretsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.Errorf("application is not alive")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Secret{st: st, doc: doc}, nil
+}
+
+// Secret returns the secret with the given id.
+func (st *State) Secret(id string) (*Secret, error) {
+	doc, err := st.secretDoc(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Secret{st: st, doc: *doc}, nil
+}
+
+// SecretByLabel returns the secret of the owner application with the
+// given label.
+func (st *State) SecretByLabel(owner, label string) (*Secret, error) {
+	secrets, err := st.secrets(bson.D{{"owner", owner}, {"label", label}})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(secrets) == 0 {
+		return nil, errors.NotFoundf("secret with label %q", label)
+	}
+	return secrets[0], nil
+}
+
+// AllSecrets returns all the secrets in the model.
+func (st *State) AllSecrets() ([]*Secret, error) {
+	return st.secrets(nil)
+}
+
+// ApplicationSecrets returns the secrets owned by the application.
+func (st *State) ApplicationSecrets(owner string) ([]*Secret, error) {
+	return st.secrets(bson.D{{"owner", owner}})
+}
+
+// SecretsGrantedTo returns the secrets, not owned by the unit's
+// application, that the named unit has been granted access to.
+func (st *State) SecretsGrantedTo(unitName string) ([]*Secret, error) {
+	appName, err := names.UnitApplication(unitName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	candidates, err := st.secrets(bson.D{
+		{"owner", bson.D{{"$ne", appName}}},
+		{"grants", bson.D{{"$exists", true}}},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var granted []*Secret
+	for _, secret := range candidates {
+		ok, err := secret.isGrantedTo(unitName, appName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if ok {
+			granted = append(granted, secret)
+		}
+	}
+	return granted, nil
+}
+
+// WatchSecrets returns a watcher that notifies of changes to any of
+// the secrets in the model.
+func (st *State) WatchSecrets() NotifyWatcher {
+	return newNotifyCollWatcher(st, secretsC, isLocalID(st))
+}
+
+func (st *State) secretDoc(id string) (*secretDoc, error) {
+	coll, closer := st.db().GetCollection(secretsC)
+	defer closer()
+
+	var doc secretDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("secret %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get secret %q", id)
+	}
+	return &doc, nil
+}
+
+func (st *State) secrets(query bson.D) ([]*Secret, error) {
+	coll, closer := st.db().GetCollection(secretsC)
+	defer closer()
+
+	var docs []secretDoc
+	if err := coll.Find(query).Sort("create-time").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get secrets")
+	}
+	secrets := make([]*Secret, len(docs))
+	for i, doc := range docs {
+		secrets[i] = &Secret{st: st, doc: doc}
+	}
+	return secrets, nil
+}
+
+// removeApplicationSecretsOps returns the operations required to
+// remove the secrets owned by the application.
+func removeApplicationSecretsOps(st *State, owner string) ([]txn.Op, error) {
+	secrets, err := st.ApplicationSecrets(owner)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(secrets))
+	for i, secret := range secrets {
+		ops[i] = txn.Op{
+			C:      secretsC,
+			Id:     secret.doc.DocID,
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
+
+// secretsKey returns the key with which the values of the model's
+// secrets are encrypted. It is derived from the controller's secrets
+// key, which is kept in the controller agents' configuration rather
+// than in the database, so that the secrets in a copy of the database
+// can't be read.
+func (st *State) secretsKey() ([]byte, error) {
+	if st.controllerSecretsKey == "" {
+		return nil, errors.NotSupportedf("secrets without a controller secrets key")
+	}
+	mac := hmac.New(sha256.New, []byte(st.controllerSecretsKey))
+	mac.Write([]byte(st.ModelUUID()))
+	return mac.Sum(nil), nil
+}
+
+// SecretsKey returns the controller's secrets key, as given when the
+// state was opened.
+func (st *State) SecretsKey() string {
+	return st.controllerSecretsKey
+}
+
+func validateSecretValue(value map[string]string) error {
+	if len(value) == 0 {
+		return errors.NotValidf("empty secret value")
+	}
+	for k := range value {
+		if k == "" {
+			return errors.NotValidf("empty secret key")
+		}
+	}
+	return nil
+}
+
+// encryptSecretValue encrypts the value of a secret with AES-GCM,
+// returning the nonce followed by the sealed value.
+func encryptSecretValue(key []byte, value map[string]string) ([]byte, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	gcm, err := newSecretsCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// decryptSecretValue decrypts a value encrypted by
+// encryptSecretValue.
+func decryptSecretValue(key, encrypted []byte) (map[string]string, error) {
+	gcm, err := newSecretsCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(encrypted) < gcm.NonceSize() {
+		return nil, errors.New("encrypted value too short")
+	}
+	nonce, sealed := encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var value map[string]string
+	if err := json.Unmarshal(plaintext, &value); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return value, nil
+}
+
+func newSecretsCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,232 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/mongo/mongotest"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type SecretsSuite struct {
+	ConnSuite
+	wordpress *state.Application
+	mysql     *state.Application
+	relation  *state.Relation
+}
+
+var _ = gc.Suite(&SecretsSuite{})
+
+func (s *SecretsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.relation, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SecretsSuite) addSecret(c *gc.C, label string) *state.Secret {
+	secret, err := s.State.AddSecret(state.AddSecretArgs{
+		Owner: "mysql",
+		Label: label,
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return secret
+}
+
+func (s *SecretsSuite) TestAddSecret(c *gc.C) {
+	secret := s.addSecret(c, "root")
+	c.Assert(secret.Id(), gc.Equals, "secret-0")
+	c.Assert(secret.Owner(), gc.Equals, "mysql")
+	c.Assert(secret.Label(), gc.Equals, "root")
+	c.Assert(secret.Revision(), gc.Equals, 1)
+	c.Assert(secret.Grants(), gc.HasLen, 0)
+
+	secret, err := s.State.Secret("secret-0")
+	c.Assert(err, jc.ErrorIsNil)
+	value, err := secret.Value()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(value, jc.DeepEquals, map[string]string{"password": "sekrit"})
+
+	secrets, err := s.State.AllSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 1)
+	c.Assert(secrets[0].Id(), gc.Equals, "secret-0")
+}
+
+func (s *SecretsSuite) TestSecretValueStoredEncrypted(c *gc.C) {
+	s.addSecret(c, "")
+	coll, closer := state.GetCollection(s.State, "secrets")
+	defer closer()
+
+	var raw bson.M
+	err := coll.FindId("secret-0").One(&raw)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(raw["value"].([]byte)), gc.Not(jc.Contains), "sekrit")
+}
+
+func (s *SecretsSuite) TestAddSecretInvalid(c *gc.C) {
+	_, err := s.State.AddSecret(state.AddSecretArgs{Owner: "mysql"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `cannot add secret for application "mysql": empty secret value not valid`)
+
+	_, err = s.State.AddSecret(state.AddSecretArgs{
+		Owner: "varnish",
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretsSuite) TestAddSecretWithoutSecretsKey(c *gc.C) {
+	st, err := state.Open(state.OpenParams{
+		Clock:              clock.WallClock,
+		ControllerTag:      s.State.ControllerTag(),
+		ControllerModelTag: s.State.ModelTag(),
+		MongoInfo:          statetesting.NewMongoInfo(),
+		MongoDialOpts:      mongotest.DialOpts(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.AddSecret(state.AddSecretArgs{
+		Owner: "mysql",
+		Value: map[string]string{"password": "sekrit"},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *SecretsSuite) TestAddSecretDuplicateLabel(c *gc.C) {
+	s.addSecret(c, "root")
+	_, err := s.State.AddSecret(state.AddSecretArgs{
+		Owner: "mysql",
+		Label: "root",
+		Value: map[string]string{"password": "other"},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `cannot add secret for application "mysql": secret with label "root" already exists`)
+
+	secret, err := s.State.SecretByLabel("mysql", "root")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Id(), gc.Equals, "secret-0")
+}
+
+func (s *SecretsSuite) TestSecretNotFound(c *gc.C) {
+	_, err := s.State.Secret("secret-42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `secret "secret-42" not found`)
+}
+
+func (s *SecretsSuite) TestRotate(c *gc.C) {
+	secret := s.addSecret(c, "")
+	err := secret.Rotate(map[string]string{"password": "new"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Revision(), gc.Equals, 2)
+
+	secret, err = s.State.Secret(secret.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Revision(), gc.Equals, 2)
+	value, err := secret.Value()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(value, jc.DeepEquals, map[string]string{"password": "new"})
+}
+
+func (s *SecretsSuite) TestGrant(c *gc.C) {
+	secret := s.addSecret(c, "")
+	canRead, err := secret.CanRead("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(canRead, jc.IsFalse)
+
+	err = secret.Grant(state.SecretGrant{Relation: s.relation.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Grants(), jc.DeepEquals, []state.SecretGrant{{Relation: s.relation.String()}})
+
+	for _, unit := range []string{"wordpress/0", "wordpress/1", "mysql/0"} {
+		canRead, err := secret.CanRead(unit)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(canRead, jc.IsTrue)
+	}
+	granted, err := s.State.SecretsGrantedTo("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(granted, gc.HasLen, 1)
+	c.Assert(granted[0].Id(), gc.Equals, secret.Id())
+
+	// Secrets are never reported as granted to their owner.
+	granted, err = s.State.SecretsGrantedTo("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(granted, gc.HasLen, 0)
+}
+
+func (s *SecretsSuite) TestGrantUnit(c *gc.C) {
+	secret := s.addSecret(c, "")
+	err := secret.Grant(state.SecretGrant{Relation: s.relation.String(), Unit: "wordpress/1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	canRead, err := secret.CanRead("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(canRead, jc.IsFalse)
+	canRead, err = secret.CanRead("wordpress/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(canRead, jc.IsTrue)
+}
+
+func (s *SecretsSuite) TestGrantUnrelatedUnit(c *gc.C) {
+	secret := s.addSecret(c, "")
+	err := secret.Grant(state.SecretGrant{Relation: s.relation.String(), Unit: "varnish/0"})
+	c.Assert(err, gc.ErrorMatches, `cannot grant secret "secret-0": unit "varnish/0" is not related to "mysql" over relation "wordpress:db mysql:server"`)
+}
+
+func (s *SecretsSuite) TestGrantRelationNotFound(c *gc.C) {
+	secret := s.addSecret(c, "")
+	err := secret.Grant(state.SecretGrant{Relation: "wordpress:db varnish:server"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretsSuite) TestGrantRemovedWithRelation(c *gc.C) {
+	secret := s.addSecret(c, "")
+	err := secret.Grant(state.SecretGrant{Relation: s.relation.String()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.relation.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	canRead, err := secret.CanRead("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(canRead, jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestRemovedWithApplication(c *gc.C) {
+	s.addSecret(c, "")
+	err := s.relation.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	secrets, err := s.State.AllSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 0)
+}
+
+func (s *SecretsSuite) TestWatchSecrets(c *gc.C) {
+	w := s.State.WatchSecrets()
+	defer statetesting.AssertStop(c, w)
+
+	// Initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	secret := s.addSecret(c, "")
+	wc.AssertOneChange()
+
+	err := secret.Rotate(map[string]string{"password": "new"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
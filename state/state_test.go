@@ -4027,6 +4027,219 @@ func (s *StateSuite) TestEnableHALessPlacementThanNewCount(c *gc.C) {
 	s.assertControllerInfo(c, []string{"0", "1", "2"}, []string{"0", "1", "2"}, []string{"p1", "p2"})
 }
 
+func (s *StateSuite) TestEnableHAInZones(c *gc.C) {
+	cons := constraints.MustParse("mem=4G")
+	changes, err := s.State.EnableHAInZones(3, cons, "quantal", []string{"p1"}, []string{"az1", "az2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, gc.HasLen, 3)
+	s.assertControllerInfo(c, []string{"0", "1", "2"}, []string{"0", "1", "2"}, []string{"p1", "zone=az1", "zone=az2"})
+	for _, id := range []string{"1", "2"} {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		gotCons, err := m.Constraints()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(gotCons, gc.DeepEquals, cons)
+	}
+}
+
+func (s *StateSuite) TestEnableHAInZonesAvoidsPopulatedZones(c *gc.C) {
+	// Don't use agent presence to decide on machine availability.
+	s.PatchValue(state.ControllerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	m0, err := s.State.AddMachine("quantal", state.JobHostUnits, state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	zone := "az1"
+	err = m0.SetProvisioned("inst-0", "fake_nonce", &instance.HardwareCharacteristics{
+		AvailabilityZone: &zone,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	changes, err := s.State.EnableHAInZones(3, constraints.Value{}, "quantal", nil, []string{"az1", "az2", "az3"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, gc.DeepEquals, []string{"1", "2"})
+	s.assertControllerInfo(c, []string{"0", "1", "2"}, []string{"0", "1", "2"}, []string{"", "zone=az2", "zone=az3"})
+}
+
+func (s *StateSuite) TestEnableHAReducesControllerCount(c *gc.C) {
+	s.PatchValue(state.ControllerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	changes, err := s.State.EnableHA(5, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, gc.HasLen, 5)
+
+	changes, err = s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, gc.HasLen, 0)
+	c.Assert(changes.Maintained, gc.DeepEquals, []string{"0", "1", "2"})
+	c.Assert(changes.Demoted, gc.DeepEquals, []string{"4", "3"})
+	s.assertControllerInfo(c, []string{"0", "1", "2", "3", "4"}, []string{"0", "1", "2"}, nil)
+	for _, id := range []string{"3", "4"} {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(m.WantsVote(), jc.IsFalse)
+		c.Check(m.IsDecommissioning(), jc.IsTrue)
+	}
+
+	// The decommissioning machines are not promoted again.
+	changes, err = s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Promoted, gc.HasLen, 0)
+	c.Assert(changes.Added, gc.HasLen, 0)
+}
+
+func (s *StateSuite) TestEnableHAReducesControllerCountDeliberately(c *gc.C) {
+	s.PatchValue(state.ControllerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	_, err := s.State.EnableHA(5, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, id := range []string{"0", "1", "2", "3"} {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		err = m.SetHasVote(true)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.PatchValue(state.ControllerAvailable, func(m *state.Machine) (bool, error) {
+		return m.Id() != "1", nil
+	})
+
+	// The unavailable controller is removed rather than kept without
+	// a vote, followed by the controller which does not yet have a vote.
+	changes, err := s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Demoted, gc.DeepEquals, []string{"1", "4"})
+	c.Assert(changes.Maintained, jc.SameContents, []string{"0", "2", "3"})
+	s.assertControllerInfo(c, []string{"0", "1", "2", "3", "4"}, []string{"0", "2", "3"}, nil)
+	for _, id := range []string{"1", "4"} {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(m.IsDecommissioning(), jc.IsTrue)
+	}
+}
+
+func (s *StateSuite) TestRemoveControllerMachine(c *gc.C) {
+	s.PatchValue(state.ControllerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	changes, err := s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, gc.HasLen, 3)
+
+	// The machine's vote is replaced in the same transaction.
+	changes, err = s.State.RemoveControllerMachine("1", constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Demoted, gc.DeepEquals, []string{"1"})
+	c.Assert(changes.Added, gc.DeepEquals, []string{"3"})
+	s.assertControllerInfo(c, []string{"0", "1", "2", "3"}, []string{"0", "2", "3"}, nil)
+	m1, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m1.WantsVote(), jc.IsFalse)
+	c.Assert(m1.IsDecommissioning(), jc.IsTrue)
+
+	// Removing it again is a no-op.
+	changes, err = s.State.RemoveControllerMachine("1", constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, state.ControllersChanges{})
+}
+
+func (s *StateSuite) TestRemoveControllerMachinePromotes(c *gc.C) {
+	available := map[string]bool{"0": true, "1": true, "2": true}
+	s.PatchValue(state.ControllerAvailable, func(m *state.Machine) (bool, error) {
+		return available[m.Id()], nil
+	})
+	_, err := s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Machine 2 is unavailable, so its vote is given to a new machine.
+	available["2"] = false
+	changes, err := s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Demoted, gc.DeepEquals, []string{"2"})
+	c.Assert(changes.Added, gc.DeepEquals, []string{"3"})
+	available["2"] = true
+	available["3"] = true
+
+	// The available non-voting controller replaces the removed one.
+	changes, err = s.State.RemoveControllerMachine("1", constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Promoted, gc.DeepEquals, []string{"2"})
+	c.Assert(changes.Demoted, gc.DeepEquals, []string{"1"})
+	c.Assert(changes.Added, gc.HasLen, 0)
+	s.assertControllerInfo(c, []string{"0", "1", "2", "3"}, []string{"0", "2", "3"}, nil)
+}
+
+func (s *StateSuite) TestRemoveControllerMachineConcurrentChange(c *gc.C) {
+	s.PatchValue(state.ControllerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	_, err := s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := s.State.RemoveControllerMachine("2", constraints.Value{}, "quantal", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		s.assertControllerInfo(c, []string{"0", "1", "2", "3"}, []string{"0", "1", "3"}, nil)
+	}).Check()
+
+	// The concurrent removal changes the voters, so the replacement is
+	// worked out again rather than leaving an even number of voters.
+	changes, err := s.State.RemoveControllerMachine("1", constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Demoted, gc.DeepEquals, []string{"1"})
+	c.Assert(changes.Added, gc.DeepEquals, []string{"4"})
+	s.assertControllerInfo(c, []string{"0", "1", "2", "3", "4"}, []string{"0", "3", "4"}, nil)
+}
+
+func (s *StateSuite) TestRemoveControllerMachineOnlyVoter(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits, state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.RemoveControllerMachine("0", constraints.Value{}, "quantal", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot remove controller machine 0: machine is the only voting controller`)
+}
+
+func (s *StateSuite) TestRemoveControllerMachineNotController(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.RemoveControllerMachine("0", constraints.Value{}, "quantal", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot remove controller machine 0: machine is not a controller`)
+}
+
+func (s *StateSuite) TestDecommission(c *gc.C) {
+	s.PatchValue(state.ControllerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	changes, err := s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, gc.HasLen, 3)
+	m1, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = m1.SetHasVote(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = m1.Decommission()
+	c.Assert(err, gc.ErrorMatches, `cannot decommission controller machine 1: machine is not being decommissioned`)
+
+	_, err = s.State.RemoveControllerMachine("1", constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	err = m1.Decommission()
+	c.Assert(err, gc.ErrorMatches, `cannot decommission controller machine 1: machine still has a vote`)
+
+	err = m1.SetHasVote(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m1.Decommission()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertControllerInfo(c, []string{"0", "2", "3"}, []string{"0", "2", "3"}, nil)
+	err = m1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m1.IsManager(), jc.IsFalse)
+	c.Assert(m1.IsDecommissioning(), jc.IsFalse)
+	c.Assert(m1.Life(), gc.Equals, state.Dying)
+}
+
 func (s *StateSuite) TestEnableHADemotesUnavailableMachines(c *gc.C) {
 	changes, err := s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -4260,10 +4473,12 @@ func (s *StateSuite) TestEnableHAConcurrentMore(c *gc.C) {
 	// This call to EnableHA will initially attempt to allocate
 	// machines 0..2, and fail due to the concurrent change. It will then
 	// find that the number of voting machines in state is greater than
-	// what we're attempting to ensure, and fail.
+	// what we're attempting to ensure, and decommission the newest.
 	changes, err := s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
-	c.Assert(err, gc.ErrorMatches, "failed to create new controller machines: cannot reduce controller count")
+	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(changes.Added, gc.HasLen, 0)
+	c.Assert(changes.Demoted, gc.DeepEquals, []string{"7", "6"})
+	s.assertControllerInfo(c, []string{"3", "4", "5", "6", "7"}, []string{"3", "4", "5"}, nil)
 
 	// Machine 0 should never have been created.
 	_, err = s.State.Machine("0")
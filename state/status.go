@@ -124,6 +124,16 @@ func (m *ModelStatus) UnitWorkloadVersion(unitName string) (string, error) {
 	return info.Message, nil
 }
 
+// UnitHealth returns the health status of the unit.
+func (m *ModelStatus) UnitHealth(unitName string) (status.StatusInfo, error) {
+	return m.getStatus(globalHealthKey(unitName), "health")
+}
+
+// UnitContainer returns the status of the unit's container.
+func (m *ModelStatus) UnitContainer(unitName string) (status.StatusInfo, error) {
+	return m.getStatus(globalContainerKey(unitName), "container")
+}
+
 // UnitWorkload returns the status of the machine instance.
 func (m *ModelStatus) UnitAgent(unitName string) (status.StatusInfo, error) {
 	// We do horrible things with unit status.
@@ -105,6 +105,7 @@ func InitializeWithArgs(c *gc.C, args InitializeArgs) (*state.Controller, *state
 		MongoInfo:     mgoInfo,
 		MongoDialOpts: dialOpts,
 		NewPolicy:     args.NewPolicy,
+		SecretsKey:    testing.SecretsKey,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	return ctlr, st
@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/core/actions"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
+	mongoutils "github.com/juju/juju/mongo/utils"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state/presence"
 	"github.com/juju/juju/status"
@@ -92,6 +93,10 @@ type unitDoc struct {
 	Life                   Life
 	TxnRevno               int64 `bson:"txn-revno"`
 	PasswordHash           string
+
+	// ContainerAddress is the address of the container running the
+	// unit, as reported by the CAAS substrate.
+	ContainerAddress string `bson:"containeraddress,omitempty"`
 }
 
 // Unit represents the state of a service unit.
@@ -167,6 +172,18 @@ func globalWorkloadVersionKey(name string) string {
 	return unitGlobalKey(name) + "#sat#workload-version"
 }
 
+// globalHealthKey returns the global database key for the health
+// status of the named unit.
+func globalHealthKey(name string) string {
+	return unitGlobalKey(name) + "#sat#health"
+}
+
+// globalContainerKey returns the global database key for the status
+// of the container running the named unit on a CAAS substrate.
+func globalContainerKey(name string) string {
+	return unitGlobalKey(name) + "#sat#container"
+}
+
 // globalAgentKey returns the global database key for the unit.
 func (u *Unit) globalAgentKey() string {
 	return unitAgentGlobalKey(u.doc.Name)
@@ -188,6 +205,18 @@ func (u *Unit) globalWorkloadVersionKey() string {
 	return globalWorkloadVersionKey(u.doc.Name)
 }
 
+// globalHealthKey returns the global database key for the unit's
+// health status.
+func (u *Unit) globalHealthKey() string {
+	return globalHealthKey(u.doc.Name)
+}
+
+// globalContainerKey returns the global database key for the status
+// of the unit's container.
+func (u *Unit) globalContainerKey() string {
+	return globalContainerKey(u.doc.Name)
+}
+
 // Life returns whether the unit is Alive, Dying or Dead.
 func (u *Unit) Life() Life {
 	return u.doc.Life
@@ -228,6 +257,158 @@ func (u *Unit) WorkloadVersionHistory() *HistoryGetter {
 	return &HistoryGetter{st: u.st, globalKey: u.globalWorkloadVersionKey()}
 }
 
+// HealthStatus returns the health of the unit's workload, as reported
+// by the unit agent from the results of the health checks declared by
+// the charm. The status is unknown if the charm declares no checks.
+func (u *Unit) HealthStatus() (status.StatusInfo, error) {
+	info, err := getStatus(u.st.db(), u.globalHealthKey(), "health")
+	if errors.IsNotFound(err) {
+		return status.StatusInfo{Status: status.Unknown}, nil
+	} else if err != nil {
+		return status.StatusInfo{}, errors.Trace(err)
+	}
+	return info, nil
+}
+
+// SetHealthStatus sets the health of the unit's workload. Like the
+// workload version, the health is kept in its own status document, so
+// that the periodic results of the health checks do not disturb the
+// watchers of the unit or its workload status.
+func (u *Unit) SetHealthStatus(healthInfo status.StatusInfo) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set health status of unit %q", u)
+	if !status.ValidHealthStatus(healthInfo.Status) {
+		return errors.Errorf("invalid health status %q", healthInfo.Status)
+	}
+	updated := timeOrNow(healthInfo.Since, u.st.clock())
+	doc := statusDoc{
+		Status:     healthInfo.Status,
+		StatusInfo: healthInfo.Message,
+		StatusData: mongoutils.EscapeKeys(healthInfo.Data),
+		Updated:    updated.UnixNano(),
+	}
+	db := u.st.db()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.Life() == Dead {
+			return nil, ErrDead
+		}
+		_, err := getStatus(db, u.globalHealthKey(), "health")
+		if errors.IsNotFound(err) {
+			// The health is only recorded once the charm
+			// declares health checks.
+			return []txn.Op{{
+				C:      unitsC,
+				Id:     u.doc.DocID,
+				Assert: notDeadDoc,
+			}, createStatusOp(u.st, u.globalHealthKey(), doc)}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return statusSetOps(db, doc, u.globalHealthKey())
+	}
+	if err := db.Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	probablyUpdateStatusHistory(db, u.globalHealthKey(), doc)
+	return nil
+}
+
+// HealthHistory returns a HistoryGetter which enables the caller to
+// request past health status changes.
+func (u *Unit) HealthHistory() status.StatusHistoryGetter {
+	return &HistoryGetter{st: u.st, globalKey: u.globalHealthKey()}
+}
+
+// ContainerStatus returns the status of the container running the
+// unit, as reported by the CAAS substrate. The status is unknown if
+// the unit is not running in a container, or none has yet been
+// reported.
+func (u *Unit) ContainerStatus() (status.StatusInfo, error) {
+	info, err := getStatus(u.st.db(), u.globalContainerKey(), "container")
+	if errors.IsNotFound(err) {
+		return status.StatusInfo{Status: status.Unknown}, nil
+	} else if err != nil {
+		return status.StatusInfo{}, errors.Trace(err)
+	}
+	return info, nil
+}
+
+// SetContainerStatus sets the status of the container running the
+// unit. It is kept apart from the agent and workload status, which
+// remain under the control of the unit agent.
+func (u *Unit) SetContainerStatus(containerInfo status.StatusInfo) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set container status of unit %q", u)
+	if !status.ValidContainerStatus(containerInfo.Status) {
+		return errors.Errorf("invalid container status %q", containerInfo.Status)
+	}
+	updated := timeOrNow(containerInfo.Since, u.st.clock())
+	doc := statusDoc{
+		Status:     containerInfo.Status,
+		StatusInfo: containerInfo.Message,
+		StatusData: mongoutils.EscapeKeys(containerInfo.Data),
+		Updated:    updated.UnixNano(),
+	}
+	db := u.st.db()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.Life() == Dead {
+			return nil, ErrDead
+		}
+		_, err := getStatus(db, u.globalContainerKey(), "container")
+		if errors.IsNotFound(err) {
+			// The container status is only recorded once
+			// the substrate reports it.
+			return []txn.Op{{
+				C:      unitsC,
+				Id:     u.doc.DocID,
+				Assert: notDeadDoc,
+			}, createStatusOp(u.st, u.globalContainerKey(), doc)}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return statusSetOps(db, doc, u.globalContainerKey())
+	}
+	if err := db.Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	probablyUpdateStatusHistory(db, u.globalContainerKey(), doc)
+	return nil
+}
+
+// ContainerAddress returns the address of the container running the
+// unit, as reported by the CAAS substrate. It returns an error
+// satisfying errors.IsNotFound if no address has been reported.
+func (u *Unit) ContainerAddress() (network.Address, error) {
+	if u.doc.ContainerAddress == "" {
+		return network.Address{}, errors.NotFoundf("container address for unit %q", u)
+	}
+	return network.NewAddress(u.doc.ContainerAddress), nil
+}
+
+// SetContainerAddress records the address of the container running
+// the unit.
+func (u *Unit) SetContainerAddress(address string) error {
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"containeraddress", address}}}},
+	}}
+	if err := u.st.db().RunTransaction(ops); err != nil {
+		return errors.Errorf("cannot set container address of unit %q: %v", u, onAbort(err, ErrDead))
+	}
+	u.doc.ContainerAddress = address
+	return nil
+}
+
 // AgentTools returns the tools that the agent is currently running.
 // It an error that satisfies errors.IsNotFound if the tools have not
 // yet been set.
@@ -369,6 +550,15 @@ func (u *Unit) eraseHistory() error {
 	if err := eraseStatusHistory(u.st, u.globalWorkloadVersionKey()); err != nil {
 		return errors.Annotate(err, "version")
 	}
+	if err := eraseStatusHistory(u.st, u.globalHealthKey()); err != nil {
+		return errors.Annotate(err, "health")
+	}
+	if err := eraseStatusHistory(u.st, u.globalContainerKey()); err != nil {
+		return errors.Annotate(err, "container")
+	}
+	if err := u.eraseHookExecutions(); err != nil {
+		return errors.Annotate(err, "hook executions")
+	}
 	return nil
 }
 
@@ -789,6 +979,9 @@ func (u *Unit) noAssignedMachineOp() txn.Op {
 // PublicAddress returns the public address of the unit.
 func (u *Unit) PublicAddress() (network.Address, error) {
 	m, err := u.machine()
+	if errors.IsNotAssigned(err) {
+		return u.containerAddress("public")
+	}
 	if err != nil {
 		unitLogger.Tracef("%v", err)
 		return network.Address{}, errors.Trace(err)
@@ -799,6 +992,9 @@ func (u *Unit) PublicAddress() (network.Address, error) {
 // PrivateAddress returns the private address of the unit.
 func (u *Unit) PrivateAddress() (network.Address, error) {
 	m, err := u.machine()
+	if errors.IsNotAssigned(err) {
+		return u.containerAddress("private")
+	}
 	if err != nil {
 		unitLogger.Tracef("%v", err)
 		return network.Address{}, errors.Trace(err)
@@ -806,6 +1002,16 @@ func (u *Unit) PrivateAddress() (network.Address, error) {
 	return m.PrivateAddress()
 }
 
+// containerAddress returns the address of the container running a unit
+// that is not assigned to a machine, for use as its public or private
+// address.
+func (u *Unit) containerAddress(addressKind string) (network.Address, error) {
+	if u.doc.ContainerAddress == "" {
+		return network.Address{}, network.NoAddressError(addressKind)
+	}
+	return network.NewAddress(u.doc.ContainerAddress), nil
+}
+
 // AvailabilityZone returns the name of the availability zone into which
 // the unit's machine instance was provisioned.
 func (u *Unit) AvailabilityZone() (string, error) {
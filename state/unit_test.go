@@ -1920,6 +1920,96 @@ func (s *UnitSuite) TestWorkloadVersion(c *gc.C) {
 	c.Check(version, gc.Equals, "3.combined")
 }
 
+func (s *UnitSuite) TestHealthStatus(c *gc.C) {
+	health, err := s.unit.HealthStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(health.Status, gc.Equals, status.Unknown)
+
+	now := coretesting.NonZeroTime()
+	err = s.unit.SetHealthStatus(status.StatusInfo{
+		Status:  status.Unhealthy,
+		Message: `check "http" failed`,
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	later := now.Add(time.Second)
+	err = s.unit.SetHealthStatus(status.StatusInfo{
+		Status: status.Healthy,
+		Since:  &later,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	health, err = s.unit.HealthStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(health.Status, gc.Equals, status.Healthy)
+
+	history, err := s.unit.HealthHistory().StatusHistory(status.StatusHistoryFilter{Size: 10})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Check(history[0].Status, gc.Equals, status.Healthy)
+	c.Check(history[1].Status, gc.Equals, status.Unhealthy)
+	c.Check(history[1].Message, gc.Equals, `check "http" failed`)
+}
+
+func (s *UnitSuite) TestSetHealthStatusInvalid(c *gc.C) {
+	err := s.unit.SetHealthStatus(status.StatusInfo{Status: status.Active})
+	c.Assert(err, gc.ErrorMatches, `cannot set health status of unit "wordpress/0": invalid health status "active"`)
+}
+
+func (s *UnitSuite) TestContainerStatus(c *gc.C) {
+	containerStatus, err := s.unit.ContainerStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(containerStatus.Status, gc.Equals, status.Unknown)
+
+	now := coretesting.NonZeroTime()
+	err = s.unit.SetContainerStatus(status.StatusInfo{
+		Status:  status.Running,
+		Message: "pod running",
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	containerStatus, err = s.unit.ContainerStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(containerStatus.Status, gc.Equals, status.Running)
+	c.Check(containerStatus.Message, gc.Equals, "pod running")
+
+	// The unit's own status is untouched.
+	unitStatus, err := s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(unitStatus.Status, gc.Not(gc.Equals), status.Running)
+}
+
+func (s *UnitSuite) TestSetContainerStatusInvalid(c *gc.C) {
+	err := s.unit.SetContainerStatus(status.StatusInfo{Status: status.Active})
+	c.Assert(err, gc.ErrorMatches, `cannot set container status of unit "wordpress/0": invalid container status "active"`)
+}
+
+func (s *UnitSuite) TestContainerAddress(c *gc.C) {
+	_, err := s.unit.ContainerAddress()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.unit.PrivateAddress()
+	c.Assert(err, jc.Satisfies, network.IsNoAddressError)
+
+	err = s.unit.SetContainerAddress("10.0.0.1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+
+	address, err := s.unit.ContainerAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(address, jc.DeepEquals, network.NewAddress("10.0.0.1"))
+
+	// A unit that is not assigned to a machine is addressed
+	// by its container.
+	address, err = s.unit.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(address.Value, gc.Equals, "10.0.0.1")
+	address, err = s.unit.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(address.Value, gc.Equals, "10.0.0.1")
+}
+
 func unitMachine(c *gc.C, st *state.State, u *state.Unit) *state.Machine {
 	machineId, err := u.AssignedMachineId()
 	c.Assert(err, jc.ErrorIsNil)
@@ -6,7 +6,6 @@ package state
 import (
 	"fmt"
 	"reflect"
-	"regexp"
 	"strings"
 	"time"
 
@@ -191,6 +190,9 @@ type lifecycleWatcher struct {
 	transform func(string) string
 	// life holds the most recent known life states of interesting entities.
 	life map[string]Life
+	// scoped, if true, means entities may stop matching members; those
+	// that do are forgotten without notification.
+	scoped bool
 }
 
 func collFactory(db Database, collName string) func() (mongo.Collection, func()) {
@@ -423,31 +425,50 @@ func (st *State) WatchModelMachines() StringsWatcher {
 }
 
 // WatchContainers returns a StringsWatcher that notifies of changes to the
-// lifecycles of containers of the specified type on a machine.
+// lifecycles of containers of the specified type on a machine, including
+// containers moved to the machine.
 func (m *Machine) WatchContainers(ctype instance.ContainerType) StringsWatcher {
 	isChild := fmt.Sprintf("^%s/%s/%s$", m.doc.DocID, ctype, names.NumberSnippet)
-	return m.containersWatcher(isChild)
+	return m.containersWatcher(isChild, bson.D{
+		{"host-id", m.Id()},
+		{"containertype", string(ctype)},
+	})
 }
 
 // WatchAllContainers returns a StringsWatcher that notifies of changes to the
-// lifecycles of all containers on a machine.
+// lifecycles of all containers on a machine, including containers moved or
+// being moved to the machine.
 func (m *Machine) WatchAllContainers() StringsWatcher {
 	isChild := fmt.Sprintf("^%s/%s/%s$", m.doc.DocID, names.ContainerTypeSnippet, names.NumberSnippet)
-	return m.containersWatcher(isChild)
+	return m.containersWatcher(isChild,
+		bson.D{{"host-id", m.Id()}},
+		bson.D{{"move.target-id", m.Id()}},
+	)
 }
 
-func (m *Machine) containersWatcher(isChildRegexp string) StringsWatcher {
-	members := bson.D{{"_id", bson.D{{"$regex", isChildRegexp}}}}
-	compiled := regexp.MustCompile(isChildRegexp)
-	filter := func(key interface{}) bool {
-		k := key.(string)
-		_, err := m.st.strictLocalID(k)
+// containersWatcher watches the containers with ids matching
+// isChildRegexp that have not been moved to another machine, along
+// with any containers matching one of the moved criteria.
+func (m *Machine) containersWatcher(isChildRegexp string, moved ...bson.D) StringsWatcher {
+	children := bson.D{
+		{"_id", bson.D{{"$regex", isChildRegexp}}},
+		{"host-id", bson.D{{"$exists", false}}},
+	}
+	members := bson.D{{"$or", append([]bson.D{children}, moved...)}}
+	return newScopedLifecycleWatcher(m.st, machinesC, members, isLocalContainerID(m.st))
+}
+
+// isLocalContainerID returns a watcher filter func that rejects ids
+// that are not for containers in the backend's model. Containers may
+// move between machines, so the parent named in the id is not checked.
+func isLocalContainerID(st modelBackend) func(interface{}) bool {
+	return func(key interface{}) bool {
+		k, err := st.strictLocalID(key.(string))
 		if err != nil {
 			return false
 		}
-		return compiled.MatchString(k)
+		return strings.Contains(k, "/")
 	}
-	return newLifecycleWatcher(m.st, machinesC, members, filter, nil)
 }
 
 func newLifecycleWatcher(
@@ -457,7 +478,7 @@ func newLifecycleWatcher(
 	filter func(key interface{}) bool,
 	transform func(id string) string,
 ) StringsWatcher {
-	w := &lifecycleWatcher{
+	return startLifecycleWatcher(&lifecycleWatcher{
 		commonWatcher: newCommonWatcher(backend),
 		coll:          collFactory(backend.db(), collName),
 		collName:      collName,
@@ -466,7 +487,31 @@ func newLifecycleWatcher(
 		transform:     transform,
 		life:          make(map[string]Life),
 		out:           make(chan []string),
-	}
+	})
+}
+
+// newScopedLifecycleWatcher returns a lifecycle watcher for the
+// entities matching members, where entities may start or stop
+// matching members over their lifetime.
+func newScopedLifecycleWatcher(
+	backend modelBackend,
+	collName string,
+	members bson.D,
+	filter func(key interface{}) bool,
+) StringsWatcher {
+	return startLifecycleWatcher(&lifecycleWatcher{
+		commonWatcher: newCommonWatcher(backend),
+		coll:          collFactory(backend.db(), collName),
+		collName:      collName,
+		members:       members,
+		filter:        filter,
+		life:          make(map[string]Life),
+		out:           make(chan []string),
+		scoped:        true,
+	})
+}
+
+func startLifecycleWatcher(w *lifecycleWatcher) StringsWatcher {
 	go func() {
 		defer w.tomb.Done()
 		defer close(w.out)
@@ -533,7 +578,11 @@ func (w *lifecycleWatcher) merge(ids set.Strings, updates map[interface{}]bool)
 	// exist are ignored (we'll hear about them in the next set of updates --
 	// all that's actually happened in that situation is that the watcher
 	// events have lagged a little behind reality).
-	iter := coll.Find(bson.D{{"_id", bson.D{{"$in", changed}}}}).Select(lifeFields).Iter()
+	query := bson.D{{"_id", bson.D{{"$in", changed}}}}
+	if w.scoped {
+		query = append(query, w.members...)
+	}
+	iter := coll.Find(query).Select(lifeFields).Iter()
 	var doc lifeDoc
 	for iter.Next(&doc) {
 		latest[w.backend.localID(doc.Id)] = doc.Life
@@ -541,6 +590,16 @@ func (w *lifecycleWatcher) merge(ids set.Strings, updates map[interface{}]bool)
 	if err := iter.Close(); err != nil {
 		return err
 	}
+	if w.scoped {
+		// Entities that still exist, but no longer match members,
+		// are out of scope.
+		for _, docID := range changed {
+			id := w.backend.localID(docID)
+			if _, ok := latest[id]; !ok {
+				delete(w.life, id)
+			}
+		}
+	}
 
 	// Add to ids any whose life state is known to have changed.
 	for id, newLife := range latest {
@@ -2103,6 +2162,220 @@ func (st *State) WatchCleanups() NotifyWatcher {
 	return newNotifyCollWatcher(st, cleanupsC, isLocalID(st))
 }
 
+// WatchApplicationCharms returns a NotifyWatcher that notifies when
+// the charm of any application in the model changes.
+func (st *State) WatchApplicationCharms() NotifyWatcher {
+	return newApplicationCharmsWatcher(st)
+}
+
+// applicationCharmsWatcher notifies when the charm URL of any
+// application in the model changes.
+type applicationCharmsWatcher struct {
+	commonWatcher
+	out chan struct{}
+}
+
+var _ Watcher = (*applicationCharmsWatcher)(nil)
+
+func newApplicationCharmsWatcher(backend modelBackend) NotifyWatcher {
+	w := &applicationCharmsWatcher{
+		commonWatcher: newCommonWatcher(backend),
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *applicationCharmsWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+type applicationCharmDoc struct {
+	DocID    string     `bson:"_id"`
+	CharmURL *charm.URL `bson:"charmurl"`
+}
+
+func (w *applicationCharmsWatcher) loop() error {
+	in := make(chan watcher.Change)
+	w.watcher.WatchCollectionWithFilter(applicationsC, in, isLocalID(w.backend))
+	defer w.watcher.UnwatchCollection(applicationsC, in)
+
+	applications, closer := w.db.GetCollection(applicationsC)
+	defer closer()
+	charmURLs := make(map[interface{}]string)
+	var docs []applicationCharmDoc
+	if err := applications.Find(nil).Select(bson.D{{"charmurl", 1}}).All(&docs); err != nil {
+		return errors.Trace(err)
+	}
+	for _, doc := range docs {
+		charmURLs[doc.DocID] = doc.CharmURL.String()
+	}
+
+	out := w.out
+	for {
+		select {
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case change := <-in:
+			ids, ok := collect(change, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			for id, exists := range ids {
+				if !exists {
+					delete(charmURLs, id)
+					continue
+				}
+				var doc applicationCharmDoc
+				err := applications.FindId(id).Select(bson.D{{"charmurl", 1}}).One(&doc)
+				if err == mgo.ErrNotFound {
+					delete(charmURLs, id)
+					continue
+				} else if err != nil {
+					return errors.Trace(err)
+				}
+				if url := doc.CharmURL.String(); charmURLs[id] != url {
+					charmURLs[id] = url
+					out = w.out
+				}
+			}
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// containerMovesWatcher notifies of changes to the moves of containers
+// to or from a host machine.
+type containerMovesWatcher struct {
+	commonWatcher
+	members bson.D
+	out     chan []string
+}
+
+var _ Watcher = (*containerMovesWatcher)(nil)
+
+func newContainerMovesWatcher(m *Machine) StringsWatcher {
+	isChild := fmt.Sprintf("^%s/%s/%s$", m.doc.DocID, names.ContainerTypeSnippet, names.NumberSnippet)
+	moving := bson.D{{"$exists", true}}
+	w := &containerMovesWatcher{
+		commonWatcher: newCommonWatcher(m.st),
+		members: bson.D{{"$or", []bson.D{
+			{{"move.target-id", m.Id()}},
+			{{"move", moving}, {"host-id", m.Id()}},
+			{{"move", moving}, {"_id", bson.D{{"$regex", isChild}}}, {"host-id", bson.D{{"$exists", false}}}},
+		}}},
+		out: make(chan []string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *containerMovesWatcher) Changes() <-chan []string {
+	return w.out
+}
+
+type containerMovePhaseDoc struct {
+	DocID string `bson:"_id"`
+	Move  struct {
+		Phase string `bson:"phase"`
+	} `bson:"move"`
+}
+
+// phases returns the move phases of the watched containers, restricted
+// to those with the given document ids if any are given.
+func (w *containerMovesWatcher) phases(machines mongo.Collection, docIDs []string) (map[string]string, error) {
+	query := w.members
+	if docIDs != nil {
+		query = append(bson.D{{"_id", bson.D{{"$in", docIDs}}}}, w.members...)
+	}
+	phases := make(map[string]string)
+	var doc containerMovePhaseDoc
+	iter := machines.Find(query).Select(bson.D{{"move.phase", 1}}).Iter()
+	for iter.Next(&doc) {
+		phases[w.backend.localID(doc.DocID)] = doc.Move.Phase
+	}
+	return phases, errors.Trace(iter.Close())
+}
+
+func (w *containerMovesWatcher) loop() error {
+	in := make(chan watcher.Change)
+	w.watcher.WatchCollectionWithFilter(machinesC, in, isLocalContainerID(w.backend))
+	defer w.watcher.UnwatchCollection(machinesC, in)
+
+	machines, closer := w.db.GetCollection(machinesC)
+	defer closer()
+	phases, err := w.phases(machines, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	changes := set.NewStrings()
+	for id := range phases {
+		changes.Add(id)
+	}
+
+	out := w.out
+	for {
+		select {
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case change := <-in:
+			ids, ok := collect(change, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			docIDs := make([]string, 0, len(ids))
+			for id := range ids {
+				docIDs = append(docIDs, id.(string))
+			}
+			latest, err := w.phases(machines, docIDs)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			for _, docID := range docIDs {
+				id := w.backend.localID(docID)
+				phase, moving := latest[id]
+				oldPhase, known := phases[id]
+				switch {
+				case moving && (!known || phase != oldPhase):
+					phases[id] = phase
+				case !moving && known:
+					delete(phases, id)
+				default:
+					continue
+				}
+				changes.Add(id)
+			}
+			if !changes.IsEmpty() {
+				out = w.out
+			}
+		case out <- changes.SortedValues():
+			changes = set.NewStrings()
+			out = nil
+		}
+	}
+}
+
+// WatchFirewallRules returns a NotifyWatcher that notifies when
+// any of the model's firewall rules are added, changed or removed.
+func (st *State) WatchFirewallRules() NotifyWatcher {
+	return newNotifyCollWatcher(st, firewallRulesC, isLocalID(st))
+}
+
 // actionStatusWatcher is a StringsWatcher that filters notifications
 // to Action Id's that match the ActionReceiver and ActionStatus set
 // provided.
@@ -221,6 +221,20 @@ const (
 	ProvisioningError Status = "provisioning error"
 )
 
+const (
+	// Status values specific to the health of a unit's workload, as
+	// reported by the health checks declared by its charm.
+
+	// Healthy is set when:
+	// All of the unit's health checks passed when last run.
+	Healthy Status = "healthy"
+
+	// Unhealthy is set when:
+	// At least one of the unit's health checks failed when last run.
+	// The human-readable message names the failing checks.
+	Unhealthy Status = "unhealthy"
+)
+
 const (
 	MessageWaitForMachine    = "waiting for machine"
 	MessageInstallingAgent   = "installing agent"
@@ -290,6 +304,37 @@ func ValidWorkloadStatus(status Status) bool {
 	}
 }
 
+// ValidHealthStatus returns true if status has a valid value (that is to say,
+// a value that it's OK to set) for the health of units.
+func ValidHealthStatus(status Status) bool {
+	switch status {
+	case
+		Healthy,
+		Unhealthy,
+		Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidContainerStatus returns true if status has a valid value (that is to
+// say, a value that it's OK to set) for the container running a unit on a
+// CAAS substrate.
+func ValidContainerStatus(status Status) bool {
+	switch status {
+	case
+		Waiting,
+		Running,
+		Error,
+		Terminated,
+		Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
 // WorkloadMatches returns true if the candidate matches status,
 // taking into account that the candidate may be a legacy
 // status value which has been deprecated.
@@ -30,6 +30,7 @@ func (s *providerCommonSuite) TestCommonProvidersExported(c *gc.C) {
 		c.Assert(p, gc.NotNil)
 	}
 	c.Assert(common, jc.SameContents, []storage.ProviderType{
+		provider.KVMProviderType,
 		provider.LoopProviderType,
 		provider.RootfsProviderType,
 		provider.TmpfsProviderType,
@@ -0,0 +1,132 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// KVMProviderType is the provider type of volumes that are
+	// hotplugged into KVM containers by the machine hosting them.
+	KVMProviderType = storage.ProviderType("kvm")
+)
+
+// kvmProvider creates volume sources which hotplug volumes into KVM
+// containers. Only the machine hosting the containers can create and
+// attach the volumes, so volumeSource is nil everywhere else.
+type kvmProvider struct {
+	volumeSource storage.VolumeSource
+}
+
+var _ storage.Provider = (*kvmProvider)(nil)
+
+// NewKVMProvider returns a storage provider for volumes that the given
+// volume source hotplugs into KVM containers.
+func NewKVMProvider(volumeSource storage.VolumeSource) storage.Provider {
+	return &kvmProvider{volumeSource}
+}
+
+// ValidateConfig is defined on the Provider interface.
+func (*kvmProvider) ValidateConfig(*storage.Config) error {
+	// KVM provider has no configuration.
+	return nil
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *kvmProvider) VolumeSource(*storage.Config) (storage.VolumeSource, error) {
+	if p.volumeSource == nil {
+		return nil, errors.NotSupportedf("kvm volumes outside of the host machine")
+	}
+	return p.volumeSource, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (*kvmProvider) FilesystemSource(*storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is defined on the Provider interface.
+func (*kvmProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (*kvmProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*kvmProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is defined on the Provider interface.
+func (*kvmProvider) Releasable() bool {
+	return false
+}
+
+// DefaultPools is defined on the Provider interface.
+func (*kvmProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// MachineStorageProviders returns the storage.ProviderRegistry used by a
+// machine's storage provisioner for the machine's own storage. It contains
+// the common storage providers, except that the KVM provider is not dynamic:
+// volumes hotplugged into a KVM container are left to the machine hosting
+// it.
+func MachineStorageProviders() storage.ProviderRegistry {
+	return storage.ChainedProviderRegistry{
+		nonDynamicProviders{storage.StaticProviderRegistry{
+			map[storage.ProviderType]storage.Provider{
+				KVMProviderType: commonStorageProviders[KVMProviderType],
+			},
+		}},
+		CommonStorageProviders(),
+	}
+}
+
+// KVMHostStorageProviders returns the storage.ProviderRegistry used by the
+// storage provisioner that a machine runs for each of its KVM containers.
+// It contains a KVM provider using the given volume source. The common
+// storage providers are not dynamic, so the container's other storage is
+// left to the container's own storage provisioner.
+func KVMHostStorageProviders(volumeSource storage.VolumeSource) storage.ProviderRegistry {
+	return storage.ChainedProviderRegistry{
+		storage.StaticProviderRegistry{
+			map[storage.ProviderType]storage.Provider{
+				KVMProviderType: NewKVMProvider(volumeSource),
+			},
+		},
+		nonDynamicProviders{CommonStorageProviders()},
+	}
+}
+
+// nonDynamicProviders is a storage.ProviderRegistry whose providers are
+// all reported as not dynamic, so that a storage provisioner using it
+// leaves their storage to be provisioned elsewhere.
+type nonDynamicProviders struct {
+	storage.ProviderRegistry
+}
+
+// StorageProvider implements ProviderRegistry.
+func (r nonDynamicProviders) StorageProvider(t storage.ProviderType) (storage.Provider, error) {
+	p, err := r.ProviderRegistry.StorageProvider(t)
+	if err != nil {
+		return nil, err
+	}
+	return nonDynamicProvider{p}, nil
+}
+
+type nonDynamicProvider struct {
+	storage.Provider
+}
+
+// Dynamic is defined on the Provider interface.
+func (nonDynamicProvider) Dynamic() bool {
+	return false
+}
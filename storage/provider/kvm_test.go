@@ -0,0 +1,65 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&kvmSuite{})
+
+type kvmSuite struct {
+	testing.BaseSuite
+}
+
+func (s *kvmSuite) TestKVMProvider(c *gc.C) {
+	p, err := provider.CommonStorageProviders().StorageProvider(provider.KVMProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+
+	cfg, err := storage.NewConfig("kvm", provider.KVMProviderType, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = p.VolumeSource(cfg)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = p.FilesystemSource(cfg)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *kvmSuite) TestMachineStorageProviders(c *gc.C) {
+	registry := provider.MachineStorageProviders()
+	p, err := registry.StorageProvider(provider.KVMProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.Dynamic(), jc.IsFalse)
+	p, err = registry.StorageProvider(provider.LoopProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+}
+
+func (s *kvmSuite) TestKVMHostStorageProviders(c *gc.C) {
+	source := struct{ storage.VolumeSource }{}
+	registry := provider.KVMHostStorageProviders(source)
+	p, err := registry.StorageProvider(provider.KVMProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+	cfg, err := storage.NewConfig("kvm", provider.KVMProviderType, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeSource, err := p.VolumeSource(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeSource, gc.Equals, source)
+
+	p, err = registry.StorageProvider(provider.LoopProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.Dynamic(), jc.IsFalse)
+	_, err = registry.StorageProvider("ebs")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -21,6 +21,10 @@ const ShortWait = 50 * time.Millisecond
 // test suite
 const LongWait = 10 * time.Second
 
+// SecretsKey is the controller secrets key with which test controllers
+// encrypt charm secrets.
+const SecretsKey = "juju testing secrets key"
+
 // TODO(katco): 2016-08-09: lp:1611427
 var LongAttempt = &utils.AttemptStrategy{
 	Total: LongWait,
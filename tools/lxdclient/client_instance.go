@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"github.com/lxc/lxd"
 	"github.com/lxc/lxd/shared"
 	"github.com/lxc/lxd/shared/api"
 
@@ -42,6 +44,10 @@ type rawInstanceClient interface {
 	ContainerDeviceAdd(container, devname, devtype string, props []string) (*api.Response, error)
 	ContainerDeviceDelete(container, devname string) (*api.Response, error)
 	PushFile(container, path string, gid int, uid int, mode string, buf io.ReadSeeker) error
+	ApplyProfile(container, profile string) (*api.Response, error)
+	ServerStatus() (*api.Server, error)
+	GetMigrationSourceWS(container string, stateful bool, containerOnly bool) (*api.Response, error)
+	MigrateFrom(name string, operation string, certificate string, sourceSecrets map[string]string, architecture string, config map[string]string, devices map[string]map[string]string, profiles []string, baseImage string, ephemeral bool, push bool, sourceClient *lxd.Client, sourceOperation string, containerOnly bool) (*api.Response, error)
 }
 
 type instanceClient struct {
@@ -336,3 +342,127 @@ func (client *instanceClient) RemoveDevice(instanceName, deviceName string) erro
 	}
 	return nil
 }
+
+// ReplaceProfiles removes the old profiles from the named instance and
+// appends the new ones, leaving any other profiles in place.
+func (client *instanceClient) ReplaceProfiles(name string, oldProfiles, newProfiles []string) error {
+	info, err := client.raw.ContainerInfo(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	remove := set.NewStrings(oldProfiles...)
+	var profiles []string
+	for _, profile := range info.Profiles {
+		if !remove.Contains(profile) {
+			profiles = append(profiles, profile)
+		}
+	}
+	existing := set.NewStrings(profiles...)
+	for _, profile := range newProfiles {
+		if !existing.Contains(profile) {
+			profiles = append(profiles, profile)
+			existing.Add(profile)
+		}
+	}
+	resp, err := client.raw.ApplyProfile(name, strings.Join(profiles, ","))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := client.raw.WaitForSuccess(resp.Operation); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// StartInstance starts the named instance.
+func (client *instanceClient) StartInstance(name string) error {
+	return errors.Trace(client.startInstance(InstanceSpec{Name: name}))
+}
+
+// MigrationSource prepares the named instance to be pulled by another
+// LXD server, and returns what that server needs to do so. Unless live
+// is true, the instance is stopped first; otherwise it is migrated
+// with its running state.
+func (client *instanceClient) MigrationSource(name string, live bool) (*container.MigrationSource, error) {
+	status, err := client.raw.ServerStatus()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(status.Environment.Addresses) == 0 {
+		return nil, errors.New(
+			"LXD is not listening on the network: set core.https_address to allow containers to be moved")
+	}
+	info, err := client.raw.ContainerInfo(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !live && info.StatusCode != api.Stopped {
+		resp, err := client.raw.Action(name, shared.Stop, -1, false, false)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := client.raw.WaitForSuccess(resp.Operation); err != nil {
+			return nil, errors.Annotatef(err, "stopping %q", name)
+		}
+	}
+	resp, err := client.raw.GetMigrationSourceWS(name, live, false)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	op, err := resp.MetadataAsOperation()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	secrets := make(map[string]string)
+	for key, value := range op.Metadata {
+		if secret, ok := value.(string); ok {
+			secrets[key] = secret
+		}
+	}
+	return &container.MigrationSource{
+		Addresses:    status.Environment.Addresses,
+		Operation:    resp.Operation,
+		Secrets:      secrets,
+		Certificate:  status.Environment.Certificate,
+		Architecture: info.Architecture,
+		Config:       info.Config,
+		Devices:      info.Devices,
+		Profiles:     info.Profiles,
+	}, nil
+}
+
+// MigrateInstance pulls the instance described by source from the LXD
+// server holding it, trying each of the server's addresses in turn.
+// The new instance is started afterwards if start is true.
+func (client *instanceClient) MigrateInstance(name string, source container.MigrationSource, start bool) error {
+	if len(source.Addresses) == 0 {
+		return errors.NotValidf("migration source without addresses")
+	}
+	var err error
+	for _, addr := range source.Addresses {
+		operation := "https://" + addr + source.Operation
+		if err = client.migrateFrom(name, operation, source); err == nil {
+			break
+		}
+		logger.Debugf("cannot migrate %q from %s: %v", name, addr, err)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "migrating %q", name)
+	}
+	if start {
+		return errors.Trace(client.StartInstance(name))
+	}
+	return nil
+}
+
+func (client *instanceClient) migrateFrom(name, operation string, source container.MigrationSource) error {
+	resp, err := client.raw.MigrateFrom(
+		name, operation, source.Certificate, source.Secrets,
+		source.Architecture, source.Config, source.Devices, source.Profiles,
+		"", false, false, nil, "", false,
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(client.raw.WaitForSuccess(resp.Operation))
+}
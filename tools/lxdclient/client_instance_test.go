@@ -13,6 +13,7 @@ import (
 	lxdapi "github.com/lxc/lxd/shared/api"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/container"
 	"github.com/juju/juju/network"
 	jujutesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/tools/lxdclient"
@@ -256,3 +257,104 @@ func (s *devicesSuite) TestRemoveDeviceAsyncError(c *gc.C) {
 	err := client.RemoveDevice("instance", "device")
 	c.Assert(err, gc.ErrorMatches, "async error")
 }
+
+func (s *devicesSuite) TestReplaceProfiles(c *gc.C) {
+	s.Client.Container = &lxdapi.Container{
+		ContainerPut: lxdapi.ContainerPut{
+			Profiles: []string{"default", "juju-default-app-1", "extra"},
+		},
+	}
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.ReplaceProfiles("instance", []string{"juju-default-app-1"}, []string{"juju-default-app-2", "extra"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"ContainerInfo", []interface{}{"instance"}},
+		{"ApplyProfile", []interface{}{"instance", "default,extra,juju-default-app-2"}},
+		{"WaitForSuccess", []interface{}{""}},
+	})
+}
+
+type migrationSuite struct {
+	lxdclient.BaseSuite
+}
+
+var _ = gc.Suite(&migrationSuite{})
+
+func (s *migrationSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.Client.Server = &lxdapi.Server{
+		Environment: lxdapi.ServerEnvironment{
+			Addresses:   []string{"10.0.0.1:8443", "192.168.1.1:8443"},
+			Certificate: "server-cert",
+		},
+	}
+}
+
+func (s *migrationSuite) TestMigrationSource(c *gc.C) {
+	s.Client.Container = &lxdapi.Container{
+		ContainerPut: lxdapi.ContainerPut{
+			Architecture: "x86_64",
+			Config:       map[string]string{"user.user-data": "data"},
+			Profiles:     []string{"default"},
+		},
+		StatusCode: lxdapi.Running,
+	}
+	s.Client.Response = &lxdapi.Response{
+		Operation: "/1.0/operations/a-b-c",
+		Metadata:  []byte(`{"metadata": {"control": "s1", "fs": "s2"}}`),
+	}
+	client := lxdclient.NewInstanceClient(s.Client)
+	source, err := client.MigrationSource("instance", false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(source, jc.DeepEquals, &container.MigrationSource{
+		Addresses:    []string{"10.0.0.1:8443", "192.168.1.1:8443"},
+		Operation:    "/1.0/operations/a-b-c",
+		Secrets:      map[string]string{"control": "s1", "fs": "s2"},
+		Certificate:  "server-cert",
+		Architecture: "x86_64",
+		Config:       map[string]string{"user.user-data": "data"},
+		Profiles:     []string{"default"},
+	})
+	s.Stub.CheckCallNames(c, "ServerStatus", "ContainerInfo", "Action", "WaitForSuccess", "GetMigrationSourceWS")
+	s.Stub.CheckCall(c, 4, "GetMigrationSourceWS", "instance", false, false)
+}
+
+func (s *migrationSuite) TestMigrationSourceNotListening(c *gc.C) {
+	s.Client.Server = &lxdapi.Server{}
+	client := lxdclient.NewInstanceClient(s.Client)
+	_, err := client.MigrationSource("instance", true)
+	c.Assert(err, gc.ErrorMatches, "LXD is not listening on the network: set core.https_address .*")
+	s.Stub.CheckCallNames(c, "ServerStatus")
+}
+
+func (s *migrationSuite) TestMigrateInstance(c *gc.C) {
+	s.Stub.SetErrors(errors.New("no route to host"))
+	source := container.MigrationSource{
+		Addresses:   []string{"10.0.0.1:8443", "192.168.1.1:8443"},
+		Operation:   "/1.0/operations/a-b-c",
+		Secrets:     map[string]string{"control": "s1"},
+		Certificate: "server-cert",
+	}
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.MigrateInstance("instance", source, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCallNames(c, "MigrateFrom", "MigrateFrom", "WaitForSuccess", "Action", "WaitForSuccess")
+	s.Stub.CheckCall(c, 0, "MigrateFrom", "instance", "https://10.0.0.1:8443/1.0/operations/a-b-c",
+		"server-cert", source.Secrets, "", map[string]string(nil), map[string]map[string]string(nil), []string(nil))
+	s.Stub.CheckCall(c, 1, "MigrateFrom", "instance", "https://192.168.1.1:8443/1.0/operations/a-b-c",
+		"server-cert", source.Secrets, "", map[string]string(nil), map[string]map[string]string(nil), []string(nil))
+}
+
+func (s *migrationSuite) TestMigrateInstanceFails(c *gc.C) {
+	s.Stub.SetErrors(errors.New("no route to host"), nil, errors.New("copy failed"))
+	source := container.MigrationSource{
+		Addresses: []string{"10.0.0.1:8443", "192.168.1.1:8443"},
+		Operation: "/1.0/operations/a-b-c",
+	}
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.MigrateInstance("instance", source, true)
+	c.Assert(err, gc.ErrorMatches, `migrating "instance": copy failed`)
+	s.Stub.CheckCallNames(c, "MigrateFrom", "MigrateFrom", "WaitForSuccess")
+}
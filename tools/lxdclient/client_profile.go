@@ -18,6 +18,7 @@ type rawProfileClient interface {
 	ProfileDelete(profile string) error
 	ProfileDeviceAdd(profile, devname, devtype string, props []string) (*api.Response, error)
 	ProfileConfig(profile string) (*api.Profile, error)
+	PutProfile(name string, profile api.ProfilePut) error
 }
 
 type profileClient struct {
@@ -59,6 +60,32 @@ func (p profileClient) CreateProfile(name string, config map[string]string) erro
 	return nil
 }
 
+// WriteProfile creates the named profile with the given content, or
+// replaces the content of the profile if it already exists.
+func (p profileClient) WriteProfile(name, description string, config map[string]string, devices Devices) error {
+	exists, err := p.HasProfile(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !exists {
+		if err := p.raw.ProfileCreate(name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	put := api.ProfilePut{
+		Config:      config,
+		Description: description,
+		Devices:     make(map[string]map[string]string),
+	}
+	for deviceName, device := range devices {
+		put.Devices[deviceName] = device
+	}
+	if err := p.raw.PutProfile(name, put); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 // HasProfile returns true/false if the profile exists.
 func (p profileClient) HasProfile(name string) (bool, error) {
 	profiles, err := p.raw.ListProfiles()
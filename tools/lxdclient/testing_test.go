@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
+	"github.com/lxc/lxd"
 	"github.com/lxc/lxd/shared"
 	"github.com/lxc/lxd/shared/api"
 	gc "gopkg.in/check.v1"
@@ -49,9 +50,11 @@ type stubClient struct {
 
 	Instance   *api.ContainerState
 	Instances  []api.Container
+	Container  *api.Container
 	ReturnCode int
 	Response   *api.Response
 	Aliases    map[string]string
+	Server     *api.Server
 }
 
 func (s *stubClient) WaitForSuccess(waitURL string) error {
@@ -172,9 +175,20 @@ func (s *stubClient) ContainerInfo(name string) (*api.Container, error) {
 	if err := s.stub.NextErr(); err != nil {
 		return nil, err
 	}
+	if s.Container != nil {
+		return s.Container, nil
+	}
 	return &api.Container{}, nil
 }
 
+func (s *stubClient) ApplyProfile(container, profile string) (*api.Response, error) {
+	s.stub.AddCall("ApplyProfile", container, profile)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return &api.Response{}, nil
+}
+
 func (s *stubClient) PushFile(container, path string, gid int, uid int, mode string, buf io.ReadSeeker) error {
 	s.stub.AddCall("PushFile", container, path, gid, uid, mode, buf)
 	if err := s.stub.NextErr(); err != nil {
@@ -182,3 +196,33 @@ func (s *stubClient) PushFile(container, path string, gid int, uid int, mode str
 	}
 	return nil
 }
+
+func (s *stubClient) ServerStatus() (*api.Server, error) {
+	s.stub.AddCall("ServerStatus")
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	if s.Server != nil {
+		return s.Server, nil
+	}
+	return &api.Server{}, nil
+}
+
+func (s *stubClient) GetMigrationSourceWS(container string, stateful bool, containerOnly bool) (*api.Response, error) {
+	s.stub.AddCall("GetMigrationSourceWS", container, stateful, containerOnly)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	if s.Response != nil {
+		return s.Response, nil
+	}
+	return &api.Response{}, nil
+}
+
+func (s *stubClient) MigrateFrom(name string, operation string, certificate string, sourceSecrets map[string]string, architecture string, config map[string]string, devices map[string]map[string]string, profiles []string, baseImage string, ephemeral bool, push bool, sourceClient *lxd.Client, sourceOperation string, containerOnly bool) (*api.Response, error) {
+	s.stub.AddCall("MigrateFrom", name, operation, certificate, sourceSecrets, architecture, config, devices, profiles)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return &api.Response{}, nil
+}
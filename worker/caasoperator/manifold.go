@@ -0,0 +1,130 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/caasoperator"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldFacade extends Facade with the method needed to
+// set the passwords of the units the operator runs hooks for.
+type ManifoldFacade interface {
+	Facade
+	UnitPasswordSetter
+}
+
+// ManifoldConfig defines a CAAS operator's dependencies.
+type ManifoldConfig struct {
+	AgentName     string
+	APICallerName string
+	ClockName     string
+
+	MachineLockName     string
+	LeadershipGuarantee time.Duration
+
+	NewFacade     func(base.APICaller) ManifoldFacade
+	NewWorker     func(Config) (worker.Worker, error)
+	NewUnitWorker func(UnitWorkerConfig) (worker.Worker, error)
+}
+
+// Validate is called by start to check for bad configuration.
+func (config ManifoldConfig) Validate() error {
+	if config.AgentName == "" {
+		return errors.NotValidf("empty AgentName")
+	}
+	if config.APICallerName == "" {
+		return errors.NotValidf("empty APICallerName")
+	}
+	if config.ClockName == "" {
+		return errors.NotValidf("empty ClockName")
+	}
+	if config.MachineLockName == "" {
+		return errors.NotValidf("empty MachineLockName")
+	}
+	if config.NewFacade == nil {
+		return errors.NotValidf("nil NewFacade")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	if config.NewUnitWorker == nil {
+		return errors.NotValidf("nil NewUnitWorker")
+	}
+	return nil
+}
+
+// start is a StartFunc for a Worker manifold.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var agent agent.Agent
+	if err := context.Get(config.AgentName, &agent); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var apiCaller base.APICaller
+	if err := context.Get(config.APICallerName, &apiCaller); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var clock clock.Clock
+	if err := context.Get(config.ClockName, &clock); err != nil {
+		return nil, errors.Trace(err)
+	}
+	agentConfig := agent.CurrentConfig()
+	tag := agentConfig.Tag()
+	applicationTag, ok := tag.(names.ApplicationTag)
+	if !ok {
+		return nil, errors.Errorf("expected an application tag, got %v", tag)
+	}
+	facade := config.NewFacade(apiCaller)
+	w, err := config.NewWorker(Config{
+		Application: applicationTag.Id(),
+		Facade:      facade,
+		Clock:       clock,
+		StartUnitWorker: func(unitName string) (worker.Worker, error) {
+			return config.NewUnitWorker(UnitWorkerConfig{
+				UnitTag:             names.NewUnitTag(unitName),
+				AgentConfig:         agent.CurrentConfig(),
+				Facade:              facade,
+				Clock:               clock,
+				APIOpen:             api.Open,
+				MachineLockName:     config.MachineLockName,
+				LeadershipGuarantee: config.LeadershipGuarantee,
+			})
+		},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Manifold creates a manifold that runs a CAAS operator.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.AgentName,
+			config.APICallerName,
+			config.ClockName,
+		},
+		Start: config.start,
+	}
+}
+
+// NewFacade returns a CAASOperator facade client
+// using the given API caller.
+func NewFacade(apiCaller base.APICaller) ManifoldFacade {
+	return caasoperator.NewClient(apiCaller)
+}
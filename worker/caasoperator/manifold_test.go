@@ -0,0 +1,171 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/worker/caasoperator"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	testing.Stub
+
+	manifold dependency.Manifold
+	context  dependency.Context
+	agent    *mockAgent
+	facade   *mockFacade
+	clock    *testing.Clock
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+type fakeAPICaller struct {
+	base.APICaller
+}
+
+type fakeWorker struct {
+	worker.Worker
+}
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.ResetCalls()
+	s.agent = &mockAgent{conf: mockAgentConfig{tag: names.NewApplicationTag("gitlab")}}
+	s.facade = newMockFacade()
+	s.clock = testing.NewClock(time.Time{})
+	s.context = dt.StubContext(nil, map[string]interface{}{
+		"agent":      s.agent,
+		"api-caller": &fakeAPICaller{},
+		"clock":      s.clock,
+	})
+	s.manifold = caasoperator.Manifold(s.validConfig())
+}
+
+func (s *ManifoldSuite) validConfig() caasoperator.ManifoldConfig {
+	return caasoperator.ManifoldConfig{
+		AgentName:           "agent",
+		APICallerName:       "api-caller",
+		ClockName:           "clock",
+		MachineLockName:     "machine-lock",
+		LeadershipGuarantee: 30 * time.Second,
+		NewFacade:           s.newFacade,
+		NewWorker:           s.newWorker,
+		NewUnitWorker:       s.newUnitWorker,
+	}
+}
+
+func (s *ManifoldSuite) newFacade(apiCaller base.APICaller) caasoperator.ManifoldFacade {
+	s.MethodCall(s, "NewFacade", apiCaller)
+	return s.facade
+}
+
+func (s *ManifoldSuite) newWorker(config caasoperator.Config) (worker.Worker, error) {
+	s.MethodCall(s, "NewWorker", config)
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return &fakeWorker{}, nil
+}
+
+func (s *ManifoldSuite) newUnitWorker(config caasoperator.UnitWorkerConfig) (worker.Worker, error) {
+	s.MethodCall(s, "NewUnitWorker", config)
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return &fakeWorker{}, nil
+}
+
+func (s *ManifoldSuite) TestMissingAgentName(c *gc.C) {
+	config := s.validConfig()
+	config.AgentName = ""
+	s.checkConfigInvalid(c, config, "empty AgentName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingAPICallerName(c *gc.C) {
+	config := s.validConfig()
+	config.APICallerName = ""
+	s.checkConfigInvalid(c, config, "empty APICallerName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingClockName(c *gc.C) {
+	config := s.validConfig()
+	config.ClockName = ""
+	s.checkConfigInvalid(c, config, "empty ClockName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingMachineLockName(c *gc.C) {
+	config := s.validConfig()
+	config.MachineLockName = ""
+	s.checkConfigInvalid(c, config, "empty MachineLockName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewFacade(c *gc.C) {
+	config := s.validConfig()
+	config.NewFacade = nil
+	s.checkConfigInvalid(c, config, "nil NewFacade not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewWorker(c *gc.C) {
+	config := s.validConfig()
+	config.NewWorker = nil
+	s.checkConfigInvalid(c, config, "nil NewWorker not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewUnitWorker(c *gc.C) {
+	config := s.validConfig()
+	config.NewUnitWorker = nil
+	s.checkConfigInvalid(c, config, "nil NewUnitWorker not valid")
+}
+
+func (s *ManifoldSuite) checkConfigInvalid(c *gc.C, config caasoperator.ManifoldConfig, expect string) {
+	err := config.Validate()
+	c.Check(err, gc.ErrorMatches, expect)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	c.Assert(s.manifold.Inputs, jc.SameContents, []string{"agent", "api-caller", "clock"})
+}
+
+func (s *ManifoldSuite) TestStart(c *gc.C) {
+	w, err := s.manifold.Start(s.context)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w, gc.FitsTypeOf, &fakeWorker{})
+
+	s.CheckCallNames(c, "NewFacade", "NewWorker")
+	config := s.Calls()[1].Args[0].(caasoperator.Config)
+	c.Assert(config.Application, gc.Equals, "gitlab")
+	c.Assert(config.Facade, gc.Equals, s.facade)
+	c.Assert(config.Clock, gc.Equals, s.clock)
+
+	_, err = config.StartUnitWorker("gitlab/0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.CheckCallNames(c, "NewFacade", "NewWorker", "NewUnitWorker")
+	unitConfig := s.Calls()[2].Args[0].(caasoperator.UnitWorkerConfig)
+	c.Assert(unitConfig.UnitTag, gc.Equals, names.NewUnitTag("gitlab/0"))
+	c.Assert(unitConfig.Facade, gc.Equals, s.facade)
+	c.Assert(unitConfig.Clock, gc.Equals, s.clock)
+	c.Assert(unitConfig.MachineLockName, gc.Equals, "machine-lock")
+	c.Assert(unitConfig.LeadershipGuarantee, gc.Equals, 30*time.Second)
+	c.Assert(unitConfig.APIOpen, gc.NotNil)
+}
+
+func (s *ManifoldSuite) TestStartNotApplicationAgent(c *gc.C) {
+	s.agent.conf.tag = names.NewUnitTag("gitlab/0")
+	w, err := s.manifold.Start(s.context)
+	c.Assert(err, gc.ErrorMatches, `expected an application tag, got unit-gitlab-0`)
+	c.Assert(w, gc.IsNil)
+}
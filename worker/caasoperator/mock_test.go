@@ -0,0 +1,134 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator_test
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/watcher"
+)
+
+type mockFacade struct {
+	testing.Stub
+
+	mu           sync.Mutex
+	life         map[string]params.Life
+	unitsWatcher *mockStringsWatcher
+}
+
+func newMockFacade() *mockFacade {
+	return &mockFacade{
+		life:         make(map[string]params.Life),
+		unitsWatcher: newMockStringsWatcher(),
+	}
+}
+
+func (f *mockFacade) setLife(entityName string, life params.Life) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if life == "" {
+		delete(f.life, entityName)
+	} else {
+		f.life[entityName] = life
+	}
+}
+
+func (f *mockFacade) WatchUnits(application string) (watcher.StringsWatcher, error) {
+	f.MethodCall(f, "WatchUnits", application)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.unitsWatcher, nil
+}
+
+func (f *mockFacade) Life(entityName string) (params.Life, error) {
+	f.MethodCall(f, "Life", entityName)
+	if err := f.NextErr(); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	life, ok := f.life[entityName]
+	if !ok {
+		return "", errors.NotFoundf("%v", entityName)
+	}
+	return life, nil
+}
+
+func (f *mockFacade) SetUnitPassword(unitName, password string) error {
+	f.MethodCall(f, "SetUnitPassword", unitName, password)
+	return f.NextErr()
+}
+
+type mockWatcher struct {
+	testing.Stub
+	tomb.Tomb
+}
+
+func (w *mockWatcher) doneWhenDying() {
+	<-w.Tomb.Dying()
+	w.Tomb.Done()
+}
+
+func (w *mockWatcher) Kill() {
+	w.MethodCall(w, "Kill")
+	w.Tomb.Kill(nil)
+}
+
+func (w *mockWatcher) Wait() error {
+	w.MethodCall(w, "Wait")
+	return w.Tomb.Wait()
+}
+
+type mockStringsWatcher struct {
+	mockWatcher
+	changes chan []string
+}
+
+func newMockStringsWatcher() *mockStringsWatcher {
+	w := &mockStringsWatcher{changes: make(chan []string, 5)}
+	go w.doneWhenDying()
+	return w
+}
+
+func (w *mockStringsWatcher) Changes() watcher.StringsChannel {
+	return w.changes
+}
+
+// mockUnitWorker stands in for the worker that runs a unit's hooks.
+type mockUnitWorker struct {
+	mockWatcher
+	unitName string
+}
+
+func newMockUnitWorker(unitName string) *mockUnitWorker {
+	w := &mockUnitWorker{unitName: unitName}
+	go w.doneWhenDying()
+	return w
+}
+
+type mockAgent struct {
+	agent.Agent
+	conf mockAgentConfig
+}
+
+func (a *mockAgent) CurrentConfig() agent.Config {
+	return &a.conf
+}
+
+type mockAgentConfig struct {
+	agent.Config
+	tag names.Tag
+}
+
+func (c *mockAgentConfig) Tag() names.Tag {
+	return c.tag
+}
@@ -0,0 +1,202 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/arch"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/series"
+	"github.com/juju/version"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/api"
+	apileadership "github.com/juju/juju/api/leadership"
+	apiretrystrategy "github.com/juju/juju/api/retrystrategy"
+	apiuniter "github.com/juju/juju/api/uniter"
+	jujuversion "github.com/juju/juju/version"
+	jworker "github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/catacomb"
+	"github.com/juju/juju/worker/fortress"
+	"github.com/juju/juju/worker/leadership"
+	"github.com/juju/juju/worker/uniter"
+	"github.com/juju/juju/worker/uniter/operation"
+)
+
+// UnitPasswordSetter provides the method the unit worker
+// uses to set the password for the unit's API connection.
+type UnitPasswordSetter interface {
+	SetUnitPassword(unitName, password string) error
+}
+
+// UnitWorkerConfig holds configuration for a worker that
+// runs the hooks of a single unit of a CAAS application.
+type UnitWorkerConfig struct {
+	UnitTag             names.UnitTag
+	AgentConfig         agent.Config
+	Facade              UnitPasswordSetter
+	Clock               clock.Clock
+	APIOpen             api.OpenFunc
+	MachineLockName     string
+	LeadershipGuarantee time.Duration
+}
+
+// Validate validates the unit worker configuration.
+func (config UnitWorkerConfig) Validate() error {
+	if config.AgentConfig == nil {
+		return errors.NotValidf("missing AgentConfig")
+	}
+	if config.Facade == nil {
+		return errors.NotValidf("missing Facade")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("missing Clock")
+	}
+	if config.APIOpen == nil {
+		return errors.NotValidf("missing APIOpen")
+	}
+	if config.MachineLockName == "" {
+		return errors.NotValidf("missing MachineLockName")
+	}
+	return nil
+}
+
+// NewUnitWorker connects to the API as the configured unit, using
+// a freshly generated password, and starts a uniter that runs the
+// unit's hooks over that connection.
+func NewUnitWorker(config UnitWorkerConfig) (_ worker.Worker, err error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	unitTag := config.UnitTag
+	agentConfig := config.AgentConfig
+
+	// Link the operator's tools into the unit's tools directory,
+	// so the uniter can install the hook tool symlinks there.
+	hostSeries, err := series.HostSeries()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	current := version.Binary{
+		Number: jujuversion.Current,
+		Arch:   arch.HostArch(),
+		Series: hostSeries,
+	}
+	if _, err := tools.ChangeAgentTools(agentConfig.DataDir(), unitTag.String(), current); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// The operator never records unit passwords; it sets a new
+	// one each time it starts a unit's worker.
+	password, err := utils.RandomPassword()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := config.Facade.SetUnitPassword(unitTag.Id(), password); err != nil {
+		return nil, errors.Annotatef(err, "setting password for %s", names.ReadableString(unitTag))
+	}
+	info, ok := agentConfig.APIInfo()
+	if !ok {
+		return nil, errors.New("API info not available")
+	}
+	info.Tag = unitTag
+	info.Password = password
+	conn, err := config.APIOpen(info, api.DefaultDialOpts())
+	if err != nil {
+		return nil, errors.Annotatef(err, "connecting as %s", names.ReadableString(unitTag))
+	}
+	defer func() {
+		if err != nil {
+			conn.Close()
+		}
+	}()
+
+	hookRetryStrategy, err := apiretrystrategy.NewClient(conn).RetryStrategy(unitTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tracker := leadership.NewTracker(
+		unitTag, apileadership.NewClient(conn), config.Clock, config.LeadershipGuarantee,
+	)
+	uniterWorker, err := uniter.NewUniter(&uniter.UniterParams{
+		UniterFacade:         apiuniter.NewState(conn, unitTag),
+		UnitTag:              unitTag,
+		LeadershipTracker:    tracker,
+		DataDir:              agentConfig.DataDir(),
+		Downloader:           api.NewCharmDownloader(conn.Client()),
+		MachineLockName:      config.MachineLockName,
+		CharmDirGuard:        charmDirGuard{},
+		UpdateStatusSignal:   uniter.NewUpdateStatusTimer(),
+		HookRetryStrategy:    hookRetryStrategy,
+		NewOperationExecutor: operation.NewExecutor,
+		TranslateResolverErr: uniter.TranslateFortressErrors,
+		Clock:                config.Clock,
+	})
+	if err != nil {
+		worker.Stop(tracker)
+		return nil, errors.Trace(err)
+	}
+
+	w := &unitWorker{conn: conn}
+	err = catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+		Init: []worker.Worker{tracker, uniterWorker},
+	})
+	return w, errors.Trace(err)
+}
+
+// unitWorker runs a unit's uniter and the workers it
+// depends on, and owns the unit's API connection.
+type unitWorker struct {
+	catacomb catacomb.Catacomb
+	conn     api.Connection
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *unitWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *unitWorker) Wait() error {
+	err := w.catacomb.Wait()
+	if err == jworker.ErrTerminateAgent {
+		// The uniter stops with ErrTerminateAgent once the unit
+		// is dead; that must not terminate the operator itself.
+		err = nil
+	}
+	return err
+}
+
+func (w *unitWorker) loop() error {
+	defer w.conn.Close()
+	select {
+	case <-w.catacomb.Dying():
+		return w.catacomb.ErrDying()
+	case <-w.conn.Broken():
+		return errors.New("API connection broken")
+	}
+}
+
+// charmDirGuard is the fortress.Guard given to the uniter. The
+// operator runs no other workers that read the unit's charm
+// directory, so there are no visitors to lock out.
+type charmDirGuard struct{}
+
+// Unlock is part of the fortress.Guard interface.
+func (charmDirGuard) Unlock() error {
+	return nil
+}
+
+// Lockdown is part of the fortress.Guard interface.
+func (charmDirGuard) Lockdown(fortress.Abort) error {
+	return nil
+}
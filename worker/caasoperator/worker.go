@@ -0,0 +1,162 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.workers.caasoperator")
+
+// Facade provides the methods of the CAASOperator facade
+// required by the worker.
+type Facade interface {
+	WatchUnits(application string) (watcher.StringsWatcher, error)
+	Life(entityName string) (params.Life, error)
+}
+
+// Config holds configuration for the CAAS operator worker.
+type Config struct {
+	// Application is the name of the application whose
+	// units the operator runs hooks for.
+	Application string
+
+	Facade Facade
+	Clock  clock.Clock
+
+	// StartUnitWorker starts the worker that runs the hooks
+	// of the named unit.
+	StartUnitWorker func(unitName string) (worker.Worker, error)
+}
+
+// Validate validates the worker configuration.
+func (config Config) Validate() error {
+	if config.Application == "" {
+		return errors.NotValidf("missing Application")
+	}
+	if config.Facade == nil {
+		return errors.NotValidf("missing Facade")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("missing Clock")
+	}
+	if config.StartUnitWorker == nil {
+		return errors.NotValidf("missing StartUnitWorker")
+	}
+	return nil
+}
+
+// NewWorker starts and returns a new CAAS operator worker, which
+// runs a unit worker for each of the application's units that is
+// not yet dead.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	op := &operator{
+		config: config,
+		runner: worker.NewRunner(worker.RunnerParams{
+			Clock: config.Clock,
+
+			// One unit's worker failing should not prevent
+			// the others from running.
+			IsFatal: func(error) bool { return false },
+
+			// For any failures, try again in 3 seconds.
+			RestartDelay: 3 * time.Second,
+		}),
+		unitWorkers: make(map[string]bool),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &op.catacomb,
+		Work: op.loop,
+		Init: []worker.Worker{op.runner},
+	})
+	return op, errors.Trace(err)
+}
+
+type operator struct {
+	catacomb catacomb.Catacomb
+	config   Config
+	runner   *worker.Runner
+
+	// unitWorkers records the units for which
+	// a worker has been started.
+	unitWorkers map[string]bool
+}
+
+// Kill is part of the worker.Worker interface.
+func (op *operator) Kill() {
+	op.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (op *operator) Wait() error {
+	return op.catacomb.Wait()
+}
+
+func (op *operator) loop() error {
+	w, err := op.config.Facade.WatchUnits(op.config.Application)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := op.catacomb.Add(w); err != nil {
+		return errors.Trace(err)
+	}
+
+	for {
+		select {
+		case <-op.catacomb.Dying():
+			return op.catacomb.ErrDying()
+		case units, ok := <-w.Changes():
+			if !ok {
+				return errors.New("unit watcher closed channel")
+			}
+			for _, unitName := range units {
+				if err := op.unitChanged(unitName); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+	}
+}
+
+func (op *operator) unitChanged(unitName string) error {
+	life, err := op.config.Facade.Life(unitName)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if errors.IsNotFound(err) || life == params.Dead {
+		if !op.unitWorkers[unitName] {
+			return nil
+		}
+		logger.Debugf("stopping worker for unit %v", unitName)
+		if err := op.runner.StopWorker(unitName); err != nil {
+			return errors.Trace(err)
+		}
+		delete(op.unitWorkers, unitName)
+		return nil
+	}
+	if op.unitWorkers[unitName] {
+		return nil
+	}
+	logger.Debugf("starting worker for unit %v", unitName)
+	err = op.runner.StartWorker(unitName, func() (worker.Worker, error) {
+		return op.config.StartUnitWorker(unitName)
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	op.unitWorkers[unitName] = true
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperator_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/caasoperator"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	config  caasoperator.Config
+	facade  *mockFacade
+	clock   *testing.Clock
+	started chan *mockUnitWorker
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.facade = newMockFacade()
+	s.facade.setLife("gitlab/0", params.Alive)
+	s.facade.setLife("gitlab/1", params.Alive)
+	s.clock = testing.NewClock(time.Time{})
+	s.started = make(chan *mockUnitWorker, 5)
+	s.config = caasoperator.Config{
+		Application:     "gitlab",
+		Facade:          s.facade,
+		Clock:           s.clock,
+		StartUnitWorker: s.startUnitWorker,
+	}
+}
+
+func (s *WorkerSuite) startUnitWorker(unitName string) (worker.Worker, error) {
+	w := newMockUnitWorker(unitName)
+	s.started <- w
+	return w, nil
+}
+
+func (s *WorkerSuite) TestValidateConfig(c *gc.C) {
+	s.testValidateConfig(c, func(config *caasoperator.Config) {
+		config.Application = ""
+	}, `missing Application not valid`)
+
+	s.testValidateConfig(c, func(config *caasoperator.Config) {
+		config.Facade = nil
+	}, `missing Facade not valid`)
+
+	s.testValidateConfig(c, func(config *caasoperator.Config) {
+		config.Clock = nil
+	}, `missing Clock not valid`)
+
+	s.testValidateConfig(c, func(config *caasoperator.Config) {
+		config.StartUnitWorker = nil
+	}, `missing StartUnitWorker not valid`)
+}
+
+func (s *WorkerSuite) testValidateConfig(c *gc.C, f func(*caasoperator.Config), expect string) {
+	config := s.config
+	f(&config)
+	w, err := caasoperator.NewWorker(config)
+	if err == nil {
+		workertest.DirtyKill(c, w)
+	}
+	c.Check(err, gc.ErrorMatches, expect)
+}
+
+func (s *WorkerSuite) TestStartStop(c *gc.C) {
+	w, err := caasoperator.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CheckAlive(c, w)
+	workertest.CleanKill(c, w)
+	s.facade.CheckCall(c, 0, "WatchUnits", "gitlab")
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := caasoperator.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, w) })
+	return w
+}
+
+func (s *WorkerSuite) assertStarted(c *gc.C, unitName string) (w *mockUnitWorker) {
+	select {
+	case w = <-s.started:
+		c.Assert(w.unitName, gc.Equals, unitName)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for worker for %v to start", unitName)
+	}
+	return w
+}
+
+func (s *WorkerSuite) assertNotStarted(c *gc.C) {
+	select {
+	case w := <-s.started:
+		c.Fatalf("unexpected worker started for %v", w.unitName)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestUnitWorkersStarted(c *gc.C) {
+	s.facade.unitsWatcher.changes <- []string{"gitlab/0", "gitlab/1", "gitlab/2"}
+	s.startWorker(c)
+
+	started := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case w := <-s.started:
+			started[w.unitName] = true
+		case <-time.After(coretesting.LongWait):
+			c.Fatal("timed out waiting for unit workers to start")
+		}
+	}
+	c.Assert(started, jc.DeepEquals, map[string]bool{"gitlab/0": true, "gitlab/1": true})
+	s.assertNotStarted(c)
+
+	// A change to a unit that already has a worker
+	// does not start another.
+	s.facade.unitsWatcher.changes <- []string{"gitlab/0"}
+	s.assertNotStarted(c)
+}
+
+func (s *WorkerSuite) TestUnitWorkerStoppedWhenDead(c *gc.C) {
+	s.facade.unitsWatcher.changes <- []string{"gitlab/0"}
+	s.startWorker(c)
+	unitWorker := s.assertStarted(c, "gitlab/0")
+
+	s.facade.setLife("gitlab/0", params.Dead)
+	s.facade.unitsWatcher.changes <- []string{"gitlab/0"}
+	workertest.CheckKilled(c, unitWorker)
+}
+
+func (s *WorkerSuite) TestUnitWorkerStoppedWhenRemoved(c *gc.C) {
+	s.facade.unitsWatcher.changes <- []string{"gitlab/0"}
+	s.startWorker(c)
+	unitWorker := s.assertStarted(c, "gitlab/0")
+
+	s.facade.setLife("gitlab/0", "")
+	s.facade.unitsWatcher.changes <- []string{"gitlab/0"}
+	workertest.CheckKilled(c, unitWorker)
+}
+
+func (s *WorkerSuite) TestWatchUnitsError(c *gc.C) {
+	s.facade.SetErrors(errors.New("splat"))
+	w, err := caasoperator.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "splat")
+}
@@ -0,0 +1,286 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+// statusPollInterval is how often the status of an application's
+// pods is read back from the broker.
+const statusPollInterval = 30 * time.Second
+
+// applicationWorker keeps the service for a single application in
+// step with its units and pod spec, and reports the status of the
+// application's pods back to its units.
+type applicationWorker struct {
+	catacomb    catacomb.Catacomb
+	application string
+	facade      Facade
+	broker      ServiceBroker
+	clock       clock.Clock
+
+	// aliveUnits holds the names of the application's units
+	// that are alive.
+	aliveUnits set.Strings
+
+	// reported holds the container status last reported
+	// for each unit.
+	reported map[string]status.StatusInfo
+
+	// reportedAddresses holds the container address last
+	// reported for each unit.
+	reportedAddresses map[string]string
+}
+
+func newApplicationWorker(
+	application string,
+	facade Facade,
+	broker ServiceBroker,
+	clock clock.Clock,
+) (*applicationWorker, error) {
+	w := &applicationWorker{
+		application: application,
+		facade:      facade,
+		broker:      broker,
+		clock:       clock,
+		aliveUnits:  set.NewStrings(),
+		reported:    make(map[string]status.StatusInfo),
+
+		reportedAddresses: make(map[string]string),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	return w, err
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *applicationWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *applicationWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *applicationWorker) loop() error {
+	unitsWatcher, err := w.facade.WatchUnits(w.application)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(unitsWatcher); err != nil {
+		return errors.Trace(err)
+	}
+	specWatcher, err := w.facade.WatchPodSpec(w.application)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(specWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	// The service is not ensured until both watchers have
+	// delivered their initial events.
+	var gotUnits, gotSpec bool
+	var statusTimer <-chan time.Time
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case units, ok := <-unitsWatcher.Changes():
+			if !ok {
+				return errors.New("units watcher closed channel")
+			}
+			if err := w.unitsChanged(units); err != nil {
+				return errors.Trace(err)
+			}
+			gotUnits = true
+		case _, ok := <-specWatcher.Changes():
+			if !ok {
+				return errors.New("pod spec watcher closed channel")
+			}
+			gotSpec = true
+		case <-statusTimer:
+			if err := w.updateStatus(); err != nil {
+				return errors.Trace(err)
+			}
+			statusTimer = w.clock.After(statusPollInterval)
+			continue
+		}
+		if !gotUnits || !gotSpec {
+			continue
+		}
+		if err := w.ensureService(); err != nil {
+			return errors.Trace(err)
+		}
+		if statusTimer == nil {
+			statusTimer = w.clock.After(statusPollInterval)
+		}
+	}
+}
+
+func (w *applicationWorker) unitsChanged(units []string) error {
+	for _, unitName := range units {
+		life, err := w.facade.Life(unitName)
+		if err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+		if errors.IsNotFound(err) || life != params.Alive {
+			w.aliveUnits.Remove(unitName)
+			delete(w.reported, unitName)
+			delete(w.reportedAddresses, unitName)
+			continue
+		}
+		w.aliveUnits.Add(unitName)
+	}
+	return nil
+}
+
+func (w *applicationWorker) ensureService() error {
+	info, err := w.facade.ProvisioningInfo(w.application)
+	if errors.IsNotFound(err) {
+		logger.Debugf("no pod spec set yet for %v, not creating service", w.application)
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	spec, err := caas.ParsePodSpec(info.PodSpec)
+	if err != nil {
+		// The charm will need to set a new spec; there's
+		// nothing to be gained by restarting the worker.
+		logger.Errorf("invalid pod spec for %v: %v", w.application, err)
+		return nil
+	}
+	serviceParams := &caas.ServiceParams{PodSpec: spec}
+	for _, fs := range info.Filesystems {
+		serviceParams.Filesystems = append(serviceParams.Filesystems, caas.FilesystemParams{
+			StorageName: fs.StorageName,
+			Size:        fs.Size,
+			Location:    fs.Location,
+		})
+	}
+	numUnits := w.aliveUnits.Size()
+	logger.Debugf("ensuring service for %v with %d units", w.application, numUnits)
+	if err := w.broker.EnsureService(w.application, serviceParams, numUnits); err != nil {
+		return errors.Annotatef(err, "ensuring service for %v", w.application)
+	}
+	return nil
+}
+
+// updateStatus reads the status and addresses of the application's
+// pods from the broker, and reports any changes to the units.
+func (w *applicationWorker) updateStatus() error {
+	pods, err := w.broker.Units(w.application)
+	if err != nil {
+		return errors.Annotatef(err, "getting pods for %v", w.application)
+	}
+	unitPods, err := w.assignPods(pods)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	changed := make(map[string]status.StatusInfo)
+	changedAddresses := make(map[string]string)
+	for _, unitName := range w.aliveUnits.Values() {
+		info := status.StatusInfo{
+			Status:  status.Waiting,
+			Message: "waiting for container",
+		}
+		var address string
+		if pod, ok := unitPods[unitName]; ok {
+			info = pod.Status
+			address = pod.Address
+		}
+		if address != "" && address != w.reportedAddresses[unitName] {
+			changedAddresses[unitName] = address
+		}
+		if last, ok := w.reported[unitName]; ok && last.Status == info.Status && last.Message == info.Message {
+			continue
+		}
+		changed[unitName] = info
+	}
+	if len(changed) > 0 {
+		if err := w.facade.SetContainerStatuses(changed); err != nil {
+			return errors.Annotatef(err, "setting container status for %v", w.application)
+		}
+		for unitName, info := range changed {
+			w.reported[unitName] = info
+		}
+	}
+	if len(changedAddresses) > 0 {
+		if err := w.facade.SetContainerAddresses(changedAddresses); err != nil {
+			return errors.Annotatef(err, "setting container addresses for %v", w.application)
+		}
+		for unitName, address := range changedAddresses {
+			w.reportedAddresses[unitName] = address
+		}
+	}
+	return nil
+}
+
+// assignPods returns the pods assigned to each alive unit. Pods are
+// assigned to units through the broker, so that a unit keeps its pod
+// for as long as the pod exists. Units without a pod are assigned, in
+// unit number order, the oldest pods not assigned to an alive unit;
+// these include the pods of units that have since been removed, which
+// the orchestrator may keep when scaling down in place of another.
+func (w *applicationWorker) assignPods(pods []caas.Unit) (map[string]caas.Unit, error) {
+	unitPods := make(map[string]caas.Unit)
+	var unassigned []caas.Unit
+	for _, pod := range pods {
+		if _, ok := unitPods[pod.UnitName]; !ok && w.aliveUnits.Contains(pod.UnitName) {
+			unitPods[pod.UnitName] = pod
+			continue
+		}
+		unassigned = append(unassigned, pod)
+	}
+	for _, unitName := range sortedUnitNames(w.aliveUnits.Values()) {
+		if _, ok := unitPods[unitName]; ok {
+			continue
+		}
+		for len(unassigned) > 0 {
+			pod := unassigned[0]
+			unassigned = unassigned[1:]
+			err := w.broker.AssignUnit(w.application, pod.Id, unitName)
+			if errors.IsNotFound(err) {
+				// The pod has gone away; try the next one.
+				continue
+			} else if err != nil {
+				return nil, errors.Annotatef(err, "assigning pod to %v", unitName)
+			}
+			logger.Debugf("assigned pod %v to %v", pod.Id, unitName)
+			pod.UnitName = unitName
+			unitPods[unitName] = pod
+			break
+		}
+	}
+	return unitPods, nil
+}
+
+// sortedUnitNames returns the unit names sorted by unit number.
+func sortedUnitNames(unitNames []string) []string {
+	sort.Slice(unitNames, func(i, j int) bool {
+		return unitNumber(unitNames[i]) < unitNumber(unitNames[j])
+	})
+	return unitNames
+}
+
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.LastIndex(unitName, "/")+1:])
+	return n
+}
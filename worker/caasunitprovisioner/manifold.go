@@ -0,0 +1,127 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/caasunitprovisioner"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/caas/kubernetes/provider"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldFacade extends Facade with the methods needed by the
+// manifold to connect to the model's CAAS substrate.
+type ManifoldFacade interface {
+	Facade
+	CloudSpec() (environs.CloudSpec, error)
+	ModelConfig() (*config.Config, error)
+}
+
+// ManifoldConfig defines a CAAS unit provisioner's dependencies.
+type ManifoldConfig struct {
+	APICallerName string
+	ClockName     string
+
+	NewFacade func(base.APICaller) (ManifoldFacade, error)
+	NewBroker func(spec environs.CloudSpec, namespace string) (caas.Broker, error)
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+// Validate is called by start to check for bad configuration.
+func (config ManifoldConfig) Validate() error {
+	if config.APICallerName == "" {
+		return errors.NotValidf("empty APICallerName")
+	}
+	if config.ClockName == "" {
+		return errors.NotValidf("empty ClockName")
+	}
+	if config.NewFacade == nil {
+		return errors.NotValidf("nil NewFacade")
+	}
+	if config.NewBroker == nil {
+		return errors.NotValidf("nil NewBroker")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// start is a StartFunc for a Worker manifold.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var apiCaller base.APICaller
+	if err := context.Get(config.APICallerName, &apiCaller); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var clock clock.Clock
+	if err := context.Get(config.ClockName, &clock); err != nil {
+		return nil, errors.Trace(err)
+	}
+	facade, err := config.NewFacade(apiCaller)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cloudSpec, err := facade.CloudSpec()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if cloudSpec.Type != provider.CloudType {
+		// Only models on a Kubernetes cloud have
+		// units to provision as pods.
+		return nil, dependency.ErrUninstall
+	}
+	modelConfig, err := facade.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Each model's resources live in a namespace named
+	// after the model.
+	broker, err := config.NewBroker(cloudSpec, modelConfig.Name())
+	if err != nil {
+		return nil, errors.Annotate(err, "creating CAAS broker")
+	}
+	if err := broker.EnsureNamespace(); err != nil {
+		return nil, errors.Annotate(err, "creating namespace")
+	}
+	w, err := config.NewWorker(Config{
+		Facade: facade,
+		Broker: broker,
+		Clock:  clock,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Manifold creates a manifold that runs a CAAS unit provisioner.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.APICallerName,
+			config.ClockName,
+		},
+		Start: config.start,
+	}
+}
+
+// NewFacade returns a CAASUnitProvisioner facade client
+// using the given API caller.
+func NewFacade(apiCaller base.APICaller) (ManifoldFacade, error) {
+	facade, err := caasunitprovisioner.NewClient(apiCaller)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return facade, nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/worker/caasunitprovisioner"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	testing.Stub
+
+	manifold dependency.Manifold
+	context  dependency.Context
+	facade   *mockFacade
+	broker   *mockBroker
+	clock    *testing.Clock
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+type fakeAPICaller struct {
+	base.APICaller
+}
+
+type fakeWorker struct {
+	worker.Worker
+}
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.ResetCalls()
+	s.facade = newMockFacade()
+	s.broker = newMockBroker()
+	s.clock = testing.NewClock(time.Time{})
+	s.context = dt.StubContext(nil, map[string]interface{}{
+		"api-caller": &fakeAPICaller{},
+		"clock":      s.clock,
+	})
+	s.manifold = caasunitprovisioner.Manifold(s.validConfig())
+}
+
+func (s *ManifoldSuite) validConfig() caasunitprovisioner.ManifoldConfig {
+	return caasunitprovisioner.ManifoldConfig{
+		APICallerName: "api-caller",
+		ClockName:     "clock",
+		NewFacade:     s.newFacade,
+		NewBroker:     s.newBroker,
+		NewWorker:     s.newWorker,
+	}
+}
+
+func (s *ManifoldSuite) newFacade(apiCaller base.APICaller) (caasunitprovisioner.ManifoldFacade, error) {
+	s.MethodCall(s, "NewFacade", apiCaller)
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return s.facade, nil
+}
+
+func (s *ManifoldSuite) newBroker(spec environs.CloudSpec, namespace string) (caas.Broker, error) {
+	s.MethodCall(s, "NewBroker", spec, namespace)
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return s.broker, nil
+}
+
+func (s *ManifoldSuite) newWorker(config caasunitprovisioner.Config) (worker.Worker, error) {
+	s.MethodCall(s, "NewWorker", config)
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return &fakeWorker{}, nil
+}
+
+func (s *ManifoldSuite) TestMissingAPICallerName(c *gc.C) {
+	config := s.validConfig()
+	config.APICallerName = ""
+	s.checkConfigInvalid(c, config, "empty APICallerName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingClockName(c *gc.C) {
+	config := s.validConfig()
+	config.ClockName = ""
+	s.checkConfigInvalid(c, config, "empty ClockName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewFacade(c *gc.C) {
+	config := s.validConfig()
+	config.NewFacade = nil
+	s.checkConfigInvalid(c, config, "nil NewFacade not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewBroker(c *gc.C) {
+	config := s.validConfig()
+	config.NewBroker = nil
+	s.checkConfigInvalid(c, config, "nil NewBroker not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewWorker(c *gc.C) {
+	config := s.validConfig()
+	config.NewWorker = nil
+	s.checkConfigInvalid(c, config, "nil NewWorker not valid")
+}
+
+func (s *ManifoldSuite) checkConfigInvalid(c *gc.C, config caasunitprovisioner.ManifoldConfig, expect string) {
+	err := config.Validate()
+	c.Check(err, gc.ErrorMatches, expect)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	c.Assert(s.manifold.Inputs, jc.SameContents, []string{"api-caller", "clock"})
+}
+
+func (s *ManifoldSuite) TestStart(c *gc.C) {
+	w, err := s.manifold.Start(s.context)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w, gc.FitsTypeOf, &fakeWorker{})
+
+	s.CheckCallNames(c, "NewFacade", "NewBroker", "NewWorker")
+	args := s.Calls()[1].Args
+	c.Assert(args[0], jc.DeepEquals, environs.CloudSpec{Type: "kubernetes", Name: "k8s"})
+	c.Assert(args[1], gc.Equals, "testenv")
+	s.broker.CheckCallNames(c, "EnsureNamespace")
+
+	config := s.Calls()[2].Args[0].(caasunitprovisioner.Config)
+	c.Assert(config.Facade, gc.Equals, s.facade)
+	c.Assert(config.Broker, gc.Equals, s.broker)
+	c.Assert(config.Clock, gc.Equals, s.clock)
+}
+
+func (s *ManifoldSuite) TestStartNotKubernetes(c *gc.C) {
+	s.facade.cloudType = "ec2"
+	w, err := s.manifold.Start(s.context)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+	c.Assert(w, gc.IsNil)
+	s.CheckCallNames(c, "NewFacade")
+}
+
+func (s *ManifoldSuite) TestStartBrokerError(c *gc.C) {
+	s.SetErrors(nil, errors.New("boom"))
+	w, err := s.manifold.Start(s.context)
+	c.Assert(err, gc.ErrorMatches, "creating CAAS broker: boom")
+	c.Assert(w, gc.IsNil)
+}
@@ -0,0 +1,264 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner_test
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+)
+
+type mockFacade struct {
+	testing.Stub
+
+	mu               sync.Mutex
+	cloudType        string
+	life             map[string]params.Life
+	provisioningInfo *params.CAASUnitProvisioningInfo
+	statuses         chan map[string]status.StatusInfo
+	addresses        chan map[string]string
+
+	applicationsWatcher *mockStringsWatcher
+	unitsWatcher        *mockStringsWatcher
+	podSpecWatcher      *mockNotifyWatcher
+}
+
+func newMockFacade() *mockFacade {
+	return &mockFacade{
+		cloudType:           "kubernetes",
+		life:                make(map[string]params.Life),
+		statuses:            make(chan map[string]status.StatusInfo, 5),
+		addresses:           make(chan map[string]string, 5),
+		applicationsWatcher: newMockStringsWatcher(),
+		unitsWatcher:        newMockStringsWatcher(),
+		podSpecWatcher:      newMockNotifyWatcher(),
+	}
+}
+
+func (f *mockFacade) setLife(entityName string, life params.Life) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if life == "" {
+		delete(f.life, entityName)
+	} else {
+		f.life[entityName] = life
+	}
+}
+
+func (f *mockFacade) WatchApplications() (watcher.StringsWatcher, error) {
+	f.MethodCall(f, "WatchApplications")
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.applicationsWatcher, nil
+}
+
+func (f *mockFacade) WatchUnits(application string) (watcher.StringsWatcher, error) {
+	f.MethodCall(f, "WatchUnits", application)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.unitsWatcher, nil
+}
+
+func (f *mockFacade) WatchPodSpec(application string) (watcher.NotifyWatcher, error) {
+	f.MethodCall(f, "WatchPodSpec", application)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.podSpecWatcher, nil
+}
+
+func (f *mockFacade) ProvisioningInfo(application string) (*params.CAASUnitProvisioningInfo, error) {
+	f.MethodCall(f, "ProvisioningInfo", application)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.provisioningInfo == nil {
+		return nil, errors.NotFoundf("pod spec for %v", application)
+	}
+	return f.provisioningInfo, nil
+}
+
+func (f *mockFacade) Life(entityName string) (params.Life, error) {
+	f.MethodCall(f, "Life", entityName)
+	if err := f.NextErr(); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	life, ok := f.life[entityName]
+	if !ok {
+		return "", errors.NotFoundf("%v", entityName)
+	}
+	return life, nil
+}
+
+func (f *mockFacade) SetContainerStatuses(statuses map[string]status.StatusInfo) error {
+	f.MethodCall(f, "SetContainerStatuses", statuses)
+	if err := f.NextErr(); err != nil {
+		return err
+	}
+	f.statuses <- statuses
+	return nil
+}
+
+func (f *mockFacade) SetContainerAddresses(addresses map[string]string) error {
+	f.MethodCall(f, "SetContainerAddresses", addresses)
+	if err := f.NextErr(); err != nil {
+		return err
+	}
+	f.addresses <- addresses
+	return nil
+}
+
+func (f *mockFacade) CloudSpec() (environs.CloudSpec, error) {
+	f.MethodCall(f, "CloudSpec")
+	if err := f.NextErr(); err != nil {
+		return environs.CloudSpec{}, err
+	}
+	return environs.CloudSpec{Type: f.cloudType, Name: "k8s"}, nil
+}
+
+func (f *mockFacade) ModelConfig() (*config.Config, error) {
+	f.MethodCall(f, "ModelConfig")
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return config.New(config.UseDefaults, coretesting.FakeConfig())
+}
+
+type ensureServiceCall struct {
+	appName  string
+	params   *caas.ServiceParams
+	numUnits int
+}
+
+type mockBroker struct {
+	testing.Stub
+	caas.Broker
+
+	mu      sync.Mutex
+	units   []caas.Unit
+	ensured chan ensureServiceCall
+	deleted chan string
+}
+
+func newMockBroker() *mockBroker {
+	return &mockBroker{
+		ensured: make(chan ensureServiceCall, 5),
+		deleted: make(chan string, 5),
+	}
+}
+
+func (b *mockBroker) EnsureNamespace() error {
+	b.MethodCall(b, "EnsureNamespace")
+	return b.NextErr()
+}
+
+func (b *mockBroker) EnsureService(appName string, params *caas.ServiceParams, numUnits int) error {
+	b.MethodCall(b, "EnsureService", appName, params, numUnits)
+	if err := b.NextErr(); err != nil {
+		return err
+	}
+	b.ensured <- ensureServiceCall{appName, params, numUnits}
+	return nil
+}
+
+func (b *mockBroker) DeleteService(appName string) error {
+	b.MethodCall(b, "DeleteService", appName)
+	if err := b.NextErr(); err != nil {
+		return err
+	}
+	b.deleted <- appName
+	return nil
+}
+
+func (b *mockBroker) Units(appName string) ([]caas.Unit, error) {
+	b.MethodCall(b, "Units", appName)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]caas.Unit(nil), b.units...), nil
+}
+
+func (b *mockBroker) AssignUnit(appName, id, unitName string) error {
+	b.MethodCall(b, "AssignUnit", appName, id, unitName)
+	if err := b.NextErr(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, u := range b.units {
+		if u.Id == id {
+			b.units[i].UnitName = unitName
+			return nil
+		}
+	}
+	return errors.NotFoundf("pod %q", id)
+}
+
+type mockWatcher struct {
+	testing.Stub
+	tomb.Tomb
+}
+
+func (w *mockWatcher) doneWhenDying() {
+	<-w.Tomb.Dying()
+	w.Tomb.Done()
+}
+
+func (w *mockWatcher) Kill() {
+	w.MethodCall(w, "Kill")
+	w.Tomb.Kill(nil)
+}
+
+func (w *mockWatcher) Wait() error {
+	w.MethodCall(w, "Wait")
+	return w.Tomb.Wait()
+}
+
+type mockStringsWatcher struct {
+	mockWatcher
+	changes chan []string
+}
+
+func newMockStringsWatcher() *mockStringsWatcher {
+	w := &mockStringsWatcher{changes: make(chan []string, 5)}
+	go w.doneWhenDying()
+	return w
+}
+
+func (w *mockStringsWatcher) Changes() watcher.StringsChannel {
+	return w.changes
+}
+
+type mockNotifyWatcher struct {
+	mockWatcher
+	changes chan struct{}
+}
+
+func newMockNotifyWatcher() *mockNotifyWatcher {
+	w := &mockNotifyWatcher{changes: make(chan struct{}, 5)}
+	go w.doneWhenDying()
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() watcher.NotifyChannel {
+	return w.changes
+}
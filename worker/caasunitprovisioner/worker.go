@@ -0,0 +1,153 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.workers.caasunitprovisioner")
+
+// Facade provides the methods of the CAASUnitProvisioner facade
+// required by the worker.
+type Facade interface {
+	WatchApplications() (watcher.StringsWatcher, error)
+	WatchUnits(application string) (watcher.StringsWatcher, error)
+	WatchPodSpec(application string) (watcher.NotifyWatcher, error)
+	ProvisioningInfo(application string) (*params.CAASUnitProvisioningInfo, error)
+	Life(entityName string) (params.Life, error)
+	SetContainerStatuses(map[string]status.StatusInfo) error
+	SetContainerAddresses(map[string]string) error
+}
+
+// ServiceBroker provides the methods of caas.Broker required
+// by the worker.
+type ServiceBroker interface {
+	EnsureService(appName string, params *caas.ServiceParams, numUnits int) error
+	DeleteService(appName string) error
+	Units(appName string) ([]caas.Unit, error)
+}
+
+// Config holds configuration for the CAAS unit provisioner worker.
+type Config struct {
+	Facade Facade
+	Broker ServiceBroker
+	Clock  clock.Clock
+}
+
+// Validate validates the worker configuration.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("missing Facade")
+	}
+	if config.Broker == nil {
+		return errors.NotValidf("missing Broker")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("missing Clock")
+	}
+	return nil
+}
+
+// NewWorker starts and returns a new CAAS unit provisioner worker.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	p := &provisioner{
+		config:     config,
+		appWorkers: make(map[string]worker.Worker),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &p.catacomb,
+		Work: p.loop,
+	})
+	return p, err
+}
+
+type provisioner struct {
+	catacomb catacomb.Catacomb
+	config   Config
+
+	// appWorkers holds the worker for each application
+	// currently being provisioned.
+	appWorkers map[string]worker.Worker
+}
+
+// Kill is part of the worker.Worker interface.
+func (p *provisioner) Kill() {
+	p.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (p *provisioner) Wait() error {
+	return p.catacomb.Wait()
+}
+
+func (p *provisioner) loop() error {
+	w, err := p.config.Facade.WatchApplications()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := p.catacomb.Add(w); err != nil {
+		return errors.Trace(err)
+	}
+
+	for {
+		select {
+		case <-p.catacomb.Dying():
+			return p.catacomb.ErrDying()
+		case apps, ok := <-w.Changes():
+			if !ok {
+				return errors.New("app watcher closed channel")
+			}
+			for _, appId := range apps {
+				if err := p.applicationChanged(appId); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+	}
+}
+
+func (p *provisioner) applicationChanged(appId string) error {
+	appLife, err := p.config.Facade.Life(appId)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if errors.IsNotFound(err) || appLife == params.Dead {
+		if w, ok := p.appWorkers[appId]; ok {
+			if err := worker.Stop(w); err != nil {
+				logger.Errorf("error stopping unit provisioner for %v: %v", appId, err)
+			}
+			delete(p.appWorkers, appId)
+		}
+		logger.Debugf("deleting service for %v", appId)
+		if err := p.config.Broker.DeleteService(appId); err != nil {
+			return errors.Annotatef(err, "deleting service for %v", appId)
+		}
+		return nil
+	}
+	if _, ok := p.appWorkers[appId]; ok {
+		return nil
+	}
+	w, err := newApplicationWorker(appId, p.config.Facade, p.config.Broker, p.config.Clock)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := p.catacomb.Add(w); err != nil {
+		return errors.Trace(err)
+	}
+	p.appWorkers[appId] = w
+	return nil
+}
@@ -0,0 +1,298 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitprovisioner_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/caasunitprovisioner"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	config caasunitprovisioner.Config
+	facade *mockFacade
+	broker *mockBroker
+	clock  *testing.Clock
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+const gitlabSpec = `
+containers:
+  - name: gitlab
+    image: gitlab/latest
+`
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.facade = newMockFacade()
+	s.facade.setLife("gitlab", params.Alive)
+	s.facade.setLife("gitlab/0", params.Alive)
+	s.facade.setLife("gitlab/1", params.Alive)
+	s.facade.provisioningInfo = &params.CAASUnitProvisioningInfo{
+		PodSpec: gitlabSpec,
+		Filesystems: []params.KubernetesFilesystemParams{{
+			StorageName: "data",
+			Size:        100,
+			Location:    "/srv/data",
+		}},
+	}
+	s.broker = newMockBroker()
+	s.clock = testing.NewClock(time.Time{})
+	s.config = caasunitprovisioner.Config{
+		Facade: s.facade,
+		Broker: s.broker,
+		Clock:  s.clock,
+	}
+}
+
+func (s *WorkerSuite) TestValidateConfig(c *gc.C) {
+	s.testValidateConfig(c, func(config *caasunitprovisioner.Config) {
+		config.Facade = nil
+	}, `missing Facade not valid`)
+
+	s.testValidateConfig(c, func(config *caasunitprovisioner.Config) {
+		config.Broker = nil
+	}, `missing Broker not valid`)
+
+	s.testValidateConfig(c, func(config *caasunitprovisioner.Config) {
+		config.Clock = nil
+	}, `missing Clock not valid`)
+}
+
+func (s *WorkerSuite) testValidateConfig(c *gc.C, f func(*caasunitprovisioner.Config), expect string) {
+	config := s.config
+	f(&config)
+	w, err := caasunitprovisioner.NewWorker(config)
+	if err == nil {
+		workertest.DirtyKill(c, w)
+	}
+	c.Check(err, gc.ErrorMatches, expect)
+}
+
+func (s *WorkerSuite) TestStartStop(c *gc.C) {
+	w, err := caasunitprovisioner.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CheckAlive(c, w)
+	workertest.CleanKill(c, w)
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := caasunitprovisioner.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, w) })
+	return w
+}
+
+func (s *WorkerSuite) assertEnsured(c *gc.C, numUnits int) {
+	select {
+	case call := <-s.broker.ensured:
+		c.Assert(call.appName, gc.Equals, "gitlab")
+		c.Assert(call.numUnits, gc.Equals, numUnits)
+		c.Assert(call.params, jc.DeepEquals, &caas.ServiceParams{
+			PodSpec: &caas.PodSpec{
+				Containers: []caas.ContainerSpec{{Name: "gitlab", Image: "gitlab/latest"}},
+			},
+			Filesystems: []caas.FilesystemParams{{
+				StorageName: "data",
+				Size:        100,
+				Location:    "/srv/data",
+			}},
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for service to be ensured")
+	}
+}
+
+func (s *WorkerSuite) assertNotEnsured(c *gc.C) {
+	select {
+	case call := <-s.broker.ensured:
+		c.Fatalf("unexpected EnsureService call: %+v", call)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestEnsureServiceWaitsForInitialEvents(c *gc.C) {
+	s.startWorker(c)
+	s.facade.applicationsWatcher.changes <- []string{"gitlab"}
+	s.facade.unitsWatcher.changes <- []string{"gitlab/0"}
+	s.assertNotEnsured(c)
+
+	s.facade.podSpecWatcher.changes <- struct{}{}
+	s.assertEnsured(c, 1)
+}
+
+func (s *WorkerSuite) TestScaleUnits(c *gc.C) {
+	s.startWorker(c)
+	s.facade.applicationsWatcher.changes <- []string{"gitlab"}
+	s.facade.podSpecWatcher.changes <- struct{}{}
+	s.facade.unitsWatcher.changes <- []string{"gitlab/0"}
+	s.assertEnsured(c, 1)
+
+	s.facade.unitsWatcher.changes <- []string{"gitlab/1"}
+	s.assertEnsured(c, 2)
+
+	s.facade.setLife("gitlab/0", params.Dying)
+	s.facade.unitsWatcher.changes <- []string{"gitlab/0"}
+	s.assertEnsured(c, 1)
+
+	s.facade.setLife("gitlab/1", "")
+	s.facade.unitsWatcher.changes <- []string{"gitlab/1"}
+	s.assertEnsured(c, 0)
+}
+
+func (s *WorkerSuite) TestNoPodSpec(c *gc.C) {
+	s.facade.provisioningInfo = nil
+	s.startWorker(c)
+	s.facade.applicationsWatcher.changes <- []string{"gitlab"}
+	s.facade.podSpecWatcher.changes <- struct{}{}
+	s.facade.unitsWatcher.changes <- []string{"gitlab/0"}
+	s.assertNotEnsured(c)
+}
+
+func (s *WorkerSuite) TestApplicationRemoved(c *gc.C) {
+	w := s.startWorker(c)
+	s.facade.applicationsWatcher.changes <- []string{"gitlab"}
+	s.facade.podSpecWatcher.changes <- struct{}{}
+	s.facade.unitsWatcher.changes <- []string{"gitlab/0"}
+	s.assertEnsured(c, 1)
+
+	s.facade.setLife("gitlab", "")
+	s.facade.applicationsWatcher.changes <- []string{"gitlab"}
+	select {
+	case appName := <-s.broker.deleted:
+		c.Assert(appName, gc.Equals, "gitlab")
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for service to be deleted")
+	}
+	workertest.CheckKilled(c, s.facade.unitsWatcher)
+	workertest.CheckKilled(c, s.facade.podSpecWatcher)
+	workertest.CheckAlive(c, w)
+}
+
+func (s *WorkerSuite) TestContainerStatus(c *gc.C) {
+	s.broker.units = []caas.Unit{{
+		Id:     "uid-0",
+		Status: status.StatusInfo{Status: status.Running, Message: "Running"},
+	}}
+	s.startWorker(c)
+	s.facade.applicationsWatcher.changes <- []string{"gitlab"}
+	s.facade.podSpecWatcher.changes <- struct{}{}
+	s.facade.unitsWatcher.changes <- []string{"gitlab/1", "gitlab/0"}
+	s.assertEnsured(c, 2)
+
+	c.Assert(s.clock.WaitAdvance(30*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.assertStatuses(c, map[string]status.StatusInfo{
+		"gitlab/0": {Status: status.Running, Message: "Running"},
+		"gitlab/1": {Status: status.Waiting, Message: "waiting for container"},
+	})
+
+	// Only changes are reported.
+	s.broker.mu.Lock()
+	s.broker.units = append(s.broker.units, caas.Unit{
+		Id:     "uid-1",
+		Status: status.StatusInfo{Status: status.Error, Message: "Failed"},
+	})
+	s.broker.mu.Unlock()
+	c.Assert(s.clock.WaitAdvance(30*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.assertStatuses(c, map[string]status.StatusInfo{
+		"gitlab/1": {Status: status.Error, Message: "Failed"},
+	})
+}
+
+func (s *WorkerSuite) TestContainerStatusAssignedPods(c *gc.C) {
+	// The oldest pod is already assigned to gitlab/1, and the
+	// other pod was assigned to a unit that has since been removed.
+	s.broker.units = []caas.Unit{{
+		Id:       "uid-0",
+		UnitName: "gitlab/1",
+		Status:   status.StatusInfo{Status: status.Running, Message: "Running"},
+	}, {
+		Id:       "uid-1",
+		UnitName: "gitlab/2",
+		Status:   status.StatusInfo{Status: status.Waiting, Message: "Pending"},
+	}}
+	s.startWorker(c)
+	s.facade.applicationsWatcher.changes <- []string{"gitlab"}
+	s.facade.podSpecWatcher.changes <- struct{}{}
+	s.facade.unitsWatcher.changes <- []string{"gitlab/1", "gitlab/0"}
+	s.assertEnsured(c, 2)
+
+	c.Assert(s.clock.WaitAdvance(30*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.assertStatuses(c, map[string]status.StatusInfo{
+		"gitlab/0": {Status: status.Waiting, Message: "Pending"},
+		"gitlab/1": {Status: status.Running, Message: "Running"},
+	})
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	c.Assert(s.broker.units[0].UnitName, gc.Equals, "gitlab/1")
+	c.Assert(s.broker.units[1].UnitName, gc.Equals, "gitlab/0")
+}
+
+func (s *WorkerSuite) assertStatuses(c *gc.C, expect map[string]status.StatusInfo) {
+	select {
+	case statuses := <-s.facade.statuses:
+		c.Assert(statuses, jc.DeepEquals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for container statuses")
+	}
+}
+
+func (s *WorkerSuite) TestContainerAddresses(c *gc.C) {
+	s.broker.units = []caas.Unit{{
+		Id:      "uid-0",
+		Address: "10.0.0.1",
+		Status:  status.StatusInfo{Status: status.Running},
+	}, {
+		Id:     "uid-1",
+		Status: status.StatusInfo{Status: status.Waiting},
+	}}
+	s.startWorker(c)
+	s.facade.applicationsWatcher.changes <- []string{"gitlab"}
+	s.facade.podSpecWatcher.changes <- struct{}{}
+	s.facade.unitsWatcher.changes <- []string{"gitlab/1", "gitlab/0"}
+	s.assertEnsured(c, 2)
+
+	// Pods without an address yet are not reported.
+	c.Assert(s.clock.WaitAdvance(30*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.assertAddresses(c, map[string]string{"gitlab/0": "10.0.0.1"})
+
+	// Only changes are reported.
+	s.broker.mu.Lock()
+	s.broker.units[1].Address = "10.0.0.2"
+	s.broker.mu.Unlock()
+	c.Assert(s.clock.WaitAdvance(30*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.assertAddresses(c, map[string]string{"gitlab/1": "10.0.0.2"})
+}
+
+func (s *WorkerSuite) assertAddresses(c *gc.C, expect map[string]string) {
+	select {
+	case addresses := <-s.facade.addresses:
+		c.Assert(addresses, jc.DeepEquals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for container addresses")
+	}
+}
+
+func (s *WorkerSuite) TestWatchApplicationsError(c *gc.C) {
+	s.facade.SetErrors(errors.New("boom"))
+	w, err := caasunitprovisioner.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,248 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewaller
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+)
+
+// egressRetryDelay is the time to wait before trying again to apply
+// the egress rules of machines which were not yet provisioned.
+const egressRetryDelay = 10 * time.Second
+
+// reconcileEgress compares the egress rules applied to the instances
+// of the initially started machines with those wanted by the
+// applications of their units, and opens and closes the appropriate
+// egress rules for each instance.
+func (fw *Firewaller) reconcileEgress() error {
+	if fw.environEgressFirewaller == nil {
+		return nil
+	}
+	for _, machined := range fw.machineds {
+		m, err := machined.machine()
+		if params.IsCodeNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		instanceId, err := m.InstanceId()
+		if params.IsCodeNotProvisioned(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		current, err := fw.environEgressFirewaller.EgressRules(instanceId, machined.tag.Id())
+		if errors.IsNotSupported(err) {
+			logger.Warningf("cannot reconcile egress rules: %v", err)
+			return nil
+		}
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		machined.egressRules = current
+		if err := fw.flushEgressRules(machined); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// retryPendingEgress applies the egress rules of machines that were
+// not provisioned when their rules were last flushed.
+func (fw *Firewaller) retryPendingEgress() error {
+	for tag := range fw.egressPending {
+		delete(fw.egressPending, tag)
+		machined, ok := fw.machineds[tag]
+		if !ok {
+			continue
+		}
+		if err := fw.flushEgressRules(machined); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// flushEgressRules opens and closes the egress rules of the passed
+// machine's instance, so that they match the egress rules of the
+// applications with units on the machine.
+func (fw *Firewaller) flushEgressRules(machined *machineData) error {
+	want, err := fw.gatherEgressRules(machined)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	toOpen, toClose := diffEgressRules(machined.egressRules, want)
+	if len(toOpen) == 0 && len(toClose) == 0 {
+		return nil
+	}
+	if fw.environEgressFirewaller == nil {
+		// The recorded rules are left unchanged, so that the rules
+		// are tried again the next time the machine's rules change.
+		logger.Warningf("egress rules %v for %q not applied: egress rules are not supported by the cloud", want, machined.tag)
+		return nil
+	}
+	m, err := machined.machine()
+	if params.IsCodeNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	instanceId, err := m.InstanceId()
+	if params.IsCodeNotProvisioned(err) {
+		// Try again once the machine has been provisioned.
+		fw.egressPending[machined.tag] = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	machineId := machined.tag.Id()
+	if len(toOpen) > 0 {
+		err := fw.environEgressFirewaller.OpenEgressPorts(instanceId, machineId, toOpen)
+		if errors.IsNotSupported(err) {
+			logger.Warningf("egress rules %v for %q not applied: %v", want, machined.tag, err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		logger.Infof("opened egress rules %v on %q", toOpen, machined.tag)
+	}
+	if len(toClose) > 0 {
+		err := fw.environEgressFirewaller.CloseEgressPorts(instanceId, machineId, toClose)
+		if errors.IsNotSupported(err) {
+			logger.Warningf("egress rules %v for %q not applied: %v", want, machined.tag, err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		logger.Infof("closed egress rules %v on %q", toClose, machined.tag)
+	}
+	machined.egressRules = want
+	return nil
+}
+
+// gatherEgressRules returns the egress rules wanted for the passed
+// machine: the union of the egress rules of the applications with
+// units on the machine. If any egress is restricted, the rules also
+// allow the machine's agents to reach the controller.
+func (fw *Firewaller) gatherEgressRules(machined *machineData) ([]network.EgressRule, error) {
+	var rules []network.EgressRule
+	for _, unitd := range machined.unitds {
+		rules = append(rules, unitd.applicationd.egressRules...)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	info, err := fw.firewallerApi.ControllerAPIInfoForModel(fw.modelUUID)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get controller addresses")
+	}
+	rules = append(rules, controllerEgressRules(info.Addrs)...)
+	return mergeEgressRules(rules), nil
+}
+
+// controllerEgressRules returns the egress rules allowing access to
+// the controller API at the given host:port addresses. Addresses which
+// are not IP addresses cannot be expressed as a rule and are skipped.
+func controllerEgressRules(addrs []string) []network.EgressRule {
+	var rules []network.EgressRule
+	for _, addr := range addrs {
+		host, portStr, err := net.SplitHostPort(addr)
+		if err != nil {
+			logger.Warningf("ignoring invalid controller address %q: %v", addr, err)
+			continue
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			logger.Warningf("ignoring invalid controller address %q: %v", addr, err)
+			continue
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			logger.Warningf("cannot allow egress to controller address %q: not an IP address", addr)
+			continue
+		}
+		cidr := ip.String() + "/32"
+		if ip.To4() == nil {
+			cidr = ip.String() + "/128"
+		}
+		rules = append(rules, network.EgressRule{
+			PortRange:        network.PortRange{Protocol: "tcp", FromPort: port, ToPort: port},
+			DestinationCIDRs: []string{cidr},
+		})
+	}
+	return mergeEgressRules(rules)
+}
+
+// egressCIDRs returns the destinations of the rules, keyed on port range.
+func egressCIDRs(rules []network.EgressRule) map[network.PortRange]set.Strings {
+	result := make(map[network.PortRange]set.Strings)
+	for _, rule := range rules {
+		cidrs, ok := result[rule.PortRange]
+		if !ok {
+			cidrs = set.NewStrings()
+			result[rule.PortRange] = cidrs
+		}
+		for _, cidr := range rule.DestinationCIDRs {
+			cidrs.Add(cidr)
+		}
+	}
+	return result
+}
+
+// egressRulesFromCIDRs is the inverse of egressCIDRs, returning the
+// rules sorted and with a single rule for each port range.
+func egressRulesFromCIDRs(portCIDRs map[network.PortRange]set.Strings) []network.EgressRule {
+	var rules []network.EgressRule
+	for portRange, cidrs := range portCIDRs {
+		if cidrs.IsEmpty() {
+			continue
+		}
+		rules = append(rules, network.EgressRule{
+			PortRange:        portRange,
+			DestinationCIDRs: cidrs.SortedValues(),
+		})
+	}
+	network.SortEgressRules(rules)
+	return rules
+}
+
+// mergeEgressRules combines the destinations of rules for the same
+// port range.
+func mergeEgressRules(rules []network.EgressRule) []network.EgressRule {
+	return egressRulesFromCIDRs(egressCIDRs(rules))
+}
+
+// diffEgressRules returns the destinations to allow and to stop
+// allowing for each port range, in order to get from the current to
+// the wanted egress rules.
+func diffEgressRules(currentRules, wantedRules []network.EgressRule) (toOpen, toClose []network.EgressRule) {
+	current := egressCIDRs(currentRules)
+	wanted := egressCIDRs(wantedRules)
+	opened := make(map[network.PortRange]set.Strings)
+	for portRange, cidrs := range wanted {
+		opened[portRange] = cidrs.Difference(current[portRange])
+	}
+	closed := make(map[network.PortRange]set.Strings)
+	for portRange, cidrs := range current {
+		closed[portRange] = cidrs.Difference(wanted[portRange])
+	}
+	return egressRulesFromCIDRs(opened), egressRulesFromCIDRs(closed)
+}
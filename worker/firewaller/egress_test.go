@@ -0,0 +1,72 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewaller
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+)
+
+type EgressRulesSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&EgressRulesSuite{})
+
+func (s *EgressRulesSuite) TestDiffEgressRules(c *gc.C) {
+	current := []network.EgressRule{
+		network.MustNewEgressRule("tcp", 80, 80, "10.0.0.0/8"),
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8", "192.168.1.0/24"),
+	}
+	wanted := []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8", "172.16.0.0/12"),
+		network.MustNewEgressRule("udp", 53, 53, "10.0.0.1/32"),
+	}
+	toOpen, toClose := diffEgressRules(current, wanted)
+	c.Assert(toOpen, jc.DeepEquals, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "172.16.0.0/12"),
+		network.MustNewEgressRule("udp", 53, 53, "10.0.0.1/32"),
+	})
+	c.Assert(toClose, jc.DeepEquals, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 80, 80, "10.0.0.0/8"),
+		network.MustNewEgressRule("tcp", 443, 443, "192.168.1.0/24"),
+	})
+}
+
+func (s *EgressRulesSuite) TestDiffEgressRulesUnchanged(c *gc.C) {
+	rules := []network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8", "192.168.1.0/24"),
+	}
+	toOpen, toClose := diffEgressRules(rules, rules)
+	c.Assert(toOpen, gc.HasLen, 0)
+	c.Assert(toClose, gc.HasLen, 0)
+}
+
+func (s *EgressRulesSuite) TestMergeEgressRules(c *gc.C) {
+	merged := mergeEgressRules([]network.EgressRule{
+		network.MustNewEgressRule("tcp", 443, 443, "192.168.1.0/24"),
+		network.MustNewEgressRule("tcp", 80, 80, "10.0.0.0/8"),
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8", "192.168.1.0/24"),
+	})
+	c.Assert(merged, jc.DeepEquals, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 80, 80, "10.0.0.0/8"),
+		network.MustNewEgressRule("tcp", 443, 443, "10.0.0.0/8", "192.168.1.0/24"),
+	})
+}
+
+func (s *EgressRulesSuite) TestControllerEgressRules(c *gc.C) {
+	rules := controllerEgressRules([]string{
+		"10.0.0.1:17070",
+		"[2001:db8::1]:17070",
+		"controller.example.com:17070",
+		"10.0.0.2:17070",
+		"bogus",
+	})
+	c.Assert(rules, jc.DeepEquals, []network.EgressRule{
+		network.MustNewEgressRule("tcp", 17070, 17070, "10.0.0.1/32", "10.0.0.2/32", "2001:db8::1/128"),
+	})
+}
@@ -5,6 +5,7 @@ package firewaller
 
 import (
 	"io"
+	"reflect"
 	"strings"
 	"time"
 
@@ -43,6 +44,8 @@ type FirewallerAPI interface {
 	MacaroonForRelation(relationKey string) (*macaroon.Macaroon, error)
 	SetRelationStatus(relationKey string, status relation.Status, message string) error
 	FirewallRules(serviceNames ...string) ([]params.FirewallRule, error)
+	ApplicationFirewallRules(tag names.ApplicationTag) ([]params.FirewallRule, error)
+	WatchFirewallRules() (watcher.NotifyWatcher, error)
 }
 
 // CrossModelFirewallerFacade exposes firewaller functionality on the
@@ -65,6 +68,12 @@ type EnvironFirewaller interface {
 	environs.Firewaller
 }
 
+// EnvironEgressFirewaller defines methods to allow the worker to
+// restrict the traffic sent by instances in a Juju cloud environment.
+type EnvironEgressFirewaller interface {
+	environs.EgressFirewaller
+}
+
 // EnvironInstances defines methods to allow the worker to perform
 // operations on instances in a Juju cloud environment.
 type EnvironInstances interface {
@@ -82,6 +91,10 @@ type Config struct {
 	EnvironFirewaller  EnvironFirewaller
 	EnvironInstances   EnvironInstances
 
+	// EnvironEgressFirewaller, if set, is used to apply the egress
+	// rules of applications to the instances hosting their units.
+	EnvironEgressFirewaller EnvironEgressFirewaller
+
 	NewCrossModelFacadeFunc newCrossModelFacadeFunc
 
 	Clock clock.Clock
@@ -122,8 +135,12 @@ type Firewaller struct {
 	environFirewaller  EnvironFirewaller
 	environInstances   EnvironInstances
 
+	environEgressFirewaller EnvironEgressFirewaller
+	egressPending           map[names.MachineTag]bool
+
 	machinesWatcher      watcher.StringsWatcher
 	portsWatcher         watcher.StringsWatcher
+	rulesWatcher         watcher.NotifyWatcher
 	machineds            map[names.MachineTag]*machineData
 	unitsChange          chan *unitsChange
 	unitds               map[names.UnitTag]*unitData
@@ -155,6 +172,8 @@ func NewFirewaller(cfg Config) (worker.Worker, error) {
 		remoteRelationsApi:         cfg.RemoteRelationsApi,
 		environFirewaller:          cfg.EnvironFirewaller,
 		environInstances:           cfg.EnvironInstances,
+		environEgressFirewaller:    cfg.EnvironEgressFirewaller,
+		egressPending:              make(map[names.MachineTag]bool),
 		newRemoteFirewallerAPIFunc: cfg.NewCrossModelFacadeFunc,
 		modelUUID:                  cfg.ModelUUID,
 		machineds:                  make(map[names.MachineTag]*machineData),
@@ -215,6 +234,14 @@ func (fw *Firewaller) setUp() error {
 		return errors.Trace(err)
 	}
 
+	fw.rulesWatcher, err = fw.firewallerApi.WatchFirewallRules()
+	if err != nil {
+		return errors.Annotatef(err, "failed to start firewall rules watcher")
+	}
+	if err := fw.catacomb.Add(fw.rulesWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
 	fw.remoteRelationsWatcher, err = fw.remoteRelationsApi.WatchRemoteRelations()
 	if err != nil {
 		return errors.Trace(err)
@@ -232,8 +259,12 @@ func (fw *Firewaller) loop() error {
 		return errors.Trace(err)
 	}
 	var reconciled bool
+	var egressRetry <-chan time.Time
 	portsChange := fw.portsWatcher.Changes()
 	for {
+		if egressRetry == nil && len(fw.egressPending) > 0 {
+			egressRetry = fw.pollClock.After(egressRetryDelay)
+		}
 		select {
 		case <-fw.catacomb.Dying():
 			return fw.catacomb.ErrDying()
@@ -257,6 +288,14 @@ func (fw *Firewaller) loop() error {
 				if err != nil {
 					return errors.Trace(err)
 				}
+				if err := fw.reconcileEgress(); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		case <-egressRetry:
+			egressRetry = nil
+			if err := fw.retryPendingEgress(); err != nil {
+				return errors.Trace(err)
 			}
 		case change, ok := <-portsChange:
 			if !ok {
@@ -271,6 +310,13 @@ func (fw *Firewaller) loop() error {
 					return errors.Trace(err)
 				}
 			}
+		case _, ok := <-fw.rulesWatcher.Changes():
+			if !ok {
+				return errors.New("firewall rules watcher closed")
+			}
+			if err := fw.firewallRulesChanged(); err != nil {
+				return errors.Trace(err)
+			}
 		case change, ok := <-fw.remoteRelationsWatcher.Changes():
 			if !ok {
 				return errors.New("remote relations watcher closed")
@@ -292,7 +338,8 @@ func (fw *Firewaller) loop() error {
 				return errors.Trace(err)
 			}
 		case change := <-fw.exposedChange:
-			change.applicationd.exposed = change.exposed
+			change.applicationd.exposeInfo = change.exposeInfo
+			change.applicationd.egressRules = change.egressRules
 			unitds := []*unitData{}
 			for _, unitd := range change.applicationd.unitds {
 				unitds = append(unitds, unitd)
@@ -326,6 +373,24 @@ func (fw *Firewaller) relationIngressChanged(change *remoteRelationNetworkChange
 	return nil
 }
 
+// firewallRulesChanged reloads the firewall rules for each of the
+// known applications and updates the ingress rules for their units.
+func (fw *Firewaller) firewallRulesChanged() error {
+	var unitds []*unitData
+	for _, applicationd := range fw.applicationids {
+		if err := applicationd.refreshFirewallRules(); err != nil {
+			return errors.Trace(err)
+		}
+		for _, unitd := range applicationd.unitds {
+			unitds = append(unitds, unitd)
+		}
+	}
+	if err := fw.flushUnits(unitds); err != nil {
+		return errors.Annotate(err, "cannot change firewall ports")
+	}
+	return nil
+}
+
 // startMachine creates a new data value for tracking details of the
 // machine and starts watching the machine for units added or removed.
 func (fw *Firewaller) startMachine(tag names.MachineTag) error {
@@ -334,7 +399,7 @@ func (fw *Firewaller) startMachine(tag names.MachineTag) error {
 		tag:          tag,
 		unitds:       make(map[names.UnitTag]*unitData),
 		ingressRules: make([]network.IngressRule, 0),
-		definedPorts: make(map[names.UnitTag]portRanges),
+		definedPorts: make(map[names.SubnetTag]map[names.UnitTag]portRanges),
 	}
 	m, err := machined.machine()
 	if params.IsCodeNotFound(err) {
@@ -443,22 +508,30 @@ func (fw *Firewaller) startUnit(unit *firewaller.Unit, machineTag names.MachineT
 // startApplication creates a new data value for tracking details of the
 // application and starts watching the application for exposure changes.
 func (fw *Firewaller) startApplication(app *firewaller.Application) error {
-	exposed, err := app.IsExposed()
+	exposeInfo, err := app.ExposeInfo()
+	if err != nil {
+		return err
+	}
+	egressRules, err := app.EgressRules()
 	if err != nil {
 		return err
 	}
 	applicationd := &applicationData{
 		fw:          fw,
 		application: app,
-		exposed:     exposed,
+		exposeInfo:  exposeInfo,
+		egressRules: egressRules,
 		unitds:      make(map[names.UnitTag]*unitData),
 	}
+	if err := applicationd.refreshFirewallRules(); err != nil {
+		return errors.Trace(err)
+	}
 	fw.applicationids[app.Tag()] = applicationd
 
 	err = catacomb.Invoke(catacomb.Plan{
 		Site: &applicationd.catacomb,
 		Work: func() error {
-			return applicationd.watchLoop(exposed)
+			return applicationd.watchLoop(exposeInfo, egressRules)
 		},
 	})
 	if err != nil {
@@ -606,7 +679,8 @@ func (fw *Firewaller) unitsChanged(change *unitsChange) error {
 	return nil
 }
 
-// openedPortsChanged handles port change notifications
+// openedPortsChanged handles port change notifications for the
+// ports opened on the machine in the specified subnet.
 func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, subnetTag names.SubnetTag) error {
 
 	machined, ok := fw.machineds[machineTag]
@@ -646,8 +720,12 @@ func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, subnetTag
 		ranges[portRange] = true
 	}
 
-	if !unitPortsEqual(machined.definedPorts, newPortRanges) {
-		machined.definedPorts = newPortRanges
+	if !unitPortsEqual(machined.definedPorts[subnetTag], newPortRanges) {
+		if len(newPortRanges) == 0 {
+			delete(machined.definedPorts, subnetTag)
+		} else {
+			machined.definedPorts[subnetTag] = newPortRanges
+		}
 		return fw.flushMachine(machined)
 	}
 	return nil
@@ -708,9 +786,14 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 	toOpen, toClose := diffRanges(machined.ingressRules, want)
 	machined.ingressRules = want
 	if fw.globalMode {
-		return fw.flushGlobalPorts(toOpen, toClose)
+		err = fw.flushGlobalPorts(toOpen, toClose)
+	} else {
+		err = fw.flushInstancePorts(machined, toOpen, toClose)
 	}
-	return fw.flushInstancePorts(machined, toOpen, toClose)
+	if err != nil {
+		return err
+	}
+	return fw.flushEgressRules(machined)
 }
 
 // gatherIngressRules returns the ingress rules to open and close
@@ -718,32 +801,48 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 func (fw *Firewaller) gatherIngressRules(machines ...*machineData) ([]network.IngressRule, error) {
 	var want []network.IngressRule
 	for _, machined := range machines {
-		for unitTag, portRanges := range machined.definedPorts {
-			unitd, known := machined.unitds[unitTag]
-			if !known {
-				logger.Debugf("no ingress rules for unknown %v on %v", unitTag, machined.tag)
-				continue
-			}
+		for subnetTag, unitPorts := range machined.definedPorts {
+			for unitTag, portRanges := range unitPorts {
+				unitd, known := machined.unitds[unitTag]
+				if !known {
+					logger.Debugf("no ingress rules for unknown %v on %v", unitTag, machined.tag)
+					continue
+				}
+
+				// If the unit is exposed, allow access to the ports of
+				// its exposed endpoints from the permitted subnets.
+				if unitd.applicationd.exposeInfo.Exposed {
+					for portRange := range portRanges {
+						sourceCidrs := unitd.applicationd.ingressCIDRs(subnetTag, portRange)
+						if len(sourceCidrs) == 0 {
+							// The ports are only used by endpoints
+							// which have not been exposed.
+							continue
+						}
+						rule, err := network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, sourceCidrs...)
+						if err != nil {
+							return nil, errors.Trace(err)
+						}
+						want = append(want, rule)
+					}
+					continue
+				}
 
-			cidrs := set.NewStrings()
-			// If the unit is exposed, allow access from everywhere.
-			if unitd.applicationd.exposed {
-				cidrs.Add("0.0.0.0/0")
-			} else {
 				// Not exposed, so add any ingress rules required by remote relations.
+				cidrs := set.NewStrings()
 				if err := fw.updateForRemoteRelationIngress(unitd.applicationd.application.Tag(), cidrs); err != nil {
 					return nil, errors.Trace(err)
 				}
 				logger.Debugf("CIDRS for %v: %v", unitTag, cidrs.Values())
-			}
-			if cidrs.Size() > 0 {
-				for portRange := range portRanges {
-					sourceCidrs := cidrs.SortedValues()
-					rule, err := network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, sourceCidrs...)
-					if err != nil {
-						return nil, errors.Trace(err)
+				if cidrs.Size() > 0 {
+					for portRange := range portRanges {
+						sourceCidrs := cidrs.SortedValues()
+						rule, err := network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, sourceCidrs...)
+						if err != nil {
+							return nil, errors.Trace(err)
+						}
+						want = append(want, rule)
 					}
-					want = append(want, rule)
 				}
 			}
 		}
@@ -943,6 +1042,7 @@ func (fw *Firewaller) forgetMachine(machined *machineData) error {
 	// watch loop has stopped before we nuke the last data and return.
 	worker.Stop(machined)
 	delete(fw.machineds, machined.tag)
+	delete(fw.egressPending, machined.tag)
 	logger.Debugf("stopped watching %q", machined.tag)
 	return nil
 }
@@ -1000,8 +1100,11 @@ type machineData struct {
 	tag          names.MachineTag
 	unitds       map[names.UnitTag]*unitData
 	ingressRules []network.IngressRule
-	// ports defined by units on this machine
-	definedPorts map[names.UnitTag]portRanges
+	// egress rules applied to the machine's instance
+	egressRules []network.EgressRule
+	// ports defined by units on this machine, keyed on the subnet
+	// they were opened in
+	definedPorts map[names.SubnetTag]map[names.UnitTag]portRanges
 }
 
 func (md *machineData) machine() (*firewaller.Machine, error) {
@@ -1049,23 +1152,136 @@ type unitData struct {
 	machined     *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific application.
+// exposedChange contains the changed expose settings and egress
+// rules for one specific application.
 type exposedChange struct {
 	applicationd *applicationData
-	exposed      bool
+	exposeInfo   params.ExposeInfoResult
+	egressRules  []network.EgressRule
 }
 
 // applicationData holds application details and watches exposure changes.
 type applicationData struct {
-	catacomb    catacomb.Catacomb
-	fw          *Firewaller
-	application *firewaller.Application
-	exposed     bool
-	unitds      map[names.UnitTag]*unitData
+	catacomb      catacomb.Catacomb
+	fw            *Firewaller
+	application   *firewaller.Application
+	exposeInfo    params.ExposeInfoResult
+	egressRules   []network.EgressRule
+	firewallRules []params.FirewallRule
+	unitds        map[names.UnitTag]*unitData
 }
 
-// watchLoop watches the application's exposed flag for changes.
-func (ad *applicationData) watchLoop(exposed bool) error {
+// refreshFirewallRules reloads the firewall rules for the application.
+func (ad *applicationData) refreshFirewallRules() error {
+	rules, err := ad.fw.firewallerApi.ApplicationFirewallRules(ad.application.Tag())
+	if err != nil && !params.IsCodeNotFound(err) {
+		return errors.Annotatef(err, "cannot get firewall rules for %q", ad.application.Tag())
+	}
+	ad.firewallRules = rules
+	return nil
+}
+
+// exposedCIDRs returns the subnets from which the specified port range
+// may be accessed when the application is exposed. A rule for the most
+// specific port range covering the ports takes precedence over a rule
+// for all of the application's ports. Without any rules, access is
+// allowed from everywhere.
+func (ad *applicationData) exposedCIDRs(portRange network.PortRange) []string {
+	var (
+		best      *params.FirewallRule
+		bestWidth int
+		allPorts  *params.FirewallRule
+	)
+	for i, rule := range ad.firewallRules {
+		if len(rule.WhitelistCIDRS) == 0 {
+			continue
+		}
+		if rule.PortRange == nil {
+			allPorts = &ad.firewallRules[i]
+			continue
+		}
+		rulePorts := rule.PortRange.NetworkPortRange()
+		if !strings.EqualFold(rulePorts.Protocol, portRange.Protocol) ||
+			rulePorts.FromPort > portRange.FromPort ||
+			rulePorts.ToPort < portRange.ToPort {
+			continue
+		}
+		width := rulePorts.ToPort - rulePorts.FromPort
+		if best == nil || width < bestWidth {
+			best = &ad.firewallRules[i]
+			bestWidth = width
+		}
+	}
+	if best == nil {
+		best = allPorts
+	}
+	if best == nil {
+		return []string{"0.0.0.0/0"}
+	}
+	return set.NewStrings(best.WhitelistCIDRS...).SortedValues()
+}
+
+// ingressCIDRs returns the subnets from which the specified port range,
+// opened in the given subnet, may be accessed when the application is
+// exposed. Without endpoint specific expose settings all opened ports
+// are exposed; otherwise a port range is exposed according to the
+// settings of the endpoints it belongs to, and an empty result means
+// the ports stay closed.
+//
+// Hook tools open ports for the unit as a whole rather than for an
+// endpoint, so ports opened without a subnet belong to all of the
+// unit's endpoints. Ports opened in a subnet belong to the endpoints
+// bound to the space containing that subnet.
+func (ad *applicationData) ingressCIDRs(subnetTag names.SubnetTag, portRange network.PortRange) []string {
+	if len(ad.exposeInfo.ExposedEndpoints) == 0 {
+		return ad.exposedCIDRs(portRange)
+	}
+	subnetCIDR := subnetTag.Id()
+	cidrs := set.NewStrings()
+	for endpoint, exp := range ad.exposeInfo.ExposedEndpoints {
+		if endpoint != "" && !ad.endpointHasPorts(endpoint, subnetCIDR) {
+			continue
+		}
+		if len(exp.ExposeToSpaces) == 0 && len(exp.ExposeToCIDRs) == 0 {
+			// Fall back to the application's firewall rules.
+			cidrs = cidrs.Union(set.NewStrings(ad.exposedCIDRs(portRange)...))
+			continue
+		}
+		cidrs = cidrs.Union(set.NewStrings(exp.ExposeToCIDRs...))
+		for _, space := range exp.ExposeToSpaces {
+			cidrs = cidrs.Union(set.NewStrings(ad.exposeInfo.SpaceSubnets[space]...))
+		}
+	}
+	return cidrs.SortedValues()
+}
+
+// endpointHasPorts reports whether ports opened in the subnet with the
+// given CIDR belong to the endpoint, based on the endpoint's binding.
+// Ports opened without a subnet belong to every endpoint. Ports opened
+// in a subnet outside of the known spaces are considered to belong to
+// the endpoints bound to the default space.
+func (ad *applicationData) endpointHasPorts(endpoint, subnetCIDR string) bool {
+	space, ok := ad.exposeInfo.EndpointBindings[endpoint]
+	if !ok {
+		return false
+	}
+	if subnetCIDR == "" {
+		return true
+	}
+	if space != "" {
+		return set.NewStrings(ad.exposeInfo.SpaceSubnets[space]...).Contains(subnetCIDR)
+	}
+	for _, cidrs := range ad.exposeInfo.SpaceSubnets {
+		if set.NewStrings(cidrs...).Contains(subnetCIDR) {
+			return false
+		}
+	}
+	return true
+}
+
+// watchLoop watches the application's expose settings and egress
+// rules for changes.
+func (ad *applicationData) watchLoop(exposeInfo params.ExposeInfoResult, egressRules []network.EgressRule) error {
 	appWatcher, err := ad.application.Watch()
 	if err != nil {
 		if params.IsCodeNotFound(err) {
@@ -1090,19 +1306,30 @@ func (ad *applicationData) watchLoop(exposed bool) error {
 				}
 				return nil
 			}
-			change, err := ad.application.IsExposed()
+			change, err := ad.application.ExposeInfo()
 			if err != nil {
-				return errors.Trace(err)
+				if !params.IsCodeNotFound(err) {
+					return errors.Trace(err)
+				}
+				return nil
+			}
+			changedRules, err := ad.application.EgressRules()
+			if err != nil {
+				if !params.IsCodeNotFound(err) {
+					return errors.Trace(err)
+				}
+				return nil
 			}
-			if change == exposed {
+			if reflect.DeepEqual(change, exposeInfo) && reflect.DeepEqual(changedRules, egressRules) {
 				continue
 			}
 
-			exposed = change
+			exposeInfo = change
+			egressRules = changedRules
 			select {
 			case <-ad.catacomb.Dying():
 				return ad.catacomb.ErrDying()
-			case ad.fw.exposedChange <- &exposedChange{ad, change}:
+			case ad.fw.exposedChange <- &exposedChange{ad, change, changedRules}:
 			}
 		}
 	}
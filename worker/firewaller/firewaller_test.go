@@ -164,6 +164,41 @@ func (s *firewallerBaseSuite) assertEnvironPorts(c *gc.C, expected []network.Ing
 	}
 }
 
+// assertEgressRules retrieves the egress rules of the instance and
+// compares them to the expected.
+func (s *firewallerBaseSuite) assertEgressRules(c *gc.C, inst instance.Instance, machineId string, expected []network.EgressRule) {
+	fwEnv, ok := s.Environ.(environs.EgressFirewaller)
+	c.Assert(ok, gc.Equals, true)
+
+	s.BackingState.StartSync()
+	start := time.Now()
+	for {
+		got, err := fwEnv.EgressRules(inst.Id(), machineId)
+		if err != nil {
+			c.Fatal(err)
+			return
+		}
+		if reflect.DeepEqual(got, expected) {
+			c.Succeed()
+			return
+		}
+		if time.Since(start) > coretesting.LongWait {
+			c.Fatalf("timed out: expected %q; got %q", expected, got)
+			return
+		}
+		time.Sleep(coretesting.ShortWait)
+	}
+}
+
+// expectedEgressRules returns the given egress rules combined with
+// those the firewaller adds for access to the controller.
+func (s *firewallerBaseSuite) expectedEgressRules(c *gc.C, rules ...network.EgressRule) []network.EgressRule {
+	info, err := s.firewaller.ControllerAPIInfoForModel(s.State.ModelUUID())
+	c.Assert(err, jc.ErrorIsNil)
+	rules = append(rules, firewaller.ControllerEgressRules(info.Addrs)...)
+	return firewaller.MergeEgressRules(rules)
+}
+
 func (s *firewallerBaseSuite) addUnit(c *gc.C, app *state.Application) (*state.Unit, *state.Machine) {
 	u, err := app.AddUnit(state.AddUnitParams{})
 	c.Assert(err, jc.ErrorIsNil)
@@ -236,6 +271,31 @@ func (s *InstanceModeSuite) newFirewallerWithClock(c *gc.C, clock clock.Clock) w
 	return fw
 }
 
+func (s *InstanceModeSuite) newFirewallerWithEgress(c *gc.C) worker.Worker {
+	s.clock = &mockClock{c: c}
+	fwEnv, ok := s.Environ.(environs.Firewaller)
+	c.Assert(ok, gc.Equals, true)
+	egressEnv, ok := s.Environ.(environs.EgressFirewaller)
+	c.Assert(ok, gc.Equals, true)
+
+	cfg := firewaller.Config{
+		ModelUUID:               s.State.ModelUUID(),
+		Mode:                    config.FwInstance,
+		EnvironFirewaller:       fwEnv,
+		EnvironInstances:        s.Environ,
+		EnvironEgressFirewaller: egressEnv,
+		FirewallerAPI:           s.firewaller,
+		RemoteRelationsApi:      s.remoteRelations,
+		NewCrossModelFacadeFunc: func(*api.Info) (firewaller.CrossModelFirewallerFacadeCloser, error) {
+			return s.crossmodelFirewaller, nil
+		},
+		Clock: s.clock,
+	}
+	fw, err := firewaller.NewFirewaller(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	return fw
+}
+
 func (s *InstanceModeSuite) TestStartStop(c *gc.C) {
 	fw := s.newFirewaller(c)
 	statetesting.AssertKillAndWait(c, fw)
@@ -291,6 +351,122 @@ func (s *InstanceModeSuite) TestExposedApplication(c *gc.C) {
 	})
 }
 
+func (s *InstanceModeSuite) TestExposedApplicationWithFirewallRules(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		WellKnownService: state.ApplicationRule,
+		Application:      "wordpress",
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+
+	err = u.OpenPorts("tcp", 80, 90)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.OpenPort("tcp", 8080)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 90, "10.0.0.0/8"),
+		network.MustNewIngressRule("tcp", 8080, 8080, "10.0.0.0/8"),
+	})
+
+	// A rule for a specific port range takes precedence.
+	err = rules.Save(state.FirewallRule{
+		WellKnownService: state.ApplicationRule,
+		Application:      "wordpress",
+		PortRange:        network.MustParsePortRange("8080/tcp"),
+		WhitelistCIDRs:   []string{"192.168.1.0/24"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 90, "10.0.0.0/8"),
+		network.MustNewIngressRule("tcp", 8080, 8080, "192.168.1.0/24"),
+	})
+
+	// Changing the application rule updates the ingress rules.
+	err = rules.Save(state.FirewallRule{
+		WellKnownService: state.ApplicationRule,
+		Application:      "wordpress",
+		WhitelistCIDRs:   []string{"10.1.0.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 90, "10.1.0.0/16"),
+		network.MustNewIngressRule("tcp", 8080, 8080, "192.168.1.0/24"),
+	})
+}
+
+func (s *InstanceModeSuite) TestExposedApplicationWithEndpoints(c *gc.C) {
+	for _, cidr := range []string{"10.0.0.0/24", "10.1.0.0/24"} {
+		_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := s.State.AddSpace("public", "", []string{"10.0.0.0/24"}, true)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", "", []string{"10.1.0.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplicationWithBindings(c, "wordpress", s.AddTestingCharm(c, "wordpress"), map[string]string{
+		"url": "public",
+		"db":  "internal",
+	})
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+
+	// Hook tools open ports for the unit as a whole, so the ports
+	// belong to all of the unit's endpoints.
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.OpenPort("tcp", 3306)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// An endpoint bound to a non-default space exposes the unit's
+	// ports to the subnets it is exposed to.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"db": {ExposeToSpaces: []string{"internal"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.1.0.0/24"),
+		network.MustNewIngressRule("tcp", 3306, 3306, "10.1.0.0/24"),
+	})
+
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"192.168.0.0/16"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.1.0.0/24", "192.168.0.0/16"),
+		network.MustNewIngressRule("tcp", 3306, 3306, "10.1.0.0/24", "192.168.0.0/16"),
+	})
+
+	// Unexposing an endpoint removes only the access it allowed.
+	err = app.UnsetExposeSettings([]string{"db"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "192.168.0.0/16"),
+		network.MustNewIngressRule("tcp", 3306, 3306, "192.168.0.0/16"),
+	})
+
+	err = app.UnsetExposeSettings([]string{"url"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), nil)
+}
+
 func (s *InstanceModeSuite) TestMultipleExposedApplications(c *gc.C) {
 	fw := s.newFirewaller(c)
 	defer statetesting.AssertKillAndWait(c, fw)
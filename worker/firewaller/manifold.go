@@ -92,6 +92,9 @@ func (cfg ManifoldConfig) start(context dependency.Context) (worker.Worker, erro
 	// nil value, as it won't be used.
 	fwEnv, fwEnvOK := environ.(environs.Firewaller)
 
+	// Egress rules are only applied if the environ supports them.
+	egressEnv, _ := environ.(environs.EgressFirewaller)
+
 	mode := environ.Config().FirewallMode()
 	if mode == config.FwNone {
 		logger.Infof("stopping firewaller (not required)")
@@ -119,6 +122,7 @@ func (cfg ManifoldConfig) start(context dependency.Context) (worker.Worker, erro
 		EnvironFirewaller:  fwEnv,
 		EnvironInstances:   environ,
 		Mode:               mode,
+		EnvironEgressFirewaller: egressEnv,
 		NewCrossModelFacadeFunc: crossmodelFirewallerFacadeFunc(cfg.NewControllerConnection),
 	})
 	if err != nil {
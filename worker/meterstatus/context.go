@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
@@ -81,6 +82,10 @@ func (ctx *limitedContext) HasExecutionSetUnitStatus() bool { return false }
 // ResetExecutionSetUnitStatus implements runner.Context.
 func (ctx *limitedContext) ResetExecutionSetUnitStatus() {}
 
+// RecordHookExecution implements runner.Context. Executions of hooks
+// in this context are not recorded with the unit's history.
+func (ctx *limitedContext) RecordHookExecution(params.HookExecution) error { return nil }
+
 // Id implements runner.Context.
 func (ctx *limitedContext) Id() string { return ctx.id }
 
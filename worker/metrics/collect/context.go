@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/metrics/spool"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -83,6 +84,10 @@ func (ctx *hookContext) HasExecutionSetUnitStatus() bool { return false }
 // ResetExecutionSetUnitStatus implements runner.Context.
 func (ctx *hookContext) ResetExecutionSetUnitStatus() {}
 
+// RecordHookExecution implements runner.Context. Executions of hooks
+// in this context are not recorded with the unit's history.
+func (ctx *hookContext) RecordHookExecution(params.HookExecution) error { return nil }
+
 // Id implements runner.Context.
 func (ctx *hookContext) Id() string { return ctx.id }
 
@@ -147,9 +147,73 @@ func possiblePeerGroupChanges(
 	sort.Sort(byId(toRemoveVote))
 	sort.Sort(byId(toAddVote))
 	sort.Sort(byId(toKeep))
+
+	// Prefer candidates in zones holding the fewest votes, so
+	// that the loss of a single zone is least likely to lose
+	// quorum.
+	voterZones := make(map[string]int)
+	for _, m := range toKeep {
+		if member := members[m]; member != nil && isVotingMember(member) {
+			voterZones[m.Zone()]++
+		}
+	}
+	toAddVote = zoneDiverseOrder(toAddVote, voterZones)
 	return toRemoveVote, toAddVote, toKeep
 }
 
+// zoneDiverseOrder returns the candidates reordered so that each is
+// in the zone holding the fewest votes after the votes of the
+// candidates before it are counted. The voters map holds the number
+// of existing votes in each zone. Machines in unknown zones are
+// treated as being in the same zone, and candidates in equally
+// populated zones keep their relative order.
+func zoneDiverseOrder(candidates []*machineTracker, voters map[string]int) []*machineTracker {
+	counts := make(map[string]int)
+	for zone, n := range voters {
+		counts[zone] = n
+	}
+	remaining := append([]*machineTracker(nil), candidates...)
+	result := make([]*machineTracker, 0, len(candidates))
+	for len(remaining) > 0 {
+		best := 0
+		for i, m := range remaining {
+			if counts[m.Zone()] < counts[remaining[best].Zone()] {
+				best = i
+			}
+		}
+		m := remaining[best]
+		result = append(result, m)
+		counts[m.Zone()]++
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+	return result
+}
+
+// concentratedZone returns the zone holding enough of the votes of
+// the given machines to form a quorum on its own, along with the number
+// of votes in that zone and the total number of votes. The zone is the
+// empty string if there is no such zone, or if there are too few votes
+// for their placement to matter.
+func concentratedZone(voting map[*machineTracker]bool) (string, int, int) {
+	counts := make(map[string]int)
+	total := 0
+	for m, hasVote := range voting {
+		if hasVote {
+			counts[m.Zone()]++
+			total++
+		}
+	}
+	if total < 3 {
+		return "", 0, total
+	}
+	for zone, n := range counts {
+		if zone != "" && n > total/2 {
+			return zone, n, total
+		}
+	}
+	return "", 0, total
+}
+
 // updateAddresses updates the members' addresses from the machines' addresses.
 // It reports whether any changes have been made.
 func updateAddresses(
@@ -150,6 +150,13 @@ func desiredPeerGroupTests(ipVersion TestIPVersion) []desiredPeerGroupTest {
 			statuses:      mkStatuses("1p 2s 3s 4s 5s 6s 7s 8s", ipVersion),
 			expectVoting:  []bool{true, true, false, false, false, true, true, true},
 			expectMembers: mkMembers("1v 2v 3 4 5 6v 7v 8v", ipVersion),
+		}, {
+			about:         "candidates in zones without votes are preferred",
+			machines:      withZones(mkMachines("11v 12v 13v 14v", ipVersion), "az1", "az1", "az2", "az3"),
+			members:       mkMembers("1v 2 3 4", ipVersion),
+			statuses:      mkStatuses("1p 2s 3s 4s", ipVersion),
+			expectVoting:  []bool{true, false, true, true},
+			expectMembers: mkMembers("1v 2 3v 4v", ipVersion),
 		}, {
 			about: "a changed machine address should propagate to the members",
 			machines: append(mkMachines("11v 12v", ipVersion), &machineTracker{
@@ -238,6 +245,34 @@ func (s *desiredPeerGroupSuite) TestDesiredPeerGroup(c *gc.C) {
 	})
 }
 
+func (s *desiredPeerGroupSuite) TestZoneDiverseOrder(c *gc.C) {
+	ms := withZones(mkMachines("1v 2v 3v 4v 5v", testIPv4), "az1", "az1", "az2", "az2", "az3")
+	ordered := zoneDiverseOrder(ms, map[string]int{"az2": 1})
+	var ids []string
+	for _, m := range ordered {
+		ids = append(ids, m.Id())
+	}
+	c.Assert(ids, jc.DeepEquals, []string{"1", "5", "2", "3", "4"})
+}
+
+func (s *desiredPeerGroupSuite) TestConcentratedZone(c *gc.C) {
+	ms := withZones(mkMachines("1v 2v 3v 4v 5v", testIPv4), "az1", "az1", "az2", "az1", "")
+	voting := map[*machineTracker]bool{ms[0]: true, ms[1]: true, ms[2]: true}
+	zone, n, total := concentratedZone(voting)
+	c.Check(zone, gc.Equals, "az1")
+	c.Check(n, gc.Equals, 2)
+	c.Check(total, gc.Equals, 3)
+
+	voting = map[*machineTracker]bool{ms[0]: true, ms[2]: true, ms[4]: true, ms[3]: false}
+	zone, _, total = concentratedZone(voting)
+	c.Check(zone, gc.Equals, "")
+	c.Check(total, gc.Equals, 3)
+
+	// A single voter is concentrated by definition; don't warn.
+	zone, _, _ = concentratedZone(map[*machineTracker]bool{ms[0]: true})
+	c.Check(zone, gc.Equals, "")
+}
+
 func countVotes(members []replicaset.Member) int {
 	tot := 0
 	for _, m := range members {
@@ -283,6 +318,15 @@ func mkMachines(description string, ipVersion TestIPVersion) []*machineTracker {
 	return ms
 }
 
+// withZones sets the availability zones of the given machines
+// and returns them.
+func withZones(ms []*machineTracker, zones ...string) []*machineTracker {
+	for i, zone := range zones {
+		ms[i].zone = zone
+	}
+	return ms
+}
+
 func memberTag(id string) map[string]string {
 	return map[string]string{jujuMachineKey: id}
 }
@@ -26,22 +26,30 @@ type machineTracker struct {
 	// Outside of the machineTracker implementation itself, these
 	// should always be accessed via the getter methods in order to be
 	// protected by the mutex.
-	id             string
-	wantsVote      bool
-	apiHostPorts   []network.HostPort
-	mongoHostPorts []network.HostPort
+	id              string
+	wantsVote       bool
+	apiHostPorts    []network.HostPort
+	mongoHostPorts  []network.HostPort
+	zone            string
+	decommissioning bool
 }
 
 func newMachineTracker(stm stateMachine, notifyCh chan struct{}) (*machineTracker, error) {
 	m := &machineTracker{
-		notifyCh:       notifyCh,
-		id:             stm.Id(),
-		stm:            stm,
-		apiHostPorts:   stm.APIHostPorts(),
-		mongoHostPorts: stm.MongoHostPorts(),
-		wantsVote:      stm.WantsVote(),
-	}
-	err := catacomb.Invoke(catacomb.Plan{
+		notifyCh:        notifyCh,
+		id:              stm.Id(),
+		stm:             stm,
+		apiHostPorts:    stm.APIHostPorts(),
+		mongoHostPorts:  stm.MongoHostPorts(),
+		wantsVote:       stm.WantsVote(),
+		decommissioning: stm.IsDecommissioning(),
+	}
+	zone, err := machineZone(stm)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	m.zone = zone
+	err = catacomb.Invoke(catacomb.Plan{
 		Site: &m.catacomb,
 		Work: m.loop,
 	})
@@ -76,6 +84,22 @@ func (m *machineTracker) WantsVote() bool {
 	return m.wantsVote
 }
 
+// Zone returns the availability zone of the machine's instance, or
+// the empty string if it is not known.
+func (m *machineTracker) Zone() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.zone
+}
+
+// IsDecommissioning returns whether the machine is to be removed from
+// the controller once it has lost its vote (according to state).
+func (m *machineTracker) IsDecommissioning() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.decommissioning
+}
+
 // WantsVote returns the MongoDB hostports from state.
 func (m *machineTracker) MongoHostPorts() []network.HostPort {
 	m.mu.Lock()
@@ -114,8 +138,8 @@ func (m *machineTracker) GoString() string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	return fmt.Sprintf("&peergrouper.machine{id: %q, wantsVote: %v, hostPorts: %v}",
-		m.id, m.wantsVote, m.mongoHostPorts)
+	return fmt.Sprintf("&peergrouper.machine{id: %q, wantsVote: %v, hostPorts: %v, zone: %q}",
+		m.id, m.wantsVote, m.mongoHostPorts, m.zone)
 }
 
 func (m *machineTracker) loop() error {
@@ -167,6 +191,10 @@ func (m *machineTracker) hasChanged() (bool, error) {
 		m.wantsVote = wantsVote
 		changed = true
 	}
+	if decommissioning := m.stm.IsDecommissioning(); decommissioning != m.decommissioning {
+		m.decommissioning = decommissioning
+		changed = true
+	}
 	if hps := m.stm.MongoHostPorts(); !hostPortsEqual(hps, m.mongoHostPorts) {
 		m.mongoHostPorts = hps
 		changed = true
@@ -175,9 +203,31 @@ func (m *machineTracker) hasChanged() (bool, error) {
 		m.apiHostPorts = hps
 		changed = true
 	}
+	zone, err := machineZone(m.stm)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if zone != m.zone {
+		m.zone = zone
+		changed = true
+	}
 	return changed, nil
 }
 
+// machineZone returns the availability zone of the machine's instance,
+// or the empty string if the machine has not been provisioned or the
+// provider does not support zones.
+func machineZone(stm stateMachine) (string, error) {
+	zone, err := stm.AvailabilityZone()
+	if errors.IsNotProvisioned(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot get availability zone of machine %q", stm.Id())
+	}
+	return zone, nil
+}
+
 func hostPortsEqual(hps1, hps2 []network.HostPort) bool {
 	if len(hps1) != len(hps2) {
 		return false
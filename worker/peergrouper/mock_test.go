@@ -294,8 +294,12 @@ type machineDoc struct {
 	wantsVote      bool
 	hasVote        bool
 	instanceId     instance.Id
+	zone           string
 	mongoHostPorts []network.HostPort
 	apiHostPorts   []network.HostPort
+
+	decommissioning bool
+	decommissioned  bool
 }
 
 func (m *fakeMachine) Refresh() error {
@@ -329,6 +333,15 @@ func (m *fakeMachine) InstanceId() (instance.Id, error) {
 	return m.doc.instanceId, nil
 }
 
+func (m *fakeMachine) AvailabilityZone() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.errors.errorFor("Machine.AvailabilityZone", m.doc.id); err != nil {
+		return "", err
+	}
+	return m.doc.zone, nil
+}
+
 func (m *fakeMachine) Watch() state.NotifyWatcher {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -432,6 +445,39 @@ func (m *fakeMachine) setWantsVote(wantsVote bool) {
 	})
 }
 
+// IsDecommissioning implements stateMachine.IsDecommissioning.
+func (m *fakeMachine) IsDecommissioning() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.doc.decommissioning
+}
+
+// Decommission implements stateMachine.Decommission.
+func (m *fakeMachine) Decommission() error {
+	if err := m.errors.errorFor("Machine.Decommission", m.doc.id); err != nil {
+		return err
+	}
+	m.mutate(func(doc *machineDoc) {
+		doc.decommissioned = true
+	})
+	return nil
+}
+
+// setDecommissioning marks the machine for removal, as
+// State.RemoveControllerMachine does.
+func (m *fakeMachine) setDecommissioning() {
+	m.mutate(func(doc *machineDoc) {
+		doc.wantsVote = false
+		doc.decommissioning = true
+	})
+}
+
+func (m *fakeMachine) isDecommissioned() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.doc.decommissioned
+}
+
 type fakeMongoSession struct {
 	// If InstantlyReady is true, replica status of
 	// all members will be instantly reported as ready.
@@ -38,12 +38,15 @@ type stateInterface interface {
 type stateMachine interface {
 	Id() string
 	InstanceId() (instance.Id, error)
+	AvailabilityZone() (string, error)
 	Status() (status.StatusInfo, error)
 	Refresh() error
 	Watch() state.NotifyWatcher
 	WantsVote() bool
 	HasVote() bool
 	SetHasVote(hasVote bool) error
+	IsDecommissioning() bool
+	Decommission() error
 	APIHostPorts() []network.HostPort
 	MongoHostPorts() []network.HostPort
 }
@@ -115,6 +118,11 @@ type pgWorker struct {
 	// hub is the central hub of the apiserver, and is used to publish the
 	// details of the api servers.
 	hub Hub
+
+	// concentratedZone holds the zone last reported as holding a
+	// quorum of the votes, so that the warning is not repeated at
+	// every update.
+	concentratedZone string
 }
 
 // New returns a new worker that maintains the mongo replica set
@@ -211,6 +219,13 @@ func (w *pgWorker) loop() error {
 				logger.Errorf("cannot set replicaset: %v", err)
 				ok = false
 			}
+			if ok {
+				// Only once the API server addresses have been
+				// published without them, and the replica set
+				// no longer relies on them, can machines being
+				// decommissioned be removed.
+				ok = w.decommissionMachines()
+			}
 			if ok {
 				// Update the replica set members occasionally
 				// to keep them up to date with the current
@@ -345,6 +360,11 @@ func (w *pgWorker) apiPublishInfo() ([][]network.HostPort, []instance.Id, error)
 	servers := make([][]network.HostPort, 0, len(w.machineTrackers))
 	instanceIds := make([]instance.Id, 0, len(w.machineTrackers))
 	for _, m := range w.machineTrackers {
+		if m.IsDecommissioning() {
+			// Clients should no longer connect to machines
+			// that are about to be removed.
+			continue
+		}
 		hostPorts := m.APIHostPorts()
 		server := apiserver.APIServer{ID: m.Id()}
 		if len(hostPorts) == 0 {
@@ -483,6 +503,7 @@ func (w *pgWorker) updateReplicaset() error {
 	if err != nil {
 		return fmt.Errorf("cannot compute desired peer group: %v", err)
 	}
+	w.checkZoneConcentration(voting)
 	if logger.IsDebugEnabled() {
 		if members != nil {
 			logger.Debugf("desired peer group members: \n%s", prettyReplicaSetMembers(members))
@@ -546,6 +567,43 @@ func (w *pgWorker) updateReplicaset() error {
 	return nil
 }
 
+// decommissionMachines removes the controller machines which are being
+// decommissioned and no longer have a vote. The machines then leave
+// the controller, and are removed from the replica set at the next
+// update. It reports whether all such machines were removed; failures
+// are logged, and retried at the next update.
+func (w *pgWorker) decommissionMachines() bool {
+	ok := true
+	for _, m := range w.machineTrackers {
+		if !m.IsDecommissioning() || m.stm.HasVote() {
+			continue
+		}
+		logger.Infof("decommissioning controller machine %q", m.Id())
+		if err := m.stm.Decommission(); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			logger.Errorf("cannot decommission machine %q: %v", m.Id(), err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// checkZoneConcentration warns when the voting machines in a single
+// availability zone are enough to form a quorum, as the loss of that
+// zone would then leave the controller unable to elect a primary.
+func (w *pgWorker) checkZoneConcentration(voting map[*machineTracker]bool) {
+	zone, n, total := concentratedZone(voting)
+	if zone != "" && zone != w.concentratedZone {
+		logger.Warningf(
+			"%d of %d controller votes are in availability zone %q; "+
+				"losing that zone would lose quorum", n, total, zone,
+		)
+	}
+	w.concentratedZone = zone
+}
+
 // setHasVote sets the HasVote status of all the given
 // machines to hasVote.
 func setHasVote(ms []*machineTracker, hasVote bool) error {
@@ -824,3 +824,66 @@ func (s *workerSuite) newPublishWorker(c *gc.C, st stateInterface, pub publisher
 func newNoPublishWorker(st stateInterface, clock clock.Clock, hub Hub) (worker.Worker, error) {
 	return newWorker(st, clock, noPublisher{}, false, hub)
 }
+
+func (s *workerSuite) TestDecommissioningMachineNotPublishedAndRemoved(c *gc.C) {
+	s.PatchValue(&pollInterval, coretesting.LongWait+time.Second)
+
+	publishCh := make(chan []instance.Id, 100)
+	publish := func(apiServers [][]network.HostPort, instanceIds []instance.Id) error {
+		publishCh <- instanceIds
+		return nil
+	}
+	st := NewFakeState()
+	InitState(c, st, 3, testIPv4)
+	st.machine("12").setDecommissioning()
+
+	s.newPublishWorker(c, st, PublisherFunc(publish))
+
+	select {
+	case instanceIds := <-publishCh:
+		c.Assert(instanceIds, jc.SameContents, []instance.Id{"id-10", "id-11"})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for publish")
+	}
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if st.machine("12").isDecommissioned() {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("machine was not decommissioned")
+		}
+	}
+	c.Assert(st.machine("10").isDecommissioned(), jc.IsFalse)
+	c.Assert(st.machine("11").isDecommissioned(), jc.IsFalse)
+}
+
+func (s *workerSuite) TestDecommissionErrorIsNotFatal(c *gc.C) {
+	s.PatchValue(&pollInterval, coretesting.LongWait+time.Second)
+
+	st := NewFakeState()
+	InitState(c, st, 3, testIPv4)
+	st.machine("12").setDecommissioning()
+	calls := 0
+	st.errors.setErrorFuncFor("Machine.Decommission 12", func() error {
+		calls++
+		if calls == 1 {
+			return errors.New("sample")
+		}
+		return nil
+	})
+
+	w := s.newNoPublishWorker(c, st)
+
+	// The worker retries, rather than dying, until the machine
+	// has been decommissioned.
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if st.machine("12").isDecommissioned() {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("machine was not decommissioned")
+		}
+		s.clock.Advance(maxRetryInterval)
+	}
+	workertest.CheckAlive(c, w)
+}
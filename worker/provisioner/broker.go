@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/tools"
@@ -24,6 +25,8 @@ type APICalls interface {
 	ReleaseContainerAddresses(names.MachineTag) error
 	SetHostMachineNetworkConfig(names.MachineTag, []params.NetworkConfig) error
 	HostChangesForContainer(containerTag names.MachineTag) ([]network.DeviceToBridge, int, error)
+	CharmProfiles(containerTag names.MachineTag) (map[string]lxdprofile.Profile, []string, error)
+	SetCharmProfiles(containerTag names.MachineTag, profiles []string) error
 }
 
 type hostArchToolsFinder struct {
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	instancetest "github.com/juju/juju/instance/testing"
@@ -50,6 +51,8 @@ type fakeAPI struct {
 	fakeDeviceToBridge  network.DeviceToBridge
 	fakeBridger         network.Bridger
 	fakePreparer        provisioner.PrepareHostFunc
+	fakeCharmProfiles   map[string]lxdprofile.Profile
+	fakeCurrentProfiles []string
 }
 
 var _ provisioner.APICalls = (*fakeAPI)(nil)
@@ -136,6 +139,19 @@ func (f *fakeAPI) HostChangesForContainer(machineTag names.MachineTag) ([]networ
 	return []network.DeviceToBridge{f.fakeDeviceToBridge}, 0, nil
 }
 
+func (f *fakeAPI) CharmProfiles(containerTag names.MachineTag) (map[string]lxdprofile.Profile, []string, error) {
+	f.MethodCall(f, "CharmProfiles", containerTag)
+	if err := f.NextErr(); err != nil {
+		return nil, nil, err
+	}
+	return f.fakeCharmProfiles, f.fakeCurrentProfiles, nil
+}
+
+func (f *fakeAPI) SetCharmProfiles(containerTag names.MachineTag, profiles []string) error {
+	f.MethodCall(f, "SetCharmProfiles", containerTag, profiles)
+	return f.NextErr()
+}
+
 func (f *fakeAPI) PrepareHost(containerTag names.MachineTag, log loggo.Logger) error {
 	// This is not actually part of the API, however it is something that the
 	// Brokers should be calling, and putting it here means we get a wholistic
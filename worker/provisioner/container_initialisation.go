@@ -6,6 +6,7 @@ package provisioner
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync/atomic"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	worker "gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/common"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/apiserver/params"
@@ -27,6 +29,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
 	"github.com/juju/juju/watcher"
 )
 
@@ -44,6 +47,7 @@ type ContainerSetup struct {
 	supportedContainers []instance.ContainerType
 	provisioner         *apiprovisioner.State
 	machine             *apiprovisioner.Machine
+	apiCaller           base.APICaller
 	config              agent.Config
 	initLockName        string
 
@@ -64,6 +68,7 @@ type ContainerSetupParams struct {
 	SupportedContainers []instance.ContainerType
 	Machine             *apiprovisioner.Machine
 	Provisioner         *apiprovisioner.State
+	APICaller           base.APICaller
 	Config              agent.Config
 	InitLockName        string
 }
@@ -76,6 +81,7 @@ func NewContainerSetupHandler(params ContainerSetupParams) watcher.StringsHandle
 		machine:             params.Machine,
 		supportedContainers: params.SupportedContainers,
 		provisioner:         params.Provisioner,
+		apiCaller:           params.APICaller,
 		config:              params.Config,
 		workerName:          params.WorkerName,
 		initLockName:        params.InitLockName,
@@ -147,14 +153,42 @@ func (cs *ContainerSetup) initialiseAndStartProvisioner(abort <-chan struct{}, c
 
 	logger.Debugf("setup and start provisioner for %s containers", containerType)
 	toolsFinder := getToolsFinder(cs.provisioner)
-	initialiser, broker, toolsFinder, err := cs.getContainerArtifacts(containerType, toolsFinder)
+	initialiser, broker, volumeSource, toolsFinder, err := cs.getContainerArtifacts(containerType, toolsFinder)
 	if err != nil {
 		return errors.Annotate(err, "initialising container infrastructure on host machine")
 	}
 	if err := cs.runInitialiser(abort, containerType, initialiser); err != nil {
 		return errors.Annotate(err, "setting up container dependencies on host machine")
 	}
-	return StartProvisioner(cs.runner, containerType, cs.provisioner, cs.config, broker, toolsFinder)
+	if err := StartProvisioner(cs.runner, containerType, cs.provisioner, cs.config, broker, toolsFinder); err != nil {
+		return errors.Trace(err)
+	}
+	if volumeSource == nil {
+		return nil
+	}
+	return cs.startStorageProvisioners(containerType, volumeSource)
+}
+
+// startStorageProvisioners kicks off a worker which runs a storage
+// provisioner for each container of the specified type on the machine,
+// attaching the containers' volumes through the given volume source.
+func (cs *ContainerSetup) startStorageProvisioners(
+	containerType instance.ContainerType, volumeSource storage.VolumeSource,
+) error {
+	handler := NewKVMStorageHandler(KVMStorageParams{
+		Runner:       cs.runner,
+		Machine:      cs.machine,
+		Provisioner:  cs.provisioner,
+		APICaller:    cs.apiCaller,
+		StorageDir:   filepath.Join(cs.config.DataDir(), "storage"),
+		VolumeSource: volumeSource,
+	})
+	workerName := fmt.Sprintf("%s-storage-provisioners", containerType)
+	return cs.runner.StartWorker(workerName, func() (worker.Worker, error) {
+		return watcher.NewStringsWorker(watcher.StringsConfig{
+			Handler: handler,
+		})
+	})
 }
 
 // acquireLock tries to grab the machine lock (initLockName), and either
@@ -243,7 +277,8 @@ func (cs *ContainerSetup) prepareHost(containerTag names.MachineTag, log loggo.L
 }
 
 // getContainerArtifacts returns type-specific interfaces for
-// managing containers.
+// managing containers. The returned VolumeSource is nil unless
+// the host machine attaches the containers' volumes itself.
 //
 // The ToolsFinder passed in may be replaced or wrapped to
 // enforce container-specific constraints.
@@ -252,20 +287,22 @@ func (cs *ContainerSetup) getContainerArtifacts(
 ) (
 	container.Initialiser,
 	environs.InstanceBroker,
+	storage.VolumeSource,
 	ToolsFinder,
 	error,
 ) {
 	var broker environs.InstanceBroker
+	var volumeSource storage.VolumeSource
 	var series string
 
 	managerConfig, err := containerManagerConfig(containerType, cs.provisioner, cs.config)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	availabilityZone, err := cs.machine.AvailabilityZone()
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 	// pass host machine's availability zone to the container manager config
 	managerConfig[container.ConfigAvailabilityZone] = availabilityZone
@@ -274,7 +311,7 @@ func (cs *ContainerSetup) getContainerArtifacts(
 	case instance.KVM:
 		manager, err := kvm.NewContainerManager(managerConfig)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 		broker, err = NewKVMBroker(
 			cs.prepareHost,
@@ -284,17 +321,18 @@ func (cs *ContainerSetup) getContainerArtifacts(
 		)
 		if err != nil {
 			logger.Errorf("failed to create new kvm broker")
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
+		volumeSource = kvm.NewVolumeSource(manager.(container.VolumeAttacher))
 	case instance.LXD:
 		series, err = cs.machine.Series()
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 
 		manager, err := lxd.NewContainerManager(managerConfig)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 		broker, err = NewLXDBroker(
 			cs.prepareHost,
@@ -304,13 +342,13 @@ func (cs *ContainerSetup) getContainerArtifacts(
 		)
 		if err != nil {
 			logger.Errorf("failed to create new lxd broker")
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 	default:
-		return nil, nil, nil, fmt.Errorf("unknown container type: %v", containerType)
+		return nil, nil, nil, nil, fmt.Errorf("unknown container type: %v", containerType)
 	}
 	initialiser := getContainerInitialiser(containerType, series)
-	return initialiser, broker, toolsFinder, nil
+	return initialiser, broker, volumeSource, toolsFinder, nil
 }
 
 // getContainerInitialiser exists to patch out in tests.
@@ -113,6 +113,7 @@ func (s *ContainerSetupSuite) setupContainerWorker(c *gc.C, tag names.MachineTag
 		SupportedContainers: instance.ContainerTypes,
 		Machine:             machine,
 		Provisioner:         pr,
+		APICaller:           s.st,
 		Config:              cfg,
 		InitLockName:        s.lockName,
 	}
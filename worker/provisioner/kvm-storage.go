@@ -0,0 +1,150 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	apiprovisioner "github.com/juju/juju/api/provisioner"
+	apistorageprovisioner "github.com/juju/juju/api/storageprovisioner"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/storageprovisioner"
+)
+
+// KVMStorageParams are used to initialise a KVM storage handler.
+type KVMStorageParams struct {
+	Runner       *worker.Runner
+	Machine      *apiprovisioner.Machine
+	Provisioner  *apiprovisioner.State
+	APICaller    base.APICaller
+	StorageDir   string
+	VolumeSource storage.VolumeSource
+}
+
+// kvmStorageHandler is a StringsWatchHandler that is notified when KVM
+// containers on the given machine change. A KVM container's volumes are
+// hotplugged by the machine hosting it, so the handler runs a storage
+// provisioner for each container, which manages only the container's
+// "kvm" volumes.
+type kvmStorageHandler struct {
+	runner       *worker.Runner
+	machine      *apiprovisioner.Machine
+	provisioner  *apiprovisioner.State
+	apiCaller    base.APICaller
+	storageDir   string
+	volumeSource storage.VolumeSource
+
+	// started holds the ids of the containers whose storage
+	// provisioners have been started.
+	started set.Strings
+}
+
+// NewKVMStorageHandler returns a StringsWatchHandler which runs a storage
+// provisioner for each KVM container on the given machine.
+func NewKVMStorageHandler(params KVMStorageParams) watcher.StringsHandler {
+	return &kvmStorageHandler{
+		runner:       params.Runner,
+		machine:      params.Machine,
+		provisioner:  params.Provisioner,
+		apiCaller:    params.APICaller,
+		storageDir:   params.StorageDir,
+		volumeSource: params.VolumeSource,
+		started:      set.NewStrings(),
+	}
+}
+
+// SetUp is defined on the StringsWatchHandler interface.
+func (h *kvmStorageHandler) SetUp() (watcher.StringsWatcher, error) {
+	return h.machine.WatchContainers(instance.KVM)
+}
+
+// Handle is defined on the StringsWatchHandler interface.
+func (h *kvmStorageHandler) Handle(_ <-chan struct{}, containerIds []string) error {
+	for _, id := range containerIds {
+		tag := names.NewMachineTag(id)
+		results, err := h.provisioner.Machines(tag)
+		if err != nil {
+			return errors.Annotatef(err, "cannot load machine %s", tag)
+		}
+		if len(results) != 1 {
+			return errors.Errorf("expected 1 result, got %d", len(results))
+		}
+		result := results[0]
+		if result.Err != nil && !params.IsCodeNotFound(result.Err) {
+			return errors.Annotatef(result.Err, "cannot load machine %s", tag)
+		}
+		if result.Err != nil || result.Machine.Life() == params.Dead {
+			if err := h.stopStorageProvisioner(id); err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		}
+		if err := h.startStorageProvisioner(tag); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (h *kvmStorageHandler) startStorageProvisioner(tag names.MachineTag) error {
+	if h.started.Contains(tag.Id()) {
+		return nil
+	}
+	logger.Debugf("starting storage provisioner for %s", tag)
+	err := h.runner.StartWorker(storageProvisionerName(tag.Id()), func() (worker.Worker, error) {
+		api, err := apistorageprovisioner.NewState(h.apiCaller, tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return storageprovisioner.NewStorageProvisioner(storageprovisioner.Config{
+			Scope:       tag,
+			StorageDir:  h.storageDir,
+			Volumes:     api,
+			Filesystems: api,
+			Life:        api,
+			Registry:    provider.KVMHostStorageProviders(h.volumeSource),
+			Machines:    api,
+			Status:      api,
+			Clock:       clock.WallClock,
+			VolumesOnly: true,
+		})
+	})
+	if err != nil {
+		return errors.Annotatef(err, "starting storage provisioner for %s", tag)
+	}
+	h.started.Add(tag.Id())
+	return nil
+}
+
+func (h *kvmStorageHandler) stopStorageProvisioner(id string) error {
+	if !h.started.Contains(id) {
+		return nil
+	}
+	logger.Debugf("stopping storage provisioner for machine %s", id)
+	if err := h.runner.StopWorker(storageProvisionerName(id)); err != nil {
+		return errors.Annotatef(err, "stopping storage provisioner for machine %s", id)
+	}
+	h.started.Remove(id)
+	return nil
+}
+
+// TearDown is defined on the StringsWatchHandler interface.
+func (h *kvmStorageHandler) TearDown() error {
+	return nil
+}
+
+func storageProvisionerName(containerId string) string {
+	return fmt.Sprintf("%s-storage-provisioner", containerId)
+}
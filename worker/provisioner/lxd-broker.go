@@ -4,13 +4,17 @@
 package provisioner
 
 import (
+	"sort"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 )
@@ -119,6 +123,20 @@ func (broker *lxdBroker) StartInstance(args environs.StartInstanceParams) (*envi
 		return nil, err
 	}
 
+	containerTag := names.NewMachineTag(containerMachineID)
+	var charmProfiles []string
+	if profileManager, ok := broker.manager.(container.LXDProfileManager); ok {
+		profiles, _, err := broker.api.CharmProfiles(containerTag)
+		if err != nil && !errors.IsNotSupported(err) {
+			return nil, errors.Annotate(err, "cannot get charm profiles")
+		}
+		charmProfiles, err = writeCharmProfiles(profileManager, profiles)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot write charm profiles")
+		}
+	}
+	args.InstanceConfig.CharmLXDProfiles = charmProfiles
+
 	storageConfig := &container.StorageConfig{}
 	inst, hardware, err := broker.manager.CreateContainer(
 		args.InstanceConfig, args.Constraints,
@@ -127,6 +145,11 @@ func (broker *lxdBroker) StartInstance(args environs.StartInstanceParams) (*envi
 	if err != nil {
 		return nil, err
 	}
+	if len(charmProfiles) > 0 {
+		if err := broker.api.SetCharmProfiles(containerTag, charmProfiles); err != nil {
+			lxdLogger.Errorf("cannot record charm profiles for %q: %v", containerMachineID, err)
+		}
+	}
 
 	return &environs.StartInstanceResult{
 		Instance:    inst,
@@ -168,3 +191,135 @@ func (broker *lxdBroker) MaintainInstance(args environs.StartInstanceParams) err
 	)
 	return err
 }
+
+// writeCharmProfiles writes the given charm LXD profiles, and returns
+// their names in order.
+func writeCharmProfiles(profileManager container.LXDProfileManager, profiles map[string]lxdprofile.Profile) ([]string, error) {
+	profileNames := make([]string, 0, len(profiles))
+	for name := range profiles {
+		profileNames = append(profileNames, name)
+	}
+	sort.Strings(profileNames)
+	for _, name := range profileNames {
+		profile := profiles[name]
+		if err := profileManager.WriteLXDProfile(name, &profile); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return profileNames, nil
+}
+
+// UpdateCharmProfiles makes sure every container started by the broker
+// has the charm LXD profiles needed by the units on it, replacing the
+// profiles of charms that have since been upgraded. Replaced profiles
+// that no container uses any more are deleted.
+func (broker *lxdBroker) UpdateCharmProfiles() error {
+	profileManager, ok := broker.manager.(container.LXDProfileManager)
+	if !ok {
+		return nil
+	}
+	containers, err := broker.manager.ListContainers()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	inUse := set.NewStrings()
+	superseded := set.NewStrings()
+	for _, inst := range containers {
+		containerTag, err := broker.manager.Namespace().MachineTag(string(inst.Id()))
+		if err != nil {
+			lxdLogger.Warningf("skipping container %q: %v", inst.Id(), err)
+			continue
+		}
+		profiles, current, err := broker.api.CharmProfiles(containerTag)
+		if errors.IsNotSupported(err) {
+			return nil
+		} else if err != nil {
+			lxdLogger.Warningf("cannot get charm profiles for %q: %v", containerTag.Id(), err)
+			continue
+		}
+		wanted := set.NewStrings()
+		for name := range profiles {
+			wanted.Add(name)
+		}
+		// Profile names include the charm revision, so an unchanged
+		// set of names means there is nothing to update.
+		if wanted.Difference(set.NewStrings(current...)).IsEmpty() && wanted.Size() == len(current) {
+			inUse = inUse.Union(wanted)
+			continue
+		}
+		profileNames, err := writeCharmProfiles(profileManager, profiles)
+		if err != nil {
+			return errors.Annotatef(err, "cannot write charm profiles for %q", containerTag.Id())
+		}
+		if err := profileManager.ReplaceLXDProfiles(inst.Id(), current, profileNames); err != nil {
+			return errors.Annotatef(err, "cannot replace charm profiles for %q", containerTag.Id())
+		}
+		if err := broker.api.SetCharmProfiles(containerTag, profileNames); err != nil {
+			return errors.Trace(err)
+		}
+		inUse = inUse.Union(wanted)
+		superseded = superseded.Union(set.NewStrings(current...).Difference(wanted))
+	}
+	for _, name := range superseded.Difference(inUse).SortedValues() {
+		// LXD refuses to delete a profile that is still in use, such
+		// as by a container whose profiles could not be read above,
+		// so a failure here leaves the profile for a later update.
+		if err := profileManager.DeleteLXDProfile(name); err != nil {
+			lxdLogger.Warningf("cannot delete charm profile %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// PrepareContainerMove makes the container with the given instance id
+// available to be pulled by the host it is moving to. Unless the move
+// is live, the container is stopped first.
+func (broker *lxdBroker) PrepareContainerMove(id instance.Id, live bool) (*container.MigrationSource, error) {
+	migrator, ok := broker.manager.(container.Migrator)
+	if !ok {
+		return nil, errors.NotSupportedf("moving containers")
+	}
+	source, err := migrator.MigrationSource(id, live)
+	return source, errors.Trace(err)
+}
+
+// ReceiveContainer pulls a container moving to this host from the host
+// described by source, after writing the charm LXD profiles it needs.
+// Unless the move is live, the container is started once copied.
+func (broker *lxdBroker) ReceiveContainer(containerTag names.MachineTag, id instance.Id, source container.MigrationSource, live bool) error {
+	migrator, ok := broker.manager.(container.Migrator)
+	if !ok {
+		return errors.NotSupportedf("moving containers")
+	}
+	if profileManager, ok := broker.manager.(container.LXDProfileManager); ok {
+		profiles, _, err := broker.api.CharmProfiles(containerTag)
+		if err != nil && !errors.IsNotSupported(err) {
+			return errors.Annotate(err, "cannot get charm profiles")
+		}
+		if _, err := writeCharmProfiles(profileManager, profiles); err != nil {
+			return errors.Annotate(err, "cannot write charm profiles")
+		}
+	}
+	return errors.Trace(migrator.MigrateContainer(id, source, !live))
+}
+
+// RemoveMovedContainer removes this host's copy of a container that has
+// been copied to another host. The container's addresses move with it,
+// so unlike StopInstances this does not release them.
+func (broker *lxdBroker) RemoveMovedContainer(id instance.Id) error {
+	lxdLogger.Infof("removing moved lxd container for instance: %s", id)
+	return errors.Trace(broker.manager.DestroyContainer(id))
+}
+
+// RestoreContainer restarts a container whose move has failed. Live
+// moves never stop the container, so there is nothing to restore.
+func (broker *lxdBroker) RestoreContainer(id instance.Id, live bool) error {
+	if live {
+		return nil
+	}
+	migrator, ok := broker.manager.(container.Migrator)
+	if !ok {
+		return errors.NotSupportedf("moving containers")
+	}
+	return errors.Trace(migrator.StartContainer(id))
+}
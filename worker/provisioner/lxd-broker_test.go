@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
@@ -153,6 +154,191 @@ func (s *lxdBrokerSuite) TestStartInstanceNoHostArchTools(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `need tools for arch amd64, only found \[arm64\]`)
 }
 
+func (s *lxdBrokerSuite) TestStartInstanceWritesCharmProfiles(c *gc.C) {
+	profileManager := &fakeLXDProfileManager{}
+	broker, err := provisioner.NewLXDBroker(s.api.PrepareHost, s.api, profileManager, s.agentConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	profile := lxdprofile.Profile{Config: map[string]string{"security.nesting": "true"}}
+	s.api.fakeCharmProfiles = map[string]lxdprofile.Profile{
+		"juju-default-b-1": profile,
+		"juju-default-a-2": profile,
+	}
+
+	_, err = s.startInstance(c, broker, "1/lxd/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	containerTag := names.NewMachineTag("1-lxd-0")
+	s.api.CheckCallNames(c,
+		"ContainerConfig", "PrepareHost", "PrepareContainerInterfaceInfo",
+		"CharmProfiles", "SetCharmProfiles",
+	)
+	s.api.CheckCall(c, 4, "SetCharmProfiles", containerTag, []string{"juju-default-a-2", "juju-default-b-1"})
+	profileManager.CheckCallNames(c, "WriteLXDProfile", "WriteLXDProfile", "CreateContainer")
+	profileManager.CheckCall(c, 0, "WriteLXDProfile", "juju-default-a-2", profile)
+	instanceConfig := profileManager.Calls()[2].Args[0].(*instancecfg.InstanceConfig)
+	c.Assert(instanceConfig.CharmLXDProfiles, jc.DeepEquals, []string{"juju-default-a-2", "juju-default-b-1"})
+}
+
+func (s *lxdBrokerSuite) TestUpdateCharmProfiles(c *gc.C) {
+	ns, err := instance.NewNamespace(coretesting.ModelTag.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	hostname, err := ns.Hostname("1/lxd/0")
+	c.Assert(err, jc.ErrorIsNil)
+	profileManager := &fakeLXDProfileManager{
+		containers: []instance.Instance{fakeContainerInstance{id: instance.Id(hostname)}},
+	}
+	broker, err := provisioner.NewLXDBroker(s.api.PrepareHost, s.api, profileManager, s.agentConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	profile := lxdprofile.Profile{Config: map[string]string{"security.nesting": "true"}}
+	s.api.fakeCharmProfiles = map[string]lxdprofile.Profile{"juju-default-app-2": profile}
+	s.api.fakeCurrentProfiles = []string{"juju-default-app-1"}
+
+	updater := broker.(interface {
+		UpdateCharmProfiles() error
+	})
+	err = updater.UpdateCharmProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+
+	containerTag := names.NewMachineTag("1-lxd-0")
+	s.api.CheckCallNames(c, "CharmProfiles", "SetCharmProfiles")
+	s.api.CheckCall(c, 1, "SetCharmProfiles", containerTag, []string{"juju-default-app-2"})
+	profileManager.CheckCallNames(c, "ListContainers", "WriteLXDProfile", "ReplaceLXDProfiles", "DeleteLXDProfile")
+	profileManager.CheckCall(c, 2, "ReplaceLXDProfiles",
+		instance.Id(hostname), []string{"juju-default-app-1"}, []string{"juju-default-app-2"},
+	)
+	profileManager.CheckCall(c, 3, "DeleteLXDProfile", "juju-default-app-1")
+
+	// Once the container is up to date, nothing changes.
+	s.api.ResetCalls()
+	profileManager.ResetCalls()
+	s.api.fakeCurrentProfiles = []string{"juju-default-app-2"}
+	err = updater.UpdateCharmProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c, "CharmProfiles")
+	profileManager.CheckCallNames(c, "ListContainers")
+}
+
+func (s *lxdBrokerSuite) TestPrepareContainerMove(c *gc.C) {
+	migrator := &fakeMigrator{source: &container.MigrationSource{Operation: "op"}}
+	broker, err := provisioner.NewLXDBroker(s.api.PrepareHost, s.api, migrator, s.agentConfig)
+	c.Assert(err, jc.ErrorIsNil)
+
+	source, err := broker.(containerMover).PrepareContainerMove("juju-0-lxd-0", true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(source, jc.DeepEquals, &container.MigrationSource{Operation: "op"})
+	migrator.CheckCalls(c, []gitjujutesting.StubCall{
+		{"MigrationSource", []interface{}{instance.Id("juju-0-lxd-0"), true}},
+	})
+}
+
+func (s *lxdBrokerSuite) TestReceiveContainer(c *gc.C) {
+	migrator := &fakeMigrator{}
+	broker, err := provisioner.NewLXDBroker(s.api.PrepareHost, s.api, migrator, s.agentConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	profile := lxdprofile.Profile{Config: map[string]string{"security.nesting": "true"}}
+	s.api.fakeCharmProfiles = map[string]lxdprofile.Profile{"juju-default-app-1": profile}
+
+	source := container.MigrationSource{
+		Addresses: []string{"10.0.0.1:8443"},
+		Operation: "op",
+		Profiles:  []string{"default", "juju-default-app-1"},
+	}
+	containerTag := names.NewMachineTag("0/lxd/0")
+	err = broker.(containerMover).ReceiveContainer(containerTag, "juju-0-lxd-0", source, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{{"CharmProfiles", []interface{}{containerTag}}})
+	migrator.CheckCalls(c, []gitjujutesting.StubCall{
+		{"WriteLXDProfile", []interface{}{"juju-default-app-1", profile}},
+		{"MigrateContainer", []interface{}{instance.Id("juju-0-lxd-0"), source, true}},
+	})
+}
+
+func (s *lxdBrokerSuite) TestRestoreContainer(c *gc.C) {
+	migrator := &fakeMigrator{}
+	broker, err := provisioner.NewLXDBroker(s.api.PrepareHost, s.api, migrator, s.agentConfig)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Live moves leave the container running.
+	err = broker.(containerMover).RestoreContainer("juju-0-lxd-0", true)
+	c.Assert(err, jc.ErrorIsNil)
+	migrator.CheckNoCalls(c)
+
+	err = broker.(containerMover).RestoreContainer("juju-0-lxd-0", false)
+	c.Assert(err, jc.ErrorIsNil)
+	migrator.CheckCalls(c, []gitjujutesting.StubCall{
+		{"StartContainer", []interface{}{instance.Id("juju-0-lxd-0")}},
+	})
+}
+
+func (s *lxdBrokerSuite) TestContainerMovesNotSupported(c *gc.C) {
+	broker, err := provisioner.NewLXDBroker(s.api.PrepareHost, s.api, s.manager, s.agentConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = broker.(containerMover).PrepareContainerMove("juju-0-lxd-0", false)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+type containerMover interface {
+	PrepareContainerMove(id instance.Id, live bool) (*container.MigrationSource, error)
+	ReceiveContainer(containerTag names.MachineTag, id instance.Id, source container.MigrationSource, live bool) error
+	RemoveMovedContainer(id instance.Id) error
+	RestoreContainer(id instance.Id, live bool) error
+}
+
+type fakeMigrator struct {
+	fakeLXDProfileManager
+	source *container.MigrationSource
+}
+
+func (m *fakeMigrator) MigrationSource(id instance.Id, live bool) (*container.MigrationSource, error) {
+	m.MethodCall(m, "MigrationSource", id, live)
+	return m.source, m.NextErr()
+}
+
+func (m *fakeMigrator) MigrateContainer(id instance.Id, source container.MigrationSource, start bool) error {
+	m.MethodCall(m, "MigrateContainer", id, source, start)
+	return m.NextErr()
+}
+
+func (m *fakeMigrator) StartContainer(id instance.Id) error {
+	m.MethodCall(m, "StartContainer", id)
+	return m.NextErr()
+}
+
+type fakeLXDProfileManager struct {
+	fakeContainerManager
+	containers []instance.Instance
+}
+
+func (m *fakeLXDProfileManager) ListContainers() ([]instance.Instance, error) {
+	m.MethodCall(m, "ListContainers")
+	return m.containers, m.NextErr()
+}
+
+func (m *fakeLXDProfileManager) WriteLXDProfile(name string, profile *lxdprofile.Profile) error {
+	m.MethodCall(m, "WriteLXDProfile", name, *profile)
+	return m.NextErr()
+}
+
+func (m *fakeLXDProfileManager) ReplaceLXDProfiles(id instance.Id, oldProfiles, newProfiles []string) error {
+	m.MethodCall(m, "ReplaceLXDProfiles", id, oldProfiles, newProfiles)
+	return m.NextErr()
+}
+
+func (m *fakeLXDProfileManager) DeleteLXDProfile(name string) error {
+	m.MethodCall(m, "DeleteLXDProfile", name)
+	return m.NextErr()
+}
+
+type fakeContainerInstance struct {
+	instance.Instance
+	id instance.Id
+}
+
+func (i fakeContainerInstance) Id() instance.Id {
+	return i.id
+}
+
 type fakeContainerManager struct {
 	gitjujutesting.Stub
 }
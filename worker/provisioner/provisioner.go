@@ -14,6 +14,8 @@ import (
 
 	"github.com/juju/juju/agent"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/container"
 	"github.com/juju/juju/controller/authentication"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -315,6 +317,46 @@ func (p *containerProvisioner) loop() error {
 		return errors.Trace(err)
 	}
 
+	// Brokers that can update the charm profiles of running
+	// containers do so whenever an application's charm changes.
+	var charmChanges watcher.NotifyChannel
+	updater, ok := p.broker.(charmProfileUpdater)
+	if ok {
+		charmWatcher, err := p.st.WatchApplicationCharms()
+		if errors.IsNotSupported(err) {
+			logger.Debugf("not updating charm profiles: %v", err)
+		} else if err != nil {
+			return errors.Trace(err)
+		} else {
+			if err := p.catacomb.Add(charmWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			charmChanges = charmWatcher.Changes()
+		}
+	}
+
+	// Brokers that can move containers between hosts take part in
+	// the moves of containers to and from this machine.
+	var moveChanges watcher.StringsChannel
+	mover, ok := p.broker.(containerMover)
+	if ok {
+		machine, err := p.getMachine()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		moveWatcher, err := p.st.WatchContainerMoves(machine.MachineTag())
+		if errors.IsNotSupported(err) {
+			logger.Debugf("not moving containers: %v", err)
+		} else if err != nil {
+			return errors.Trace(err)
+		} else {
+			if err := p.catacomb.Add(moveWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			moveChanges = moveWatcher.Changes()
+		}
+	}
+
 	for {
 		select {
 		case <-p.catacomb.Dying():
@@ -329,10 +371,125 @@ func (p *containerProvisioner) loop() error {
 			}
 			p.configObserver.notify(modelConfig)
 			task.SetHarvestMode(modelConfig.ProvisionerHarvestMode())
+		case _, ok := <-charmChanges:
+			if !ok {
+				return errors.New("application charms watch closed")
+			}
+			if err := updater.UpdateCharmProfiles(); err != nil {
+				return errors.Annotate(err, "cannot update charm profiles")
+			}
+		case ids, ok := <-moveChanges:
+			if !ok {
+				return errors.New("container moves watch closed")
+			}
+			for _, id := range ids {
+				if err := p.advanceContainerMove(mover, id); err != nil {
+					return errors.Annotatef(err, "cannot move container %s", id)
+				}
+			}
 		}
 	}
 }
 
+// charmProfileUpdater is implemented by brokers that can update the
+// charm LXD profiles of the containers they have started.
+type charmProfileUpdater interface {
+	UpdateCharmProfiles() error
+}
+
+// containerMover is implemented by brokers that can move containers
+// between hosts.
+type containerMover interface {
+	PrepareContainerMove(id instance.Id, live bool) (*container.MigrationSource, error)
+	ReceiveContainer(containerTag names.MachineTag, id instance.Id, source container.MigrationSource, live bool) error
+	RemoveMovedContainer(id instance.Id) error
+	RestoreContainer(id instance.Id, live bool) error
+}
+
+// advanceContainerMove plays this machine's part in the move of the
+// given container, according to whether it is the container's current
+// host or the move's target, and the move's phase. The host prepares
+// the container, and then either removes its copy once the target has
+// copied it, or restores it if the target failed to.
+func (p *containerProvisioner) advanceContainerMove(mover containerMover, id string) error {
+	machine, err := p.getMachine()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	containerTag := names.NewMachineTag(id)
+	move, err := p.st.ContainerMove(containerTag)
+	if params.IsCodeNotFoundOrCodeUnauthorized(err) {
+		// The move is over, or no longer involves this machine.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	instId := instance.Id(move.InstanceId)
+	isHost := move.HostTag == machine.MachineTag().String()
+	isTarget := move.TargetTag == machine.MachineTag().String()
+	update := func(phase string, source *params.ContainerMoveSource, message string) error {
+		return errors.Trace(p.st.UpdateContainerMove(params.UpdateContainerMoveArg{
+			Tag:     containerTag.String(),
+			Phase:   phase,
+			Source:  source,
+			Message: message,
+		}))
+	}
+
+	switch {
+	case isHost && move.Phase == params.ContainerMoveRequested:
+		logger.Infof("preparing container %s to move to %s", id, move.TargetTag)
+		source, err := mover.PrepareContainerMove(instId, move.Live)
+		if err != nil {
+			logger.Errorf("cannot prepare container %s to move: %v", id, err)
+			if err := mover.RestoreContainer(instId, move.Live); err != nil {
+				logger.Errorf("cannot restore container %s: %v", id, err)
+			}
+			return update(params.ContainerMoveAborted, nil, "")
+		}
+		return update(params.ContainerMoveSourceReady, &params.ContainerMoveSource{
+			Addresses:    source.Addresses,
+			Operation:    source.Operation,
+			Certificate:  source.Certificate,
+			Secrets:      source.Secrets,
+			Architecture: source.Architecture,
+			Config:       source.Config,
+			Devices:      source.Devices,
+			Profiles:     source.Profiles,
+		}, "")
+	case isTarget && move.Phase == params.ContainerMoveSourceReady && move.Source != nil:
+		logger.Infof("copying container %s from %s", id, move.HostTag)
+		err := mover.ReceiveContainer(containerTag, instId, container.MigrationSource{
+			Addresses:    move.Source.Addresses,
+			Operation:    move.Source.Operation,
+			Certificate:  move.Source.Certificate,
+			Secrets:      move.Source.Secrets,
+			Architecture: move.Source.Architecture,
+			Config:       move.Source.Config,
+			Devices:      move.Source.Devices,
+			Profiles:     move.Source.Profiles,
+		}, move.Live)
+		if err != nil {
+			logger.Errorf("cannot copy container %s: %v", id, err)
+			return update(params.ContainerMoveFailed, nil, err.Error())
+		}
+		return update(params.ContainerMoveCopied, nil, "")
+	case isHost && move.Phase == params.ContainerMoveCopied:
+		if err := mover.RemoveMovedContainer(instId); err != nil {
+			return errors.Trace(err)
+		}
+		logger.Infof("container %s moved to %s", id, move.TargetTag)
+		return update(params.ContainerMoveCompleted, nil, "")
+	case isHost && move.Phase == params.ContainerMoveFailed:
+		logger.Errorf("cannot move container %s to %s: %s", id, move.TargetTag, move.Message)
+		if err := mover.RestoreContainer(instId, move.Live); err != nil {
+			return errors.Trace(err)
+		}
+		return update(params.ContainerMoveAborted, nil, "")
+	}
+	return nil
+}
+
 func (p *containerProvisioner) getMachine() (*apiprovisioner.Machine, error) {
 	if p.machine == nil {
 		tag := p.agentConfig.Tag()
@@ -22,6 +22,12 @@ type Config struct {
 	Machines    MachineAccessor
 	Status      StatusSetter
 	Clock       clock.Clock
+
+	// VolumesOnly is true if the storage provisioner manages only
+	// volumes, leaving filesystems to another storage provisioner
+	// with the same scope. A machine runs such a storage provisioner
+	// for each of its KVM containers, to hotplug their volumes.
+	VolumesOnly bool
 }
 
 // Validate returns an error if the config cannot be relied upon to start a worker.
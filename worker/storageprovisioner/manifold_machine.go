@@ -49,7 +49,7 @@ func (config MachineManifoldConfig) newWorker(a agent.Agent, apiCaller base.APIC
 		Volumes:     api,
 		Filesystems: api,
 		Life:        api,
-		Registry:    provider.CommonStorageProviders(),
+		Registry:    provider.MachineStorageProviders(),
 		Machines:    api,
 		Status:      api,
 		Clock:       config.Clock,
@@ -235,14 +235,16 @@ func (w *storageProvisioner) loop() error {
 	}
 	volumesChanges = volumesWatcher.Changes()
 
-	filesystemsWatcher, err := w.config.Filesystems.WatchFilesystems()
-	if err != nil {
-		return errors.Annotate(err, "watching filesystems")
-	}
-	if err := w.catacomb.Add(filesystemsWatcher); err != nil {
-		return errors.Trace(err)
+	if !w.config.VolumesOnly {
+		filesystemsWatcher, err := w.config.Filesystems.WatchFilesystems()
+		if err != nil {
+			return errors.Annotate(err, "watching filesystems")
+		}
+		if err := w.catacomb.Add(filesystemsWatcher); err != nil {
+			return errors.Trace(err)
+		}
+		filesystemsChanges = filesystemsWatcher.Changes()
 	}
-	filesystemsChanges = filesystemsWatcher.Changes()
 
 	volumeAttachmentsWatcher, err := w.config.Volumes.WatchVolumeAttachments()
 	if err != nil {
@@ -253,14 +255,16 @@ func (w *storageProvisioner) loop() error {
 	}
 	volumeAttachmentsChanges = volumeAttachmentsWatcher.Changes()
 
-	filesystemAttachmentsWatcher, err := w.config.Filesystems.WatchFilesystemAttachments()
-	if err != nil {
-		return errors.Annotate(err, "watching filesystem attachments")
-	}
-	if err := w.catacomb.Add(filesystemAttachmentsWatcher); err != nil {
-		return errors.Trace(err)
+	if !w.config.VolumesOnly {
+		filesystemAttachmentsWatcher, err := w.config.Filesystems.WatchFilesystemAttachments()
+		if err != nil {
+			return errors.Annotate(err, "watching filesystem attachments")
+		}
+		if err := w.catacomb.Add(filesystemAttachmentsWatcher); err != nil {
+			return errors.Trace(err)
+		}
+		filesystemAttachmentsChanges = filesystemAttachmentsWatcher.Changes()
 	}
-	filesystemAttachmentsChanges = filesystemAttachmentsWatcher.Changes()
 
 	ctx := context{
 		kill:                                 w.catacomb.Kill,
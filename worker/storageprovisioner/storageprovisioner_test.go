@@ -72,6 +72,39 @@ func (s *storageProvisionerSuite) TestStartStop(c *gc.C) {
 	c.Assert(worker.Wait(), gc.IsNil)
 }
 
+func (s *storageProvisionerSuite) TestVolumesOnly(c *gc.C) {
+	worker, err := storageprovisioner.NewStorageProvisioner(storageprovisioner.Config{
+		Scope:       names.NewMachineTag("0/kvm/0"),
+		StorageDir:  "storage-dir",
+		Volumes:     newMockVolumeAccessor(),
+		Filesystems: unwatchedFilesystemAccessor{newMockFilesystemAccessor()},
+		Life:        &mockLifecycleManager{},
+		Registry:    s.registry,
+		Machines:    newMockMachineAccessor(c),
+		Status:      &mockStatusSetter{},
+		Clock:       &mockClock{},
+		VolumesOnly: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	worker.Kill()
+	c.Assert(worker.Wait(), gc.IsNil)
+}
+
+// unwatchedFilesystemAccessor is a FilesystemAccessor that fails if
+// filesystems are watched.
+type unwatchedFilesystemAccessor struct {
+	storageprovisioner.FilesystemAccessor
+}
+
+func (unwatchedFilesystemAccessor) WatchFilesystems() (watcher.StringsWatcher, error) {
+	return nil, errors.New("unexpected WatchFilesystems call")
+}
+
+func (unwatchedFilesystemAccessor) WatchFilesystemAttachments() (watcher.MachineStorageIdsWatcher, error) {
+	return nil, errors.New("unexpected WatchFilesystemAttachments call")
+}
+
 func (s *storageProvisionerSuite) TestInvalidConfig(c *gc.C) {
 	_, err := storageprovisioner.NewStorageProvisioner(almostValidConfig())
 	c.Check(err, jc.Satisfies, errors.IsNotValid)
@@ -0,0 +1,190 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package healthcheck runs the health checks declared by a charm with
+// the health-check-set hook tool, and reports their results as the
+// health status of the unit.
+package healthcheck
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/worker/uniter/processgroup"
+)
+
+const (
+	// DefaultInterval is the time between runs of a check which does
+	// not specify an interval.
+	DefaultInterval = 30 * time.Second
+
+	// DefaultTimeout is the time after which a check which does not
+	// specify a timeout is considered to have failed.
+	DefaultTimeout = 10 * time.Second
+
+	// MinInterval is the shortest interval allowed between runs of a
+	// check.
+	MinInterval = 5 * time.Second
+)
+
+// CheckType identifies how a health check is performed.
+type CheckType string
+
+const (
+	// HTTPCheck passes if a GET of the check's URL succeeds with a
+	// status code below 400.
+	HTTPCheck CheckType = "http"
+
+	// TCPCheck passes if a connection can be made to the check's
+	// host:port address.
+	TCPCheck CheckType = "tcp"
+
+	// ExecCheck passes if the check's command, run by the shell in the
+	// charm directory, exits with status zero.
+	ExecCheck CheckType = "exec"
+)
+
+var validCheckName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Check describes a health check declared by a charm.
+type Check struct {
+	Name     string        `yaml:"name"`
+	Type     CheckType     `yaml:"type"`
+	Target   string        `yaml:"target"`
+	Interval time.Duration `yaml:"interval,omitempty"`
+	Timeout  time.Duration `yaml:"timeout,omitempty"`
+}
+
+// Validate returns an error if the check is not valid.
+func (c Check) Validate() error {
+	if !validCheckName.MatchString(c.Name) {
+		return errors.NotValidf("health check name %q", c.Name)
+	}
+	switch c.Type {
+	case HTTPCheck:
+		u, err := url.Parse(c.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.NotValidf("health check %q URL %q", c.Name, c.Target)
+		}
+	case TCPCheck:
+		if _, _, err := net.SplitHostPort(c.Target); err != nil {
+			return errors.NotValidf("health check %q address %q", c.Name, c.Target)
+		}
+	case ExecCheck:
+		if strings.TrimSpace(c.Target) == "" {
+			return errors.NotValidf("health check %q with empty command", c.Name)
+		}
+	default:
+		return errors.NotValidf("health check %q type %q", c.Name, c.Type)
+	}
+	if c.Interval != 0 && c.Interval < MinInterval {
+		return errors.NotValidf("health check %q interval %v shorter than %v", c.Name, c.Interval, MinInterval)
+	}
+	if c.Timeout < 0 {
+		return errors.NotValidf("health check %q timeout %v", c.Name, c.Timeout)
+	}
+	if c.Timeout > c.interval() {
+		return errors.NotValidf("health check %q timeout %v longer than its interval", c.Name, c.Timeout)
+	}
+	return nil
+}
+
+// interval returns the time between runs of the check.
+func (c Check) interval() time.Duration {
+	if c.Interval == 0 {
+		return DefaultInterval
+	}
+	return c.Interval
+}
+
+// timeout returns the time after which the check has failed.
+func (c Check) timeout() time.Duration {
+	if c.Timeout == 0 {
+		return DefaultTimeout
+	}
+	return c.Timeout
+}
+
+// Run runs the check, returning an error describing why the check
+// failed. Commands of exec checks are run in the given charm directory.
+func Run(check Check, charmDir string) error {
+	switch check.Type {
+	case HTTPCheck:
+		return runHTTP(check)
+	case TCPCheck:
+		return runTCP(check)
+	case ExecCheck:
+		return runExec(check, charmDir)
+	}
+	return errors.NotValidf("health check type %q", check.Type)
+}
+
+func runHTTP(check Check) error {
+	client := &http.Client{Timeout: check.timeout()}
+	resp, err := client.Get(check.Target)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("GET %s: %s", check.Target, resp.Status)
+	}
+	return nil
+}
+
+func runTCP(check Check) error {
+	conn, err := net.DialTimeout("tcp", check.Target, check.timeout())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return conn.Close()
+}
+
+func runExec(check Check, charmDir string) error {
+	var output bytes.Buffer
+	cmd := exec.Command("/bin/sh", "-c", check.Target)
+	cmd.Dir = charmDir
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	processgroup.Setup(cmd)
+	if err := cmd.Start(); err != nil {
+		return errors.Trace(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			return nil
+		}
+		if out := lastLine(output.String()); out != "" {
+			return errors.Errorf("%v: %s", err, out)
+		}
+		return errors.Trace(err)
+	case <-time.After(check.timeout()):
+		// Kill any processes started by the shell too, as they
+		// keep its output open until they exit.
+		if err := processgroup.Kill(cmd.Process); err != nil {
+			logger.Warningf("cannot kill health check %q: %v", check.Name, err)
+		}
+		<-done
+		return errors.Errorf("timed out after %v", check.timeout())
+	}
+}
+
+// lastLine returns the last non-empty line of the output, which is
+// usually the most useful part of a failing command's output.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
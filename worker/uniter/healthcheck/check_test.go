@@ -0,0 +1,125 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/healthcheck"
+)
+
+type checkSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&checkSuite{})
+
+var validateTests = []struct {
+	check healthcheck.Check
+	err   string
+}{{
+	check: healthcheck.Check{Name: "web", Type: healthcheck.HTTPCheck, Target: "https://localhost:8443/health"},
+}, {
+	check: healthcheck.Check{Name: "db", Type: healthcheck.TCPCheck, Target: "10.0.0.1:5432", Interval: time.Minute, Timeout: time.Minute},
+}, {
+	check: healthcheck.Check{Name: "ready", Type: healthcheck.ExecCheck, Target: "test -f ready"},
+}, {
+	check: healthcheck.Check{Name: "1st", Type: healthcheck.ExecCheck, Target: "true"},
+	err:   `health check name "1st" not valid`,
+}, {
+	check: healthcheck.Check{Name: "web", Type: healthcheck.HTTPCheck, Target: "ftp://localhost/"},
+	err:   `health check "web" URL "ftp://localhost/" not valid`,
+}, {
+	check: healthcheck.Check{Name: "db", Type: healthcheck.TCPCheck, Target: "localhost"},
+	err:   `health check "db" address "localhost" not valid`,
+}, {
+	check: healthcheck.Check{Name: "ready", Type: healthcheck.ExecCheck, Target: " "},
+	err:   `health check "ready" with empty command not valid`,
+}, {
+	check: healthcheck.Check{Name: "ping", Type: "icmp", Target: "localhost"},
+	err:   `health check "ping" type "icmp" not valid`,
+}, {
+	check: healthcheck.Check{Name: "db", Type: healthcheck.TCPCheck, Target: "localhost:80", Interval: time.Second},
+	err:   `health check "db" interval 1s shorter than 5s not valid`,
+}, {
+	check: healthcheck.Check{Name: "db", Type: healthcheck.TCPCheck, Target: "localhost:80", Timeout: time.Minute},
+	err:   `health check "db" timeout 1m0s longer than its interval not valid`,
+}}
+
+func (s *checkSuite) TestValidate(c *gc.C) {
+	for i, t := range validateTests {
+		c.Logf("test %d: %+v", i, t.check)
+		err := t.check.Validate()
+		if t.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, t.err)
+		}
+	}
+}
+
+func (s *checkSuite) TestRunHTTP(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	check := healthcheck.Check{Name: "web", Type: healthcheck.HTTPCheck, Target: server.URL + "/health"}
+	c.Assert(healthcheck.Run(check, c.MkDir()), jc.ErrorIsNil)
+
+	check.Target = server.URL + "/missing"
+	err := healthcheck.Run(check, c.MkDir())
+	c.Assert(err, gc.ErrorMatches, `GET .*/missing: 404 Not Found`)
+}
+
+func (s *checkSuite) TestRunTCP(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	addr := listener.Addr().String()
+
+	check := healthcheck.Check{Name: "db", Type: healthcheck.TCPCheck, Target: addr}
+	c.Assert(healthcheck.Run(check, c.MkDir()), jc.ErrorIsNil)
+
+	listener.Close()
+	c.Assert(healthcheck.Run(check, c.MkDir()), gc.NotNil)
+}
+
+func (s *checkSuite) TestRunExec(c *gc.C) {
+	charmDir := c.MkDir()
+	check := healthcheck.Check{Name: "ready", Type: healthcheck.ExecCheck, Target: "echo ok > ready"}
+	c.Assert(healthcheck.Run(check, charmDir), jc.ErrorIsNil)
+	c.Assert(filepath.Join(charmDir, "ready"), jc.IsNonEmptyFile)
+}
+
+func (s *checkSuite) TestRunExecFailure(c *gc.C) {
+	check := healthcheck.Check{Name: "ready", Type: healthcheck.ExecCheck, Target: "echo starting; echo not ready >&2; exit 3"}
+	err := healthcheck.Run(check, c.MkDir())
+	c.Assert(err, gc.ErrorMatches, "exit status 3: not ready")
+}
+
+func (s *checkSuite) TestRunExecTimeout(c *gc.C) {
+	check := healthcheck.Check{Name: "slow", Type: healthcheck.ExecCheck, Target: "sleep 10", Timeout: 50 * time.Millisecond}
+	err := healthcheck.Run(check, c.MkDir())
+	c.Assert(err, gc.ErrorMatches, "timed out after 50ms")
+}
+
+func (s *checkSuite) TestRunExecTimeoutKillsChildren(c *gc.C) {
+	// The background sleep keeps the command's output open, so the
+	// check only returns promptly if it is killed too.
+	check := healthcheck.Check{Name: "slow", Type: healthcheck.ExecCheck, Target: "sleep 10 & wait", Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	err := healthcheck.Run(check, c.MkDir())
+	c.Assert(err, gc.ErrorMatches, "timed out after 50ms")
+	c.Assert(time.Since(start) < 5*time.Second, jc.IsTrue)
+}
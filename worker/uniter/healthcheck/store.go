@@ -0,0 +1,83 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck
+
+import (
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+// Store holds the health checks declared by a charm in a file, so that
+// they survive restarts of the unit agent.
+type Store struct {
+	path string
+
+	mu sync.Mutex
+}
+
+// NewStore returns a Store keeping the health checks in the file at
+// the given path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// checksFile is the content of the file holding the health checks.
+type checksFile struct {
+	Checks []Check `yaml:"checks"`
+}
+
+// Checks returns the declared health checks, keyed on their names.
+func (s *Store) Checks() (map[string]Check, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+// Update adds or replaces the given checks, and removes the checks
+// with the given names.
+func (s *Store) Update(set []Check, remove []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checks, err := s.read()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range remove {
+		delete(checks, name)
+	}
+	for _, check := range set {
+		if err := check.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		checks[check.Name] = check
+	}
+	var file checksFile
+	for _, check := range checks {
+		file.Checks = append(file.Checks, check)
+	}
+	sort.Sort(byName(file.Checks))
+	return errors.Annotate(utils.WriteYaml(s.path, &file), "cannot write health checks")
+}
+
+func (s *Store) read() (map[string]Check, error) {
+	var file checksFile
+	if err := utils.ReadYaml(s.path, &file); err != nil && !os.IsNotExist(err) {
+		return nil, errors.Annotate(err, "cannot read health checks")
+	}
+	checks := make(map[string]Check)
+	for _, check := range file.Checks {
+		checks[check.Name] = check
+	}
+	return checks, nil
+}
+
+type byName []Check
+
+func (b byName) Len() int           { return len(b) }
+func (b byName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byName) Less(i, j int) bool { return b[i].Name < b[j].Name }
@@ -0,0 +1,56 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck_test
+
+import (
+	"path/filepath"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/healthcheck"
+)
+
+type storeSuite struct {
+	testing.IsolationSuite
+	path string
+}
+
+var _ = gc.Suite(&storeSuite{})
+
+func (s *storeSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.path = filepath.Join(c.MkDir(), "health-checks")
+}
+
+func (s *storeSuite) TestChecksMissingFile(c *gc.C) {
+	checks, err := healthcheck.NewStore(s.path).Checks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, gc.HasLen, 0)
+}
+
+func (s *storeSuite) TestUpdate(c *gc.C) {
+	web := healthcheck.Check{Name: "web", Type: healthcheck.HTTPCheck, Target: "http://localhost/"}
+	db := healthcheck.Check{Name: "db", Type: healthcheck.TCPCheck, Target: "localhost:5432"}
+	store := healthcheck.NewStore(s.path)
+	err := store.Update([]healthcheck.Check{web, db}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	web.Target = "http://localhost:8080/"
+	err = store.Update([]healthcheck.Check{web}, []string{"db"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A new store reads the checks written by the old one.
+	checks, err := healthcheck.NewStore(s.path).Checks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, jc.DeepEquals, map[string]healthcheck.Check{"web": web})
+}
+
+func (s *storeSuite) TestUpdateInvalid(c *gc.C) {
+	store := healthcheck.NewStore(s.path)
+	err := store.Update([]healthcheck.Check{{Name: "web", Type: "smoke"}}, nil)
+	c.Assert(err, gc.ErrorMatches, `health check "web" type "smoke" not valid`)
+	c.Assert(s.path, jc.DoesNotExist)
+}
@@ -0,0 +1,221 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.uniter.healthcheck")
+
+// PollInterval is the time between looks at the declared health
+// checks, to run those that are due.
+const PollInterval = 5 * time.Second
+
+// Reporter records the health of the unit.
+type Reporter interface {
+	SetHealthStatus(health status.Status, info string, data map[string]interface{}) error
+}
+
+// Config holds the dependencies and configuration of a Worker.
+type Config struct {
+	// Store holds the health checks declared by the charm.
+	Store *Store
+
+	// Reporter records the results of the checks.
+	Reporter Reporter
+
+	// Clock is used to schedule the checks.
+	Clock clock.Clock
+
+	// CharmDir is the directory in which the commands of exec checks
+	// are run.
+	CharmDir string
+
+	// RunCheck runs a check in the charm directory. If it is nil, Run
+	// is used.
+	RunCheck func(check Check, charmDir string) error
+}
+
+// Validate returns an error if the config cannot be expected to drive
+// a functional Worker.
+func (config Config) Validate() error {
+	if config.Store == nil {
+		return errors.NotValidf("nil Store")
+	}
+	if config.Reporter == nil {
+		return errors.NotValidf("nil Reporter")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.CharmDir == "" {
+		return errors.NotValidf("empty CharmDir")
+	}
+	return nil
+}
+
+// Worker runs the health checks declared by a charm at their
+// intervals, and reports the unit as healthy when all of them passed
+// at their last run, and unhealthy otherwise.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+
+	lastRun  map[string]time.Time
+	failures map[string]string
+	reported *healthReport
+}
+
+// healthReport is the health last reported for the unit.
+type healthReport struct {
+	status  status.Status
+	message string
+}
+
+// NewWorker returns a Worker running the health checks held by the
+// configured Store.
+func NewWorker(config Config) (*Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if config.RunCheck == nil {
+		config.RunCheck = Run
+	}
+	w := &Worker{
+		config:   config,
+		lastRun:  make(map[string]time.Time),
+		failures: make(map[string]string),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *Worker) loop() error {
+	for {
+		checks, err := w.config.Store.Checks()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := w.runDueChecks(checks); err != nil {
+			return err
+		}
+		err = w.report(checks)
+		if errors.IsNotSupported(err) {
+			logger.Warningf("health checks not reported: %v", err)
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-w.config.Clock.After(PollInterval):
+		}
+	}
+}
+
+// runDueChecks runs the checks which have not been run within their
+// interval, and records which of them failed.
+func (w *Worker) runDueChecks(checks map[string]Check) error {
+	for name := range w.lastRun {
+		if _, ok := checks[name]; !ok {
+			delete(w.lastRun, name)
+			delete(w.failures, name)
+		}
+	}
+	type result struct {
+		name string
+		err  error
+	}
+	now := w.config.Clock.Now()
+	results := make(chan result, len(checks))
+	running := 0
+	for name, check := range checks {
+		if last, ok := w.lastRun[name]; ok && now.Sub(last) < check.interval() {
+			continue
+		}
+		w.lastRun[name] = now
+		running++
+		go func(check Check) {
+			results <- result{check.Name, w.config.RunCheck(check, w.config.CharmDir)}
+		}(check)
+	}
+	for ; running > 0; running-- {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case r := <-results:
+			if r.err != nil {
+				logger.Debugf("health check %q failed: %v", r.name, r.err)
+				w.failures[r.name] = r.err.Error()
+			} else {
+				delete(w.failures, r.name)
+			}
+		}
+	}
+	return nil
+}
+
+// report records the health of the unit if it has changed since it
+// was last reported. Nothing is reported until the charm declares a
+// check; if the charm then removes all of its checks, the health of
+// the unit becomes unknown.
+func (w *Worker) report(checks map[string]Check) error {
+	var health healthReport
+	switch {
+	case len(checks) == 0:
+		if w.reported == nil {
+			return nil
+		}
+		health = healthReport{status.Unknown, "no health checks"}
+	case len(w.failures) == 0:
+		health = healthReport{status: status.Healthy}
+	default:
+		var names []string
+		for name := range w.failures {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var messages []string
+		for _, name := range names {
+			messages = append(messages, fmt.Sprintf("%s: %s", name, w.failures[name]))
+		}
+		health = healthReport{status.Unhealthy, strings.Join(messages, "; ")}
+	}
+	if w.reported != nil && *w.reported == health {
+		return nil
+	}
+	if err := w.config.Reporter.SetHealthStatus(health.status, health.message, nil); err != nil {
+		return errors.Trace(err)
+	}
+	w.reported = &health
+	return nil
+}
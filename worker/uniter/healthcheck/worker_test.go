@@ -0,0 +1,188 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck_test
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/healthcheck"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type workerSuite struct {
+	testing.IsolationSuite
+
+	store    *healthcheck.Store
+	clock    *testing.Clock
+	reporter *fakeReporter
+
+	mu       sync.Mutex
+	failures map[string]error
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.store = healthcheck.NewStore(filepath.Join(c.MkDir(), "health-checks"))
+	s.clock = testing.NewClock(time.Time{})
+	s.reporter = &fakeReporter{reports: make(chan report, 10)}
+	s.failures = make(map[string]error)
+}
+
+func (s *workerSuite) setFailure(name string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[name] = err
+}
+
+func (s *workerSuite) runCheck(check healthcheck.Check, charmDir string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failures[check.Name]
+}
+
+func (s *workerSuite) startWorker(c *gc.C) *healthcheck.Worker {
+	w, err := healthcheck.NewWorker(healthcheck.Config{
+		Store:    s.store,
+		Reporter: s.reporter,
+		Clock:    s.clock,
+		CharmDir: "/charm",
+		RunCheck: s.runCheck,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
+func (s *workerSuite) setChecks(c *gc.C, names ...string) {
+	checks, err := s.store.Checks()
+	c.Assert(err, jc.ErrorIsNil)
+	var remove []string
+	for name := range checks {
+		remove = append(remove, name)
+	}
+	var set []healthcheck.Check
+	for _, name := range names {
+		set = append(set, healthcheck.Check{
+			Name:     name,
+			Type:     healthcheck.ExecCheck,
+			Target:   "true",
+			Interval: healthcheck.MinInterval,
+			Timeout:  time.Second,
+		})
+	}
+	err = s.store.Update(set, remove)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *workerSuite) poll(c *gc.C) {
+	err := s.clock.WaitAdvance(healthcheck.PollInterval, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *workerSuite) TestValidateConfig(c *gc.C) {
+	_, err := healthcheck.NewWorker(healthcheck.Config{
+		Reporter: s.reporter,
+		Clock:    s.clock,
+		CharmDir: "/charm",
+	})
+	c.Assert(err, gc.ErrorMatches, "nil Store not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *workerSuite) TestNoChecksNotReported(c *gc.C) {
+	w := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+	s.poll(c)
+	s.reporter.checkNoReport(c)
+}
+
+func (s *workerSuite) TestReportsHealth(c *gc.C) {
+	s.setChecks(c, "web", "db")
+	s.setFailure("web", errors.New("connection refused"))
+	s.setFailure("db", errors.New("timed out after 1s"))
+	w := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+	s.reporter.checkReport(c, status.Unhealthy, "db: timed out after 1s; web: connection refused")
+
+	s.setFailure("db", nil)
+	s.poll(c)
+	s.reporter.checkReport(c, status.Unhealthy, "web: connection refused")
+
+	s.setFailure("web", nil)
+	s.poll(c)
+	s.reporter.checkReport(c, status.Healthy, "")
+
+	// Unchanged health is not reported again.
+	s.poll(c)
+	s.reporter.checkNoReport(c)
+
+	s.setChecks(c)
+	s.poll(c)
+	s.reporter.checkReport(c, status.Unknown, "no health checks")
+}
+
+func (s *workerSuite) TestRemovedCheckForgotten(c *gc.C) {
+	s.setChecks(c, "web", "db")
+	s.setFailure("web", errors.New("connection refused"))
+	w := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+	s.reporter.checkReport(c, status.Unhealthy, "web: connection refused")
+
+	s.setChecks(c, "db")
+	s.poll(c)
+	s.reporter.checkReport(c, status.Healthy, "")
+}
+
+func (s *workerSuite) TestNotSupportedStopsWorker(c *gc.C) {
+	s.setChecks(c, "web")
+	s.reporter.err = errors.NotSupportedf("health checks")
+	w := s.startWorker(c)
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type report struct {
+	status  status.Status
+	message string
+}
+
+type fakeReporter struct {
+	err     error
+	reports chan report
+}
+
+func (r *fakeReporter) SetHealthStatus(health status.Status, info string, data map[string]interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.reports <- report{health, info}
+	return nil
+}
+
+func (r *fakeReporter) checkReport(c *gc.C, health status.Status, message string) {
+	select {
+	case got := <-r.reports:
+		c.Assert(got, gc.Equals, report{health, message})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for health report")
+	}
+}
+
+func (r *fakeReporter) checkNoReport(c *gc.C) {
+	select {
+	case got := <-r.reports:
+		c.Fatalf("unexpected health report %+v", got)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
@@ -16,6 +16,10 @@ const (
 	LeaderElected         hooks.Kind = "leader-elected"
 	LeaderDeposed         hooks.Kind = "leader-deposed"
 	LeaderSettingsChanged hooks.Kind = "leader-settings-changed"
+
+	// SecretRotated is run when a secret granted to the unit by
+	// another application has been given a new value.
+	SecretRotated hooks.Kind = "secret-rotated"
 )
 
 // Info holds details required to execute a hook. Not all fields are
@@ -37,6 +41,12 @@ type Info struct {
 
 	// StorageId is the ID of the storage instance relevant to the hook.
 	StorageId string `yaml:"storage-id,omitempty"`
+
+	// SecretId is the ID of the secret relevant to the hook, and
+	// SecretRevision the revision of the secret's value that caused
+	// the hook to run. They are only set when Kind is SecretRotated.
+	SecretId       string `yaml:"secret-id,omitempty"`
+	SecretRevision int    `yaml:"secret-revision,omitempty"`
 }
 
 // Validate returns an error if the info is not valid.
@@ -60,6 +70,11 @@ func (hi Info) Validate() error {
 	// TODO(fwereade): define these in charm/hooks...
 	case LeaderElected, LeaderDeposed, LeaderSettingsChanged:
 		return nil
+	case SecretRotated:
+		if hi.SecretId == "" {
+			return fmt.Errorf("%q hook requires a secret id", hi.Kind)
+		}
+		return nil
 	}
 	return fmt.Errorf("unknown hook kind %q", hi.Kind)
 }
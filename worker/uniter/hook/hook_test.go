@@ -50,6 +50,8 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.StorageAttached}, `invalid storage ID ""`},
 	{hook.Info{Kind: hooks.StorageAttached, StorageId: "data/0"}, ""},
 	{hook.Info{Kind: hooks.StorageDetaching, StorageId: "data/0"}, ""},
+	{hook.Info{Kind: hook.SecretRotated}, `"secret-rotated" hook requires a secret id`},
+	{hook.Info{Kind: hook.SecretRotated, SecretId: "secret-0", SecretRevision: 2}, ""},
 }
 
 func (s *InfoSuite) TestValidate(c *gc.C) {
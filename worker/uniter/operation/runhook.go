@@ -104,6 +104,17 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	case cause == context.ErrReboot:
 		err = ErrNeedsReboot
 	case err == nil:
+	case context.IsHookTimeoutError(cause):
+		logger.Errorf("hook %q timed out: %v", rh.name, err)
+		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
+		// Record the timeout, so that the hook's failure is reported
+		// as such, and retried according to the timeout policy.
+		return stateChange{
+			Kind:         RunHook,
+			Step:         Pending,
+			Hook:         &rh.info,
+			HookTimedOut: true,
+		}.apply(state), ErrHookFailed
 	default:
 		logger.Errorf("hook %q failed: %v", rh.name, err)
 		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
@@ -236,6 +247,13 @@ func (rh *runHook) Commit(state State) (*State, error) {
 		newState.Started = true
 	case hooks.Stop:
 		newState.Stopped = true
+	case hook.SecretRotated:
+		revisions := make(map[string]int)
+		for id, revision := range state.SecretRevisions {
+			revisions[id] = revision
+		}
+		revisions[rh.info.SecretId] = rh.info.SecretRevision
+		newState.SecretRevisions = revisions
 	}
 
 	return newState, nil
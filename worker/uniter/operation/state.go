@@ -85,6 +85,10 @@ type State struct {
 	// upgrade is complete (instead of running an upgrade-charm hook).
 	Hook *hook.Info `yaml:"hook,omitempty"`
 
+	// HookTimedOut indicates that the hook of a RunHook operation failed
+	// because it ran for longer than its timeout.
+	HookTimedOut bool `yaml:"hook-timed-out,omitempty"`
+
 	// ActionId holds action information relevant to the current operation. If
 	// Kind is Continue, it holds the last action that was executed; if Kind is
 	// RunAction, it holds the running action.
@@ -93,6 +97,11 @@ type State struct {
 	// Charm describes the charm being deployed by an Install or Upgrade
 	// operation, and is otherwise blank.
 	CharmURL *charm.URL `yaml:"charm,omitempty"`
+
+	// SecretRevisions holds the revision of each secret granted to the
+	// unit, keyed on secret id, for which the unit has most recently
+	// run (or skipped) the secret-rotated hook.
+	SecretRevisions map[string]int `yaml:"secret-revisions,omitempty"`
 }
 
 // validate returns an error if the state violates expectations.
@@ -146,6 +155,9 @@ func (st State) validate() (err error) {
 	default:
 		return errors.Errorf("unknown operation %q", st.Kind)
 	}
+	if st.HookTimedOut && st.Kind != RunHook {
+		return errors.New("unexpected hook timeout")
+	}
 	switch st.Step {
 	case Queued, Pending, Done:
 	default:
@@ -165,6 +177,7 @@ type stateChange struct {
 	ActionId        *string
 	CharmURL        *charm.URL
 	HasRunStatusSet bool
+	HookTimedOut    bool
 }
 
 func (change stateChange) apply(state State) *State {
@@ -174,6 +187,7 @@ func (change stateChange) apply(state State) *State {
 	state.ActionId = change.ActionId
 	state.CharmURL = change.CharmURL
 	state.StatusSet = state.StatusSet || change.HasRunStatusSet
+	state.HookTimedOut = change.HookTimedOut
 	return &state
 }
 
@@ -137,6 +137,13 @@ var stateTests = []struct {
 			Step: operation.Pending,
 			Hook: relhook,
 		},
+	}, {
+		st: operation.State{
+			Kind:         operation.RunHook,
+			Step:         operation.Pending,
+			Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+			HookTimedOut: true,
+		},
 	},
 	// Upgrade operation.
 	{
@@ -166,6 +173,15 @@ var stateTests = []struct {
 			Hook:     relhook,
 			CharmURL: stcurl,
 		},
+	}, {
+		st: operation.State{
+			Kind:         operation.Upgrade,
+			Step:         operation.Pending,
+			Hook:         relhook,
+			CharmURL:     stcurl,
+			HookTimedOut: true,
+		},
+		err: `unexpected hook timeout`,
 	},
 	// Continue operation.
 	{
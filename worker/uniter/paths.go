@@ -103,6 +103,9 @@ type StatePaths struct {
 	// MetricsSpoolDir acts as temporary storage for metrics being sent from
 	// the uniter to state.
 	MetricsSpoolDir string
+
+	// HealthChecksFile holds the health checks declared by the charm.
+	HealthChecksFile string
 }
 
 // NewPaths returns the set of filesystem paths that the supplied unit should
@@ -145,14 +148,15 @@ func NewWorkerPaths(dataDir string, unitTag names.UnitTag, worker string) Paths
 			JujucServerSocket: socket("agent", true),
 		},
 		State: StatePaths{
-			BaseDir:         baseDir,
-			CharmDir:        join(baseDir, "charm"),
-			OperationsFile:  join(stateDir, "uniter"),
-			RelationsDir:    join(stateDir, "relations"),
-			BundlesDir:      join(stateDir, "bundles"),
-			DeployerDir:     join(stateDir, "deployer"),
-			StorageDir:      join(stateDir, "storage"),
-			MetricsSpoolDir: join(stateDir, "spool", "metrics"),
+			BaseDir:          baseDir,
+			CharmDir:         join(baseDir, "charm"),
+			OperationsFile:   join(stateDir, "uniter"),
+			RelationsDir:     join(stateDir, "relations"),
+			BundlesDir:       join(stateDir, "bundles"),
+			DeployerDir:      join(stateDir, "deployer"),
+			StorageDir:       join(stateDir, "storage"),
+			MetricsSpoolDir:  join(stateDir, "spool", "metrics"),
+			HealthChecksFile: join(stateDir, "health-checks"),
 		},
 	}
 }
@@ -44,14 +44,15 @@ func (s *PathsSuite) TestWindows(c *gc.C) {
 			JujucServerSocket: `\\.\pipe\unit-some-application-323-agent`,
 		},
 		State: uniter.StatePaths{
-			BaseDir:         relAgent(),
-			CharmDir:        relAgent("charm"),
-			OperationsFile:  relAgent("state", "uniter"),
-			RelationsDir:    relAgent("state", "relations"),
-			BundlesDir:      relAgent("state", "bundles"),
-			DeployerDir:     relAgent("state", "deployer"),
-			StorageDir:      relAgent("state", "storage"),
-			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			BaseDir:          relAgent(),
+			CharmDir:         relAgent("charm"),
+			OperationsFile:   relAgent("state", "uniter"),
+			RelationsDir:     relAgent("state", "relations"),
+			BundlesDir:       relAgent("state", "bundles"),
+			DeployerDir:      relAgent("state", "deployer"),
+			StorageDir:       relAgent("state", "storage"),
+			MetricsSpoolDir:  relAgent("state", "spool", "metrics"),
+			HealthChecksFile: relAgent("state", "health-checks"),
 		},
 	})
 }
@@ -73,14 +74,15 @@ func (s *PathsSuite) TestWorkerPathsWindows(c *gc.C) {
 			JujucServerSocket: `\\.\pipe\unit-some-application-323-some-worker-agent`,
 		},
 		State: uniter.StatePaths{
-			BaseDir:         relAgent(),
-			CharmDir:        relAgent("charm"),
-			OperationsFile:  relAgent("state", "uniter"),
-			RelationsDir:    relAgent("state", "relations"),
-			BundlesDir:      relAgent("state", "bundles"),
-			DeployerDir:     relAgent("state", "deployer"),
-			StorageDir:      relAgent("state", "storage"),
-			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			BaseDir:          relAgent(),
+			CharmDir:         relAgent("charm"),
+			OperationsFile:   relAgent("state", "uniter"),
+			RelationsDir:     relAgent("state", "relations"),
+			BundlesDir:       relAgent("state", "bundles"),
+			DeployerDir:      relAgent("state", "deployer"),
+			StorageDir:       relAgent("state", "storage"),
+			MetricsSpoolDir:  relAgent("state", "spool", "metrics"),
+			HealthChecksFile: relAgent("state", "health-checks"),
 		},
 	})
 }
@@ -101,14 +103,15 @@ func (s *PathsSuite) TestOther(c *gc.C) {
 			JujucServerSocket: "@" + relAgent("agent.socket"),
 		},
 		State: uniter.StatePaths{
-			BaseDir:         relAgent(),
-			CharmDir:        relAgent("charm"),
-			OperationsFile:  relAgent("state", "uniter"),
-			RelationsDir:    relAgent("state", "relations"),
-			BundlesDir:      relAgent("state", "bundles"),
-			DeployerDir:     relAgent("state", "deployer"),
-			StorageDir:      relAgent("state", "storage"),
-			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			BaseDir:          relAgent(),
+			CharmDir:         relAgent("charm"),
+			OperationsFile:   relAgent("state", "uniter"),
+			RelationsDir:     relAgent("state", "relations"),
+			BundlesDir:       relAgent("state", "bundles"),
+			DeployerDir:      relAgent("state", "deployer"),
+			StorageDir:       relAgent("state", "storage"),
+			MetricsSpoolDir:  relAgent("state", "spool", "metrics"),
+			HealthChecksFile: relAgent("state", "health-checks"),
 		},
 	})
 }
@@ -130,14 +133,15 @@ func (s *PathsSuite) TestWorkerPaths(c *gc.C) {
 			JujucServerSocket: "@" + relAgent(worker+"-agent.socket"),
 		},
 		State: uniter.StatePaths{
-			BaseDir:         relAgent(),
-			CharmDir:        relAgent("charm"),
-			OperationsFile:  relAgent("state", "uniter"),
-			RelationsDir:    relAgent("state", "relations"),
-			BundlesDir:      relAgent("state", "bundles"),
-			DeployerDir:     relAgent("state", "deployer"),
-			StorageDir:      relAgent("state", "storage"),
-			MetricsSpoolDir: relAgent("state", "spool", "metrics"),
+			BaseDir:          relAgent(),
+			CharmDir:         relAgent("charm"),
+			OperationsFile:   relAgent("state", "uniter"),
+			RelationsDir:     relAgent("state", "relations"),
+			BundlesDir:       relAgent("state", "bundles"),
+			DeployerDir:      relAgent("state", "deployer"),
+			StorageDir:       relAgent("state", "storage"),
+			MetricsSpoolDir:  relAgent("state", "spool", "metrics"),
+			HealthChecksFile: relAgent("state", "health-checks"),
 		},
 	})
 }
@@ -0,0 +1,7 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package processgroup starts commands such that they can be killed
+// along with any processes they start, as is needed for hooks and
+// health checks that time out.
+package processgroup
@@ -0,0 +1,24 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package processgroup
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// Setup makes the command run in a process group of its own, so that
+// it can be killed along with any processes it starts. It must be
+// called before the command is started.
+func Setup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// Kill kills the process group led by the given process.
+func Kill(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,24 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package processgroup
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// Setup does nothing on windows, where the processes started by a
+// command are found from the process tree when it is killed.
+func Setup(cmd *exec.Cmd) {}
+
+// Kill kills the given process along with the processes it started.
+func Kill(p *os.Process) error {
+	err := exec.Command("taskkill", "/F", "/T", "/PID", strconv.Itoa(p.Pid)).Run()
+	if err != nil {
+		// Fall back to killing the process itself.
+		return p.Kill()
+	}
+	return nil
+}
@@ -198,6 +198,8 @@ type mockUnit struct {
 	storageWatcher        *mockStringsWatcher
 	actionWatcher         *mockStringsWatcher
 	relationsWatcher      *mockStringsWatcher
+	secretsWatcher        *mockNotifyWatcher
+	secretRevisions       map[string]int
 }
 
 func (u *mockUnit) Life() params.Life {
@@ -248,6 +250,14 @@ func (u *mockUnit) WatchRelations() (watcher.StringsWatcher, error) {
 	return u.relationsWatcher, nil
 }
 
+func (u *mockUnit) WatchSecrets() (watcher.NotifyWatcher, error) {
+	return u.secretsWatcher, nil
+}
+
+func (u *mockUnit) SecretRevisions() (map[string]int, error) {
+	return u.secretRevisions, nil
+}
+
 type mockApplication struct {
 	tag                   names.ApplicationTag
 	life                  params.Life
@@ -256,6 +266,7 @@ type mockApplication struct {
 	forceUpgrade          bool
 	applicationWatcher    *mockNotifyWatcher
 	leaderSettingsWatcher *mockNotifyWatcher
+	updateStatusInterval  time.Duration
 }
 
 func (s *mockApplication) CharmModifiedVersion() (int, error) {
@@ -286,6 +297,13 @@ func (s *mockApplication) WatchLeadershipSettings() (watcher.NotifyWatcher, erro
 	return s.leaderSettingsWatcher, nil
 }
 
+func (s *mockApplication) UpdateStatusHookInterval() (time.Duration, error) {
+	if s.updateStatusInterval == 0 {
+		return 5 * time.Minute, nil
+	}
+	return s.updateStatusInterval, nil
+}
+
 type mockRelation struct {
 	id        int
 	life      params.Life
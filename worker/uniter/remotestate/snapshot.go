@@ -71,6 +71,11 @@ type Snapshot struct {
 
 	// Series is the current series running on the unit
 	Series string
+
+	// SecretRevisions contains the current revision of each
+	// secret granted to the unit by another application, keyed
+	// by secret id.
+	SecretRevisions map[string]int
 }
 
 type RelationSnapshot struct {
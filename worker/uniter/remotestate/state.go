@@ -45,6 +45,12 @@ type Unit interface {
 	// WatchRelation returns a watcher that fires when relations
 	// relevant for this unit change.
 	WatchRelations() (watcher.StringsWatcher, error)
+	// WatchSecrets returns a watcher that fires when secrets that
+	// may be granted to this unit change.
+	WatchSecrets() (watcher.NotifyWatcher, error)
+	// SecretRevisions returns the current revision of each secret
+	// granted to this unit by another application.
+	SecretRevisions() (map[string]int, error)
 }
 
 type Application interface {
@@ -64,6 +70,9 @@ type Application interface {
 	// WatchLeadershipSettings returns a watcher that fires when the leadership
 	// settings for this service change.
 	WatchLeadershipSettings() (watcher.NotifyWatcher, error)
+	// UpdateStatusHookInterval returns how often the update-status hook
+	// runs for the service's units.
+	UpdateStatusHookInterval() (time.Duration, error)
 }
 
 type Relation interface {
@@ -5,6 +5,7 @@ package remotestate
 
 import (
 	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -33,6 +34,7 @@ type RemoteStateWatcher struct {
 	storageAttachmentChanges  chan storageAttachmentChange
 	leadershipTracker         leadership.Tracker
 	updateStatusChannel       UpdateStatusTimerFunc
+	updateStatusInterval      time.Duration
 	commandChannel            <-chan string
 	retryHookChannel          <-chan struct{}
 
@@ -127,6 +129,12 @@ func (w *RemoteStateWatcher) Snapshot() Snapshot {
 	copy(snapshot.Actions, w.current.Actions)
 	snapshot.Commands = make([]string, len(w.current.Commands))
 	copy(snapshot.Commands, w.current.Commands)
+	if w.current.SecretRevisions != nil {
+		snapshot.SecretRevisions = make(map[string]int)
+		for id, revision := range w.current.SecretRevisions {
+			snapshot.SecretRevisions[id] = revision
+		}
+	}
 	return snapshot
 }
 
@@ -257,6 +265,22 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 	}
 	requiredEvents++
 
+	var seenSecretsChange bool
+	var secretsChanges watcher.NotifyChannel
+	secretsw, err := w.unit.WatchSecrets()
+	if errors.IsNotSupported(err) {
+		// The controller predates secrets, so none can be granted.
+		logger.Debugf("secrets not supported by the controller")
+	} else if err != nil {
+		return errors.Trace(err)
+	} else {
+		if err := w.catacomb.Add(secretsw); err != nil {
+			return errors.Trace(err)
+		}
+		secretsChanges = secretsw.Changes()
+		requiredEvents++
+	}
+
 	var seenLeadershipChange bool
 	// There's no watcher for this per se; we wait on a channel
 	// returned by the leadership tracker.
@@ -301,7 +325,9 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 	}
 
 	// TODO(wallyworld) - listen for changes to this value
-	updateStatusInterval, err := w.st.UpdateStatusHookInterval()
+	// The model's interval is used until the application's interval,
+	// which takes precedence, is read when the application changes.
+	w.updateStatusInterval, err = w.st.UpdateStatusHookInterval()
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -391,6 +417,16 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 			}
 			observedEvent(&seenStorageChange)
 
+		case _, ok := <-secretsChanges:
+			logger.Debugf("got secrets change: ok=%t", ok)
+			if !ok {
+				return errors.New("secrets watcher closed")
+			}
+			if err := w.secretsChanged(); err != nil {
+				return errors.Trace(err)
+			}
+			observedEvent(&seenSecretsChange)
+
 		case <-waitMinion:
 			logger.Debugf("got leadership change: minion")
 			if err := w.leadershipChanged(false); err != nil {
@@ -419,7 +455,7 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 				return errors.Trace(err)
 			}
 
-		case <-w.updateStatusChannel(updateStatusInterval).After():
+		case <-w.updateStatusChannel(w.updateStatusInterval).After():
 			logger.Debugf("update status timer triggered")
 			if err := w.updateStatusChanged(); err != nil {
 				return errors.Trace(err)
@@ -499,6 +535,14 @@ func (w *RemoteStateWatcher) applicationChanged() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// The application may override the model's update-status hook
+	// interval; older controllers only support the model's interval.
+	interval, err := w.service.UpdateStatusHookInterval()
+	if err == nil {
+		w.updateStatusInterval = interval
+	} else if !errors.IsNotSupported(err) {
+		return errors.Trace(err)
+	}
 	w.mu.Lock()
 	w.current.CharmURL = url
 	w.current.ForceCharmUpgrade = force
@@ -528,6 +572,17 @@ func (w *RemoteStateWatcher) leaderSettingsChanged() error {
 	return nil
 }
 
+func (w *RemoteStateWatcher) secretsChanged() error {
+	revisions, err := w.unit.SecretRevisions()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.mu.Lock()
+	w.current.SecretRevisions = revisions
+	w.mu.Unlock()
+	return nil
+}
+
 func (w *RemoteStateWatcher) leadershipChanged(isLeader bool) error {
 	w.mu.Lock()
 	w.current.Leader = isLeader
@@ -4,6 +4,7 @@
 package remotestate_test
 
 import (
+	"sync"
 	"time"
 
 	"github.com/juju/testing"
@@ -25,6 +26,9 @@ type WatcherSuite struct {
 	leadership *mockLeadershipTracker
 	watcher    *remotestate.RemoteStateWatcher
 	clock      *testing.Clock
+
+	mu                 sync.Mutex
+	updateStatusWaited time.Duration
 }
 
 // Duration is arbitrary, we'll trigger the ticker
@@ -53,6 +57,7 @@ func (s *WatcherSuite) SetUpTest(c *gc.C) {
 			storageWatcher:        newMockStringsWatcher(),
 			actionWatcher:         newMockStringsWatcher(),
 			relationsWatcher:      newMockStringsWatcher(),
+			secretsWatcher:        newMockNotifyWatcher(),
 		},
 		relations:                 make(map[names.RelationTag]*mockRelation),
 		storageAttachment:         make(map[params.StorageAttachmentId]params.StorageAttachment),
@@ -68,6 +73,9 @@ func (s *WatcherSuite) SetUpTest(c *gc.C) {
 
 	s.clock = testing.NewClock(time.Now())
 	statusTicker := func(wait time.Duration) remotestate.Waiter {
+		s.mu.Lock()
+		s.updateStatusWaited = wait
+		s.mu.Unlock()
 		return dummyWaiter{s.clock.After(statusTickDuration)}
 	}
 
@@ -118,6 +126,7 @@ func (s *WatcherSuite) TestInitialSignal(c *gc.C) {
 	s.st.unit.application.applicationWatcher.changes <- struct{}{}
 	s.st.unit.application.leaderSettingsWatcher.changes <- struct{}{}
 	s.st.unit.relationsWatcher.changes <- []string{}
+	s.st.unit.secretsWatcher.changes <- struct{}{}
 	s.leadership.claimTicket.ch <- struct{}{}
 	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
 }
@@ -131,6 +140,7 @@ func signalAll(st *mockState, l *mockLeadershipTracker) {
 	st.unit.application.applicationWatcher.changes <- struct{}{}
 	st.unit.application.leaderSettingsWatcher.changes <- struct{}{}
 	st.unit.relationsWatcher.changes <- []string{}
+	st.unit.secretsWatcher.changes <- struct{}{}
 	l.claimTicket.ch <- struct{}{}
 }
 
@@ -206,6 +216,17 @@ func (s *WatcherSuite) TestRemoteStateChanged(c *gc.C) {
 	assertOneChange()
 }
 
+func (s *WatcherSuite) TestSecretsChanged(c *gc.C) {
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().SecretRevisions, gc.HasLen, 0)
+
+	s.st.unit.secretRevisions = map[string]int{"secret-0": 2}
+	s.st.unit.secretsWatcher.changes <- struct{}{}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().SecretRevisions, jc.DeepEquals, map[string]int{"secret-0": 2})
+}
+
 func (s *WatcherSuite) TestActionsReceived(c *gc.C) {
 	signalAll(s.st, s.leadership)
 	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
@@ -591,6 +612,17 @@ func (s *WatcherSuite) TestUpdateStatusTicker(c *gc.C) {
 	c.Assert(s.watcher.Snapshot().UpdateStatusVersion, gc.Equals, initial.UpdateStatusVersion+2)
 }
 
+func (s *WatcherSuite) TestUpdateStatusTickerApplicationInterval(c *gc.C) {
+	s.st.unit.application.updateStatusInterval = 15 * time.Minute
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+
+	s.waitAlarmsStable(c)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Assert(s.updateStatusWaited, gc.Equals, 15*time.Minute)
+}
+
 // waitAlarmsStable is used to wait until the remote watcher's loop has
 // stopped churning (at least for testing.ShortWait), so that we can
 // then Advance the clock with some confidence that the SUT really is
@@ -16,16 +16,18 @@ import (
 
 // ResolverConfig defines configuration for the uniter resolver.
 type ResolverConfig struct {
-	ClearResolved       func() error
-	ReportHookError     func(hook.Info) error
-	ShouldRetryHooks    bool
-	StartRetryHookTimer func()
-	StopRetryHookTimer  func()
-	Leadership          resolver.Resolver
-	Actions             resolver.Resolver
-	Relations           resolver.Resolver
-	Storage             resolver.Resolver
-	Commands            resolver.Resolver
+	ClearResolved            func() error
+	ReportHookError          func(info hook.Info, timedOut bool) error
+	ShouldRetryHooks         bool
+	ShouldRetryTimedOutHooks bool
+	StartRetryHookTimer      func()
+	StopRetryHookTimer       func()
+	Leadership               resolver.Resolver
+	Actions                  resolver.Resolver
+	Relations                resolver.Resolver
+	Secrets                  resolver.Resolver
+	Storage                  resolver.Resolver
+	Commands                 resolver.Resolver
 }
 
 type uniterResolver struct {
@@ -153,7 +155,7 @@ func (s *uniterResolver) nextOpHookError(
 ) (operation.Operation, error) {
 
 	// Report the hook error.
-	if err := s.config.ReportHookError(*localState.Hook); err != nil {
+	if err := s.config.ReportHookError(*localState.Hook, localState.HookTimedOut); err != nil {
 		return nil, errors.Trace(err)
 	}
 
@@ -174,7 +176,7 @@ func (s *uniterResolver) nextOpHookError(
 			s.retryHookTimerStarted = false
 			return opFactory.NewRunHook(*localState.Hook)
 		}
-		if !s.retryHookTimerStarted && s.config.ShouldRetryHooks {
+		if !s.retryHookTimerStarted && s.shouldRetryHook(localState) {
 			// We haven't yet started a retry timer, so start one
 			// now. If we retry and fail, retryHookTimerStarted is
 			// cleared so that we'll still start it again.
@@ -270,6 +272,11 @@ func (s *uniterResolver) nextOp(
 		return op, err
 	}
 
+	op, err = s.config.Secrets.NextOp(localState, remoteState, opFactory)
+	if errors.Cause(err) != resolver.ErrNoOperation {
+		return op, err
+	}
+
 	// UpdateStatus hook runs if nothing else needs to.
 	if localState.UpdateStatusVersion != remoteState.UpdateStatusVersion {
 		return opFactory.NewRunHook(hook.Info{Kind: hooks.UpdateStatus})
@@ -277,3 +284,13 @@ func (s *uniterResolver) nextOp(
 
 	return nil, resolver.ErrNoOperation
 }
+
+// shouldRetryHook returns whether the failed hook should be retried
+// automatically. Hooks that timed out may be excluded from retries by
+// the application's hook timeout policy.
+func (s *uniterResolver) shouldRetryHook(localState resolver.LocalState) bool {
+	if !s.config.ShouldRetryHooks {
+		return false
+	}
+	return !localState.HookTimedOut || s.config.ShouldRetryTimedOutHooks
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/worker/uniter/relation"
 	"github.com/juju/juju/worker/uniter/remotestate"
 	"github.com/juju/juju/worker/uniter/resolver"
+	"github.com/juju/juju/worker/uniter/secrets"
 	"github.com/juju/juju/worker/uniter/storage"
 )
 
@@ -35,6 +36,7 @@ type resolverSuite struct {
 
 	clearResolved   func() error
 	reportHookError func(hook.Info) error
+	hookTimedOut    bool
 }
 
 var _ = gc.Suite(&resolverSuite{})
@@ -60,16 +62,21 @@ func (s *resolverSuite) SetUpTest(c *gc.C) {
 	}
 
 	s.resolverConfig = uniter.ResolverConfig{
-		ClearResolved:       func() error { return s.clearResolved() },
-		ReportHookError:     func(info hook.Info) error { return s.reportHookError(info) },
-		StartRetryHookTimer: func() { s.stub.AddCall("StartRetryHookTimer") },
-		StopRetryHookTimer:  func() { s.stub.AddCall("StopRetryHookTimer") },
-		ShouldRetryHooks:    true,
-		Leadership:          leadership.NewResolver(),
-		Actions:             uniteractions.NewResolver(),
-		Relations:           relation.NewRelationsResolver(&dummyRelations{}),
-		Storage:             storage.NewResolver(attachments),
-		Commands:            nopResolver{},
+		ClearResolved: func() error { return s.clearResolved() },
+		ReportHookError: func(info hook.Info, timedOut bool) error {
+			s.hookTimedOut = timedOut
+			return s.reportHookError(info)
+		},
+		StartRetryHookTimer:      func() { s.stub.AddCall("StartRetryHookTimer") },
+		StopRetryHookTimer:       func() { s.stub.AddCall("StopRetryHookTimer") },
+		ShouldRetryHooks:         true,
+		ShouldRetryTimedOutHooks: true,
+		Leadership:               leadership.NewResolver(),
+		Actions:                  uniteractions.NewResolver(),
+		Relations:                relation.NewRelationsResolver(&dummyRelations{}),
+		Secrets:                  secrets.NewResolver(),
+		Storage:                  storage.NewResolver(attachments),
+		Commands:                 nopResolver{},
 	}
 
 	s.resolver = uniter.NewUniterResolver(s.resolverConfig)
@@ -164,6 +171,55 @@ func (s *resolverSuite) TestHookErrorDoesNotStartRetryTimerIfShouldRetryFalse(c
 	s.stub.CheckNoCalls(c)
 }
 
+func (s *resolverSuite) TestHookTimeoutStartRetryTimer(c *gc.C) {
+	s.reportHookError = func(hook.Info) error { return nil }
+	localState := resolver.LocalState{
+		CharmModifiedVersion: s.charmModifiedVersion,
+		CharmURL:             s.charmURL,
+		State: operation.State{
+			Kind:         operation.RunHook,
+			Step:         operation.Pending,
+			Installed:    true,
+			Started:      true,
+			Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+			HookTimedOut: true,
+		},
+	}
+	_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	c.Assert(s.hookTimedOut, jc.IsTrue)
+	s.stub.CheckCallNames(c, "StartRetryHookTimer")
+}
+
+func (s *resolverSuite) TestHookTimeoutDoesNotStartRetryTimerIfShouldRetryTimedOutFalse(c *gc.C) {
+	s.resolverConfig.ShouldRetryTimedOutHooks = false
+	s.resolver = uniter.NewUniterResolver(s.resolverConfig)
+	s.reportHookError = func(hook.Info) error { return nil }
+	localState := resolver.LocalState{
+		CharmModifiedVersion: s.charmModifiedVersion,
+		CharmURL:             s.charmURL,
+		State: operation.State{
+			Kind:         operation.RunHook,
+			Step:         operation.Pending,
+			Installed:    true,
+			Started:      true,
+			Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+			HookTimedOut: true,
+		},
+	}
+	_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	c.Assert(s.hookTimedOut, jc.IsTrue)
+	s.stub.CheckNoCalls(c)
+
+	// Hooks that fail otherwise are still retried.
+	localState.HookTimedOut = false
+	_, err = s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	c.Assert(s.hookTimedOut, jc.IsFalse)
+	s.stub.CheckCallNames(c, "StartRetryHookTimer")
+}
+
 func (s *resolverSuite) TestHookErrorStartRetryTimer(c *gc.C) {
 	s.reportHookError = func(hook.Info) error { return nil }
 	localState := resolver.LocalState{
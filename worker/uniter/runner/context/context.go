@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker/uniter/healthcheck"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
 
@@ -193,6 +194,10 @@ type HookContext struct {
 	// storageId is the tag of the storage instance associated with the running hook.
 	storageTag names.StorageTag
 
+	// secretId is the id of the secret associated with the running
+	// secret-rotated hook.
+	secretId string
+
 	// hasRunSetStatus is true if a call to the status-set was made during the
 	// invocation of a hook.
 	// This attribute is persisted to local uniter state at the end of the hook
@@ -206,6 +211,15 @@ type HookContext struct {
 	// hook run, so the actual add will happen in a flush.
 	storageAddConstraints map[string][]params.StorageConstraints
 
+	// healthChecks holds the health checks run by the unit agent. It
+	// is nil if the unit agent does not run health checks.
+	healthChecks *healthcheck.Store
+
+	// pendingHealthChecks contains the health checks declared or, where
+	// the check is nil, removed during the hook, keyed on their names.
+	// The changes are applied to healthChecks on successful hook run.
+	pendingHealthChecks map[string]*healthcheck.Check
+
 	// clock is used for any time operations.
 	clock clock.Clock
 
@@ -391,6 +405,12 @@ func (ctx *HookContext) ResetExecutionSetUnitStatus() {
 	ctx.hasRunStatusSet = false
 }
 
+// RecordHookExecution records an execution of a hook or action with
+// the unit's execution history.
+func (ctx *HookContext) RecordHookExecution(execution params.HookExecution) error {
+	return ctx.unit.RecordHookExecution(execution)
+}
+
 func (ctx *HookContext) PublicAddress() (string, error) {
 	if ctx.publicAddress == "" {
 		return "", errors.NotFoundf("public address")
@@ -601,6 +621,9 @@ func (context *HookContext) HookVars(paths Paths) ([]string, error) {
 	} else if !errors.IsNotFound(err) {
 		return nil, errors.Trace(err)
 	}
+	if context.secretId != "" {
+		vars = append(vars, "JUJU_SECRET_ID="+context.secretId)
+	}
 	if context.actionData != nil {
 		vars = append(vars,
 			"JUJU_ACTION_NAME="+context.actionData.Name,
@@ -723,6 +746,25 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 		}
 	}
 
+	if len(ctx.pendingHealthChecks) > 0 && writeChanges {
+		var set []healthcheck.Check
+		var remove []string
+		for name, check := range ctx.pendingHealthChecks {
+			if check == nil {
+				remove = append(remove, name)
+			} else {
+				set = append(set, *check)
+			}
+		}
+		if err := ctx.healthChecks.Update(set, remove); err != nil {
+			err = errors.Annotatef(err, "cannot update health checks")
+			logger.Errorf("%v", err)
+			if ctxErr == nil {
+				ctxErr = err
+			}
+		}
+	}
+
 	// TODO (tasdomas) 2014 09 03: context finalization needs to modified to apply all
 	//                             changes in one api call to minimize the risk
 	//                             of partial failures.
@@ -834,6 +876,87 @@ func (ctx *HookContext) SetUnitWorkloadVersion(version string) error {
 	return result.OneError()
 }
 
+// SetHealthCheck implements jujuc.ContextHealthChecks. The check is
+// run by the unit agent once the hook has completed successfully.
+func (ctx *HookContext) SetHealthCheck(check healthcheck.Check) error {
+	if ctx.healthChecks == nil {
+		return errors.NotSupportedf("health checks")
+	}
+	if err := check.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if ctx.pendingHealthChecks == nil {
+		ctx.pendingHealthChecks = make(map[string]*healthcheck.Check)
+	}
+	ctx.pendingHealthChecks[check.Name] = &check
+	return nil
+}
+
+// RemoveHealthCheck implements jujuc.ContextHealthChecks. The check
+// is no longer run once the hook has completed successfully.
+func (ctx *HookContext) RemoveHealthCheck(name string) error {
+	if ctx.healthChecks == nil {
+		return errors.NotSupportedf("health checks")
+	}
+	if ctx.pendingHealthChecks == nil {
+		ctx.pendingHealthChecks = make(map[string]*healthcheck.Check)
+	}
+	ctx.pendingHealthChecks[name] = nil
+	return nil
+}
+
+// AddSecret implements jujuc.ContextSecrets. Only the leader may add
+// secrets for the application.
+func (ctx *HookContext) AddSecret(label string, value map[string]string) (string, error) {
+	if err := ctx.checkLeader(); err != nil {
+		return "", errors.Trace(err)
+	}
+	return ctx.unit.AddSecret(label, value)
+}
+
+// RotateSecret implements jujuc.ContextSecrets. Only the leader may
+// rotate the application's secrets.
+func (ctx *HookContext) RotateSecret(id string, value map[string]string) error {
+	if err := ctx.checkLeader(); err != nil {
+		return errors.Trace(err)
+	}
+	return ctx.unit.RotateSecret(id, value)
+}
+
+// GetSecret implements jujuc.ContextSecrets.
+func (ctx *HookContext) GetSecret(id string) (map[string]string, error) {
+	return ctx.unit.SecretValue(id)
+}
+
+// GrantSecret implements jujuc.ContextSecrets.
+func (ctx *HookContext) GrantSecret(id string, relationId int, unitName string) error {
+	r, found := ctx.relations[relationId]
+	if !found {
+		return errors.NotFoundf("relation %d", relationId)
+	}
+	return ctx.unit.GrantSecret(id, r.ru.Relation().Tag(), unitName)
+}
+
+// SetPodSpec implements jujuc.ContextPodSpec. Only the leader may set
+// the application's pod spec.
+func (ctx *HookContext) SetPodSpec(spec string) error {
+	if err := ctx.checkLeader(); err != nil {
+		return errors.Trace(err)
+	}
+	return ctx.state.SetPodSpec(ctx.unit.ApplicationName(), spec)
+}
+
+func (ctx *HookContext) checkLeader() error {
+	isLeader, err := ctx.IsLeader()
+	if err != nil {
+		return errors.Annotatef(err, "cannot determine leadership")
+	}
+	if !isLeader {
+		return ErrIsNotLeader
+	}
+	return nil
+}
+
 // NetworkInfo returns the network info for the given bindings on the given relation.
 func (ctx *HookContext) NetworkInfo(bindingNames []string, relationId int) (map[string]params.NetworkInfoResult, error) {
 	var relId *int
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/worker/uniter/healthcheck"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
@@ -72,6 +73,7 @@ type contextFactory struct {
 	envName    string
 	machineTag names.MachineTag
 	storage    StorageContextAccessor
+	health     *healthcheck.Store
 	clock      clock.Clock
 	zone       string
 	principal  string
@@ -94,6 +96,10 @@ type FactoryConfig struct {
 	Storage          StorageContextAccessor
 	Paths            Paths
 	Clock            clock.Clock
+
+	// HealthChecks holds the health checks declared by the charm. If
+	// it is nil, hooks cannot declare health checks.
+	HealthChecks *healthcheck.Store
 }
 
 // NewContextFactory returns a ContextFactory capable of creating execution contexts backed
@@ -103,20 +109,25 @@ func NewContextFactory(config FactoryConfig) (ContextFactory, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	var zone string
 	machineTag, err := unit.AssignedMachine()
-	if err != nil {
+	switch {
+	case params.IsCodeNotAssigned(err):
+		// Units deployed to a CAAS model are not assigned to
+		// machines, and so have neither machine nor zone.
+	case err != nil:
 		return nil, errors.Trace(err)
+	default:
+		zone, err = unit.AvailabilityZone()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 	model, err := config.State.Model()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	zone, err := unit.AvailabilityZone()
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-
 	principal, ok, err := unit.PrincipalName()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -135,6 +146,7 @@ func NewContextFactory(config FactoryConfig) (ContextFactory, error) {
 		getRelationInfos: config.GetRelationInfos,
 		relationCaches:   map[int]*RelationCache{},
 		storage:          config.Storage,
+		health:           config.HealthChecks,
 		rand:             rand.New(rand.NewSource(time.Now().Unix())),
 		clock:            config.Clock,
 		zone:             zone,
@@ -167,6 +179,7 @@ func (f *contextFactory) coreContext() (*HookContext, error) {
 		relationId:         -1,
 		pendingPorts:       make(map[PortRange]PortRangeInfo),
 		storage:            f.storage,
+		healthChecks:       f.health,
 		clock:              f.clock,
 		componentDir:       f.paths.ComponentDir,
 		componentFuncs:     registeredComponentFuncs,
@@ -226,6 +239,9 @@ func (f *contextFactory) HookContext(hookInfo hook.Info) (*HookContext, error) {
 		}
 		hookName = fmt.Sprintf("%s-%s", storageName, hookName)
 	}
+	if hookInfo.Kind == hook.SecretRotated {
+		ctx.secretId = hookInfo.SecretId
+	}
 	ctx.id = f.newId(hookName)
 	return ctx, nil
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/worker/uniter/healthcheck"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
 
@@ -103,6 +104,10 @@ func SetEnvironmentHookContextRelation(
 	}
 }
 
+func SetEnvironmentHookContextSecret(context *HookContext, secretId string) {
+	context.secretId = secretId
+}
+
 func PatchCachedStatus(ctx jujuc.Context, status, info string, data map[string]interface{}) func() {
 	hctx := ctx.(*HookContext)
 	oldStatus := hctx.status
@@ -136,6 +141,10 @@ func StorageAddConstraints(ctx *HookContext) map[string][]params.StorageConstrai
 	return ctx.storageAddConstraints
 }
 
+func SetHealthCheckStore(ctx *HookContext, store *healthcheck.Store) {
+	ctx.healthChecks = store
+}
+
 // NewModelHookContext exists purely to set the fields used in rs.
 // The returned value is not otherwise valid.
 func NewModelHookContext(
@@ -4,6 +4,8 @@
 package context_test
 
 import (
+	"path/filepath"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -14,6 +16,7 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker/metrics/spool"
+	"github.com/juju/juju/worker/uniter/healthcheck"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	runnertesting "github.com/juju/juju/worker/uniter/runner/testing"
 )
@@ -208,6 +211,48 @@ func (s *FlushContextSuite) TestRunHookAddUnitStorageOnSuccess(c *gc.C) {
 	c.Assert(all, gc.HasLen, 0)
 }
 
+func (s *FlushContextSuite) TestRunHookUpdatesHealthChecks(c *gc.C) {
+	store := healthcheck.NewStore(filepath.Join(c.MkDir(), "health-checks"))
+	err := store.Update([]healthcheck.Check{
+		{Name: "old", Type: healthcheck.TCPCheck, Target: "localhost:80"},
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := s.context(c)
+	context.SetHealthCheckStore(ctx, store)
+	web := healthcheck.Check{Name: "web", Type: healthcheck.HTTPCheck, Target: "http://localhost/"}
+	err = ctx.SetHealthCheck(web)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.RemoveHealthCheck("old")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = ctx.Flush("success", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	checks, err := store.Checks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, jc.DeepEquals, map[string]healthcheck.Check{"web": web})
+}
+
+func (s *FlushContextSuite) TestRunHookFailureDoesNotUpdateHealthChecks(c *gc.C) {
+	store := healthcheck.NewStore(filepath.Join(c.MkDir(), "health-checks"))
+	ctx := s.context(c)
+	context.SetHealthCheckStore(ctx, store)
+	err := ctx.SetHealthCheck(healthcheck.Check{Name: "web", Type: healthcheck.TCPCheck, Target: "localhost:80"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = ctx.Flush("some badge", errors.New("blam pow"))
+	c.Assert(err, gc.ErrorMatches, "blam pow")
+	checks, err := store.Checks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, gc.HasLen, 0)
+}
+
+func (s *FlushContextSuite) TestSetHealthCheckNotSupported(c *gc.C) {
+	ctx := s.context(c)
+	err := ctx.SetHealthCheck(healthcheck.Check{Name: "web", Type: healthcheck.TCPCheck, Target: "localhost:80"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *HookContextSuite) context(c *gc.C) *context.HookContext {
 	uuid, err := utils.NewUUID()
 	c.Assert(err, jc.ErrorIsNil)
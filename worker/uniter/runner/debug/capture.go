@@ -0,0 +1,120 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package debug
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+	goyaml "gopkg.in/yaml.v2"
+)
+
+// CaptureRequestFile returns the path of the file with which a
+// "juju capture-hook-context" client requests the capture of the
+// context of the unit's next matching hook.
+func (c *HooksContext) CaptureRequestFile() string {
+	basename := fmt.Sprintf("juju-%s-capture-hook-context", names.NewUnitTag(c.Unit))
+	return filepath.Join(c.FlockDir, basename)
+}
+
+// CaptureBundleFile returns the path of the file to which the
+// captured hook context bundle is written.
+func (c *HooksContext) CaptureBundleFile() string {
+	basename := fmt.Sprintf("juju-%s-hook-context.tar.gz", names.NewUnitTag(c.Unit))
+	return filepath.Join(c.FlockDir, basename)
+}
+
+// CaptureRequest represents a "juju capture-hook-context" request.
+type CaptureRequest struct {
+	*HooksContext
+	hooks set.Strings
+}
+
+// FindCaptureRequest returns the pending request for the capture of a
+// hook context of the unit specified in the context. The error returned
+// satisfies os.IsNotExist if there is no request.
+func (c *HooksContext) FindCaptureRequest() (*CaptureRequest, error) {
+	data, err := ioutil.ReadFile(c.CaptureRequestFile())
+	if err != nil {
+		return nil, err
+	}
+	var args hookArgs
+	if err := goyaml.Unmarshal(data, &args); err != nil {
+		return nil, err
+	}
+	return &CaptureRequest{HooksContext: c, hooks: set.NewStrings(args.Hooks...)}, nil
+}
+
+// MatchHook returns true if the specified hook name matches
+// the hooks specified by the capture-hook-context client.
+func (r *CaptureRequest) MatchHook(hookName string) bool {
+	return r.hooks.IsEmpty() || r.hooks.Contains(hookName)
+}
+
+// Capture completes the request, by writing the hook context bundle
+// with the given function and making it available to the client. The
+// request is removed, so that only one hook is captured.
+func (r *CaptureRequest) Capture(writeBundle func(io.Writer) error) error {
+	defer os.Remove(r.CaptureRequestFile())
+	f, err := ioutil.TempFile(r.FlockDir, "juju-hook-context")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := writeBundle(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// The bundle appears to the client only once it's complete.
+	return os.Rename(f.Name(), r.CaptureBundleFile())
+}
+
+// CaptureClientScript returns a bash script suitable for executing on
+// the unit system to request the capture of the context of the next
+// of the given hooks, wait for it and write the bundle to stdout.
+func CaptureClientScript(c *HooksContext, hooks []string) string {
+	// If any hook is "*", then the client is interested in all.
+	for _, hook := range hooks {
+		if hook == "*" {
+			hooks = nil
+			break
+		}
+	}
+
+	s := strings.Replace(captureClientScript, "{unit_name}", c.Unit, -1)
+	s = strings.Replace(s, "{request_file}", c.CaptureRequestFile(), -1)
+	s = strings.Replace(s, "{bundle_file}", c.CaptureBundleFile(), -1)
+
+	yamlArgs := encodeArgs(hooks)
+	base64Args := base64.StdEncoding.EncodeToString(yamlArgs)
+	s = strings.Replace(s, "{hook_args}", base64Args, 1)
+	return s
+}
+
+const captureClientScript = `#!/bin/bash
+set -e
+
+# Withdraw the request if we exit before the hook is captured.
+trap 'rm -f {request_file}' EXIT
+
+rm -f {bundle_file}
+echo "{hook_args}" | base64 -d > {request_file}
+echo "Waiting for the next matching hook of {unit_name} to run..." >&2
+
+while [ ! -f {bundle_file} ]; do
+    sleep 1
+done
+cat {bundle_file}
+rm -f {bundle_file}
+`
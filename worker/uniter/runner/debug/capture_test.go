@@ -0,0 +1,108 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package debug_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/debug"
+)
+
+type CaptureSuite struct {
+	ctx *debug.HooksContext
+}
+
+var _ = gc.Suite(&CaptureSuite{})
+
+func (s *CaptureSuite) SetUpTest(c *gc.C) {
+	s.ctx = debug.NewHooksContext("foo/8")
+	s.ctx.FlockDir = c.MkDir()
+}
+
+func (s *CaptureSuite) TestCaptureFiles(c *gc.C) {
+	ctx := debug.NewHooksContext("foo/8")
+	c.Assert(ctx.CaptureRequestFile(), jc.SamePath, "/tmp/juju-unit-foo-8-capture-hook-context")
+	c.Assert(ctx.CaptureBundleFile(), jc.SamePath, "/tmp/juju-unit-foo-8-hook-context.tar.gz")
+}
+
+func (s *CaptureSuite) TestFindCaptureRequest(c *gc.C) {
+	request, err := s.ctx.FindCaptureRequest()
+	c.Assert(request, gc.IsNil)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+
+	err = ioutil.WriteFile(s.ctx.CaptureRequestFile(), []byte("hooks: [start, stop]\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	request, err = s.ctx.FindCaptureRequest()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(request.MatchHook("start"), jc.IsTrue)
+	c.Assert(request.MatchHook("stop"), jc.IsTrue)
+	c.Assert(request.MatchHook("install"), jc.IsFalse)
+
+	// No hooks means any hook.
+	err = ioutil.WriteFile(s.ctx.CaptureRequestFile(), nil, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	request, err = s.ctx.FindCaptureRequest()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(request.MatchHook("install"), jc.IsTrue)
+}
+
+func (s *CaptureSuite) TestCapture(c *gc.C) {
+	err := ioutil.WriteFile(s.ctx.CaptureRequestFile(), nil, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	request, err := s.ctx.FindCaptureRequest()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = request.Capture(func(w io.Writer) error {
+		_, err := w.Write([]byte("bundle"))
+		return err
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(s.ctx.CaptureBundleFile())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "bundle")
+	_, err = os.Stat(s.ctx.CaptureRequestFile())
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+
+	// Nothing is left behind but the bundle.
+	entries, err := ioutil.ReadDir(s.ctx.FlockDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Assert(filepath.Join(s.ctx.FlockDir, entries[0].Name()), gc.Equals, s.ctx.CaptureBundleFile())
+}
+
+func (s *CaptureSuite) TestCaptureFails(c *gc.C) {
+	err := ioutil.WriteFile(s.ctx.CaptureRequestFile(), nil, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	request, err := s.ctx.FindCaptureRequest()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = request.Capture(func(w io.Writer) error {
+		return errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	entries, err := ioutil.ReadDir(s.ctx.FlockDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
+
+func (s *CaptureSuite) TestCaptureClientScript(c *gc.C) {
+	script := debug.CaptureClientScript(s.ctx, []string{"start", "stop"})
+	args := base64.StdEncoding.EncodeToString([]byte("hooks:\n- start\n- stop\n"))
+	c.Assert(script, gc.Matches, "(?s).*"+regexp.QuoteMeta(`echo "`+args+`" | base64 -d > `+s.ctx.CaptureRequestFile())+".*")
+	c.Assert(script, gc.Matches, "(?s).*"+regexp.QuoteMeta("cat "+s.ctx.CaptureBundleFile())+".*")
+
+	// "*" means any hook.
+	script = debug.CaptureClientScript(s.ctx, []string{"start", "*"})
+	args = base64.StdEncoding.EncodeToString([]byte("{}\n"))
+	c.Assert(script, gc.Matches, "(?s).*"+regexp.QuoteMeta(`echo "`+args+`"`)+".*")
+}
@@ -4,6 +4,10 @@
 package runner
 
 import (
+	"time"
+
+	"github.com/juju/utils/clock"
+
 	"github.com/juju/juju/worker/uniter/runner/context"
 )
 
@@ -17,3 +21,11 @@ var (
 func RunnerPaths(rnr Runner) context.Paths {
 	return rnr.(*runner).paths
 }
+
+func RunnerHookTimeout(rnr Runner) time.Duration {
+	return rnr.(*runner).hookTimeout
+}
+
+func NewRunnerWithHookTimeout(context Context, paths context.Paths, hookTimeout time.Duration, clock clock.Clock) Runner {
+	return &runner{context: context, paths: paths, hookTimeout: hookTimeout, clock: clock}
+}
@@ -4,9 +4,16 @@
 package runner
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/names.v2"
+	goyaml "gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
@@ -33,11 +40,15 @@ type Factory interface {
 }
 
 // NewFactory returns a Factory capable of creating runners for executing
-// charm hooks, actions and commands.
+// charm hooks, actions and commands. If hookTimeout is non-zero, hooks
+// are killed when they run for longer; otherwise the timeout declared
+// by the charm, if any, applies. The clock times hooks.
 func NewFactory(
 	state *uniter.State,
 	paths context.Paths,
 	contextFactory context.ContextFactory,
+	hookTimeout time.Duration,
+	clock clock.Clock,
 ) (
 	Factory, error,
 ) {
@@ -45,6 +56,8 @@ func NewFactory(
 		state:          state,
 		paths:          paths,
 		contextFactory: contextFactory,
+		hookTimeout:    hookTimeout,
+		clock:          clock,
 	}
 
 	return f, nil
@@ -57,7 +70,9 @@ type factory struct {
 	state *uniter.State
 
 	// Fields that shouldn't change in a factory's lifetime.
-	paths context.Paths
+	paths       context.Paths
+	hookTimeout time.Duration
+	clock       clock.Clock
 }
 
 // NewCommandRunner exists to satisfy the Factory interface.
@@ -80,7 +95,15 @@ func (f *factory) NewHookRunner(hookInfo hook.Info) (Runner, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	runner := NewRunner(ctx, f.paths)
+	runner := &runner{
+		context:     ctx,
+		paths:       f.paths,
+		hookTimeout: f.hookTimeout,
+		clock:       f.clock,
+	}
+	if runner.hookTimeout == 0 {
+		runner.hookTimeout = charmHookTimeout(f.paths.GetCharmDir())
+	}
 	return runner, nil
 }
 
@@ -127,6 +150,39 @@ func (f *factory) NewActionRunner(actionId string) (Runner, error) {
 	return runner, nil
 }
 
+// charmHookTimeout returns the hook timeout declared by the charm in
+// the given directory, in the hook-timeout field of its metadata, or
+// zero if it declares none. A timeout that can't be read is ignored.
+//
+// TODO: hook-timeout should be a field of charm.Meta, which is defined
+// outside this tree in gopkg.in/juju/charm; until it is, the field is
+// read from metadata.yaml here.
+func charmHookTimeout(charmPath string) time.Duration {
+	data, err := ioutil.ReadFile(filepath.Join(charmPath, "metadata.yaml"))
+	if os.IsNotExist(err) {
+		return 0
+	} else if err != nil {
+		logger.Warningf("cannot read charm metadata: %v", err)
+		return 0
+	}
+	var meta struct {
+		HookTimeout string `yaml:"hook-timeout"`
+	}
+	if err := goyaml.Unmarshal(data, &meta); err != nil {
+		logger.Warningf("cannot parse charm metadata: %v", err)
+		return 0
+	}
+	if meta.HookTimeout == "" {
+		return 0
+	}
+	timeout, err := time.ParseDuration(meta.HookTimeout)
+	if err != nil || timeout < 0 {
+		logger.Warningf("ignoring invalid charm hook-timeout %q", meta.HookTimeout)
+		return 0
+	}
+	return timeout
+}
+
 func getCharm(charmPath string) (charm.Charm, error) {
 	ch, err := charm.ReadCharm(charmPath)
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/core/relation"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/storage"
+	"github.com/juju/juju/worker/uniter/healthcheck"
 )
 
 // RebootPriority is the type used for reboot requests.
@@ -54,6 +55,9 @@ type HookContext interface {
 	ContextComponents
 	ContextRelations
 	ContextVersion
+	ContextHealthChecks
+	ContextSecrets
+	ContextPodSpec
 }
 
 // UnitHookContext is the context for a unit hook.
@@ -301,6 +305,48 @@ type ContextVersion interface {
 	SetUnitWorkloadVersion(string) error
 }
 
+// ContextHealthChecks expresses the parts of a hook context related to
+// the health checks run by the unit agent.
+type ContextHealthChecks interface {
+
+	// SetHealthCheck adds or replaces the health check with the check's
+	// name.
+	SetHealthCheck(healthcheck.Check) error
+
+	// RemoveHealthCheck removes the health check with the given name.
+	RemoveHealthCheck(name string) error
+}
+
+// ContextSecrets expresses the parts of a hook context related to
+// secrets defined by charms.
+type ContextSecrets interface {
+
+	// AddSecret creates a secret owned by the unit's application, and
+	// returns its id.
+	AddSecret(label string, value map[string]string) (string, error)
+
+	// RotateSecret replaces the value of a secret owned by the unit's
+	// application.
+	RotateSecret(id string, value map[string]string) error
+
+	// GetSecret returns the value of a secret readable by the unit.
+	GetSecret(id string) (map[string]string, error)
+
+	// GrantSecret grants access to a secret owned by the unit's
+	// application to the units at the other end of the relation with
+	// the given id; or, if unitName is not empty, to that unit only.
+	GrantSecret(id string, relationId int, unitName string) error
+}
+
+// ContextPodSpec expresses the parts of a hook context related to
+// the pod spec of an application deployed to a CAAS model.
+type ContextPodSpec interface {
+
+	// SetPodSpec sets the pod spec of the unit's application. Only
+	// the leader may set the pod spec.
+	SetPodSpec(spec string) error
+}
+
 // Settings is implemented by types that manipulate unit settings.
 type Settings interface {
 	Map() params.Settings
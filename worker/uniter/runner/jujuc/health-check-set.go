@@ -0,0 +1,124 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/worker/uniter/healthcheck"
+)
+
+// HealthCheckSetCommand implements the health-check-set command.
+type HealthCheckSetCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	name     string
+	http     string
+	tcp      string
+	exec     string
+	interval time.Duration
+	timeout  time.Duration
+	remove   bool
+}
+
+// NewHealthCheckSetCommand makes a jujuc health-check-set command.
+func NewHealthCheckSetCommand(ctx Context) (cmd.Command, error) {
+	return &HealthCheckSetCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *HealthCheckSetCommand) Info() *cmd.Info {
+	doc := `
+health-check-set declares a health check which the unit agent runs
+periodically, without running a hook. Exactly one of --http, --tcp
+or --exec must be given:
+
+  --http <url>        passes if a GET of the URL returns a status
+                      code below 400
+  --tcp <host:port>   passes if a connection can be made to the
+                      address
+  --exec <command>    passes if the command, run by the shell in the
+                      charm directory, exits with status zero
+
+The unit's health is reported in "juju status" and in the "health"
+status history of the unit: healthy if all of its checks passed at
+their last run, and unhealthy otherwise. Declaring a check with the
+name of an existing check replaces it; --remove removes it.
+
+Checks take effect when the hook completes successfully.
+`
+	return &cmd.Info{
+		Name:    "health-check-set",
+		Args:    "<name> (--http <url> | --tcp <host:port> | --exec <command> | --remove)",
+		Purpose: "declare a health check for the unit",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *HealthCheckSetCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.http, "http", "", "URL to GET")
+	f.StringVar(&c.tcp, "tcp", "", "address to connect to")
+	f.StringVar(&c.exec, "exec", "", "command to run")
+	f.DurationVar(&c.interval, "interval", 0, "time between runs of the check (default 30s)")
+	f.DurationVar(&c.timeout, "timeout", 0, "time after which the check fails (default 10s)")
+	f.BoolVar(&c.remove, "remove", false, "remove the health check")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *HealthCheckSetCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no health check name specified")
+	}
+	c.name = args[0]
+	if err := cmd.CheckEmpty(args[1:]); err != nil {
+		return err
+	}
+	targets := 0
+	for _, target := range []string{c.http, c.tcp, c.exec} {
+		if target != "" {
+			targets++
+		}
+	}
+	if c.remove {
+		if targets > 0 || c.interval != 0 || c.timeout != 0 {
+			return errors.New("--remove cannot be combined with other options")
+		}
+		return nil
+	}
+	if targets != 1 {
+		return errors.New("exactly one of --http, --tcp or --exec must be specified")
+	}
+	return c.check().Validate()
+}
+
+func (c *HealthCheckSetCommand) check() healthcheck.Check {
+	check := healthcheck.Check{
+		Name:     c.name,
+		Interval: c.interval,
+		Timeout:  c.timeout,
+	}
+	switch {
+	case c.http != "":
+		check.Type, check.Target = healthcheck.HTTPCheck, c.http
+	case c.tcp != "":
+		check.Type, check.Target = healthcheck.TCPCheck, c.tcp
+	default:
+		check.Type, check.Target = healthcheck.ExecCheck, c.exec
+	}
+	return check
+}
+
+// Run is part of the cmd.Command interface.
+func (c *HealthCheckSetCommand) Run(ctx *cmd.Context) error {
+	if c.remove {
+		return c.ctx.RemoveHealthCheck(c.name)
+	}
+	return c.ctx.SetHealthCheck(c.check())
+}
@@ -0,0 +1,112 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/healthcheck"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type HealthCheckSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&HealthCheckSetSuite{})
+
+func (s *HealthCheckSetSuite) createCommand(c *gc.C, err error) (*Context, cmd.Command) {
+	hctx := s.GetHookContext(c, -1, "")
+	s.Stub.SetErrors(err)
+
+	com, err := jujuc.NewCommand(hctx, cmdString("health-check-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	return hctx, com
+}
+
+var healthCheckSetInitErrorTests = []struct {
+	args []string
+	err  string
+}{{
+	args: nil,
+	err:  "no health check name specified",
+}, {
+	args: []string{"web"},
+	err:  "exactly one of --http, --tcp or --exec must be specified",
+}, {
+	args: []string{"web", "--http", "http://localhost/", "--tcp", "localhost:80"},
+	err:  "exactly one of --http, --tcp or --exec must be specified",
+}, {
+	args: []string{"web", "--remove", "--tcp", "localhost:80"},
+	err:  "--remove cannot be combined with other options",
+}, {
+	args: []string{"Web", "--tcp", "localhost:80"},
+	err:  `health check name "Web" not valid`,
+}, {
+	args: []string{"web", "--http", "localhost"},
+	err:  `health check "web" URL "localhost" not valid`,
+}, {
+	args: []string{"web", "--tcp", "localhost:80", "--interval", "1s"},
+	err:  `health check "web" interval 1s shorter than 5s not valid`,
+}, {
+	args: []string{"web", "--tcp", "localhost:80", "extra"},
+	err:  `unrecognized args: \["extra"\]`,
+}}
+
+func (s *HealthCheckSetSuite) TestInitErrors(c *gc.C) {
+	for i, t := range healthCheckSetInitErrorTests {
+		c.Logf("test %d: %v", i, t.args)
+		hctx, com := s.createCommand(c, nil)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 2)
+		c.Check(bufferString(ctx.Stderr), gc.Matches, "ERROR "+t.err+"\n")
+		c.Check(hctx.info.HealthChecks.Checks, gc.HasLen, 0)
+	}
+}
+
+func (s *HealthCheckSetSuite) TestSetHealthCheck(c *gc.C) {
+	hctx, com := s.createCommand(c, nil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"web", "--http", "http://localhost:8080/health", "--interval", "1m", "--timeout", "5s"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.info.HealthChecks.Checks, jc.DeepEquals, map[string]healthcheck.Check{
+		"web": {
+			Name:     "web",
+			Type:     healthcheck.HTTPCheck,
+			Target:   "http://localhost:8080/health",
+			Interval: time.Minute,
+			Timeout:  5 * time.Second,
+		},
+	})
+}
+
+func (s *HealthCheckSetSuite) TestRemoveHealthCheck(c *gc.C) {
+	hctx, com := s.createCommand(c, nil)
+	hctx.info.HealthChecks.Checks = map[string]healthcheck.Check{
+		"db": {Name: "db", Type: healthcheck.TCPCheck, Target: "localhost:5432"},
+	}
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"db", "--remove"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.info.HealthChecks.Checks, gc.HasLen, 0)
+	s.Stub.CheckCallNames(c, "RemoveHealthCheck")
+}
+
+func (s *HealthCheckSetSuite) TestSetHealthCheckError(c *gc.C) {
+	hctx, com := s.createCommand(c, errors.New("boom"))
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"ready", "--exec", "test -f ready"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR boom\n")
+	c.Check(hctx.info.HealthChecks.Checks, gc.HasLen, 0)
+}
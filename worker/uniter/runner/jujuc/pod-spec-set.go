@@ -0,0 +1,72 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+)
+
+// podSpecSetCommand implements the pod-spec-set command.
+type podSpecSetCommand struct {
+	cmd.CommandBase
+	ctx      Context
+	specFile cmd.FileVar
+}
+
+// NewPodSpecSetCommand returns a new podSpecSetCommand with the given context.
+func NewPodSpecSetCommand(ctx Context) (cmd.Command, error) {
+	return &podSpecSetCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *podSpecSetCommand) Info() *cmd.Info {
+	doc := `
+pod-spec-set sets the pod spec of the unit's application, which describes
+the containers the CAAS substrate runs for each of the application's units.
+The spec is read from the YAML file given with --file; a value of "-" means
+<stdin>. Only the application leader may set the pod spec, and only for
+applications deployed to a CAAS model.
+
+Examples:
+    pod-spec-set --file spec.yaml
+`
+	return &cmd.Info{
+		Name:    "pod-spec-set",
+		Args:    "--file <spec.yaml>",
+		Purpose: "set the pod spec of the application",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *podSpecSetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.specFile.SetStdin()
+	f.Var(&c.specFile, "file", "file containing the pod spec")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *podSpecSetCommand) Init(args []string) error {
+	if c.specFile.Path == "" {
+		return errors.New("no pod spec file specified")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run is part of the cmd.Command interface.
+func (c *podSpecSetCommand) Run(ctx *cmd.Context) error {
+	file, err := c.specFile.Open(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer file.Close()
+	spec, err := ioutil.ReadAll(file)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Annotate(c.ctx.SetPodSpec(string(spec)), "cannot set pod spec")
+}
@@ -0,0 +1,90 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+const podSpecYaml = `
+containers:
+  - name: gitlab
+    image: gitlab/latest
+`
+
+type PodSpecSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&PodSpecSetSuite{})
+
+func (s *PodSpecSetSuite) createCommand(c *gc.C, err error) (*Context, cmd.Command) {
+	hctx := s.GetHookContext(c, -1, "")
+	s.Stub.SetErrors(err)
+
+	com, err := jujuc.NewCommand(hctx, cmdString("pod-spec-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	return hctx, com
+}
+
+func (s *PodSpecSetSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no pod spec file specified",
+	}, {
+		args: []string{"--file", "spec.yaml", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		_, com := s.createCommand(c, nil)
+		err := cmdtesting.InitCommand(com, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *PodSpecSetSuite) TestSetPodSpecFromFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "spec.yaml")
+	err := ioutil.WriteFile(path, []byte(podSpecYaml), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	hctx, com := s.createCommand(c, nil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--file", path})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.info.PodSpec.Spec, gc.Equals, podSpecYaml)
+}
+
+func (s *PodSpecSetSuite) TestSetPodSpecFromStdin(c *gc.C) {
+	hctx, com := s.createCommand(c, nil)
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = bytes.NewBufferString(podSpecYaml)
+	code := cmd.Main(com, ctx, []string{"--file", "-"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.info.PodSpec.Spec, gc.Equals, podSpecYaml)
+}
+
+func (s *PodSpecSetSuite) TestSetPodSpecError(c *gc.C) {
+	hctx, com := s.createCommand(c, errors.New("not the leader"))
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = bytes.NewBufferString(podSpecYaml)
+	code := cmd.Main(com, ctx, []string{"--file", "-"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR cannot set pod spec: not the leader\n")
+	c.Check(hctx.info.PodSpec.Spec, gc.Equals, "")
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/worker/uniter/healthcheck"
 )
 
 // ErrRestrictedContext indicates a method is not implemented in the given context.
@@ -147,3 +148,38 @@ func (*RestrictedContext) UnitWorkloadVersion() (string, error) {
 func (*RestrictedContext) SetUnitWorkloadVersion(string) error {
 	return ErrRestrictedContext
 }
+
+// SetHealthCheck implements jujuc.Context.
+func (*RestrictedContext) SetHealthCheck(healthcheck.Check) error {
+	return ErrRestrictedContext
+}
+
+// RemoveHealthCheck implements jujuc.Context.
+func (*RestrictedContext) RemoveHealthCheck(string) error {
+	return ErrRestrictedContext
+}
+
+// AddSecret implements jujuc.Context.
+func (*RestrictedContext) AddSecret(string, map[string]string) (string, error) {
+	return "", ErrRestrictedContext
+}
+
+// RotateSecret implements jujuc.Context.
+func (*RestrictedContext) RotateSecret(string, map[string]string) error {
+	return ErrRestrictedContext
+}
+
+// GetSecret implements jujuc.Context.
+func (*RestrictedContext) GetSecret(string) (map[string]string, error) {
+	return nil, ErrRestrictedContext
+}
+
+// GrantSecret implements jujuc.Context.
+func (*RestrictedContext) GrantSecret(string, int, string) error {
+	return ErrRestrictedContext
+}
+
+// SetPodSpec implements jujuc.Context.
+func (*RestrictedContext) SetPodSpec(string) error {
+	return ErrRestrictedContext
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/keyvalues"
+)
+
+// secretAddCommand implements the secret-add command.
+type secretAddCommand struct {
+	cmd.CommandBase
+	ctx    Context
+	label  string
+	rotate string
+	value  map[string]string
+}
+
+// NewSecretAddCommand returns a new secretAddCommand with the given context.
+func NewSecretAddCommand(ctx Context) (cmd.Command, error) {
+	return &secretAddCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *secretAddCommand) Info() *cmd.Info {
+	doc := `
+secret-add stores the supplied key/value pairs as a new secret owned by the
+unit's application, and prints the id of the secret. The secret's value is
+stored encrypted by the controller, and may be read only by units of the
+owning application and by units it is granted to with secret-grant.
+
+With --rotate, the value of an existing secret owned by the application is
+replaced instead, and units the secret is granted to run the secret-rotated
+hook. Only the application leader may add or rotate secrets.
+
+Examples:
+    secret-add --label db-admin username=admin password=s3cret
+    secret-add --rotate secret-3 username=admin password=n3w
+`
+	return &cmd.Info{
+		Name:    "secret-add",
+		Args:    "<key>=<value> [...]",
+		Purpose: "add or rotate a secret",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *secretAddCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.label, "label", "", "a label identifying the secret among those of the application")
+	f.StringVar(&c.rotate, "rotate", "", "the id of an existing secret to replace the value of")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *secretAddCommand) Init(args []string) (err error) {
+	if c.label != "" && c.rotate != "" {
+		return errors.New("--label cannot be used with --rotate")
+	}
+	if len(args) == 0 {
+		return errors.New("no secret value specified")
+	}
+	c.value, err = keyvalues.Parse(args, false)
+	return errors.Trace(err)
+}
+
+// Run is part of the cmd.Command interface.
+func (c *secretAddCommand) Run(ctx *cmd.Context) error {
+	if c.rotate != "" {
+		err := c.ctx.RotateSecret(c.rotate, c.value)
+		return errors.Annotatef(err, "cannot rotate secret %q", c.rotate)
+	}
+	id, err := c.ctx.AddSecret(c.label, c.value)
+	if err != nil {
+		return errors.Annotate(err, "cannot add secret")
+	}
+	fmt.Fprintln(ctx.Stdout, id)
+	return nil
+}
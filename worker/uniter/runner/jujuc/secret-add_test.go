@@ -0,0 +1,96 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type SecretAddSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&SecretAddSuite{})
+
+func (s *SecretAddSuite) createCommand(c *gc.C, err error) (*Context, cmd.Command) {
+	hctx := s.GetHookContext(c, -1, "")
+	s.Stub.SetErrors(err)
+
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-add"))
+	c.Assert(err, jc.ErrorIsNil)
+	return hctx, com
+}
+
+var secretAddInitErrorTests = []struct {
+	args []string
+	err  string
+}{{
+	args: nil,
+	err:  "no secret value specified",
+}, {
+	args: []string{"password"},
+	err:  `expected "key=value", got "password"`,
+}, {
+	args: []string{"a=1", "a=2"},
+	err:  `key "a" specified more than once`,
+}, {
+	args: []string{"--label", "admin", "--rotate", "secret-0", "a=1"},
+	err:  "--label cannot be used with --rotate",
+}}
+
+func (s *SecretAddSuite) TestInitErrors(c *gc.C) {
+	for i, t := range secretAddInitErrorTests {
+		c.Logf("test %d: %v", i, t.args)
+		hctx, com := s.createCommand(c, nil)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 2)
+		c.Check(bufferString(ctx.Stderr), gc.Matches, "ERROR "+t.err+"\n")
+		c.Check(hctx.info.Secrets.Values, gc.HasLen, 0)
+	}
+}
+
+func (s *SecretAddSuite) TestAddSecret(c *gc.C) {
+	hctx, com := s.createCommand(c, nil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--label", "admin", "username=admin", "password=sekrit"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "secret-0\n")
+	c.Check(hctx.info.Secrets.Values, jc.DeepEquals, map[string]map[string]string{
+		"secret-0": {"username": "admin", "password": "sekrit"},
+	})
+	c.Check(hctx.info.Secrets.Labels, jc.DeepEquals, map[string]string{"secret-0": "admin"})
+}
+
+func (s *SecretAddSuite) TestRotateSecret(c *gc.C) {
+	hctx, com := s.createCommand(c, nil)
+	hctx.info.Secrets.Values = map[string]map[string]string{
+		"secret-0": {"password": "sekrit"},
+	}
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--rotate", "secret-0", "password=new"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Check(hctx.info.Secrets.Values, jc.DeepEquals, map[string]map[string]string{
+		"secret-0": {"password": "new"},
+	})
+	s.Stub.CheckCallNames(c, "RotateSecret")
+}
+
+func (s *SecretAddSuite) TestAddSecretError(c *gc.C) {
+	hctx, com := s.createCommand(c, errors.New("not the leader"))
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"password=sekrit"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR cannot add secret: not the leader\n")
+	c.Check(hctx.info.Secrets.Values, gc.HasLen, 0)
+}
@@ -0,0 +1,72 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type SecretGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&SecretGetSuite{})
+
+func (s *SecretGetSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.Secrets.Values = map[string]map[string]string{
+		"secret-0": {"username": "admin", "password": "sekrit"},
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+var secretGetTests = []struct {
+	args []string
+	code int
+	out  string
+	err  string
+}{{
+	args: nil,
+	code: 2,
+	err:  "ERROR no secret id specified\n",
+}, {
+	args: []string{"secret-0", "password", "extra"},
+	code: 2,
+	err:  "ERROR unrecognized args: [\"extra\"]\n",
+}, {
+	args: []string{"secret-0"},
+	out:  "password: sekrit\nusername: admin\n",
+}, {
+	args: []string{"secret-0", "password"},
+	out:  "sekrit\n",
+}, {
+	args: []string{"secret-0", "missing"},
+	out:  "",
+}, {
+	args: []string{"secret-0", "--format", "json"},
+	out:  `{"password":"sekrit","username":"admin"}` + "\n",
+}, {
+	args: []string{"secret-1"},
+	code: 1,
+	err:  "ERROR cannot read secret \"secret-1\": secret \"secret-1\" not found\n",
+}}
+
+func (s *SecretGetSuite) TestSecretGet(c *gc.C) {
+	for i, t := range secretGetTests {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.err)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+)
+
+// secretGrantCommand implements the secret-grant command.
+type secretGrantCommand struct {
+	cmd.CommandBase
+	ctx             Context
+	id              string
+	relationId      int
+	relationIdProxy gnuflag.Value
+	unitName        string
+}
+
+// NewSecretGrantCommand returns a new secretGrantCommand with the given context.
+func NewSecretGrantCommand(ctx Context) (cmd.Command, error) {
+	c := &secretGrantCommand{ctx: ctx}
+	rV, err := newRelationIdValue(ctx, &c.relationId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.relationIdProxy = rV
+	return c, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *secretGrantCommand) Info() *cmd.Info {
+	doc := `
+secret-grant allows the units of the application at the other end of a
+relation to read a secret owned by the unit's application. If no relation
+is specified then the current relation is used. With --unit, only the
+named unit of the related application may read the secret. Access is
+withdrawn when the relation is removed.
+
+Examples:
+    secret-grant secret-3 -r db:2
+    secret-grant secret-3 -r db:2 --unit wordpress/1
+`
+	return &cmd.Info{
+		Name:    "secret-grant",
+		Args:    "<id>",
+		Purpose: "grant access to a secret over a relation",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *secretGrantCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(c.relationIdProxy, "r", "specify a relation by id")
+	f.Var(c.relationIdProxy, "relation", "")
+	f.StringVar(&c.unitName, "unit", "", "restrict access to a single unit of the related application")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *secretGrantCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no secret id specified")
+	}
+	c.id = args[0]
+	if c.relationId == -1 {
+		return errors.New("no relation id specified")
+	}
+	if c.unitName != "" && !names.IsValidUnit(c.unitName) {
+		return errors.NotValidf("unit name %q", c.unitName)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run is part of the cmd.Command interface.
+func (c *secretGrantCommand) Run(_ *cmd.Context) error {
+	err := c.ctx.GrantSecret(c.id, c.relationId, c.unitName)
+	return errors.Annotatef(err, "cannot grant secret %q", c.id)
+}
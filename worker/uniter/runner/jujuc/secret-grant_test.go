@@ -0,0 +1,79 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
+)
+
+type SecretGrantSuite struct {
+	relationSuite
+}
+
+var _ = gc.Suite(&SecretGrantSuite{})
+
+var secretGrantTests = []struct {
+	summary string
+	relid   int
+	args    []string
+	code    int
+	err     string
+	grants  []jujuctesting.SecretGrant
+}{{
+	summary: "no secret id",
+	relid:   -1,
+	code:    2,
+	err:     "ERROR no secret id specified\n",
+}, {
+	summary: "no relation",
+	relid:   -1,
+	args:    []string{"secret-0"},
+	code:    2,
+	err:     "ERROR no relation id specified\n",
+}, {
+	summary: "invalid unit",
+	relid:   -1,
+	args:    []string{"secret-0", "-r", "peer1:1", "--unit", "foo"},
+	code:    2,
+	err:     "ERROR unit name \"foo\" not valid\n",
+}, {
+	summary: "default relation",
+	relid:   1,
+	args:    []string{"secret-0"},
+	grants:  []jujuctesting.SecretGrant{{RelationId: 1}},
+}, {
+	summary: "explicit relation and unit",
+	relid:   -1,
+	args:    []string{"secret-0", "-r", "peer0:0", "--unit", "u/1"},
+	grants:  []jujuctesting.SecretGrant{{RelationId: 0, UnitName: "u/1"}},
+}, {
+	summary: "unknown secret",
+	relid:   1,
+	args:    []string{"secret-1"},
+	code:    1,
+	err:     "ERROR cannot grant secret \"secret-1\": secret \"secret-1\" not found\n",
+}}
+
+func (s *SecretGrantSuite) TestSecretGrant(c *gc.C) {
+	for i, t := range secretGrantTests {
+		c.Logf("test %d: %s", i, t.summary)
+		hctx, info := s.newHookContext(t.relid, "")
+		info.Secrets.Values = map[string]map[string]string{
+			"secret-0": {"password": "sekrit"},
+		}
+		com, err := jujuc.NewCommand(hctx, cmdString("secret-grant"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.err)
+		c.Check(info.Secrets.Grants["secret-0"], jc.DeepEquals, t.grants)
+	}
+}
@@ -63,6 +63,11 @@ var baseCommands = map[string]creator{
 	"status-set" + cmdSuffix:              NewStatusSetCommand,
 	"network-get" + cmdSuffix:             NewNetworkGetCommand,
 	"application-version-set" + cmdSuffix: NewApplicationVersionSetCommand,
+	"health-check-set" + cmdSuffix:        NewHealthCheckSetCommand,
+	"secret-add" + cmdSuffix:              NewSecretAddCommand,
+	"secret-get" + cmdSuffix:              NewSecretGetCommand,
+	"secret-grant" + cmdSuffix:            NewSecretGrantCommand,
+	"pod-spec-set" + cmdSuffix:            NewPodSpecSetCommand,
 }
 
 var storageCommands = map[string]creator{
@@ -23,6 +23,9 @@ type ContextInfo struct {
 	RelationHook
 	ActionHook
 	Version
+	HealthChecks
+	Secrets
+	PodSpec
 }
 
 // Context returns a Context that wraps the info.
@@ -63,6 +66,9 @@ type Context struct {
 	ContextRelationHook
 	ContextActionHook
 	ContextVersion
+	ContextHealthChecks
+	ContextSecrets
+	ContextPodSpec
 }
 
 // NewContext builds a jujuc.Context test double.
@@ -92,5 +98,11 @@ func NewContext(stub *testing.Stub, info *ContextInfo) *Context {
 	ctx.ContextActionHook.info = &info.ActionHook
 	ctx.ContextVersion.stub = stub
 	ctx.ContextVersion.info = &info.Version
+	ctx.ContextHealthChecks.stub = stub
+	ctx.ContextHealthChecks.info = &info.HealthChecks
+	ctx.ContextSecrets.stub = stub
+	ctx.ContextSecrets.info = &info.Secrets
+	ctx.ContextPodSpec.stub = stub
+	ctx.ContextPodSpec.info = &info.PodSpec
 	return &ctx
 }
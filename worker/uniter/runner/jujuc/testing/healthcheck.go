@@ -0,0 +1,44 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/worker/uniter/healthcheck"
+)
+
+// HealthChecks holds values for the hook context.
+type HealthChecks struct {
+	Checks map[string]healthcheck.Check
+}
+
+// ContextHealthChecks is a test double for jujuc.ContextHealthChecks.
+type ContextHealthChecks struct {
+	contextBase
+	info *HealthChecks
+}
+
+// SetHealthCheck implements jujuc.ContextHealthChecks.
+func (c *ContextHealthChecks) SetHealthCheck(check healthcheck.Check) error {
+	c.stub.AddCall("SetHealthCheck", check)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	if c.info.Checks == nil {
+		c.info.Checks = make(map[string]healthcheck.Check)
+	}
+	c.info.Checks[check.Name] = check
+	return nil
+}
+
+// RemoveHealthCheck implements jujuc.ContextHealthChecks.
+func (c *ContextHealthChecks) RemoveHealthCheck(name string) error {
+	c.stub.AddCall("RemoveHealthCheck", name)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	delete(c.info.Checks, name)
+	return nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"github.com/juju/errors"
+)
+
+// PodSpec holds values for the hook context.
+type PodSpec struct {
+	Spec string
+}
+
+// ContextPodSpec is a test double for jujuc.ContextPodSpec.
+type ContextPodSpec struct {
+	contextBase
+	info *PodSpec
+}
+
+// SetPodSpec implements jujuc.ContextPodSpec.
+func (c *ContextPodSpec) SetPodSpec(spec string) error {
+	c.stub.AddCall("SetPodSpec", spec)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	c.info.Spec = spec
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+)
+
+// SecretGrant records a grant of access to a secret.
+type SecretGrant struct {
+	RelationId int
+	UnitName   string
+}
+
+// Secrets holds values for the hook context.
+type Secrets struct {
+	Values map[string]map[string]string
+	Labels map[string]string
+	Grants map[string][]SecretGrant
+}
+
+// ContextSecrets is a test double for jujuc.ContextSecrets.
+type ContextSecrets struct {
+	contextBase
+	info *Secrets
+}
+
+// AddSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) AddSecret(label string, value map[string]string) (string, error) {
+	c.stub.AddCall("AddSecret", label, value)
+	if err := c.stub.NextErr(); err != nil {
+		return "", errors.Trace(err)
+	}
+	if c.info.Values == nil {
+		c.info.Values = make(map[string]map[string]string)
+		c.info.Labels = make(map[string]string)
+	}
+	id := fmt.Sprintf("secret-%d", len(c.info.Values))
+	c.info.Values[id] = value
+	c.info.Labels[id] = label
+	return id, nil
+}
+
+// RotateSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) RotateSecret(id string, value map[string]string) error {
+	c.stub.AddCall("RotateSecret", id, value)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := c.info.Values[id]; !ok {
+		return errors.NotFoundf("secret %q", id)
+	}
+	c.info.Values[id] = value
+	return nil
+}
+
+// GetSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) GetSecret(id string) (map[string]string, error) {
+	c.stub.AddCall("GetSecret", id)
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	value, ok := c.info.Values[id]
+	if !ok {
+		return nil, errors.NotFoundf("secret %q", id)
+	}
+	return value, nil
+}
+
+// GrantSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) GrantSecret(id string, relationId int, unitName string) error {
+	c.stub.AddCall("GrantSecret", id, relationId, unitName)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := c.info.Values[id]; !ok {
+		return errors.NotFoundf("secret %q", id)
+	}
+	if c.info.Grants == nil {
+		c.info.Grants = make(map[string][]SecretGrant)
+	}
+	c.info.Grants[id] = append(c.info.Grants[id], SecretGrant{relationId, unitName})
+	return nil
+}
@@ -12,12 +12,17 @@ import (
 	"github.com/juju/loggo"
 )
 
+// hookOutputTailSize is the amount of a hook's output, from the end,
+// that is kept with the record of its execution.
+const hookOutputTailSize = 4096
+
 type hookLogger struct {
 	r       io.ReadCloser
 	done    chan struct{}
 	mu      sync.Mutex
 	stopped bool
 	logger  loggo.Logger
+	tail    []byte
 }
 
 func (l *hookLogger) run() {
@@ -38,10 +43,29 @@ func (l *hookLogger) run() {
 			return
 		}
 		l.logger.Debugf("%s", line)
+		l.appendTail(line)
 		l.mu.Unlock()
 	}
 }
 
+// appendTail adds the line to the tail of the output, discarding the
+// oldest output beyond hookOutputTailSize. It must be called with
+// l.mu held.
+func (l *hookLogger) appendTail(line []byte) {
+	l.tail = append(l.tail, line...)
+	l.tail = append(l.tail, '\n')
+	if excess := len(l.tail) - hookOutputTailSize; excess > 0 {
+		l.tail = append(l.tail[:0], l.tail[excess:]...)
+	}
+}
+
+// output returns the tail of the output logged.
+func (l *hookLogger) output() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return string(l.tail)
+}
+
 func (l *hookLogger) stop() {
 	// We can see the process exit before the logger has processed
 	// all its output, so allow a moment for the data buffered
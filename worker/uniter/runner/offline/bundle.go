@@ -0,0 +1,174 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offline
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// snapshotFile is the name of the file holding the snapshot in a
+	// hook context bundle.
+	snapshotFile = "context.yaml"
+
+	// BundleCharmDir is the name of the directory holding the charm
+	// in a hook context bundle, and in the directory into which the
+	// bundle is extracted.
+	BundleCharmDir = "charm"
+)
+
+// WriteBundle writes a hook context bundle, holding the snapshot and
+// the contents of the charm directory, to w in gzipped tar format.
+func WriteBundle(w io.Writer, snapshot *Snapshot, charmDir string) (err error) {
+	data, err := goyaml.Marshal(snapshot)
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal snapshot")
+	}
+	gzw := gzip.NewWriter(w)
+	defer closeErrorCheck(&err, gzw)
+	tarw := tar.NewWriter(gzw)
+	defer closeErrorCheck(&err, tarw)
+
+	err = tarw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     snapshotFile,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  snapshot.Captured,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := tarw.Write(data); err != nil {
+		return errors.Trace(err)
+	}
+	err = filepath.Walk(charmDir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(charmDir, file)
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		} else if !info.IsDir() && !info.Mode().IsRegular() {
+			// Sockets, pipes and devices can't be replayed.
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(BundleCharmDir, filepath.ToSlash(rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tarw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tarw, f)
+		return err
+	})
+	return errors.Annotatef(err, "cannot archive charm directory %q", charmDir)
+}
+
+// ReadBundle extracts the hook context bundle read from r into dir,
+// which must exist, and returns the snapshot held in the bundle. The
+// charm is extracted into the BundleCharmDir directory within dir.
+func ReadBundle(r io.Reader, dir string) (*Snapshot, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read hook context bundle")
+	}
+	defer gzr.Close()
+	tarr := tar.NewReader(gzr)
+
+	var snapshot *Snapshot
+	for {
+		header, err := tarr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Annotate(err, "cannot read hook context bundle")
+		}
+		name := path.Clean(header.Name)
+		if name == snapshotFile {
+			data, err := ioutil.ReadAll(tarr)
+			if err != nil {
+				return nil, errors.Annotate(err, "cannot read snapshot")
+			}
+			snapshot = new(Snapshot)
+			if err := goyaml.Unmarshal(data, snapshot); err != nil {
+				return nil, errors.Annotate(err, "cannot unmarshal snapshot")
+			}
+			continue
+		}
+		if name != BundleCharmDir && !strings.HasPrefix(name, BundleCharmDir+"/") {
+			return nil, errors.NotValidf("hook context bundle entry %q", header.Name)
+		}
+		if err := extractEntry(tarr, header, filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			return nil, errors.Annotatef(err, "cannot extract %q", header.Name)
+		}
+	}
+	if snapshot == nil {
+		return nil, errors.NotValidf("hook context bundle without %s", snapshotFile)
+	}
+	return snapshot, nil
+}
+
+func extractEntry(r io.Reader, header *tar.Header, target string) error {
+	mode := os.FileMode(header.Mode).Perm()
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, mode|0700)
+	case tar.TypeSymlink:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.Symlink(header.Linkname, target)
+	case tar.TypeReg, tar.TypeRegA:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return errors.Errorf("unexpected entry type %q", header.Typeflag)
+}
+
+func closeErrorCheck(errp *error, c io.Closer) {
+	err := c.Close()
+	if *errp == nil {
+		*errp = errors.Trace(err)
+	}
+}
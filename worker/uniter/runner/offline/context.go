@@ -0,0 +1,650 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package offline runs charm hooks outside of a unit agent. The hook
+// tools invoked by a hook are served by a jujuc.Server, as they are by
+// the unit agent, but they run against a Context that holds the unit's
+// state in memory rather than against a controller.
+package offline
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/relation"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/worker/uniter/healthcheck"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+// errIsNotLeader is returned when a unit that is not the leader
+// attempts something that only the leader may do.
+var errIsNotLeader = errors.Errorf("this unit is not the leader")
+
+// State holds the state of a unit as seen by its hooks. A Context
+// reads and updates the State as the hook tools are run.
+type State struct {
+	// UnitName is the name of the unit.
+	UnitName string
+
+	// Config holds the charm config settings of the application.
+	Config charm.Settings
+
+	// UnitStatus and ApplicationStatus hold the status of the unit
+	// and of its application.
+	UnitStatus        jujuc.StatusInfo
+	ApplicationStatus jujuc.StatusInfo
+
+	// WorkloadVersion is the workload version of the unit.
+	WorkloadVersion string
+
+	// Leader records whether the unit is the leader of its
+	// application.
+	Leader bool
+
+	// LeaderSettings holds the settings of the application's leader.
+	LeaderSettings map[string]string
+
+	// AvailabilityZone, PublicAddress and PrivateAddress describe the
+	// unit's machine.
+	AvailabilityZone string
+	PublicAddress    string
+	PrivateAddress   string
+
+	// Ports holds the port ranges opened by the unit.
+	Ports []network.PortRange
+
+	// NetworkInfo holds the network information reported for each
+	// endpoint binding.
+	NetworkInfo map[string]params.NetworkInfoResult
+
+	// Relations holds the relations of the unit, by relation id.
+	Relations map[int]*Relation
+
+	// Storage holds the storage attached to the unit, by storage id.
+	Storage map[string]*StorageAttachment
+
+	// AddedStorage records the storage requested with storage-add.
+	AddedStorage map[string][]params.StorageConstraints
+
+	// Metrics records the metrics added with add-metric.
+	Metrics []Metric
+
+	// HealthChecks holds the health checks set with
+	// health-check-set, by name.
+	HealthChecks map[string]healthcheck.Check
+
+	// Secrets holds the secrets visible to the unit, by secret id.
+	Secrets map[string]*Secret
+
+	// PodSpec records the pod spec set with pod-spec-set.
+	PodSpec string
+
+	// RebootPriority records any reboot requested with juju-reboot.
+	RebootPriority jujuc.RebootPriority
+
+	// ActionParams holds the parameters of the action being run; it
+	// is nil when a hook is being run.
+	ActionParams map[string]interface{}
+
+	// ActionResults, ActionMessage and ActionFailed record the
+	// outcome of the action being run.
+	ActionResults map[string]interface{}
+	ActionMessage string
+	ActionFailed  bool
+}
+
+// Relation holds the state of one of the unit's relations.
+type Relation struct {
+	// Id is the relation's id.
+	Id int
+
+	// Name is the name of the unit's endpoint of the relation.
+	Name string
+
+	// Settings holds the unit's own settings in the relation.
+	Settings map[string]string
+
+	// Units holds the settings of the remote units in the relation,
+	// by unit name.
+	Units map[string]map[string]string
+
+	// Suspended records whether the relation is suspended.
+	Suspended bool
+
+	// Status holds the status of the relation.
+	Status relation.Status
+}
+
+// Secret holds a secret visible to the unit.
+type Secret struct {
+	// Label is the label given to the secret by its owner.
+	Label string
+
+	// Value holds the secret's value.
+	Value map[string]string
+
+	// Revision is incremented each time the secret is rotated.
+	Revision int
+
+	// Grants records the grants made with secret-grant.
+	Grants []SecretGrant
+}
+
+// SecretGrant records access to a secret granted over a relation.
+type SecretGrant struct {
+	RelationId int
+	UnitName   string
+}
+
+// StorageAttachment describes storage attached to the unit.
+type StorageAttachment struct {
+	Kind     storage.StorageKind
+	Location string
+}
+
+// Metric is a metric added by a hook.
+type Metric struct {
+	Key   string
+	Value string
+	Time  time.Time
+}
+
+// Context is an in-memory implementation of jujuc.Context.
+type Context struct {
+	state *State
+
+	hookRelationId int
+	remoteUnitName string
+	hookStorageId  string
+}
+
+var _ jujuc.Context = (*Context)(nil)
+
+// NewContext returns a Context that runs hook tools against the
+// given state. The context is not that of a relation or storage
+// hook until SetRelationHook or SetStorageHook is called.
+func NewContext(state *State) *Context {
+	return &Context{
+		state:          state,
+		hookRelationId: -1,
+	}
+}
+
+// State returns the state of the unit against which the context runs.
+func (ctx *Context) State() *State {
+	return ctx.state
+}
+
+// SetRelationHook makes the context that of a hook of the relation
+// with the given id. The remote unit may be empty.
+func (ctx *Context) SetRelationHook(id int, remoteUnitName string) error {
+	if _, ok := ctx.state.Relations[id]; !ok {
+		return errors.NotFoundf("relation %d", id)
+	}
+	ctx.hookRelationId = id
+	ctx.remoteUnitName = remoteUnitName
+	return nil
+}
+
+// SetStorageHook makes the context that of a hook of the storage with
+// the given id.
+func (ctx *Context) SetStorageHook(id string) error {
+	if _, ok := ctx.state.Storage[id]; !ok {
+		return errors.NotFoundf("storage %q", id)
+	}
+	ctx.hookStorageId = id
+	return nil
+}
+
+// UnitName implements jujuc.ContextUnit.
+func (ctx *Context) UnitName() string {
+	return ctx.state.UnitName
+}
+
+// ConfigSettings implements jujuc.ContextUnit.
+func (ctx *Context) ConfigSettings() (charm.Settings, error) {
+	settings := make(charm.Settings)
+	for key, value := range ctx.state.Config {
+		settings[key] = value
+	}
+	return settings, nil
+}
+
+// UnitStatus implements jujuc.ContextStatus.
+func (ctx *Context) UnitStatus() (*jujuc.StatusInfo, error) {
+	status := ctx.state.UnitStatus
+	return &status, nil
+}
+
+// SetUnitStatus implements jujuc.ContextStatus.
+func (ctx *Context) SetUnitStatus(status jujuc.StatusInfo) error {
+	ctx.state.UnitStatus = status
+	return nil
+}
+
+// ApplicationStatus implements jujuc.ContextStatus.
+func (ctx *Context) ApplicationStatus() (jujuc.ApplicationStatusInfo, error) {
+	if !ctx.state.Leader {
+		return jujuc.ApplicationStatusInfo{}, errIsNotLeader
+	}
+	unitStatus := ctx.state.UnitStatus
+	unitStatus.Tag = names.NewUnitTag(ctx.state.UnitName).String()
+	return jujuc.ApplicationStatusInfo{
+		Application: ctx.state.ApplicationStatus,
+		Units:       []jujuc.StatusInfo{unitStatus},
+	}, nil
+}
+
+// SetApplicationStatus implements jujuc.ContextStatus.
+func (ctx *Context) SetApplicationStatus(status jujuc.StatusInfo) error {
+	if !ctx.state.Leader {
+		return errIsNotLeader
+	}
+	ctx.state.ApplicationStatus = status
+	return nil
+}
+
+// AvailabilityZone implements jujuc.ContextInstance.
+func (ctx *Context) AvailabilityZone() (string, error) {
+	if ctx.state.AvailabilityZone == "" {
+		return "", errors.NotFoundf("availability zone")
+	}
+	return ctx.state.AvailabilityZone, nil
+}
+
+// RequestReboot implements jujuc.ContextInstance.
+func (ctx *Context) RequestReboot(priority jujuc.RebootPriority) error {
+	ctx.state.RebootPriority = priority
+	return nil
+}
+
+// PublicAddress implements jujuc.ContextNetworking.
+func (ctx *Context) PublicAddress() (string, error) {
+	if ctx.state.PublicAddress == "" {
+		return "", errors.NotFoundf("public address")
+	}
+	return ctx.state.PublicAddress, nil
+}
+
+// PrivateAddress implements jujuc.ContextNetworking.
+func (ctx *Context) PrivateAddress() (string, error) {
+	if ctx.state.PrivateAddress == "" {
+		return "", errors.NotFoundf("private address")
+	}
+	return ctx.state.PrivateAddress, nil
+}
+
+// OpenPorts implements jujuc.ContextNetworking.
+func (ctx *Context) OpenPorts(protocol string, fromPort, toPort int) error {
+	portRange := network.PortRange{
+		Protocol: protocol,
+		FromPort: fromPort,
+		ToPort:   toPort,
+	}
+	if err := portRange.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	for _, existing := range ctx.state.Ports {
+		if existing == portRange {
+			return nil
+		}
+		if existing.ConflictsWith(portRange) {
+			return errors.Errorf("cannot open %v (unit %q): conflicts with existing %v", portRange, ctx.state.UnitName, existing)
+		}
+	}
+	ctx.state.Ports = append(ctx.state.Ports, portRange)
+	network.SortPortRanges(ctx.state.Ports)
+	return nil
+}
+
+// ClosePorts implements jujuc.ContextNetworking.
+func (ctx *Context) ClosePorts(protocol string, fromPort, toPort int) error {
+	portRange := network.PortRange{
+		Protocol: protocol,
+		FromPort: fromPort,
+		ToPort:   toPort,
+	}
+	if err := portRange.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	for i, existing := range ctx.state.Ports {
+		if existing == portRange {
+			ctx.state.Ports = append(ctx.state.Ports[:i], ctx.state.Ports[i+1:]...)
+			return nil
+		}
+		if existing.ConflictsWith(portRange) {
+			return errors.Errorf("cannot close %v (unit %q): conflicts with existing %v", portRange, ctx.state.UnitName, existing)
+		}
+	}
+	return nil
+}
+
+// OpenedPorts implements jujuc.ContextNetworking.
+func (ctx *Context) OpenedPorts() []network.PortRange {
+	ports := make([]network.PortRange, len(ctx.state.Ports))
+	copy(ports, ctx.state.Ports)
+	return ports
+}
+
+// NetworkInfo implements jujuc.ContextNetworking.
+func (ctx *Context) NetworkInfo(bindingNames []string, relationId int) (map[string]params.NetworkInfoResult, error) {
+	results := make(map[string]params.NetworkInfoResult)
+	for _, name := range bindingNames {
+		result, ok := ctx.state.NetworkInfo[name]
+		if !ok {
+			result.Error = &params.Error{
+				Message: errors.NotFoundf("binding %q", name).Error(),
+				Code:    params.CodeNotFound,
+			}
+		}
+		results[name] = result
+	}
+	return results, nil
+}
+
+// IsLeader implements jujuc.ContextLeadership.
+func (ctx *Context) IsLeader() (bool, error) {
+	return ctx.state.Leader, nil
+}
+
+// LeaderSettings implements jujuc.ContextLeadership.
+func (ctx *Context) LeaderSettings() (map[string]string, error) {
+	settings := make(map[string]string)
+	for key, value := range ctx.state.LeaderSettings {
+		settings[key] = value
+	}
+	return settings, nil
+}
+
+// WriteLeaderSettings implements jujuc.ContextLeadership. As with
+// the controller, settings with empty values are removed.
+func (ctx *Context) WriteLeaderSettings(settings map[string]string) error {
+	if !ctx.state.Leader {
+		return errors.Annotate(errIsNotLeader, "cannot write settings")
+	}
+	if ctx.state.LeaderSettings == nil {
+		ctx.state.LeaderSettings = make(map[string]string)
+	}
+	for key, value := range settings {
+		if value == "" {
+			delete(ctx.state.LeaderSettings, key)
+		} else {
+			ctx.state.LeaderSettings[key] = value
+		}
+	}
+	return nil
+}
+
+// AddMetric implements jujuc.ContextMetrics.
+func (ctx *Context) AddMetric(key, value string, created time.Time) error {
+	ctx.state.Metrics = append(ctx.state.Metrics, Metric{
+		Key:   key,
+		Value: value,
+		Time:  created,
+	})
+	return nil
+}
+
+// StorageTags implements jujuc.ContextStorage.
+func (ctx *Context) StorageTags() ([]names.StorageTag, error) {
+	ids := make([]string, 0, len(ctx.state.Storage))
+	for id := range ctx.state.Storage {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	tags := make([]names.StorageTag, len(ids))
+	for i, id := range ids {
+		tags[i] = names.NewStorageTag(id)
+	}
+	return tags, nil
+}
+
+// Storage implements jujuc.ContextStorage.
+func (ctx *Context) Storage(tag names.StorageTag) (jujuc.ContextStorageAttachment, error) {
+	attachment, ok := ctx.state.Storage[tag.Id()]
+	if !ok {
+		return nil, errors.NotFoundf("storage %q", tag.Id())
+	}
+	return &contextStorageAttachment{tag, attachment}, nil
+}
+
+// HookStorage implements jujuc.ContextStorage.
+func (ctx *Context) HookStorage() (jujuc.ContextStorageAttachment, error) {
+	if ctx.hookStorageId == "" {
+		return nil, errors.NotFoundf("hook storage")
+	}
+	return ctx.Storage(names.NewStorageTag(ctx.hookStorageId))
+}
+
+// AddUnitStorage implements jujuc.ContextStorage.
+func (ctx *Context) AddUnitStorage(constraints map[string]params.StorageConstraints) error {
+	if ctx.state.AddedStorage == nil {
+		ctx.state.AddedStorage = make(map[string][]params.StorageConstraints)
+	}
+	for name, cons := range constraints {
+		ctx.state.AddedStorage[name] = append(ctx.state.AddedStorage[name], cons)
+	}
+	return nil
+}
+
+// Component implements jujuc.ContextComponents. No components are
+// available offline.
+func (ctx *Context) Component(name string) (jujuc.ContextComponent, error) {
+	return nil, errors.NotFoundf("context component %q", name)
+}
+
+// Relation implements jujuc.ContextRelations.
+func (ctx *Context) Relation(id int) (jujuc.ContextRelation, error) {
+	relation, ok := ctx.state.Relations[id]
+	if !ok {
+		return nil, errors.NotFoundf("relation")
+	}
+	return &contextRelation{relation}, nil
+}
+
+// RelationIds implements jujuc.ContextRelations.
+func (ctx *Context) RelationIds() ([]int, error) {
+	ids := make([]int, 0, len(ctx.state.Relations))
+	for id := range ctx.state.Relations {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// UnitWorkloadVersion implements jujuc.ContextVersion.
+func (ctx *Context) UnitWorkloadVersion() (string, error) {
+	return ctx.state.WorkloadVersion, nil
+}
+
+// SetUnitWorkloadVersion implements jujuc.ContextVersion.
+func (ctx *Context) SetUnitWorkloadVersion(version string) error {
+	ctx.state.WorkloadVersion = version
+	return nil
+}
+
+// SetHealthCheck implements jujuc.ContextHealthChecks.
+func (ctx *Context) SetHealthCheck(check healthcheck.Check) error {
+	if err := check.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if ctx.state.HealthChecks == nil {
+		ctx.state.HealthChecks = make(map[string]healthcheck.Check)
+	}
+	ctx.state.HealthChecks[check.Name] = check
+	return nil
+}
+
+// RemoveHealthCheck implements jujuc.ContextHealthChecks.
+func (ctx *Context) RemoveHealthCheck(name string) error {
+	delete(ctx.state.HealthChecks, name)
+	return nil
+}
+
+// AddSecret implements jujuc.ContextSecrets.
+func (ctx *Context) AddSecret(label string, value map[string]string) (string, error) {
+	if !ctx.state.Leader {
+		return "", errIsNotLeader
+	}
+	if label != "" {
+		for _, secret := range ctx.state.Secrets {
+			if secret.Label == label {
+				return "", errors.AlreadyExistsf("secret with label %q", label)
+			}
+		}
+	}
+	if ctx.state.Secrets == nil {
+		ctx.state.Secrets = make(map[string]*Secret)
+	}
+	id := fmt.Sprintf("secret-%d", len(ctx.state.Secrets))
+	ctx.state.Secrets[id] = &Secret{
+		Label:    label,
+		Value:    value,
+		Revision: 1,
+	}
+	return id, nil
+}
+
+// RotateSecret implements jujuc.ContextSecrets.
+func (ctx *Context) RotateSecret(id string, value map[string]string) error {
+	if !ctx.state.Leader {
+		return errIsNotLeader
+	}
+	secret, ok := ctx.state.Secrets[id]
+	if !ok {
+		return errors.NotFoundf("secret %q", id)
+	}
+	secret.Value = value
+	secret.Revision++
+	return nil
+}
+
+// GetSecret implements jujuc.ContextSecrets.
+func (ctx *Context) GetSecret(id string) (map[string]string, error) {
+	secret, ok := ctx.state.Secrets[id]
+	if !ok {
+		return nil, errors.NotFoundf("secret %q", id)
+	}
+	return secret.Value, nil
+}
+
+// GrantSecret implements jujuc.ContextSecrets.
+func (ctx *Context) GrantSecret(id string, relationId int, unitName string) error {
+	secret, ok := ctx.state.Secrets[id]
+	if !ok {
+		return errors.NotFoundf("secret %q", id)
+	}
+	if _, ok := ctx.state.Relations[relationId]; !ok {
+		return errors.NotFoundf("relation %d", relationId)
+	}
+	secret.Grants = append(secret.Grants, SecretGrant{
+		RelationId: relationId,
+		UnitName:   unitName,
+	})
+	return nil
+}
+
+// SetPodSpec implements jujuc.ContextPodSpec.
+func (ctx *Context) SetPodSpec(spec string) error {
+	if !ctx.state.Leader {
+		return errIsNotLeader
+	}
+	if _, err := caas.ParsePodSpec(spec); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.state.PodSpec = spec
+	return nil
+}
+
+// HookRelation implements jujuc.Context.
+func (ctx *Context) HookRelation() (jujuc.ContextRelation, error) {
+	if ctx.hookRelationId == -1 {
+		return nil, errors.NotFoundf("hook relation")
+	}
+	return ctx.Relation(ctx.hookRelationId)
+}
+
+// RemoteUnitName implements jujuc.Context.
+func (ctx *Context) RemoteUnitName() (string, error) {
+	if ctx.remoteUnitName == "" {
+		return "", errors.NotFoundf("remote unit")
+	}
+	return ctx.remoteUnitName, nil
+}
+
+// ActionParams implements jujuc.Context.
+func (ctx *Context) ActionParams() (map[string]interface{}, error) {
+	if ctx.state.ActionParams == nil {
+		return nil, errors.New("not running an action")
+	}
+	return ctx.state.ActionParams, nil
+}
+
+// UpdateActionResults implements jujuc.Context.
+func (ctx *Context) UpdateActionResults(keys []string, value string) error {
+	if ctx.state.ActionParams == nil {
+		return errors.New("not running an action")
+	}
+	if ctx.state.ActionResults == nil {
+		ctx.state.ActionResults = make(map[string]interface{})
+	}
+	target := ctx.state.ActionResults
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := target[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			target[key] = next
+		}
+		target = next
+	}
+	target[keys[len(keys)-1]] = value
+	return nil
+}
+
+// SetActionMessage implements jujuc.Context.
+func (ctx *Context) SetActionMessage(message string) error {
+	if ctx.state.ActionParams == nil {
+		return errors.New("not running an action")
+	}
+	ctx.state.ActionMessage = message
+	return nil
+}
+
+// SetActionFailed implements jujuc.Context.
+func (ctx *Context) SetActionFailed() error {
+	if ctx.state.ActionParams == nil {
+		return errors.New("not running an action")
+	}
+	ctx.state.ActionFailed = true
+	return nil
+}
+
+// contextStorageAttachment implements jujuc.ContextStorageAttachment.
+type contextStorageAttachment struct {
+	tag        names.StorageTag
+	attachment *StorageAttachment
+}
+
+// Tag implements jujuc.ContextStorageAttachment.
+func (s *contextStorageAttachment) Tag() names.StorageTag {
+	return s.tag
+}
+
+// Kind implements jujuc.ContextStorageAttachment.
+func (s *contextStorageAttachment) Kind() storage.StorageKind {
+	return s.attachment.Kind
+}
+
+// Location implements jujuc.ContextStorageAttachment.
+func (s *contextStorageAttachment) Location() string {
+	return s.attachment.Location
+}
@@ -0,0 +1,174 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offline_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	"github.com/juju/juju/worker/uniter/runner/offline"
+)
+
+type ContextSuite struct {
+	coretesting.BaseSuite
+	state *offline.State
+	ctx   *offline.Context
+}
+
+var _ = gc.Suite(&ContextSuite{})
+
+func (s *ContextSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.state = &offline.State{
+		UnitName: "wordpress/0",
+		Config:   charm.Settings{"blog-title": "My Title"},
+		Relations: map[int]*offline.Relation{
+			1: {
+				Id:       1,
+				Name:     "db",
+				Settings: map[string]string{"database": "wordpress"},
+				Units: map[string]map[string]string{
+					"mysql/0": {"host": "10.0.0.1"},
+				},
+			},
+		},
+	}
+	s.ctx = offline.NewContext(s.state)
+}
+
+func (s *ContextSuite) runHookTool(c *gc.C, name string, args ...string) (int, string, string) {
+	com, err := jujuc.NewCommand(s.ctx, name+jujuc.CmdSuffix)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, args)
+	return code, cmdtesting.Stdout(ctx), cmdtesting.Stderr(ctx)
+}
+
+func (s *ContextSuite) TestConfigGet(c *gc.C) {
+	code, stdout, _ := s.runHookTool(c, "config-get", "blog-title")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, "My Title\n")
+}
+
+func (s *ContextSuite) TestRelationSetAndGet(c *gc.C) {
+	err := s.ctx.SetRelationHook(1, "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	code, _, stderr := s.runHookTool(c, "relation-set", "url=http://wordpress/", "database=")
+	c.Assert(code, gc.Equals, 0, gc.Commentf("stderr: %s", stderr))
+	c.Assert(s.state.Relations[1].Settings, jc.DeepEquals, map[string]string{
+		"url": "http://wordpress/",
+	})
+
+	code, stdout, _ := s.runHookTool(c, "relation-get", "host")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, "10.0.0.1\n")
+}
+
+func (s *ContextSuite) TestSetRelationHookUnknown(c *gc.C) {
+	err := s.ctx.SetRelationHook(2, "")
+	c.Assert(err, gc.ErrorMatches, "relation 2 not found")
+}
+
+func (s *ContextSuite) TestLeaderSet(c *gc.C) {
+	code, _, stderr := s.runHookTool(c, "leader-set", "password=secret")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "ERROR cannot write leadership settings: cannot write settings: this unit is not the leader\n")
+
+	s.state.Leader = true
+	s.state.LeaderSettings = map[string]string{"old": "value"}
+	code, _, stderr = s.runHookTool(c, "leader-set", "password=secret", "old=")
+	c.Assert(code, gc.Equals, 0, gc.Commentf("stderr: %s", stderr))
+	c.Assert(s.state.LeaderSettings, jc.DeepEquals, map[string]string{"password": "secret"})
+}
+
+func (s *ContextSuite) TestStatusSet(c *gc.C) {
+	code, _, _ := s.runHookTool(c, "status-set", "active", "ready")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(s.state.UnitStatus, jc.DeepEquals, jujuc.StatusInfo{Status: "active", Info: "ready"})
+
+	code, _, stderr := s.runHookTool(c, "status-set", "--application", "active")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Matches, ".*this unit is not the leader\n")
+}
+
+func (s *ContextSuite) TestPorts(c *gc.C) {
+	code, _, _ := s.runHookTool(c, "open-port", "80/tcp")
+	c.Assert(code, gc.Equals, 0)
+	code, _, _ = s.runHookTool(c, "open-port", "8000-8080/tcp")
+	c.Assert(code, gc.Equals, 0)
+	code, _, stderr := s.runHookTool(c, "open-port", "8080/tcp")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Matches, "ERROR cannot open 8080/tcp .*: conflicts with existing 8000-8080/tcp\n")
+	code, _, _ = s.runHookTool(c, "close-port", "80/tcp")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(s.state.Ports, jc.DeepEquals, []network.PortRange{
+		{Protocol: "tcp", FromPort: 8000, ToPort: 8080},
+	})
+}
+
+func (s *ContextSuite) TestActionOnlyInActions(c *gc.C) {
+	code, _, stderr := s.runHookTool(c, "action-set", "result=done")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "ERROR not running an action\n")
+
+	s.state.ActionParams = map[string]interface{}{}
+	code, _, _ = s.runHookTool(c, "action-set", "outcome.result=done")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(s.state.ActionResults, jc.DeepEquals, map[string]interface{}{
+		"outcome": map[string]interface{}{"result": "done"},
+	})
+}
+
+func (s *ContextSuite) TestSecrets(c *gc.C) {
+	code, _, stderr := s.runHookTool(c, "secret-add", "password=sekrit")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "ERROR cannot add secret: this unit is not the leader\n")
+
+	s.state.Leader = true
+	code, stdout, _ := s.runHookTool(c, "secret-add", "--label", "admin", "password=sekrit")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, "secret-0\n")
+
+	code, _, _ = s.runHookTool(c, "secret-add", "--rotate", "secret-0", "password=new")
+	c.Assert(code, gc.Equals, 0)
+	code, stdout, _ = s.runHookTool(c, "secret-get", "secret-0", "password")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, gc.Equals, "new\n")
+
+	code, _, _ = s.runHookTool(c, "secret-grant", "secret-0", "-r", "1", "--unit", "mysql/0")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(s.state.Secrets["secret-0"], jc.DeepEquals, &offline.Secret{
+		Label:    "admin",
+		Value:    map[string]string{"password": "new"},
+		Revision: 2,
+		Grants:   []offline.SecretGrant{{RelationId: 1, UnitName: "mysql/0"}},
+	})
+}
+
+func (s *ContextSuite) TestPodSpec(c *gc.C) {
+	spec := "containers:\n  - name: wordpress\n    image: wordpress/latest\n"
+	path := filepath.Join(c.MkDir(), "spec.yaml")
+	err := ioutil.WriteFile(path, []byte(spec), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	code, _, stderr := s.runHookTool(c, "pod-spec-set", "--file", path)
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "ERROR cannot set pod spec: this unit is not the leader\n")
+
+	s.state.Leader = true
+	code, _, stderr = s.runHookTool(c, "pod-spec-set", "--file", path)
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stderr, gc.Equals, "")
+	c.Assert(s.state.PodSpec, gc.Equals, spec)
+}
@@ -0,0 +1,178 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offline
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/symlink"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+var logger = loggo.GetLogger("juju.worker.uniter.runner.offline")
+
+// HookConfig holds the configuration with which hooks are run
+// offline.
+type HookConfig struct {
+	// CharmDir is the directory holding the charm whose hooks are run.
+	CharmDir string
+
+	// Jujud is the path of the jujud executable, to which the hook
+	// tools are linked.
+	Jujud string
+
+	// Env holds the environment variables with which the hooks are
+	// run, in addition to those describing the hook context.
+	Env []string
+
+	// Stdout and Stderr receive the output of the hooks.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Validate returns an error if the config is not valid.
+func (config HookConfig) Validate() error {
+	if config.CharmDir == "" {
+		return errors.NotValidf("empty CharmDir")
+	}
+	if config.Jujud == "" {
+		return errors.NotValidf("empty Jujud")
+	}
+	return nil
+}
+
+// RunHook runs the named hook of the charm against ctx, with the hook
+// tools served from a jujuc.Server bound to ctx. The error returned
+// satisfies errors.IsNotFound if the charm doesn't implement the hook.
+func RunHook(config HookConfig, ctx *Context, hookName string) error {
+	return runCharmHook(config, ctx, "hooks", hookName)
+}
+
+// RunAction runs the named action of the charm against ctx, whose
+// state's ActionParams must be set.
+func RunAction(config HookConfig, ctx *Context, actionName string) error {
+	if ctx.state.ActionParams == nil {
+		return errors.New("action parameters not set")
+	}
+	return runCharmHook(config, ctx, "actions", actionName)
+}
+
+func runCharmHook(config HookConfig, ctx *Context, location, hookName string) error {
+	if err := config.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	hookPath := filepath.Join(config.CharmDir, location, hookName)
+	if _, err := os.Stat(hookPath); os.IsNotExist(err) {
+		return errors.NotFoundf("charm %s %q", strings.TrimSuffix(location, "s"), hookName)
+	}
+
+	dir, err := ioutil.TempDir("", "juju-offline-hook")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+
+	toolsDir := filepath.Join(dir, "tools")
+	if err := ensureHookTools(toolsDir, config.Jujud); err != nil {
+		return errors.Trace(err)
+	}
+	contextId := fmt.Sprintf("%s-%s-offline", ctx.UnitName(), hookName)
+	socketPath := filepath.Join(dir, "agent.socket")
+	getCmd := func(ctxId, cmdName string) (cmd.Command, error) {
+		if ctxId != contextId {
+			return nil, errors.Errorf("expected context id %q, got %q", contextId, ctxId)
+		}
+		return jujuc.NewCommand(ctx, cmdName)
+	}
+	srv, err := jujuc.NewServer(getCmd, socketPath)
+	if err != nil {
+		return errors.Annotate(err, "starting jujuc server")
+	}
+	go srv.Run()
+	defer srv.Close()
+
+	env, err := hookEnv(config, ctx, location, hookName, contextId, socketPath, toolsDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Debugf("running %s %q of %s offline", location, hookName, ctx.UnitName())
+	ps := exec.Command(hookPath)
+	ps.Env = env
+	ps.Dir = config.CharmDir
+	ps.Stdout = config.Stdout
+	ps.Stderr = config.Stderr
+	return errors.Trace(ps.Run())
+}
+
+// ensureHookTools creates a link to jujud within dir for each hook
+// tool.
+func ensureHookTools(dir, jujud string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range jujuc.CommandNames() {
+		if err := symlink.New(jujud, filepath.Join(dir, name)); err != nil {
+			return errors.Annotatef(err, "cannot create hook tool %q", name)
+		}
+	}
+	return nil
+}
+
+// hookEnv returns the environment for running the named hook or
+// action against ctx. The configured environment is overridden by the variables that
+// describe the hook context, and the hook tools are put first in the
+// PATH.
+func hookEnv(config HookConfig, ctx *Context, location, hookName, contextId, socketPath, toolsDir string) ([]string, error) {
+	vars := map[string]string{
+		"CHARM_DIR":         config.CharmDir,
+		"JUJU_CHARM_DIR":    config.CharmDir,
+		"JUJU_CONTEXT_ID":   contextId,
+		"JUJU_AGENT_SOCKET": socketPath,
+		"JUJU_UNIT_NAME":    ctx.UnitName(),
+	}
+	if location == "actions" {
+		vars["JUJU_ACTION_NAME"] = hookName
+	}
+	if relation, err := ctx.HookRelation(); err == nil {
+		vars["JUJU_RELATION"] = relation.Name()
+		vars["JUJU_RELATION_ID"] = relation.FakeId()
+		vars["JUJU_REMOTE_UNIT"] = ctx.remoteUnitName
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+
+	path := os.Getenv("PATH")
+	var env []string
+	for _, kv := range config.Env {
+		key := strings.SplitN(kv, "=", 2)[0]
+		if key == "PATH" {
+			path = strings.TrimPrefix(kv, "PATH=")
+			continue
+		}
+		if _, ok := vars[key]; ok {
+			continue
+		}
+		if strings.HasPrefix(key, "JUJU_RELATION") || strings.HasPrefix(key, "JUJU_ACTION_") || key == "JUJU_REMOTE_UNIT" {
+			// Only the hook context decides what relation or
+			// action the hook is run for.
+			continue
+		}
+		env = append(env, kv)
+	}
+	for key, value := range vars {
+		env = append(env, key+"="+value)
+	}
+	env = append(env, "PATH="+toolsDir+string(os.PathListSeparator)+path)
+	return env, nil
+}
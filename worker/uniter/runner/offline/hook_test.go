@@ -0,0 +1,96 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offline_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/offline"
+)
+
+type HookSuite struct {
+	coretesting.BaseSuite
+	charmDir string
+	config   offline.HookConfig
+	stdout   bytes.Buffer
+	ctx      *offline.Context
+}
+
+var _ = gc.Suite(&HookSuite{})
+
+func (s *HookSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("hooks are run offline only on unix")
+	}
+	s.BaseSuite.SetUpTest(c)
+	s.charmDir = c.MkDir()
+	err := os.Mkdir(filepath.Join(s.charmDir, "hooks"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stdout.Reset()
+	s.config = offline.HookConfig{
+		CharmDir: s.charmDir,
+		Jujud:    "/path/to/jujud",
+		Env: []string{
+			"JUJU_MODEL_NAME=default",
+			"JUJU_CONTEXT_ID=wordpress/0-db-relation-changed-42",
+			"JUJU_RELATION_ID=db:7",
+		},
+		Stdout: &s.stdout,
+	}
+	s.ctx = offline.NewContext(&offline.State{
+		UnitName:  "wordpress/0",
+		Relations: map[int]*offline.Relation{1: {Id: 1, Name: "db"}},
+	})
+}
+
+func (s *HookSuite) writeHook(c *gc.C, name, script string) {
+	err := ioutil.WriteFile(filepath.Join(s.charmDir, "hooks", name), []byte("#!/bin/bash\n"+script), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *HookSuite) TestRunHookEnvironment(c *gc.C) {
+	s.writeHook(c, "db-relation-changed", `
+echo $JUJU_UNIT_NAME $JUJU_MODEL_NAME $JUJU_CONTEXT_ID
+echo $JUJU_RELATION $JUJU_RELATION_ID $JUJU_REMOTE_UNIT
+echo $PWD
+readlink ${PATH%%:*}/relation-get
+`)
+	err := s.ctx.SetRelationHook(1, "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = offline.RunHook(s.config, s.ctx, "db-relation-changed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.stdout.String(), gc.Equals, ""+
+		"wordpress/0 default wordpress/0-db-relation-changed-offline\n"+
+		"db db:1 mysql/0\n"+
+		s.charmDir+"\n"+
+		"/path/to/jujud\n")
+}
+
+func (s *HookSuite) TestRunHookFails(c *gc.C) {
+	s.writeHook(c, "install", "exit 3\n")
+	err := offline.RunHook(s.config, s.ctx, "install")
+	c.Assert(err, gc.ErrorMatches, "exit status 3")
+}
+
+func (s *HookSuite) TestRunHookMissing(c *gc.C) {
+	err := offline.RunHook(s.config, s.ctx, "install")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `charm hook "install" not found`)
+}
+
+func (s *HookSuite) TestRunHookInvalidConfig(c *gc.C) {
+	s.config.Jujud = ""
+	err := offline.RunHook(s.config, s.ctx, "install")
+	c.Assert(err, gc.ErrorMatches, "empty Jujud not valid")
+}
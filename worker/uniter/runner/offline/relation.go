@@ -0,0 +1,95 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offline
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/relation"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+// contextRelation implements jujuc.ContextRelation for a Relation.
+type contextRelation struct {
+	relation *Relation
+}
+
+// Id implements jujuc.ContextRelation.
+func (r *contextRelation) Id() int {
+	return r.relation.Id
+}
+
+// Name implements jujuc.ContextRelation.
+func (r *contextRelation) Name() string {
+	return r.relation.Name
+}
+
+// FakeId implements jujuc.ContextRelation.
+func (r *contextRelation) FakeId() string {
+	return fmt.Sprintf("%s:%d", r.relation.Name, r.relation.Id)
+}
+
+// Settings implements jujuc.ContextRelation.
+func (r *contextRelation) Settings() (jujuc.Settings, error) {
+	if r.relation.Settings == nil {
+		r.relation.Settings = make(map[string]string)
+	}
+	return settings(r.relation.Settings), nil
+}
+
+// UnitNames implements jujuc.ContextRelation.
+func (r *contextRelation) UnitNames() []string {
+	var unitNames []string
+	for unitName := range r.relation.Units {
+		unitNames = append(unitNames, unitName)
+	}
+	sort.Strings(unitNames)
+	return unitNames
+}
+
+// ReadSettings implements jujuc.ContextRelation.
+func (r *contextRelation) ReadSettings(unitName string) (params.Settings, error) {
+	unitSettings, ok := r.relation.Units[unitName]
+	if !ok {
+		return nil, errors.NotFoundf("settings for unit %q in relation %d", unitName, r.relation.Id)
+	}
+	return settings(unitSettings).Map(), nil
+}
+
+// Suspended implements jujuc.ContextRelation.
+func (r *contextRelation) Suspended() bool {
+	return r.relation.Suspended
+}
+
+// SetStatus implements jujuc.ContextRelation.
+func (r *contextRelation) SetStatus(status relation.Status) error {
+	r.relation.Status = status
+	return nil
+}
+
+// settings implements jujuc.Settings, updating the map in place.
+type settings map[string]string
+
+// Map implements jujuc.Settings.
+func (s settings) Map() params.Settings {
+	result := make(params.Settings)
+	for key, value := range s {
+		result[key] = value
+	}
+	return result
+}
+
+// Set implements jujuc.Settings.
+func (s settings) Set(key, value string) {
+	s[key] = value
+}
+
+// Delete implements jujuc.Settings.
+func (s settings) Delete(key string) {
+	delete(s, key)
+}
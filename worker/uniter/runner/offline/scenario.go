@@ -0,0 +1,476 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offline
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+	goyaml "gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+// Scenario describes a sequence of hooks and actions to run against a
+// unit, and the state the unit is expected to be left in.
+type Scenario struct {
+	// Unit is the name of the unit the charm is deployed as.
+	Unit string `yaml:"unit"`
+
+	// State holds the state of the unit before the first step.
+	State ScenarioState `yaml:"state,omitempty"`
+
+	// Steps holds the hooks and actions to run, in order.
+	Steps []ScenarioStep `yaml:"steps"`
+
+	// Expect holds the expectations checked once every step has run.
+	Expect *Expectation `yaml:"expect,omitempty"`
+}
+
+// ScenarioState describes the state of a unit.
+type ScenarioState struct {
+	Config           map[string]interface{}     `yaml:"config,omitempty"`
+	Leader           bool                       `yaml:"leader,omitempty"`
+	LeaderSettings   map[string]string          `yaml:"leader-settings,omitempty"`
+	Relations        []RelationSnapshot         `yaml:"relations,omitempty"`
+	Storage          map[string]StorageSnapshot `yaml:"storage,omitempty"`
+	Ports            []string                   `yaml:"ports,omitempty"`
+	UnitStatus       *ScenarioStatus            `yaml:"unit-status,omitempty"`
+	AvailabilityZone string                     `yaml:"availability-zone,omitempty"`
+	PublicAddress    string                     `yaml:"public-address,omitempty"`
+	PrivateAddress   string                     `yaml:"private-address,omitempty"`
+}
+
+// StorageSnapshot describes storage attached to a unit.
+type StorageSnapshot struct {
+	// Kind is either "block" or "filesystem".
+	Kind     string `yaml:"kind"`
+	Location string `yaml:"location"`
+}
+
+// ScenarioStatus describes a workload status.
+type ScenarioStatus struct {
+	Status  string `yaml:"status"`
+	Message string `yaml:"message,omitempty"`
+}
+
+// ScenarioStep describes a hook or action to run, and the changes to
+// the unit's state that lead to it being run.
+type ScenarioStep struct {
+	// Hook is the name of the hook to run; exactly one of Hook and
+	// Action must be set.
+	Hook string `yaml:"hook,omitempty"`
+
+	// Action is the name of the action to run, and Params holds its
+	// parameters.
+	Action string                 `yaml:"action,omitempty"`
+	Params map[string]interface{} `yaml:"params,omitempty"`
+
+	// Relation is the id of the relation of a relation hook, and
+	// RemoteUnit the name of the remote unit the hook is run for.
+	// RemoteSettings, if set, replace the remote unit's settings in
+	// the relation before the hook is run.
+	Relation       *int              `yaml:"relation,omitempty"`
+	RemoteUnit     string            `yaml:"remote-unit,omitempty"`
+	RemoteSettings map[string]string `yaml:"remote-settings,omitempty"`
+
+	// Storage is the id of the storage of a storage hook.
+	Storage string `yaml:"storage,omitempty"`
+
+	// Config holds config settings changed before the hook is run.
+	Config map[string]interface{} `yaml:"config,omitempty"`
+
+	// Leader, if set, changes the leadership of the unit before the
+	// hook is run.
+	Leader *bool `yaml:"leader,omitempty"`
+
+	// Fails records that the hook or action is expected to fail.
+	Fails bool `yaml:"fails,omitempty"`
+
+	// Expect holds the expectations checked once the step has run.
+	Expect *Expectation `yaml:"expect,omitempty"`
+}
+
+// Expectation describes the expected state of a unit. Only the
+// aspects of the state that are set are checked.
+type Expectation struct {
+	UnitStatus        *ScenarioStatus `yaml:"unit-status,omitempty"`
+	ApplicationStatus *ScenarioStatus `yaml:"application-status,omitempty"`
+	WorkloadVersion   *string         `yaml:"workload-version,omitempty"`
+
+	// LeaderSettings and RelationSettings hold the expected values of
+	// the listed settings; an empty value means the setting is unset.
+	LeaderSettings   map[string]string         `yaml:"leader-settings,omitempty"`
+	RelationSettings map[int]map[string]string `yaml:"relation-settings,omitempty"`
+
+	// Ports holds the port ranges that are expected to be open, and
+	// no others.
+	Ports []string `yaml:"ports,omitempty"`
+
+	// StorageAdded holds the number of storage instances expected to
+	// have been requested with storage-add, by storage name.
+	StorageAdded map[string]uint64 `yaml:"storage-added,omitempty"`
+
+	// Reboot records whether a reboot is expected to have been
+	// requested.
+	Reboot *bool `yaml:"reboot,omitempty"`
+
+	// ActionResults holds the expected results of the last action,
+	// keyed as passed to action-set; ActionFailed records whether it
+	// is expected to have called action-fail.
+	ActionResults map[string]string `yaml:"action-results,omitempty"`
+	ActionFailed  *bool             `yaml:"action-failed,omitempty"`
+}
+
+// ParseScenario parses a scenario from its YAML representation.
+func ParseScenario(data []byte) (*Scenario, error) {
+	var scenario Scenario
+	if err := goyaml.Unmarshal(data, &scenario); err != nil {
+		return nil, errors.Annotate(err, "cannot parse scenario")
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &scenario, nil
+}
+
+// Validate returns an error if the scenario is not valid.
+func (s *Scenario) Validate() error {
+	if s.Unit == "" {
+		return errors.NotValidf("scenario without unit")
+	}
+	if len(s.Steps) == 0 {
+		return errors.NotValidf("scenario without steps")
+	}
+	for i, step := range s.Steps {
+		if (step.Hook == "") == (step.Action == "") {
+			return errors.NotValidf("step %d: expected one of hook or action", i+1)
+		}
+		if step.Action != "" && (step.Relation != nil || step.Storage != "") {
+			return errors.NotValidf("step %d: action with relation or storage", i+1)
+		}
+		if step.Relation == nil && (step.RemoteUnit != "" || step.RemoteSettings != nil) {
+			return errors.NotValidf("step %d: remote unit without relation", i+1)
+		}
+		if step.RemoteSettings != nil && step.RemoteUnit == "" {
+			return errors.NotValidf("step %d: remote settings without remote unit", i+1)
+		}
+	}
+	return nil
+}
+
+// NewState returns the unit state described by the scenario state.
+func (s ScenarioState) NewState(unitName string) (*State, error) {
+	snapshot := &Snapshot{
+		Unit:             unitName,
+		Config:           s.Config,
+		Leader:           s.Leader,
+		LeaderSettings:   s.LeaderSettings,
+		Relations:        s.Relations,
+		AvailabilityZone: s.AvailabilityZone,
+		PublicAddress:    s.PublicAddress,
+		PrivateAddress:   s.PrivateAddress,
+	}
+	state := snapshot.State()
+	for id, attachment := range s.Storage {
+		var kind storage.StorageKind
+		switch attachment.Kind {
+		case "block":
+			kind = storage.StorageKindBlock
+		case "filesystem":
+			kind = storage.StorageKindFilesystem
+		default:
+			return nil, errors.NotValidf("storage %q kind %q", id, attachment.Kind)
+		}
+		if state.Storage == nil {
+			state.Storage = make(map[string]*StorageAttachment)
+		}
+		state.Storage[id] = &StorageAttachment{
+			Kind:     kind,
+			Location: attachment.Location,
+		}
+	}
+	for _, port := range s.Ports {
+		portRange, err := network.ParsePortRange(port)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		state.Ports = append(state.Ports, portRange)
+	}
+	network.SortPortRanges(state.Ports)
+	if s.UnitStatus != nil {
+		state.UnitStatus = jujuc.StatusInfo{
+			Status: s.UnitStatus.Status,
+			Info:   s.UnitStatus.Message,
+		}
+	}
+	return state, nil
+}
+
+// ScenarioResult holds the outcome of running a scenario.
+type ScenarioResult struct {
+	// State holds the state of the unit after the scenario has run.
+	State *State
+
+	// Failures describes the steps that failed and the expectations
+	// that were not met; the scenario passed if it is empty.
+	Failures []string
+}
+
+// Run runs the scenario's steps in order against the charm, as
+// configured, and checks its expectations. Running stops at the first
+// step that fails unexpectedly. The error returned describes a
+// problem running the scenario, rather than a failure of the charm.
+func (s *Scenario) Run(config HookConfig) (*ScenarioResult, error) {
+	if err := s.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	state, err := s.State.NewState(s.Unit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := &ScenarioResult{State: state}
+	for i, step := range s.Steps {
+		prefix := fmt.Sprintf("step %d (%s)", i+1, step.name())
+		ctx, err := step.prepare(state)
+		if err != nil {
+			return nil, errors.Annotate(err, prefix)
+		}
+		if step.Action != "" {
+			err = RunAction(config, ctx, step.Action)
+			state.ActionParams = nil
+		} else {
+			err = RunHook(config, ctx, step.Hook)
+			if errors.IsNotFound(err) {
+				// As with a unit agent, hooks the charm
+				// doesn't implement are skipped.
+				logger.Debugf("%s: skipped: %v", prefix, err)
+				err = nil
+			}
+		}
+		step.finish(state)
+		switch {
+		case err != nil && !step.Fails:
+			result.Failures = append(result.Failures, fmt.Sprintf("%s: %v", prefix, err))
+			return result, nil
+		case err == nil && step.Fails:
+			result.Failures = append(result.Failures, fmt.Sprintf("%s: expected failure", prefix))
+		}
+		for _, failure := range step.Expect.Check(state) {
+			result.Failures = append(result.Failures, fmt.Sprintf("%s: %s", prefix, failure))
+		}
+	}
+	result.Failures = append(result.Failures, s.Expect.Check(state)...)
+	return result, nil
+}
+
+func (step ScenarioStep) name() string {
+	if step.Action != "" {
+		return "action " + step.Action
+	}
+	return step.Hook
+}
+
+// prepare updates the state as described by the step, and returns
+// the context in which the step's hook or action is run.
+func (step ScenarioStep) prepare(state *State) (*Context, error) {
+	if step.Config != nil && state.Config == nil {
+		state.Config = make(charm.Settings)
+	}
+	for key, value := range step.Config {
+		state.Config[key] = value
+	}
+	if step.Leader != nil {
+		state.Leader = *step.Leader
+	}
+	if step.Action != "" {
+		state.ActionParams = make(map[string]interface{})
+		for key, value := range step.Params {
+			state.ActionParams[key] = value
+		}
+		state.ActionResults = nil
+		state.ActionMessage = ""
+		state.ActionFailed = false
+	}
+
+	ctx := NewContext(state)
+	if step.Relation != nil {
+		relation, ok := state.Relations[*step.Relation]
+		if !ok {
+			return nil, errors.NotFoundf("relation %d", *step.Relation)
+		}
+		if step.RemoteSettings != nil {
+			if relation.Units == nil {
+				relation.Units = make(map[string]map[string]string)
+			}
+			relation.Units[step.RemoteUnit] = copySettings(step.RemoteSettings)
+		}
+		switch {
+		case strings.HasSuffix(step.Hook, "-relation-departed"):
+			// The departing unit is no longer a member of the
+			// relation by the time the hook runs.
+			delete(relation.Units, step.RemoteUnit)
+		case strings.HasSuffix(step.Hook, "-relation-joined"):
+			if _, ok := relation.Units[step.RemoteUnit]; !ok && step.RemoteUnit != "" {
+				if relation.Units == nil {
+					relation.Units = make(map[string]map[string]string)
+				}
+				relation.Units[step.RemoteUnit] = make(map[string]string)
+			}
+		case strings.HasSuffix(step.Hook, "-relation-broken"):
+			relation.Units = nil
+		}
+		if err := ctx.SetRelationHook(*step.Relation, step.RemoteUnit); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if step.Storage != "" {
+		if err := ctx.SetStorageHook(step.Storage); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return ctx, nil
+}
+
+// finish updates the state once the step's hook or action has run.
+func (step ScenarioStep) finish(state *State) {
+	if step.Relation != nil && strings.HasSuffix(step.Hook, "-relation-broken") {
+		delete(state.Relations, *step.Relation)
+	}
+	if step.Storage != "" && strings.HasSuffix(step.Hook, "-storage-detaching") {
+		delete(state.Storage, step.Storage)
+	}
+}
+
+// Check returns a description of each way in which the state doesn't
+// meet the expectation.
+func (e *Expectation) Check(state *State) []string {
+	if e == nil {
+		return nil
+	}
+	var failures []string
+	fail := func(format string, args ...interface{}) {
+		failures = append(failures, fmt.Sprintf(format, args...))
+	}
+	checkStatus := func(kind string, expected *ScenarioStatus, actual jujuc.StatusInfo) {
+		if expected == nil {
+			return
+		}
+		if expected.Status != actual.Status || expected.Message != actual.Info {
+			fail("%s status: expected %s %q, got %s %q", kind, expected.Status, expected.Message, actual.Status, actual.Info)
+		}
+	}
+	checkStatus("unit", e.UnitStatus, state.UnitStatus)
+	checkStatus("application", e.ApplicationStatus, state.ApplicationStatus)
+	if e.WorkloadVersion != nil && *e.WorkloadVersion != state.WorkloadVersion {
+		fail("workload version: expected %q, got %q", *e.WorkloadVersion, state.WorkloadVersion)
+	}
+	for _, failure := range checkSettings(e.LeaderSettings, state.LeaderSettings) {
+		fail("leader settings: %s", failure)
+	}
+	ids := make([]int, 0, len(e.RelationSettings))
+	for id := range e.RelationSettings {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		relation, ok := state.Relations[id]
+		if !ok {
+			fail("relation %d: not found", id)
+			continue
+		}
+		for _, failure := range checkSettings(e.RelationSettings[id], relation.Settings) {
+			fail("relation %d settings: %s", id, failure)
+		}
+	}
+	if e.Ports != nil {
+		expected := make([]network.PortRange, len(e.Ports))
+		for i, port := range e.Ports {
+			portRange, err := network.ParsePortRange(port)
+			if err != nil {
+				fail("ports: %v", err)
+				return failures
+			}
+			expected[i] = portRange
+		}
+		network.SortPortRanges(expected)
+		if !portRangesEqual(expected, state.Ports) {
+			fail("ports: expected %v, got %v", expected, state.Ports)
+		}
+	}
+	names := make([]string, 0, len(e.StorageAdded))
+	for name := range e.StorageAdded {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var count uint64
+		for _, cons := range state.AddedStorage[name] {
+			if cons.Count != nil {
+				count += *cons.Count
+			} else {
+				count++
+			}
+		}
+		if count != e.StorageAdded[name] {
+			fail("storage %q added: expected %d, got %d", name, e.StorageAdded[name], count)
+		}
+	}
+	if e.Reboot != nil && *e.Reboot != (state.RebootPriority != jujuc.RebootSkip) {
+		fail("reboot: expected %v, got %v", *e.Reboot, !*e.Reboot)
+	}
+	if e.ActionResults != nil {
+		actual := make(map[string]string)
+		flattenResults("", state.ActionResults, actual)
+		for _, failure := range checkSettings(e.ActionResults, actual) {
+			fail("action results: %s", failure)
+		}
+	}
+	if e.ActionFailed != nil && *e.ActionFailed != state.ActionFailed {
+		fail("action failed: expected %v, got %v", *e.ActionFailed, state.ActionFailed)
+	}
+	return failures
+}
+
+// checkSettings checks that each of the expected settings has the
+// expected value, where an empty value means the setting is unset.
+func checkSettings(expected, actual map[string]string) []string {
+	keys := make([]string, 0, len(expected))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var failures []string
+	for _, key := range keys {
+		if value := actual[key]; value != expected[key] {
+			failures = append(failures, fmt.Sprintf("%s: expected %q, got %q", key, expected[key], value))
+		}
+	}
+	return failures
+}
+
+func portRangesEqual(a, b []network.PortRange) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// flattenResults flattens nested action results into keys of the form
+// passed to action-set.
+func flattenResults(prefix string, results map[string]interface{}, flattened map[string]string) {
+	for key, value := range results {
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenResults(prefix+key+".", nested, flattened)
+		} else {
+			flattened[prefix+key] = fmt.Sprint(value)
+		}
+	}
+}
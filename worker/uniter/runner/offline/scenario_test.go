@@ -0,0 +1,303 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offline_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	"github.com/juju/juju/worker/uniter/runner/offline"
+)
+
+type ScenarioSuite struct {
+	coretesting.BaseSuite
+	charmDir string
+	config   offline.HookConfig
+	stdout   bytes.Buffer
+}
+
+var _ = gc.Suite(&ScenarioSuite{})
+
+func (s *ScenarioSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.charmDir = c.MkDir()
+	for _, dir := range []string{"hooks", "actions"} {
+		err := os.Mkdir(filepath.Join(s.charmDir, dir), 0755)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.stdout.Reset()
+	s.config = offline.HookConfig{
+		CharmDir: s.charmDir,
+		Jujud:    "/path/to/jujud",
+		Stdout:   &s.stdout,
+	}
+}
+
+func (s *ScenarioSuite) writeHook(c *gc.C, name, script string) {
+	err := ioutil.WriteFile(filepath.Join(s.charmDir, name), []byte("#!/bin/bash\n"+script), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+const scenarioYAML = `
+unit: wordpress/0
+state:
+  config:
+    blog-title: My Title
+  leader: true
+  relations:
+  - id: 1
+    name: db
+    units:
+      mysql/0: {host: 10.0.0.1}
+  storage:
+    data/0: {kind: filesystem, location: /srv/data}
+  ports: [80/tcp]
+  unit-status: {status: active, message: Ready}
+steps:
+- hook: config-changed
+  config: {blog-title: New Title}
+- hook: db-relation-changed
+  relation: 1
+  remote-unit: mysql/0
+  remote-settings: {host: 10.0.0.2}
+- action: backup
+  params: {target: /tmp}
+  fails: true
+  expect:
+    action-failed: false
+expect:
+  unit-status: {status: active, message: Ready}
+  ports: [80/tcp]
+`
+
+func (s *ScenarioSuite) TestParseScenario(c *gc.C) {
+	scenario, err := offline.ParseScenario([]byte(scenarioYAML))
+	c.Assert(err, jc.ErrorIsNil)
+	relationId := 1
+	actionFailed := false
+	c.Assert(scenario, jc.DeepEquals, &offline.Scenario{
+		Unit: "wordpress/0",
+		State: offline.ScenarioState{
+			Config: map[string]interface{}{"blog-title": "My Title"},
+			Leader: true,
+			Relations: []offline.RelationSnapshot{{
+				Id:   1,
+				Name: "db",
+				Units: map[string]map[string]string{
+					"mysql/0": {"host": "10.0.0.1"},
+				},
+			}},
+			Storage: map[string]offline.StorageSnapshot{
+				"data/0": {Kind: "filesystem", Location: "/srv/data"},
+			},
+			Ports:      []string{"80/tcp"},
+			UnitStatus: &offline.ScenarioStatus{Status: "active", Message: "Ready"},
+		},
+		Steps: []offline.ScenarioStep{{
+			Hook:   "config-changed",
+			Config: map[string]interface{}{"blog-title": "New Title"},
+		}, {
+			Hook:           "db-relation-changed",
+			Relation:       &relationId,
+			RemoteUnit:     "mysql/0",
+			RemoteSettings: map[string]string{"host": "10.0.0.2"},
+		}, {
+			Action: "backup",
+			Params: map[string]interface{}{"target": "/tmp"},
+			Fails:  true,
+			Expect: &offline.Expectation{ActionFailed: &actionFailed},
+		}},
+		Expect: &offline.Expectation{
+			UnitStatus: &offline.ScenarioStatus{Status: "active", Message: "Ready"},
+			Ports:      []string{"80/tcp"},
+		},
+	})
+}
+
+func (s *ScenarioSuite) TestParseScenarioInvalid(c *gc.C) {
+	for i, test := range []struct {
+		yaml string
+		err  string
+	}{{
+		yaml: "steps: [{hook: install}]",
+		err:  "scenario without unit not valid",
+	}, {
+		yaml: "unit: wordpress/0",
+		err:  "scenario without steps not valid",
+	}, {
+		yaml: "unit: wordpress/0\nsteps: [{hook: install, action: backup}]",
+		err:  "step 1: expected one of hook or action not valid",
+	}, {
+		yaml: "unit: wordpress/0\nsteps: [{hook: install}, {}]",
+		err:  "step 2: expected one of hook or action not valid",
+	}, {
+		yaml: "unit: wordpress/0\nsteps: [{action: backup, relation: 1}]",
+		err:  "step 1: action with relation or storage not valid",
+	}, {
+		yaml: "unit: wordpress/0\nsteps: [{hook: install, remote-unit: mysql/0}]",
+		err:  "step 1: remote unit without relation not valid",
+	}, {
+		yaml: "unit: wordpress/0\nsteps: [{hook: db-relation-changed, relation: 1, remote-settings: {a: b}}]",
+		err:  "step 1: remote settings without remote unit not valid",
+	}, {
+		yaml: "unit: [",
+		err:  "cannot parse scenario: .*",
+	}} {
+		c.Logf("test %d: %s", i, test.yaml)
+		_, err := offline.ParseScenario([]byte(test.yaml))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ScenarioSuite) TestRunScenario(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("hooks are run offline only on unix")
+	}
+	s.writeHook(c, "hooks/config-changed", "echo config-changed\n")
+	s.writeHook(c, "hooks/db-relation-changed", "echo $JUJU_RELATION_ID $JUJU_REMOTE_UNIT\n")
+	s.writeHook(c, "actions/backup", "echo backup $JUJU_ACTION_NAME\nexit 1\n")
+	scenario, err := offline.ParseScenario([]byte(scenarioYAML))
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := scenario.Run(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Failures, gc.HasLen, 0)
+	c.Assert(s.stdout.String(), gc.Equals, "config-changed\ndb:1 mysql/0\nbackup backup\n")
+	c.Assert(result.State.Config, jc.DeepEquals, charm.Settings{"blog-title": "New Title"})
+	c.Assert(result.State.Relations[1].Units, jc.DeepEquals, map[string]map[string]string{
+		"mysql/0": {"host": "10.0.0.2"},
+	})
+	c.Assert(result.State.ActionParams, gc.IsNil)
+}
+
+func (s *ScenarioSuite) TestRunScenarioSkipsMissingHooks(c *gc.C) {
+	scenario := &offline.Scenario{
+		Unit:  "wordpress/0",
+		Steps: []offline.ScenarioStep{{Hook: "install"}, {Hook: "start"}},
+	}
+	result, err := scenario.Run(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Failures, gc.HasLen, 0)
+}
+
+func (s *ScenarioSuite) TestRunScenarioHookFails(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("hooks are run offline only on unix")
+	}
+	s.writeHook(c, "hooks/install", "exit 1\n")
+	s.writeHook(c, "hooks/start", "echo start\n")
+	scenario := &offline.Scenario{
+		Unit:  "wordpress/0",
+		Steps: []offline.ScenarioStep{{Hook: "start", Fails: true}, {Hook: "install"}, {Hook: "start"}},
+	}
+	result, err := scenario.Run(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Failures, jc.DeepEquals, []string{
+		"step 1 (start): expected failure",
+		"step 2 (install): exit status 1",
+	})
+	c.Assert(s.stdout.String(), gc.Equals, "start\n")
+}
+
+func (s *ScenarioSuite) TestRunScenarioRelationLifecycle(c *gc.C) {
+	relationId := 1
+	scenario := &offline.Scenario{
+		Unit: "wordpress/0",
+		State: offline.ScenarioState{
+			Relations: []offline.RelationSnapshot{{Id: 1, Name: "db"}},
+		},
+		Steps: []offline.ScenarioStep{
+			{Hook: "db-relation-joined", Relation: &relationId, RemoteUnit: "mysql/0"},
+			{Hook: "db-relation-joined", Relation: &relationId, RemoteUnit: "mysql/1"},
+			{Hook: "db-relation-departed", Relation: &relationId, RemoteUnit: "mysql/0"},
+		},
+	}
+	result, err := scenario.Run(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.State.Relations[1].Units, jc.DeepEquals, map[string]map[string]string{
+		"mysql/1": {},
+	})
+
+	scenario.Steps = append(scenario.Steps, offline.ScenarioStep{Hook: "db-relation-broken", Relation: &relationId})
+	result, err = scenario.Run(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.State.Relations, gc.HasLen, 0)
+}
+
+func (s *ScenarioSuite) TestRunScenarioUnknownRelation(c *gc.C) {
+	relationId := 2
+	scenario := &offline.Scenario{
+		Unit:  "wordpress/0",
+		Steps: []offline.ScenarioStep{{Hook: "db-relation-joined", Relation: &relationId}},
+	}
+	_, err := scenario.Run(s.config)
+	c.Assert(err, gc.ErrorMatches, `step 1 \(db-relation-joined\): relation 2 not found`)
+}
+
+func (s *ScenarioSuite) TestExpectationCheck(c *gc.C) {
+	count := uint64(2)
+	state := &offline.State{
+		UnitStatus:      jujuc.StatusInfo{Status: "blocked", Info: "need db"},
+		WorkloadVersion: "4.9",
+		LeaderSettings:  map[string]string{"password": "secret"},
+		Relations: map[int]*offline.Relation{
+			1: {Id: 1, Name: "db", Settings: map[string]string{"database": "wordpress"}},
+		},
+		Ports:          []network.PortRange{{FromPort: 80, ToPort: 80, Protocol: "tcp"}},
+		AddedStorage:   map[string][]params.StorageConstraints{"data": {{Count: &count}, {}}},
+		RebootPriority: jujuc.RebootNow,
+		ActionResults:  map[string]interface{}{"outcome": map[string]interface{}{"size": "10"}},
+	}
+	version := "4.9"
+	reboot := true
+	met := &offline.Expectation{
+		UnitStatus:       &offline.ScenarioStatus{Status: "blocked", Message: "need db"},
+		WorkloadVersion:  &version,
+		LeaderSettings:   map[string]string{"password": "secret", "user": ""},
+		RelationSettings: map[int]map[string]string{1: {"database": "wordpress"}},
+		Ports:            []string{"80"},
+		StorageAdded:     map[string]uint64{"data": 3},
+		Reboot:           &reboot,
+		ActionResults:    map[string]string{"outcome.size": "10"},
+	}
+	c.Assert(met.Check(state), gc.HasLen, 0)
+
+	version = "5.0"
+	reboot = false
+	unmet := &offline.Expectation{
+		UnitStatus:       &offline.ScenarioStatus{Status: "active"},
+		WorkloadVersion:  &version,
+		LeaderSettings:   map[string]string{"password": ""},
+		RelationSettings: map[int]map[string]string{1: {"database": "blog"}, 2: {}},
+		Ports:            []string{},
+		StorageAdded:     map[string]uint64{"data": 1},
+		Reboot:           &reboot,
+		ActionResults:    map[string]string{"outcome": "10"},
+	}
+	c.Assert(unmet.Check(state), jc.DeepEquals, []string{
+		`unit status: expected active "", got blocked "need db"`,
+		`workload version: expected "5.0", got "4.9"`,
+		`leader settings: password: expected "", got "secret"`,
+		`relation 1 settings: database: expected "blog", got "wordpress"`,
+		`relation 2: not found`,
+		`ports: expected [], got [80/tcp]`,
+		`storage "data" added: expected 1, got 3`,
+		`reboot: expected false, got true`,
+		`action results: outcome: expected "10", got ""`,
+	})
+
+	var none *offline.Expectation
+	c.Assert(none.Check(state), gc.HasLen, 0)
+}
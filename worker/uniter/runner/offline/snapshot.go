@@ -0,0 +1,212 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offline
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+// Snapshot records the context in which a hook ran on a unit, so that
+// the hook can be replayed offline.
+type Snapshot struct {
+	// Unit is the name of the unit.
+	Unit string `yaml:"unit"`
+
+	// Hook is the name of the hook.
+	Hook string `yaml:"hook"`
+
+	// Captured holds when the snapshot was taken.
+	Captured time.Time `yaml:"captured"`
+
+	// Env holds the environment variables with which the hook ran.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// Config holds the charm config settings of the application.
+	Config map[string]interface{} `yaml:"config,omitempty"`
+
+	// Leader records whether the unit was the leader.
+	Leader bool `yaml:"leader"`
+
+	// LeaderSettings holds the application's leader settings.
+	LeaderSettings map[string]string `yaml:"leader-settings,omitempty"`
+
+	// Relations holds the unit's relations.
+	Relations []RelationSnapshot `yaml:"relations,omitempty"`
+
+	// HookRelation holds the id of the relation of a relation hook,
+	// and RemoteUnit its remote unit, if any.
+	HookRelation *int   `yaml:"hook-relation,omitempty"`
+	RemoteUnit   string `yaml:"remote-unit,omitempty"`
+
+	// AvailabilityZone, PublicAddress and PrivateAddress describe the
+	// unit's machine.
+	AvailabilityZone string `yaml:"availability-zone,omitempty"`
+	PublicAddress    string `yaml:"public-address,omitempty"`
+	PrivateAddress   string `yaml:"private-address,omitempty"`
+}
+
+// RelationSnapshot records one of the unit's relations.
+type RelationSnapshot struct {
+	Id       int                          `yaml:"id"`
+	Name     string                       `yaml:"name"`
+	Settings map[string]string            `yaml:"settings,omitempty"`
+	Units    map[string]map[string]string `yaml:"units,omitempty"`
+}
+
+// NewSnapshot takes a snapshot of the context in which the named hook
+// is about to run with the given environment.
+func NewSnapshot(ctx jujuc.Context, hookName string, env []string) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Unit:     ctx.UnitName(),
+		Hook:     hookName,
+		Captured: time.Now().UTC(),
+		Env:      make(map[string]string),
+	}
+	for _, kv := range env {
+		if parts := strings.SplitN(kv, "=", 2); len(parts) == 2 {
+			snapshot.Env[parts[0]] = parts[1]
+		}
+	}
+
+	config, err := ctx.ConfigSettings()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read config")
+	}
+	snapshot.Config = map[string]interface{}(config)
+	if snapshot.Leader, err = ctx.IsLeader(); err != nil {
+		return nil, errors.Annotate(err, "cannot determine leadership")
+	}
+	if snapshot.LeaderSettings, err = ctx.LeaderSettings(); err != nil {
+		return nil, errors.Annotate(err, "cannot read leader settings")
+	}
+
+	ids, err := ctx.RelationIds()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read relations")
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		relation, err := ctx.Relation(id)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot read relation %d", id)
+		}
+		relationSnapshot, err := snapshotRelation(relation)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot read relation %q", relation.FakeId())
+		}
+		snapshot.Relations = append(snapshot.Relations, relationSnapshot)
+	}
+	if relation, err := ctx.HookRelation(); err == nil {
+		id := relation.Id()
+		snapshot.HookRelation = &id
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	if remoteUnit, err := ctx.RemoteUnitName(); err == nil {
+		snapshot.RemoteUnit = remoteUnit
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+
+	// The unit's machine may not report all of these, which is of
+	// no concern to the snapshot.
+	snapshot.AvailabilityZone, _ = ctx.AvailabilityZone()
+	snapshot.PublicAddress, _ = ctx.PublicAddress()
+	snapshot.PrivateAddress, _ = ctx.PrivateAddress()
+	return snapshot, nil
+}
+
+func snapshotRelation(relation jujuc.ContextRelation) (RelationSnapshot, error) {
+	snapshot := RelationSnapshot{
+		Id:   relation.Id(),
+		Name: relation.Name(),
+	}
+	settings, err := relation.Settings()
+	if err != nil {
+		return RelationSnapshot{}, errors.Trace(err)
+	}
+	snapshot.Settings = map[string]string(settings.Map())
+	for _, unitName := range relation.UnitNames() {
+		settings, err := relation.ReadSettings(unitName)
+		if err != nil {
+			return RelationSnapshot{}, errors.Annotatef(err, "cannot read settings of unit %q", unitName)
+		}
+		if snapshot.Units == nil {
+			snapshot.Units = make(map[string]map[string]string)
+		}
+		snapshot.Units[unitName] = map[string]string(settings)
+	}
+	return snapshot, nil
+}
+
+// State returns the state of the unit recorded in the snapshot.
+func (s *Snapshot) State() *State {
+	state := &State{
+		UnitName:         s.Unit,
+		Config:           make(charm.Settings),
+		Leader:           s.Leader,
+		LeaderSettings:   make(map[string]string),
+		AvailabilityZone: s.AvailabilityZone,
+		PublicAddress:    s.PublicAddress,
+		PrivateAddress:   s.PrivateAddress,
+		Relations:        make(map[int]*Relation),
+	}
+	for key, value := range s.Config {
+		state.Config[key] = value
+	}
+	for key, value := range s.LeaderSettings {
+		state.LeaderSettings[key] = value
+	}
+	for _, relationSnapshot := range s.Relations {
+		relation := &Relation{
+			Id:       relationSnapshot.Id,
+			Name:     relationSnapshot.Name,
+			Settings: copySettings(relationSnapshot.Settings),
+			Units:    make(map[string]map[string]string),
+		}
+		for unitName, settings := range relationSnapshot.Units {
+			relation.Units[unitName] = copySettings(settings)
+		}
+		state.Relations[relation.Id] = relation
+	}
+	return state
+}
+
+// Context returns a Context for replaying the hook recorded in the
+// snapshot, running against the given state.
+func (s *Snapshot) Context(state *State) (*Context, error) {
+	ctx := NewContext(state)
+	if s.HookRelation != nil {
+		if err := ctx.SetRelationHook(*s.HookRelation, s.RemoteUnit); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return ctx, nil
+}
+
+// Environ returns the environment variables recorded in the snapshot,
+// in the form used by os.Environ.
+func (s *Snapshot) Environ() []string {
+	env := make([]string, 0, len(s.Env))
+	for key, value := range s.Env {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
+
+func copySettings(settings map[string]string) map[string]string {
+	result := make(map[string]string)
+	for key, value := range settings {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package offline_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/offline"
+)
+
+type SnapshotSuite struct {
+	coretesting.BaseSuite
+	ctx *offline.Context
+}
+
+var _ = gc.Suite(&SnapshotSuite{})
+
+func (s *SnapshotSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.ctx = offline.NewContext(&offline.State{
+		UnitName:       "wordpress/0",
+		Config:         charm.Settings{"blog-title": "My Title"},
+		Leader:         true,
+		LeaderSettings: map[string]string{"password": "secret"},
+		PrivateAddress: "10.0.0.2",
+		Relations: map[int]*offline.Relation{
+			1: {
+				Id:       1,
+				Name:     "db",
+				Settings: map[string]string{"database": "wordpress"},
+				Units: map[string]map[string]string{
+					"mysql/0": {"host": "10.0.0.1"},
+				},
+			},
+		},
+	})
+	err := s.ctx.SetRelationHook(1, "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SnapshotSuite) TestNewSnapshot(c *gc.C) {
+	snapshot, err := offline.NewSnapshot(s.ctx, "db-relation-changed", []string{
+		"JUJU_UNIT_NAME=wordpress/0",
+		"JUJU_MODEL_NAME=default",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Captured.IsZero(), jc.IsFalse)
+	relationId := 1
+	c.Assert(snapshot, jc.DeepEquals, &offline.Snapshot{
+		Unit:     "wordpress/0",
+		Hook:     "db-relation-changed",
+		Captured: snapshot.Captured,
+		Env: map[string]string{
+			"JUJU_UNIT_NAME":  "wordpress/0",
+			"JUJU_MODEL_NAME": "default",
+		},
+		Config:         map[string]interface{}{"blog-title": "My Title"},
+		Leader:         true,
+		LeaderSettings: map[string]string{"password": "secret"},
+		Relations: []offline.RelationSnapshot{{
+			Id:       1,
+			Name:     "db",
+			Settings: map[string]string{"database": "wordpress"},
+			Units: map[string]map[string]string{
+				"mysql/0": {"host": "10.0.0.1"},
+			},
+		}},
+		HookRelation:   &relationId,
+		RemoteUnit:     "mysql/0",
+		PrivateAddress: "10.0.0.2",
+	})
+}
+
+func (s *SnapshotSuite) TestSnapshotContext(c *gc.C) {
+	snapshot, err := offline.NewSnapshot(s.ctx, "db-relation-changed", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	state := snapshot.State()
+	c.Assert(state, jc.DeepEquals, s.ctx.State())
+	ctx, err := snapshot.Context(state)
+	c.Assert(err, jc.ErrorIsNil)
+	relation, err := ctx.HookRelation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relation.FakeId(), gc.Equals, "db:1")
+	remoteUnit, err := ctx.RemoteUnitName()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remoteUnit, gc.Equals, "mysql/0")
+}
+
+func (s *SnapshotSuite) TestBundleRoundTrip(c *gc.C) {
+	charmDir := c.MkDir()
+	err := os.Mkdir(filepath.Join(charmDir, "hooks"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(charmDir, "hooks", "install"), []byte("#!/bin/sh\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Symlink("install", filepath.Join(charmDir, "hooks", "start"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	snapshot, err := offline.NewSnapshot(s.ctx, "db-relation-changed", []string{"FOO=bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = offline.WriteBundle(&buf, snapshot, charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	dir := c.MkDir()
+	read, err := offline.ReadBundle(&buf, dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read.Captured.Equal(snapshot.Captured), jc.IsTrue)
+	read.Captured = snapshot.Captured
+	c.Assert(read, jc.DeepEquals, snapshot)
+
+	extracted := filepath.Join(dir, offline.BundleCharmDir)
+	data, err := ioutil.ReadFile(filepath.Join(extracted, "hooks", "install"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "#!/bin/sh\n")
+	info, err := os.Stat(filepath.Join(extracted, "hooks", "install"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0755))
+	link, err := os.Readlink(filepath.Join(extracted, "hooks", "start"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(link, gc.Equals, "install")
+}
+
+func (s *SnapshotSuite) TestReadBundleInvalid(c *gc.C) {
+	_, err := offline.ReadBundle(bytes.NewBufferString("not a bundle"), c.MkDir())
+	c.Assert(err, gc.ErrorMatches, "cannot read hook context bundle: .*")
+}
@@ -6,9 +6,11 @@ package runner
 import (
 	"encoding/base64"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"syscall"
 	"time"
 	"unicode/utf8"
 
@@ -18,11 +20,15 @@ import (
 	"github.com/juju/utils/clock"
 	utilexec "github.com/juju/utils/exec"
 	jujuos "github.com/juju/utils/os"
+	"gopkg.in/juju/charm.v6-unstable/hooks"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/actions"
+	"github.com/juju/juju/worker/uniter/processgroup"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/debug"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	"github.com/juju/juju/worker/uniter/runner/offline"
 )
 
 var logger = loggo.GetLogger("juju.worker.uniter.runner")
@@ -52,6 +58,7 @@ type Context interface {
 	SetProcess(process context.HookProcess)
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
+	RecordHookExecution(execution params.HookExecution) error
 
 	Prepare() error
 	Flush(badge string, failure error) error
@@ -59,13 +66,18 @@ type Context interface {
 
 // NewRunner returns a Runner backed by the supplied context and paths.
 func NewRunner(context Context, paths context.Paths) Runner {
-	return &runner{context, paths}
+	return &runner{context: context, paths: paths, clock: clock.WallClock}
 }
 
 // runner implements Runner.
 type runner struct {
 	context Context
 	paths   context.Paths
+
+	// hookTimeout, if non-zero, is how long a hook may run before
+	// it is killed. It doesn't apply to actions or commands.
+	hookTimeout time.Duration
+	clock       clock.Clock
 }
 
 func (runner *runner) Context() Context {
@@ -135,17 +147,23 @@ func (runner *runner) runJujuRunAction() (err error) {
 		logger.Debugf("unable to read juju-run action timeout, will continue running action without one")
 	}
 
-	results, err := runner.runCommandsWithTimeout(command, time.Duration(timeout), clock.WallClock)
-
-	if err != nil {
-		return runner.context.Flush("juju-run", err)
+	execution := params.HookExecution{
+		Kind:    actionExecution,
+		Name:    actions.JujuRunActionName,
+		Started: time.Now(),
 	}
-
-	if err := runner.updateActionResults(results); err != nil {
-		return runner.context.Flush("juju-run", err)
+	results, err := runner.runCommandsWithTimeout(command, time.Duration(timeout), clock.WallClock)
+	if err == nil {
+		execution.ExitCode = results.Code
+		execution.Stdout = outputTail(results.Stdout)
+		execution.Stderr = outputTail(results.Stderr)
+		err = runner.updateActionResults(results)
+	} else {
+		execution.ExitCode = -1
 	}
-
-	return runner.context.Flush("juju-run", nil)
+	err = runner.context.Flush("juju-run", err)
+	runner.recordExecution(execution, err)
+	return err
 }
 
 func encodeBytes(input []byte) (value string, encoding string) {
@@ -221,48 +239,188 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) e
 		env = mergeWindowsEnvironment(env, os.Environ())
 	}
 
+	execution := params.HookExecution{
+		Kind:    hookExecution,
+		Name:    hookName,
+		Started: time.Now(),
+	}
+	if charmLocation == "actions" {
+		execution.Kind = actionExecution
+	}
 	debugctx := debug.NewHooksContext(runner.context.UnitName())
+	if request, _ := debugctx.FindCaptureRequest(); request != nil && charmLocation == "hooks" && request.MatchHook(hookName) {
+		runner.captureContext(request, hookName, env)
+	}
 	if session, _ := debugctx.FindSession(); session != nil && session.MatchHook(hookName) {
 		logger.Infof("executing %s via debug-hooks", hookName)
 		err = session.RunHook(hookName, runner.paths.GetCharmDir(), env)
 	} else {
-		err = runner.runCharmHook(hookName, env, charmLocation)
+		execution.Stdout, execution.Stderr, err = runner.runCharmHook(hookName, env, charmLocation)
 	}
-	return runner.context.Flush(hookName, err)
+	missing := context.IsMissingHookError(errors.Cause(err))
+	execution.ExitCode = exitCode(err)
+	err = runner.context.Flush(hookName, err)
+	// The update-status hook runs every few minutes; only its failures
+	// are worth the cost of recording.
+	if !missing && (err != nil || hookName != string(hooks.UpdateStatus)) {
+		runner.recordExecution(execution, err)
+	}
+	return err
 }
 
-func (runner *runner) runCharmHook(hookName string, env []string, charmLocation string) error {
+func (runner *runner) runCharmHook(hookName string, env []string, charmLocation string) (stdout, stderr string, err error) {
 	charmDir := runner.paths.GetCharmDir()
 	hook, err := searchHook(charmDir, filepath.Join(charmLocation, hookName))
 	if err != nil {
-		return err
+		return "", "", err
 	}
 	hookCmd := hookCommand(hook)
 	ps := exec.Command(hookCmd[0], hookCmd[1:]...)
 	ps.Env = env
 	ps.Dir = charmDir
-	outReader, outWriter, err := os.Pipe()
+	timeout := runner.hookTimeout
+	if charmLocation != "hooks" {
+		timeout = 0
+	}
+	if timeout > 0 {
+		processgroup.Setup(ps)
+	}
+	// Stdout and stderr are logged alike, but are captured separately
+	// for the record of the hook's execution.
+	outLogger, outWriter, err := runner.newHookLogger(hookName)
 	if err != nil {
-		return errors.Errorf("cannot make logging pipe: %v", err)
+		return "", "", errors.Trace(err)
 	}
-	ps.Stdout = outWriter
-	ps.Stderr = outWriter
-	hookLogger := &hookLogger{
-		r:      outReader,
-		done:   make(chan struct{}),
-		logger: runner.getLogger(hookName),
+	errLogger, errWriter, err := runner.newHookLogger(hookName)
+	if err != nil {
+		outWriter.Close()
+		outLogger.r.Close()
+		return "", "", errors.Trace(err)
 	}
-	go hookLogger.run()
+	ps.Stdout = outWriter
+	ps.Stderr = errWriter
+	go outLogger.run()
+	go errLogger.run()
 	err = ps.Start()
 	outWriter.Close()
+	errWriter.Close()
 	if err == nil {
 		// Record the *os.Process of the hook
 		runner.context.SetProcess(hookProcess{ps.Process})
 		// Block until execution finishes
-		err = ps.Wait()
+		err = runner.waitHook(ps, hookName, timeout)
+	}
+	outLogger.stop()
+	errLogger.stop()
+	return outLogger.output(), errLogger.output(), errors.Trace(err)
+}
+
+// waitHook waits for the started hook to finish. If it runs for longer
+// than a non-zero timeout, its process group is killed, so that any
+// processes it started don't outlive it, and an error satisfying
+// context.IsHookTimeoutError is returned.
+func (runner *runner) waitHook(ps *exec.Cmd, hookName string, timeout time.Duration) error {
+	if timeout == 0 {
+		return ps.Wait()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- ps.Wait()
+	}()
+	timer := runner.clock.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.Chan():
+	}
+	logger.Warningf("%s hook timed out after %v, killing it", hookName, timeout)
+	if err := processgroup.Kill(ps.Process); err != nil {
+		logger.Errorf("cannot kill %s hook: %v", hookName, err)
+	}
+	<-done
+	return context.NewHookTimeoutError(hookName, timeout)
+}
+
+// captureContext captures the context in which the named hook is
+// about to run, as requested by capture-hook-context. A failure to
+// capture the context doesn't affect the hook.
+func (runner *runner) captureContext(request *debug.CaptureRequest, hookName string, env []string) {
+	logger.Infof("capturing context of %s for capture-hook-context", hookName)
+	snapshot, err := offline.NewSnapshot(runner.context, hookName, env)
+	if err == nil {
+		err = request.Capture(func(w io.Writer) error {
+			return offline.WriteBundle(w, snapshot, runner.paths.GetCharmDir())
+		})
+	}
+	if err != nil {
+		logger.Warningf("cannot capture context of %s: %v", hookName, err)
+	}
+}
+
+// newHookLogger returns a hookLogger for the output of the named hook,
+// and the pipe to which the output should be written.
+func (runner *runner) newHookLogger(hookName string) (*hookLogger, *os.File, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, nil, errors.Errorf("cannot make logging pipe: %v", err)
+	}
+	return &hookLogger{
+		r:      reader,
+		done:   make(chan struct{}),
+		logger: runner.getLogger(hookName),
+	}, writer, nil
+}
+
+const (
+	hookExecution   = "hook"
+	actionExecution = "action"
+)
+
+// recordExecution completes the record of an execution of a hook or
+// action, which failed with err if it is not nil, and records it with
+// the unit's execution history. A failure to record the execution
+// doesn't affect the outcome of the hook.
+func (runner *runner) recordExecution(execution params.HookExecution, err error) {
+	execution.Finished = time.Now()
+	if err != nil {
+		execution.Error = err.Error()
+	}
+	if relation, err := runner.context.HookRelation(); err == nil {
+		execution.Relation = relation.FakeId()
+	}
+	if remoteUnit, err := runner.context.RemoteUnitName(); err == nil {
+		execution.RemoteUnit = remoteUnit
+	}
+	err = runner.context.RecordHookExecution(execution)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("%s %q not recorded: %v", execution.Kind, execution.Name, err)
+	} else if err != nil {
+		logger.Warningf("cannot record %s %q: %v", execution.Kind, execution.Name, err)
+	}
+}
+
+// exitCode returns the exit code of the process whose execution
+// returned err, or -1 if the process didn't exit normally.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := errors.Cause(err).(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+	}
+	return -1
+}
+
+// outputTail returns the tail of the given output, as recorded with the
+// execution of a hook.
+func outputTail(output []byte) string {
+	if len(output) > hookOutputTailSize {
+		output = output[len(output)-hookOutputTailSize:]
 	}
-	hookLogger.stop()
-	return errors.Trace(err)
+	return string(output)
 }
 
 func (runner *runner) startJujucServer() (*jujuc.Server, error) {
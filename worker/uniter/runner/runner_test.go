@@ -6,6 +6,7 @@ package runner_test
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -19,9 +20,12 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
 	runnertesting "github.com/juju/juju/worker/uniter/runner/testing"
 )
 
@@ -155,6 +159,7 @@ type MockContext struct {
 	flushBadge      string
 	flushFailure    error
 	flushResult     error
+	executions      []params.HookExecution
 }
 
 func (ctx *MockContext) UnitName() string {
@@ -186,6 +191,19 @@ func (ctx *MockContext) Flush(badge string, failure error) error {
 	return ctx.flushResult
 }
 
+func (ctx *MockContext) HookRelation() (jujuc.ContextRelation, error) {
+	return nil, errors.NotFoundf("hook relation")
+}
+
+func (ctx *MockContext) RemoteUnitName() (string, error) {
+	return "", errors.NotFoundf("remote unit")
+}
+
+func (ctx *MockContext) RecordHookExecution(execution params.HookExecution) error {
+	ctx.executions = append(ctx.executions, execution)
+	return nil
+}
+
 func (ctx *MockContext) ActionParams() (map[string]interface{}, error) {
 	return ctx.actionParams, ctx.actionParamsErr
 }
@@ -234,6 +252,38 @@ func (s *RunMockContextSuite) TestRunHookFlushSuccess(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunHookTimeout(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("the hook is a bash script")
+	}
+	ctx := &MockContext{}
+	hooksDir := filepath.Join(s.paths.GetCharmDir(), "hooks")
+	err := os.Mkdir(hooksDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	// The hook's background process must be killed along with it,
+	// or it would hold the hook's output open.
+	script := "#!/bin/bash\nsleep 60 &\nsleep 60\n"
+	err = ioutil.WriteFile(filepath.Join(hooksDir, "something-happened"), []byte(script), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	clock := envtesting.NewClock(time.Time{})
+	rnr := runner.NewRunnerWithHookTimeout(ctx, s.paths, time.Minute, clock)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- rnr.RunHook("something-happened")
+	}()
+	err = clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case err := <-errc:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for hook to be killed")
+	}
+	c.Assert(context.IsHookTimeoutError(errors.Cause(ctx.flushFailure)), jc.IsTrue)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `hook "something-happened" timed out after 1m0s`)
+}
+
 func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
@@ -336,6 +386,87 @@ func (s *RunMockContextSuite) TestRunActionCancelled(c *gc.C) {
 	c.Assert(ctx.actionResults["Stderr"], gc.Equals, nil)
 }
 
+func (s *RunMockContextSuite) TestRunHookRecordsExecution(c *gc.C) {
+	ctx := &MockContext{
+		flushResult: errors.New("pew pew pew"),
+	}
+	makeCharm(c, hookSpec{
+		dir:    "hooks",
+		name:   hookName,
+		perm:   0700,
+		code:   3,
+		stdout: "hello",
+		stderr: "goodbye",
+	}, s.paths.GetCharmDir())
+	err := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(err, gc.ErrorMatches, "pew pew pew")
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, "exit status 3")
+	c.Assert(ctx.executions, gc.HasLen, 1)
+	execution := ctx.executions[0]
+	c.Assert(execution.Kind, gc.Equals, "hook")
+	c.Assert(execution.Name, gc.Equals, "something-happened")
+	c.Assert(execution.ExitCode, gc.Equals, 3)
+	c.Assert(execution.Error, gc.Equals, "pew pew pew")
+	c.Assert(strings.TrimRight(execution.Stdout, "\r\n"), gc.Equals, "hello")
+	c.Assert(strings.TrimRight(execution.Stderr, "\r\n"), gc.Equals, "goodbye")
+	c.Assert(execution.Finished.Before(execution.Started), jc.IsFalse)
+}
+
+func (s *RunMockContextSuite) TestRunUpdateStatusHookNotRecorded(c *gc.C) {
+	ctx := &MockContext{}
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: "update-status",
+		perm: 0700,
+	}, s.paths.GetCharmDir())
+	err := runner.NewRunner(ctx, s.paths).RunHook("update-status")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.executions, gc.HasLen, 0)
+}
+
+func (s *RunMockContextSuite) TestRunUpdateStatusHookFailureRecorded(c *gc.C) {
+	ctx := &MockContext{}
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: "update-status",
+		perm: 0700,
+		code: 1,
+	}, s.paths.GetCharmDir())
+	err := runner.NewRunner(ctx, s.paths).RunHook("update-status")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, "exit status 1")
+	c.Assert(ctx.executions, gc.HasLen, 1)
+	c.Assert(ctx.executions[0].Name, gc.Equals, "update-status")
+	c.Assert(ctx.executions[0].ExitCode, gc.Equals, 1)
+}
+
+func (s *RunMockContextSuite) TestRunMissingHookNotRecorded(c *gc.C) {
+	ctx := &MockContext{}
+	err := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(context.IsMissingHookError(ctx.flushFailure), jc.IsTrue)
+	c.Assert(ctx.executions, gc.HasLen, 0)
+}
+
+func (s *RunMockContextSuite) TestRunActionRecordsExecution(c *gc.C) {
+	ctx := &MockContext{
+		actionData: &context.ActionData{},
+		actionParams: map[string]interface{}{
+			"command": "echo 1",
+			"timeout": 0,
+		},
+		actionResults: map[string]interface{}{},
+	}
+	err := runner.NewRunner(ctx, s.paths).RunAction("juju-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.executions, gc.HasLen, 1)
+	execution := ctx.executions[0]
+	c.Assert(execution.Kind, gc.Equals, "action")
+	c.Assert(execution.Name, gc.Equals, "juju-run")
+	c.Assert(execution.ExitCode, gc.Equals, 0)
+	c.Assert(strings.TrimRight(execution.Stdout, "\r\n"), gc.Equals, "1")
+}
+
 func (s *RunMockContextSuite) TestRunCommandsFlushSuccess(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
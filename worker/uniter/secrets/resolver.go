@@ -0,0 +1,63 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secrets provides the resolver that runs the secret-rotated
+// hook when secrets granted to a unit are given new values.
+package secrets
+
+import (
+	"sort"
+
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/remotestate"
+	"github.com/juju/juju/worker/uniter/resolver"
+)
+
+var logger = loggo.GetLogger("juju.worker.uniter.secrets")
+
+type secretsResolver struct{}
+
+// NewResolver returns a new secrets resolver.
+func NewResolver() resolver.Resolver {
+	return &secretsResolver{}
+}
+
+// NextOp is defined on the Resolver interface.
+func (r *secretsResolver) NextOp(
+	localState resolver.LocalState,
+	remoteState remotestate.Snapshot,
+	opFactory operation.Factory,
+) (operation.Operation, error) {
+	if !localState.Installed || localState.Kind != operation.Continue {
+		return nil, resolver.ErrNoOperation
+	}
+
+	ids := make([]string, 0, len(remoteState.SecretRevisions))
+	for id := range remoteState.SecretRevisions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		revision := remoteState.SecretRevisions[id]
+		info := hook.Info{
+			Kind:           hook.SecretRotated,
+			SecretId:       id,
+			SecretRevision: revision,
+		}
+		seen, ok := localState.SecretRevisions[id]
+		if !ok {
+			// The secret has only just been granted, so the charm
+			// will read its current value when it first needs it;
+			// record the revision without running the hook.
+			logger.Debugf("recording revision %d of newly granted secret %q", revision, id)
+			return opFactory.NewSkipHook(info)
+		}
+		if revision > seen {
+			return opFactory.NewRunHook(info)
+		}
+	}
+	return nil, resolver.ErrNoOperation
+}
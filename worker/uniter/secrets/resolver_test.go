@@ -0,0 +1,102 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"fmt"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/remotestate"
+	"github.com/juju/juju/worker/uniter/resolver"
+	"github.com/juju/juju/worker/uniter/secrets"
+)
+
+type secretsSuite struct{}
+
+var _ = gc.Suite(&secretsSuite{})
+
+func installedState(revisions map[string]int) resolver.LocalState {
+	return resolver.LocalState{
+		State: operation.State{
+			Installed:       true,
+			Kind:            operation.Continue,
+			SecretRevisions: revisions,
+		},
+	}
+}
+
+func (s *secretsSuite) TestNoSecrets(c *gc.C) {
+	r := secrets.NewResolver()
+	_, err := r.NextOp(installedState(nil), remotestate.Snapshot{}, &mockOperations{})
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *secretsSuite) TestNotInstalled(c *gc.C) {
+	r := secrets.NewResolver()
+	remoteState := remotestate.Snapshot{
+		SecretRevisions: map[string]int{"secret-0": 1},
+	}
+	_, err := r.NextOp(resolver.LocalState{}, remoteState, &mockOperations{})
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *secretsSuite) TestNewlyGrantedSecretSkipped(c *gc.C) {
+	r := secrets.NewResolver()
+	remoteState := remotestate.Snapshot{
+		SecretRevisions: map[string]int{"secret-0": 1},
+	}
+	op, err := r.NextOp(installedState(nil), remoteState, &mockOperations{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "skip secret-rotated secret-0/1")
+}
+
+func (s *secretsSuite) TestRotatedSecretRunsHook(c *gc.C) {
+	r := secrets.NewResolver()
+	remoteState := remotestate.Snapshot{
+		SecretRevisions: map[string]int{"secret-0": 1, "secret-1": 3},
+	}
+	localState := installedState(map[string]int{"secret-0": 1, "secret-1": 2})
+	op, err := r.NextOp(localState, remoteState, &mockOperations{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run secret-rotated secret-1/3")
+}
+
+func (s *secretsSuite) TestUpToDate(c *gc.C) {
+	r := secrets.NewResolver()
+	remoteState := remotestate.Snapshot{
+		SecretRevisions: map[string]int{"secret-0": 2},
+	}
+	localState := installedState(map[string]int{"secret-0": 2, "secret-9": 1})
+	_, err := r.NextOp(localState, remoteState, &mockOperations{})
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+type mockOperations struct {
+	operation.Factory
+}
+
+func (m *mockOperations) NewRunHook(info hook.Info) (operation.Operation, error) {
+	return mockOp(fmt.Sprintf("run %s %s/%d", info.Kind, info.SecretId, info.SecretRevision)), nil
+}
+
+func (m *mockOperations) NewSkipHook(info hook.Info) (operation.Operation, error) {
+	return mockOp(fmt.Sprintf("skip %s %s/%d", info.Kind, info.SecretId, info.SecretRevision)), nil
+}
+
+func mockOp(name string) operation.Operation {
+	return &mockOperation{name: name}
+}
+
+type mockOperation struct {
+	operation.Operation
+	name string
+}
+
+func (op *mockOperation) String() string {
+	return op.name
+}
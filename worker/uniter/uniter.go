@@ -28,6 +28,7 @@ import (
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/uniter/actions"
 	"github.com/juju/juju/worker/uniter/charm"
+	"github.com/juju/juju/worker/uniter/healthcheck"
 	"github.com/juju/juju/worker/uniter/hook"
 	uniterleadership "github.com/juju/juju/worker/uniter/leadership"
 	"github.com/juju/juju/worker/uniter/operation"
@@ -38,6 +39,7 @@ import (
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	"github.com/juju/juju/worker/uniter/secrets"
 	"github.com/juju/juju/worker/uniter/storage"
 )
 
@@ -282,15 +284,17 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 		}
 
 		uniterResolver := NewUniterResolver(ResolverConfig{
-			ClearResolved:       clearResolved,
-			ReportHookError:     u.reportHookError,
-			ShouldRetryHooks:    u.hookRetryStrategy.ShouldRetry,
-			StartRetryHookTimer: retryHookTimer.Start,
-			StopRetryHookTimer:  retryHookTimer.Reset,
-			Actions:             actions.NewResolver(),
-			Leadership:          uniterleadership.NewResolver(),
-			Relations:           relation.NewRelationsResolver(u.relations),
-			Storage:             storage.NewResolver(u.storage),
+			ClearResolved:            clearResolved,
+			ReportHookError:          u.reportHookError,
+			ShouldRetryHooks:         u.hookRetryStrategy.ShouldRetry,
+			ShouldRetryTimedOutHooks: u.hookRetryStrategy.RetryTimedOutHooks,
+			StartRetryHookTimer:      retryHookTimer.Start,
+			StopRetryHookTimer:       retryHookTimer.Reset,
+			Actions:                  actions.NewResolver(),
+			Leadership:               uniterleadership.NewResolver(),
+			Relations:                relation.NewRelationsResolver(u.relations),
+			Secrets:                  secrets.NewResolver(),
+			Storage:                  storage.NewResolver(u.storage),
 			Commands: runcommands.NewCommandsResolver(
 				u.commands, watcher.CommandCompleted,
 			),
@@ -464,6 +468,7 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 	if err != nil {
 		return errors.Annotatef(err, "cannot create deployer")
 	}
+	healthChecks := healthcheck.NewStore(u.paths.State.HealthChecksFile)
 	contextFactory, err := context.NewContextFactory(context.FactoryConfig{
 		State:            u.st,
 		UnitTag:          unitTag,
@@ -472,12 +477,13 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		Storage:          u.storage,
 		Paths:            u.paths,
 		Clock:            u.clock,
+		HealthChecks:     healthChecks,
 	})
 	if err != nil {
 		return err
 	}
 	runnerFactory, err := runner.NewFactory(
-		u.st, u.paths, contextFactory,
+		u.st, u.paths, contextFactory, u.hookRetryStrategy.HookTimeout, u.clock,
 	)
 	if err != nil {
 		return errors.Trace(err)
@@ -516,6 +522,19 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 	if err := u.catacomb.Add(rlw); err != nil {
 		return errors.Trace(err)
 	}
+
+	healthChecker, err := healthcheck.NewWorker(healthcheck.Config{
+		Store:    healthChecks,
+		Reporter: u.unit,
+		Clock:    u.clock,
+		CharmDir: u.paths.State.CharmDir,
+	})
+	if err != nil {
+		return errors.Annotate(err, "creating health checker")
+	}
+	if err := u.catacomb.Add(healthChecker); err != nil {
+		return errors.Trace(err)
+	}
 	return nil
 }
 
@@ -565,7 +584,7 @@ func (u *Uniter) acquireExecutionLock() (mutex.Releaser, error) {
 	return releaser, nil
 }
 
-func (u *Uniter) reportHookError(hookInfo hook.Info) error {
+func (u *Uniter) reportHookError(hookInfo hook.Info, timedOut bool) error {
 	// Set the agent status to "error". We must do this here in case the
 	// hook is interrupted (e.g. unit agent crashes), rather than immediately
 	// after attempting a runHookOp.
@@ -584,5 +603,11 @@ func (u *Uniter) reportHookError(hookInfo hook.Info) error {
 	}
 	statusData["hook"] = hookName
 	statusMessage := fmt.Sprintf("hook failed: %q", hookName)
+	if timedOut {
+		// A hook that was killed for running too long is reported
+		// distinctly, as it may not have failed on its own.
+		statusData["timed-out"] = true
+		statusMessage = fmt.Sprintf("hook timed out: %q", hookName)
+	}
 	return setAgentStatus(u, status.Error, statusMessage, statusData)
 }